@@ -34,6 +34,7 @@ import (
 	"vitess.io/vitess/go/acl"
 	"vitess.io/vitess/go/cmd"
 	"vitess.io/vitess/go/exit"
+	"vitess.io/vitess/go/json2"
 	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/vt/dbconfigs"
 	"vitess.io/vitess/go/vt/log"
@@ -41,6 +42,7 @@ import (
 	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/mysqlctlproto"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/topo"
@@ -68,13 +70,19 @@ const (
 )
 
 var (
-	minBackupInterval   time.Duration
-	minRetentionTime    time.Duration
-	minRetentionCount   = 1
-	initialBackup       bool
-	allowFirstBackup    bool
-	restartBeforeBackup bool
-	upgradeSafe         bool
+	minBackupInterval          time.Duration
+	minRetentionTime           time.Duration
+	minRetentionCount          = 1
+	retentionFullBackups       int
+	retentionPointInTimeWindow time.Duration
+	retentionMaxAge            time.Duration
+	storedRetentionPolicy      bool
+	initialBackup              bool
+	allowFirstBackup           bool
+	restartBeforeBackup        bool
+	upgradeSafe                bool
+	verifyBackupMode           bool
+	verifyBackupName           string
 
 	// vttablet-like flags
 	initDbNameOverride string
@@ -170,6 +178,12 @@ query traffic will ever be routed to vtbackup's mysqld. This silent operation
 mode helps make backups minimally disruptive to serving capacity and orthogonal
 to the handling of the query path.
 
+With --verify-backup, vtbackup instead restores an existing backup into a scratch
+mysqld, runs CHECK TABLE and counts the rows of every restored table, and checks
+that the restored replication position is the one recorded in the backup. It
+prints the outcome as JSON and fails if the backup can't be verified. No backup
+is taken or removed in this mode.
+
 The command-line parameters to vtbackup specify a policy for when a new backup
 is needed, and when old backups should be removed. If the existing backups
 already satisfy the policy, then vtbackup will do nothing and return success
@@ -191,10 +205,16 @@ func init() {
 	Main.Flags().DurationVar(&minBackupInterval, "min_backup_interval", minBackupInterval, "Only take a new backup if it's been at least this long since the most recent backup.")
 	Main.Flags().DurationVar(&minRetentionTime, "min_retention_time", minRetentionTime, "Keep each old backup for at least this long before removing it. Set to 0 to disable pruning of old backups.")
 	Main.Flags().IntVar(&minRetentionCount, "min_retention_count", minRetentionCount, "Always keep at least this many of the most recent backups in this backup storage location, even if some are older than the min_retention_time. This must be at least 1 since a backup must always exist to allow new backups to be made")
+	Main.Flags().IntVar(&retentionFullBackups, "retention-full-backups", retentionFullBackups, "If set, prune old backups with a retention policy that keeps this many of the most recent full backups, instead of using min_retention_count. Backups younger than min_retention_time, and backups that retained incremental backups depend on, are always kept.")
+	Main.Flags().DurationVar(&retentionPointInTimeWindow, "retention-point-in-time-window", retentionPointInTimeWindow, "If set, prune old backups with a retention policy that keeps the full and incremental backups needed to restore to any point in time within this window.")
+	Main.Flags().DurationVar(&retentionMaxAge, "retention-max-age", retentionMaxAge, "If set, prune old backups with a retention policy that doesn't keep full backups older than this, except for the most recent one and the backups that retained incremental backups depend on.")
+	Main.Flags().BoolVar(&storedRetentionPolicy, "stored-retention-policy", storedRetentionPolicy, "If set, and none of the retention-* flags is set, prune old backups with the backup retention policy stored in the topology for the shard, or else for its keyspace, if there is one.")
 	Main.Flags().BoolVar(&initialBackup, "initial_backup", initialBackup, "Instead of restoring from backup, initialize an empty database with the provided init_db_sql_file and upload a backup of that for the shard, if the shard has no backups yet. This can be used to seed a brand new shard with an initial, empty backup. If any backups already exist for the shard, this will be considered a successful no-op. This can only be done before the shard exists in topology (i.e. before any tablets are deployed).")
 	Main.Flags().BoolVar(&allowFirstBackup, "allow_first_backup", allowFirstBackup, "Allow this job to take the first backup of an existing shard.")
 	Main.Flags().BoolVar(&restartBeforeBackup, "restart_before_backup", restartBeforeBackup, "Perform a mysqld clean/full restart after applying binlogs, but before taking the backup. Only makes sense to work around xtrabackup bugs.")
 	Main.Flags().BoolVar(&upgradeSafe, "upgrade-safe", upgradeSafe, "Whether to use innodb_fast_shutdown=0 for the backup so it is safe to use for MySQL upgrades.")
+	Main.Flags().BoolVar(&verifyBackupMode, "verify-backup", verifyBackupMode, "Instead of taking a backup, restore an existing backup into a scratch mysqld, check the restored data and report the outcome. No backup is taken or removed.")
+	Main.Flags().StringVar(&verifyBackupName, "verify-backup-name", verifyBackupName, "Name of the backup to check with --verify-backup. Defaults to the most recent complete backup of the shard.")
 
 	// vttablet-like flags
 	Main.Flags().StringVar(&initDbNameOverride, "init_db_name_override", initDbNameOverride, "(init parameter) override the name of the db used by vttablet")
@@ -246,6 +266,17 @@ func run(_ *cobra.Command, args []string) error {
 		log.Errorf("min_retention_count must be at least 1 to allow restores to succeed")
 		exit.Return(1)
 	}
+	if policy := backupRetentionPolicy(); policy != nil {
+		if err := policy.Validate(); err != nil {
+			return fmt.Errorf("invalid backup retention policy: %w", err)
+		}
+	}
+	if verifyBackupMode {
+		if initialBackup {
+			return fmt.Errorf("--verify-backup and --initial_backup are mutually exclusive")
+		}
+		return verifyBackup(ctx)
+	}
 
 	// Open connection backup storage.
 	backupStorage, err := backupstorage.GetBackupStorage()
@@ -282,7 +313,7 @@ func run(_ *cobra.Command, args []string) error {
 	}
 
 	// Prune old backups.
-	if err := pruneBackups(ctx, backupStorage, backupDir); err != nil {
+	if err := pruneBackups(ctx, topoServer, backupStorage, backupDir); err != nil {
 		return fmt.Errorf("Couldn't prune old backups: %w", err)
 	}
 
@@ -298,6 +329,49 @@ func run(_ *cobra.Command, args []string) error {
 	return nil
 }
 
+// verifyBackup restores a backup of the shard into a scratch mysqld, checks
+// the restored data and prints the outcome.
+func verifyBackup(ctx context.Context) error {
+	initCtx, initCancel := context.WithTimeout(ctx, mysqlTimeout)
+	defer initCancel()
+	mysqld, mycnf, cleanup, err := mysqlctl.NewScratchMysqld(initCtx, &dbconfigs.GlobalDBConfigs, initDBSQLFile)
+	if err != nil {
+		return fmt.Errorf("failed to initialize mysql data dir and start mysqld: %v", err)
+	}
+	defer cleanup()
+
+	dbName := initDbNameOverride
+	if dbName == "" {
+		dbName = fmt.Sprintf("vt_%s", initKeyspace)
+	}
+	verification, err := mysqlctl.VerifyBackup(ctx, mysqlctl.VerifyBackupParams{
+		Cnf:         mycnf,
+		Mysqld:      mysqld,
+		Logger:      logutil.NewConsoleLogger(),
+		Concurrency: concurrency,
+		DbName:      dbName,
+		Keyspace:    initKeyspace,
+		Shard:       initShard,
+		BackupName:  verifyBackupName,
+		Stats:       backupstats.RestoreStats(),
+	})
+	if err != nil {
+		return fmt.Errorf("can't verify backup: %w", err)
+	}
+
+	data, err := json2.MarshalIndentPB(mysqlctlproto.BackupVerificationToProto(verification), "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", data)
+
+	if !verification.OK() {
+		return fmt.Errorf("backup %v/%v failed verification: %v", verification.Directory, verification.BackupName, strings.Join(verification.Errors, "; "))
+	}
+	log.Infof("Backup %v/%v verified.", verification.Directory, verification.BackupName)
+	return nil
+}
+
 func takeBackup(ctx context.Context, topoServer *topo.Server, backupStorage backupstorage.BackupStorage) error {
 	// This is an imaginary tablet alias. The value doesn't matter for anything,
 	// except that we generate a random UID to ensure the target backup
@@ -708,7 +782,59 @@ func retryOnError(ctx context.Context, fn func() error) error {
 	}
 }
 
-func pruneBackups(ctx context.Context, backupStorage backupstorage.BackupStorage, backupDir string) error {
+// backupRetentionPolicy returns the retention policy given by the retention-*
+// flags, or nil if none of them is set.
+func backupRetentionPolicy() *mysqlctl.BackupRetentionPolicy {
+	if retentionFullBackups == 0 && retentionPointInTimeWindow == 0 && retentionMaxAge == 0 {
+		return nil
+	}
+	return &mysqlctl.BackupRetentionPolicy{
+		KeepFullBackups:   retentionFullBackups,
+		PointInTimeWindow: retentionPointInTimeWindow,
+		MinAge:            minRetentionTime,
+		MaxAge:            retentionMaxAge,
+	}
+}
+
+// storedBackupRetentionPolicy returns the backup retention policy stored in
+// the topology for the shard or its keyspace, or nil if there is none.
+func storedBackupRetentionPolicy(ctx context.Context, topoServer *topo.Server) (*mysqlctl.BackupRetentionPolicy, error) {
+	policypb, err := topoServer.FindBackupRetentionPolicy(ctx, initKeyspace, initShard)
+	switch {
+	case topo.IsErrType(err, topo.NoNode):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	policy, err := mysqlctlproto.BackupRetentionPolicyFromProto(policypb)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func pruneBackups(ctx context.Context, topoServer *topo.Server, backupStorage backupstorage.BackupStorage, backupDir string) error {
+	policy := backupRetentionPolicy()
+	if policy == nil && storedRetentionPolicy {
+		var err error
+		if policy, err = storedBackupRetentionPolicy(ctx, topoServer); err != nil {
+			return fmt.Errorf("can't get the stored backup retention policy: %v", err)
+		}
+		if policy == nil {
+			log.Infof("No backup retention policy is stored for %v/%v.", initKeyspace, initShard)
+		}
+	}
+	if policy != nil && !policy.IsEmpty() {
+		plan, err := mysqlctl.PruneBackups(ctx, logutil.NewConsoleLogger(), backupStorage, backupDir, policy, false)
+		if err != nil {
+			return fmt.Errorf("can't prune backups: %v", err)
+		}
+		log.Infof("Pruned %v backups from %v, %v backups are retained.", len(plan.Pruned), backupDir, len(plan.Retained))
+		return nil
+	}
 	if minRetentionTime == 0 {
 		log.Info("Pruning of old backups is disabled.")
 		return nil
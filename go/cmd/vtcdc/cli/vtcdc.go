@@ -0,0 +1,202 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/vt/grpccommon"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtcdc"
+	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+var (
+	server         string
+	keyspace       string
+	shards         []string
+	tables         []string
+	tabletType     = "primary"
+	snapshot       bool
+	outputDir      string
+	subscription   string
+	maxFileSize    int64 = 128 * 1024 * 1024
+	rotateInterval       = time.Hour
+	checkpointFile       = "vtcdc_checkpoint.json"
+	name                 = "vtcdc"
+
+	Main = &cobra.Command{
+		Use:   "vtcdc",
+		Short: "vtcdc streams the changes of a keyspace from vtgate and writes them as Debezium change events.",
+		Long: `vtcdc subscribes to a VStream of a keyspace and converts its row and DDL events into
+Debezium-compatible JSON change envelopes, written one per line to stdout or to rotating files.
+
+The VGTID of the stream is saved to a checkpoint file once the events before it are written,
+and vtcdc resumes from it when restarted. Events written after the last checkpoint may be
+written again after a restart.
+
+With --subscription, the VGTID is acknowledged to a named VStream subscription stored by
+vtgate instead of the checkpoint file, so vtcdc resumes from it even if its local state is lost.`,
+		Example: `vtcdc --server vtgate:15991 --keyspace commerce
+
+vtcdc --server vtgate:15991 --keyspace customer --tables customer,corder --snapshot --output-dir /var/lib/vtcdc/customer --checkpoint-file /var/lib/vtcdc/customer.json
+
+vtcdc --server vtgate:15991 --keyspace customer --snapshot --subscription customer-cdc`,
+		Args:    cobra.NoArgs,
+		Version: servenv.AppVersion.String(),
+		PreRunE: servenv.CobraPreRunE,
+		RunE:    run,
+	}
+)
+
+func init() {
+	servenv.MoveFlagsToCobraCommand(Main)
+
+	Main.Flags().StringVar(&server, "server", server, "vtgate server to connect to")
+	Main.Flags().StringVar(&keyspace, "keyspace", keyspace, "keyspace to stream the changes of")
+	Main.Flags().StringSliceVar(&shards, "shards", shards, "shards to stream the changes of. Defaults to all the shards of the keyspace.")
+	Main.Flags().StringSliceVar(&tables, "tables", tables, "tables to stream the changes of. Defaults to all the tables of the keyspace.")
+	Main.Flags().StringVar(&tabletType, "tablet-type", tabletType, "type of the tablets to stream from")
+	Main.Flags().BoolVar(&snapshot, "snapshot", snapshot, "when starting without a checkpoint, write the existing rows as read events before streaming the changes")
+	Main.Flags().StringVar(&outputDir, "output-dir", outputDir, "directory to write the rotating files of change events to. The events are written to stdout if empty.")
+	Main.Flags().Int64Var(&maxFileSize, "max-file-size", maxFileSize, "size in bytes after which a new output file is started. 0 disables the rotation on size.")
+	Main.Flags().DurationVar(&rotateInterval, "rotate-interval", rotateInterval, "age after which a new output file is started. 0 disables the rotation on age.")
+	Main.Flags().StringVar(&checkpointFile, "checkpoint-file", checkpointFile, "file to save the position of the stream to, and to resume from on restart")
+	Main.Flags().StringVar(&subscription, "subscription", subscription, "name of the VStream subscription to acknowledge the position of the stream to, instead of the checkpoint file. It is created if it does not exist.")
+	Main.Flags().StringVar(&name, "name", name, "logical name of the source, reported in the source block of the change events")
+
+	Main.MarkFlagRequired("server")
+	Main.MarkFlagRequired("keyspace")
+
+	acl.RegisterFlags(Main.Flags())
+	grpccommon.RegisterFlags(Main.Flags())
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	defer logutil.Flush()
+
+	tt, err := topoproto.ParseTabletType(tabletType)
+	if err != nil {
+		return err
+	}
+
+	var vgtid *binlogdatapb.VGtid
+	if subscription == "" {
+		vgtid, err = vtcdc.LoadCheckpoint(checkpointFile)
+		if err != nil {
+			return err
+		}
+		if vgtid != nil {
+			log.Infof("Resuming from checkpoint %s", checkpointFile)
+		} else {
+			vgtid = initialVGtid()
+		}
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	conn, err := vtgateconn.Dial(ctx, server)
+	if err != nil {
+		return fmt.Errorf("client error: %w", err)
+	}
+	defer conn.Close()
+
+	// With a subscription, vtgate resumes from its acknowledged position, and
+	// the initial position is only used to create it.
+	startVGtid := vgtid
+	if subscription != "" {
+		startVGtid = initialVGtid()
+	}
+	reader, err := conn.VStream(ctx, tt, startVGtid, streamFilter(), &vtgatepb.VStreamFlags{Subscription: subscription})
+	if err != nil {
+		return err
+	}
+
+	var writer vtcdc.Writer
+	if outputDir == "" {
+		writer = vtcdc.NewStreamWriter(os.Stdout)
+	} else {
+		writer, err = vtcdc.NewRotatingWriter(outputDir, maxFileSize, rotateInterval)
+		if err != nil {
+			return err
+		}
+	}
+	defer writer.Close()
+
+	converter, err := vtcdc.NewConverter(name, servenv.AppVersion.ToStringMap()["version"], vgtid)
+	if err != nil {
+		return err
+	}
+	checkpoint := vtcdc.FileCheckpoint(checkpointFile)
+	if subscription != "" {
+		checkpoint = func(vgtid *binlogdatapb.VGtid, timestamp int64) error {
+			return conn.VStreamAck(ctx, subscription, vgtid, timestamp)
+		}
+	}
+	err = vtcdc.NewSink(converter, writer, checkpoint).Run(reader)
+	if err != nil && ctx.Err() != nil {
+		// the stream was interrupted by a signal, the checkpoint is up to date
+		log.Infof("Stopping: %v", ctx.Err())
+		return nil
+	}
+	return err
+}
+
+// initialVGtid returns the position to start streaming from when there is no checkpoint:
+// the current position, or the beginning of the tables to write their existing rows first.
+func initialVGtid() *binlogdatapb.VGtid {
+	gtid := "current"
+	if snapshot {
+		gtid = ""
+	}
+	vgtid := &binlogdatapb.VGtid{}
+	if len(shards) == 0 {
+		vgtid.ShardGtids = append(vgtid.ShardGtids, &binlogdatapb.ShardGtid{Keyspace: keyspace, Gtid: gtid})
+		return vgtid
+	}
+	for _, shard := range shards {
+		vgtid.ShardGtids = append(vgtid.ShardGtids, &binlogdatapb.ShardGtid{Keyspace: keyspace, Shard: shard, Gtid: gtid})
+	}
+	return vgtid
+}
+
+func streamFilter() *binlogdatapb.Filter {
+	filter := &binlogdatapb.Filter{}
+	if len(tables) == 0 {
+		filter.Rules = append(filter.Rules, &binlogdatapb.Rule{Match: "/.*"})
+		return filter
+	}
+	for _, table := range tables {
+		filter.Rules = append(filter.Rules, &binlogdatapb.Rule{Match: table})
+	}
+	return filter
+}
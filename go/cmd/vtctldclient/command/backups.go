@@ -27,8 +27,10 @@ import (
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/mysqlctl/mysqlctlproto"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 
+	mysqlctlpb "vitess.io/vitess/go/vt/proto/mysqlctl"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
@@ -52,6 +54,17 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandBackupShard,
 	}
+	// GetBackupRetentionPolicy makes a GetBackupRetentionPolicy gRPC call to a vtctld.
+	GetBackupRetentionPolicy = &cobra.Command{
+		Use:   "GetBackupRetentionPolicy <keyspace|keyspace/shard>",
+		Short: "Outputs the backup retention policy of the given keyspace, or the one that applies to the given shard.",
+		Long: `Outputs the backup retention policy of the given keyspace, or the one that applies to the given shard.
+
+A shard without a policy of its own uses the policy of its keyspace, in which case the output has "inherited" set.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandGetBackupRetentionPolicy,
+	}
 	// GetBackups makes a GetBackups gRPC call to a vtctld.
 	GetBackups = &cobra.Command{
 		Use:                   "GetBackups [--limit <limit>] [--json] <keyspace/shard>",
@@ -60,6 +73,25 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandGetBackups,
 	}
+	// PruneBackups makes a PruneBackups gRPC call to a vtctld.
+	PruneBackups = &cobra.Command{
+		Use:   "PruneBackups [--keep-full-backups <count>] [--point-in-time-window <duration>] [--min-age <duration>] [--max-age <duration>] [--dry-run] [--json] <keyspace/shard>",
+		Short: "Removes the backups of the given shard that are not retained by the given retention policy.",
+		Long: `Removes the backups of the given shard that are not retained by the given retention policy.
+
+A backup is retained if it is one of the --keep-full-backups most recent full backups, if it is needed to restore
+to any point in time within --point-in-time-window, or if it is younger than --min-age. --max-age stops the first
+two rules from retaining older backups. The most recent full backup, and every backup a retained incremental
+backup depends on, are always retained.
+
+If none of the retention flags is given, the policy stored for the shard, or else for its keyspace, is enforced
+(see SetBackupRetentionPolicy). The command fails if there is no such policy either.
+
+With --dry-run, the backups that would be removed are listed, but not removed.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandPruneBackups,
+	}
 	// RemoveBackup makes a RemoveBackup gRPC call to a vtctld.
 	RemoveBackup = &cobra.Command{
 		Use:                   "RemoveBackup <keyspace/shard> <backup name>",
@@ -76,6 +108,33 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandRestoreFromBackup,
 	}
+	// SetBackupRetentionPolicy makes a SetBackupRetentionPolicy gRPC call to a vtctld.
+	SetBackupRetentionPolicy = &cobra.Command{
+		Use:   "SetBackupRetentionPolicy [--keep-full-backups <count>] [--point-in-time-window <duration>] [--min-age <duration>] [--max-age <duration>] [--clear] <keyspace|keyspace/shard>",
+		Short: "Sets or clears the backup retention policy of the given keyspace or shard.",
+		Long: `Sets or clears the backup retention policy of the given keyspace or shard.
+
+The policy of a shard overrides the policy of its keyspace. PruneBackups enforces the stored policy when it is run
+without retention flags. See PruneBackups for the meaning of the retention flags, at least one of which is required
+unless --clear is given.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandSetBackupRetentionPolicy,
+	}
+	// VerifyBackup makes a VerifyBackup gRPC call to a vtctld.
+	VerifyBackup = &cobra.Command{
+		Use:   "VerifyBackup [--backup-name <name>] [--concurrency <concurrency>] [--json] <tablet_alias>",
+		Short: "Restores a backup of the tablet's shard into a scratch mysqld on the tablet's host, and checks the restored data.",
+		Long: `Restores a backup of the tablet's shard into a scratch mysqld on the tablet's host, and checks the restored data.
+
+The tablet keeps serving and its own mysqld is not touched. The verification fails if
+the restore fails, if the restored replication position is not the one recorded in the
+backup MANIFEST, or if any restored table fails CHECK TABLE. Row counts are reported for
+every table.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandVerifyBackup,
+	}
 )
 
 var backupOptions = struct {
@@ -199,6 +258,133 @@ func commandGetBackups(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// backupRetentionPolicyOptions are the retention flags shared by PruneBackups
+// and SetBackupRetentionPolicy.
+type backupRetentionPolicyOptions struct {
+	KeepFullBackups   uint32
+	PointInTimeWindow time.Duration
+	MinAge            time.Duration
+	MaxAge            time.Duration
+}
+
+func (o *backupRetentionPolicyOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint32Var(&o.KeepFullBackups, "keep-full-backups", 0, "Number of most recent full backups to keep. The most recent full backup is always kept.")
+	cmd.Flags().DurationVar(&o.PointInTimeWindow, "point-in-time-window", 0, "Keep the full and incremental backups needed to restore to any point in time within this window.")
+	cmd.Flags().DurationVar(&o.MinAge, "min-age", 0, "Keep every backup younger than this.")
+	cmd.Flags().DurationVar(&o.MaxAge, "max-age", 0, "Do not keep backups older than this for --keep-full-backups or --point-in-time-window. The most recent full backup is always kept.")
+}
+
+// policy returns the retention policy given by the flags, or nil if none of
+// the flags was given. It fails if the flags don't set any retention rule,
+// since such a policy would remove all but the most recent full backup.
+func (o *backupRetentionPolicyOptions) policy(cmd *cobra.Command) (*mysqlctlpb.BackupRetentionPolicy, error) {
+	changed := false
+	for _, name := range []string{"keep-full-backups", "point-in-time-window", "min-age", "max-age"} {
+		changed = changed || cmd.Flags().Changed(name)
+	}
+	if !changed {
+		return nil, nil
+	}
+
+	policy := &mysqlctl.BackupRetentionPolicy{
+		KeepFullBackups:   int(o.KeepFullBackups),
+		PointInTimeWindow: o.PointInTimeWindow,
+		MinAge:            o.MinAge,
+		MaxAge:            o.MaxAge,
+	}
+	if policy.IsEmpty() {
+		return nil, fmt.Errorf("the retention flags don't set any retention rule")
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	return mysqlctlproto.BackupRetentionPolicyToProto(policy), nil
+}
+
+// parseKeyspaceOrShard parses a "keyspace" or "keyspace/shard" argument.
+func parseKeyspaceOrShard(arg string) (keyspace string, shard string, err error) {
+	if !strings.ContainsAny(arg, "/:") {
+		return arg, "", nil
+	}
+	return topoproto.ParseKeyspaceShard(arg)
+}
+
+func commandGetBackupRetentionPolicy(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := parseKeyspaceOrShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetBackupRetentionPolicy(commandCtx, &vtctldatapb.GetBackupRetentionPolicyRequest{
+		Keyspace: keyspace,
+		Shard:    shard,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+var pruneBackupsOptions = struct {
+	backupRetentionPolicyOptions
+	DryRun     bool
+	OutputJSON bool
+}{}
+
+func commandPruneBackups(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	policy, err := pruneBackupsOptions.policy(cmd)
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.PruneBackups(commandCtx, &vtctldatapb.PruneBackupsRequest{
+		Keyspace: keyspace,
+		Shard:    shard,
+		Policy:   policy,
+		DryRun:   pruneBackupsOptions.DryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if pruneBackupsOptions.OutputJSON {
+		data, err := cli.MarshalJSON(resp)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s\n", data)
+		return nil
+	}
+
+	names := make([]string, len(resp.RemovedBackups))
+	for i, b := range resp.RemovedBackups {
+		names[i] = b.Name
+	}
+
+	fmt.Printf("%s\n", strings.Join(names, "\n"))
+
+	return nil
+}
+
 func commandRemoveBackup(cmd *cobra.Command, args []string) error {
 	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
@@ -278,6 +464,126 @@ func commandRestoreFromBackup(cmd *cobra.Command, args []string) error {
 	}
 }
 
+var setBackupRetentionPolicyOptions = struct {
+	backupRetentionPolicyOptions
+	Clear bool
+}{}
+
+func commandSetBackupRetentionPolicy(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := parseKeyspaceOrShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	policy, err := setBackupRetentionPolicyOptions.policy(cmd)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case setBackupRetentionPolicyOptions.Clear && policy != nil:
+		return fmt.Errorf("--clear cannot be combined with retention flags")
+	case !setBackupRetentionPolicyOptions.Clear && policy == nil:
+		return fmt.Errorf("either --clear or at least one retention flag is required")
+	}
+
+	cli.FinishedParsing(cmd)
+
+	_, err = client.SetBackupRetentionPolicy(commandCtx, &vtctldatapb.SetBackupRetentionPolicyRequest{
+		Keyspace: keyspace,
+		Shard:    shard,
+		Policy:   policy,
+		Clear:    setBackupRetentionPolicyOptions.Clear,
+	})
+	if err != nil {
+		return err
+	}
+
+	if setBackupRetentionPolicyOptions.Clear {
+		fmt.Printf("Cleared the backup retention policy of %s.\n", cmd.Flags().Arg(0))
+	} else {
+		fmt.Printf("Set the backup retention policy of %s.\n", cmd.Flags().Arg(0))
+	}
+
+	return nil
+}
+
+var verifyBackupOptions = struct {
+	BackupName  string
+	Concurrency uint64
+	OutputJSON  bool
+}{}
+
+func commandVerifyBackup(cmd *cobra.Command, args []string) error {
+	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	stream, err := client.VerifyBackup(commandCtx, &vtctldatapb.VerifyBackupRequest{
+		TabletAlias: alias,
+		BackupName:  verifyBackupOptions.BackupName,
+		Concurrency: int64(verifyBackupOptions.Concurrency),
+	})
+	if err != nil {
+		return err
+	}
+
+	var verification *mysqlctlpb.BackupVerification
+	for verification == nil {
+		resp, err := stream.Recv()
+		switch err {
+		case nil:
+			if resp.Event != nil {
+				fmt.Printf("%s/%s (%s): %v\n", resp.Keyspace, resp.Shard, topoproto.TabletAliasString(resp.TabletAlias), resp.Event)
+			}
+			verification = resp.Verification
+		case io.EOF:
+			return fmt.Errorf("VerifyBackup on %s ended without a verification", topoproto.TabletAliasString(alias))
+		default:
+			return err
+		}
+	}
+
+	if verifyBackupOptions.OutputJSON {
+		data, err := cli.MarshalJSON(verification)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s\n", data)
+	} else {
+		printBackupVerification(verification)
+	}
+
+	if !verification.Ok {
+		return fmt.Errorf("backup %s/%s failed verification", verification.Directory, verification.BackupName)
+	}
+	return nil
+}
+
+func printBackupVerification(v *mysqlctlpb.BackupVerification) {
+	status := "OK"
+	if !v.Ok {
+		status = "FAILED"
+	}
+	fmt.Printf("Backup %s/%s: %s\n", v.Directory, v.BackupName, status)
+	fmt.Printf("Position: %s\n", v.Position)
+	fmt.Printf("Restored position: %s\n", v.RestoredPosition)
+	fmt.Printf("Tables:\n")
+	for _, table := range v.Tables {
+		fmt.Printf("  %s.%s: %d rows, %s\n", table.Database, table.Table, table.RowCount, table.Status)
+	}
+	if len(v.Errors) > 0 {
+		fmt.Printf("Errors:\n")
+		for _, msg := range v.Errors {
+			fmt.Printf("  %s\n", msg)
+		}
+	}
+}
+
 func init() {
 	Backup.Flags().BoolVar(&backupOptions.AllowPrimary, "allow-primary", false, "Allow the primary of a shard to be used for the backup. WARNING: If using the builtin backup engine, this will shutdown mysqld on the primary and stop writes for the duration of the backup.")
 	Backup.Flags().Uint64Var(&backupOptions.Concurrency, "concurrency", 4, "Specifies the number of compression/checksum jobs to run simultaneously.")
@@ -292,10 +598,17 @@ func init() {
 	BackupShard.Flags().BoolVar(&backupOptions.UpgradeSafe, "upgrade-safe", false, "Whether to use innodb_fast_shutdown=0 for the backup so it is safe to use for MySQL upgrades.")
 	Root.AddCommand(BackupShard)
 
+	Root.AddCommand(GetBackupRetentionPolicy)
+
 	GetBackups.Flags().Uint32VarP(&getBackupsOptions.Limit, "limit", "l", 0, "Retrieve only the most recent N backups.")
 	GetBackups.Flags().BoolVarP(&getBackupsOptions.OutputJSON, "json", "j", false, "Output backup info in JSON format rather than a list of backups.")
 	Root.AddCommand(GetBackups)
 
+	pruneBackupsOptions.addFlags(PruneBackups)
+	PruneBackups.Flags().BoolVar(&pruneBackupsOptions.DryRun, "dry-run", false, "Only list the backups that would be removed, without removing them.")
+	PruneBackups.Flags().BoolVarP(&pruneBackupsOptions.OutputJSON, "json", "j", false, "Output the removed and retained backups in JSON format.")
+	Root.AddCommand(PruneBackups)
+
 	Root.AddCommand(RemoveBackup)
 
 	RestoreFromBackup.Flags().StringVarP(&restoreFromBackupOptions.BackupTimestamp, "backup-timestamp", "t", "", "Use the backup taken at, or closest before, this timestamp. Omit to use the latest backup. Timestamp format is \"YYYY-mm-DD.HHMMSS\".")
@@ -303,4 +616,13 @@ func init() {
 	RestoreFromBackup.Flags().StringVar(&restoreFromBackupOptions.RestoreToTimestamp, "restore-to-timestamp", "", "Run a point in time recovery that restores up to, and excluding, given timestamp in RFC3339 format (`2006-01-02T15:04:05Z07:00`). This will attempt to use one full backup followed by zero or more incremental backups")
 	RestoreFromBackup.Flags().BoolVar(&restoreFromBackupOptions.DryRun, "dry-run", false, "Only validate restore steps, do not actually restore data")
 	Root.AddCommand(RestoreFromBackup)
+
+	setBackupRetentionPolicyOptions.addFlags(SetBackupRetentionPolicy)
+	SetBackupRetentionPolicy.Flags().BoolVar(&setBackupRetentionPolicyOptions.Clear, "clear", false, "Clear the backup retention policy instead of setting it.")
+	Root.AddCommand(SetBackupRetentionPolicy)
+
+	VerifyBackup.Flags().StringVar(&verifyBackupOptions.BackupName, "backup-name", "", "Name of the backup to verify. Omit to verify the latest complete backup.")
+	VerifyBackup.Flags().Uint64Var(&verifyBackupOptions.Concurrency, "concurrency", 4, "Specifies the number of files to restore concurrently.")
+	VerifyBackup.Flags().BoolVarP(&verifyBackupOptions.OutputJSON, "json", "j", false, "Output the verification in JSON format.")
+	Root.AddCommand(VerifyBackup)
 }
@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/sqltypes"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// GetDeadMessages makes a GetDeadMessages gRPC call to a vtctld.
+	GetDeadMessages = &cobra.Command{
+		Use:   "GetDeadMessages [--limit <limit>] [--json|-j] <keyspace> <table>",
+		Short: "Lists the dead-lettered messages of a message table.",
+		Long: `Lists the dead-lettered messages of a message table.
+
+A message is dead-lettered once it was sent vt_max_attempts times without being
+acked. Dead messages are read from the vt_dead_letter_table of the message table
+if it has one, or else from the message table itself, where they are marked
+failed with a NULL time_next.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandGetDeadMessages,
+	}
+	// PurgeDeadMessages makes a PurgeDeadMessages gRPC call to a vtctld.
+	PurgeDeadMessages = &cobra.Command{
+		Use:                   "PurgeDeadMessages [--all] <keyspace> <table> [<id> ...]",
+		Short:                 "Permanently deletes dead-lettered messages of a message table.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MinimumNArgs(2),
+		RunE:                  commandPurgeDeadMessages,
+	}
+	// RequeueDeadMessages makes a RequeueDeadMessages gRPC call to a vtctld.
+	RequeueDeadMessages = &cobra.Command{
+		Use:   "RequeueDeadMessages <keyspace> <table> [<id> ...]",
+		Short: "Makes dead-lettered messages of a message table deliverable again.",
+		Long: `Makes dead-lettered messages of a message table deliverable again.
+
+The delivery attempts of the requeued messages are reset. If no ids are given, up
+to 10000 dead messages per shard are requeued.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MinimumNArgs(2),
+		RunE:                  commandRequeueDeadMessages,
+	}
+)
+
+var getDeadMessagesOptions = struct {
+	Limit uint64
+	JSON  bool
+}{}
+
+func commandGetDeadMessages(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetDeadMessages(commandCtx, &vtctldatapb.GetDeadMessagesRequest{
+		Keyspace: cmd.Flags().Arg(0),
+		Table:    cmd.Flags().Arg(1),
+		Limit:    getDeadMessagesOptions.Limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	qr := sqltypes.Proto3ToResult(resp.Result)
+	switch getDeadMessagesOptions.JSON {
+	case true:
+		data, err := cli.MarshalJSON(qr)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s\n", data)
+	default:
+		cli.WriteQueryResultTable(cmd.OutOrStdout(), qr)
+	}
+
+	return nil
+}
+
+var purgeDeadMessagesOptions = struct {
+	All bool
+}{}
+
+func commandPurgeDeadMessages(cmd *cobra.Command, args []string) error {
+	ids := cmd.Flags().Args()[2:]
+	if len(ids) == 0 && !purgeDeadMessagesOptions.All {
+		return fmt.Errorf("must specify message ids, or --all to purge every dead message")
+	}
+	if len(ids) != 0 && purgeDeadMessagesOptions.All {
+		return fmt.Errorf("cannot specify both message ids and --all")
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.PurgeDeadMessages(commandCtx, &vtctldatapb.PurgeDeadMessagesRequest{
+		Keyspace: cmd.Flags().Arg(0),
+		Table:    cmd.Flags().Arg(1),
+		Ids:      ids,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Purged %d dead messages.\n", resp.Count)
+	return nil
+}
+
+func commandRequeueDeadMessages(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.RequeueDeadMessages(commandCtx, &vtctldatapb.RequeueDeadMessagesRequest{
+		Keyspace: cmd.Flags().Arg(0),
+		Table:    cmd.Flags().Arg(1),
+		Ids:      cmd.Flags().Args()[2:],
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Requeued %d dead messages.\n", resp.Count)
+	return nil
+}
+
+func init() {
+	GetDeadMessages.Flags().Uint64Var(&getDeadMessagesOptions.Limit, "limit", 0, "The maximum number of dead messages to return per shard. At most 10000 are returned.")
+	GetDeadMessages.Flags().BoolVarP(&getDeadMessagesOptions.JSON, "json", "j", false, "Output the results in JSON instead of a human-readable table.")
+	Root.AddCommand(GetDeadMessages)
+
+	PurgeDeadMessages.Flags().BoolVar(&purgeDeadMessagesOptions.All, "all", false, "Purge every dead message of the table.")
+	Root.AddCommand(PurgeDeadMessages)
+
+	Root.AddCommand(RequeueDeadMessages)
+}
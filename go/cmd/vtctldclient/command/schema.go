@@ -40,7 +40,7 @@ import (
 var (
 	// ApplySchema makes an ApplySchema gRPC call to a vtctld.
 	ApplySchema = &cobra.Command{
-		Use:   "ApplySchema [--ddl-strategy <strategy>] [--uuid <uuid> ...] [--migration-context <context>] [--wait-replicas-timeout <duration>] [--caller-id <caller_id>] {--sql-file <file> | --sql <sql>} <keyspace>",
+		Use:   "ApplySchema [--ddl-strategy <strategy>] [--uuid <uuid> ...] [--migration-context <context>] [--wait-replicas-timeout <duration>] [--caller-id <caller_id>] [--lint [--lint-rules <rules>]] {--sql-file <file> | --sql <sql>} <keyspace>",
 		Short: "Applies the schema change to the specified keyspace on every primary, running in parallel on all shards. The changes are then propagated to replicas via replication.",
 		Long: `Applies the schema change to the specified keyspace on every primary, running in parallel on all shards. The changes are then propagated to replicas via replication.
 
@@ -48,6 +48,7 @@ If --allow-long-unavailability is set, schema changes affecting a large number o
 --ddl-strategy is used to instruct migrations via vreplication, gh-ost or pt-osc with optional parameters.
 --migration-context allows the user to specify a custom migration context for online DDL migrations.
 If --skip-preflight, SQL goes directly to shards without going through sanity checks.
+If --lint is set, the SQL is first checked against the current schema with the same rules as LintSchema, and the change is rejected if any rule reports an error.
 
 The --uuid and --sql flags are repeatable, so they can be passed multiple times to build a list of values.
 For --uuid, this is used like "--uuid $first_uuid --uuid $second_uuid".
@@ -67,6 +68,33 @@ For --sql, semi-colons and repeated values may be mixed, for example:
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandGetSchema,
 	}
+	// LintSchema makes a LintSchema gRPC call to a vtctld.
+	LintSchema = &cobra.Command{
+		Use:   "LintSchema [--rules <rules>] [{--sql-file <file> | --sql <sql>}] <keyspace>",
+		Short: "Checks the schema of a keyspace, or SQL commands against it, for violations of the schema lint rules.",
+		Long: `Checks the schema of a keyspace, or SQL commands against it, for violations of the schema lint rules.
+
+Without --sql or --sql-file, all tables in the current schema of the keyspace are checked. Otherwise, the given SQL
+commands are checked against the current schema, including rules that only apply to changes, such as narrowing a
+column's type or dropping a column that is still referenced by a view.
+
+Available rules are:
+	missing-primary-key          (error)   table has no PRIMARY KEY
+	non-innodb-engine            (error)   table uses a storage engine other than InnoDB
+	deprecated-column-type       (warning) column uses a deprecated type or type attribute
+	unindexed-sharding-key       (error)   primary vindex columns are not covered by an index
+	unsafe-column-type-change    (error)   column type change may lose or alter data
+	drop-view-referenced-column  (error)   dropped or renamed column is referenced by a view
+
+Severities may be overridden with --rules, for example:
+
+	LintSchema --rules "missing-primary-key=warning,non-innodb-engine=off" commerce
+
+The command fails if any rule reports an error.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandLintSchema,
+	}
 	// ReloadSchema makes a ReloadSchema gRPC call to a vtctld.
 	ReloadSchema = &cobra.Command{
 		Use:                   "ReloadSchema <tablet_alias>",
@@ -104,26 +132,34 @@ var applySchemaOptions = struct {
 	SkipPreflight           bool
 	CallerID                string
 	BatchSize               int64
+	Lint                    bool
+	LintRules               string
 }{}
 
-func commandApplySchema(cmd *cobra.Command, args []string) error {
+// readSQL returns the individual SQL statements given either via repeated, semicolon-delimited --sql
+// flags or via a --sql-file.
+func readSQL(sqls []string, sqlFile string) ([]string, error) {
 	var allSQL string
-	if applySchemaOptions.SQLFile != "" {
-		if len(applySchemaOptions.SQL) != 0 {
-			return errors.New("Exactly one of --sql and --sql-file must be specified, not both.") // nolint
+	if sqlFile != "" {
+		if len(sqls) != 0 {
+			return nil, errors.New("Exactly one of --sql and --sql-file must be specified, not both.") // nolint
 		}
 
-		data, err := os.ReadFile(applySchemaOptions.SQLFile)
+		data, err := os.ReadFile(sqlFile)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		allSQL = string(data)
 	} else {
-		allSQL = strings.Join(applySchemaOptions.SQL, ";")
+		allSQL = strings.Join(sqls, ";")
 	}
 
-	parts, err := sqlparser.SplitStatementToPieces(allSQL)
+	return sqlparser.SplitStatementToPieces(allSQL)
+}
+
+func commandApplySchema(cmd *cobra.Command, args []string) error {
+	parts, err := readSQL(applySchemaOptions.SQL, applySchemaOptions.SQLFile)
 	if err != nil {
 		return err
 	}
@@ -146,11 +182,16 @@ func commandApplySchema(cmd *cobra.Command, args []string) error {
 		WaitReplicasTimeout: protoutil.DurationToProto(applySchemaOptions.WaitReplicasTimeout),
 		CallerId:            cid,
 		BatchSize:           applySchemaOptions.BatchSize,
+		Lint:                applySchemaOptions.Lint,
+		LintRules:           applySchemaOptions.LintRules,
 	})
 	if err != nil {
 		return err
 	}
 
+	for _, finding := range resp.LintFindings {
+		fmt.Fprintf(os.Stderr, "%s: [%s] %s: %s\n", finding.Severity, finding.Rule, finding.Entity, finding.Message)
+	}
 	fmt.Println(strings.Join(resp.UuidList, "\n"))
 	return nil
 }
@@ -211,6 +252,49 @@ func commandGetSchema(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var lintSchemaOptions = struct {
+	SQL     []string
+	SQLFile string
+	Rules   string
+}{}
+
+func commandLintSchema(cmd *cobra.Command, args []string) error {
+	parts, err := readSQL(lintSchemaOptions.SQL, lintSchemaOptions.SQLFile)
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.LintSchema(commandCtx, &vtctldatapb.LintSchemaRequest{
+		Keyspace: cmd.Flags().Arg(0),
+		Sql:      parts,
+		Rules:    lintSchemaOptions.Rules,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	errCount := 0
+	for _, finding := range resp.Findings {
+		if finding.Severity == "error" {
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		return fmt.Errorf("schema lint found %d error(s)", errCount)
+	}
+
+	return nil
+}
+
 func commandReloadSchema(cmd *cobra.Command, args []string) error {
 	tabletAlias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
@@ -296,6 +380,8 @@ func init() {
 	ApplySchema.Flags().StringArrayVar(&applySchemaOptions.SQL, "sql", nil, "Semicolon-delimited, repeatable SQL commands to apply. Exactly one of --sql|--sql-file is required.")
 	ApplySchema.Flags().StringVar(&applySchemaOptions.SQLFile, "sql-file", "", "Path to a file containing semicolon-delimited SQL commands to apply. Exactly one of --sql|--sql-file is required.")
 	ApplySchema.Flags().Int64Var(&applySchemaOptions.BatchSize, "batch-size", 0, "How many queries to batch together. Only applicable when all queries are CREATE TABLE|VIEW")
+	ApplySchema.Flags().BoolVar(&applySchemaOptions.Lint, "lint", false, "Lint the SQL commands against the current schema before applying them, and abort if any lint rule reports an error.")
+	ApplySchema.Flags().StringVar(&applySchemaOptions.LintRules, "lint-rules", "", "Comma-separated rule=severity entries overriding the severity of lint rules, where severity is one of error, warning or off. Only used with --lint.")
 
 	Root.AddCommand(ApplySchema)
 
@@ -308,6 +394,11 @@ func init() {
 
 	Root.AddCommand(GetSchema)
 
+	LintSchema.Flags().StringArrayVar(&lintSchemaOptions.SQL, "sql", nil, "Semicolon-delimited, repeatable SQL commands to lint. At most one of --sql|--sql-file may be given; if neither is, the current schema is linted.")
+	LintSchema.Flags().StringVar(&lintSchemaOptions.SQLFile, "sql-file", "", "Path to a file containing semicolon-delimited SQL commands to lint. At most one of --sql|--sql-file may be given; if neither is, the current schema is linted.")
+	LintSchema.Flags().StringVar(&lintSchemaOptions.Rules, "rules", "", "Comma-separated rule=severity entries overriding the severity of lint rules, where severity is one of error, warning or off.")
+	Root.AddCommand(LintSchema)
+
 	Root.AddCommand(ReloadSchema)
 
 	ReloadSchemaKeyspace.Flags().Uint32Var(&reloadSchemaKeyspaceOptions.Concurrency, "concurrency", 10, "Number of tablets to reload in parallel. Set to zero for unbounded concurrency.")
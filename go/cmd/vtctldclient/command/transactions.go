@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// GetUnresolvedTransactions makes a GetUnresolvedTransactions gRPC call to a vtctld.
+	GetUnresolvedTransactions = &cobra.Command{
+		Use:   "GetUnresolvedTransactions [--abandon-age <duration>] <keyspace>",
+		Short: "Lists the unresolved distributed transactions created by the shards of a keyspace.",
+		Long: `Lists the unresolved distributed transactions created by the shards of a keyspace.
+
+Distributed transactions are created by vtgate with --transaction_mode=TWOPC. A
+transaction is unresolved until it was committed or rolled back on all of its
+participants. Each transaction is listed with its state, its age and the shards
+participating in it, besides the shard that created it.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandGetUnresolvedTransactions,
+	}
+	// ResolveTransaction makes a ResolveTransaction gRPC call to a vtctld.
+	ResolveTransaction = &cobra.Command{
+		Use:   "ResolveTransaction [--action auto|commit|rollback] [--abandon-age <duration>] <dtid>",
+		Short: "Commits or rolls back an unresolved distributed transaction.",
+		Long: `Commits or rolls back an unresolved distributed transaction.
+
+A transaction whose commit decision was recorded (state COMMIT) can only be
+committed, and any other transaction can only be rolled back. By default the
+transaction is resolved according to its state. If --action is commit or
+rollback, the command fails instead of resolving the transaction in the other
+way.
+
+The command fails for transactions younger than --abandon-age, which vtgate
+may still be committing or rolling back.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandResolveTransaction,
+	}
+)
+
+var getUnresolvedTransactionsOptions = struct {
+	AbandonAge time.Duration
+}{}
+
+// unresolvedTransaction is the output format of GetUnresolvedTransactions.
+type unresolvedTransaction struct {
+	Dtid         string   `json:"dtid"`
+	State        string   `json:"state"`
+	Age          string   `json:"age"`
+	Participants []string `json:"participants"`
+}
+
+func commandGetUnresolvedTransactions(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetUnresolvedTransactions(commandCtx, &vtctldatapb.GetUnresolvedTransactionsRequest{
+		Keyspace:   cmd.Flags().Arg(0),
+		AbandonAge: int64(getUnresolvedTransactionsOptions.AbandonAge.Seconds()),
+	})
+	if err != nil {
+		return err
+	}
+
+	transactions := make([]*unresolvedTransaction, 0, len(resp.Transactions))
+	for _, tx := range resp.Transactions {
+		participants := make([]string, 0, len(tx.Participants))
+		for _, p := range tx.Participants {
+			participants = append(participants, p.Keyspace+"/"+p.Shard)
+		}
+		transactions = append(transactions, &unresolvedTransaction{
+			Dtid:         tx.Dtid,
+			State:        tx.State.String(),
+			Age:          time.Since(time.Unix(0, tx.TimeCreated)).Round(time.Second).String(),
+			Participants: participants,
+		})
+	}
+
+	data, err := cli.MarshalJSON(transactions)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+var resolveTransactionOptions = struct {
+	Action     string
+	AbandonAge time.Duration
+}{}
+
+func commandResolveTransaction(cmd *cobra.Command, args []string) error {
+	action, ok := vtctldatapb.ResolveTransactionRequest_Action_value[strings.ToUpper(resolveTransactionOptions.Action)]
+	if !ok {
+		return fmt.Errorf("invalid --action %s, must be one of auto, commit or rollback", resolveTransactionOptions.Action)
+	}
+
+	cli.FinishedParsing(cmd)
+
+	dtid := cmd.Flags().Arg(0)
+	resp, err := client.ResolveTransaction(commandCtx, &vtctldatapb.ResolveTransactionRequest{
+		Dtid:       dtid,
+		Action:     vtctldatapb.ResolveTransactionRequest_Action(action),
+		AbandonAge: int64(resolveTransactionOptions.AbandonAge.Seconds()),
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.State == querypb.TransactionState_COMMIT {
+		fmt.Printf("Committed transaction %s.\n", dtid)
+	} else {
+		fmt.Printf("Rolled back transaction %s.\n", dtid)
+	}
+	return nil
+}
+
+func init() {
+	GetUnresolvedTransactions.Flags().DurationVar(&getUnresolvedTransactionsOptions.AbandonAge, "abandon-age", 0, "Only list the transactions older than this.")
+	Root.AddCommand(GetUnresolvedTransactions)
+
+	ResolveTransaction.Flags().StringVar(&resolveTransactionOptions.Action, "action", "auto", "How to resolve the transaction: auto, commit or rollback.")
+	ResolveTransaction.Flags().DurationVar(&resolveTransactionOptions.AbandonAge, "abandon-age", time.Minute, "Only resolve the transaction if it is older than this.")
+	Root.AddCommand(ResolveTransaction)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// DeleteVStreamSubscription makes a DeleteVStreamSubscription gRPC call to a vtctld.
+	DeleteVStreamSubscription = &cobra.Command{
+		Use:   "DeleteVStreamSubscription <name>",
+		Short: "Deletes a VStream subscription and its acknowledged position.",
+		Long: `Deletes a VStream subscription and its acknowledged position.
+
+The next VStream using the name creates the subscription again, starting at the
+position of the request.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandDeleteVStreamSubscription,
+	}
+	// GetVStreamSubscriptions makes a GetVStreamSubscriptions gRPC call to a vtctld.
+	GetVStreamSubscriptions = &cobra.Command{
+		Use:   "GetVStreamSubscriptions [<name> ...]",
+		Short: "Lists the VStream subscriptions with their acknowledged positions and lag.",
+		Long: `Lists the VStream subscriptions with their acknowledged positions and lag.
+
+The lag of a subscription is the time elapsed since the commit of the last event
+its consumer acknowledged, or -1 if it did not acknowledge any event yet. All the
+subscriptions are listed if no names are given.`,
+		DisableFlagsInUseLine: true,
+		RunE:                  commandGetVStreamSubscriptions,
+	}
+)
+
+func commandDeleteVStreamSubscription(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	name := cmd.Flags().Arg(0)
+	_, err := client.DeleteVStreamSubscription(commandCtx, &vtctldatapb.DeleteVStreamSubscriptionRequest{
+		Name: name,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Deleted VStream subscription: %s\n", name)
+	return nil
+}
+
+func commandGetVStreamSubscriptions(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetVStreamSubscriptions(commandCtx, &vtctldatapb.GetVStreamSubscriptionsRequest{
+		Names: cmd.Flags().Args(),
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.Subscriptions)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+func init() {
+	Root.AddCommand(DeleteVStreamSubscription)
+	Root.AddCommand(GetVStreamSubscriptions)
+}
@@ -25,6 +25,7 @@ import (
 	"vitess.io/vitess/go/vt/vtorc/config"
 	"vitess.io/vitess/go/vt/vtorc/inst"
 	"vitess.io/vitess/go/vt/vtorc/logic"
+	"vitess.io/vitess/go/vt/vtorc/notify"
 	"vitess.io/vitess/go/vt/vtorc/server"
 )
 
@@ -65,6 +66,9 @@ func run(cmd *cobra.Command, args []string) {
 		inst.EnableAuditSyslog()
 	}
 	config.MarkConfigurationLoaded()
+	if err := notify.Init(); err != nil {
+		log.Exitf("failed to initialize notifications: %v", err)
+	}
 
 	// Log final config values to debug if something goes wrong.
 	config.LogConfigValues()
@@ -98,6 +102,7 @@ func init() {
 
 	logic.RegisterFlags(Main.Flags())
 	config.RegisterFlags(Main.Flags())
+	notify.RegisterFlags(Main.Flags())
 	acl.RegisterFlags(Main.Flags())
 	Main.Flags().StringVar(&configFile, "config", "", "config file name")
 }
@@ -47,6 +47,9 @@ var (
 	//go:embed vtaclcheck.txt
 	vtaclcheckTxt string
 
+	//go:embed vtcdc.txt
+	vtcdcTxt string
+
 	//go:embed vtcombo.txt
 	vtcomboTxt string
 
@@ -98,6 +101,7 @@ var (
 		"topo2topo":        topo2topoTxt,
 		"vtaclcheck":       vtaclcheckTxt,
 		"vtbackup":         vtbackupTxt,
+		"vtcdc":            vtcdcTxt,
 		"vtcombo":          vtcomboTxt,
 		"vtctlclient":      vtctlclientTxt,
 		"vtctld":           vtctldTxt,
@@ -174,7 +174,7 @@ func Connect(ctx context.Context, params *ConnParams) (*Conn, error) {
 // Ping implements mysql ping command.
 func (c *Conn) Ping() error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
 	data, pos := c.startEphemeralPacketWithHeader(1)
 	data[pos] = ComPing
 
@@ -300,6 +300,11 @@ func (c *Conn) clientHandshake(params *ConnParams) error {
 		return sqlerror.NewSQLError(sqlerror.CRSSLConnectionError, sqlerror.SSUnknownSQLState, "server doesn't support ClientSessionTrack but client asked for it")
 	}
 
+	// Protocol compression, if the server supports the requested algorithm.
+	if err := c.negotiateClientCompression(capabilities, params); err != nil {
+		return err
+	}
+
 	// Build and send our handshake response 41.
 	// Note this one will never have SSL flag on.
 	if err := c.writeHandshakeResponse41(capabilities, scrambledPassword, charset, params); err != nil {
@@ -311,6 +316,9 @@ func (c *Conn) clientHandshake(params *ConnParams) error {
 		return err
 	}
 
+	// Everything after the authentication OK packet is compressed.
+	c.enableCompression()
+
 	// If the server didn't support DbName in its handshake, set
 	// it now. This is what the 'mysql' client does.
 	if capabilities&CapabilityClientConnectWithDB == 0 && params.DbName != "" {
@@ -515,6 +523,37 @@ func (c *Conn) writeSSLRequest(capabilities uint32, characterSet uint8, params *
 	return nil
 }
 
+// negotiateClientCompression picks the compression algorithm to request in
+// the handshake response, based on the ConnParams and on what the server
+// advertised. Returns a SQLError.
+func (c *Conn) negotiateClientCompression(capabilities uint32, params *ConnParams) error {
+	algorithm, err := ParseCompressionAlgorithm(params.Compression)
+	if err != nil {
+		return sqlerror.NewSQLError(sqlerror.CRUnknownError, sqlerror.SSUnknownSQLState, "%v", err)
+	}
+
+	c.compressionAlgorithm = CompressionNone
+	switch algorithm {
+	case CompressionZlib:
+		if capabilities&CapabilityClientCompress != 0 {
+			c.compressionAlgorithm = CompressionZlib
+		}
+	case CompressionZstd:
+		level := params.ZstdCompressionLevel
+		if level == 0 {
+			level = DefaultZstdCompressionLevel
+		}
+		if err := validateZstdCompressionLevel(level); err != nil {
+			return sqlerror.NewSQLError(sqlerror.CRUnknownError, sqlerror.SSUnknownSQLState, "%v", err)
+		}
+		if capabilities&CapabilityClientZstdCompressionAlgorithm != 0 {
+			c.compressionAlgorithm = CompressionZstd
+			c.zstdCompressionLevel = level
+		}
+	}
+	return nil
+}
+
 // CapabilityFlags are client capability flag sent to mysql on connect
 const CapabilityFlags uint32 = CapabilityClientLongPassword |
 	CapabilityClientLongFlag |
@@ -544,6 +583,13 @@ func (c *Conn) writeHandshakeResponse41(capabilities uint32, scrambledPassword [
 		// CapabilityClientSessionTrack, we also support it.
 		c.Capabilities&CapabilityClientSessionTrack
 
+	switch c.compressionAlgorithm {
+	case CompressionZlib:
+		capabilityFlags |= CapabilityClientCompress
+	case CompressionZstd:
+		capabilityFlags |= CapabilityClientZstdCompressionAlgorithm
+	}
+
 	// FIXME(alainjobart) add multi statement.
 
 	length :=
@@ -557,6 +603,11 @@ func (c *Conn) writeHandshakeResponse41(capabilities uint32, scrambledPassword [
 			len(c.authPluginName) +
 			1 // terminating zero.
 
+	// The zstd compression level is sent as the last byte.
+	if c.compressionAlgorithm == CompressionZstd {
+		length++
+	}
+
 	// Add the DB name if the server supports it.
 	if params.DbName != "" && (capabilities&CapabilityClientConnectWithDB != 0) {
 		capabilityFlags |= CapabilityClientConnectWithDB
@@ -605,6 +656,12 @@ func (c *Conn) writeHandshakeResponse41(capabilities uint32, scrambledPassword [
 	// Assume native client during response
 	pos = writeNullString(data, pos, string(c.authPluginName))
 
+	// zstd compression level. We don't send connection attributes, so
+	// it directly follows the auth plugin name.
+	if c.compressionAlgorithm == CompressionZstd {
+		pos = writeByte(data, pos, byte(c.zstdCompressionLevel))
+	}
+
 	// Sanity-check the length.
 	if pos != len(data) {
 		return sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "writeHandshakeResponse41: only packed %v bytes, out of %v allocated", pos, len(data))
@@ -0,0 +1,348 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zlib"
+	"github.com/klauspost/compress/zstd"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// This file contains the implementation of the compressed client/server
+// protocol. Once negotiated during the handshake (CLIENT_COMPRESS for zlib,
+// CLIENT_ZSTD_COMPRESSION_ALGORITHM for zstd), every packet exchanged after
+// the authentication OK packet is wrapped in a compressed packet:
+//
+//	3 bytes: length of the (possibly compressed) payload
+//	1 byte:  compressed sequence number
+//	3 bytes: length of the payload before compression, or 0 if the
+//	         payload was sent uncompressed
+//
+// The payload of compressed packets is a stream of regular MySQL packets,
+// which may be split across several compressed packets.
+// See https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_basic_compression.html
+
+// CompressionAlgorithm is the name of a compression algorithm that can be
+// used by the MySQL protocol.
+type CompressionAlgorithm string
+
+const (
+	// CompressionNone means the connection is not compressed.
+	CompressionNone CompressionAlgorithm = ""
+
+	// CompressionZlib is the zlib compressed protocol (CLIENT_COMPRESS).
+	CompressionZlib CompressionAlgorithm = "zlib"
+
+	// CompressionZstd is the zstd compressed protocol
+	// (CLIENT_ZSTD_COMPRESSION_ALGORITHM).
+	CompressionZstd CompressionAlgorithm = "zstd"
+)
+
+const (
+	// DefaultZstdCompressionLevel is the zstd level used when the client
+	// does not ask for one. It is the MySQL default.
+	DefaultZstdCompressionLevel = 3
+
+	// minZstdCompressionLevel and maxZstdCompressionLevel are the bounds
+	// MySQL accepts for zstd_compression_level.
+	minZstdCompressionLevel = 1
+	maxZstdCompressionLevel = 22
+
+	// compressedPacketHeaderSize is the size of the header of a
+	// compressed packet.
+	compressedPacketHeaderSize = 7
+
+	// minCompressLength is the payload size below which we do not bother
+	// compressing. This is the same threshold MySQL uses.
+	minCompressLength = 50
+)
+
+// ParseCompressionAlgorithm parses the name of a compression algorithm.
+// An empty string, "none" and "uncompressed" all mean no compression.
+func ParseCompressionAlgorithm(name string) (CompressionAlgorithm, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "none", "uncompressed":
+		return CompressionNone, nil
+	case string(CompressionZlib):
+		return CompressionZlib, nil
+	case string(CompressionZstd):
+		return CompressionZstd, nil
+	}
+	return CompressionNone, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown compression algorithm: %q, must be one of zlib, zstd or uncompressed", name)
+}
+
+// ParseCompressionAlgorithms parses a comma separated list of compression
+// algorithms, as used by the server side flags. Uncompressed entries are
+// ignored, since clients can always connect without compression.
+func ParseCompressionAlgorithms(names string) ([]CompressionAlgorithm, error) {
+	var algorithms []CompressionAlgorithm
+	for _, name := range strings.Split(names, ",") {
+		algorithm, err := ParseCompressionAlgorithm(name)
+		if err != nil {
+			return nil, err
+		}
+		if algorithm != CompressionNone {
+			algorithms = append(algorithms, algorithm)
+		}
+	}
+	return algorithms, nil
+}
+
+// compressionCapabilities returns the capability flags to advertise for the
+// given compression algorithms.
+func compressionCapabilities(algorithms []CompressionAlgorithm) uint32 {
+	var capabilities uint32
+	for _, algorithm := range algorithms {
+		switch algorithm {
+		case CompressionZlib:
+			capabilities |= CapabilityClientCompress
+		case CompressionZstd:
+			capabilities |= CapabilityClientZstdCompressionAlgorithm
+		}
+	}
+	return capabilities
+}
+
+func validateZstdCompressionLevel(level int) error {
+	if level < minZstdCompressionLevel || level > maxZstdCompressionLevel {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid zstd compression level %d, must be between %d and %d", level, minZstdCompressionLevel, maxZstdCompressionLevel)
+	}
+	return nil
+}
+
+// zstdEncoders caches one encoder per compression level. EncodeAll is safe
+// for concurrent use, so the encoders are shared by all connections.
+var zstdEncoders sync.Map
+
+func zstdEncoderForLevel(level int) (*zstd.Encoder, error) {
+	if enc, ok := zstdEncoders.Load(level); ok {
+		return enc.(*zstd.Encoder), nil
+	}
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+	if err != nil {
+		return nil, err
+	}
+	actual, loaded := zstdEncoders.LoadOrStore(level, enc)
+	if loaded {
+		enc.Close()
+	}
+	return actual.(*zstd.Encoder), nil
+}
+
+// compressedIO wraps the reader and writer of a connection once the
+// compressed protocol has been negotiated. Reads return the uncompressed
+// stream of regular packets, and every Write is sent as one or more
+// compressed packets.
+type compressedIO struct {
+	r io.Reader
+	w io.Writer
+
+	algorithm CompressionAlgorithm
+	zstdLevel int
+
+	// sequence is the sequence number of compressed packets. It is reset
+	// with the packet sequence at the start of every command.
+	sequence uint8
+
+	header [compressedPacketHeaderSize]byte
+
+	// pending is the part of the last payload that has not been returned
+	// by Read yet. It points into readBuf or payload.
+	pending []byte
+	readBuf []byte
+	payload []byte
+
+	writeBuf []byte
+	zlibBuf  bytes.Buffer
+	zlibW    *zlib.Writer
+	zlibR    io.ReadCloser
+}
+
+func newCompressedIO(r io.Reader, w io.Writer, algorithm CompressionAlgorithm, zstdLevel int) *compressedIO {
+	return &compressedIO{
+		r:         r,
+		w:         w,
+		algorithm: algorithm,
+		zstdLevel: zstdLevel,
+	}
+}
+
+// Read implements io.Reader.
+func (cio *compressedIO) Read(p []byte) (int, error) {
+	for len(cio.pending) == 0 {
+		if err := cio.readCompressedPacket(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, cio.pending)
+	cio.pending = cio.pending[n:]
+	return n, nil
+}
+
+func (cio *compressedIO) readCompressedPacket() error {
+	if _, err := io.ReadFull(cio.r, cio.header[:]); err != nil {
+		return err
+	}
+	compressedLength := int(uint32(cio.header[0]) | uint32(cio.header[1])<<8 | uint32(cio.header[2])<<16)
+	sequence := cio.header[3]
+	uncompressedLength := int(uint32(cio.header[4]) | uint32(cio.header[5])<<8 | uint32(cio.header[6])<<16)
+
+	if sequence != cio.sequence {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid compressed sequence, expected %v got %v", cio.sequence, sequence)
+	}
+	cio.sequence++
+
+	cio.payload = growBuffer(cio.payload, compressedLength)
+	if _, err := io.ReadFull(cio.r, cio.payload); err != nil {
+		return vterrors.Wrapf(err, "io.ReadFull(compressed packet body of length %v) failed", compressedLength)
+	}
+
+	if uncompressedLength == 0 {
+		// The payload was sent as is.
+		cio.pending = cio.payload
+		return nil
+	}
+
+	cio.readBuf = growBuffer(cio.readBuf, uncompressedLength)
+	switch cio.algorithm {
+	case CompressionZlib:
+		if cio.zlibR == nil {
+			zr, err := zlib.NewReader(bytes.NewReader(cio.payload))
+			if err != nil {
+				return vterrors.Wrapf(err, "cannot decompress zlib packet")
+			}
+			cio.zlibR = zr
+		} else if err := cio.zlibR.(zlib.Resetter).Reset(bytes.NewReader(cio.payload), nil); err != nil {
+			return vterrors.Wrapf(err, "cannot decompress zlib packet")
+		}
+		if _, err := io.ReadFull(cio.zlibR, cio.readBuf); err != nil {
+			return vterrors.Wrapf(err, "cannot decompress zlib packet")
+		}
+	case CompressionZstd:
+		out, err := zstdDecoder.DecodeAll(cio.payload, cio.readBuf[:0])
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot decompress zstd packet")
+		}
+		if len(out) != uncompressedLength {
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "decompressed zstd packet has length %v, expected %v", len(out), uncompressedLength)
+		}
+		cio.readBuf = out
+	default:
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unsupported compression algorithm %q", cio.algorithm)
+	}
+	cio.pending = cio.readBuf
+	return nil
+}
+
+// Write implements io.Writer. The data is split in compressed packets
+// of at most MaxPacketSize uncompressed bytes.
+func (cio *compressedIO) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > MaxPacketSize {
+			chunk = chunk[:MaxPacketSize]
+		}
+		if err := cio.writeCompressedPacket(chunk); err != nil {
+			return written, err
+		}
+		written += len(chunk)
+		p = p[len(chunk):]
+	}
+	return written, nil
+}
+
+func (cio *compressedIO) writeCompressedPacket(data []byte) error {
+	cio.writeBuf = growBuffer(cio.writeBuf, compressedPacketHeaderSize)
+
+	uncompressedLength := 0
+	if len(data) >= minCompressLength {
+		var err error
+		cio.writeBuf, err = cio.compress(cio.writeBuf, data)
+		if err != nil {
+			return err
+		}
+		uncompressedLength = len(data)
+	}
+	if uncompressedLength != 0 && len(cio.writeBuf)-compressedPacketHeaderSize >= len(data) {
+		// Compression didn't help, send the data as is.
+		cio.writeBuf = cio.writeBuf[:compressedPacketHeaderSize]
+		uncompressedLength = 0
+	}
+	if uncompressedLength == 0 {
+		cio.writeBuf = append(cio.writeBuf, data...)
+	}
+
+	compressedLength := len(cio.writeBuf) - compressedPacketHeaderSize
+	cio.writeBuf[0] = byte(compressedLength)
+	cio.writeBuf[1] = byte(compressedLength >> 8)
+	cio.writeBuf[2] = byte(compressedLength >> 16)
+	cio.writeBuf[3] = cio.sequence
+	cio.writeBuf[4] = byte(uncompressedLength)
+	cio.writeBuf[5] = byte(uncompressedLength >> 8)
+	cio.writeBuf[6] = byte(uncompressedLength >> 16)
+
+	if n, err := cio.w.Write(cio.writeBuf); err != nil {
+		return vterrors.Wrapf(err, "Write(compressed packet) failed")
+	} else if n != len(cio.writeBuf) {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "Write(compressed packet) returned a short write: %v < %v", n, len(cio.writeBuf))
+	}
+	cio.sequence++
+	return nil
+}
+
+// compress appends the compressed form of data to dst.
+func (cio *compressedIO) compress(dst, data []byte) ([]byte, error) {
+	switch cio.algorithm {
+	case CompressionZlib:
+		cio.zlibBuf.Reset()
+		if cio.zlibW == nil {
+			cio.zlibW = zlib.NewWriter(&cio.zlibBuf)
+		} else {
+			cio.zlibW.Reset(&cio.zlibBuf)
+		}
+		if _, err := cio.zlibW.Write(data); err != nil {
+			return nil, vterrors.Wrapf(err, "cannot compress zlib packet")
+		}
+		if err := cio.zlibW.Close(); err != nil {
+			return nil, vterrors.Wrapf(err, "cannot compress zlib packet")
+		}
+		return append(dst, cio.zlibBuf.Bytes()...), nil
+	case CompressionZstd:
+		enc, err := zstdEncoderForLevel(cio.zstdLevel)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot create zstd encoder")
+		}
+		return enc.EncodeAll(data, dst), nil
+	}
+	return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unsupported compression algorithm %q", cio.algorithm)
+}
+
+// growBuffer returns a slice of length n, reusing buf if it is large enough.
+func growBuffer(buf []byte, n int) []byte {
+	if cap(buf) < n {
+		return make([]byte, n)
+	}
+	return buf[:n]
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/vttls"
+)
+
+func TestParseCompressionAlgorithms(t *testing.T) {
+	algorithms, err := ParseCompressionAlgorithms("zstd, ZLIB,uncompressed")
+	require.NoError(t, err)
+	assert.Equal(t, []CompressionAlgorithm{CompressionZstd, CompressionZlib}, algorithms)
+
+	algorithms, err = ParseCompressionAlgorithms("")
+	require.NoError(t, err)
+	assert.Empty(t, algorithms)
+
+	_, err = ParseCompressionAlgorithms("zlib,lz4")
+	assert.ErrorContains(t, err, `unknown compression algorithm: "lz4"`)
+}
+
+func TestCompressedIO(t *testing.T) {
+	payloads := [][]byte{
+		[]byte("short"),
+		bytes.Repeat([]byte("compressible "), 1000),
+		bytes.Repeat([]byte("x"), MaxPacketSize+100),
+	}
+	for _, algorithm := range []CompressionAlgorithm{CompressionZlib, CompressionZstd} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			var wire bytes.Buffer
+			w := newCompressedIO(nil, &wire, algorithm, DefaultZstdCompressionLevel)
+			for _, payload := range payloads {
+				n, err := w.Write(payload)
+				require.NoError(t, err)
+				assert.Equal(t, len(payload), n)
+			}
+			// Everything but the short payload is compressed.
+			assert.Less(t, wire.Len(), 100000)
+
+			r := newCompressedIO(&wire, nil, algorithm, DefaultZstdCompressionLevel)
+			for _, payload := range payloads {
+				got := make([]byte, len(payload))
+				_, err := io.ReadFull(r, got)
+				require.NoError(t, err)
+				assert.True(t, bytes.Equal(payload, got))
+			}
+			_, err := r.Read(make([]byte, 1))
+			assert.Equal(t, io.EOF, err)
+		})
+	}
+}
+
+func TestCompressedIOInvalidSequence(t *testing.T) {
+	var wire bytes.Buffer
+	w := newCompressedIO(nil, &wire, CompressionZlib, 0)
+	w.sequence = 3
+	_, err := w.Write([]byte("select 1"))
+	require.NoError(t, err)
+
+	r := newCompressedIO(&wire, nil, CompressionZlib, 0)
+	_, err = r.Read(make([]byte, 8))
+	assert.ErrorContains(t, err, "invalid compressed sequence, expected 0 got 3")
+}
+
+func TestCompressedConnection(t *testing.T) {
+	th := &testHandler{}
+
+	authServer := NewAuthServerStatic("", "", 0)
+	authServer.entries["user1"] = []*AuthServerStaticEntry{{Password: "password1"}}
+	defer authServer.close()
+
+	l, err := NewListener("tcp", "127.0.0.1:", authServer, th, 0, 0, false, false, 0)
+	require.NoError(t, err)
+	defer l.Close()
+	l.CompressionAlgorithms = []CompressionAlgorithm{CompressionZlib, CompressionZstd}
+	go l.Accept()
+
+	host := l.Addr().(*net.TCPAddr).IP.String()
+	port := l.Addr().(*net.TCPAddr).Port
+
+	// A query bigger than MaxPacketSize is split in several packets,
+	// and in several compressed packets.
+	largeQuery := benchmarkQueryPrefix + strings.Repeat("large query ", MaxPacketSize/10)
+
+	for _, algorithm := range []CompressionAlgorithm{CompressionZlib, CompressionZstd} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			params := &ConnParams{
+				Host:                 host,
+				Port:                 port,
+				Uname:                "user1",
+				Pass:                 "password1",
+				SslMode:              vttls.Disabled,
+				Compression:          string(algorithm),
+				ZstdCompressionLevel: 5,
+			}
+			conn, err := Connect(context.Background(), params)
+			require.NoError(t, err)
+			defer conn.Close()
+			assert.Equal(t, algorithm, conn.CompressionAlgorithm())
+
+			for i := 0; i < 3; i++ {
+				result, err := conn.ExecuteFetch("select rows", 10000, true)
+				require.NoError(t, err)
+				utils.MustMatch(t, selectRowsResult, result)
+			}
+
+			result, err := conn.ExecuteFetch(largeQuery, 10, true)
+			require.NoError(t, err)
+			require.Len(t, result.Rows, 1)
+			assert.Equal(t, largeQuery, result.Rows[0][0].ToString())
+
+			require.NoError(t, conn.Ping())
+
+			th.SetErr(sqlerror.NewSQLError(sqlerror.ERUnknownComError, sqlerror.SSNetError, "forced query error"))
+			_, err = conn.ExecuteFetch("error", 10, false)
+			th.SetErr(nil)
+			assert.ErrorContains(t, err, "forced query error")
+
+			// The connection is still usable after an error.
+			result, err = conn.ExecuteFetch("select rows", 10000, true)
+			require.NoError(t, err)
+			utils.MustMatch(t, selectRowsResult, result)
+
+			conn.writeComQuit()
+		})
+	}
+
+	// A client asking for an algorithm the server does not advertise
+	// falls back to the uncompressed protocol.
+	l.CompressionAlgorithms = []CompressionAlgorithm{CompressionZlib}
+	conn, err := Connect(context.Background(), &ConnParams{
+		Host:        host,
+		Port:        port,
+		Uname:       "user1",
+		Pass:        "password1",
+		SslMode:     vttls.Disabled,
+		Compression: string(CompressionZstd),
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, CompressionNone, conn.CompressionAlgorithm())
+	result, err := conn.ExecuteFetch("select rows", 10000, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, selectRowsResult, result)
+	conn.writeComQuit()
+}
+
+func TestCompressedConnectionInvalidZstdLevel(t *testing.T) {
+	c := &Conn{}
+	err := c.negotiateClientCompression(CapabilityClientZstdCompressionAlgorithm, &ConnParams{
+		Compression:          string(CompressionZstd),
+		ZstdCompressionLevel: 50,
+	})
+	assert.ErrorContains(t, err, "invalid zstd compression level 50")
+}
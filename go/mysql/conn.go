@@ -192,6 +192,16 @@ type Conn struct {
 	// Packet encoding variables.
 	sequence uint8
 
+	// compression is set once the compressed protocol has been negotiated
+	// and enabled. All packets are then read and written through it.
+	compression *compressedIO
+
+	// compressionAlgorithm and zstdCompressionLevel are the compression
+	// parameters negotiated during the handshake. Compression only starts
+	// once enableCompression is called, after the authentication OK packet.
+	compressionAlgorithm CompressionAlgorithm
+	zstdCompressionLevel int
+
 	// ExpectSemiSyncIndicator is applicable when the connection is used for replication (ComBinlogDump).
 	// When 'true', events are assumed to be padded with 2-byte semi-sync information
 	// See https://dev.mysql.com/doc/internals/en/semi-sync-binlog-event.html
@@ -315,7 +325,7 @@ func (c *Conn) startWriterBuffering() {
 	defer c.bufMu.Unlock()
 
 	c.bufferedWriter = writersPool.Get().(*bufio.Writer)
-	c.bufferedWriter.Reset(c.conn)
+	c.bufferedWriter.Reset(c.getWriter())
 }
 
 // endWriterBuffering must be called to terminate startWriteBuffering.
@@ -363,14 +373,59 @@ func (c *Conn) startFlushTimer() {
 }
 
 // getReader returns reader for connection. It can be *bufio.Reader or net.Conn
-// depending on which buffer size was passed to newServerConn.
+// depending on which buffer size was passed to newServerConn, or the
+// decompressing reader once compression is enabled.
 func (c *Conn) getReader() io.Reader {
+	if c.compression != nil {
+		return c.compression
+	}
+	return c.getRawReader()
+}
+
+func (c *Conn) getRawReader() io.Reader {
 	if c.bufferedReader != nil {
 		return c.bufferedReader
 	}
 	return c.conn
 }
 
+// getWriter returns the unbuffered writer for the connection. It is the
+// net.Conn, or the compressing writer once compression is enabled.
+func (c *Conn) getWriter() io.Writer {
+	if c.compression != nil {
+		return c.compression
+	}
+	return c.conn
+}
+
+// enableCompression switches the connection to the compressed protocol
+// negotiated during the handshake. It is a no-op if no compression was
+// negotiated.
+func (c *Conn) enableCompression() {
+	if c.compressionAlgorithm == CompressionNone {
+		return
+	}
+	c.compression = newCompressedIO(c.getRawReader(), c.conn, c.compressionAlgorithm, c.zstdCompressionLevel)
+}
+
+// resetSequence resets the packet sequence numbers at the start of a
+// new command.
+func (c *Conn) resetSequence() {
+	c.sequence = 0
+	if c.compression != nil {
+		c.compression.sequence = 0
+	}
+}
+
+// CompressionAlgorithm returns the compression algorithm in use on this
+// connection, or CompressionNone.
+func (c *Conn) CompressionAlgorithm() CompressionAlgorithm {
+	if c.compression == nil {
+		return CompressionNone
+	}
+	return c.compression.algorithm
+}
+
 func (c *Conn) readHeaderFrom(r io.Reader) (int, error) {
 	// Note io.ReadFull will return two different types of errors:
 	// 1. if the socket is already closed, and the go runtime knows it,
@@ -392,7 +447,12 @@ func (c *Conn) readHeaderFrom(r io.Reader) (int, error) {
 	}
 
 	sequence := uint8(c.header[3])
-	if sequence != c.sequence {
+	if c.compression != nil {
+		// With compression, the sequence is checked on the compressed
+		// packets. MySQL re-syncs the packet sequence with the compressed
+		// one every time it flushes, so just follow the peer here.
+		c.sequence = sequence
+	} else if sequence != c.sequence {
 		return 0, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid sequence, expected %v got %v", c.sequence, sequence)
 	}
 
@@ -603,7 +663,7 @@ func (c *Conn) writePacket(data []byte) error {
 		}()
 	} else {
 		c.bufMu.Unlock()
-		w = c.conn
+		w = c.getWriter()
 	}
 
 	var header [packetHeaderSize]byte
@@ -707,7 +767,7 @@ func (c *Conn) recycleWritePacket() {
 // Returns SQLError(CRServerGone) if it can't.
 func (c *Conn) writeComQuit() error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
 
 	data, pos := c.startEphemeralPacketWithHeader(1)
 	data[pos] = ComQuit
@@ -913,7 +973,7 @@ func (c *Conn) writeEOFPacket(flags uint16, warnings uint16) error {
 // handleNextCommand is called in the server loop to process
 // incoming packets.
 func (c *Conn) handleNextCommand(handler Handler) bool {
-	c.sequence = 0
+	c.resetSequence()
 	data, err := c.readEphemeralPacket()
 	if err != nil {
 		// Don't log EOF errors. They cause too much spam.
@@ -57,6 +57,16 @@ type ConnParams struct {
 	// for informative purposes. It has no programmatic value. Returning this field is
 	// disabled by default.
 	EnableQueryInfo bool
+
+	// Compression is the compression algorithm to use for the protocol:
+	// zlib, zstd, or empty for an uncompressed connection. If the server
+	// does not support the requested algorithm, the connection falls back
+	// to the uncompressed protocol.
+	Compression string `json:"compression"`
+
+	// ZstdCompressionLevel is the compression level requested when
+	// Compression is zstd. Zero means DefaultZstdCompressionLevel.
+	ZstdCompressionLevel int `json:"zstd_compression_level"`
 }
 
 // EnableSSL will set the right flag on the parameters.
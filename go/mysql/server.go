@@ -181,6 +181,11 @@ type Listener struct {
 	// beyond which a warning is logged to identify the slow connection
 	SlowConnectWarnThreshold atomic.Int64
 
+	// CompressionAlgorithms are the compressed protocols we will
+	// advertise. Clients that ask for one of them get a compressed
+	// connection once authenticated.
+	CompressionAlgorithms []CompressionAlgorithm
+
 	// The following parameters are changed by the Accept routine.
 
 	// Incrementing ID for connection id.
@@ -375,7 +380,7 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 	defer connCount.Add(-1)
 
 	// First build and send the server handshake packet.
-	serverAuthPluginData, err := c.writeHandshakeV10(l.ServerVersion, l.authServer, l.TLSConfig.Load() != nil)
+	serverAuthPluginData, err := c.writeHandshakeV10(l.ServerVersion, l.authServer, l.TLSConfig.Load() != nil, l.CompressionAlgorithms)
 	if err != nil {
 		if err != io.EOF {
 			log.Errorf("Cannot send HandshakeV10 packet to %s: %v", c, err)
@@ -518,6 +523,9 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 		return
 	}
 
+	// Everything after the authentication OK packet is compressed.
+	c.enableCompression()
+
 	// Record how long we took to establish the connection
 	timings.Record(connectTimingKey, acceptTime)
 
@@ -556,7 +564,7 @@ func (l *Listener) Shutdown() {
 
 // writeHandshakeV10 writes the Initial Handshake Packet, server side.
 // It returns the salt data.
-func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, enableTLS bool) ([]byte, error) {
+func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, enableTLS bool, compression []CompressionAlgorithm) ([]byte, error) {
 	capabilities := CapabilityClientLongPassword |
 		CapabilityClientFoundRows |
 		CapabilityClientLongFlag |
@@ -573,6 +581,7 @@ func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, en
 	if enableTLS {
 		capabilities |= CapabilityClientSSL
 	}
+	capabilities |= int(compressionCapabilities(compression))
 
 	// Grab the default auth method. This can only be either
 	// mysql_native_password or caching_sha2_password. Both
@@ -790,9 +799,28 @@ func (l *Listener) parseClientHandshakePacket(c *Conn, firstTime bool, data []by
 
 	// Decode connection attributes send by the client
 	if clientFlags&CapabilityClientConnAttr != 0 {
-		if _, _, err := parseConnAttrs(data, pos); err != nil {
+		if _, attrsEnd, err := parseConnAttrs(data, pos); err != nil {
 			log.Warningf("Decode connection attributes send by the client: %v", err)
+		} else {
+			pos = attrsEnd
+		}
+	}
+
+	// Compression. zlib wins if the client asked for both, as MySQL does.
+	advertised := compressionCapabilities(l.CompressionAlgorithms)
+	switch {
+	case clientFlags&advertised&CapabilityClientCompress != 0:
+		c.compressionAlgorithm = CompressionZlib
+	case clientFlags&advertised&CapabilityClientZstdCompressionAlgorithm != 0:
+		level, _, ok := readByte(data, pos)
+		if !ok {
+			return "", "", nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseClientHandshakePacket: can't read zstd compression level")
+		}
+		if err := validateZstdCompressionLevel(int(level)); err != nil {
+			return "", "", nil, vterrors.Wrapf(err, "parseClientHandshakePacket")
 		}
+		c.compressionAlgorithm = CompressionZstd
+		c.zstdCompressionLevel = int(level)
 	}
 
 	return username, AuthMethodDescription(authMethod), authResponse, nil
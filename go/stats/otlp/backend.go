@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otlp
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"vitess.io/vitess/go/stats"
+)
+
+// exportTimeout bounds the time spent pushing a batch of stats.
+const exportTimeout = 30 * time.Second
+
+var scope = instrumentation.Scope{Name: "vitess.io/vitess/go/stats"}
+
+// backend implements stats.PushBackend
+type backend struct {
+	// The namespace is the name of the binary (vtgate, vttablet, etc.) and
+	// will be prepended to all the stats reported.
+	namespace string
+	// resource describes the process reporting the stats.
+	resource *resource.Resource
+	// exporter sends the stats to the collector.
+	exporter sdkmetric.Exporter
+	// startTime is the start of the cumulative counters and histograms,
+	// which count from the start of the process.
+	startTime time.Time
+}
+
+// PushAll pushes all stats to the OTLP collector
+func (b *backend) PushAll() error {
+	collector := b.collector()
+	collector.collectAll()
+	return b.export(collector.metrics)
+}
+
+// PushOne pushes a single stat to the OTLP collector
+func (b *backend) PushOne(name string, v stats.Variable) error {
+	collector := b.collector()
+	collector.collectOne(name, v)
+	return b.export(collector.metrics)
+}
+
+func (b *backend) collector() *collector {
+	return &collector{
+		namespace: b.namespace,
+		startTime: b.startTime,
+		timestamp: time.Now(),
+	}
+}
+
+func (b *backend) export(metrics []metricdata.Metrics) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+	return b.exporter.Export(ctx, &metricdata.ResourceMetrics{
+		Resource: b.resource,
+		ScopeMetrics: []metricdata.ScopeMetrics{{
+			Scope:   scope,
+			Metrics: metrics,
+		}},
+	})
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otlp
+
+import (
+	"expvar"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"vitess.io/vitess/go/stats"
+)
+
+// collector tracks state for a single pass of stats reporting / data collection.
+type collector struct {
+	namespace string
+	startTime time.Time
+	timestamp time.Time
+	metrics   []metricdata.Metrics
+}
+
+func (c *collector) collectAll() {
+	expvar.Do(func(kv expvar.KeyValue) {
+		c.addExpVar(kv)
+	})
+}
+
+func (c *collector) collectOne(name string, v expvar.Var) {
+	c.addExpVar(expvar.KeyValue{
+		Key:   name,
+		Value: v,
+	})
+}
+
+// addExpVar adds the metric associated with a particular expvar. Counters are
+// exported as cumulative sums, gauges as gauges and timings as histograms,
+// with the labels of the stat as attributes. Durations are reported in
+// seconds. Names follow the Prometheus backend, so dashboards can be shared
+// between both.
+//
+// Strings, rates and expvars that aren't stats aren't exported.
+func (c *collector) addExpVar(kv expvar.KeyValue) {
+	k := kv.Key
+	switch v := kv.Value.(type) {
+	case stats.FloatFunc:
+		c.add(k, v.Help(), "", c.floatGauge(v()))
+	case *stats.Counter:
+		c.add(k, v.Help(), "", c.sum(v.Get()))
+	case *stats.CounterFunc:
+		c.add(k, v.Help(), "", c.sum(v.F()))
+	case *stats.Gauge:
+		c.add(k, v.Help(), "", c.gauge(v.Get()))
+	case *stats.GaugeFloat64:
+		c.add(k, v.Help(), "", c.floatGauge(v.Get()))
+	case *stats.GaugeFunc:
+		c.add(k, v.Help(), "", c.gauge(v.F()))
+	case *stats.CounterDuration:
+		c.add(k, v.Help(), "s", c.floatSum(v.Get().Seconds()))
+	case *stats.CounterDurationFunc:
+		c.add(k, v.Help(), "s", c.floatSum(v.F().Seconds()))
+	case *stats.GaugeDuration:
+		c.add(k, v.Help(), "s", c.floatGauge(v.Get().Seconds()))
+	case *stats.GaugeDurationFunc:
+		c.add(k, v.Help(), "s", c.floatGauge(v.F().Seconds()))
+	case *stats.CountersWithSingleLabel:
+		c.add(k, v.Help(), "", c.sums([]string{v.Label()}, v.Counts()))
+	case *stats.CountersWithMultiLabels:
+		c.add(k, v.Help(), "", c.sums(v.Labels(), v.Counts()))
+	case *stats.CountersFuncWithMultiLabels:
+		c.add(k, v.Help(), "", c.sums(v.Labels(), v.Counts()))
+	case *stats.GaugesWithSingleLabel:
+		c.add(k, v.Help(), "", c.gauges([]string{v.Label()}, v.Counts()))
+	case *stats.GaugesWithMultiLabels:
+		c.add(k, v.Help(), "", c.gauges(v.Labels(), v.Counts()))
+	case *stats.GaugesFuncWithMultiLabels:
+		c.add(k, v.Help(), "", c.gauges(v.Labels(), v.Counts()))
+	case *stats.Timings:
+		c.add(k, v.Help(), "s", c.timings([]string{v.Label()}, v))
+	case *stats.MultiTimings:
+		c.add(k, v.Help(), "s", c.timings(v.Labels(), &v.Timings))
+	case *stats.Histogram:
+		c.add(k, v.Help(), "", metricdata.Histogram[float64]{
+			DataPoints:  []metricdata.HistogramDataPoint[float64]{c.histogramPoint(nil, v, 1)},
+			Temporality: metricdata.CumulativeTemporality,
+		})
+	case *stats.StringMapFuncWithMultiLabels:
+		// Like in Prometheus, each value is a gauge set to 1, with the value
+		// as an attribute.
+		values := v.StringMapFunc()
+		points := make([]metricdata.DataPoint[int64], 0, len(values))
+		for labelVals, val := range values {
+			attrs := append(makeAttributes(v.KeyLabels(), labelVals), attribute.String(normalizeMetric(v.ValueLabel()), val))
+			points = append(points, c.point(attrs, 1))
+		}
+		c.add(k, v.Help(), "", metricdata.Gauge[int64]{DataPoints: points})
+	}
+}
+
+func (c *collector) add(name, help, unit string, data metricdata.Aggregation) {
+	c.metrics = append(c.metrics, metricdata.Metrics{
+		Name:        c.buildName(name),
+		Description: help,
+		Unit:        unit,
+		Data:        data,
+	})
+}
+
+// buildName specifies the namespace as a prefix to the metric name
+func (c *collector) buildName(name string) string {
+	s := strings.TrimPrefix(normalizeMetric(name), c.namespace+"_")
+	return c.namespace + "_" + s
+}
+
+func (c *collector) sum(val int64) metricdata.Sum[int64] {
+	return metricdata.Sum[int64]{
+		DataPoints:  []metricdata.DataPoint[int64]{c.cumulativePoint(nil, val)},
+		Temporality: metricdata.CumulativeTemporality,
+		IsMonotonic: true,
+	}
+}
+
+func (c *collector) sums(labels []string, counts map[string]int64) metricdata.Sum[int64] {
+	points := make([]metricdata.DataPoint[int64], 0, len(counts))
+	for labelVals, val := range counts {
+		points = append(points, c.cumulativePoint(makeAttributes(labels, labelVals), val))
+	}
+	return metricdata.Sum[int64]{
+		DataPoints:  points,
+		Temporality: metricdata.CumulativeTemporality,
+		IsMonotonic: true,
+	}
+}
+
+func (c *collector) floatSum(val float64) metricdata.Sum[float64] {
+	return metricdata.Sum[float64]{
+		DataPoints: []metricdata.DataPoint[float64]{{
+			Attributes: *attribute.EmptySet(),
+			StartTime:  c.startTime,
+			Time:       c.timestamp,
+			Value:      val,
+		}},
+		Temporality: metricdata.CumulativeTemporality,
+		IsMonotonic: true,
+	}
+}
+
+func (c *collector) gauge(val int64) metricdata.Gauge[int64] {
+	return metricdata.Gauge[int64]{DataPoints: []metricdata.DataPoint[int64]{c.point(nil, val)}}
+}
+
+func (c *collector) gauges(labels []string, values map[string]int64) metricdata.Gauge[int64] {
+	points := make([]metricdata.DataPoint[int64], 0, len(values))
+	for labelVals, val := range values {
+		points = append(points, c.point(makeAttributes(labels, labelVals), val))
+	}
+	return metricdata.Gauge[int64]{DataPoints: points}
+}
+
+func (c *collector) floatGauge(val float64) metricdata.Gauge[float64] {
+	return metricdata.Gauge[float64]{
+		DataPoints: []metricdata.DataPoint[float64]{{
+			Attributes: *attribute.EmptySet(),
+			Time:       c.timestamp,
+			Value:      val,
+		}},
+	}
+}
+
+func (c *collector) point(attrs []attribute.KeyValue, val int64) metricdata.DataPoint[int64] {
+	return metricdata.DataPoint[int64]{
+		Attributes: attribute.NewSet(attrs...),
+		Time:       c.timestamp,
+		Value:      val,
+	}
+}
+
+func (c *collector) cumulativePoint(attrs []attribute.KeyValue, val int64) metricdata.DataPoint[int64] {
+	point := c.point(attrs, val)
+	point.StartTime = c.startTime
+	return point
+}
+
+// timings converts a vitess Timings stat to a histogram in seconds.
+func (c *collector) timings(labels []string, timings *stats.Timings) metricdata.Histogram[float64] {
+	histograms := timings.Histograms()
+	points := make([]metricdata.HistogramDataPoint[float64], 0, len(histograms))
+	for labelVals, histogram := range histograms {
+		points = append(points, c.histogramPoint(makeAttributes(labels, labelVals), histogram, float64(time.Second)))
+	}
+	return metricdata.Histogram[float64]{DataPoints: points, Temporality: metricdata.CumulativeTemporality}
+}
+
+func (c *collector) histogramPoint(attrs []attribute.KeyValue, histogram *stats.Histogram, divideBy float64) metricdata.HistogramDataPoint[float64] {
+	cutoffs := histogram.Cutoffs()
+	bounds := make([]float64, len(cutoffs))
+	for i, cutoff := range cutoffs {
+		bounds[i] = float64(cutoff) / divideBy
+	}
+	buckets := histogram.Buckets()
+	counts := make([]uint64, len(buckets))
+	for i, bucket := range buckets {
+		counts[i] = uint64(bucket)
+	}
+	return metricdata.HistogramDataPoint[float64]{
+		Attributes:   attribute.NewSet(attrs...),
+		StartTime:    c.startTime,
+		Time:         c.timestamp,
+		Count:        uint64(histogram.Count()),
+		Bounds:       bounds,
+		BucketCounts: counts,
+		Sum:          float64(histogram.Total()) / divideBy,
+	}
+}
+
+// makeAttributes takes the vitess stat representation of label values ("."-separated list) and breaks it
+// apart into attributes named after the labels.
+func makeAttributes(labelNames []string, labelValsCombined string) []attribute.KeyValue {
+	labelVals := strings.Split(labelValsCombined, ".")
+	attrs := make([]attribute.KeyValue, 0, len(labelNames))
+	for i, v := range labelVals {
+		if i < len(labelNames) {
+			attrs = append(attrs, attribute.String(normalizeMetric(labelNames[i]), v))
+		}
+	}
+	return attrs
+}
+
+// normalizeMetric applies the same conversions as the Prometheus backend:
+// special cases, then camel case to snake case.
+func normalizeMetric(name string) string {
+	r := strings.NewReplacer("VSchema", "vschema", "VtGate", "vtgate")
+	return stats.GetSnakeName(r.Replace(name))
+}
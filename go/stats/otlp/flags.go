@@ -0,0 +1,47 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otlp
+
+import (
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+const (
+	protocolGRPC = "grpc"
+	protocolHTTP = "http/protobuf"
+)
+
+var (
+	otlpEndpoint string
+	otlpProtocol = protocolGRPC
+	otlpInsecure bool
+)
+
+func registerFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&otlpEndpoint, "otlp_metrics_endpoint", otlpEndpoint, "host and port of the OTLP collector to push stats to")
+	fs.StringVar(&otlpProtocol, "otlp_metrics_protocol", otlpProtocol, "protocol used to push stats to the OTLP collector, grpc or http/protobuf")
+	fs.BoolVar(&otlpInsecure, "otlp_metrics_insecure", otlpInsecure, "whether to push stats to the OTLP collector without TLS")
+}
+
+func init() {
+	servenv.OnParseFor("vtbackup", registerFlags)
+	servenv.OnParseFor("vtctld", registerFlags)
+	servenv.OnParseFor("vtgate", registerFlags)
+	servenv.OnParseFor("vttablet", registerFlags)
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+// shutdownTimeout bounds the time spent shutting the exporter down when the
+// process exits.
+const shutdownTimeout = 5 * time.Second
+
+var singletonBackend stats.PushBackend
+
+// Init attempts to create a singleton *otlp.backend and register it as a PushBackend.
+// If it fails to create one, this is a noop. The namespace is the name of the
+// binary, used as the service name and as the prefix of every metric.
+func Init(namespace string) {
+	// Needs to happen in servenv.OnRun() instead of init because it requires flag parsing and logging
+	servenv.OnRun(func() {
+		log.Info("Initializing otlp backend...")
+		b, err := InitWithoutServenv(namespace)
+		if err != nil {
+			log.Infof("Failed to initialize singleton otlp backend: %v", err)
+			return
+		}
+		singletonBackend = b
+		log.Info("Initialized otlp backend.")
+	})
+	servenv.OnClose(func() {
+		b, ok := singletonBackend.(*backend)
+		if !ok {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := b.exporter.Shutdown(ctx); err != nil {
+			log.Warningf("Failed to shut down otlp backend: %v", err)
+		}
+	})
+}
+
+// InitWithoutServenv initializes the otlp backend without servenv
+func InitWithoutServenv(namespace string) (stats.PushBackend, error) {
+	exporter, err := newExporter(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	b, err := newBackend(namespace, exporter)
+	if err != nil {
+		return nil, err
+	}
+	stats.RegisterPushBackend("otlp", b)
+	return b, nil
+}
+
+func newExporter(ctx context.Context) (sdkmetric.Exporter, error) {
+	if otlpEndpoint == "" {
+		return nil, fmt.Errorf("cannot create otlp PushBackend with empty --otlp_metrics_endpoint")
+	}
+
+	switch otlpProtocol {
+	case protocolGRPC:
+		opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(otlpEndpoint)}
+		if otlpInsecure {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+		return otlpmetricgrpc.New(ctx, opts...)
+	case protocolHTTP:
+		opts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(otlpEndpoint)}
+		if otlpInsecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported --otlp_metrics_protocol %q, expected %q or %q", otlpProtocol, protocolGRPC, protocolHTTP)
+	}
+}
+
+func newBackend(namespace string, exporter sdkmetric.Exporter) (*backend, error) {
+	// The common tags describe the process, so they are exported as resource
+	// attributes rather than on every data point. OTEL_RESOURCE_ATTRIBUTES and
+	// OTEL_SERVICE_NAME override them.
+	attrs := []attribute.KeyValue{semconv.ServiceName(namespace)}
+	for k, v := range stats.ParseCommonTags(stats.CommonTags) {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	res, err := resource.New(context.Background(),
+		resource.WithAttributes(attrs...),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &backend{
+		namespace: namespace,
+		resource:  res,
+		exporter:  exporter,
+		startTime: time.Now(),
+	}, nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otlp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+
+	"vitess.io/vitess/go/stats"
+)
+
+// fakeExporter records the metrics it is asked to export.
+type fakeExporter struct {
+	sdkmetric.Exporter
+	exported []*metricdata.ResourceMetrics
+}
+
+func (e *fakeExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	e.exported = append(e.exported, rm)
+	return nil
+}
+
+func checkOutput(t *testing.T, statName string, v stats.Variable, want metricdata.Metrics) {
+	t.Helper()
+	exporter := &fakeExporter{}
+	b, err := newBackend("vtgate", exporter)
+	require.NoError(t, err)
+
+	require.NoError(t, b.PushOne(statName, v))
+
+	require.Len(t, exporter.exported, 1)
+	rm := exporter.exported[0]
+	assert.Equal(t, "vtgate", attributeValue(t, rm.Resource.Set(), "service.name"))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+	metricdatatest.AssertEqual(t, want, rm.ScopeMetrics[0].Metrics[0], metricdatatest.IgnoreTimestamp())
+}
+
+func attributeValue(t *testing.T, set *attribute.Set, key attribute.Key) string {
+	v, ok := set.Value(key)
+	require.True(t, ok, "attribute %v not found", key)
+	return v.AsString()
+}
+
+func TestOTLPCounter(t *testing.T) {
+	name := "OTLPCounterName"
+	c := stats.NewCounter(name, "counter description")
+	c.Add(3)
+
+	checkOutput(t, name, c, metricdata.Metrics{
+		Name:        "vtgate_otlp_counter_name",
+		Description: "counter description",
+		Data: metricdata.Sum[int64]{
+			DataPoints:  []metricdata.DataPoint[int64]{{Value: 3}},
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+		},
+	})
+}
+
+func TestOTLPGaugesWithMultiLabels(t *testing.T) {
+	name := "otlp_gauges_with_multi_labels"
+	g := stats.NewGaugesWithMultiLabels(name, "help", []string{"Keyspace", "ShardName"})
+	g.Set([]string{"ks", "-80"}, 2)
+	g.Set([]string{"ks", "80-"}, 5)
+
+	checkOutput(t, name, g, metricdata.Metrics{
+		Name:        "vtgate_otlp_gauges_with_multi_labels",
+		Description: "help",
+		Data: metricdata.Gauge[int64]{
+			DataPoints: []metricdata.DataPoint[int64]{
+				{Attributes: attribute.NewSet(attribute.String("keyspace", "ks"), attribute.String("shard_name", "-80")), Value: 2},
+				{Attributes: attribute.NewSet(attribute.String("keyspace", "ks"), attribute.String("shard_name", "80-")), Value: 5},
+			},
+		},
+	})
+}
+
+func TestOTLPCounterDuration(t *testing.T) {
+	name := "vtgate_otlp_counter_duration"
+	d := stats.NewCounterDuration(name, "help")
+	d.Add(1500 * time.Millisecond)
+
+	checkOutput(t, name, d, metricdata.Metrics{
+		Name:        "vtgate_otlp_counter_duration",
+		Description: "help",
+		Unit:        "s",
+		Data: metricdata.Sum[float64]{
+			DataPoints:  []metricdata.DataPoint[float64]{{Value: 1.5}},
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+		},
+	})
+}
+
+func TestOTLPMultiTimings(t *testing.T) {
+	name := "otlp_multi_timings"
+	mt := stats.NewMultiTimings(name, "help", []string{"Operation", "Table"})
+	mt.Add([]string{"select", "t1"}, 2*time.Millisecond)
+	mt.Add([]string{"select", "t1"}, 20*time.Second)
+
+	cutoffs := mt.Cutoffs()
+	bounds := make([]float64, len(cutoffs))
+	for i, cutoff := range cutoffs {
+		bounds[i] = time.Duration(cutoff).Seconds()
+	}
+	buckets := make([]uint64, len(cutoffs)+1)
+	buckets[2] = 1 // 2ms falls in the (1ms, 5ms] bucket.
+	buckets[len(cutoffs)] = 1
+
+	checkOutput(t, name, mt, metricdata.Metrics{
+		Name:        "vtgate_otlp_multi_timings",
+		Description: "help",
+		Unit:        "s",
+		Data: metricdata.Histogram[float64]{
+			DataPoints: []metricdata.HistogramDataPoint[float64]{{
+				Attributes:   attribute.NewSet(attribute.String("operation", "select"), attribute.String("table", "t1")),
+				Count:        2,
+				Bounds:       bounds,
+				BucketCounts: buckets,
+				Sum:          20.002,
+			}},
+			Temporality: metricdata.CumulativeTemporality,
+		},
+	})
+}
+
+func TestOTLPStringMapFuncWithMultiLabels(t *testing.T) {
+	name := "otlp_string_map_func_with_multi_labels"
+	smf := stats.NewStringMapFuncWithMultiLabels(name, "help", []string{"Keyspace"}, "Status", func() map[string]string {
+		return map[string]string{"ks": "serving.ok"}
+	})
+
+	checkOutput(t, name, smf, metricdata.Metrics{
+		Name:        "vtgate_otlp_string_map_func_with_multi_labels",
+		Description: "help",
+		Data: metricdata.Gauge[int64]{
+			DataPoints: []metricdata.DataPoint[int64]{
+				{Attributes: attribute.NewSet(attribute.String("keyspace", "ks"), attribute.String("status", "serving.ok")), Value: 1},
+			},
+		},
+	})
+}
+
+func TestOTLPIgnoresStrings(t *testing.T) {
+	name := "otlp_string"
+	v := stats.NewString(name)
+	v.Set("value")
+
+	b, err := newBackend("vtgate", &fakeExporter{})
+	require.NoError(t, err)
+	collector := b.collector()
+	collector.collectOne(name, v)
+	assert.Empty(t, collector.metrics)
+}
@@ -48,6 +48,10 @@ type VSchemaWrapper struct {
 	Version       plancontext.PlannerVersion
 	EnableViews   bool
 	TestBuilder   func(query string, vschema plancontext.VSchema, keyspace string) (*engine.Plan, error)
+
+	// DisableHashJoin stops the planner from choosing hash joins for cross-shard joins,
+	// which vtgate does by default
+	DisableHashJoin bool
 }
 
 func (vw *VSchemaWrapper) GetPrepareData(stmtName string) *vtgatepb.PrepareData {
@@ -327,3 +331,7 @@ func (vw *VSchemaWrapper) FindRoutedShard(keyspace, shard string) (string, error
 func (vw *VSchemaWrapper) IsViewsEnabled() bool {
 	return vw.EnableViews
 }
+
+func (vw *VSchemaWrapper) IsHashJoinEnabled() bool {
+	return !vw.DisableHashJoin
+}
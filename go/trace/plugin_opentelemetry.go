@@ -0,0 +1,400 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"vitess.io/vitess/go/viperutil"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+/*
+This file makes it easy to build Vitess without including the OpenTelemetry
+SDK. All that is needed is to delete this file.
+*/
+
+const (
+	otelProtocolGRPC = "grpc"
+	otelProtocolHTTP = "http/protobuf"
+
+	// otelShutdownTimeout bounds the time spent flushing pending spans to
+	// the collector when the tracer is closed.
+	otelShutdownTimeout = 5 * time.Second
+)
+
+var (
+	otelConfigKey = viperutil.KeyPrefixFunc(configKey("otel"))
+
+	otelProtocol = viperutil.Configure(
+		otelConfigKey("exporter.protocol"),
+		viperutil.Options[string]{
+			Default:  otelProtocolGRPC,
+			EnvVars:  []string{"OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "OTEL_EXPORTER_OTLP_PROTOCOL"},
+			FlagName: "otel-exporter-protocol",
+		},
+	)
+	otelEndpoint = viperutil.Configure(
+		otelConfigKey("exporter.endpoint"),
+		viperutil.Options[string]{
+			FlagName: "otel-exporter-endpoint",
+		},
+	)
+	otelInsecure = viperutil.Configure(
+		otelConfigKey("exporter.insecure"),
+		viperutil.Options[bool]{
+			FlagName: "otel-exporter-insecure",
+		},
+	)
+)
+
+func init() {
+	// If compiled with plugin_opentelemetry, ensure that trace.RegisterFlags
+	// includes OpenTelemetry tracing flags.
+	pluginFlags = append(pluginFlags, func(fs *pflag.FlagSet) {
+		fs.String("otel-exporter-protocol", otelProtocol.Default(), "protocol used to send spans to the OTLP collector. possible values are 'grpc' or 'http/protobuf'")
+		fs.String("otel-exporter-endpoint", "", "host and port of the OTLP collector to send spans to. if empty, OTEL_EXPORTER_OTLP_ENDPOINT or the exporter default is used")
+		fs.Bool("otel-exporter-insecure", false, "whether to send spans to the OTLP collector without TLS")
+
+		viperutil.BindFlags(fs, otelProtocol, otelEndpoint, otelInsecure)
+	})
+}
+
+// newOpenTelemetryTracer will instantiate a tracingService implemented by the
+// OpenTelemetry SDK, exporting spans over OTLP. Besides the flags, the exporter
+// honors the standard OTEL_EXPORTER_OTLP_* environment variables, and the
+// resource honors OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES. Spans are
+// sampled with --tracing-sampling-rate, unless the parent span was propagated
+// with its own sampling decision. Span contexts are propagated in the W3C
+// traceparent and tracestate formats.
+func newOpenTelemetryTracer(serviceName string) (tracingService, io.Closer, error) {
+	ctx := context.Background()
+
+	exporter, err := newOTLPTraceExporter(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Detectors that come later win, so OTEL_SERVICE_NAME overrides the
+	// service name used in code.
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName)),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(samplingRate.Get()))),
+	)
+	propagator := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+	if enableLogging.Get() {
+		otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+			log.Errorf("opentelemetry: %v", err)
+		}))
+	}
+	log.Infof("Tracing to OTLP collector over %v as %v (sampling rate: %v)", otelProtocol.Get(), serviceName, samplingRate.Get())
+
+	return newOpenTelemetryService(provider.Tracer("vitess.io/vitess/go/trace"), propagator), &otelCloser{provider: provider}, nil
+}
+
+func newOTLPTraceExporter(ctx context.Context) (*otlptrace.Exporter, error) {
+	switch protocol := otelProtocol.Get(); protocol {
+	case otelProtocolGRPC:
+		var opts []otlptracegrpc.Option
+		if endpoint := otelEndpoint.Get(); endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(endpoint))
+		}
+		if otelInsecure.Get() {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case otelProtocolHTTP:
+		var opts []otlptracehttp.Option
+		if endpoint := otelEndpoint.Get(); endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(endpoint))
+		}
+		if otelInsecure.Get() {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q, expected %q or %q", protocol, otelProtocolGRPC, otelProtocolHTTP)
+	}
+}
+
+func init() {
+	tracingBackendFactories["opentelemetry"] = newOpenTelemetryTracer
+}
+
+var _ io.Closer = (*otelCloser)(nil)
+
+type otelCloser struct {
+	provider *sdktrace.TracerProvider
+}
+
+// Close flushes pending spans and shuts the tracer provider down.
+func (c *otelCloser) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), otelShutdownTimeout)
+	defer cancel()
+	return c.provider.Shutdown(ctx)
+}
+
+var _ Span = (*otelSpan)(nil)
+
+type otelSpan struct {
+	span oteltrace.Span
+}
+
+// Finish will mark a span as finished
+func (s otelSpan) Finish() {
+	s.span.End()
+}
+
+// Annotate will add information to an existing span
+func (s otelSpan) Annotate(key string, value any) {
+	s.span.SetAttributes(otelAttribute(key, value))
+}
+
+// otelAttribute converts a span annotation to an OpenTelemetry attribute.
+// Values of types OpenTelemetry doesn't support are formatted as strings.
+func otelAttribute(key string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int32:
+		return attribute.Int64(key, int64(v))
+	case int64:
+		return attribute.Int64(key, v)
+	case uint32:
+		return attribute.Int64(key, int64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	case fmt.Stringer:
+		return attribute.Stringer(key, v)
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
+
+var _ tracingService = (*otelTracingService)(nil)
+
+type otelTracingService struct {
+	tracer     oteltrace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+func newOpenTelemetryService(tracer oteltrace.Tracer, propagator propagation.TextMapPropagator) otelTracingService {
+	return otelTracingService{tracer: tracer, propagator: propagator}
+}
+
+// New is part of an interface implementation
+func (s otelTracingService) New(parent Span, label string) Span {
+	ctx := context.Background()
+	if parent, ok := parent.(otelSpan); ok {
+		ctx = oteltrace.ContextWithSpan(ctx, parent.span)
+	}
+	_, span := s.tracer.Start(ctx, label)
+	return otelSpan{span: span}
+}
+
+// NewFromString is part of an interface implementation. The parent is either
+// a W3C traceparent header value, or the base64 encoding of a JSON object
+// holding the propagation headers, as used by VT_SPAN_CONTEXT comments.
+func (s otelTracingService) NewFromString(parent, label string) (Span, error) {
+	carrier, err := otelCarrierFromString(parent)
+	if err != nil {
+		return nil, err
+	}
+	ctx := s.propagator.Extract(context.Background(), carrier)
+	if !oteltrace.SpanContextFromContext(ctx).IsValid() {
+		return nil, vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "failed to deserialize span context")
+	}
+	_, span := s.tracer.Start(ctx, label)
+	return otelSpan{span: span}, nil
+}
+
+func otelCarrierFromString(in string) (propagation.MapCarrier, error) {
+	if strings.Count(in, "-") == 3 {
+		return propagation.MapCarrier{"traceparent": in}, nil
+	}
+	decodedBytes, err := base64.StdEncoding.DecodeString(in)
+	if err != nil {
+		return nil, err
+	}
+	var carrier propagation.MapCarrier
+	if err := json.Unmarshal(decodedBytes, &carrier); err != nil {
+		return nil, err
+	}
+	return carrier, nil
+}
+
+// FromContext is part of an interface implementation
+func (s otelTracingService) FromContext(ctx context.Context) (Span, bool) {
+	span := oteltrace.SpanFromContext(ctx)
+	if !span.SpanContext().IsValid() {
+		return nil, false
+	}
+	return otelSpan{span: span}, true
+}
+
+// NewContext is part of an interface implementation
+func (s otelTracingService) NewContext(parent context.Context, span Span) context.Context {
+	otSpan, ok := span.(otelSpan)
+	if !ok {
+		return nil
+	}
+	return oteltrace.ContextWithSpan(parent, otSpan.span)
+}
+
+// AddGrpcServerOptions is part of an interface implementation. The
+// interceptors extract the span context propagated in the grpc metadata, and
+// start a server span for each call.
+func (s otelTracingService) AddGrpcServerOptions(addInterceptors func(s grpc.StreamServerInterceptor, u grpc.UnaryServerInterceptor)) {
+	addInterceptors(s.streamServerInterceptor, s.unaryServerInterceptor)
+}
+
+// AddGrpcClientOptions is part of an interface implementation. The
+// interceptors propagate the span context in the grpc metadata. Unary calls
+// get a client span of their own.
+func (s otelTracingService) AddGrpcClientOptions(addInterceptors func(s grpc.StreamClientInterceptor, u grpc.UnaryClientInterceptor)) {
+	addInterceptors(s.streamClientInterceptor, s.unaryClientInterceptor)
+}
+
+func (s otelTracingService) startServerSpan(ctx context.Context, method string) (context.Context, oteltrace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = s.propagator.Extract(ctx, metadataCarrier(md))
+	return s.tracer.Start(ctx, method, oteltrace.WithSpanKind(oteltrace.SpanKindServer), oteltrace.WithAttributes(semconv.RPCSystemGRPC))
+}
+
+func (s otelTracingService) unaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, span := s.startServerSpan(ctx, info.FullMethod)
+	defer span.End()
+	resp, err := handler(ctx, req)
+	recordSpanError(span, err)
+	return resp, err
+}
+
+func (s otelTracingService) streamServerInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, span := s.startServerSpan(ss.Context(), info.FullMethod)
+	defer span.End()
+	err := handler(srv, &otelServerStream{ServerStream: ss, ctx: ctx})
+	recordSpanError(span, err)
+	return err
+}
+
+func (s otelTracingService) injectMetadata(ctx context.Context) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	s.propagator.Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+func (s otelTracingService) unaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx, span := s.tracer.Start(ctx, method, oteltrace.WithSpanKind(oteltrace.SpanKindClient), oteltrace.WithAttributes(semconv.RPCSystemGRPC))
+	defer span.End()
+	err := invoker(s.injectMetadata(ctx), method, req, reply, cc, opts...)
+	recordSpanError(span, err)
+	return err
+}
+
+func (s otelTracingService) streamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(s.injectMetadata(ctx), desc, cc, method, opts...)
+}
+
+func recordSpanError(span oteltrace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// otelServerStream overrides the context of a grpc.ServerStream so handlers
+// see the server span.
+type otelServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ss *otelServerStream) Context() context.Context {
+	return ss.ctx
+}
+
+var _ propagation.TextMapCarrier = (metadataCarrier)(nil)
+
+// metadataCarrier adapts grpc metadata to a propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (mc metadataCarrier) Get(key string) string {
+	if values := metadata.MD(mc).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (mc metadataCarrier) Set(key, value string) {
+	metadata.MD(mc).Set(key, value)
+}
+
+func (mc metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(mc))
+	for k := range mc {
+		keys = append(keys, k)
+	}
+	return keys
+}
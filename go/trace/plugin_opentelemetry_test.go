@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func newTestOpenTelemetryService(t *testing.T) (otelTracingService, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	return newOpenTelemetryService(provider.Tracer("test"), propagation.TraceContext{}), recorder
+}
+
+func TestOpenTelemetrySpans(t *testing.T) {
+	svc, recorder := newTestOpenTelemetryService(t)
+
+	parent := svc.New(nil, "parent")
+	ctx := svc.NewContext(context.Background(), parent)
+	fromCtx, ok := svc.FromContext(ctx)
+	require.True(t, ok)
+
+	child := svc.New(fromCtx, "child")
+	child.Annotate("keyspace", "ks")
+	child.Annotate("shards", 2)
+	child.Finish()
+	parent.Finish()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0].Name())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, spans[1].SpanContext().TraceID(), spans[0].SpanContext().TraceID())
+	assert.ElementsMatch(t, []attribute.KeyValue{attribute.String("keyspace", "ks"), attribute.Int("shards", 2)}, spans[0].Attributes())
+
+	_, ok = svc.FromContext(context.Background())
+	assert.False(t, ok)
+}
+
+func TestOpenTelemetryNewFromString(t *testing.T) {
+	svc, recorder := newTestOpenTelemetryService(t)
+
+	jsonBytes, err := json.Marshal(map[string]string{"traceparent": testTraceparent})
+	require.NoError(t, err)
+	for _, parent := range []string{testTraceparent, base64.StdEncoding.EncodeToString(jsonBytes)} {
+		span, err := svc.NewFromString(parent, "label")
+		require.NoError(t, err)
+		span.Finish()
+	}
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	for _, span := range spans {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+		assert.True(t, span.Parent().IsRemote())
+	}
+
+	_, err = svc.NewFromString("this is not base64", "label")
+	assert.Error(t, err)
+	_, err = svc.NewFromString("00-not-a-traceparent", "label")
+	assert.ErrorContains(t, err, "failed to deserialize span context")
+}
+
+func TestOpenTelemetryGrpcPropagation(t *testing.T) {
+	svc, recorder := newTestOpenTelemetryService(t)
+
+	var unaryClient grpc.UnaryClientInterceptor
+	svc.AddGrpcClientOptions(func(_ grpc.StreamClientInterceptor, u grpc.UnaryClientInterceptor) { unaryClient = u })
+	var unaryServer grpc.UnaryServerInterceptor
+	svc.AddGrpcServerOptions(func(_ grpc.StreamServerInterceptor, u grpc.UnaryServerInterceptor) { unaryServer = u })
+
+	root := svc.New(nil, "root")
+	ctx := svc.NewContext(context.Background(), root)
+
+	// The client interceptor sends the metadata to the server interceptor,
+	// as if they were connected over grpc.
+	var serverSpanContext oteltrace.SpanContext
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		assert.NotEmpty(t, md.Get("traceparent"))
+		_, err := unaryServer(metadata.NewIncomingContext(context.Background(), md), req, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+			serverSpanContext = oteltrace.SpanContextFromContext(ctx)
+			return nil, nil
+		})
+		return err
+	}
+	err := unaryClient(ctx, "/queryservice.Query/Execute", nil, nil, nil, invoker)
+	require.NoError(t, err)
+	root.Finish()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	server, client := spans[0], spans[1]
+	assert.Equal(t, oteltrace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, oteltrace.SpanKindClient, client.SpanKind())
+	assert.Equal(t, "/queryservice.Query/Execute", server.Name())
+	assert.Equal(t, client.SpanContext().SpanID(), server.Parent().SpanID())
+	assert.Equal(t, root.(otelSpan).span.SpanContext().SpanID(), client.Parent().SpanID())
+	assert.Equal(t, server.SpanContext(), serverSpanContext)
+}
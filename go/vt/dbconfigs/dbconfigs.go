@@ -89,6 +89,8 @@ type DBConfigs struct {
 	ConnectTimeoutMilliseconds int           `json:"connectTimeoutMilliseconds,omitempty"`
 	DBName                     string        `json:"dbName,omitempty"`
 	EnableQueryInfo            bool          `json:"enableQueryInfo,omitempty"`
+	Compression                string        `json:"compression,omitempty"`
+	ZstdCompressionLevel       int           `json:"zstdCompressionLevel,omitempty"`
 
 	App          UserConfig `json:"app,omitempty"`
 	Dba          UserConfig `json:"dba,omitempty"`
@@ -145,6 +147,8 @@ func registerBaseFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&GlobalDBConfigs.ServerName, "db_server_name", "", "server name of the DB we are connecting to.")
 	fs.IntVar(&GlobalDBConfigs.ConnectTimeoutMilliseconds, "db_connect_timeout_ms", 0, "connection timeout to mysqld in milliseconds (0 for no timeout)")
 	fs.BoolVar(&GlobalDBConfigs.EnableQueryInfo, "db_conn_query_info", false, "enable parsing and processing of QUERY_OK info fields")
+	fs.StringVar(&GlobalDBConfigs.Compression, "db-compression", "", "Compressed protocol to request from mysqld: zlib or zstd. Connections are uncompressed if empty or if mysqld doesn't support it.")
+	fs.IntVar(&GlobalDBConfigs.ZstdCompressionLevel, "db-zstd-compression-level", mysql.DefaultZstdCompressionLevel, "zstd compression level (1-22) used when db-compression is zstd.")
 }
 
 // The flags will change the global singleton
@@ -311,6 +315,22 @@ func (dbcfgs *DBConfigs) Clone() *DBConfigs {
 	return &result
 }
 
+// CloneWithSocket returns a clone of the DBConfigs with the same users, but
+// which connects all of them to the mysqld listening on the given socket file.
+// Global and per-user host, port and socket settings are dropped.
+func (dbcfgs *DBConfigs) CloneWithSocket(socketFile string) *DBConfigs {
+	result := dbcfgs.Clone()
+	result.Host = ""
+	result.Port = 0
+	result.Socket = ""
+	for _, userKey := range All {
+		_, cp := result.getParams(userKey, result)
+		*cp = mysql.ConnParams{}
+	}
+	result.InitWithSocket(socketFile)
+	return result
+}
+
 // InitWithSocket will initialize all the necessary connection parameters.
 // Precedence is as follows: if UserConfig settings are set,
 // they supersede all other settings.
@@ -350,6 +370,8 @@ func (dbcfgs *DBConfigs) InitWithSocket(defaultSocketFile string) {
 		}
 		cp.ConnectTimeoutMs = uint64(dbcfgs.ConnectTimeoutMilliseconds)
 		cp.EnableQueryInfo = dbcfgs.EnableQueryInfo
+		cp.Compression = dbcfgs.Compression
+		cp.ZstdCompressionLevel = dbcfgs.ZstdCompressionLevel
 
 		cp.Uname = uc.User
 		cp.Pass = uc.Password
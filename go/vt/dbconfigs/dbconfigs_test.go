@@ -53,6 +53,8 @@ func TestInit(t *testing.T) {
 		SslCert:                    "f",
 		SslKey:                     "g",
 		ConnectTimeoutMilliseconds: 250,
+		Compression:                "zstd",
+		ZstdCompressionLevel:       7,
 		App: UserConfig{
 			User:     "app",
 			Password: "apppass",
@@ -75,46 +77,52 @@ func TestInit(t *testing.T) {
 	dbConfigs.InitWithSocket("default")
 
 	want := mysql.ConnParams{
-		Host:             "a",
-		Port:             1,
-		Uname:            "app",
-		Pass:             "apppass",
-		UnixSocket:       "b",
-		Charset:          "utf8mb4",
-		Flags:            2,
-		Flavor:           "flavor",
-		ConnectTimeoutMs: 250,
+		Host:                 "a",
+		Port:                 1,
+		Uname:                "app",
+		Pass:                 "apppass",
+		UnixSocket:           "b",
+		Charset:              "utf8mb4",
+		Flags:                2,
+		Flavor:               "flavor",
+		ConnectTimeoutMs:     250,
+		Compression:          "zstd",
+		ZstdCompressionLevel: 7,
 	}
 	assert.Equal(t, want, dbConfigs.appParams)
 
 	want = mysql.ConnParams{
-		Host:             "a",
-		Port:             1,
-		UnixSocket:       "b",
-		Charset:          "utf8mb4",
-		Flags:            2,
-		Flavor:           "flavor",
-		SslCa:            "d",
-		SslCaPath:        "e",
-		SslCert:          "f",
-		SslKey:           "g",
-		ConnectTimeoutMs: 250,
+		Host:                 "a",
+		Port:                 1,
+		UnixSocket:           "b",
+		Charset:              "utf8mb4",
+		Flags:                2,
+		Flavor:               "flavor",
+		SslCa:                "d",
+		SslCaPath:            "e",
+		SslCert:              "f",
+		SslKey:               "g",
+		ConnectTimeoutMs:     250,
+		Compression:          "zstd",
+		ZstdCompressionLevel: 7,
 	}
 	assert.Equal(t, want, dbConfigs.appdebugParams)
 	want = mysql.ConnParams{
-		Host:             "a",
-		Port:             1,
-		Uname:            "dba",
-		Pass:             "dbapass",
-		UnixSocket:       "b",
-		Charset:          "utf8mb4",
-		Flags:            2,
-		Flavor:           "flavor",
-		SslCa:            "d",
-		SslCaPath:        "e",
-		SslCert:          "f",
-		SslKey:           "g",
-		ConnectTimeoutMs: 250,
+		Host:                 "a",
+		Port:                 1,
+		Uname:                "dba",
+		Pass:                 "dbapass",
+		UnixSocket:           "b",
+		Charset:              "utf8mb4",
+		Flags:                2,
+		Flavor:               "flavor",
+		SslCa:                "d",
+		SslCaPath:            "e",
+		SslCert:              "f",
+		SslKey:               "g",
+		ConnectTimeoutMs:     250,
+		Compression:          "zstd",
+		ZstdCompressionLevel: 7,
 	}
 	assert.Equal(t, want, dbConfigs.dbaParams)
 
@@ -221,6 +229,46 @@ func TestUseTCP(t *testing.T) {
 	assert.Equal(t, want, dbConfigs.dbaParams)
 }
 
+func TestCloneWithSocket(t *testing.T) {
+	dbConfigs := DBConfigs{
+		Host:   "a",
+		Port:   1,
+		Socket: "b",
+		App: UserConfig{
+			User:     "app",
+			Password: "apppass",
+			UseTCP:   true,
+		},
+		Dba: UserConfig{
+			User: "dba",
+		},
+		Charset: "utf8",
+		DBName:  "db",
+	}
+	dbConfigs.InitWithSocket("default")
+	clone := dbConfigs.CloneWithSocket("scratch")
+
+	want := mysql.ConnParams{
+		Uname:      "app",
+		Pass:       "apppass",
+		UnixSocket: "scratch",
+		Charset:    "utf8",
+	}
+	assert.Equal(t, want, clone.appParams)
+
+	want = mysql.ConnParams{
+		Uname:      "dba",
+		UnixSocket: "scratch",
+		Charset:    "utf8",
+	}
+	assert.Equal(t, want, clone.dbaParams)
+	assert.Equal(t, "db", clone.DBName)
+
+	// The original is unchanged.
+	assert.Equal(t, "a", dbConfigs.appParams.Host)
+	assert.Equal(t, "b", dbConfigs.dbaParams.UnixSocket)
+}
+
 func TestAccessors(t *testing.T) {
 	dbc := &DBConfigs{
 		appParams:      mysql.ConnParams{},
@@ -0,0 +1,274 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"time"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// BackupRetentionPolicy describes which backups of a shard are kept when
+// pruning old backups. A backup is kept if any of the rules retains it, or if
+// a kept incremental backup depends on it.
+type BackupRetentionPolicy struct {
+	// KeepFullBackups is the number of most recent complete full backups to
+	// keep. The most recent full backup is always kept, so 0 is the same as 1.
+	KeepFullBackups int
+	// PointInTimeWindow, if non zero, keeps the full and incremental backups
+	// needed to restore to any point in time within this window.
+	PointInTimeWindow time.Duration
+	// MinAge, if non zero, keeps every backup younger than this.
+	MinAge time.Duration
+	// MaxAge, if non zero, stops KeepFullBackups and PointInTimeWindow from
+	// keeping backups older than this. The most recent full backup is still
+	// kept.
+	MaxAge time.Duration
+}
+
+// IsEmpty returns true if the policy has no rule set, in which case it only
+// keeps the most recent full backup.
+func (p *BackupRetentionPolicy) IsEmpty() bool {
+	return p.KeepFullBackups == 0 && p.PointInTimeWindow == 0 && p.MinAge == 0 && p.MaxAge == 0
+}
+
+// Validate returns an error if the policy is inconsistent.
+func (p *BackupRetentionPolicy) Validate() error {
+	switch {
+	case p.KeepFullBackups < 0:
+		return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "keep full backups must not be negative, got %d", p.KeepFullBackups)
+	case p.PointInTimeWindow < 0, p.MinAge < 0, p.MaxAge < 0:
+		return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "retention durations must not be negative")
+	case p.MaxAge > 0 && p.PointInTimeWindow > p.MaxAge:
+		return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "point in time window %v is larger than max age %v", p.PointInTimeWindow, p.MaxAge)
+	}
+	return nil
+}
+
+// BackupPruningPlan is the outcome of applying a BackupRetentionPolicy to the
+// backups of a shard. Both lists are sorted oldest first.
+type BackupPruningPlan struct {
+	Retained []backupstorage.BackupHandle
+	Pruned   []backupstorage.BackupHandle
+}
+
+// retentionCandidate is a backup considered for pruning.
+type retentionCandidate struct {
+	handle backupstorage.BackupHandle
+	// manifest is nil if the MANIFEST of the backup can't be read, which means
+	// the backup is either in progress or failed.
+	manifest *BackupManifest
+	time     time.Time
+	retained bool
+}
+
+func (c *retentionCandidate) isFull() bool {
+	return c.manifest != nil && !c.manifest.Incremental
+}
+
+// PlanBackupPruning applies the policy to the given backups, which are
+// expected in the order returned by BackupStorage.ListBackups, i.e. oldest
+// first. Backups whose time can't be determined are always retained, and so
+// are backups without a MANIFEST that are newer than the most recent complete
+// backup, since they may still be in progress.
+func PlanBackupPruning(ctx context.Context, logger logutil.Logger, bhs []backupstorage.BackupHandle, policy *BackupRetentionPolicy, now time.Time) (*BackupPruningPlan, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	candidates := make([]*retentionCandidate, 0, len(bhs))
+	latestComplete := -1
+	for i, bh := range bhs {
+		c := &retentionCandidate{handle: bh}
+		if bm, err := GetBackupManifest(ctx, bh); err != nil {
+			logger.Warningf("Possibly incomplete backup %v in directory %v on BackupStorage: %v", bh.Name(), bh.Directory(), err)
+		} else {
+			c.manifest = bm
+			latestComplete = i
+		}
+		var err error
+		if c.time, err = backupTime(bh, c.manifest); err != nil {
+			logger.Warningf("Retaining backup %v in directory %v: %v", bh.Name(), bh.Directory(), err)
+			c.retained = true
+		}
+		candidates = append(candidates, c)
+	}
+
+	retain := func(c *retentionCandidate) { c.retained = true }
+	isTooOld := func(c *retentionCandidate) bool {
+		return policy.MaxAge > 0 && now.Sub(c.time) > policy.MaxAge
+	}
+
+	var fulls []*retentionCandidate
+	for i, c := range candidates {
+		switch {
+		case policy.MinAge > 0 && now.Sub(c.time) < policy.MinAge:
+			retain(c)
+		case c.manifest == nil && i > latestComplete:
+			retain(c)
+		}
+		if c.isFull() {
+			fulls = append(fulls, c)
+		}
+	}
+
+	// Keep the most recent full backups.
+	kept := 0
+	for i := len(fulls) - 1; i >= 0; i-- {
+		c := fulls[i]
+		if i == len(fulls)-1 || (kept < policy.KeepFullBackups && !isTooOld(c)) {
+			retain(c)
+			kept++
+		}
+	}
+
+	// Keep what's needed to restore to any point in the window: the most
+	// recent full backup taken before the window starts, and every complete
+	// backup taken since. If no full backup predates the window, the window
+	// starts with the oldest full backup we can keep.
+	if policy.PointInTimeWindow > 0 {
+		windowStart := now.Add(-policy.PointInTimeWindow)
+		var base *retentionCandidate
+		for _, c := range fulls {
+			if isTooOld(c) {
+				continue
+			}
+			if base == nil || !c.time.After(windowStart) {
+				base = c
+			}
+		}
+		if base != nil {
+			for _, c := range candidates {
+				if c.manifest != nil && !c.time.Before(base.time) {
+					retain(c)
+				}
+			}
+		}
+	}
+
+	// Never break the chain of a retained incremental backup.
+	var incrementals []*retentionCandidate
+	for _, c := range candidates {
+		if c.retained && c.manifest != nil && c.manifest.Incremental {
+			incrementals = append(incrementals, c)
+		}
+	}
+	for len(incrementals) > 0 {
+		c := incrementals[0]
+		incrementals = incrementals[1:]
+		parent := incrementalBackupParent(c, candidates)
+		switch {
+		case parent == nil:
+			logger.Warningf("Cannot find the backup that incremental backup %v in directory %v is based on", c.handle.Name(), c.handle.Directory())
+		case !parent.retained:
+			retain(parent)
+			if parent.manifest.Incremental {
+				incrementals = append(incrementals, parent)
+			}
+		}
+	}
+
+	plan := &BackupPruningPlan{}
+	for _, c := range candidates {
+		if c.retained {
+			plan.Retained = append(plan.Retained, c.handle)
+		} else {
+			plan.Pruned = append(plan.Pruned, c.handle)
+		}
+	}
+	return plan, nil
+}
+
+// PruneBackups removes the backups in backupDir that are not retained by the
+// policy. They are removed newest first, so that an interrupted run doesn't
+// leave incremental backups behind without their base. In dry run mode,
+// nothing is removed.
+func PruneBackups(ctx context.Context, logger logutil.Logger, bs backupstorage.BackupStorage, backupDir string, policy *BackupRetentionPolicy, dryRun bool) (*BackupPruningPlan, error) {
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+	plan, err := PlanBackupPruning(ctx, logger, bhs, policy, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for i := len(plan.Pruned) - 1; i >= 0; i-- {
+		bh := plan.Pruned[i]
+		if dryRun {
+			logger.Infof("Dry run: would remove backup %v from %v", bh.Name(), backupDir)
+			continue
+		}
+		logger.Infof("Removing backup %v from %v", bh.Name(), backupDir)
+		if err := bs.RemoveBackup(ctx, backupDir, bh.Name()); err != nil {
+			return nil, vterrors.Wrapf(err, "cannot remove backup %v from %v", bh.Name(), backupDir)
+		}
+	}
+	return plan, nil
+}
+
+// backupTime returns the time a backup was taken, from its MANIFEST if there
+// is one, or from its name otherwise.
+func backupTime(bh backupstorage.BackupHandle, manifest *BackupManifest) (time.Time, error) {
+	if manifest != nil && manifest.BackupTime != "" {
+		if t, err := ParseRFC3339(manifest.BackupTime); err == nil {
+			return t, nil
+		}
+	}
+	t, _, err := ParseBackupName(bh.Directory(), bh.Name())
+	if err != nil {
+		return time.Time{}, err
+	}
+	if t == nil {
+		return time.Time{}, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "cannot parse the time of backup %v", bh.Name())
+	}
+	return *t, nil
+}
+
+// incrementalBackupParent returns the backup an incremental backup is based
+// on: the backup it names in FromBackup, or else the most recent complete
+// backup taken before it whose position is, or includes, its from position.
+func incrementalBackupParent(c *retentionCandidate, candidates []*retentionCandidate) *retentionCandidate {
+	manifest := c.manifest
+	if manifest.FromBackup != "" {
+		for _, p := range candidates {
+			if p != c && p.manifest != nil && p.handle.Name() == manifest.FromBackup {
+				return p
+			}
+		}
+	}
+	if manifest.FromPosition.IsZero() {
+		return nil
+	}
+	var containing *retentionCandidate
+	for i := len(candidates) - 1; i >= 0; i-- {
+		p := candidates[i]
+		if p == c || p.manifest == nil || p.time.After(c.time) || p.manifest.Position.IsZero() {
+			continue
+		}
+		if p.manifest.Position.Equal(manifest.FromPosition) {
+			return p
+		}
+		if containing == nil && p.manifest.Position.GTIDSet.Contains(manifest.FromPosition.GTIDSet) && !p.manifest.Position.GTIDSet.Contains(manifest.Position.GTIDSet) {
+			containing = p
+		}
+	}
+	return containing
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+const retentionTestDir = "ks/0"
+
+// retentionTestBackup describes a backup created by createRetentionTestBackups.
+// A backup without a position has no MANIFEST.
+type retentionTestBackup struct {
+	name         string
+	position     string
+	fromPosition string
+	fromBackup   string
+}
+
+// retentionTestBackups are the backups of a shard with three chains of
+// incremental backups, an in progress backup and a failed one.
+var retentionTestBackups = []retentionTestBackup{
+	{name: "2023-05-31.000000.zone1-100"},
+	{name: "2023-06-01.000000.zone1-100", position: "1-100"},
+	{name: "2023-06-02.000000.zone1-100", position: "1-200", fromPosition: "1-100"},
+	{name: "2023-06-10.000000.zone1-100", position: "1-1000"},
+	{name: "2023-06-11.000000.zone1-100", position: "1-1100", fromPosition: "1-1000"},
+	{name: "2023-06-20.000000.zone1-100", position: "1-2000"},
+	{name: "2023-06-25.000000.zone1-100", position: "1-2500", fromPosition: "1-2000"},
+	{name: "2023-06-28.000000.zone1-100", position: "1-2800", fromPosition: "1-2500", fromBackup: "2023-06-25.000000.zone1-100"},
+	{name: "2023-06-29.000000.zone1-100", position: "1-2900", fromPosition: "1-1100"},
+	{name: "2023-06-29.120000.zone1-100"},
+}
+
+var retentionTestNow = time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC)
+
+func retentionTestPosition(t *testing.T, gtids string) replication.Position {
+	if gtids == "" {
+		return replication.Position{}
+	}
+	pos, err := replication.DecodePosition("MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:" + gtids)
+	require.NoError(t, err)
+	return pos
+}
+
+func createRetentionTestBackups(t *testing.T, ctx context.Context) backupstorage.BackupStorage {
+	root := filebackupstorage.FileBackupStorageRoot
+	t.Cleanup(func() { filebackupstorage.FileBackupStorageRoot = root })
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	bs := backupstorage.BackupStorageMap["file"]
+
+	for _, b := range retentionTestBackups {
+		bh, err := bs.StartBackup(ctx, retentionTestDir, b.name)
+		require.NoError(t, err)
+		if b.position == "" {
+			continue
+		}
+		backupTime, _, err := ParseBackupName(retentionTestDir, b.name)
+		require.NoError(t, err)
+		manifest, err := json.Marshal(&BackupManifest{
+			BackupMethod: builtinBackupEngineName,
+			Position:     retentionTestPosition(t, b.position),
+			FromPosition: retentionTestPosition(t, b.fromPosition),
+			FromBackup:   b.fromBackup,
+			Incremental:  b.fromPosition != "",
+			BackupTime:   FormatRFC3339(*backupTime),
+		})
+		require.NoError(t, err)
+		w, err := bh.AddFile(ctx, backupManifestFileName, int64(len(manifest)))
+		require.NoError(t, err)
+		_, err = w.Write(manifest)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		require.NoError(t, bh.EndBackup(ctx))
+	}
+	return bs
+}
+
+func backupNames(bhs []backupstorage.BackupHandle) []string {
+	names := make([]string, 0, len(bhs))
+	for _, bh := range bhs {
+		names = append(names, bh.Name())
+	}
+	return names
+}
+
+func TestPlanBackupPruning(t *testing.T) {
+	ctx := context.Background()
+	bs := createRetentionTestBackups(t, ctx)
+
+	const day = 24 * time.Hour
+	tcs := []struct {
+		name     string
+		policy   BackupRetentionPolicy
+		retained []int
+	}{
+		{
+			name:     "latest full backup",
+			policy:   BackupRetentionPolicy{},
+			retained: []int{5, 9},
+		},
+		{
+			name:     "full backups",
+			policy:   BackupRetentionPolicy{KeepFullBackups: 3},
+			retained: []int{1, 3, 5, 9},
+		},
+		{
+			name:     "max age",
+			policy:   BackupRetentionPolicy{KeepFullBackups: 3, MaxAge: 25 * day},
+			retained: []int{3, 5, 9},
+		},
+		{
+			name:     "min age keeps the chain of incremental backups",
+			policy:   BackupRetentionPolicy{MinAge: 2 * day},
+			retained: []int{3, 4, 5, 8, 9},
+		},
+		{
+			name:     "from backup",
+			policy:   BackupRetentionPolicy{MinAge: 2*day + time.Hour},
+			retained: []int{3, 4, 5, 6, 7, 8, 9},
+		},
+		{
+			name:     "point in time window",
+			policy:   BackupRetentionPolicy{PointInTimeWindow: 7 * day},
+			retained: []int{3, 4, 5, 6, 7, 8, 9},
+		},
+		{
+			name:     "point in time window larger than the backups",
+			policy:   BackupRetentionPolicy{PointInTimeWindow: 60 * day},
+			retained: []int{1, 2, 3, 4, 5, 6, 7, 8, 9},
+		},
+		{
+			name:     "point in time window within max age",
+			policy:   BackupRetentionPolicy{PointInTimeWindow: 15 * day, MaxAge: 15 * day},
+			retained: []int{3, 4, 5, 6, 7, 8, 9},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			bhs, err := bs.ListBackups(ctx, retentionTestDir)
+			require.NoError(t, err)
+			plan, err := PlanBackupPruning(ctx, logutil.NewMemoryLogger(), bhs, &tc.policy, retentionTestNow)
+			require.NoError(t, err)
+
+			var retained, pruned []string
+			for i, b := range retentionTestBackups {
+				if slices.Contains(tc.retained, i) {
+					retained = append(retained, b.name)
+				} else {
+					pruned = append(pruned, b.name)
+				}
+			}
+			assert.Equal(t, retained, backupNames(plan.Retained))
+			assert.Equal(t, pruned, backupNames(plan.Pruned))
+		})
+	}
+}
+
+func TestBackupRetentionPolicyValidate(t *testing.T) {
+	assert.NoError(t, (&BackupRetentionPolicy{KeepFullBackups: 2, PointInTimeWindow: time.Hour, MaxAge: time.Hour}).Validate())
+	assert.ErrorContains(t, (&BackupRetentionPolicy{KeepFullBackups: -1}).Validate(), "must not be negative")
+	assert.ErrorContains(t, (&BackupRetentionPolicy{MinAge: -time.Hour}).Validate(), "must not be negative")
+	assert.ErrorContains(t, (&BackupRetentionPolicy{PointInTimeWindow: 2 * time.Hour, MaxAge: time.Hour}).Validate(), "point in time window 2h0m0s is larger than max age 1h0m0s")
+}
+
+func TestPruneBackups(t *testing.T) {
+	ctx := context.Background()
+	bs := createRetentionTestBackups(t, ctx)
+
+	policy := &BackupRetentionPolicy{KeepFullBackups: 2}
+	plan, err := PruneBackups(ctx, logutil.NewMemoryLogger(), bs, retentionTestDir, policy, true)
+	require.NoError(t, err)
+	assert.Len(t, plan.Pruned, 7)
+
+	// A dry run doesn't remove anything.
+	bhs, err := bs.ListBackups(ctx, retentionTestDir)
+	require.NoError(t, err)
+	assert.Len(t, bhs, len(retentionTestBackups))
+
+	plan, err = PruneBackups(ctx, logutil.NewMemoryLogger(), bs, retentionTestDir, policy, false)
+	require.NoError(t, err)
+	assert.Len(t, plan.Pruned, 7)
+
+	bhs, err = bs.ListBackups(ctx, retentionTestDir)
+	require.NoError(t, err)
+	assert.Equal(t, backupNames(plan.Retained), backupNames(bhs))
+
+	_, err = PruneBackups(ctx, logutil.NewMemoryLogger(), bs, retentionTestDir, &BackupRetentionPolicy{KeepFullBackups: -1}, false)
+	assert.ErrorContains(t, err, "must not be negative")
+}
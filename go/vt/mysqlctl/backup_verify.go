@@ -0,0 +1,348 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"os"
+	"time"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/vt/dbconfigs"
+	"vitess.io/vitess/go/vt/env"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const (
+	// restoredTablesQuery lists the tables of a restored mysqld that are
+	// checked when verifying a backup.
+	restoredTablesQuery = "SELECT table_schema, table_name FROM information_schema.tables WHERE table_type = 'BASE TABLE' AND table_schema NOT IN ('information_schema', 'mysql', 'performance_schema', 'sys') ORDER BY table_schema, table_name"
+
+	// checkTableOK and checkTableUpToDate are the CHECK TABLE statuses of a
+	// healthy table.
+	checkTableOK       = "OK"
+	checkTableUpToDate = "Table is already up to date"
+
+	scratchMysqldShutdownTimeout = 30 * time.Second
+)
+
+// VerifyBackupParams are the parameters of VerifyBackup.
+type VerifyBackupParams struct {
+	// Cnf and Mysqld are those of the scratch mysqld the backup is restored
+	// into. Its data is deleted.
+	Cnf    *Mycnf
+	Mysqld MysqlDaemon
+	Logger logutil.Logger
+	// Concurrency is the number of files restored in parallel.
+	Concurrency int
+	// Extra env variables for pre-restore and post-restore transform hooks
+	HookExtraEnv map[string]string
+	// DbName is the name of the managed database / schema
+	DbName string
+	// Keyspace and Shard are used to infer the directory where backups are stored
+	Keyspace string
+	Shard    string
+	// BackupName is the backup to verify. If empty, the most recent complete
+	// backup is verified.
+	BackupName string
+	// Stats let's restore engines report detailed restore timings.
+	Stats backupstats.Stats
+}
+
+// BackupVerification is the outcome of VerifyBackup.
+type BackupVerification struct {
+	BackupName string
+	Directory  string
+	// Position is the replication position recorded in the backup MANIFEST.
+	Position replication.Position
+	// RestoredPosition is the replication position of the scratch mysqld
+	// after the restore.
+	RestoredPosition replication.Position
+	Tables           []TableCheck
+	// Errors lists the reasons the verification failed.
+	Errors []string
+}
+
+// OK returns true if the backup was verified successfully.
+func (v *BackupVerification) OK() bool {
+	return len(v.Errors) == 0
+}
+
+func (v *BackupVerification) fail(logger logutil.Logger, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	logger.Errorf("VerifyBackup: %s", msg)
+	v.Errors = append(v.Errors, msg)
+}
+
+// TableCheck is the outcome of checking a restored table.
+type TableCheck struct {
+	Database string
+	Table    string
+	RowCount uint64
+	// Status is the last status reported by CHECK TABLE, or the error that
+	// prevented checking the table.
+	Status string
+	OK     bool
+}
+
+// VerifyBackup restores a backup into the scratch mysqld of the params, and
+// checks that the restored position is the one of the backup MANIFEST and
+// that every restored table passes CHECK TABLE. A backup that fails
+// verification is reported in the returned BackupVerification, the error is
+// only set if the verification couldn't be carried out.
+func VerifyBackup(ctx context.Context, params VerifyBackupParams) (*BackupVerification, error) {
+	if params.Stats == nil {
+		params.Stats = backupstats.NoStats()
+	}
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+
+	backupDir := GetBackupDir(params.Keyspace, params.Shard)
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+	bh, manifest, err := findBackupToVerify(ctx, params.Logger, bhs, params.BackupName)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &BackupVerification{
+		BackupName: bh.Name(),
+		Directory:  backupDir,
+		Position:   manifest.Position,
+	}
+	params.Logger.Infof("VerifyBackup: verifying backup %v/%v at position %v", backupDir, bh.Name(), manifest.Position)
+
+	restoreParams := RestoreParams{
+		Cnf:                 params.Cnf,
+		Mysqld:              params.Mysqld,
+		Logger:              params.Logger,
+		Concurrency:         params.Concurrency,
+		HookExtraEnv:        params.HookExtraEnv,
+		DeleteBeforeRestore: true,
+		DbName:              params.DbName,
+		Keyspace:            params.Keyspace,
+		Shard:               params.Shard,
+		Stats:               params.Stats,
+	}
+	// Restore picks the backups to restore by itself: a full backup is found
+	// by its time, and an incremental one by the position it reaches.
+	if manifest.Incremental {
+		restoreParams.RestoreToPos = manifest.Position
+	} else {
+		backupTime, err := ParseRFC3339(manifest.BackupTime)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot parse the time of backup %v", bh.Name())
+		}
+		restoreParams.StartTime = backupTime
+	}
+	restoredManifest, err := Restore(ctx, restoreParams)
+	switch {
+	case ctx.Err() != nil:
+		return nil, ctx.Err()
+	case err != nil:
+		v.fail(params.Logger, "restore failed: %v", err)
+		return v, nil
+	case !manifest.Incremental && restoredManifest.HashKey() != manifest.HashKey():
+		v.fail(params.Logger, "restored backup taken at %v instead of %v", restoredManifest.BackupTime, manifest.BackupTime)
+		return v, nil
+	}
+
+	restoredPos, err := params.Mysqld.PrimaryPosition()
+	if err != nil {
+		v.fail(params.Logger, "cannot read the restored position: %v", err)
+	} else {
+		v.RestoredPosition = restoredPos
+		if !restoredPos.Equal(manifest.Position) {
+			v.fail(params.Logger, "restored position %v doesn't match the backup position %v", restoredPos, manifest.Position)
+		}
+	}
+
+	if err := checkRestoredTables(ctx, params.Mysqld, params.Logger, v); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		v.fail(params.Logger, "cannot list the restored tables: %v", err)
+	}
+
+	if v.OK() {
+		params.Logger.Infof("VerifyBackup: backup %v/%v verified, %v tables checked", backupDir, bh.Name(), len(v.Tables))
+	}
+	return v, nil
+}
+
+// findBackupToVerify returns the named backup, or the most recent complete
+// backup if name is empty, with its manifest.
+func findBackupToVerify(ctx context.Context, logger logutil.Logger, bhs []backupstorage.BackupHandle, name string) (backupstorage.BackupHandle, *BackupManifest, error) {
+	if name == "" {
+		bh, manifest, err := FindLatestSuccessfulBackup(ctx, logger, bhs, "")
+		if err == ErrNoCompleteBackup && len(bhs) == 0 {
+			err = ErrNoBackup
+		}
+		return bh, manifest, err
+	}
+	for _, bh := range bhs {
+		if bh.Name() != name {
+			continue
+		}
+		manifest, err := GetBackupManifest(ctx, bh)
+		if err != nil {
+			return nil, nil, vterrors.Wrapf(err, "backup %v is incomplete", name)
+		}
+		return bh, manifest, nil
+	}
+	return nil, nil, vterrors.Errorf(vtrpc.Code_NOT_FOUND, "backup %v not found", name)
+}
+
+// checkRestoredTables runs CHECK TABLE and counts the rows of every table of
+// the restored mysqld, and records the outcome in v.
+func checkRestoredTables(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, v *BackupVerification) error {
+	qr, err := mysqld.FetchSuperQuery(ctx, restoredTablesQuery)
+	if err != nil {
+		return err
+	}
+	for _, row := range qr.Rows {
+		check := TableCheck{
+			Database: row[0].ToString(),
+			Table:    row[1].ToString(),
+		}
+		if err := checkRestoredTable(ctx, mysqld, &check); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			check.Status = err.Error()
+		}
+		v.Tables = append(v.Tables, check)
+		if !check.OK {
+			v.fail(logger, "table %v.%v failed CHECK TABLE: %v", check.Database, check.Table, check.Status)
+		}
+	}
+	return nil
+}
+
+func checkRestoredTable(ctx context.Context, mysqld MysqlDaemon, check *TableCheck) error {
+	name := sqlescape.EscapeID(check.Database) + "." + sqlescape.EscapeID(check.Table)
+
+	qr, err := mysqld.FetchSuperQuery(ctx, "CHECK TABLE "+name)
+	if err != nil {
+		return err
+	}
+	// CHECK TABLE returns any number of info, warning and error rows,
+	// followed by a status row.
+	var errorText string
+	for _, row := range qr.Named().Rows {
+		switch row.AsString("Msg_type", "") {
+		case "status":
+			check.Status = row.AsString("Msg_text", "")
+		case "error":
+			if errorText == "" {
+				errorText = row.AsString("Msg_text", "")
+			}
+		}
+	}
+	check.OK = errorText == "" && (check.Status == checkTableOK || check.Status == checkTableUpToDate)
+	if errorText != "" {
+		check.Status = errorText
+	}
+
+	qr, err = mysqld.FetchSuperQuery(ctx, "SELECT COUNT(*) FROM "+name)
+	if err != nil {
+		check.OK = false
+		return err
+	}
+	if len(qr.Rows) == 1 && len(qr.Rows[0]) == 1 {
+		check.RowCount, err = qr.Rows[0][0].ToCastUint64()
+	}
+	return err
+}
+
+// NewScratchMysqld creates, initializes and starts a mysqld with its own
+// tablet directory under VTDATAROOT, a random server id and a free port, for
+// work that must not touch the mysqld of the tablet, like verifying backups.
+// The mysqld is reached over its socket with the users of dbcfgs. The returned
+// function shuts it down and removes its directory.
+//
+// A scratch mysqld can't be started when mysqld is managed by mysqlctld or is
+// remote.
+func NewScratchMysqld(ctx context.Context, dbcfgs *dbconfigs.DBConfigs, initDBSQLFile string) (*Mysqld, *Mycnf, func(), error) {
+	if socketFile != "" || dbcfgs.HasGlobalSettings() {
+		return nil, nil, nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "a scratch mysqld needs a locally managed mysqld")
+	}
+
+	bigN, err := rand.Int(rand.Reader, big.NewInt(math.MaxUint32))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("can't generate random tablet UID: %v", err)
+	}
+	uid := uint32(bigN.Uint64())
+	port, err := freePort()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("can't find a free port for the scratch mysqld: %v", err)
+	}
+	tabletDir := DefaultTabletDirAtRoot(env.VtDataRoot(), uid)
+	cnf := newMycnfAtDir(tabletDir, uid, port)
+	if err := cnf.RandomizeMysqlServerID(); err != nil {
+		return nil, nil, nil, fmt.Errorf("couldn't generate random MySQL server_id: %v", err)
+	}
+
+	mysqld := NewMysqld(dbcfgs.CloneWithSocket(cnf.SocketFile))
+	cleanup := func() {
+		// Don't use the original context, it may be done already.
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), scratchMysqldShutdownTimeout)
+		defer cancel()
+		if err := mysqld.Shutdown(shutdownCtx, cnf, true); err != nil {
+			log.Errorf("failed to shutdown scratch mysqld: %v", err)
+		}
+		mysqld.Close()
+		log.Infof("Removing scratch tablet directory: %v", tabletDir)
+		if err := os.RemoveAll(tabletDir); err != nil {
+			log.Warningf("Failed to remove scratch tablet directory: %v", err)
+		}
+	}
+
+	log.Infof("Starting scratch mysqld in %v on port %v", tabletDir, port)
+	if err := mysqld.Init(ctx, cnf, initDBSQLFile); err != nil {
+		cleanup()
+		return nil, nil, nil, fmt.Errorf("failed to initialize the scratch mysqld: %v", err)
+	}
+	return mysqld, cnf, cleanup, nil
+}
+
+// freePort returns a TCP port that is free at the time of the call.
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+)
+
+func checkTableResult(rows ...string) *sqltypes.Result {
+	values := make([]string, 0, len(rows)/2)
+	for i := 0; i < len(rows); i += 2 {
+		values = append(values, "t|check|"+rows[i]+"|"+rows[i+1])
+	}
+	return sqltypes.MakeTestResult(sqltypes.MakeTestFields("Table|Op|Msg_type|Msg_text", "varchar|varchar|varchar|varchar"), values...)
+}
+
+func TestCheckRestoredTables(t *testing.T) {
+	ctx := context.Background()
+	mysqld := NewFakeMysqlDaemon(nil)
+	mysqld.FetchSuperQueryMap = map[string]*sqltypes.Result{
+		restoredTablesQuery: sqltypes.MakeTestResult(sqltypes.MakeTestFields("table_schema|table_name", "varchar|varchar"),
+			"_vt|heartbeat",
+			"vt_ks|t1",
+			"vt_ks|t2",
+			"vt_ks|t3",
+		),
+		"CHECK TABLE `_vt`.`heartbeat`":          checkTableResult("status", "OK"),
+		"SELECT COUNT(*) FROM `_vt`.`heartbeat`": sqltypes.MakeTestResult(sqltypes.MakeTestFields("count(*)", "int64"), "1"),
+		"CHECK TABLE `vt_ks`.`t1`":               checkTableResult("status", "Table is already up to date"),
+		"SELECT COUNT(*) FROM `vt_ks`.`t1`":      sqltypes.MakeTestResult(sqltypes.MakeTestFields("count(*)", "int64"), "42"),
+		"CHECK TABLE `vt_ks`.`t2`":               checkTableResult("warning", "1 client is using or hasn't closed the table properly", "error", "Found key at page 1024 that points to record outside datafile", "status", "Corrupt"),
+		"SELECT COUNT(*) FROM `vt_ks`.`t2`":      sqltypes.MakeTestResult(sqltypes.MakeTestFields("count(*)", "int64"), "7"),
+		"CHECK TABLE `vt_ks`.`t3`":               checkTableResult("status", "OK"),
+	}
+
+	v := &BackupVerification{}
+	err := checkRestoredTables(ctx, mysqld, logutil.NewMemoryLogger(), v)
+	require.NoError(t, err)
+
+	assert.Equal(t, []TableCheck{
+		{Database: "_vt", Table: "heartbeat", RowCount: 1, Status: "OK", OK: true},
+		{Database: "vt_ks", Table: "t1", RowCount: 42, Status: "Table is already up to date", OK: true},
+		{Database: "vt_ks", Table: "t2", RowCount: 7, Status: "Found key at page 1024 that points to record outside datafile"},
+		{Database: "vt_ks", Table: "t3", Status: "unexpected query: SELECT COUNT(*) FROM `vt_ks`.`t3`"},
+	}, v.Tables)
+	assert.False(t, v.OK())
+	assert.Equal(t, []string{
+		"table vt_ks.t2 failed CHECK TABLE: Found key at page 1024 that points to record outside datafile",
+		"table vt_ks.t3 failed CHECK TABLE: unexpected query: SELECT COUNT(*) FROM `vt_ks`.`t3`",
+	}, v.Errors)
+}
+
+func TestFindBackupToVerify(t *testing.T) {
+	ctx := context.Background()
+	bs := createRetentionTestBackups(t, ctx)
+	bhs, err := bs.ListBackups(ctx, retentionTestDir)
+	require.NoError(t, err)
+	logger := logutil.NewMemoryLogger()
+
+	bh, manifest, err := findBackupToVerify(ctx, logger, bhs, "")
+	require.NoError(t, err)
+	assert.Equal(t, "2023-06-29.000000.zone1-100", bh.Name())
+	assert.True(t, manifest.Incremental)
+
+	bh, manifest, err = findBackupToVerify(ctx, logger, bhs, "2023-06-10.000000.zone1-100")
+	require.NoError(t, err)
+	assert.Equal(t, "2023-06-10.000000.zone1-100", bh.Name())
+	assert.False(t, manifest.Incremental)
+
+	_, _, err = findBackupToVerify(ctx, logger, bhs, "2023-06-29.120000.zone1-100")
+	assert.ErrorContains(t, err, "backup 2023-06-29.120000.zone1-100 is incomplete")
+
+	_, _, err = findBackupToVerify(ctx, logger, bhs, "2023-07-01.000000.zone1-100")
+	assert.ErrorContains(t, err, "backup 2023-07-01.000000.zone1-100 not found")
+
+	_, _, err = findBackupToVerify(ctx, logger, nil, "")
+	assert.ErrorIs(t, err, ErrNoBackup)
+}
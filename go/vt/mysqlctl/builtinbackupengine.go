@@ -123,6 +123,10 @@ type builtinBackupManifest struct {
 	// ExternalDecompressor will be used. If neither are set, the restore will
 	// abort.
 	ExternalDecompressor string
+
+	// Encryption is set if the backup files were encrypted, after being
+	// compressed. It holds the encrypted data key needed to restore them.
+	Encryption *BackupEncryption `json:",omitempty"`
 }
 
 // FileEntry is one file to backup
@@ -578,6 +582,12 @@ func (be *BuiltinBackupEngine) backupFiles(
 	}
 	params.Logger.Infof("found %v files to backup", len(fes))
 
+	// Generate the data key of the backup, if encryption is enabled.
+	encryption, dataKey, err := newBackupEncryption(ctx)
+	if err != nil {
+		return vterrors.Wrap(err, "can't set up backup encryption")
+	}
+
 	// Backup with the provided concurrency.
 	sema := semaphore.NewWeighted(int64(params.Concurrency))
 	wg := sync.WaitGroup{}
@@ -613,7 +623,7 @@ func (be *BuiltinBackupEngine) backupFiles(
 
 			// Backup the individual file.
 			name := fmt.Sprintf("%v", i)
-			bh.RecordError(be.backupFile(ctx, params, bh, fe, name))
+			bh.RecordError(be.backupFile(ctx, params, bh, fe, name, dataKey))
 		}(i)
 	}
 
@@ -669,6 +679,7 @@ func (be *BuiltinBackupEngine) backupFiles(
 		SkipCompress:         !backupStorageCompress,
 		CompressionEngine:    CompressionEngineName,
 		ExternalDecompressor: ManifestExternalDecompressorCmd,
+		Encryption:           encryption,
 	}
 	data, err := json.MarshalIndent(bm, "", "  ")
 	if err != nil {
@@ -764,8 +775,9 @@ func (bp *backupPipe) ReportProgress(period time.Duration, logger logutil.Logger
 	}
 }
 
-// backupFile backs up an individual file.
-func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fe *FileEntry, name string) (finalErr error) {
+// backupFile backs up an individual file. If dataKey is set, the file is
+// encrypted with it.
+func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fe *FileEntry, name string, dataKey []byte) (finalErr error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	// Open the source file for reading.
@@ -818,12 +830,29 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 	bw := newBackupWriter(fe.Name, builtinBackupStorageWriteBufferSize, fi.Size(), timedDest)
 
 	// We create the following inner function because:
-	// - we must `defer` the compressor's Close() function
+	// - we must `defer` the compressor's and encryptor's Close() functions
 	// - but it must take place before we close the pipe reader&writer
 	createAndCopy := func() (createAndCopyErr error) {
 		var reader io.Reader = br
 		var writer io.Writer = bw
 
+		// Create the encryption pipe, if necessary. It is closed after
+		// the compressor, which flushes into it.
+		if dataKey != nil {
+			encryptor, err := newEncryptingWriter(writer, dataKey)
+			if err != nil {
+				return vterrors.Wrap(err, "can't create encryptor")
+			}
+			writer = encryptor
+			defer func() {
+				if cerr := encryptor.Close(); cerr != nil {
+					cerr = vterrors.Wrapf(cerr, "failed to close encryptor %v", name)
+					params.Logger.Error(cerr)
+					createAndCopyErr = errors.Join(createAndCopyErr, cerr)
+				}
+			}()
+		}
+
 		// Create the gzip compression pipe, if necessary.
 		if backupStorageCompress {
 			var compressor io.WriteCloser
@@ -986,6 +1015,13 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 			return "", err
 		}
 	}
+	var dataKey []byte
+	if bm.Encryption != nil {
+		if dataKey, err = bm.Encryption.DataKey(ctx); err != nil {
+			return createdDir, err
+		}
+	}
+
 	fes := bm.FileEntries
 	sema := semaphore.NewWeighted(int64(params.Concurrency))
 	rec := concurrency.AllErrorRecorder{}
@@ -1024,7 +1060,7 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 			// And restore the file.
 			name := fmt.Sprintf("%v", i)
 			params.Logger.Infof("Copying file %v: %v", name, fe.Name)
-			err := be.restoreFile(ctx, params, bh, fe, bm, name)
+			err := be.restoreFile(ctx, params, bh, fe, bm, name, dataKey)
 			if err != nil {
 				rec.RecordError(vterrors.Wrapf(err, "can't restore file %v to %v", name, fe.Name))
 			}
@@ -1034,8 +1070,9 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 	return createdDir, rec.Error()
 }
 
-// restoreFile restores an individual file.
-func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, fe *FileEntry, bm builtinBackupManifest, name string) (finalErr error) {
+// restoreFile restores an individual file. If dataKey is set, the file is
+// decrypted with it.
+func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, fe *FileEntry, bm builtinBackupManifest, name string, dataKey []byte) (finalErr error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	// Open the source file for reading.
@@ -1080,6 +1117,16 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 
 	bufferedDest := bufio.NewWriterSize(timedDest, int(builtinBackupFileWriteBufferSize))
 
+	// Create the decrypter if needed. The file was encrypted after
+	// compression, so it is decrypted before decompression.
+	if dataKey != nil {
+		decryptor, err := newDecryptingReader(reader, dataKey)
+		if err != nil {
+			return vterrors.Wrap(err, "can't create decryptor")
+		}
+		reader = decryptor
+	}
+
 	// Create the uncompresser if needed.
 	if !bm.SkipCompress {
 		var decompressor io.ReadCloser
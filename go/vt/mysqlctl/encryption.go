@@ -0,0 +1,429 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// This file implements the envelope encryption of builtin backups. Every
+// backup gets a random data key, which encrypts all its files with
+// AES-256-GCM. The data key itself is encrypted by a key provider, and
+// stored in the MANIFEST, so that a restore only needs access to the
+// key provider.
+//
+// Files are encrypted after compression, in chunks, so that they can be
+// streamed. An encrypted file is laid out as:
+//
+//	1 byte:  format version
+//	8 bytes: random nonce prefix
+//	chunks:  AES-GCM sealed chunks of encryptionChunkSize plaintext bytes,
+//	         the last one being shorter (possibly empty)
+//
+// The nonce of each chunk is the nonce prefix followed by the chunk index,
+// and the additional data marks the last chunk, so chunks can be neither
+// reordered nor dropped.
+
+const (
+	// BackupEncryptionAlgorithmAES256GCM is the only supported cipher.
+	BackupEncryptionAlgorithmAES256GCM = "aes-256-gcm"
+
+	// FileKeyProvider is the name of the key provider reading the key
+	// encryption key from --backup-encryption-keyfile.
+	FileKeyProvider = "file"
+
+	encryptionFormatVersion  = 1
+	encryptionNoncePrefixLen = 8
+	encryptionChunkSize      = 64 * 1024
+	encryptionDataKeySize    = 32
+)
+
+var (
+	// backupEncryptionKeyProvider is the key provider used to encrypt new
+	// backups. Backups are not encrypted if empty.
+	backupEncryptionKeyProvider string
+
+	// backupEncryptionKeyFile is the key file used by FileKeyProvider.
+	backupEncryptionKeyFile string
+
+	// BackupEncryptionKeyProviderMap contains the registered key providers.
+	BackupEncryptionKeyProviderMap = make(map[string]BackupEncryptionKeyProvider)
+
+	errTruncatedEncryptedFile = errors.New("encrypted backup file is truncated")
+)
+
+func init() {
+	for _, cmd := range []string{"vtbackup", "vtcombo", "vttablet", "vttestserver"} {
+		servenv.OnParseFor(cmd, registerBackupEncryptionFlags)
+	}
+	BackupEncryptionKeyProviderMap[FileKeyProvider] = fileKeyProvider{}
+}
+
+func registerBackupEncryptionFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&backupEncryptionKeyProvider, "backup-encryption-key-provider", backupEncryptionKeyProvider, "key provider used to encrypt the files of new builtin backups. Backups are not encrypted if empty. Supported values: 'file'.")
+	fs.StringVar(&backupEncryptionKeyFile, "backup-encryption-keyfile", backupEncryptionKeyFile, "path to the file holding the AES-256 key (32 raw or 64 hex encoded bytes) used by the 'file' backup encryption key provider.")
+}
+
+// BackupEncryptionKeyProvider encrypts and decrypts the per-backup data
+// keys with a key encryption key it manages, like a local key file or a
+// key management service.
+type BackupEncryptionKeyProvider interface {
+	// WrapKey encrypts a data key. It returns the id of the key encryption
+	// key that was used, which is stored in the MANIFEST along with the
+	// encrypted data key.
+	WrapKey(ctx context.Context, dataKey []byte) (keyID string, wrapped []byte, err error)
+
+	// UnwrapKey decrypts a data key that was encrypted by WrapKey.
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// BackupEncryption is stored in the MANIFEST of encrypted backups.
+type BackupEncryption struct {
+	// Algorithm is the cipher used to encrypt the files.
+	Algorithm string
+
+	// KeyProvider is the name of the key provider that encrypted the
+	// data key.
+	KeyProvider string
+
+	// KeyID identifies the key encryption key for the key provider.
+	KeyID string `json:",omitempty"`
+
+	// EncryptedDataKey is the data key of the backup, encrypted by the
+	// key provider.
+	EncryptedDataKey []byte
+}
+
+// newBackupEncryption generates the data key for a new backup. It returns
+// nil values if backup encryption is disabled.
+func newBackupEncryption(ctx context.Context) (*BackupEncryption, []byte, error) {
+	if backupEncryptionKeyProvider == "" {
+		return nil, nil, nil
+	}
+	provider, ok := BackupEncryptionKeyProviderMap[backupEncryptionKeyProvider]
+	if !ok {
+		return nil, nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "unknown backup encryption key provider %q", backupEncryptionKeyProvider)
+	}
+
+	dataKey := make([]byte, encryptionDataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, vterrors.Wrap(err, "cannot generate backup data key")
+	}
+	keyID, wrapped, err := provider.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, nil, vterrors.Wrapf(err, "cannot encrypt backup data key with key provider %q", backupEncryptionKeyProvider)
+	}
+	return &BackupEncryption{
+		Algorithm:        BackupEncryptionAlgorithmAES256GCM,
+		KeyProvider:      backupEncryptionKeyProvider,
+		KeyID:            keyID,
+		EncryptedDataKey: wrapped,
+	}, dataKey, nil
+}
+
+// DataKey decrypts the data key of the backup with its key provider.
+func (enc *BackupEncryption) DataKey(ctx context.Context) ([]byte, error) {
+	if enc.Algorithm != BackupEncryptionAlgorithmAES256GCM {
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "unsupported backup encryption algorithm %q", enc.Algorithm)
+	}
+	provider, ok := BackupEncryptionKeyProviderMap[enc.KeyProvider]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "unknown backup encryption key provider %q", enc.KeyProvider)
+	}
+	dataKey, err := provider.UnwrapKey(ctx, enc.KeyID, enc.EncryptedDataKey)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot decrypt backup data key with key provider %q", enc.KeyProvider)
+	}
+	return dataKey, nil
+}
+
+// fileKeyProvider uses the key in --backup-encryption-keyfile as the key
+// encryption key. The key id is a fingerprint of the key, so restoring
+// with the wrong key file fails with an explicit error.
+type fileKeyProvider struct{}
+
+func (fileKeyProvider) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	key, keyID, err := readBackupEncryptionKeyFile(backupEncryptionKeyFile)
+	if err != nil {
+		return "", nil, err
+	}
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return "", nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+	return keyID, aead.Seal(nonce, nonce, dataKey, nil), nil
+}
+
+func (fileKeyProvider) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	key, fileKeyID, err := readBackupEncryptionKeyFile(backupEncryptionKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	if keyID != fileKeyID {
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "backup was encrypted with key %v, but %v holds key %v", keyID, backupEncryptionKeyFile, fileKeyID)
+	}
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "encrypted data key is too short")
+	}
+	return aead.Open(nil, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], nil)
+}
+
+// readBackupEncryptionKeyFile reads an AES-256 key, stored either raw or
+// hex encoded, and returns it with its fingerprint.
+func readBackupEncryptionKeyFile(path string) ([]byte, string, error) {
+	if path == "" {
+		return nil, "", vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "--backup-encryption-keyfile is required by the %q backup encryption key provider", FileKeyProvider)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", vterrors.Wrapf(err, "cannot read backup encryption key file")
+	}
+	key := data
+	if decoded, err := hex.DecodeString(string(bytes.TrimSpace(data))); err == nil && len(decoded) == encryptionDataKeySize {
+		key = decoded
+	}
+	if len(key) != encryptionDataKeySize {
+		return nil, "", vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "backup encryption key file %v must hold a %d bytes key, raw or hex encoded", path, encryptionDataKeySize)
+	}
+	fingerprint := sha256.Sum256(key)
+	return key, hex.EncodeToString(fingerprint[:8]), nil
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "cannot create AES cipher")
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce returns the nonce of the given chunk.
+func chunkNonce(nonce []byte, prefix []byte, index uint32) []byte {
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[encryptionNoncePrefixLen:], index)
+	return nonce
+}
+
+// chunkAdditionalData authenticates whether a chunk is the last one.
+func chunkAdditionalData(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// encryptingWriter encrypts everything written to it. Close must be called
+// to write the last chunk. It does not close the underlying writer.
+type encryptingWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	prefix []byte
+	index  uint32
+
+	// plaintext holds the data of the current chunk. A full chunk is only
+	// sealed once more data comes in, since we don't know yet if it is the
+	// last one.
+	plaintext []byte
+	sealed    []byte
+	nonce     []byte
+	closed    bool
+}
+
+func newEncryptingWriter(w io.Writer, dataKey []byte) (*encryptingWriter, error) {
+	aead, err := newAESGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, 1+encryptionNoncePrefixLen)
+	header[0] = encryptionFormatVersion
+	if _, err := rand.Read(header[1:]); err != nil {
+		return nil, vterrors.Wrap(err, "cannot generate nonce")
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &encryptingWriter{
+		w:         w,
+		aead:      aead,
+		prefix:    header[1:],
+		plaintext: make([]byte, 0, encryptionChunkSize),
+		nonce:     make([]byte, aead.NonceSize()),
+	}, nil
+}
+
+// Write implements io.Writer.
+func (ew *encryptingWriter) Write(p []byte) (int, error) {
+	if ew.closed {
+		return 0, vterrors.Errorf(vtrpc.Code_INTERNAL, "write to closed encrypting writer")
+	}
+	written := 0
+	for len(p) > 0 {
+		if len(ew.plaintext) == encryptionChunkSize {
+			if err := ew.seal(false); err != nil {
+				return written, err
+			}
+		}
+		n := copy(ew.plaintext[len(ew.plaintext):encryptionChunkSize], p)
+		ew.plaintext = ew.plaintext[:len(ew.plaintext)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+// Close writes the last chunk.
+func (ew *encryptingWriter) Close() error {
+	if ew.closed {
+		return nil
+	}
+	ew.closed = true
+	return ew.seal(true)
+}
+
+func (ew *encryptingWriter) seal(last bool) error {
+	if ew.index == ^uint32(0) {
+		return vterrors.Errorf(vtrpc.Code_OUT_OF_RANGE, "backup file is too large to be encrypted")
+	}
+	ew.sealed = ew.aead.Seal(ew.sealed[:0], chunkNonce(ew.nonce, ew.prefix, ew.index), ew.plaintext, chunkAdditionalData(last))
+	if _, err := ew.w.Write(ew.sealed); err != nil {
+		return err
+	}
+	ew.index++
+	ew.plaintext = ew.plaintext[:0]
+	return nil
+}
+
+// decryptingReader decrypts a file written by encryptingWriter. Read
+// returns an error if the file was tampered with or truncated.
+type decryptingReader struct {
+	r      *bufio.Reader
+	aead   cipher.AEAD
+	prefix []byte
+	index  uint32
+
+	sealed  []byte
+	opened  []byte
+	pending []byte
+	nonce   []byte
+	last    bool
+}
+
+func newDecryptingReader(r io.Reader, dataKey []byte) (*decryptingReader, error) {
+	aead, err := newAESGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptingReader{
+		r:     bufio.NewReader(r),
+		aead:  aead,
+		nonce: make([]byte, aead.NonceSize()),
+	}, nil
+}
+
+// Read implements io.Reader.
+func (dr *decryptingReader) Read(p []byte) (int, error) {
+	for len(dr.pending) == 0 {
+		if dr.last {
+			return 0, io.EOF
+		}
+		if err := dr.openChunk(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, dr.pending)
+	dr.pending = dr.pending[n:]
+	return n, nil
+}
+
+func (dr *decryptingReader) readHeader() error {
+	header := make([]byte, 1+encryptionNoncePrefixLen)
+	if _, err := io.ReadFull(dr.r, header); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return errTruncatedEncryptedFile
+		}
+		return err
+	}
+	if header[0] != encryptionFormatVersion {
+		return vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "unsupported encrypted backup file version %d", header[0])
+	}
+	dr.prefix = header[1:]
+	dr.sealed = make([]byte, encryptionChunkSize+dr.aead.Overhead())
+	return nil
+}
+
+func (dr *decryptingReader) openChunk() error {
+	if dr.prefix == nil {
+		if err := dr.readHeader(); err != nil {
+			return err
+		}
+	}
+
+	n, err := io.ReadFull(dr.r, dr.sealed)
+	switch err {
+	case nil:
+		// A full chunk is the last one if nothing follows it.
+		if _, err := dr.r.Peek(1); err == io.EOF {
+			dr.last = true
+		} else if err != nil {
+			return err
+		}
+	case io.ErrUnexpectedEOF:
+		dr.last = true
+	case io.EOF:
+		return errTruncatedEncryptedFile
+	default:
+		return err
+	}
+
+	dr.opened, err = dr.aead.Open(dr.opened[:0], chunkNonce(dr.nonce, dr.prefix, dr.index), dr.sealed[:n], chunkAdditionalData(dr.last))
+	if err != nil {
+		if dr.last {
+			// The last chunk we got may be a truncated one.
+			return vterrors.Wrapf(err, "cannot decrypt chunk %d, the file is corrupted or truncated", dr.index)
+		}
+		return vterrors.Wrapf(err, "cannot decrypt chunk %d, the file is corrupted", dr.index)
+	}
+	dr.index++
+	dr.pending = dr.opened
+	return nil
+}
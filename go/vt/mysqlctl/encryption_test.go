@@ -0,0 +1,222 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+func newTestDataKey(t *testing.T) []byte {
+	key := make([]byte, encryptionDataKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+func encryptForTest(t *testing.T, dataKey, data []byte) []byte {
+	var buf bytes.Buffer
+	ew, err := newEncryptingWriter(&buf, dataKey)
+	require.NoError(t, err)
+	// Write in odd sizes to exercise the chunking.
+	for len(data) > 0 {
+		n := min(len(data), 1000)
+		_, err := ew.Write(data[:n])
+		require.NoError(t, err)
+		data = data[n:]
+	}
+	require.NoError(t, ew.Close())
+	return buf.Bytes()
+}
+
+func decryptForTest(dataKey, encrypted []byte) ([]byte, error) {
+	dr, err := newDecryptingReader(bytes.NewReader(encrypted), dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(dr)
+}
+
+func TestEncryptionRoundTrip(t *testing.T) {
+	dataKey := newTestDataKey(t)
+	for _, size := range []int{0, 1, encryptionChunkSize - 1, encryptionChunkSize, encryptionChunkSize + 1, 3*encryptionChunkSize + 17} {
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+
+		encrypted := encryptForTest(t, dataKey, data)
+		assert.False(t, size > 16 && bytes.Contains(encrypted, data[:16]), "size %d: plaintext found in encrypted data", size)
+
+		decrypted, err := decryptForTest(dataKey, encrypted)
+		require.NoError(t, err, "size %d", size)
+		assert.True(t, bytes.Equal(data, decrypted), "size %d", size)
+	}
+}
+
+func TestEncryptionDetectsCorruption(t *testing.T) {
+	dataKey := newTestDataKey(t)
+	data := bytes.Repeat([]byte("some backup data "), encryptionChunkSize/4)
+	encrypted := encryptForTest(t, dataKey, data)
+	chunk := encryptionChunkSize + 16
+	header := 1 + encryptionNoncePrefixLen
+
+	_, err := decryptForTest(newTestDataKey(t), encrypted)
+	assert.ErrorContains(t, err, "cannot decrypt chunk 0")
+
+	tampered := bytes.Clone(encrypted)
+	tampered[header+10] ^= 1
+	_, err = decryptForTest(dataKey, tampered)
+	assert.ErrorContains(t, err, "cannot decrypt chunk 0, the file is corrupted")
+
+	// Dropping the last chunk, or cutting one, is detected.
+	_, err = decryptForTest(dataKey, encrypted[:header+chunk])
+	assert.ErrorContains(t, err, "cannot decrypt chunk 0, the file is corrupted or truncated")
+	_, err = decryptForTest(dataKey, encrypted[:len(encrypted)-1])
+	assert.ErrorContains(t, err, "the file is corrupted or truncated")
+	_, err = decryptForTest(dataKey, encrypted[:header])
+	assert.ErrorIs(t, err, errTruncatedEncryptedFile)
+
+	// Swapping chunks is detected.
+	swapped := append(bytes.Clone(encrypted[:header]), encrypted[header+chunk:header+2*chunk]...)
+	swapped = append(swapped, encrypted[header:header+chunk]...)
+	swapped = append(swapped, encrypted[header+2*chunk:]...)
+	_, err = decryptForTest(dataKey, swapped)
+	assert.ErrorContains(t, err, "cannot decrypt chunk 0")
+}
+
+func TestFileKeyProvider(t *testing.T) {
+	defer func(keyFile string) { backupEncryptionKeyFile = keyFile }(backupEncryptionKeyFile)
+	defer func(provider string) { backupEncryptionKeyProvider = provider }(backupEncryptionKeyProvider)
+
+	dir := t.TempDir()
+	rawKeyFile := path.Join(dir, "raw.key")
+	require.NoError(t, os.WriteFile(rawKeyFile, newTestDataKey(t), 0600))
+	hexKeyFile := path.Join(dir, "hex.key")
+	require.NoError(t, os.WriteFile(hexKeyFile, []byte(hex.EncodeToString(newTestDataKey(t))+"\n"), 0600))
+	badKeyFile := path.Join(dir, "bad.key")
+	require.NoError(t, os.WriteFile(badKeyFile, []byte("too short"), 0600))
+
+	ctx := context.Background()
+
+	backupEncryptionKeyProvider = ""
+	encryption, dataKey, err := newBackupEncryption(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, encryption)
+	assert.Nil(t, dataKey)
+
+	backupEncryptionKeyProvider = FileKeyProvider
+	for _, keyFile := range []string{rawKeyFile, hexKeyFile} {
+		backupEncryptionKeyFile = keyFile
+		encryption, dataKey, err := newBackupEncryption(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, BackupEncryptionAlgorithmAES256GCM, encryption.Algorithm)
+		assert.Equal(t, FileKeyProvider, encryption.KeyProvider)
+		assert.NotContains(t, string(encryption.EncryptedDataKey), string(dataKey))
+
+		// The encryption settings survive the MANIFEST round trip.
+		data, err := json.Marshal(builtinBackupManifest{Encryption: encryption})
+		require.NoError(t, err)
+		var bm builtinBackupManifest
+		require.NoError(t, json.Unmarshal(data, &bm))
+
+		got, err := bm.Encryption.DataKey(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, dataKey, got)
+	}
+
+	// Restoring with another key fails explicitly.
+	encryption, _, err = newBackupEncryption(ctx)
+	require.NoError(t, err)
+	backupEncryptionKeyFile = rawKeyFile
+	_, err = encryption.DataKey(ctx)
+	assert.ErrorContains(t, err, "backup was encrypted with key "+encryption.KeyID)
+
+	backupEncryptionKeyFile = badKeyFile
+	_, _, err = newBackupEncryption(ctx)
+	assert.ErrorContains(t, err, "must hold a 32 bytes key")
+
+	backupEncryptionKeyProvider = "kms"
+	_, _, err = newBackupEncryption(ctx)
+	assert.ErrorContains(t, err, `unknown backup encryption key provider "kms"`)
+}
+
+func TestBuiltinBackupFileEncryption(t *testing.T) {
+	ctx := context.Background()
+	defer func(root string) { filebackupstorage.FileBackupStorageRoot = root }(filebackupstorage.FileBackupStorageRoot)
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+
+	sourceDir := t.TempDir()
+	data := bytes.Repeat([]byte("innodb page "), 100000)
+	require.NoError(t, os.WriteFile(path.Join(sourceDir, "t1.ibd"), data, 0600))
+
+	be := &BuiltinBackupEngine{}
+	dataKey := newTestDataKey(t)
+	fe := &FileEntry{Base: backupData, Name: "t1.ibd"}
+	bh := filebackupstorage.NewBackupHandle(nil, "", "", false)
+	err := be.backupFile(ctx, BackupParams{
+		Cnf:    &Mycnf{DataDir: sourceDir},
+		Logger: logutil.NewMemoryLogger(),
+		Stats:  backupstats.NewFakeStats(),
+	}, bh, fe, "0", dataKey)
+	require.NoError(t, err)
+
+	stored, err := os.ReadFile(path.Join(filebackupstorage.FileBackupStorageRoot, "0"))
+	require.NoError(t, err)
+	assert.Equal(t, byte(encryptionFormatVersion), stored[0])
+
+	restoreDir := t.TempDir()
+	bm := builtinBackupManifest{
+		CompressionEngine: CompressionEngineName,
+		SkipCompress:      !backupStorageCompress,
+	}
+	if bm.CompressionEngine == PargzipCompressor {
+		bm.CompressionEngine = PgzipCompressor
+	}
+	bh = filebackupstorage.NewBackupHandle(nil, "", "", true)
+	err = be.restoreFile(ctx, RestoreParams{
+		Cnf:    &Mycnf{DataDir: restoreDir},
+		Logger: logutil.NewMemoryLogger(),
+		Stats:  backupstats.NewFakeStats(),
+	}, bh, fe, bm, "0", dataKey)
+	require.NoError(t, err)
+
+	restored, err := os.ReadFile(path.Join(restoreDir, "t1.ibd"))
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(data, restored))
+
+	// The wrong key can't restore the file.
+	err = be.restoreFile(ctx, RestoreParams{
+		Cnf:    &Mycnf{DataDir: t.TempDir()},
+		Logger: logutil.NewMemoryLogger(),
+		Stats:  backupstats.NewFakeStats(),
+	}, bh, fe, bm, "0", newTestDataKey(t))
+	assert.ErrorContains(t, err, "cannot decrypt chunk 0")
+}
@@ -74,9 +74,14 @@ func registerMyCnfFlags(fs *pflag.FlagSet) {
 // tabletservers deployed within a keyspace, lest there be collisions on disk.
 // mysqldPort needs to be unique per instance per machine.
 func NewMycnf(tabletUID uint32, mysqlPort int) *Mycnf {
+	return newMycnfAtDir(TabletDir(tabletUID), tabletUID, mysqlPort)
+}
+
+// newMycnfAtDir is like NewMycnf, with all the files of the mysql instance in
+// the given tablet directory.
+func newMycnfAtDir(tabletDir string, tabletUID uint32, mysqlPort int) *Mycnf {
 	cnf := new(Mycnf)
-	cnf.Path = MycnfFile(tabletUID)
-	tabletDir := TabletDir(tabletUID)
+	cnf.Path = path.Join(tabletDir, "my.cnf")
 	cnf.ServerID = tabletUID
 	cnf.MysqlPort = mysqlPort
 	cnf.DataDir = path.Join(tabletDir, dataDir)
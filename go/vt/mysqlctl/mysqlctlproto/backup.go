@@ -17,6 +17,7 @@ limitations under the License.
 package mysqlctlproto
 
 import (
+	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
@@ -44,3 +45,67 @@ func BackupHandleToProto(bh backupstorage.BackupHandle) *mysqlctlpb.BackupInfo {
 
 	return bi
 }
+
+// BackupRetentionPolicyFromProto returns a mysqlctl.BackupRetentionPolicy from
+// its proto representation.
+func BackupRetentionPolicyFromProto(p *mysqlctlpb.BackupRetentionPolicy) (*mysqlctl.BackupRetentionPolicy, error) {
+	policy := &mysqlctl.BackupRetentionPolicy{
+		KeepFullBackups: int(p.GetKeepFullBackups()),
+	}
+	var err error
+	if policy.PointInTimeWindow, _, err = protoutil.DurationFromProto(p.GetPointInTimeWindow()); err != nil {
+		return nil, err
+	}
+	if policy.MinAge, _, err = protoutil.DurationFromProto(p.GetMinAge()); err != nil {
+		return nil, err
+	}
+	if policy.MaxAge, _, err = protoutil.DurationFromProto(p.GetMaxAge()); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// BackupRetentionPolicyToProto returns the proto representation of a
+// mysqlctl.BackupRetentionPolicy.
+func BackupRetentionPolicyToProto(policy *mysqlctl.BackupRetentionPolicy) *mysqlctlpb.BackupRetentionPolicy {
+	p := &mysqlctlpb.BackupRetentionPolicy{
+		KeepFullBackups: uint32(policy.KeepFullBackups),
+	}
+	if policy.PointInTimeWindow > 0 {
+		p.PointInTimeWindow = protoutil.DurationToProto(policy.PointInTimeWindow)
+	}
+	if policy.MinAge > 0 {
+		p.MinAge = protoutil.DurationToProto(policy.MinAge)
+	}
+	if policy.MaxAge > 0 {
+		p.MaxAge = protoutil.DurationToProto(policy.MaxAge)
+	}
+	return p
+}
+
+// BackupVerificationToProto returns a BackupVerification proto from the
+// outcome of mysqlctl.VerifyBackup.
+func BackupVerificationToProto(v *mysqlctl.BackupVerification) *mysqlctlpb.BackupVerification {
+	p := &mysqlctlpb.BackupVerification{
+		BackupName: v.BackupName,
+		Directory:  v.Directory,
+		Errors:     v.Errors,
+		Ok:         v.OK(),
+	}
+	if !v.Position.IsZero() {
+		p.Position = replication.EncodePosition(v.Position)
+	}
+	if !v.RestoredPosition.IsZero() {
+		p.RestoredPosition = replication.EncodePosition(v.RestoredPosition)
+	}
+	for _, check := range v.Tables {
+		p.Tables = append(p.Tables, &mysqlctlpb.BackupVerification_TableCheck{
+			Database: check.Database,
+			Table:    check.Table,
+			RowCount: check.RowCount,
+			Status:   check.Status,
+			Ok:       check.OK,
+		})
+	}
+	return p
+}
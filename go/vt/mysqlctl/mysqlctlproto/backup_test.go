@@ -21,8 +21,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
 
 	mysqlctlpb "vitess.io/vitess/go/vt/proto/mysqlctl"
@@ -112,3 +116,54 @@ func TestBackupHandleToProto(t *testing.T) {
 		})
 	}
 }
+
+func TestBackupRetentionPolicyProto(t *testing.T) {
+	t.Parallel()
+
+	policy := &mysqlctl.BackupRetentionPolicy{
+		KeepFullBackups:   3,
+		PointInTimeWindow: 7 * 24 * time.Hour,
+		MaxAge:            30 * 24 * time.Hour,
+	}
+	p := BackupRetentionPolicyToProto(policy)
+	utils.MustMatch(t, &mysqlctlpb.BackupRetentionPolicy{
+		KeepFullBackups:   3,
+		PointInTimeWindow: protoutil.DurationToProto(7 * 24 * time.Hour),
+		MaxAge:            protoutil.DurationToProto(30 * 24 * time.Hour),
+	}, p)
+
+	got, err := BackupRetentionPolicyFromProto(p)
+	require.NoError(t, err)
+	utils.MustMatch(t, policy, got)
+
+	got, err = BackupRetentionPolicyFromProto(nil)
+	require.NoError(t, err)
+	utils.MustMatch(t, &mysqlctl.BackupRetentionPolicy{}, got)
+}
+
+func TestBackupVerificationToProto(t *testing.T) {
+	t.Parallel()
+
+	pos, err := replication.DecodePosition("MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-100")
+	require.NoError(t, err)
+	v := &mysqlctl.BackupVerification{
+		BackupName: "2023-06-01.000000.zone1-100",
+		Directory:  "ks/0",
+		Position:   pos,
+		Tables: []mysqlctl.TableCheck{
+			{Database: "vt_ks", Table: "t1", RowCount: 10, Status: "OK", OK: true},
+			{Database: "vt_ks", Table: "t2", Status: "Corrupt"},
+		},
+		Errors: []string{"table vt_ks.t2 failed CHECK TABLE: Corrupt"},
+	}
+	utils.MustMatch(t, &mysqlctlpb.BackupVerification{
+		BackupName: "2023-06-01.000000.zone1-100",
+		Directory:  "ks/0",
+		Position:   "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-100",
+		Tables: []*mysqlctlpb.BackupVerification_TableCheck{
+			{Database: "vt_ks", Table: "t1", RowCount: 10, Status: "OK", Ok: true},
+			{Database: "vt_ks", Table: "t2", Status: "Corrupt"},
+		},
+		Errors: []string{"table vt_ks.t2 failed CHECK TABLE: Corrupt"},
+	}, BackupVerificationToProto(v))
+}
@@ -115,6 +115,31 @@ func (e *ApplyViewNotFoundError) Error() string {
 	return fmt.Sprintf("view %s not found", sqlescape.EscapeID(e.View))
 }
 
+type ApplyTriggerNotFoundError struct {
+	Trigger string
+}
+
+func (e *ApplyTriggerNotFoundError) Error() string {
+	return fmt.Sprintf("trigger %s not found", sqlescape.EscapeID(e.Trigger))
+}
+
+type ApplyRoutineNotFoundError struct {
+	Type    string
+	Routine string
+}
+
+func (e *ApplyRoutineNotFoundError) Error() string {
+	return fmt.Sprintf("%s %s not found", e.Type, sqlescape.EscapeID(e.Routine))
+}
+
+type ApplyEventNotFoundError struct {
+	Event string
+}
+
+func (e *ApplyEventNotFoundError) Error() string {
+	return fmt.Sprintf("event %s not found", sqlescape.EscapeID(e.Event))
+}
+
 type ApplyKeyNotFoundError struct {
 	Table string
 	Key   string
@@ -375,6 +400,25 @@ func (e *ViewDependencyUnresolvedError) Error() string {
 	return fmt.Sprintf("view %s has unresolved/loop dependencies", sqlescape.EscapeID(e.View))
 }
 
+type TriggerTableNotFoundError struct {
+	Trigger string
+	Table   string
+}
+
+func (e *TriggerTableNotFoundError) Error() string {
+	return fmt.Sprintf("trigger %s references non-existent table %s", sqlescape.EscapeID(e.Trigger), sqlescape.EscapeID(e.Table))
+}
+
+type TriggerOrderDependencyUnresolvedError struct {
+	Trigger           string
+	ReferencedTrigger string
+}
+
+func (e *TriggerOrderDependencyUnresolvedError) Error() string {
+	return fmt.Sprintf("trigger %s is ordered relative to trigger %s, which is not a trigger on the same table with the same timing and event, or which has a loop dependency",
+		sqlescape.EscapeID(e.Trigger), sqlescape.EscapeID(e.ReferencedTrigger))
+}
+
 type InvalidColumnReferencedInViewError struct {
 	View      string
 	Column    string
@@ -0,0 +1,242 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemadiff
+
+import (
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+type CreateEventEntityDiff struct {
+	createEvent *sqlparser.CreateEvent
+
+	canonicalStatementString string
+}
+
+// IsEmpty implements EntityDiff
+func (d *CreateEventEntityDiff) IsEmpty() bool {
+	return d.Statement() == nil
+}
+
+// EntityName implements EntityDiff
+func (d *CreateEventEntityDiff) EntityName() string {
+	_, to := d.Entities()
+	return to.Name()
+}
+
+// Entities implements EntityDiff
+func (d *CreateEventEntityDiff) Entities() (from Entity, to Entity) {
+	return nil, &CreateEventEntity{CreateEvent: d.createEvent}
+}
+
+// Statement implements EntityDiff
+func (d *CreateEventEntityDiff) Statement() sqlparser.Statement {
+	if d == nil {
+		return nil
+	}
+	return d.createEvent
+}
+
+// CreateEvent returns the underlying sqlparser.CreateEvent that was generated for the diff.
+func (d *CreateEventEntityDiff) CreateEvent() *sqlparser.CreateEvent {
+	if d == nil {
+		return nil
+	}
+	return d.createEvent
+}
+
+// StatementString implements EntityDiff
+func (d *CreateEventEntityDiff) StatementString() (s string) {
+	if stmt := d.Statement(); stmt != nil {
+		s = sqlparser.String(stmt)
+	}
+	return s
+}
+
+// CanonicalStatementString implements EntityDiff
+func (d *CreateEventEntityDiff) CanonicalStatementString() string {
+	if d == nil {
+		return ""
+	}
+	if d.canonicalStatementString == "" {
+		if stmt := d.Statement(); stmt != nil {
+			d.canonicalStatementString = sqlparser.CanonicalString(stmt)
+		}
+	}
+	return d.canonicalStatementString
+}
+
+// SubsequentDiff implements EntityDiff
+func (d *CreateEventEntityDiff) SubsequentDiff() EntityDiff {
+	return nil
+}
+
+// SetSubsequentDiff implements EntityDiff
+func (d *CreateEventEntityDiff) SetSubsequentDiff(EntityDiff) {
+}
+
+// DropEventEntityDiff drops an event. A changed event is expressed as a DropEventEntityDiff followed by a
+// subsequent CreateEventEntityDiff.
+type DropEventEntityDiff struct {
+	from      *CreateEventEntity
+	dropEvent *sqlparser.DropEvent
+
+	subsequentDiff *CreateEventEntityDiff
+
+	canonicalStatementString string
+}
+
+// IsEmpty implements EntityDiff
+func (d *DropEventEntityDiff) IsEmpty() bool {
+	return d.Statement() == nil
+}
+
+// EntityName implements EntityDiff
+func (d *DropEventEntityDiff) EntityName() string {
+	return d.from.Name()
+}
+
+// Entities implements EntityDiff
+func (d *DropEventEntityDiff) Entities() (from Entity, to Entity) {
+	return d.from, nil
+}
+
+// Statement implements EntityDiff
+func (d *DropEventEntityDiff) Statement() sqlparser.Statement {
+	if d == nil {
+		return nil
+	}
+	return d.dropEvent
+}
+
+// DropEvent returns the underlying sqlparser.DropEvent that was generated for the diff.
+func (d *DropEventEntityDiff) DropEvent() *sqlparser.DropEvent {
+	if d == nil {
+		return nil
+	}
+	return d.dropEvent
+}
+
+// StatementString implements EntityDiff
+func (d *DropEventEntityDiff) StatementString() (s string) {
+	if stmt := d.Statement(); stmt != nil {
+		s = sqlparser.String(stmt)
+	}
+	return s
+}
+
+// CanonicalStatementString implements EntityDiff
+func (d *DropEventEntityDiff) CanonicalStatementString() string {
+	if d == nil {
+		return ""
+	}
+	if d.canonicalStatementString == "" {
+		if stmt := d.Statement(); stmt != nil {
+			d.canonicalStatementString = sqlparser.CanonicalString(stmt)
+		}
+	}
+	return d.canonicalStatementString
+}
+
+// SubsequentDiff implements EntityDiff
+func (d *DropEventEntityDiff) SubsequentDiff() EntityDiff {
+	if d == nil || d.subsequentDiff == nil {
+		return nil
+	}
+	return d.subsequentDiff
+}
+
+// SetSubsequentDiff implements EntityDiff
+func (d *DropEventEntityDiff) SetSubsequentDiff(subDiff EntityDiff) {
+	if d == nil {
+		return
+	}
+	if createDiff, ok := subDiff.(*CreateEventEntityDiff); ok {
+		d.subsequentDiff = createDiff
+	} else {
+		d.subsequentDiff = nil
+	}
+}
+
+// CreateEventEntity stands for an EVENT construct. It contains the event's CREATE statement.
+type CreateEventEntity struct {
+	*sqlparser.CreateEvent
+}
+
+func NewCreateEventEntity(c *sqlparser.CreateEvent) (*CreateEventEntity, error) {
+	entity := &CreateEventEntity{CreateEvent: c}
+	entity.normalize()
+	return entity, nil
+}
+
+func (c *CreateEventEntity) normalize() {
+	// IF NOT EXISTS does not affect the resulting event
+	c.CreateEvent.IfNotExists = false
+}
+
+// Name implements Entity interface
+func (c *CreateEventEntity) Name() string {
+	return c.CreateEvent.Name.Name.String()
+}
+
+// Diff implements Entity interface function
+func (c *CreateEventEntity) Diff(other Entity, hints *DiffHints) (EntityDiff, error) {
+	otherCreateEvent, ok := other.(*CreateEventEntity)
+	if !ok {
+		return nil, ErrEntityTypeMismatch
+	}
+	return c.EventDiff(otherCreateEvent, hints)
+}
+
+// EventDiff compares this event statement with another event statement, and sees what it takes to
+// change this event to look like the other event.
+// If changes are found, the function returns a DROP diff, followed by a subsequent CREATE diff.
+// It returns nil if no changes are found.
+// the other event may be of different name; its name is ignored.
+func (c *CreateEventEntity) EventDiff(other *CreateEventEntity, _ *DiffHints) (*DropEventEntityDiff, error) {
+	if c.identicalOtherThanName(other) {
+		return nil, nil
+	}
+	dropDiff := c.Drop().(*DropEventEntityDiff)
+	dropDiff.subsequentDiff = other.Create().(*CreateEventEntityDiff)
+	return dropDiff, nil
+}
+
+// Create implements Entity interface
+func (c *CreateEventEntity) Create() EntityDiff {
+	return &CreateEventEntityDiff{createEvent: c.CreateEvent}
+}
+
+// Drop implements Entity interface
+func (c *CreateEventEntity) Drop() EntityDiff {
+	dropEvent := &sqlparser.DropEvent{
+		Name: c.CreateEvent.Name,
+	}
+	return &DropEventEntityDiff{from: c, dropEvent: dropEvent}
+}
+
+func (c *CreateEventEntity) Clone() Entity {
+	return &CreateEventEntity{CreateEvent: sqlparser.CloneRefOfCreateEvent(c.CreateEvent)}
+}
+
+func (c *CreateEventEntity) identicalOtherThanName(other *CreateEventEntity) bool {
+	if other == nil {
+		return false
+	}
+	return c.Definition == other.Definition &&
+		sqlparser.Equals.RefOfDefiner(c.Definer, other.Definer) &&
+		sqlparser.Equals.RefOfParsedComments(c.Comments, other.Comments)
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemadiff
+
+import (
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+type CreateRoutineEntityDiff struct {
+	createRoutine *sqlparser.CreateRoutine
+
+	canonicalStatementString string
+}
+
+// IsEmpty implements EntityDiff
+func (d *CreateRoutineEntityDiff) IsEmpty() bool {
+	return d.Statement() == nil
+}
+
+// EntityName implements EntityDiff
+func (d *CreateRoutineEntityDiff) EntityName() string {
+	_, to := d.Entities()
+	return to.Name()
+}
+
+// Entities implements EntityDiff
+func (d *CreateRoutineEntityDiff) Entities() (from Entity, to Entity) {
+	return nil, &CreateRoutineEntity{CreateRoutine: d.createRoutine}
+}
+
+// Statement implements EntityDiff
+func (d *CreateRoutineEntityDiff) Statement() sqlparser.Statement {
+	if d == nil {
+		return nil
+	}
+	return d.createRoutine
+}
+
+// CreateRoutine returns the underlying sqlparser.CreateRoutine that was generated for the diff.
+func (d *CreateRoutineEntityDiff) CreateRoutine() *sqlparser.CreateRoutine {
+	if d == nil {
+		return nil
+	}
+	return d.createRoutine
+}
+
+// StatementString implements EntityDiff
+func (d *CreateRoutineEntityDiff) StatementString() (s string) {
+	if stmt := d.Statement(); stmt != nil {
+		s = sqlparser.String(stmt)
+	}
+	return s
+}
+
+// CanonicalStatementString implements EntityDiff
+func (d *CreateRoutineEntityDiff) CanonicalStatementString() string {
+	if d == nil {
+		return ""
+	}
+	if d.canonicalStatementString == "" {
+		if stmt := d.Statement(); stmt != nil {
+			d.canonicalStatementString = sqlparser.CanonicalString(stmt)
+		}
+	}
+	return d.canonicalStatementString
+}
+
+// SubsequentDiff implements EntityDiff
+func (d *CreateRoutineEntityDiff) SubsequentDiff() EntityDiff {
+	return nil
+}
+
+// SetSubsequentDiff implements EntityDiff
+func (d *CreateRoutineEntityDiff) SetSubsequentDiff(EntityDiff) {
+}
+
+// DropRoutineEntityDiff drops a stored procedure or function. ALTER PROCEDURE/FUNCTION cannot change a
+// routine's parameters or body, and so a changed routine is expressed as a DropRoutineEntityDiff followed by a
+// subsequent CreateRoutineEntityDiff.
+type DropRoutineEntityDiff struct {
+	from        *CreateRoutineEntity
+	dropRoutine *sqlparser.DropRoutine
+
+	subsequentDiff *CreateRoutineEntityDiff
+
+	canonicalStatementString string
+}
+
+// IsEmpty implements EntityDiff
+func (d *DropRoutineEntityDiff) IsEmpty() bool {
+	return d.Statement() == nil
+}
+
+// EntityName implements EntityDiff
+func (d *DropRoutineEntityDiff) EntityName() string {
+	return d.from.Name()
+}
+
+// Entities implements EntityDiff
+func (d *DropRoutineEntityDiff) Entities() (from Entity, to Entity) {
+	return d.from, nil
+}
+
+// Statement implements EntityDiff
+func (d *DropRoutineEntityDiff) Statement() sqlparser.Statement {
+	if d == nil {
+		return nil
+	}
+	return d.dropRoutine
+}
+
+// DropRoutine returns the underlying sqlparser.DropRoutine that was generated for the diff.
+func (d *DropRoutineEntityDiff) DropRoutine() *sqlparser.DropRoutine {
+	if d == nil {
+		return nil
+	}
+	return d.dropRoutine
+}
+
+// StatementString implements EntityDiff
+func (d *DropRoutineEntityDiff) StatementString() (s string) {
+	if stmt := d.Statement(); stmt != nil {
+		s = sqlparser.String(stmt)
+	}
+	return s
+}
+
+// CanonicalStatementString implements EntityDiff
+func (d *DropRoutineEntityDiff) CanonicalStatementString() string {
+	if d == nil {
+		return ""
+	}
+	if d.canonicalStatementString == "" {
+		if stmt := d.Statement(); stmt != nil {
+			d.canonicalStatementString = sqlparser.CanonicalString(stmt)
+		}
+	}
+	return d.canonicalStatementString
+}
+
+// SubsequentDiff implements EntityDiff
+func (d *DropRoutineEntityDiff) SubsequentDiff() EntityDiff {
+	if d == nil || d.subsequentDiff == nil {
+		return nil
+	}
+	return d.subsequentDiff
+}
+
+// SetSubsequentDiff implements EntityDiff
+func (d *DropRoutineEntityDiff) SetSubsequentDiff(subDiff EntityDiff) {
+	if d == nil {
+		return
+	}
+	if createDiff, ok := subDiff.(*CreateRoutineEntityDiff); ok {
+		d.subsequentDiff = createDiff
+	} else {
+		d.subsequentDiff = nil
+	}
+}
+
+// CreateRoutineEntity stands for a PROCEDURE or FUNCTION construct. It contains the routine's CREATE statement.
+type CreateRoutineEntity struct {
+	*sqlparser.CreateRoutine
+}
+
+func NewCreateRoutineEntity(c *sqlparser.CreateRoutine) (*CreateRoutineEntity, error) {
+	entity := &CreateRoutineEntity{CreateRoutine: c}
+	entity.normalize()
+	return entity, nil
+}
+
+func (c *CreateRoutineEntity) normalize() {
+	// IF NOT EXISTS does not affect the resulting routine
+	c.CreateRoutine.IfNotExists = false
+}
+
+// Name implements Entity interface
+func (c *CreateRoutineEntity) Name() string {
+	return c.CreateRoutine.Name.Name.String()
+}
+
+// Diff implements Entity interface function
+func (c *CreateRoutineEntity) Diff(other Entity, hints *DiffHints) (EntityDiff, error) {
+	otherCreateRoutine, ok := other.(*CreateRoutineEntity)
+	if !ok || otherCreateRoutine.Type != c.Type {
+		return nil, ErrEntityTypeMismatch
+	}
+	return c.RoutineDiff(otherCreateRoutine, hints)
+}
+
+// RoutineDiff compares this routine statement with another routine statement of the same type, and sees what
+// it takes to change this routine to look like the other routine.
+// If changes are found, the function returns a DROP diff, followed by a subsequent CREATE diff.
+// It returns nil if no changes are found.
+// the other routine may be of different name; its name is ignored.
+func (c *CreateRoutineEntity) RoutineDiff(other *CreateRoutineEntity, _ *DiffHints) (*DropRoutineEntityDiff, error) {
+	if c.identicalOtherThanName(other) {
+		return nil, nil
+	}
+	dropDiff := c.Drop().(*DropRoutineEntityDiff)
+	dropDiff.subsequentDiff = other.Create().(*CreateRoutineEntityDiff)
+	return dropDiff, nil
+}
+
+// Create implements Entity interface
+func (c *CreateRoutineEntity) Create() EntityDiff {
+	return &CreateRoutineEntityDiff{createRoutine: c.CreateRoutine}
+}
+
+// Drop implements Entity interface
+func (c *CreateRoutineEntity) Drop() EntityDiff {
+	dropRoutine := &sqlparser.DropRoutine{
+		Type: c.CreateRoutine.Type,
+		Name: c.CreateRoutine.Name,
+	}
+	return &DropRoutineEntityDiff{from: c, dropRoutine: dropRoutine}
+}
+
+func (c *CreateRoutineEntity) Clone() Entity {
+	return &CreateRoutineEntity{CreateRoutine: sqlparser.CloneRefOfCreateRoutine(c.CreateRoutine)}
+}
+
+func (c *CreateRoutineEntity) identicalOtherThanName(other *CreateRoutineEntity) bool {
+	if other == nil {
+		return false
+	}
+	return c.Type == other.Type &&
+		c.Definition == other.Definition &&
+		sqlparser.Equals.RefOfDefiner(c.Definer, other.Definer) &&
+		sqlparser.Equals.RefOfParsedComments(c.Comments, other.Comments)
+}
@@ -30,14 +30,19 @@ import (
 	"vitess.io/vitess/go/vt/vtgate/semantics"
 )
 
-// Schema represents a database schema, which may contain entities such as tables and views.
+// Schema represents a database schema, which may contain entities such as tables, views, triggers,
+// stored routines and events.
 // Schema is not in itself an Entity, since it is more of a collection of entities.
 type Schema struct {
-	tables []*CreateTableEntity
-	views  []*CreateViewEntity
+	tables   []*CreateTableEntity
+	views    []*CreateViewEntity
+	routines []*CreateRoutineEntity
+	triggers []*CreateTriggerEntity
+	events   []*CreateEventEntity
 
-	named  map[string]Entity
-	sorted []Entity
+	named      map[string]Entity // tables and views, which share a namespace
+	namespaced map[namespacedName]Entity
+	sorted     []Entity
 
 	foreignKeyParents  []*CreateTableEntity // subset of tables
 	foreignKeyChildren []*CreateTableEntity // subset of tables
@@ -46,10 +51,14 @@ type Schema struct {
 // newEmptySchema is used internally to initialize a Schema object
 func newEmptySchema() *Schema {
 	schema := &Schema{
-		tables: []*CreateTableEntity{},
-		views:  []*CreateViewEntity{},
-		named:  map[string]Entity{},
-		sorted: []Entity{},
+		tables:     []*CreateTableEntity{},
+		views:      []*CreateViewEntity{},
+		routines:   []*CreateRoutineEntity{},
+		triggers:   []*CreateTriggerEntity{},
+		events:     []*CreateEventEntity{},
+		named:      map[string]Entity{},
+		namespaced: map[namespacedName]Entity{},
+		sorted:     []Entity{},
 
 		foreignKeyParents:  []*CreateTableEntity{},
 		foreignKeyChildren: []*CreateTableEntity{},
@@ -66,6 +75,12 @@ func NewSchemaFromEntities(entities []Entity) (*Schema, error) {
 			schema.tables = append(schema.tables, c)
 		case *CreateViewEntity:
 			schema.views = append(schema.views, c)
+		case *CreateRoutineEntity:
+			schema.routines = append(schema.routines, c)
+		case *CreateTriggerEntity:
+			schema.triggers = append(schema.triggers, c)
+		case *CreateEventEntity:
+			schema.events = append(schema.events, c)
 		default:
 			return nil, &UnsupportedEntityError{Entity: c.Name(), Statement: c.Create().CanonicalStatementString()}
 		}
@@ -91,6 +106,24 @@ func NewSchemaFromStatements(statements []sqlparser.Statement) (*Schema, error)
 				return nil, err
 			}
 			entities = append(entities, v)
+		case *sqlparser.CreateRoutine:
+			r, err := NewCreateRoutineEntity(stmt)
+			if err != nil {
+				return nil, err
+			}
+			entities = append(entities, r)
+		case *sqlparser.CreateTrigger:
+			t, err := NewCreateTriggerEntity(stmt)
+			if err != nil {
+				return nil, err
+			}
+			entities = append(entities, t)
+		case *sqlparser.CreateEvent:
+			e, err := NewCreateEventEntity(stmt)
+			if err != nil {
+				return nil, err
+			}
+			entities = append(entities, e)
 		default:
 			return nil, &UnsupportedStatementError{Statement: sqlparser.CanonicalString(s)}
 		}
@@ -112,7 +145,7 @@ func NewSchemaFromQueries(queries []string) (*Schema, error) {
 }
 
 // NewSchemaFromSQL creates a valid and normalized schema based on a SQL blob that contains
-// CREATE statements for various objects (tables, views)
+// CREATE statements for various objects (tables, views, triggers, stored routines, events)
 func NewSchemaFromSQL(sql string) (*Schema, error) {
 	var statements []sqlparser.Statement
 	tokenizer := sqlparser.NewStringTokenizer(sql)
@@ -159,6 +192,57 @@ func getViewDependentTableNames(createView *sqlparser.CreateView) (names []strin
 	return names
 }
 
+// getViewDependentFunctionNames analyzes a CREATE VIEW definition and extracts the names of all functions called
+// by this view. These may be built-in functions or stored functions.
+func getViewDependentFunctionNames(createView *sqlparser.CreateView) (names []string) {
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
+		if funcExpr, ok := node.(*sqlparser.FuncExpr); ok {
+			names = append(names, funcExpr.Name.String())
+		}
+		return true, nil
+	}, createView)
+	return names
+}
+
+// namespacedName identifies an entity in a namespace other than that of tables and views. In MySQL,
+// triggers, procedures, functions and events each have their own namespace.
+type namespacedName struct {
+	namespace string
+	name      string
+}
+
+func newNamespacedName(e Entity) (namespacedName, bool) {
+	switch e := e.(type) {
+	case *CreateRoutineEntity:
+		return namespacedName{namespace: e.Type.ToString(), name: e.Name()}, true
+	case *CreateTriggerEntity:
+		return namespacedName{namespace: "trigger", name: e.Name()}, true
+	case *CreateEventEntity:
+		return namespacedName{namespace: "event", name: e.Name()}, true
+	}
+	return namespacedName{}, false
+}
+
+// namesake returns this schema's entity that has the same name, and is in the same namespace, as the given entity.
+func (s *Schema) namesake(e Entity) (Entity, bool) {
+	if key, ok := newNamespacedName(e); ok {
+		entity, ok := s.namespaced[key]
+		return entity, ok
+	}
+	entity, ok := s.named[e.Name()]
+	return entity, ok
+}
+
+// getTriggerDependentNames returns the names of the table a CREATE TRIGGER definition is defined on, and of
+// the trigger it FOLLOWS or PRECEDES, if any
+func getTriggerDependentNames(createTrigger *sqlparser.CreateTrigger) (names []string) {
+	names = append(names, createTrigger.Table.Name.String())
+	if createTrigger.Order != "" {
+		names = append(names, createTrigger.OrderTrigger.String())
+	}
+	return names
+}
+
 // normalize is called as part of Schema creation process. The user may only get a hold of normalized schema.
 // It validates some cross-entity constraints, and orders entity based on dependencies (e.g. tables, views that read from tables, 2nd level views, etc.)
 func (s *Schema) normalize() error {
@@ -181,14 +265,34 @@ func (s *Schema) normalize() error {
 		}
 		s.named[name] = v
 	}
+	s.namespaced = make(map[namespacedName]Entity, len(s.routines)+len(s.triggers)+len(s.events))
+	for _, e := range s.namespacedEntities() {
+		key, _ := newNamespacedName(e)
+		if _, ok := s.namespaced[key]; ok {
+			return &ApplyDuplicateEntityError{Entity: key.name}
+		}
+		s.namespaced[key] = e
+	}
 
-	// Generally speaking, we want tables and views to be sorted alphabetically
+	// Generally speaking, we want entities to be sorted alphabetically
 	sort.SliceStable(s.tables, func(i, j int) bool {
 		return s.tables[i].Name() < s.tables[j].Name()
 	})
 	sort.SliceStable(s.views, func(i, j int) bool {
 		return s.views[i].Name() < s.views[j].Name()
 	})
+	sort.SliceStable(s.routines, func(i, j int) bool {
+		if s.routines[i].Name() == s.routines[j].Name() {
+			return s.routines[i].Type < s.routines[j].Type
+		}
+		return s.routines[i].Name() < s.routines[j].Name()
+	})
+	sort.SliceStable(s.triggers, func(i, j int) bool {
+		return s.triggers[i].Name() < s.triggers[j].Name()
+	})
+	sort.SliceStable(s.events, func(i, j int) bool {
+		return s.events[i].Name() < s.events[j].Name()
+	})
 
 	// More importantly, we want tables and views to be sorted in applicable order.
 	// For example, if a view v reads from table t, then t must be defined before v.
@@ -258,6 +362,11 @@ func (s *Schema) normalize() error {
 			s.foreignKeyParents = append(s.foreignKeyParents, t)
 		}
 	}
+	// Stored routines come next. Routines are not validated against the objects they use, but a view may call
+	// a stored function, which must then be defined before the view.
+	for _, r := range s.routines {
+		s.sorted = append(s.sorted, r)
+	}
 	// We now iterate all views. We iterate "dependency levels":
 	// - first we want all views that only depend on tables. These are 1st level views.
 	// - then we only want views that depend on 1st level views or on tables. These are 2nd level views.
@@ -293,7 +402,7 @@ func (s *Schema) normalize() error {
 		}
 		iterationLevel++
 	}
-	if len(s.sorted) != len(s.tables)+len(s.views) {
+	if len(s.sorted) != len(s.tables)+len(s.routines)+len(s.views) {
 		// We have leftover tables or views. This can happen if the schema definition is invalid:
 		// - a table's foreign key references a nonexistent table
 		// - two or more tables have circular FK dependency
@@ -317,6 +426,55 @@ func (s *Schema) normalize() error {
 		}
 	}
 
+	// Triggers follow. A trigger's table must exist. A trigger that FOLLOWS or PRECEDES another trigger must be
+	// defined after that other trigger, which in turn must be defined on the same table, with the same timing and event.
+	triggerLevels := make(map[string]int, len(s.triggers))
+	for _, t := range s.triggers {
+		if s.Table(t.TableName()) == nil {
+			errs = errors.Join(errs, &TriggerTableNotFoundError{Trigger: t.Name(), Table: t.TableName()})
+		}
+	}
+	for iterationLevel = 0; ; iterationLevel++ {
+		handledAnyTriggersInIteration := false
+		for _, t := range s.triggers {
+			if _, ok := triggerLevels[t.Name()]; ok {
+				// already handled; skip
+				continue
+			}
+			if t.Order != "" {
+				referencedLevel, ok := triggerLevels[t.OrderTrigger.String()]
+				if !ok || referencedLevel >= iterationLevel {
+					continue
+				}
+			}
+			s.sorted = append(s.sorted, t)
+			triggerLevels[t.Name()] = iterationLevel
+			handledAnyTriggersInIteration = true
+		}
+		if !handledAnyTriggersInIteration {
+			break
+		}
+	}
+	for _, t := range s.triggers {
+		if _, ok := triggerLevels[t.Name()]; ok {
+			if t.Order == "" {
+				continue
+			}
+			referenced := s.Trigger(t.OrderTrigger.String())
+			if t.TableName() == referenced.TableName() && t.Timing == referenced.Timing && t.Event == referenced.Event {
+				continue
+			}
+		} else {
+			// We still add it so it shows up in the output if that is used for anything.
+			s.sorted = append(s.sorted, t)
+		}
+		errs = errors.Join(errs, &TriggerOrderDependencyUnresolvedError{Trigger: t.Name(), ReferencedTrigger: t.OrderTrigger.String()})
+	}
+	// Events come last
+	for _, e := range s.events {
+		s.sorted = append(s.sorted, e)
+	}
+
 	// Validate views' referenced columns: do these columns actually exist in referenced tables/views?
 	if err := s.ValidateViewReferences(); err != nil {
 		errs = errors.Join(errs, err)
@@ -443,13 +601,61 @@ func (s *Schema) ViewNames() []string {
 	return names
 }
 
+// Routines returns this schema's stored procedures and functions
+func (s *Schema) Routines() []*CreateRoutineEntity {
+	var routines []*CreateRoutineEntity
+	for _, entity := range s.sorted {
+		if routine, ok := entity.(*CreateRoutineEntity); ok {
+			routines = append(routines, routine)
+		}
+	}
+	return routines
+}
+
+// Triggers returns this schema's triggers in good order (may be applied without error)
+func (s *Schema) Triggers() []*CreateTriggerEntity {
+	var triggers []*CreateTriggerEntity
+	for _, entity := range s.sorted {
+		if trigger, ok := entity.(*CreateTriggerEntity); ok {
+			triggers = append(triggers, trigger)
+		}
+	}
+	return triggers
+}
+
+// Events returns this schema's events
+func (s *Schema) Events() []*CreateEventEntity {
+	var events []*CreateEventEntity
+	for _, entity := range s.sorted {
+		if event, ok := entity.(*CreateEventEntity); ok {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// namespacedEntities returns this schema's routines, triggers and events, in no particular order
+func (s *Schema) namespacedEntities() []Entity {
+	entities := make([]Entity, 0, len(s.routines)+len(s.triggers)+len(s.events))
+	for _, r := range s.routines {
+		entities = append(entities, r)
+	}
+	for _, t := range s.triggers {
+		entities = append(entities, t)
+	}
+	for _, e := range s.events {
+		entities = append(entities, e)
+	}
+	return entities
+}
+
 // Diff compares this schema with another schema, and sees what it takes to make this schema look
 // like the other. It returns a list of diffs.
 func (s *Schema) diff(other *Schema, hints *DiffHints) (diffs []EntityDiff, err error) {
 	// dropped entities
 	var dropDiffs []EntityDiff
 	for _, e := range s.Entities() {
-		if _, ok := other.named[e.Name()]; !ok {
+		if _, ok := other.namesake(e); !ok {
 			// other schema does not have the entity
 			// Entities are sorted in foreign key CREATE TABLE valid order (create parents first, then children).
 			// When issuing DROPs, we want to reverse that order. We want to first frop children, then parents.
@@ -463,7 +669,7 @@ func (s *Schema) diff(other *Schema, hints *DiffHints) (diffs []EntityDiff, err
 	var alterDiffs []EntityDiff
 	var createDiffs []EntityDiff
 	for _, e := range other.Entities() {
-		if fromEntity, ok := s.named[e.Name()]; ok {
+		if fromEntity, ok := s.namesake(e); ok {
 			// entities exist by same name in both schemas. Let's diff them.
 			diff, err := fromEntity.Diff(e, hints)
 
@@ -572,7 +778,7 @@ func (s *Schema) heuristicallyDetectTableRenames(
 	return dropDiffs, createDiffs, renameDiffs
 }
 
-// Entity returns an entity by name, or nil if nonexistent
+// Entity returns a table or a view by name, or nil if nonexistent
 func (s *Schema) Entity(name string) Entity {
 	return s.named[name]
 }
@@ -593,6 +799,38 @@ func (s *Schema) View(name string) *CreateViewEntity {
 	return nil
 }
 
+// Trigger returns a trigger by name, or nil if nonexistent
+func (s *Schema) Trigger(name string) *CreateTriggerEntity {
+	if trigger, ok := s.namespaced[namespacedName{namespace: "trigger", name: name}].(*CreateTriggerEntity); ok {
+		return trigger
+	}
+	return nil
+}
+
+// Procedure returns a stored procedure by name, or nil if nonexistent
+func (s *Schema) Procedure(name string) *CreateRoutineEntity {
+	if routine, ok := s.namespaced[namespacedName{namespace: sqlparser.ProcedureType.ToString(), name: name}].(*CreateRoutineEntity); ok {
+		return routine
+	}
+	return nil
+}
+
+// Function returns a stored function by name, or nil if nonexistent
+func (s *Schema) Function(name string) *CreateRoutineEntity {
+	if routine, ok := s.namespaced[namespacedName{namespace: sqlparser.FunctionType.ToString(), name: name}].(*CreateRoutineEntity); ok {
+		return routine
+	}
+	return nil
+}
+
+// Event returns an event by name, or nil if nonexistent
+func (s *Schema) Event(name string) *CreateEventEntity {
+	if event, ok := s.namespaced[namespacedName{namespace: "event", name: name}].(*CreateEventEntity); ok {
+		return event
+	}
+	return nil
+}
+
 // ToStatements returns an ordered list of statements which can be applied to create the schema
 func (s *Schema) ToStatements() []sqlparser.Statement {
 	stmts := make([]sqlparser.Statement, 0, len(s.Entities()))
@@ -629,17 +867,78 @@ func (s *Schema) copy() *Schema {
 	copy(dup.tables, s.tables)
 	dup.views = make([]*CreateViewEntity, len(s.views))
 	copy(dup.views, s.views)
+	dup.routines = make([]*CreateRoutineEntity, len(s.routines))
+	copy(dup.routines, s.routines)
+	dup.triggers = make([]*CreateTriggerEntity, len(s.triggers))
+	copy(dup.triggers, s.triggers)
+	dup.events = make([]*CreateEventEntity, len(s.events))
+	copy(dup.events, s.events)
 	dup.named = make(map[string]Entity, len(s.named))
 	for k, v := range s.named {
 		dup.named[k] = v
 	}
+	dup.namespaced = make(map[namespacedName]Entity, len(s.namespaced))
+	for k, v := range s.namespaced {
+		dup.namespaced[k] = v
+	}
 	dup.sorted = make([]Entity, len(s.sorted))
 	copy(dup.sorted, s.sorted)
 	return dup
 }
 
+// addNamespaced adds a routine, trigger or event to this schema. We expect the entity to not exist.
+func (s *Schema) addNamespaced(e Entity) error {
+	key, _ := newNamespacedName(e)
+	if _, ok := s.namespaced[key]; ok {
+		return &ApplyDuplicateEntityError{Entity: key.name}
+	}
+	switch e := e.(type) {
+	case *CreateRoutineEntity:
+		s.routines = append(s.routines, e)
+	case *CreateTriggerEntity:
+		s.triggers = append(s.triggers, e)
+	case *CreateEventEntity:
+		s.events = append(s.events, e)
+	}
+	s.namespaced[key] = e
+	return nil
+}
+
+// dropNamespaced removes a routine, trigger or event from this schema. It returns false if the entity does not exist.
+func (s *Schema) dropNamespaced(e Entity) bool {
+	key, _ := newNamespacedName(e)
+	if _, ok := s.namespaced[key]; !ok {
+		return false
+	}
+	switch e.(type) {
+	case *CreateRoutineEntity:
+		for i, r := range s.routines {
+			if k, _ := newNamespacedName(r); k == key {
+				s.routines = append(s.routines[0:i], s.routines[i+1:]...)
+				break
+			}
+		}
+	case *CreateTriggerEntity:
+		for i, t := range s.triggers {
+			if t.Name() == key.name {
+				s.triggers = append(s.triggers[0:i], s.triggers[i+1:]...)
+				break
+			}
+		}
+	case *CreateEventEntity:
+		for i, ev := range s.events {
+			if ev.Name() == key.name {
+				s.events = append(s.events[0:i], s.events[i+1:]...)
+				break
+			}
+		}
+	}
+	delete(s.namespaced, key)
+	return true
+}
+
 // apply attempts to apply given list of diffs to this object.
-// These diffs are CREATE/DROP/ALTER TABLE/VIEW.
+// These diffs are CREATE/DROP/ALTER TABLE/VIEW, and CREATE/DROP TRIGGER/PROCEDURE/FUNCTION/EVENT.
 func (s *Schema) apply(diffs []EntityDiff) error {
 	for _, diff := range diffs {
 		switch diff := diff.(type) {
@@ -673,6 +972,16 @@ func (s *Schema) apply(diffs []EntityDiff) error {
 			if !found {
 				return &ApplyTableNotFoundError{Table: diff.from.Table.Name.String()}
 			}
+			// Like MySQL, dropping a table implicitly drops its triggers
+			var tableTriggers []*CreateTriggerEntity
+			for _, t := range s.triggers {
+				if t.TableName() == diff.from.Table.Name.String() {
+					tableTriggers = append(tableTriggers, t)
+				}
+			}
+			for _, t := range tableTriggers {
+				s.dropNamespaced(t)
+			}
 		case *DropViewEntityDiff:
 			// We expect the view to exist
 			found := false
@@ -746,6 +1055,51 @@ func (s *Schema) apply(diffs []EntityDiff) error {
 			if !found {
 				return &ApplyTableNotFoundError{Table: diff.from.Table.Name.String()}
 			}
+		case *CreateRoutineEntityDiff:
+			_, to := diff.Entities()
+			if err := s.addNamespaced(to); err != nil {
+				return err
+			}
+		case *DropRoutineEntityDiff:
+			if !s.dropNamespaced(diff.from) {
+				return &ApplyRoutineNotFoundError{Type: diff.from.Type.ToString(), Routine: diff.from.Name()}
+			}
+			if diff.subsequentDiff != nil {
+				_, to := diff.subsequentDiff.Entities()
+				if err := s.addNamespaced(to); err != nil {
+					return err
+				}
+			}
+		case *CreateTriggerEntityDiff:
+			_, to := diff.Entities()
+			if err := s.addNamespaced(to); err != nil {
+				return err
+			}
+		case *DropTriggerEntityDiff:
+			if !s.dropNamespaced(diff.from) {
+				return &ApplyTriggerNotFoundError{Trigger: diff.from.Name()}
+			}
+			if diff.subsequentDiff != nil {
+				_, to := diff.subsequentDiff.Entities()
+				if err := s.addNamespaced(to); err != nil {
+					return err
+				}
+			}
+		case *CreateEventEntityDiff:
+			_, to := diff.Entities()
+			if err := s.addNamespaced(to); err != nil {
+				return err
+			}
+		case *DropEventEntityDiff:
+			if !s.dropNamespaced(diff.from) {
+				return &ApplyEventNotFoundError{Event: diff.from.Name()}
+			}
+			if diff.subsequentDiff != nil {
+				_, to := diff.subsequentDiff.Entities()
+				if err := s.addNamespaced(to); err != nil {
+					return err
+				}
+			}
 		default:
 			return &UnsupportedApplyOperationError{Statement: diff.CanonicalStatementString()}
 		}
@@ -757,7 +1111,7 @@ func (s *Schema) apply(diffs []EntityDiff) error {
 }
 
 // Apply attempts to apply given list of diffs to the schema described by this object.
-// These diffs are CREATE/DROP/ALTER TABLE/VIEW.
+// These diffs are CREATE/DROP/ALTER TABLE/VIEW, and CREATE/DROP TRIGGER/PROCEDURE/FUNCTION/EVENT.
 // The operation does not modify this object. Instead, if successful, a new (modified) Schema is returned.
 func (s *Schema) Apply(diffs []EntityDiff) (*Schema, error) {
 	dup := s.copy()
@@ -795,6 +1149,18 @@ func (s *Schema) SchemaDiff(other *Schema, hints *DiffHints) (*SchemaDiff, error
 		return dependentDiffs, relationsMade
 	}
 
+	// Utility function to record dependencies of a view diff on diffs of stored functions the view calls
+	checkFunctionDependencies := func(diff EntityDiff, functionNames []string) {
+		for _, functionName := range functionNames {
+			for _, dependentDiff := range schemaDiff.diffsByEntityName(functionName) {
+				switch dependentDiff.(type) {
+				case *CreateRoutineEntityDiff, *DropRoutineEntityDiff:
+					schemaDiff.addDep(diff, dependentDiff, DiffDependencyOrderUnknown)
+				}
+			}
+		}
+	}
+
 	checkChildForeignKeyDefinition := func(fk *sqlparser.ForeignKeyDefinition, diff EntityDiff) (bool, error) {
 		// We add a foreign key. Normally that's fine, expect for a couple specific scenarios
 		parentTableName := fk.ReferenceDefinition.ReferencedTable.Name.String()
@@ -862,11 +1228,19 @@ func (s *Schema) SchemaDiff(other *Schema, hints *DiffHints) (*SchemaDiff, error
 		switch diff := diff.(type) {
 		case *CreateViewEntityDiff:
 			checkDependencies(diff, getViewDependentTableNames(diff.createView))
+			checkFunctionDependencies(diff, getViewDependentFunctionNames(diff.createView))
 		case *AlterViewEntityDiff:
 			checkDependencies(diff, getViewDependentTableNames(diff.from.CreateView))
 			checkDependencies(diff, getViewDependentTableNames(diff.to.CreateView))
+			checkFunctionDependencies(diff, getViewDependentFunctionNames(diff.from.CreateView))
+			checkFunctionDependencies(diff, getViewDependentFunctionNames(diff.to.CreateView))
 		case *DropViewEntityDiff:
 			checkDependencies(diff, getViewDependentTableNames(diff.from.CreateView))
+			checkFunctionDependencies(diff, getViewDependentFunctionNames(diff.from.CreateView))
+		case *CreateTriggerEntityDiff:
+			checkDependencies(diff, getTriggerDependentNames(diff.createTrigger))
+		case *DropTriggerEntityDiff:
+			checkDependencies(diff, getTriggerDependentNames(diff.from.CreateTrigger))
 		case *CreateTableEntityDiff:
 			checkDependencies(diff, getForeignKeyParentTableNames(diff.CreateTable()))
 			_ = sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
@@ -927,6 +1301,12 @@ func (s *Schema) ValidateViewReferences() error {
 	// that only depend on those tables (or on dual), then 2nd tier views, etc.
 	// Thus, the order of iteration below is valid and sufficient, to build
 	for _, e := range s.Entities() {
+		switch e.(type) {
+		case *CreateTableEntity, *CreateViewEntity:
+		default:
+			// Not a table nor a view: no columns to read
+			continue
+		}
 		entityColumns, err := s.getEntityColumnNames(e.Name(), schemaInformation)
 		if err != nil {
 			errs = errors.Join(errs, err)
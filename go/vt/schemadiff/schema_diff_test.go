@@ -835,6 +835,120 @@ func TestSchemaDiff(t *testing.T) {
 			sequential:  true,
 			entityOrder: []string{"t1", "t3"},
 		},
+		// Triggers, routines, events
+		{
+			name: "create table and trigger",
+			toQueries: append(createQueries,
+				"create table t3 (id int primary key, ts timestamp);",
+				"create trigger t3_bi before insert on t3 for each row set new.ts = now();",
+			),
+			expectDiffs: 2,
+			expectDeps:  1,
+			entityOrder: []string{"t3", "t3_bi"},
+		},
+		{
+			name: "alter table and trigger",
+			fromQueries: append(createQueries,
+				"create trigger t1_bi before insert on t1 for each row set new.info = 1;",
+			),
+			toQueries: []string{
+				"create table t1 (id int primary key, info int not null, ts timestamp);",
+				"create table t2 (id int primary key, ts timestamp);",
+				"create view v1 as select id from t1",
+				"create trigger t1_bi before insert on t1 for each row begin set new.info = 1; set new.ts = now(); end;",
+			},
+			expectDiffs: 3,
+			expectDeps:  3,
+			sequential:  true,
+			entityOrder: []string{"t1", "t1_bi", "t1_bi"},
+		},
+		{
+			name: "drop table with trigger",
+			fromQueries: append(createQueries,
+				"create trigger t2_bi before insert on t2 for each row set new.ts = now();",
+			),
+			toQueries: []string{
+				"create table t1 (id int primary key, info int not null);",
+				"create view v1 as select id from t1",
+			},
+			expectDiffs: 2,
+			expectDeps:  1,
+			entityOrder: []string{"t2_bi", "t2"},
+		},
+		{
+			name: "ordered triggers",
+			toQueries: append(createQueries,
+				"create trigger t1_bi_2 before insert on t1 for each row follows t1_bi_1 set new.info = new.info + 1;",
+				"create trigger t1_bi_1 before insert on t1 for each row set new.info = 1;",
+			),
+			expectDiffs: 2,
+			expectDeps:  1,
+			entityOrder: []string{"t1_bi_1", "t1_bi_2"},
+		},
+		{
+			name: "create function and view calling it",
+			toQueries: append(createQueries,
+				"create view v2 as select f1(id) as fid from t1",
+				"create function f1(x int) returns int deterministic return x + 1;",
+			),
+			expectDiffs: 2,
+			expectDeps:  1,
+			entityOrder: []string{"f1", "v2"},
+		},
+		{
+			name: "drop function and view calling it",
+			fromQueries: append(createQueries,
+				"create view v2 as select f1(id) as fid from t1",
+				"create function f1(x int) returns int deterministic return x + 1;",
+			),
+			toQueries:   createQueries,
+			expectDiffs: 2,
+			expectDeps:  1,
+			entityOrder: []string{"v2", "f1"},
+		},
+		{
+			name: "change function",
+			fromQueries: append(createQueries,
+				"create function f1(x int) returns int deterministic return x + 1;",
+			),
+			toQueries: append(createQueries,
+				"create function f1(x int) returns int deterministic return x + 2;",
+			),
+			expectDiffs: 2,
+			expectDeps:  1,
+			sequential:  true,
+			entityOrder: []string{"f1", "f1"},
+		},
+		{
+			name: "change procedure with case statement",
+			fromQueries: append(createQueries,
+				"create procedure p1(x int) begin case x when 1 then select 1; else select 2; end case; end;",
+			),
+			toQueries: append(createQueries,
+				"create procedure p1(x int) begin case x when 1 then select 1; when 2 then select 2; else select 3; end case; end;",
+			),
+			expectDiffs: 2,
+			expectDeps:  1,
+			sequential:  true,
+			entityOrder: []string{"p1", "p1"},
+		},
+		{
+			name: "procedure and function of same name",
+			toQueries: append(createQueries,
+				"create procedure r1() begin select 1; end;",
+				"create function r1() returns int deterministic return 1;",
+			),
+			expectDiffs: 2,
+			entityOrder: []string{"r1", "r1"},
+		},
+		{
+			name: "create event",
+			toQueries: append(createQueries,
+				"create event e1 on schedule every 1 hour do delete from t2 where ts < now() - interval 1 day;",
+			),
+			expectDiffs: 1,
+			entityOrder: []string{"e1"},
+		},
 	}
 	hints := &DiffHints{RangeRotationStrategy: RangeRotationDistinctStatements}
 	for _, tc := range tt {
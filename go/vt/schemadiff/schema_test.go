@@ -370,6 +370,29 @@ func TestInvalidSchema(t *testing.T) {
 			schema:    "create table t10(id varchar(50) charset utf8mb3 primary key); create table t11 (id int primary key, i varchar(100) charset utf8mb4, key ix(i), constraint f10 foreign key (i) references t10(id) on delete restrict)",
 			expectErr: &ForeignKeyColumnTypeMismatchError{Table: "t11", Constraint: "f10", Column: "i", ReferencedTable: "t10", ReferencedColumn: "id"},
 		},
+		{
+			schema: "create table t1(id int primary key); create trigger t1 before insert on t1 for each row set new.id = 1",
+		},
+		{
+			schema:    "create table t1(id int primary key); create trigger t2_bi before insert on t2 for each row set new.id = 1",
+			expectErr: &TriggerTableNotFoundError{Trigger: "t2_bi", Table: "t2"},
+		},
+		{
+			schema:    "create table t1(id int primary key); create view v1 as select id from t1; create trigger v1_bi before insert on v1 for each row set new.id = 1",
+			expectErr: &TriggerTableNotFoundError{Trigger: "v1_bi", Table: "v1"},
+		},
+		{
+			schema:    "create table t1(id int primary key); create trigger t1_bi before insert on t1 for each row follows t1_bi0 set new.id = 1",
+			expectErr: &TriggerOrderDependencyUnresolvedError{Trigger: "t1_bi", ReferencedTrigger: "t1_bi0"},
+		},
+		{
+			schema:    "create table t1(id int primary key); create trigger t1_bi0 after insert on t1 for each row set @x = 1; create trigger t1_bi before insert on t1 for each row follows t1_bi0 set new.id = 1",
+			expectErr: &TriggerOrderDependencyUnresolvedError{Trigger: "t1_bi", ReferencedTrigger: "t1_bi0"},
+		},
+		{
+			schema:    "create table t1(id int primary key); create trigger t1_bi before insert on t1 for each row set @x = 1; create trigger t1_bi before update on t1 for each row set @x = 2",
+			expectErr: &ApplyDuplicateEntityError{Entity: "t1_bi"},
+		},
 	}
 	for _, ts := range tt {
 		t.Run(ts.schema, func(t *testing.T) {
@@ -385,6 +408,64 @@ func TestInvalidSchema(t *testing.T) {
 	}
 }
 
+func TestTriggersRoutinesAndEvents(t *testing.T) {
+	queries := []string{
+		"create event e1 on schedule every 1 day do call p1()",
+		"create trigger t1_bi_2 before insert on t1 for each row follows t1_bi_1 set new.info = f1(new.info)",
+		"create view v1 as select f1(id) as fid from t1",
+		"create procedure p1() begin delete from t1 where info < 0; select count(*) from v1; end",
+		"create trigger t1_bi_1 before insert on t1 for each row begin if new.info is null then set new.info = 0; end if; end",
+		"create function f1(x int) returns int deterministic return x + 1",
+		"create table t1 (id int primary key, info int)",
+	}
+	schema, err := NewSchemaFromQueries(queries)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1", "f1", "p1", "v1", "t1_bi_1", "t1_bi_2", "e1"}, schema.EntityNames())
+	assert.Equal(t, []string{"t1"}, schema.TableNames())
+	assert.Equal(t, []string{"v1"}, schema.ViewNames())
+	assert.Len(t, schema.Routines(), 2)
+	assert.Len(t, schema.Triggers(), 2)
+	assert.Len(t, schema.Events(), 1)
+
+	assert.NotNil(t, schema.Function("f1"))
+	assert.Nil(t, schema.Procedure("f1"))
+	assert.NotNil(t, schema.Procedure("p1"))
+	assert.NotNil(t, schema.Trigger("t1_bi_1"))
+	assert.NotNil(t, schema.Event("e1"))
+	// triggers, routines and events do not share a namespace with tables and views
+	assert.Nil(t, schema.Entity("f1"))
+
+	t.Run("to SQL and back", func(t *testing.T) {
+		sql := schema.ToSQL()
+		assert.Contains(t, sql, "CREATE PROCEDURE `p1` () begin delete from t1 where info < 0; select count(*) from v1; end;\n")
+
+		schemaFromSQL, err := NewSchemaFromSQL(sql)
+		require.NoError(t, err)
+		assert.Equal(t, schema.EntityNames(), schemaFromSQL.EntityNames())
+
+		diffs, err := schema.diff(schemaFromSQL, &DiffHints{})
+		require.NoError(t, err)
+		assert.Empty(t, diffs)
+	})
+	t.Run("apply", func(t *testing.T) {
+		dropTable := schema.Table("t1").Drop()
+		dropView := schema.View("v1").Drop()
+		applied, err := schema.Apply([]EntityDiff{dropView, dropTable})
+		require.NoError(t, err)
+		// Dropping a table implicitly drops its triggers
+		assert.Equal(t, []string{"f1", "p1", "e1"}, applied.EntityNames())
+
+		_, err = applied.Apply([]EntityDiff{schema.Trigger("t1_bi_1").Drop()})
+		assert.EqualError(t, err, (&ApplyTriggerNotFoundError{Trigger: "t1_bi_1"}).Error())
+
+		_, err = applied.Apply([]EntityDiff{schema.Trigger("t1_bi_1").Create()})
+		assert.EqualError(t, err, (&TriggerTableNotFoundError{Trigger: "t1_bi_1", Table: "t1"}).Error())
+
+		_, err = schema.Apply([]EntityDiff{schema.Function("f1").Create()})
+		assert.EqualError(t, err, (&ApplyDuplicateEntityError{Entity: "f1"}).Error())
+	})
+}
+
 func TestInvalidTableForeignKeyReference(t *testing.T) {
 	{
 		fkQueries := []string{
@@ -0,0 +1,257 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemadiff
+
+import (
+	"strings"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+type CreateTriggerEntityDiff struct {
+	createTrigger *sqlparser.CreateTrigger
+
+	canonicalStatementString string
+}
+
+// IsEmpty implements EntityDiff
+func (d *CreateTriggerEntityDiff) IsEmpty() bool {
+	return d.Statement() == nil
+}
+
+// EntityName implements EntityDiff
+func (d *CreateTriggerEntityDiff) EntityName() string {
+	_, to := d.Entities()
+	return to.Name()
+}
+
+// Entities implements EntityDiff
+func (d *CreateTriggerEntityDiff) Entities() (from Entity, to Entity) {
+	return nil, &CreateTriggerEntity{CreateTrigger: d.createTrigger}
+}
+
+// Statement implements EntityDiff
+func (d *CreateTriggerEntityDiff) Statement() sqlparser.Statement {
+	if d == nil {
+		return nil
+	}
+	return d.createTrigger
+}
+
+// CreateTrigger returns the underlying sqlparser.CreateTrigger that was generated for the diff.
+func (d *CreateTriggerEntityDiff) CreateTrigger() *sqlparser.CreateTrigger {
+	if d == nil {
+		return nil
+	}
+	return d.createTrigger
+}
+
+// StatementString implements EntityDiff
+func (d *CreateTriggerEntityDiff) StatementString() (s string) {
+	if stmt := d.Statement(); stmt != nil {
+		s = sqlparser.String(stmt)
+	}
+	return s
+}
+
+// CanonicalStatementString implements EntityDiff
+func (d *CreateTriggerEntityDiff) CanonicalStatementString() string {
+	if d == nil {
+		return ""
+	}
+	if d.canonicalStatementString == "" {
+		if stmt := d.Statement(); stmt != nil {
+			d.canonicalStatementString = sqlparser.CanonicalString(stmt)
+		}
+	}
+	return d.canonicalStatementString
+}
+
+// SubsequentDiff implements EntityDiff
+func (d *CreateTriggerEntityDiff) SubsequentDiff() EntityDiff {
+	return nil
+}
+
+// SetSubsequentDiff implements EntityDiff
+func (d *CreateTriggerEntityDiff) SetSubsequentDiff(EntityDiff) {
+}
+
+// DropTriggerEntityDiff drops a trigger. Since MySQL cannot alter a trigger, a changed trigger
+// is expressed as a DropTriggerEntityDiff followed by a subsequent CreateTriggerEntityDiff.
+type DropTriggerEntityDiff struct {
+	from        *CreateTriggerEntity
+	dropTrigger *sqlparser.DropTrigger
+
+	subsequentDiff *CreateTriggerEntityDiff
+
+	canonicalStatementString string
+}
+
+// IsEmpty implements EntityDiff
+func (d *DropTriggerEntityDiff) IsEmpty() bool {
+	return d.Statement() == nil
+}
+
+// EntityName implements EntityDiff
+func (d *DropTriggerEntityDiff) EntityName() string {
+	return d.from.Name()
+}
+
+// Entities implements EntityDiff
+func (d *DropTriggerEntityDiff) Entities() (from Entity, to Entity) {
+	return d.from, nil
+}
+
+// Statement implements EntityDiff
+func (d *DropTriggerEntityDiff) Statement() sqlparser.Statement {
+	if d == nil {
+		return nil
+	}
+	return d.dropTrigger
+}
+
+// DropTrigger returns the underlying sqlparser.DropTrigger that was generated for the diff.
+func (d *DropTriggerEntityDiff) DropTrigger() *sqlparser.DropTrigger {
+	if d == nil {
+		return nil
+	}
+	return d.dropTrigger
+}
+
+// StatementString implements EntityDiff
+func (d *DropTriggerEntityDiff) StatementString() (s string) {
+	if stmt := d.Statement(); stmt != nil {
+		s = sqlparser.String(stmt)
+	}
+	return s
+}
+
+// CanonicalStatementString implements EntityDiff
+func (d *DropTriggerEntityDiff) CanonicalStatementString() string {
+	if d == nil {
+		return ""
+	}
+	if d.canonicalStatementString == "" {
+		if stmt := d.Statement(); stmt != nil {
+			d.canonicalStatementString = sqlparser.CanonicalString(stmt)
+		}
+	}
+	return d.canonicalStatementString
+}
+
+// SubsequentDiff implements EntityDiff
+func (d *DropTriggerEntityDiff) SubsequentDiff() EntityDiff {
+	if d == nil || d.subsequentDiff == nil {
+		return nil
+	}
+	return d.subsequentDiff
+}
+
+// SetSubsequentDiff implements EntityDiff
+func (d *DropTriggerEntityDiff) SetSubsequentDiff(subDiff EntityDiff) {
+	if d == nil {
+		return
+	}
+	if createDiff, ok := subDiff.(*CreateTriggerEntityDiff); ok {
+		d.subsequentDiff = createDiff
+	} else {
+		d.subsequentDiff = nil
+	}
+}
+
+// CreateTriggerEntity stands for a TRIGGER construct. It contains the trigger's CREATE statement.
+type CreateTriggerEntity struct {
+	*sqlparser.CreateTrigger
+}
+
+func NewCreateTriggerEntity(c *sqlparser.CreateTrigger) (*CreateTriggerEntity, error) {
+	entity := &CreateTriggerEntity{CreateTrigger: c}
+	entity.normalize()
+	return entity, nil
+}
+
+func (c *CreateTriggerEntity) normalize() {
+	// IF NOT EXISTS does not affect the resulting trigger
+	c.CreateTrigger.IfNotExists = false
+	c.CreateTrigger.Timing = strings.ToLower(c.CreateTrigger.Timing)
+	c.CreateTrigger.Event = strings.ToLower(c.CreateTrigger.Event)
+	c.CreateTrigger.Order = strings.ToLower(c.CreateTrigger.Order)
+}
+
+// Name implements Entity interface
+func (c *CreateTriggerEntity) Name() string {
+	return c.CreateTrigger.Name.Name.String()
+}
+
+// TableName returns the name of the table this trigger is defined on
+func (c *CreateTriggerEntity) TableName() string {
+	return c.CreateTrigger.Table.Name.String()
+}
+
+// Diff implements Entity interface function
+func (c *CreateTriggerEntity) Diff(other Entity, hints *DiffHints) (EntityDiff, error) {
+	otherCreateTrigger, ok := other.(*CreateTriggerEntity)
+	if !ok {
+		return nil, ErrEntityTypeMismatch
+	}
+	return c.TriggerDiff(otherCreateTrigger, hints)
+}
+
+// TriggerDiff compares this trigger statement with another trigger statement, and sees what it takes to
+// change this trigger to look like the other trigger.
+// MySQL does not support altering a trigger. Therefore, if changes are found, the function returns a
+// DROP TRIGGER diff, followed by a subsequent CREATE TRIGGER diff. It returns nil if no changes are found.
+// the other trigger may be of different name; its name is ignored.
+func (c *CreateTriggerEntity) TriggerDiff(other *CreateTriggerEntity, _ *DiffHints) (*DropTriggerEntityDiff, error) {
+	if c.identicalOtherThanName(other) {
+		return nil, nil
+	}
+	dropDiff := c.Drop().(*DropTriggerEntityDiff)
+	dropDiff.subsequentDiff = other.Create().(*CreateTriggerEntityDiff)
+	return dropDiff, nil
+}
+
+// Create implements Entity interface
+func (c *CreateTriggerEntity) Create() EntityDiff {
+	return &CreateTriggerEntityDiff{createTrigger: c.CreateTrigger}
+}
+
+// Drop implements Entity interface
+func (c *CreateTriggerEntity) Drop() EntityDiff {
+	dropTrigger := &sqlparser.DropTrigger{
+		Name: c.CreateTrigger.Name,
+	}
+	return &DropTriggerEntityDiff{from: c, dropTrigger: dropTrigger}
+}
+
+func (c *CreateTriggerEntity) Clone() Entity {
+	return &CreateTriggerEntity{CreateTrigger: sqlparser.CloneRefOfCreateTrigger(c.CreateTrigger)}
+}
+
+func (c *CreateTriggerEntity) identicalOtherThanName(other *CreateTriggerEntity) bool {
+	if other == nil {
+		return false
+	}
+	return c.Timing == other.Timing &&
+		c.Event == other.Event &&
+		c.Order == other.Order &&
+		c.Body == other.Body &&
+		sqlparser.Equals.IdentifierCS(c.Table.Name, other.Table.Name) &&
+		sqlparser.Equals.IdentifierCS(c.OrderTrigger, other.OrderTrigger) &&
+		sqlparser.Equals.RefOfDefiner(c.Definer, other.Definer) &&
+		sqlparser.Equals.RefOfParsedComments(c.Comments, other.Comments)
+}
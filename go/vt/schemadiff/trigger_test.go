@@ -0,0 +1,167 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemadiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestCreateTriggerDiff(t *testing.T) {
+	tt := []struct {
+		name  string
+		from  string
+		to    string
+		diffs []string
+	}{
+		{
+			name: "identical",
+			from: "create trigger t1_bi before insert on t1 for each row set new.x = 1",
+			to:   "create trigger t1_bi before insert on t1 for each row set new.x = 1",
+		},
+		{
+			name: "identical, spacing and if not exists",
+			from: "create trigger t1_bi before insert on t1 for each row begin set new.x = 1; end",
+			to: `create trigger if not exists t1_bi BEFORE INSERT on t1 for each row
+				begin
+					set new.x = 1;
+				end`,
+		},
+		{
+			name: "identical, case statement",
+			from: "create trigger t1_bi before insert on t1 for each row begin case when new.x > 1 then set new.y = 1; else set new.y = 2; end case; end",
+			to: `create trigger t1_bi before insert on t1 for each row
+				begin
+					case
+						when new.x > 1 then set new.y = 1;
+						else set new.y = 2;
+					end case;
+				end`,
+		},
+		{
+			name: "change of body",
+			from: "create trigger t1_bi before insert on t1 for each row set new.x = 1",
+			to:   "create trigger t1_bi before insert on t1 for each row set new.x = 2",
+			diffs: []string{
+				"drop trigger t1_bi",
+				"create trigger t1_bi before insert on t1 for each row set new.x = 2",
+			},
+		},
+		{
+			name: "change of timing",
+			from: "create trigger t1_bi before insert on t1 for each row set new.x = 1",
+			to:   "create trigger t1_bi after insert on t1 for each row set new.x = 1",
+			diffs: []string{
+				"drop trigger t1_bi",
+				"create trigger t1_bi after insert on t1 for each row set new.x = 1",
+			},
+		},
+		{
+			name: "change of table",
+			from: "create trigger t1_bi before insert on t1 for each row set new.x = 1",
+			to:   "create trigger t1_bi before insert on t2 for each row set new.x = 1",
+			diffs: []string{
+				"drop trigger t1_bi",
+				"create trigger t1_bi before insert on t2 for each row set new.x = 1",
+			},
+		},
+		{
+			name: "change of definer",
+			from: "create trigger t1_bi before insert on t1 for each row set new.x = 1",
+			to:   "create definer = `app`@`%` trigger t1_bi before insert on t1 for each row set new.x = 1",
+			diffs: []string{
+				"drop trigger t1_bi",
+				"create definer = app@`%` trigger t1_bi before insert on t1 for each row set new.x = 1",
+			},
+		},
+	}
+	hints := &DiffHints{}
+	for _, ts := range tt {
+		t.Run(ts.name, func(t *testing.T) {
+			fromStmt, err := sqlparser.ParseStrictDDL(ts.from)
+			require.NoError(t, err)
+			fromCreateTrigger, ok := fromStmt.(*sqlparser.CreateTrigger)
+			require.True(t, ok)
+
+			toStmt, err := sqlparser.ParseStrictDDL(ts.to)
+			require.NoError(t, err)
+			toCreateTrigger, ok := toStmt.(*sqlparser.CreateTrigger)
+			require.True(t, ok)
+
+			c, err := NewCreateTriggerEntity(fromCreateTrigger)
+			require.NoError(t, err)
+			other, err := NewCreateTriggerEntity(toCreateTrigger)
+			require.NoError(t, err)
+			diff, err := c.Diff(other, hints)
+			require.NoError(t, err)
+			if len(ts.diffs) == 0 {
+				assert.Nil(t, diff)
+				return
+			}
+			require.NotNil(t, diff)
+			require.False(t, diff.IsEmpty())
+
+			var diffs []string
+			for _, d := range AllSubsequent(diff) {
+				diffs = append(diffs, d.StatementString())
+				// validate we can parse back the statement
+				_, err := sqlparser.ParseStrictDDL(d.CanonicalStatementString())
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, ts.diffs, diffs)
+		})
+	}
+}
+
+func TestNormalizeTrigger(t *testing.T) {
+	tt := []struct {
+		name   string
+		from   string
+		to     string
+		ctable string
+	}{
+		{
+			name:   "basic trigger",
+			from:   "create trigger t1_bi before insert on t1 for each row set new.x = 1",
+			to:     "CREATE TRIGGER `t1_bi` BEFORE INSERT ON `t1` FOR EACH ROW set new.x = 1",
+			ctable: "t1",
+		},
+		{
+			name:   "removes if not exists",
+			from:   "create trigger if not exists t1_bu after update on t1 for each row follows t1_bu0 begin insert into log values (new.id); end",
+			to:     "CREATE TRIGGER `t1_bu` AFTER UPDATE ON `t1` FOR EACH ROW FOLLOWS `t1_bu0` begin insert into log values (new.id); end",
+			ctable: "t1",
+		},
+	}
+	for _, ts := range tt {
+		t.Run(ts.name, func(t *testing.T) {
+			stmt, err := sqlparser.ParseStrictDDL(ts.from)
+			require.NoError(t, err)
+			fromCreateTrigger, ok := stmt.(*sqlparser.CreateTrigger)
+			require.True(t, ok)
+
+			from, err := NewCreateTriggerEntity(fromCreateTrigger)
+			require.NoError(t, err)
+			assert.Equal(t, ts.to, sqlparser.CanonicalString(from))
+			assert.Equal(t, ts.ctable, from.TableName())
+		})
+	}
+}
@@ -0,0 +1,352 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package schemalint checks schemas and schema changes against a configurable set of rules.
+
+A Linter runs two kinds of rules: TableRules, which look at a single table definition (either an existing
+table in a schema, or a table about to be created), and ChangeRules, which look at a DDL
+statement in the context of the schema it is about to be applied to. Rules are registered by name via
+RegisterRule; the built-in rules are registered by this package, and each rule's severity can be
+overridden, or the rule disabled altogether, via the linter configuration.
+*/
+package schemalint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"vitess.io/vitess/go/vt/schemadiff"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// Severity indicates how a finding of a rule is treated.
+type Severity int
+
+const (
+	// SeverityOff disables a rule.
+	SeverityOff Severity = iota
+	// SeverityWarning reports findings without failing the lint.
+	SeverityWarning
+	// SeverityError reports findings and fails the lint.
+	SeverityError
+)
+
+// String returns the configuration name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityOff:
+		return "off"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("unknown severity %d", int(s))
+}
+
+// ParseSeverity parses the configuration name of a severity.
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "off":
+		return SeverityOff, nil
+	case "warning", "warn":
+		return SeverityWarning, nil
+	case "error":
+		return SeverityError, nil
+	}
+	return SeverityOff, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown lint severity: %q", s)
+}
+
+// Finding is a single violation of a rule.
+type Finding struct {
+	Rule     string
+	Severity Severity
+	// Entity is the name of the table or view the finding relates to.
+	Entity  string
+	Message string
+}
+
+// String returns a human readable representation of the finding.
+func (f *Finding) String() string {
+	return fmt.Sprintf("%s: [%s] %s: %s", f.Severity, f.Rule, f.Entity, f.Message)
+}
+
+// Findings is a list of rule violations.
+type Findings []*Finding
+
+// HasErrors returns true if any of the findings has error severity.
+func (fs Findings) HasErrors() bool {
+	for _, f := range fs {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Errors returns the findings that have error severity.
+func (fs Findings) Errors() (errs Findings) {
+	for _, f := range fs {
+		if f.Severity == SeverityError {
+			errs = append(errs, f)
+		}
+	}
+	return errs
+}
+
+// Warnings returns the findings that have warning severity.
+func (fs Findings) Warnings() (warnings Findings) {
+	for _, f := range fs {
+		if f.Severity == SeverityWarning {
+			warnings = append(warnings, f)
+		}
+	}
+	return warnings
+}
+
+// Err returns a FAILED_PRECONDITION error listing all error findings, or nil if there are none.
+func (fs Findings) Err() error {
+	errs := fs.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+	msgs := make([]string, 0, len(errs))
+	for _, f := range errs {
+		msgs = append(msgs, f.String())
+	}
+	return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "schema lint failed with %d error(s):\n%s", len(errs), strings.Join(msgs, "\n"))
+}
+
+// Env is the context in which rules are evaluated.
+type Env struct {
+	// Keyspace is the name of the keyspace being linted. Optional.
+	Keyspace string
+	// VSchema is the keyspace's VSchema. Optional; rules that need it do not report anything without it.
+	VSchema *vschemapb.Keyspace
+}
+
+// Rule is the common interface of all lint rules. A rule must additionally implement
+// TableRule, ChangeRule, or both.
+type Rule interface {
+	// Name is the unique name of the rule, used in configuration and findings.
+	Name() string
+	// Description is a short, human readable explanation of what the rule checks.
+	Description() string
+	// DefaultSeverity is the severity the rule runs at unless configured otherwise.
+	DefaultSeverity() Severity
+}
+
+// TableRule checks a single table definition.
+type TableRule interface {
+	Rule
+	// LintTable returns a message for each violation in the given table.
+	LintTable(env *Env, table *schemadiff.CreateTableEntity) []string
+}
+
+// ChangeRule checks a DDL statement against the schema it is applied to.
+type ChangeRule interface {
+	Rule
+	// LintChange returns a message for each violation the statement would introduce when applied to
+	// the given schema. The schema is never nil.
+	LintChange(env *Env, schema *schemadiff.Schema, stmt sqlparser.DDLStatement) []string
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Rule{}
+)
+
+// RegisterRule adds a rule to the set of rules available to linters. It panics if the rule
+// implements neither TableRule nor ChangeRule, or if a rule by the same name is already registered.
+func RegisterRule(rule Rule) {
+	_, isTableRule := rule.(TableRule)
+	_, isChangeRule := rule.(ChangeRule)
+	if !isTableRule && !isChangeRule {
+		panic(fmt.Sprintf("schemalint: rule %s implements neither TableRule nor ChangeRule", rule.Name()))
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[rule.Name()]; ok {
+		panic(fmt.Sprintf("schemalint: rule %s already registered", rule.Name()))
+	}
+	registry[rule.Name()] = rule
+}
+
+// Rules returns all registered rules, sorted by name.
+func Rules() []Rule {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	rules := make([]Rule, 0, len(registry))
+	for _, rule := range registry {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Name() < rules[j].Name()
+	})
+	return rules
+}
+
+type configuredRule struct {
+	rule     Rule
+	severity Severity
+}
+
+// Linter checks schemas and schema changes against a set of rules.
+type Linter struct {
+	rules []configuredRule
+}
+
+// NewLinter returns a linter running all registered rules at their default severity, overridden by
+// the given configuration. The configuration is a comma separated list of rule=severity entries, where
+// severity is one of "error", "warning" or "off"; for example:
+//
+//	missing-primary-key=warning,non-innodb-engine=off
+//
+// An empty configuration runs all rules at their default severity.
+func NewLinter(config string) (*Linter, error) {
+	severities := map[string]Severity{}
+	for _, rule := range Rules() {
+		severities[rule.Name()] = rule.DefaultSeverity()
+	}
+	for _, entry := range strings.Split(config, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid lint rule configuration %q, expected rule=severity", entry)
+		}
+		name = strings.TrimSpace(name)
+		if _, ok := severities[name]; !ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown lint rule: %q", name)
+		}
+		severity, err := ParseSeverity(value)
+		if err != nil {
+			return nil, err
+		}
+		severities[name] = severity
+	}
+
+	linter := &Linter{}
+	for _, rule := range Rules() {
+		if severity := severities[rule.Name()]; severity != SeverityOff {
+			linter.rules = append(linter.rules, configuredRule{rule: rule, severity: severity})
+		}
+	}
+	return linter, nil
+}
+
+// LintSchema runs the table rules against every table in the given schema.
+func (l *Linter) LintSchema(env *Env, schema *schemadiff.Schema) (findings Findings) {
+	for _, table := range schema.Tables() {
+		findings = append(findings, l.lintTable(env, table)...)
+	}
+	return findings
+}
+
+// LintStatements runs the linter against a list of DDL statements about to be applied to the given
+// schema. Table rules run against tables created by the statements, and change rules run against all
+// statements. Each statement is evaluated against the original schema; statements that are not DDL are
+// ignored.
+func (l *Linter) LintStatements(env *Env, schema *schemadiff.Schema, stmts []sqlparser.Statement) (Findings, error) {
+	if schema == nil {
+		var err error
+		if schema, err = schemadiff.NewSchemaFromEntities(nil); err != nil {
+			return nil, err
+		}
+	}
+	var findings Findings
+	for _, stmt := range stmts {
+		ddl, ok := stmt.(sqlparser.DDLStatement)
+		if !ok {
+			continue
+		}
+		if createTable, ok := ddl.(*sqlparser.CreateTable); ok {
+			table, err := schemadiff.NewCreateTableEntity(sqlparser.CloneRefOfCreateTable(createTable))
+			if err != nil {
+				return nil, err
+			}
+			findings = append(findings, l.lintTable(env, table)...)
+		}
+		for _, cr := range l.rules {
+			rule, ok := cr.rule.(ChangeRule)
+			if !ok {
+				continue
+			}
+			for _, msg := range rule.LintChange(env, schema, ddl) {
+				findings = append(findings, &Finding{
+					Rule:     rule.Name(),
+					Severity: cr.severity,
+					Entity:   ddl.GetTable().Name.String(),
+					Message:  msg,
+				})
+			}
+		}
+	}
+	return findings, nil
+}
+
+// LintDiffs runs the linter against the statements of the given diffs, including subsequent diffs,
+// as computed between the given schema and some target schema.
+func (l *Linter) LintDiffs(env *Env, schema *schemadiff.Schema, diffs []schemadiff.EntityDiff) (Findings, error) {
+	var stmts []sqlparser.Statement
+	for _, diff := range diffs {
+		for _, d := range schemadiff.AllSubsequent(diff) {
+			if d.IsEmpty() {
+				continue
+			}
+			stmts = append(stmts, d.Statement())
+		}
+	}
+	return l.LintStatements(env, schema, stmts)
+}
+
+func (l *Linter) lintTable(env *Env, table *schemadiff.CreateTableEntity) (findings Findings) {
+	for _, cr := range l.rules {
+		rule, ok := cr.rule.(TableRule)
+		if !ok {
+			continue
+		}
+		for _, msg := range rule.LintTable(env, table) {
+			findings = append(findings, &Finding{
+				Rule:     rule.Name(),
+				Severity: cr.severity,
+				Entity:   table.Name(),
+				Message:  msg,
+			})
+		}
+	}
+	return findings
+}
+
+// NewSchemaFromDefinition builds a schema out of the table and view definitions reported by a tablet.
+func NewSchemaFromDefinition(sd *tabletmanagerdatapb.SchemaDefinition) (*schemadiff.Schema, error) {
+	queries := make([]string, 0, len(sd.GetTableDefinitions()))
+	for _, td := range sd.GetTableDefinitions() {
+		queries = append(queries, td.Schema)
+	}
+	return schemadiff.NewSchemaFromQueries(queries)
+}
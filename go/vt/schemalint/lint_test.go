@@ -0,0 +1,215 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemalint
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/schemadiff"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+func findingStrings(findings Findings) (strs []string) {
+	for _, f := range findings {
+		strs = append(strs, f.String())
+	}
+	return strs
+}
+
+func parseStatements(t *testing.T, queries ...string) (stmts []sqlparser.Statement) {
+	for _, q := range queries {
+		stmt, err := sqlparser.ParseStrictDDL(q)
+		require.NoError(t, err)
+		stmts = append(stmts, stmt)
+	}
+	return stmts
+}
+
+func TestNewLinter(t *testing.T) {
+	tt := []struct {
+		name   string
+		config string
+		rules  map[string]Severity
+		err    string
+	}{
+		{
+			name:   "defaults",
+			config: "",
+			rules: map[string]Severity{
+				"deprecated-column-type":      SeverityWarning,
+				"drop-view-referenced-column": SeverityError,
+				"missing-primary-key":         SeverityError,
+				"non-innodb-engine":           SeverityError,
+				"unindexed-sharding-key":      SeverityError,
+				"unsafe-column-type-change":   SeverityError,
+			},
+		},
+		{
+			name:   "overrides",
+			config: "missing-primary-key=warning, non-innodb-engine=off,deprecated-column-type=error",
+			rules: map[string]Severity{
+				"deprecated-column-type":      SeverityError,
+				"drop-view-referenced-column": SeverityError,
+				"missing-primary-key":         SeverityWarning,
+				"unindexed-sharding-key":      SeverityError,
+				"unsafe-column-type-change":   SeverityError,
+			},
+		},
+		{
+			name:   "unknown rule",
+			config: "no-such-rule=error",
+			err:    `unknown lint rule: "no-such-rule"`,
+		},
+		{
+			name:   "unknown severity",
+			config: "missing-primary-key=fatal",
+			err:    `unknown lint severity: "fatal"`,
+		},
+		{
+			name:   "malformed entry",
+			config: "missing-primary-key",
+			err:    "expected rule=severity",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			linter, err := NewLinter(tc.config)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			rules := map[string]Severity{}
+			for _, cr := range linter.rules {
+				rules[cr.rule.Name()] = cr.severity
+			}
+			assert.Equal(t, tc.rules, rules)
+		})
+	}
+}
+
+func TestLintSchema(t *testing.T) {
+	schema, err := schemadiff.NewSchemaFromQueries([]string{
+		"create table t1 (id int primary key, name varchar(64)) engine=InnoDB",
+		"create table t2 (id int, name varchar(64)) engine=MyISAM",
+		"create table t3 (id int primary key, price float(10,2) unsigned)",
+		"create view v1 as select id from t1",
+	})
+	require.NoError(t, err)
+
+	linter, err := NewLinter("")
+	require.NoError(t, err)
+	findings := linter.LintSchema(&Env{}, schema)
+	assert.Equal(t, []string{
+		"error: [missing-primary-key] t2: table has no PRIMARY KEY",
+		"error: [non-innodb-engine] t2: table uses the MyISAM storage engine",
+		"warning: [deprecated-column-type] t3: column price uses the deprecated FLOAT(M,D) syntax",
+		"warning: [deprecated-column-type] t3: column price uses the deprecated UNSIGNED attribute on a FLOAT type",
+	}, findingStrings(findings))
+	assert.True(t, findings.HasErrors())
+	assert.Len(t, findings.Warnings(), 2)
+	assert.ErrorContains(t, findings.Err(), "schema lint failed with 2 error(s)")
+
+	linter, err = NewLinter("missing-primary-key=warning,non-innodb-engine=off")
+	require.NoError(t, err)
+	findings = linter.LintSchema(&Env{}, schema)
+	assert.False(t, findings.HasErrors())
+	assert.NoError(t, findings.Err())
+	assert.Len(t, findings, 3)
+}
+
+func TestLintStatements(t *testing.T) {
+	schema, err := schemadiff.NewSchemaFromQueries([]string{
+		"create table t1 (id int primary key, name varchar(64), total decimal(10,2))",
+		"create view v1 as select id, name from t1",
+	})
+	require.NoError(t, err)
+
+	linter, err := NewLinter("")
+	require.NoError(t, err)
+	findings, err := linter.LintStatements(&Env{}, schema, parseStatements(t,
+		"create table t2 (id int)",
+		"alter table t1 modify column total decimal(8,2), drop column name",
+		"drop view v1",
+	))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"error: [missing-primary-key] t2: table has no PRIMARY KEY",
+		"error: [drop-view-referenced-column] t1: column name is referenced by view v1",
+		"error: [unsafe-column-type-change] t1: column total: precision narrows from decimal(10,2) to decimal(8,2)",
+	}, findingStrings(findings))
+
+	// statements may also be linted without a schema
+	findings, err = linter.LintStatements(&Env{}, nil, parseStatements(t, "create table t2 (id int primary key) engine=MEMORY"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"error: [non-innodb-engine] t2: table uses the MEMORY storage engine",
+	}, findingStrings(findings))
+}
+
+func TestLintDiffs(t *testing.T) {
+	from, err := schemadiff.NewSchemaFromQueries([]string{
+		"create table t1 (id int primary key, customer_id bigint, key customer_idx (customer_id))",
+	})
+	require.NoError(t, err)
+	to, err := schemadiff.NewSchemaFromQueries([]string{
+		"create table t1 (id int primary key, customer_id int)",
+		"create table t2 (id int)",
+	})
+	require.NoError(t, err)
+	schemaDiff, err := schemadiff.DiffSchemas(from, to, &schemadiff.DiffHints{})
+	require.NoError(t, err)
+	diffs, err := schemaDiff.OrderedDiffs(context.Background())
+	require.NoError(t, err)
+
+	env := &Env{
+		VSchema: &vschemapb.Keyspace{
+			Sharded: true,
+			Tables: map[string]*vschemapb.Table{
+				"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Name: "hash", Columns: []string{"customer_id"}}}},
+			},
+		},
+	}
+	linter, err := NewLinter("")
+	require.NoError(t, err)
+	findings, err := linter.LintDiffs(env, from, diffs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"error: [unindexed-sharding-key] t1: primary vindex hash column(s) customer_id are not the leftmost columns of any index",
+		"error: [unsafe-column-type-change] t1: column customer_id: type narrows from bigint to int",
+		"error: [missing-primary-key] t2: table has no PRIMARY KEY",
+	}, findingStrings(findings))
+}
+
+func TestNewSchemaFromDefinition(t *testing.T) {
+	schema, err := NewSchemaFromDefinition(&tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{Name: "t1", Schema: "CREATE TABLE `t1` (`id` int NOT NULL, PRIMARY KEY (`id`)) ENGINE=InnoDB", Type: "BASE TABLE"},
+			{Name: "v1", Schema: "CREATE ALGORITHM=UNDEFINED DEFINER=`root`@`localhost` SQL SECURITY DEFINER VIEW `v1` AS select `t1`.`id` AS `id` from `t1`", Type: "VIEW"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1"}, schema.TableNames())
+	assert.Equal(t, []string{"v1"}, schema.ViewNames())
+}
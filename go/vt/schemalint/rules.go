@@ -0,0 +1,348 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemalint
+
+import (
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/vt/schemadiff"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func init() {
+	RegisterRule(&missingPrimaryKeyRule{})
+	RegisterRule(&nonInnoDBEngineRule{})
+	RegisterRule(&deprecatedColumnTypeRule{})
+	RegisterRule(&unindexedShardingKeyRule{})
+	RegisterRule(&unsafeColumnTypeChangeRule{})
+	RegisterRule(&dropViewReferencedColumnRule{})
+}
+
+// missingPrimaryKeyRule reports tables without a PRIMARY KEY. Online DDL, VReplication and row based
+// replication all rely on tables having a primary key, or at least a unique key on non-null columns.
+type missingPrimaryKeyRule struct{}
+
+func (*missingPrimaryKeyRule) Name() string { return "missing-primary-key" }
+
+func (*missingPrimaryKeyRule) Description() string {
+	return "table has no PRIMARY KEY"
+}
+
+func (*missingPrimaryKeyRule) DefaultSeverity() Severity { return SeverityError }
+
+func (r *missingPrimaryKeyRule) LintTable(env *Env, table *schemadiff.CreateTableEntity) []string {
+	for _, index := range table.TableSpec.Indexes {
+		if index.Info.Type == sqlparser.IndexTypePrimary {
+			return nil
+		}
+	}
+	return []string{"table has no PRIMARY KEY"}
+}
+
+// nonInnoDBEngineRule reports tables using a storage engine other than InnoDB.
+type nonInnoDBEngineRule struct{}
+
+func (*nonInnoDBEngineRule) Name() string { return "non-innodb-engine" }
+
+func (*nonInnoDBEngineRule) Description() string {
+	return "table uses a storage engine other than InnoDB"
+}
+
+func (*nonInnoDBEngineRule) DefaultSeverity() Severity { return SeverityError }
+
+func (r *nonInnoDBEngineRule) LintTable(env *Env, table *schemadiff.CreateTableEntity) []string {
+	for _, option := range table.TableSpec.Options {
+		if strings.EqualFold(option.Name, "engine") && !strings.EqualFold(option.String, "innodb") {
+			return []string{fmt.Sprintf("table uses the %s storage engine", option.String)}
+		}
+	}
+	return nil
+}
+
+// deprecatedColumnTypeRule reports column definitions MySQL has deprecated: ZEROFILL, FLOAT and DOUBLE
+// with explicit precision, UNSIGNED on non-integer numeric types, AUTO_INCREMENT on floating point types,
+// and the utf8mb3 character set.
+type deprecatedColumnTypeRule struct{}
+
+func (*deprecatedColumnTypeRule) Name() string { return "deprecated-column-type" }
+
+func (*deprecatedColumnTypeRule) Description() string {
+	return "column uses a deprecated type or type attribute"
+}
+
+func (*deprecatedColumnTypeRule) DefaultSeverity() Severity { return SeverityWarning }
+
+func (r *deprecatedColumnTypeRule) LintTable(env *Env, table *schemadiff.CreateTableEntity) (msgs []string) {
+	for _, col := range table.TableSpec.Columns {
+		colType := col.Type
+		family := columnTypeFamily(colType.Type)
+		name := col.Name.String()
+		if colType.Zerofill {
+			msgs = append(msgs, fmt.Sprintf("column %s uses the deprecated ZEROFILL attribute", name))
+		}
+		if family == familyFloat && colType.Length != nil && colType.Scale != nil {
+			msgs = append(msgs, fmt.Sprintf("column %s uses the deprecated %s(M,D) syntax", name, strings.ToUpper(colType.Type)))
+		}
+		if (family == familyFloat || family == familyDecimal) && colType.Unsigned {
+			msgs = append(msgs, fmt.Sprintf("column %s uses the deprecated UNSIGNED attribute on a %s type", name, strings.ToUpper(colType.Type)))
+		}
+		if family == familyFloat && colType.Options != nil && colType.Options.Autoincrement {
+			msgs = append(msgs, fmt.Sprintf("column %s uses the deprecated AUTO_INCREMENT attribute on a %s type", name, strings.ToUpper(colType.Type)))
+		}
+		if charsetName(colType.Charset.Name) == "utf8mb3" {
+			msgs = append(msgs, fmt.Sprintf("column %s uses the deprecated utf8mb3 character set", name))
+		}
+	}
+	if charsetName(table.GetCharset()) == "utf8mb3" {
+		msgs = append(msgs, "table uses the deprecated utf8mb3 character set")
+	}
+	return msgs
+}
+
+// unindexedShardingKeyRule reports sharded tables whose primary vindex columns are not the leftmost
+// columns of any index. Such tables cannot efficiently serve the queries vtgate routes to a shard by
+// the sharding key, nor the lookups VReplication performs by it.
+type unindexedShardingKeyRule struct{}
+
+func (*unindexedShardingKeyRule) Name() string { return "unindexed-sharding-key" }
+
+func (*unindexedShardingKeyRule) Description() string {
+	return "primary vindex columns are not covered by an index"
+}
+
+func (*unindexedShardingKeyRule) DefaultSeverity() Severity { return SeverityError }
+
+func (r *unindexedShardingKeyRule) LintTable(env *Env, table *schemadiff.CreateTableEntity) []string {
+	return r.lint(env, table.Name(), table.TableSpec.Indexes)
+}
+
+// LintChange checks ALTER TABLE statements that drop indexes, as these may leave the sharding key unindexed.
+func (r *unindexedShardingKeyRule) LintChange(env *Env, schema *schemadiff.Schema, stmt sqlparser.DDLStatement) []string {
+	alterTable, ok := stmt.(*sqlparser.AlterTable)
+	if !ok {
+		return nil
+	}
+	table := schema.Table(alterTable.Table.Name.String())
+	if table == nil {
+		return nil
+	}
+	dropped := map[string]bool{}
+	var indexes []*sqlparser.IndexDefinition
+	for _, option := range alterTable.AlterOptions {
+		switch option := option.(type) {
+		case *sqlparser.DropKey:
+			if option.Type == sqlparser.PrimaryKeyType {
+				dropped["primary"] = true
+			} else {
+				dropped[option.Name.Lowered()] = true
+			}
+		case *sqlparser.AddIndexDefinition:
+			indexes = append(indexes, option.IndexDefinition)
+		}
+	}
+	if len(dropped) == 0 {
+		return nil
+	}
+	for _, index := range table.TableSpec.Indexes {
+		name := index.Info.Name.Lowered()
+		if index.Info.Type == sqlparser.IndexTypePrimary {
+			name = "primary"
+		}
+		if !dropped[name] {
+			indexes = append(indexes, index)
+		}
+	}
+	return r.lint(env, table.Name(), indexes)
+}
+
+func (r *unindexedShardingKeyRule) lint(env *Env, tableName string, indexes []*sqlparser.IndexDefinition) []string {
+	if env == nil || env.VSchema == nil || !env.VSchema.Sharded {
+		return nil
+	}
+	vschemaTable, ok := env.VSchema.Tables[tableName]
+	if !ok || len(vschemaTable.ColumnVindexes) == 0 {
+		return nil
+	}
+	primaryVindex := vschemaTable.ColumnVindexes[0]
+	columns := primaryVindex.Columns
+	if len(columns) == 0 && primaryVindex.Column != "" {
+		columns = []string{primaryVindex.Column}
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+	for _, index := range indexes {
+		if isIndexPrefix(index, columns) {
+			return nil
+		}
+	}
+	return []string{fmt.Sprintf("primary vindex %s column(s) %s are not the leftmost columns of any index", primaryVindex.Name, strings.Join(columns, ", "))}
+}
+
+// isIndexPrefix returns true when the given columns, in any order, are the leftmost columns of the index.
+func isIndexPrefix(index *sqlparser.IndexDefinition, columns []string) bool {
+	if len(index.Columns) < len(columns) {
+		return false
+	}
+	prefix := map[string]bool{}
+	for _, col := range index.Columns[:len(columns)] {
+		if col.Expression != nil {
+			return false
+		}
+		prefix[col.Column.Lowered()] = true
+	}
+	for _, col := range columns {
+		if !prefix[strings.ToLower(col)] {
+			return false
+		}
+	}
+	return true
+}
+
+// unsafeColumnTypeChangeRule reports ALTER TABLE statements that change a column's type in a way that
+// may lose or alter data: narrowing, changing signedness, changing the character set, removing enum
+// or set values, or changing the type altogether.
+type unsafeColumnTypeChangeRule struct{}
+
+func (*unsafeColumnTypeChangeRule) Name() string { return "unsafe-column-type-change" }
+
+func (*unsafeColumnTypeChangeRule) Description() string {
+	return "column type change may lose or alter data"
+}
+
+func (*unsafeColumnTypeChangeRule) DefaultSeverity() Severity { return SeverityError }
+
+func (r *unsafeColumnTypeChangeRule) LintChange(env *Env, schema *schemadiff.Schema, stmt sqlparser.DDLStatement) (msgs []string) {
+	alterTable, ok := stmt.(*sqlparser.AlterTable)
+	if !ok {
+		return nil
+	}
+	table := schema.Table(alterTable.Table.Name.String())
+	if table == nil {
+		return nil
+	}
+	tableCharset := table.GetCharset()
+	for _, option := range alterTable.AlterOptions {
+		var oldName string
+		var newCol *sqlparser.ColumnDefinition
+		switch option := option.(type) {
+		case *sqlparser.ModifyColumn:
+			oldName, newCol = option.NewColDefinition.Name.String(), option.NewColDefinition
+		case *sqlparser.ChangeColumn:
+			oldName, newCol = option.OldColumn.Name.String(), option.NewColDefinition
+		default:
+			continue
+		}
+		oldCol := findColumn(table, oldName)
+		if oldCol == nil {
+			continue
+		}
+		if reason := unsafeTypeChange(oldCol.Type, newCol.Type, tableCharset); reason != "" {
+			msgs = append(msgs, fmt.Sprintf("column %s: %s", oldName, reason))
+		}
+	}
+	return msgs
+}
+
+// dropViewReferencedColumnRule reports ALTER TABLE statements that drop or rename columns still
+// referenced by views. Such views become invalid once the change is applied.
+type dropViewReferencedColumnRule struct{}
+
+func (*dropViewReferencedColumnRule) Name() string { return "drop-view-referenced-column" }
+
+func (*dropViewReferencedColumnRule) Description() string {
+	return "dropped or renamed column is referenced by a view"
+}
+
+func (*dropViewReferencedColumnRule) DefaultSeverity() Severity { return SeverityError }
+
+func (r *dropViewReferencedColumnRule) LintChange(env *Env, schema *schemadiff.Schema, stmt sqlparser.DDLStatement) (msgs []string) {
+	alterTable, ok := stmt.(*sqlparser.AlterTable)
+	if !ok {
+		return nil
+	}
+	tableName := alterTable.Table.Name.String()
+	for _, option := range alterTable.AlterOptions {
+		var removed string
+		switch option := option.(type) {
+		case *sqlparser.DropColumn:
+			removed = option.Name.Name.String()
+		case *sqlparser.ChangeColumn:
+			if option.OldColumn.Name.Equal(option.NewColDefinition.Name) {
+				continue
+			}
+			removed = option.OldColumn.Name.String()
+		case *sqlparser.RenameColumn:
+			removed = option.OldName.Name.String()
+		default:
+			continue
+		}
+		for _, view := range schema.Views() {
+			if viewReferencesColumn(view.CreateView, tableName, removed) {
+				msgs = append(msgs, fmt.Sprintf("column %s is referenced by view %s", removed, view.Name()))
+			}
+		}
+	}
+	return msgs
+}
+
+// viewReferencesColumn returns true when the view selects from the given table and references the given
+// column, either by name or implicitly via a wildcard. Unqualified column names are attributed to the
+// table, as resolving them would require the definitions of all tables the view reads from.
+func viewReferencesColumn(view *sqlparser.CreateView, tableName string, columnName string) bool {
+	// qualifiers holds the names by which the view refers to the table
+	qualifiers := map[string]bool{}
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
+		if node, ok := node.(*sqlparser.AliasedTableExpr); ok {
+			if name, ok := node.Expr.(sqlparser.TableName); ok && name.Name.String() == tableName {
+				qualifiers[tableName] = true
+				if !node.As.IsEmpty() {
+					qualifiers[node.As.String()] = true
+				}
+			}
+		}
+		return true, nil
+	}, view.Select)
+	if len(qualifiers) == 0 {
+		return false
+	}
+	referenced := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
+		switch node := node.(type) {
+		case *sqlparser.ColName:
+			if node.Name.EqualString(columnName) && (node.Qualifier.IsEmpty() || qualifiers[node.Qualifier.Name.String()]) {
+				referenced = true
+			}
+		case *sqlparser.StarExpr:
+			if node.TableName.IsEmpty() || qualifiers[node.TableName.Name.String()] {
+				referenced = true
+			}
+		}
+		return !referenced, nil
+	}, view.Select)
+	return referenced
+}
+
+func findColumn(table *schemadiff.CreateTableEntity, name string) *sqlparser.ColumnDefinition {
+	for _, col := range table.TableSpec.Columns {
+		if col.Name.EqualString(name) {
+			return col
+		}
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemalint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/schemadiff"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+func TestTableRules(t *testing.T) {
+	vschema := &vschemapb.Keyspace{
+		Sharded: true,
+		Tables: map[string]*vschemapb.Table{
+			"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Name: "xxhash", Columns: []string{"a", "b"}}}},
+			"t2": {ColumnVindexes: []*vschemapb.ColumnVindex{{Name: "hash", Column: "customer_id"}}},
+		},
+	}
+	tt := []struct {
+		name  string
+		rule  TableRule
+		table string
+		msgs  []string
+	}{
+		{
+			name:  "primary key",
+			rule:  &missingPrimaryKeyRule{},
+			table: "create table t1 (id int primary key)",
+		},
+		{
+			name:  "no primary key, unique key",
+			rule:  &missingPrimaryKeyRule{},
+			table: "create table t1 (id int not null, unique key id_uidx (id))",
+			msgs:  []string{"table has no PRIMARY KEY"},
+		},
+		{
+			name:  "implicit engine",
+			rule:  &nonInnoDBEngineRule{},
+			table: "create table t1 (id int primary key)",
+		},
+		{
+			name:  "innodb engine, any case",
+			rule:  &nonInnoDBEngineRule{},
+			table: "create table t1 (id int primary key) ENGINE=innodb",
+		},
+		{
+			name:  "myisam engine",
+			rule:  &nonInnoDBEngineRule{},
+			table: "create table t1 (id int primary key) engine=MyISAM",
+			msgs:  []string{"table uses the MyISAM storage engine"},
+		},
+		{
+			name:  "no deprecated types",
+			rule:  &deprecatedColumnTypeRule{},
+			table: "create table t1 (id int unsigned primary key, d double, n decimal(10,2), s varchar(10) charset utf8mb4)",
+		},
+		{
+			name:  "deprecated types",
+			rule:  &deprecatedColumnTypeRule{},
+			table: "create table t1 (id int(10) zerofill primary key, d double(10,2), n decimal(10,2) unsigned, f float auto_increment, s varchar(10) charset utf8)",
+			msgs: []string{
+				"column id uses the deprecated ZEROFILL attribute",
+				"column d uses the deprecated DOUBLE(M,D) syntax",
+				"column n uses the deprecated UNSIGNED attribute on a DECIMAL type",
+				"column f uses the deprecated AUTO_INCREMENT attribute on a FLOAT type",
+				"column s uses the deprecated utf8mb3 character set",
+			},
+		},
+		{
+			name:  "deprecated table charset",
+			rule:  &deprecatedColumnTypeRule{},
+			table: "create table t1 (id int primary key) charset utf8mb3",
+			msgs:  []string{"table uses the deprecated utf8mb3 character set"},
+		},
+		{
+			name:  "sharding key indexed by primary key",
+			rule:  &unindexedShardingKeyRule{},
+			table: "create table t1 (a int, b int, c int, primary key (b, a, c))",
+		},
+		{
+			name:  "sharding key indexed by secondary key",
+			rule:  &unindexedShardingKeyRule{},
+			table: "create table t2 (id int primary key, customer_id int, key customer_idx (customer_id, id))",
+		},
+		{
+			name:  "sharding key not leftmost",
+			rule:  &unindexedShardingKeyRule{},
+			table: "create table t1 (a int, b int, c int, primary key (a, c, b))",
+			msgs:  []string{"primary vindex xxhash column(s) a, b are not the leftmost columns of any index"},
+		},
+		{
+			name:  "legacy column vindex",
+			rule:  &unindexedShardingKeyRule{},
+			table: "create table t2 (id int primary key, customer_id int)",
+			msgs:  []string{"primary vindex hash column(s) customer_id are not the leftmost columns of any index"},
+		},
+		{
+			name:  "table not in vschema",
+			rule:  &unindexedShardingKeyRule{},
+			table: "create table t3 (id int primary key, customer_id int)",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			stmt, err := sqlparser.ParseStrictDDL(tc.table)
+			require.NoError(t, err)
+			createTable, ok := stmt.(*sqlparser.CreateTable)
+			require.True(t, ok)
+			table, err := schemadiff.NewCreateTableEntity(createTable)
+			require.NoError(t, err)
+
+			msgs := tc.rule.LintTable(&Env{VSchema: vschema}, table)
+			assert.Equal(t, tc.msgs, msgs)
+		})
+	}
+}
+
+func TestChangeRules(t *testing.T) {
+	schema, err := schemadiff.NewSchemaFromQueries([]string{
+		"create table t1 (id int primary key, i int, u int unsigned, b bigint, d decimal(10,2), f float, db double, c char(10), vc varchar(64), txt text, vb varbinary(16), e enum('a','b'), dt datetime(3), j json)",
+		"create table t2 (id int primary key, customer_id int, name varchar(64), key customer_idx (customer_id))",
+		"create table t3 (id int primary key, name varchar(64)) charset latin1",
+		"create view v1 as select t.id, t.name as customer_name from t2 as t",
+		"create view v2 as select * from t3",
+		"create view v3 as select t1.id from t1 join t2 on t1.id = t2.customer_id",
+	})
+	require.NoError(t, err)
+	vschema := &vschemapb.Keyspace{
+		Sharded: true,
+		Tables: map[string]*vschemapb.Table{
+			"t2": {ColumnVindexes: []*vschemapb.ColumnVindex{{Name: "hash", Column: "customer_id"}}},
+		},
+	}
+
+	tt := []struct {
+		name  string
+		rule  ChangeRule
+		alter string
+		msgs  []string
+	}{
+		{
+			name:  "widening changes",
+			rule:  &unsafeColumnTypeChangeRule{},
+			alter: "alter table t1 modify i bigint, modify d decimal(12,3), modify f double, modify vc varchar(128), modify c text, modify e enum('a','b','c'), modify dt datetime(6)",
+		},
+		{
+			name:  "integer narrowing",
+			rule:  &unsafeColumnTypeChangeRule{},
+			alter: "alter table t1 modify b int, change column i i2 smallint",
+			msgs: []string{
+				"column b: type narrows from bigint to int",
+				"column i: type narrows from int to smallint",
+			},
+		},
+		{
+			name:  "signedness",
+			rule:  &unsafeColumnTypeChangeRule{},
+			alter: "alter table t1 modify u int, modify i int unsigned",
+			msgs: []string{
+				"column u: signedness changes",
+				"column i: signedness changes",
+			},
+		},
+		{
+			name:  "decimal and floating point narrowing",
+			rule:  &unsafeColumnTypeChangeRule{},
+			alter: "alter table t1 modify d decimal(10,1), modify db float",
+			msgs: []string{
+				"column d: precision narrows from decimal(10,2) to decimal(10,1)",
+				"column db: type narrows from double to float",
+			},
+		},
+		{
+			name:  "string narrowing",
+			rule:  &unsafeColumnTypeChangeRule{},
+			alter: "alter table t1 modify vc varchar(32), modify txt tinytext, modify vb binary(8)",
+			msgs: []string{
+				"column vc: length narrows from 64 to 32",
+				"column txt: length narrows from 65535 to 255",
+				"column vb: length narrows from 16 to 8",
+			},
+		},
+		{
+			name:  "charset change",
+			rule:  &unsafeColumnTypeChangeRule{},
+			alter: "alter table t3 modify name varchar(64) charset utf8mb4",
+			msgs:  []string{"column name: character set changes from latin1 to utf8mb4"},
+		},
+		{
+			name:  "enum values removed or reordered",
+			rule:  &unsafeColumnTypeChangeRule{},
+			alter: "alter table t1 modify e enum('b','a')",
+			msgs:  []string{"column e: enum values are removed or reordered"},
+		},
+		{
+			name:  "type family change",
+			rule:  &unsafeColumnTypeChangeRule{},
+			alter: "alter table t1 modify vc int, modify j text, modify dt date",
+			msgs: []string{
+				"column vc: type changes from varchar to int",
+				"column j: type changes from json to text",
+				"column dt: type changes from datetime to date",
+			},
+		},
+		{
+			name:  "unknown table",
+			rule:  &unsafeColumnTypeChangeRule{},
+			alter: "alter table t9 modify b int",
+		},
+		{
+			name:  "drop unreferenced column",
+			rule:  &dropViewReferencedColumnRule{},
+			alter: "alter table t1 drop column b",
+		},
+		{
+			name:  "drop column referenced by alias",
+			rule:  &dropViewReferencedColumnRule{},
+			alter: "alter table t2 drop column name",
+			msgs:  []string{"column name is referenced by view v1"},
+		},
+		{
+			name:  "rename column referenced in join",
+			rule:  &dropViewReferencedColumnRule{},
+			alter: "alter table t2 rename column customer_id to cid",
+			msgs:  []string{"column customer_id is referenced by view v3"},
+		},
+		{
+			name:  "change column keeping name",
+			rule:  &dropViewReferencedColumnRule{},
+			alter: "alter table t2 change column name name varchar(128)",
+		},
+		{
+			name:  "drop column referenced by wildcard",
+			rule:  &dropViewReferencedColumnRule{},
+			alter: "alter table t3 drop column name",
+			msgs:  []string{"column name is referenced by view v2"},
+		},
+		{
+			name:  "drop sharding key index",
+			rule:  &unindexedShardingKeyRule{},
+			alter: "alter table t2 drop key customer_idx",
+			msgs:  []string{"primary vindex hash column(s) customer_id are not the leftmost columns of any index"},
+		},
+		{
+			name:  "replace sharding key index",
+			rule:  &unindexedShardingKeyRule{},
+			alter: "alter table t2 drop key customer_idx, add key customer_id_idx (customer_id, id)",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			stmt, err := sqlparser.ParseStrictDDL(tc.alter)
+			require.NoError(t, err)
+			ddl, ok := stmt.(sqlparser.DDLStatement)
+			require.True(t, ok)
+
+			msgs := tc.rule.LintChange(&Env{VSchema: vschema}, schema, ddl)
+			assert.Equal(t, tc.msgs, msgs)
+		})
+	}
+}
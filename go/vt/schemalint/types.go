@@ -0,0 +1,197 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemalint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+type typeFamily int
+
+const (
+	familyOther typeFamily = iota
+	familyInteger
+	familyDecimal
+	familyFloat
+	familyText
+	familyBinary
+	familyEnum
+	familySet
+	familyTemporal
+)
+
+// integerSizes maps integer types to their storage size in bytes
+var integerSizes = map[string]int{
+	"tinyint":   1,
+	"bool":      1,
+	"boolean":   1,
+	"smallint":  2,
+	"mediumint": 3,
+	"int":       4,
+	"integer":   4,
+	"bigint":    8,
+}
+
+// blobCapacities maps TEXT and BLOB types to their maximum length in characters or bytes
+var blobCapacities = map[string]int64{
+	"tinytext":   255,
+	"text":       65535,
+	"mediumtext": 16777215,
+	"longtext":   4294967295,
+	"tinyblob":   255,
+	"blob":       65535,
+	"mediumblob": 16777215,
+	"longblob":   4294967295,
+}
+
+func columnTypeFamily(colType string) typeFamily {
+	colType = strings.ToLower(colType)
+	if _, ok := integerSizes[colType]; ok {
+		return familyInteger
+	}
+	switch colType {
+	case "decimal", "numeric", "dec", "fixed":
+		return familyDecimal
+	case "float", "double", "real", "double precision":
+		return familyFloat
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext":
+		return familyText
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+		return familyBinary
+	case "enum":
+		return familyEnum
+	case "set":
+		return familySet
+	case "date", "time", "datetime", "timestamp", "year":
+		return familyTemporal
+	}
+	return familyOther
+}
+
+// charsetName maps charset aliases to the actual charset name.
+func charsetName(charset string) string {
+	charset = strings.ToLower(charset)
+	if charset == "utf8" {
+		return "utf8mb3"
+	}
+	return charset
+}
+
+func literalInt(lit *sqlparser.Literal, defaultValue int64) int64 {
+	if lit == nil {
+		return defaultValue
+	}
+	i, err := strconv.ParseInt(lit.Val, 10, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return i
+}
+
+// capacity returns the maximum length of a string column
+func capacity(colType *sqlparser.ColumnType) int64 {
+	t := strings.ToLower(colType.Type)
+	if c, ok := blobCapacities[t]; ok {
+		return c
+	}
+	// CHAR and BINARY default to a length of 1
+	return literalInt(colType.Length, 1)
+}
+
+// isDoublePrecision returns true for floating point types stored in 8 bytes
+func isDoublePrecision(colType *sqlparser.ColumnType) bool {
+	switch strings.ToLower(colType.Type) {
+	case "double", "real", "double precision":
+		return true
+	}
+	// FLOAT(p) with 25 <= p <= 53 is a DOUBLE
+	return colType.Scale == nil && literalInt(colType.Length, 0) > 24
+}
+
+// unsafeTypeChange returns a description of why changing a column from one type to another may lose or
+// alter data, or an empty string if the change is safe. tableCharset is the default character set of
+// the table, used when a string column has no explicit character set.
+func unsafeTypeChange(from, to *sqlparser.ColumnType, tableCharset string) string {
+	fromFamily, toFamily := columnTypeFamily(from.Type), columnTypeFamily(to.Type)
+	fromType, toType := strings.ToLower(from.Type), strings.ToLower(to.Type)
+	if fromFamily != toFamily || fromFamily == familyOther || fromFamily == familyTemporal {
+		if fromType != toType {
+			return fmt.Sprintf("type changes from %s to %s", fromType, toType)
+		}
+	}
+	switch fromFamily {
+	case familyInteger:
+		if integerSizes[toType] < integerSizes[fromType] {
+			return fmt.Sprintf("type narrows from %s to %s", fromType, toType)
+		}
+		if from.Unsigned != to.Unsigned {
+			return "signedness changes"
+		}
+	case familyDecimal:
+		fromPrecision, fromScale := literalInt(from.Length, 10), literalInt(from.Scale, 0)
+		toPrecision, toScale := literalInt(to.Length, 10), literalInt(to.Scale, 0)
+		if toScale < fromScale || toPrecision-toScale < fromPrecision-fromScale {
+			return fmt.Sprintf("precision narrows from decimal(%d,%d) to decimal(%d,%d)", fromPrecision, fromScale, toPrecision, toScale)
+		}
+		// unlike integers, decimals keep their range when dropping UNSIGNED
+		if !from.Unsigned && to.Unsigned {
+			return "signedness changes"
+		}
+	case familyFloat:
+		if isDoublePrecision(from) && !isDoublePrecision(to) {
+			return fmt.Sprintf("type narrows from %s to %s", fromType, toType)
+		}
+		if !from.Unsigned && to.Unsigned {
+			return "signedness changes"
+		}
+	case familyText, familyBinary:
+		if capacity(to) < capacity(from) {
+			return fmt.Sprintf("length narrows from %d to %d", capacity(from), capacity(to))
+		}
+		if fromFamily == familyText {
+			fromCharset, toCharset := from.Charset.Name, to.Charset.Name
+			if fromCharset == "" {
+				fromCharset = tableCharset
+			}
+			if toCharset == "" {
+				toCharset = tableCharset
+			}
+			if charsetName(fromCharset) != charsetName(toCharset) {
+				return fmt.Sprintf("character set changes from %s to %s", charsetName(fromCharset), charsetName(toCharset))
+			}
+		}
+	case familyEnum, familySet:
+		if len(to.EnumValues) < len(from.EnumValues) {
+			return fmt.Sprintf("%s values are removed", fromType)
+		}
+		for i, value := range from.EnumValues {
+			if to.EnumValues[i] != value {
+				return fmt.Sprintf("%s values are removed or reordered", fromType)
+			}
+		}
+	case familyTemporal:
+		// fractional seconds precision
+		if literalInt(to.Length, 0) < literalInt(from.Length, 0) {
+			return fmt.Sprintf("fractional seconds precision narrows from %d to %d", literalInt(from.Length, 0), literalInt(to.Length, 0))
+		}
+	}
+	return ""
+}
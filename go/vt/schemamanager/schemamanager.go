@@ -24,6 +24,8 @@ import (
 	"context"
 
 	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/schemalint"
+
 	querypb "vitess.io/vitess/go/vt/proto/query"
 )
 
@@ -75,6 +77,8 @@ type ExecuteResult struct {
 	UUIDs          []string
 	ExecutorErr    string
 	TotalTimeSpent time.Duration
+	// LintFindings are the non-fatal findings of the schema linter, if one was set.
+	LintFindings schemalint.Findings
 }
 
 // ShardWithError contains information why a shard failed to execute given sql
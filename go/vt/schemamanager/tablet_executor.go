@@ -28,6 +28,7 @@ import (
 	"vitess.io/vitess/go/timer"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/schemalint"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vtctl/schematools"
@@ -53,6 +54,8 @@ type TabletExecutor struct {
 	ddlStrategySetting  *schema.DDLStrategySetting
 	uuids               []string
 	batchSize           int64
+	linter              *schemalint.Linter
+	lintFindings        schemalint.Findings
 }
 
 // NewTabletExecutor creates a new TabletExecutor instance
@@ -94,6 +97,12 @@ func (exec *TabletExecutor) SetUUIDList(uuids []string) error {
 	return nil
 }
 
+// SetLinter sets a schema linter to run against the sql statements during validation. Validation fails
+// if the linter reports any errors.
+func (exec *TabletExecutor) SetLinter(linter *schemalint.Linter) {
+	exec.linter = linter
+}
+
 // hasProvidedUUIDs returns true when UUIDs were provided
 func (exec *TabletExecutor) hasProvidedUUIDs() bool {
 	return len(exec.uuids) != 0
@@ -140,10 +149,50 @@ func (exec *TabletExecutor) Validate(ctx context.Context, sqls []string) error {
 	if err := exec.parseDDLs(sqls); err != nil {
 		return err
 	}
+	if exec.linter != nil {
+		if err := exec.lint(ctx, sqls); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+// lint runs the linter against the sql statements, in the context of the current schema of the first
+// shard's primary and the keyspace's VSchema.
+func (exec *TabletExecutor) lint(ctx context.Context, sqls []string) error {
+	sd, err := exec.tmc.GetSchema(ctx, exec.tablets[0], &tabletmanagerdatapb.GetSchemaRequest{IncludeViews: true, TableSchemaOnly: true})
+	if err != nil {
+		return vterrors.Wrapf(err, "unable to get schema for linting from tablet %v", exec.tablets[0].Alias)
+	}
+	currentSchema, err := schemalint.NewSchemaFromDefinition(sd)
+	if err != nil {
+		return vterrors.Wrapf(err, "unable to load schema for linting")
+	}
+	env := &schemalint.Env{Keyspace: exec.keyspace}
+	if env.VSchema, err = exec.ts.GetVSchema(ctx, exec.keyspace); err != nil && !topo.IsErrType(err, topo.NoNode) {
+		return vterrors.Wrapf(err, "unable to get vschema for linting")
+	}
+
+	stmts := make([]sqlparser.Statement, 0, len(sqls))
+	for _, sql := range sqls {
+		stmt, err := sqlparser.Parse(sql)
+		if err != nil {
+			return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "failed to parse sql: %s, got error: %v", sql, err)
+		}
+		stmts = append(stmts, stmt)
+	}
+	findings, err := exec.linter.LintStatements(env, currentSchema, stmts)
+	if err != nil {
+		return err
+	}
+	for _, finding := range findings.Warnings() {
+		exec.logger.Warningf("%s", finding)
+	}
+	exec.lintFindings = findings.Warnings()
+	return findings.Err()
+}
+
 func (exec *TabletExecutor) parseDDLs(sqls []string) error {
 	for _, sql := range sqls {
 		stmt, err := sqlparser.Parse(sql)
@@ -282,7 +331,7 @@ func allSQLsAreCreateQueries(sqls []string) (bool, error) {
 
 // Execute applies schema changes
 func (exec *TabletExecutor) Execute(ctx context.Context, sqls []string) *ExecuteResult {
-	execResult := ExecuteResult{}
+	execResult := ExecuteResult{LintFindings: exec.lintFindings}
 
 	// errorExecResult is a utility function that populates the execResult with the given error, and returns it. Used to quickly bail out of
 	// this function.
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
@@ -32,6 +33,7 @@ import (
 	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/schemalint"
 	"vitess.io/vitess/go/vt/sqlparser"
 )
 
@@ -192,6 +194,50 @@ func TestTabletExecutorDML(t *testing.T) {
 	}
 }
 
+func TestTabletExecutorValidateLint(t *testing.T) {
+	fakeTmc := newFakeTabletManagerClient()
+
+	fakeTmc.AddSchemaDefinition("vt_test_keyspace", &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{
+				Name:   "test_table",
+				Schema: "CREATE TABLE `test_table` (`id` int NOT NULL, `name` varchar(64), PRIMARY KEY (`id`)) ENGINE=InnoDB",
+				Type:   tmutils.TableBaseTable,
+			},
+			{
+				Name:   "test_view",
+				Schema: "CREATE VIEW `test_view` AS select `id`, `name` from `test_table`",
+				Type:   tmutils.TableView,
+			},
+		},
+	})
+
+	executor := NewTabletExecutor("TestTabletExecutorValidateLint", newFakeTopo(t), fakeTmc, logutil.NewConsoleLogger(), testWaitReplicasTimeout, 0)
+	linter, err := schemalint.NewLinter("")
+	require.NoError(t, err)
+	executor.SetLinter(linter)
+	ctx := context.Background()
+
+	err = executor.Open(ctx, "test_keyspace")
+	require.NoError(t, err)
+	defer executor.Close()
+
+	err = executor.Validate(ctx, []string{
+		"CREATE TABLE test_table_02 (id int primary key, price float(10,2))",
+		"ALTER TABLE test_table ADD COLUMN created_at datetime",
+	})
+	require.NoError(t, err)
+	require.Len(t, executor.lintFindings, 1)
+	assert.Equal(t, "deprecated-column-type", executor.lintFindings[0].Rule)
+
+	err = executor.Validate(ctx, []string{
+		"CREATE TABLE test_table_02 (id int)",
+		"ALTER TABLE test_table DROP COLUMN name",
+	})
+	assert.ErrorContains(t, err, "table has no PRIMARY KEY")
+	assert.ErrorContains(t, err, "column name is referenced by view test_view")
+}
+
 func TestTabletExecutorExecute(t *testing.T) {
 	executor := newFakeExecutor(t)
 	ctx := context.Background()
@@ -81,7 +81,8 @@ func ASTToStatementType(stmt Statement) StatementType {
 		return StmtSet
 	case *Show:
 		return StmtShow
-	case DDLStatement, DBDDLStatement, *AlterVschema:
+	case DDLStatement, DBDDLStatement, *AlterVschema,
+		*CreateTrigger, *DropTrigger, *CreateRoutine, *DropRoutine, *CreateEvent, *DropEvent:
 		return StmtDDL
 	case *RevertMigration:
 		return StmtRevert
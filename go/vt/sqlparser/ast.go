@@ -555,6 +555,70 @@ type (
 		Address string
 	}
 
+	// CreateTrigger represents a CREATE TRIGGER statement. The trigger body is
+	// not parsed; it is kept as SQL text with whitespace and comments collapsed.
+	CreateTrigger struct {
+		Comments     *ParsedComments
+		Definer      *Definer
+		IfNotExists  bool
+		Name         TableName
+		Timing       string
+		Event        string
+		Table        TableName
+		Order        string
+		OrderTrigger IdentifierCS
+		Body         string
+	}
+
+	// DropTrigger represents a DROP TRIGGER statement.
+	DropTrigger struct {
+		Comments *ParsedComments
+		IfExists bool
+		Name     TableName
+	}
+
+	// RoutineType is an enum for the kinds of stored routines.
+	RoutineType int8
+
+	// CreateRoutine represents a CREATE PROCEDURE or CREATE FUNCTION statement.
+	// Everything following the routine name (parameters, return type,
+	// characteristics and body) is not parsed; it is kept as SQL text with
+	// whitespace and comments collapsed.
+	CreateRoutine struct {
+		Comments    *ParsedComments
+		Type        RoutineType
+		Definer     *Definer
+		IfNotExists bool
+		Name        TableName
+		Definition  string
+	}
+
+	// DropRoutine represents a DROP PROCEDURE or DROP FUNCTION statement.
+	DropRoutine struct {
+		Comments *ParsedComments
+		Type     RoutineType
+		IfExists bool
+		Name     TableName
+	}
+
+	// CreateEvent represents a CREATE EVENT statement. Everything following
+	// the event name (schedule, options and body) is not parsed; it is kept as
+	// SQL text with whitespace and comments collapsed.
+	CreateEvent struct {
+		Comments    *ParsedComments
+		Definer     *Definer
+		IfNotExists bool
+		Name        TableName
+		Definition  string
+	}
+
+	// DropEvent represents a DROP EVENT statement.
+	DropEvent struct {
+		Comments *ParsedComments
+		IfExists bool
+		Name     TableName
+	}
+
 	// DDLAction is an enum for DDL.Action
 	DDLAction int8
 
@@ -752,6 +816,12 @@ func (*ShowThrottledApps) iStatement()   {}
 func (*ShowThrottlerStatus) iStatement() {}
 func (*DropTable) iStatement()           {}
 func (*DropView) iStatement()            {}
+func (*CreateTrigger) iStatement()       {}
+func (*DropTrigger) iStatement()         {}
+func (*CreateRoutine) iStatement()       {}
+func (*DropRoutine) iStatement()         {}
+func (*CreateEvent) iStatement()         {}
+func (*DropEvent) iStatement()           {}
 func (*TruncateTable) iStatement()       {}
 func (*RenameTable) iStatement()         {}
 func (*CallProc) iStatement()            {}
@@ -2873,9 +2943,17 @@ type (
 		SetDistinct(bool)
 	}
 
+	// WindowFunc is implemented by the expressions that can be evaluated over a window:
+	// the dedicated window functions and the aggregation functions accepting an OVER clause
+	WindowFunc interface {
+		Expr
+		GetOverClause() *OverClause
+	}
+
 	Count struct {
-		Args     Exprs
-		Distinct bool
+		Args       Exprs
+		Distinct   bool
+		OverClause *OverClause
 	}
 
 	CountStar struct {
@@ -2906,26 +2984,32 @@ type (
 		// The solution we employed was to add a dummy field `_ bool` to the otherwise empty struct `CountStar`.
 		// This ensures that each instance of `CountStar` is treated as a separate object,
 		// even in the context of out semantic state which uses these objects as map keys.
+
+		OverClause *OverClause
 	}
 
 	Avg struct {
-		Arg      Expr
-		Distinct bool
+		Arg        Expr
+		Distinct   bool
+		OverClause *OverClause
 	}
 
 	Max struct {
-		Arg      Expr
-		Distinct bool
+		Arg        Expr
+		Distinct   bool
+		OverClause *OverClause
 	}
 
 	Min struct {
-		Arg      Expr
-		Distinct bool
+		Arg        Expr
+		Distinct   bool
+		OverClause *OverClause
 	}
 
 	Sum struct {
-		Arg      Expr
-		Distinct bool
+		Arg        Expr
+		Distinct   bool
+		OverClause *OverClause
 	}
 
 	BitAnd struct {
@@ -3389,6 +3473,18 @@ func (avg *Avg) SetDistinct(distinct bool)                   { avg.Distinct = di
 func (count *Count) SetDistinct(distinct bool)               { count.Distinct = distinct }
 func (grpConcat *GroupConcatExpr) SetDistinct(distinct bool) { grpConcat.Distinct = distinct }
 
+func (sum *Sum) GetOverClause() *OverClause                     { return sum.OverClause }
+func (min *Min) GetOverClause() *OverClause                     { return min.OverClause }
+func (max *Max) GetOverClause() *OverClause                     { return max.OverClause }
+func (avg *Avg) GetOverClause() *OverClause                     { return avg.OverClause }
+func (cs *CountStar) GetOverClause() *OverClause                { return cs.OverClause }
+func (count *Count) GetOverClause() *OverClause                 { return count.OverClause }
+func (node *ArgumentLessWindowExpr) GetOverClause() *OverClause { return node.OverClause }
+func (node *FirstOrLastValueExpr) GetOverClause() *OverClause   { return node.OverClause }
+func (node *NtileExpr) GetOverClause() *OverClause              { return node.OverClause }
+func (node *NTHValueExpr) GetOverClause() *OverClause           { return node.OverClause }
+func (node *LagLeadExpr) GetOverClause() *OverClause            { return node.OverClause }
+
 func (*Sum) AggrName() string             { return "sum" }
 func (*Min) AggrName() string             { return "min" }
 func (*Max) AggrName() string             { return "max" }
@@ -127,8 +127,14 @@ func CloneSQLNode(in SQLNode) SQLNode {
 		return CloneRefOfCountStar(in)
 	case *CreateDatabase:
 		return CloneRefOfCreateDatabase(in)
+	case *CreateEvent:
+		return CloneRefOfCreateEvent(in)
+	case *CreateRoutine:
+		return CloneRefOfCreateRoutine(in)
 	case *CreateTable:
 		return CloneRefOfCreateTable(in)
+	case *CreateTrigger:
+		return CloneRefOfCreateTrigger(in)
 	case *CreateView:
 		return CloneRefOfCreateView(in)
 	case *CurTimeFuncExpr:
@@ -147,10 +153,16 @@ func CloneSQLNode(in SQLNode) SQLNode {
 		return CloneRefOfDropColumn(in)
 	case *DropDatabase:
 		return CloneRefOfDropDatabase(in)
+	case *DropEvent:
+		return CloneRefOfDropEvent(in)
 	case *DropKey:
 		return CloneRefOfDropKey(in)
+	case *DropRoutine:
+		return CloneRefOfDropRoutine(in)
 	case *DropTable:
 		return CloneRefOfDropTable(in)
+	case *DropTrigger:
+		return CloneRefOfDropTrigger(in)
 	case *DropView:
 		return CloneRefOfDropView(in)
 	case *ExecuteStmt:
@@ -799,6 +811,7 @@ func CloneRefOfAvg(n *Avg) *Avg {
 	}
 	out := *n
 	out.Arg = CloneExpr(n.Arg)
+	out.OverClause = CloneRefOfOverClause(n.OverClause)
 	return &out
 }
 
@@ -1077,6 +1090,7 @@ func CloneRefOfCount(n *Count) *Count {
 	}
 	out := *n
 	out.Args = CloneExprs(n.Args)
+	out.OverClause = CloneRefOfOverClause(n.OverClause)
 	return &out
 }
 
@@ -1086,6 +1100,7 @@ func CloneRefOfCountStar(n *CountStar) *CountStar {
 		return nil
 	}
 	out := *n
+	out.OverClause = CloneRefOfOverClause(n.OverClause)
 	return &out
 }
 
@@ -1101,6 +1116,30 @@ func CloneRefOfCreateDatabase(n *CreateDatabase) *CreateDatabase {
 	return &out
 }
 
+// CloneRefOfCreateEvent creates a deep clone of the input.
+func CloneRefOfCreateEvent(n *CreateEvent) *CreateEvent {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Comments = CloneRefOfParsedComments(n.Comments)
+	out.Definer = CloneRefOfDefiner(n.Definer)
+	out.Name = CloneTableName(n.Name)
+	return &out
+}
+
+// CloneRefOfCreateRoutine creates a deep clone of the input.
+func CloneRefOfCreateRoutine(n *CreateRoutine) *CreateRoutine {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Comments = CloneRefOfParsedComments(n.Comments)
+	out.Definer = CloneRefOfDefiner(n.Definer)
+	out.Name = CloneTableName(n.Name)
+	return &out
+}
+
 // CloneRefOfCreateTable creates a deep clone of the input.
 func CloneRefOfCreateTable(n *CreateTable) *CreateTable {
 	if n == nil {
@@ -1114,6 +1153,20 @@ func CloneRefOfCreateTable(n *CreateTable) *CreateTable {
 	return &out
 }
 
+// CloneRefOfCreateTrigger creates a deep clone of the input.
+func CloneRefOfCreateTrigger(n *CreateTrigger) *CreateTrigger {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Comments = CloneRefOfParsedComments(n.Comments)
+	out.Definer = CloneRefOfDefiner(n.Definer)
+	out.Name = CloneTableName(n.Name)
+	out.Table = CloneTableName(n.Table)
+	out.OrderTrigger = CloneIdentifierCS(n.OrderTrigger)
+	return &out
+}
+
 // CloneRefOfCreateView creates a deep clone of the input.
 func CloneRefOfCreateView(n *CreateView) *CreateView {
 	if n == nil {
@@ -1215,6 +1268,17 @@ func CloneRefOfDropDatabase(n *DropDatabase) *DropDatabase {
 	return &out
 }
 
+// CloneRefOfDropEvent creates a deep clone of the input.
+func CloneRefOfDropEvent(n *DropEvent) *DropEvent {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Comments = CloneRefOfParsedComments(n.Comments)
+	out.Name = CloneTableName(n.Name)
+	return &out
+}
+
 // CloneRefOfDropKey creates a deep clone of the input.
 func CloneRefOfDropKey(n *DropKey) *DropKey {
 	if n == nil {
@@ -1225,6 +1289,17 @@ func CloneRefOfDropKey(n *DropKey) *DropKey {
 	return &out
 }
 
+// CloneRefOfDropRoutine creates a deep clone of the input.
+func CloneRefOfDropRoutine(n *DropRoutine) *DropRoutine {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Comments = CloneRefOfParsedComments(n.Comments)
+	out.Name = CloneTableName(n.Name)
+	return &out
+}
+
 // CloneRefOfDropTable creates a deep clone of the input.
 func CloneRefOfDropTable(n *DropTable) *DropTable {
 	if n == nil {
@@ -1236,6 +1311,17 @@ func CloneRefOfDropTable(n *DropTable) *DropTable {
 	return &out
 }
 
+// CloneRefOfDropTrigger creates a deep clone of the input.
+func CloneRefOfDropTrigger(n *DropTrigger) *DropTrigger {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Comments = CloneRefOfParsedComments(n.Comments)
+	out.Name = CloneTableName(n.Name)
+	return &out
+}
+
 // CloneRefOfDropView creates a deep clone of the input.
 func CloneRefOfDropView(n *DropView) *DropView {
 	if n == nil {
@@ -2117,6 +2203,7 @@ func CloneRefOfMax(n *Max) *Max {
 	}
 	out := *n
 	out.Arg = CloneExpr(n.Arg)
+	out.OverClause = CloneRefOfOverClause(n.OverClause)
 	return &out
 }
 
@@ -2138,6 +2225,7 @@ func CloneRefOfMin(n *Min) *Min {
 	}
 	out := *n
 	out.Arg = CloneExpr(n.Arg)
+	out.OverClause = CloneRefOfOverClause(n.OverClause)
 	return &out
 }
 
@@ -3002,6 +3090,7 @@ func CloneRefOfSum(n *Sum) *Sum {
 	}
 	out := *n
 	out.Arg = CloneExpr(n.Arg)
+	out.OverClause = CloneRefOfOverClause(n.OverClause)
 	return &out
 }
 
@@ -4134,8 +4223,14 @@ func CloneStatement(in Statement) Statement {
 		return CloneRefOfCommit(in)
 	case *CreateDatabase:
 		return CloneRefOfCreateDatabase(in)
+	case *CreateEvent:
+		return CloneRefOfCreateEvent(in)
+	case *CreateRoutine:
+		return CloneRefOfCreateRoutine(in)
 	case *CreateTable:
 		return CloneRefOfCreateTable(in)
+	case *CreateTrigger:
+		return CloneRefOfCreateTrigger(in)
 	case *CreateView:
 		return CloneRefOfCreateView(in)
 	case *DeallocateStmt:
@@ -4144,8 +4239,14 @@ func CloneStatement(in Statement) Statement {
 		return CloneRefOfDelete(in)
 	case *DropDatabase:
 		return CloneRefOfDropDatabase(in)
+	case *DropEvent:
+		return CloneRefOfDropEvent(in)
+	case *DropRoutine:
+		return CloneRefOfDropRoutine(in)
 	case *DropTable:
 		return CloneRefOfDropTable(in)
+	case *DropTrigger:
+		return CloneRefOfDropTrigger(in)
 	case *DropView:
 		return CloneRefOfDropView(in)
 	case *ExecuteStmt:
@@ -4236,6 +4337,40 @@ func CloneTableExpr(in TableExpr) TableExpr {
 	}
 }
 
+// CloneWindowFunc creates a deep clone of the input.
+func CloneWindowFunc(in WindowFunc) WindowFunc {
+	if in == nil {
+		return nil
+	}
+	switch in := in.(type) {
+	case *ArgumentLessWindowExpr:
+		return CloneRefOfArgumentLessWindowExpr(in)
+	case *Avg:
+		return CloneRefOfAvg(in)
+	case *Count:
+		return CloneRefOfCount(in)
+	case *CountStar:
+		return CloneRefOfCountStar(in)
+	case *FirstOrLastValueExpr:
+		return CloneRefOfFirstOrLastValueExpr(in)
+	case *LagLeadExpr:
+		return CloneRefOfLagLeadExpr(in)
+	case *Max:
+		return CloneRefOfMax(in)
+	case *Min:
+		return CloneRefOfMin(in)
+	case *NTHValueExpr:
+		return CloneRefOfNTHValueExpr(in)
+	case *NtileExpr:
+		return CloneRefOfNtileExpr(in)
+	case *Sum:
+		return CloneRefOfSum(in)
+	default:
+		// this should never happen
+		return nil
+	}
+}
+
 // CloneSliceOfRefOfColumnDefinition creates a deep clone of the input.
 func CloneSliceOfRefOfColumnDefinition(n []*ColumnDefinition) []*ColumnDefinition {
 	if n == nil {
@@ -338,12 +338,30 @@ func (cmp *Comparator) SQLNode(inA, inB SQLNode) bool {
 			return false
 		}
 		return cmp.RefOfCreateDatabase(a, b)
+	case *CreateEvent:
+		b, ok := inB.(*CreateEvent)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfCreateEvent(a, b)
+	case *CreateRoutine:
+		b, ok := inB.(*CreateRoutine)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfCreateRoutine(a, b)
 	case *CreateTable:
 		b, ok := inB.(*CreateTable)
 		if !ok {
 			return false
 		}
 		return cmp.RefOfCreateTable(a, b)
+	case *CreateTrigger:
+		b, ok := inB.(*CreateTrigger)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfCreateTrigger(a, b)
 	case *CreateView:
 		b, ok := inB.(*CreateView)
 		if !ok {
@@ -398,18 +416,36 @@ func (cmp *Comparator) SQLNode(inA, inB SQLNode) bool {
 			return false
 		}
 		return cmp.RefOfDropDatabase(a, b)
+	case *DropEvent:
+		b, ok := inB.(*DropEvent)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfDropEvent(a, b)
 	case *DropKey:
 		b, ok := inB.(*DropKey)
 		if !ok {
 			return false
 		}
 		return cmp.RefOfDropKey(a, b)
+	case *DropRoutine:
+		b, ok := inB.(*DropRoutine)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfDropRoutine(a, b)
 	case *DropTable:
 		b, ok := inB.(*DropTable)
 		if !ok {
 			return false
 		}
 		return cmp.RefOfDropTable(a, b)
+	case *DropTrigger:
+		b, ok := inB.(*DropTrigger)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfDropTrigger(a, b)
 	case *DropView:
 		b, ok := inB.(*DropView)
 		if !ok {
@@ -1911,7 +1947,8 @@ func (cmp *Comparator) RefOfAvg(a, b *Avg) bool {
 		return false
 	}
 	return a.Distinct == b.Distinct &&
-		cmp.Expr(a.Arg, b.Arg)
+		cmp.Expr(a.Arg, b.Arg) &&
+		cmp.RefOfOverClause(a.OverClause, b.OverClause)
 }
 
 // RefOfBegin does deep equals between the two objects.
@@ -2239,7 +2276,8 @@ func (cmp *Comparator) RefOfCount(a, b *Count) bool {
 		return false
 	}
 	return a.Distinct == b.Distinct &&
-		cmp.Exprs(a.Args, b.Args)
+		cmp.Exprs(a.Args, b.Args) &&
+		cmp.RefOfOverClause(a.OverClause, b.OverClause)
 }
 
 // RefOfCountStar does deep equals between the two objects.
@@ -2250,7 +2288,7 @@ func (cmp *Comparator) RefOfCountStar(a, b *CountStar) bool {
 	if a == nil || b == nil {
 		return false
 	}
-	return true
+	return cmp.RefOfOverClause(a.OverClause, b.OverClause)
 }
 
 // RefOfCreateDatabase does deep equals between the two objects.
@@ -2268,6 +2306,37 @@ func (cmp *Comparator) RefOfCreateDatabase(a, b *CreateDatabase) bool {
 		cmp.SliceOfDatabaseOption(a.CreateOptions, b.CreateOptions)
 }
 
+// RefOfCreateEvent does deep equals between the two objects.
+func (cmp *Comparator) RefOfCreateEvent(a, b *CreateEvent) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.IfNotExists == b.IfNotExists &&
+		a.Definition == b.Definition &&
+		cmp.RefOfParsedComments(a.Comments, b.Comments) &&
+		cmp.RefOfDefiner(a.Definer, b.Definer) &&
+		cmp.TableName(a.Name, b.Name)
+}
+
+// RefOfCreateRoutine does deep equals between the two objects.
+func (cmp *Comparator) RefOfCreateRoutine(a, b *CreateRoutine) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.IfNotExists == b.IfNotExists &&
+		a.Definition == b.Definition &&
+		cmp.RefOfParsedComments(a.Comments, b.Comments) &&
+		a.Type == b.Type &&
+		cmp.RefOfDefiner(a.Definer, b.Definer) &&
+		cmp.TableName(a.Name, b.Name)
+}
+
 // RefOfCreateTable does deep equals between the two objects.
 func (cmp *Comparator) RefOfCreateTable(a, b *CreateTable) bool {
 	if a == b {
@@ -2285,6 +2354,26 @@ func (cmp *Comparator) RefOfCreateTable(a, b *CreateTable) bool {
 		cmp.RefOfParsedComments(a.Comments, b.Comments)
 }
 
+// RefOfCreateTrigger does deep equals between the two objects.
+func (cmp *Comparator) RefOfCreateTrigger(a, b *CreateTrigger) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.IfNotExists == b.IfNotExists &&
+		a.Timing == b.Timing &&
+		a.Event == b.Event &&
+		a.Order == b.Order &&
+		a.Body == b.Body &&
+		cmp.RefOfParsedComments(a.Comments, b.Comments) &&
+		cmp.RefOfDefiner(a.Definer, b.Definer) &&
+		cmp.TableName(a.Name, b.Name) &&
+		cmp.TableName(a.Table, b.Table) &&
+		cmp.IdentifierCS(a.OrderTrigger, b.OrderTrigger)
+}
+
 // RefOfCreateView does deep equals between the two objects.
 func (cmp *Comparator) RefOfCreateView(a, b *CreateView) bool {
 	if a == b {
@@ -2406,6 +2495,19 @@ func (cmp *Comparator) RefOfDropDatabase(a, b *DropDatabase) bool {
 		cmp.IdentifierCS(a.DBName, b.DBName)
 }
 
+// RefOfDropEvent does deep equals between the two objects.
+func (cmp *Comparator) RefOfDropEvent(a, b *DropEvent) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.IfExists == b.IfExists &&
+		cmp.RefOfParsedComments(a.Comments, b.Comments) &&
+		cmp.TableName(a.Name, b.Name)
+}
+
 // RefOfDropKey does deep equals between the two objects.
 func (cmp *Comparator) RefOfDropKey(a, b *DropKey) bool {
 	if a == b {
@@ -2418,6 +2520,20 @@ func (cmp *Comparator) RefOfDropKey(a, b *DropKey) bool {
 		cmp.IdentifierCI(a.Name, b.Name)
 }
 
+// RefOfDropRoutine does deep equals between the two objects.
+func (cmp *Comparator) RefOfDropRoutine(a, b *DropRoutine) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.IfExists == b.IfExists &&
+		cmp.RefOfParsedComments(a.Comments, b.Comments) &&
+		a.Type == b.Type &&
+		cmp.TableName(a.Name, b.Name)
+}
+
 // RefOfDropTable does deep equals between the two objects.
 func (cmp *Comparator) RefOfDropTable(a, b *DropTable) bool {
 	if a == b {
@@ -2432,6 +2548,19 @@ func (cmp *Comparator) RefOfDropTable(a, b *DropTable) bool {
 		cmp.RefOfParsedComments(a.Comments, b.Comments)
 }
 
+// RefOfDropTrigger does deep equals between the two objects.
+func (cmp *Comparator) RefOfDropTrigger(a, b *DropTrigger) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.IfExists == b.IfExists &&
+		cmp.RefOfParsedComments(a.Comments, b.Comments) &&
+		cmp.TableName(a.Name, b.Name)
+}
+
 // RefOfDropView does deep equals between the two objects.
 func (cmp *Comparator) RefOfDropView(a, b *DropView) bool {
 	if a == b {
@@ -3441,7 +3570,8 @@ func (cmp *Comparator) RefOfMax(a, b *Max) bool {
 		return false
 	}
 	return a.Distinct == b.Distinct &&
-		cmp.Expr(a.Arg, b.Arg)
+		cmp.Expr(a.Arg, b.Arg) &&
+		cmp.RefOfOverClause(a.OverClause, b.OverClause)
 }
 
 // RefOfMemberOfExpr does deep equals between the two objects.
@@ -3465,7 +3595,8 @@ func (cmp *Comparator) RefOfMin(a, b *Min) bool {
 		return false
 	}
 	return a.Distinct == b.Distinct &&
-		cmp.Expr(a.Arg, b.Arg)
+		cmp.Expr(a.Arg, b.Arg) &&
+		cmp.RefOfOverClause(a.OverClause, b.OverClause)
 }
 
 // RefOfModifyColumn does deep equals between the two objects.
@@ -4457,7 +4588,8 @@ func (cmp *Comparator) RefOfSum(a, b *Sum) bool {
 		return false
 	}
 	return a.Distinct == b.Distinct &&
-		cmp.Expr(a.Arg, b.Arg)
+		cmp.Expr(a.Arg, b.Arg) &&
+		cmp.RefOfOverClause(a.OverClause, b.OverClause)
 }
 
 // TableExprs does deep equals between the two objects.
@@ -6785,12 +6917,30 @@ func (cmp *Comparator) Statement(inA, inB Statement) bool {
 			return false
 		}
 		return cmp.RefOfCreateDatabase(a, b)
+	case *CreateEvent:
+		b, ok := inB.(*CreateEvent)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfCreateEvent(a, b)
+	case *CreateRoutine:
+		b, ok := inB.(*CreateRoutine)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfCreateRoutine(a, b)
 	case *CreateTable:
 		b, ok := inB.(*CreateTable)
 		if !ok {
 			return false
 		}
 		return cmp.RefOfCreateTable(a, b)
+	case *CreateTrigger:
+		b, ok := inB.(*CreateTrigger)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfCreateTrigger(a, b)
 	case *CreateView:
 		b, ok := inB.(*CreateView)
 		if !ok {
@@ -6815,12 +6965,30 @@ func (cmp *Comparator) Statement(inA, inB Statement) bool {
 			return false
 		}
 		return cmp.RefOfDropDatabase(a, b)
+	case *DropEvent:
+		b, ok := inB.(*DropEvent)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfDropEvent(a, b)
+	case *DropRoutine:
+		b, ok := inB.(*DropRoutine)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfDropRoutine(a, b)
 	case *DropTable:
 		b, ok := inB.(*DropTable)
 		if !ok {
 			return false
 		}
 		return cmp.RefOfDropTable(a, b)
+	case *DropTrigger:
+		b, ok := inB.(*DropTrigger)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfDropTrigger(a, b)
 	case *DropView:
 		b, ok := inB.(*DropView)
 		if !ok {
@@ -7058,6 +7226,87 @@ func (cmp *Comparator) TableExpr(inA, inB TableExpr) bool {
 	}
 }
 
+// WindowFunc does deep equals between the two objects.
+func (cmp *Comparator) WindowFunc(inA, inB WindowFunc) bool {
+	if inA == nil && inB == nil {
+		return true
+	}
+	if inA == nil || inB == nil {
+		return false
+	}
+	switch a := inA.(type) {
+	case *ArgumentLessWindowExpr:
+		b, ok := inB.(*ArgumentLessWindowExpr)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfArgumentLessWindowExpr(a, b)
+	case *Avg:
+		b, ok := inB.(*Avg)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfAvg(a, b)
+	case *Count:
+		b, ok := inB.(*Count)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfCount(a, b)
+	case *CountStar:
+		b, ok := inB.(*CountStar)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfCountStar(a, b)
+	case *FirstOrLastValueExpr:
+		b, ok := inB.(*FirstOrLastValueExpr)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfFirstOrLastValueExpr(a, b)
+	case *LagLeadExpr:
+		b, ok := inB.(*LagLeadExpr)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfLagLeadExpr(a, b)
+	case *Max:
+		b, ok := inB.(*Max)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfMax(a, b)
+	case *Min:
+		b, ok := inB.(*Min)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfMin(a, b)
+	case *NTHValueExpr:
+		b, ok := inB.(*NTHValueExpr)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfNTHValueExpr(a, b)
+	case *NtileExpr:
+		b, ok := inB.(*NtileExpr)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfNtileExpr(a, b)
+	case *Sum:
+		b, ok := inB.(*Sum)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfSum(a, b)
+	default:
+		// this should never happen
+		return false
+	}
+}
+
 // SliceOfRefOfColumnDefinition does deep equals between the two objects.
 func (cmp *Comparator) SliceOfRefOfColumnDefinition(a, b []*ColumnDefinition) bool {
 	if len(a) != len(b) {
@@ -2919,6 +2919,116 @@ func (node *DropView) FormatFast(buf *TrackedBuffer) {
 	node.FromTables.FormatFast(buf)
 }
 
+// FormatFast formats the node.
+func (node *CreateTrigger) FormatFast(buf *TrackedBuffer) {
+	buf.WriteString("create ")
+	node.Comments.FormatFast(buf)
+	if node.Definer != nil {
+		buf.WriteString("definer = ")
+		node.Definer.FormatFast(buf)
+		buf.WriteByte(' ')
+	}
+	buf.WriteString("trigger ")
+	if node.IfNotExists {
+		buf.WriteString("if not exists ")
+	}
+	node.Name.FormatFast(buf)
+	buf.WriteByte(' ')
+	buf.WriteString(node.Timing)
+	buf.WriteByte(' ')
+	buf.WriteString(node.Event)
+	buf.WriteString(" on ")
+	node.Table.FormatFast(buf)
+	buf.WriteString(" for each row")
+	if node.Order != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(node.Order)
+		buf.WriteByte(' ')
+		node.OrderTrigger.FormatFast(buf)
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(node.Body)
+}
+
+// FormatFast formats the node.
+func (node *DropTrigger) FormatFast(buf *TrackedBuffer) {
+	exists := ""
+	if node.IfExists {
+		exists = " if exists"
+	}
+	buf.WriteString("drop ")
+	node.Comments.FormatFast(buf)
+	buf.WriteString("trigger")
+	buf.WriteString(exists)
+	buf.WriteByte(' ')
+	node.Name.FormatFast(buf)
+}
+
+// FormatFast formats the node.
+func (node *CreateRoutine) FormatFast(buf *TrackedBuffer) {
+	buf.WriteString("create ")
+	node.Comments.FormatFast(buf)
+	if node.Definer != nil {
+		buf.WriteString("definer = ")
+		node.Definer.FormatFast(buf)
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(node.Type.ToString())
+	buf.WriteByte(' ')
+	if node.IfNotExists {
+		buf.WriteString("if not exists ")
+	}
+	node.Name.FormatFast(buf)
+	buf.WriteByte(' ')
+	buf.WriteString(node.Definition)
+}
+
+// FormatFast formats the node.
+func (node *DropRoutine) FormatFast(buf *TrackedBuffer) {
+	exists := ""
+	if node.IfExists {
+		exists = " if exists"
+	}
+	buf.WriteString("drop ")
+	node.Comments.FormatFast(buf)
+	buf.WriteString(node.Type.ToString())
+	buf.WriteString(exists)
+	buf.WriteByte(' ')
+	node.Name.FormatFast(buf)
+}
+
+// FormatFast formats the node.
+func (node *CreateEvent) FormatFast(buf *TrackedBuffer) {
+	buf.WriteString("create ")
+	node.Comments.FormatFast(buf)
+	if node.Definer != nil {
+		buf.WriteString("definer = ")
+		node.Definer.FormatFast(buf)
+		buf.WriteByte(' ')
+	}
+	buf.WriteString("event ")
+	if node.IfNotExists {
+		buf.WriteString("if not exists ")
+	}
+	node.Name.FormatFast(buf)
+	buf.WriteByte(' ')
+	buf.WriteString(node.Definition)
+}
+
+// FormatFast formats the node.
+func (node *DropEvent) FormatFast(buf *TrackedBuffer) {
+	exists := ""
+	if node.IfExists {
+		exists = " if exists"
+	}
+	buf.WriteString("drop ")
+	node.Comments.FormatFast(buf)
+	buf.WriteString("event")
+	buf.WriteString(exists)
+	buf.WriteByte(' ')
+	node.Name.FormatFast(buf)
+}
+
 // FormatFast formats the AlterTable node.
 func (node *AlterTable) FormatFast(buf *TrackedBuffer) {
 	buf.WriteString("alter ")
@@ -3556,10 +3666,18 @@ func (node *Count) FormatFast(buf *TrackedBuffer) {
 	}
 	node.Args.FormatFast(buf)
 	buf.WriteByte(')')
+	if node.OverClause != nil {
+		buf.WriteByte(' ')
+		node.OverClause.FormatFast(buf)
+	}
 }
 
 func (node *CountStar) FormatFast(buf *TrackedBuffer) {
 	buf.WriteString("count(*)")
+	if node.OverClause != nil {
+		buf.WriteByte(' ')
+		node.OverClause.FormatFast(buf)
+	}
 }
 
 func (node *AnyValue) FormatFast(buf *TrackedBuffer) {
@@ -3575,6 +3693,10 @@ func (node *Avg) FormatFast(buf *TrackedBuffer) {
 	}
 	buf.printExpr(node, node.Arg, true)
 	buf.WriteByte(')')
+	if node.OverClause != nil {
+		buf.WriteByte(' ')
+		node.OverClause.FormatFast(buf)
+	}
 }
 
 func (node *Max) FormatFast(buf *TrackedBuffer) {
@@ -3584,6 +3706,10 @@ func (node *Max) FormatFast(buf *TrackedBuffer) {
 	}
 	buf.printExpr(node, node.Arg, true)
 	buf.WriteByte(')')
+	if node.OverClause != nil {
+		buf.WriteByte(' ')
+		node.OverClause.FormatFast(buf)
+	}
 }
 
 func (node *Min) FormatFast(buf *TrackedBuffer) {
@@ -3593,6 +3719,10 @@ func (node *Min) FormatFast(buf *TrackedBuffer) {
 	}
 	buf.printExpr(node, node.Arg, true)
 	buf.WriteByte(')')
+	if node.OverClause != nil {
+		buf.WriteByte(' ')
+		node.OverClause.FormatFast(buf)
+	}
 }
 
 func (node *Sum) FormatFast(buf *TrackedBuffer) {
@@ -3602,6 +3732,10 @@ func (node *Sum) FormatFast(buf *TrackedBuffer) {
 	}
 	buf.printExpr(node, node.Arg, true)
 	buf.WriteByte(')')
+	if node.OverClause != nil {
+		buf.WriteByte(' ')
+		node.OverClause.FormatFast(buf)
+	}
 }
 
 func (node *BitAnd) FormatFast(buf *TrackedBuffer) {
@@ -2063,6 +2063,10 @@ func ContainsAggregation(e SQLNode) bool {
 			// so we don't need to worry about aggregation in the original
 			return false, nil
 		case AggrFunc:
+			if IsWindowFunc(node) {
+				// aggregations with an OVER clause are evaluated per row, they don't group rows
+				return true, nil
+			}
 			hasAggregates = true
 			return false, io.EOF
 		}
@@ -2072,6 +2076,33 @@ func ContainsAggregation(e SQLNode) bool {
 	return hasAggregates
 }
 
+// IsWindowFunc returns true if the node is evaluated over a window,
+// either a window function or an aggregation with an OVER clause
+func IsWindowFunc(node SQLNode) bool {
+	wf, ok := node.(WindowFunc)
+	return ok && wf.GetOverClause() != nil
+}
+
+// ContainsWindowFunc returns true if the expression contains a window function
+func ContainsWindowFunc(e SQLNode) bool {
+	hasWindowFunc := false
+	_ = Walk(func(node SQLNode) (kontinue bool, err error) {
+		switch node.(type) {
+		case *Offset:
+			return false, nil
+		case *Subquery:
+			// window functions inside subqueries are evaluated by the subquery
+			return false, nil
+		}
+		if IsWindowFunc(node) {
+			hasWindowFunc = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, e)
+	return hasWindowFunc
+}
+
 // GetFirstSelect gets the first select statement
 func GetFirstSelect(selStmt SelectStatement) *Select {
 	if selStmt == nil {
@@ -2422,6 +2453,16 @@ func (ty KillType) ToString() string {
 	}
 }
 
+// ToString returns the type as a string
+func (ty RoutineType) ToString() string {
+	switch ty {
+	case FunctionType:
+		return FunctionTypeStr
+	default:
+		return ProcedureTypeStr
+	}
+}
+
 // Indexes returns true, if the list of columns contains all the elements in the other list.
 // It also returns the indexes of the columns in the list.
 func (cols Columns) Indexes(subSetCols Columns) (bool, []int) {
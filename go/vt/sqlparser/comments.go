@@ -19,6 +19,7 @@ package sqlparser
 import (
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
@@ -56,6 +57,11 @@ const (
 	// DirectivePriority specifies the priority of a workload. It should be an integer between 0 and MaxPriorityValue,
 	// where 0 is the highest priority, and MaxPriorityValue is the lowest one.
 	DirectivePriority = "PRIORITY"
+	// DirectiveResultCache caches the result of a select query in vtgate. It can be set to false to bypass
+	// the result cache for tables that have it enabled in the VSchema.
+	DirectiveResultCache = "RESULT_CACHE"
+	// DirectiveResultCacheTTL sets the time to live of a result cached by vtgate, in milliseconds.
+	DirectiveResultCacheTTL = "RESULT_CACHE_TTL_MS"
 
 	// MaxPriorityValue specifies the maximum value allowed for the priority query directive. Valid priority values are
 	// between zero and MaxPriorityValue.
@@ -403,6 +409,37 @@ func Consolidator(stmt Statement) querypb.ExecuteOptions_Consolidator {
 	return querypb.ExecuteOptions_CONSOLIDATOR_UNSPECIFIED
 }
 
+// ResultCacheDirective returns whether DirectiveResultCache asks for the result of the select statement
+// to be cached, and whether the directive is set at all.
+func ResultCacheDirective(stmt Statement) (cache bool, isSet bool) {
+	var comments *ParsedComments
+	switch stmt := stmt.(type) {
+	case *Select, *Union:
+		comments = stmt.(Commented).GetParsedComments()
+	default:
+		return false, false
+	}
+	directives := comments.Directives()
+	if _, isSet = directives.GetString(DirectiveResultCache, ""); !isSet {
+		return false, false
+	}
+	return directives.IsSet(DirectiveResultCache), true
+}
+
+// ResultCacheTTLDirective returns the DirectiveResultCacheTTL value if set, otherwise returns 0.
+func ResultCacheTTLDirective(stmt Statement) time.Duration {
+	cmt, ok := stmt.(Commented)
+	if !ok {
+		return 0
+	}
+	val, _ := cmt.GetParsedComments().Directives().GetString(DirectiveResultCacheTTL, "0")
+	ttl, err := strconv.Atoi(val)
+	if err != nil || ttl < 0 {
+		return 0
+	}
+	return time.Duration(ttl) * time.Millisecond
+}
+
 // GetWorkloadNameFromStatement gets the workload name from the provided Statement, using workloadLabel as the name of
 // the query directive that specifies it.
 func GetWorkloadNameFromStatement(statement Statement) string {
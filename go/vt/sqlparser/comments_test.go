@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -487,6 +488,35 @@ func TestConsolidator(t *testing.T) {
 	}
 }
 
+func TestResultCacheDirective(t *testing.T) {
+	testCases := []struct {
+		query string
+		cache bool
+		isSet bool
+		ttl   time.Duration
+	}{
+		{"select /*vt+ RESULT_CACHE */ * from users", true, true, 0},
+		{"select /*vt+ RESULT_CACHE=1 RESULT_CACHE_TTL_MS=500 */ * from users", true, true, 500 * time.Millisecond},
+		{"select /*vt+ RESULT_CACHE=false */ * from users", false, true, 0},
+		{"select /*vt+ RESULT_CACHE RESULT_CACHE_TTL_MS=abc */ * from users", true, true, 0},
+		{"select /*vt+ RESULT_CACHE */ id from users union select id from customers", true, true, 0},
+		{"select * from users", false, false, 0},
+		{"update /*vt+ RESULT_CACHE */ users set name=1", false, false, 0},
+		{"delete /*vt+ RESULT_CACHE */ from users", false, false, 0},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.query, func(t *testing.T) {
+			stmt, err := Parse(test.query)
+			require.NoError(t, err)
+			cache, isSet := ResultCacheDirective(stmt)
+			assert.Equal(t, test.cache, cache)
+			assert.Equal(t, test.isSet, isSet)
+			assert.Equal(t, test.ttl, ResultCacheTTLDirective(stmt))
+		})
+	}
+}
+
 func TestGetPriorityFromStatement(t *testing.T) {
 	testCases := []struct {
 		query            string
@@ -469,6 +469,10 @@ const (
 	// KillType strings
 	ConnectionStr = "connection"
 	QueryStr      = "query"
+
+	// RoutineType strings
+	ProcedureTypeStr = "procedure"
+	FunctionTypeStr  = "function"
 )
 
 // Constants for Enum Type - Insert.Action
@@ -1063,6 +1067,12 @@ const (
 	QueryType
 )
 
+// Constant for Enum Type - RoutineType
+const (
+	ProcedureType RoutineType = iota
+	FunctionType
+)
+
 const (
 	IndexTypeDefault IndexType = iota
 	IndexTypePrimary
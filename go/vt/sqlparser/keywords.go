@@ -242,7 +242,7 @@ var keywords = []keyword{
 	{"dumpfile", DUMPFILE},
 	{"duplicate", DUPLICATE},
 	{"dynamic", DYNAMIC},
-	{"each", UNUSED},
+	{"each", EACH},
 	{"else", ELSE},
 	{"elseif", UNUSED},
 	{"empty", EMPTY},
@@ -283,6 +283,7 @@ var keywords = []keyword{
 	{"float8", FLOAT8_TYPE},
 	{"flush", FLUSH},
 	{"following", FOLLOWING},
+	{"follows", FOLLOWS},
 	{"for", FOR},
 	{"force", FORCE},
 	{"foreign", FOREIGN},
@@ -498,6 +499,7 @@ var keywords = []keyword{
 	{"polygon", POLYGON},
 	{"position", POSITION},
 	{"preceding", PRECEDING},
+	{"precedes", PRECEDES},
 	{"precision", UNUSED},
 	{"prepare", PREPARE},
 	{"primary", PRIMARY},
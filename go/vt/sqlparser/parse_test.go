@@ -2093,6 +2093,36 @@ var (
 		output: "drop view a, B, c",
 	}, {
 		input: "drop /*vt+ strategy=online */ view if exists v",
+	}, {
+		input: "create trigger t1_bi before insert on t1 for each row set new.x = 1",
+	}, {
+		input:  "create definer = `root`@`%` trigger if not exists ks.t1_bu after update on t1 for each row follows t1_bi begin if new.x > 1 then set new.y = 2; end if; insert into log values (new.id);   end",
+		output: "create definer = root@`%` trigger if not exists ks.t1_bu after update on t1 for each row follows t1_bi begin if new.x > 1 then set new.y = 2; end if; insert into log values (new.id); end",
+	}, {
+		input: "create trigger t1_bd before delete on t1 for each row precedes t1_bd2 delete from t2 where id = old.id",
+	}, {
+		input:  "create procedure p1(in a int, out b int)\nbegin\n\tdeclare c int default 0;\n\tlbl: loop\n\t\tset c = c + 1;\n\t\tif c > a then leave lbl; end if;\n\tend loop lbl;\n\tselect case when c > 1 then 1 else 0 end into b;\nend",
+		output: "create procedure p1 (in a int, out b int) begin declare c int default 0; lbl: loop set c = c + 1; if c > a then leave lbl; end if; end loop lbl; select case when c > 1 then 1 else 0 end into b; end",
+	}, {
+		input:  "create procedure p2(x int) begin case x when 1 then select 1; else select case x when 2 then 2 else 3 end; end case; end",
+		output: "create procedure p2 (x int) begin case x when 1 then select 1; else select case x when 2 then 2 else 3 end; end case; end",
+	}, {
+		input: "create trigger t1_bi2 before insert on t1 for each row begin case when new.x > 1 then set new.y = 2; end case; end",
+	}, {
+		input: "create event e2 on schedule every 1 day do begin case when 1 then delete from t1; end case; end",
+	}, {
+		input:  "create function f1(a int) returns int deterministic return a * 2",
+		output: "create function f1 (a int) returns int deterministic return a * 2",
+	}, {
+		input: "create /* comment */ event if not exists e1 on schedule every 1 hour do delete from t1 where ts < now()",
+	}, {
+		input: "drop trigger if exists t1_bi",
+	}, {
+		input: "drop procedure p1",
+	}, {
+		input: "drop function if exists ks.f1",
+	}, {
+		input: "drop /* comment */ event e1",
 	}, {
 		input: "drop table a",
 	}, {
@@ -3521,6 +3551,12 @@ var (
 	}, {
 		input:  "SELECT LAG(val, 10) OVER w, LEAD('val', null) OVER w, LEAD(val, 1, ASCII(1)) OVER w FROM numbers",
 		output: "select lag(val, 10) over w, lead('val', null) over w, lead(val, 1, ASCII(1)) over w from numbers",
+	}, {
+		input:  "select rank() over w3 from t window w1 as (partition by a), w2 as (w1 order by b), w3 as (w2)",
+		output: "select rank() over w3 from t window w1 AS ( partition by a), w2 AS ( w1 order by b asc), w3 AS ( w2)",
+	}, {
+		input:  "select rank() over w2 from t window w1 as (partition by a), window w2 as (w1)",
+		output: "select rank() over w2 from t window w1 AS ( partition by a), window w2 AS ( w1)",
 	}, {
 		input:  "SELECT val, ROW_NUMBER() OVER (ORDER BY val) AS 'row_number' FROM numbers WINDOW w AS (ORDER BY val);",
 		output: "select val, row_number() over ( order by val asc) as `row_number` from numbers window w AS ( order by val asc)",
@@ -3542,6 +3578,12 @@ var (
 	}, {
 		input:  "SELECT time, subject, val, FIRST_VALUE(val)  OVER w AS 'first', LAST_VALUE(val) OVER w AS 'last', NTH_VALUE(val, 2) OVER w AS 'second', NTH_VALUE(val, 4) OVER w AS 'fourth' FROM observations WINDOW w AS (PARTITION BY subject ORDER BY time ASC RANGE BETWEEN 10 PRECEDING AND 10 FOLLOWING);",
 		output: "select `time`, subject, val, first_value(val) over w as `first`, last_value(val) over w as `last`, nth_value(val, 2) over w as `second`, nth_value(val, 4) over w as fourth from observations window w AS ( partition by subject order by `time` asc range between 10 preceding and 10 following)",
+	}, {
+		input:  "SELECT subject, SUM(val) OVER (PARTITION BY subject ORDER BY time ROWS BETWEEN 2 PRECEDING AND CURRENT ROW) AS 'moving' FROM observations",
+		output: "select subject, sum(val) over ( partition by subject order by `time` asc rows between 2 preceding and current row) as moving from observations",
+	}, {
+		input:  "SELECT AVG(val) OVER w, MIN(val) OVER w, MAX(val) OVER (), COUNT(val) OVER w, COUNT(*) OVER (PARTITION BY subject) FROM observations WINDOW w AS (ORDER BY time)",
+		output: "select avg(val) over w, min(val) over w, max(val) over (), count(val) over w, count(*) over ( partition by subject) from observations window w AS ( order by `time` asc)",
 	}, {
 		input:  "SELECT ExtractValue('<a><b/></a>', '/a/b')",
 		output: "select extractvalue('<a><b/></a>', '/a/b') from dual",
@@ -3746,6 +3788,27 @@ func TestInvalid(t *testing.T) {
 	}{{
 		input: "select a, b from (select * from tbl) sort by a",
 		err:   "syntax error",
+	}, {
+		input: "create procedure p1() begin select 1",
+		err:   "unterminated BEGIN block in routine body at position 37",
+	}, {
+		input: "create procedure p1() begin select 1; end; select 2",
+		err:   "syntax error at position 50 near 'select'",
+	}, {
+		input: "create or replace function f1() returns int return 1",
+		err:   "syntax error",
+	}, {
+		input: "create trigger t1_bi before insert on t1 for each row",
+		err:   "missing routine body",
+	}, {
+		input: "create procedure p1()",
+		err:   "missing routine body",
+	}, {
+		input: "create procedure p1(a enum('(', ')'))",
+		err:   "missing routine body",
+	}, {
+		input: "create function f1(a int)",
+		err:   "missing routine body",
 	}, {
 		input: "/*!*/",
 		err:   "Query was empty",
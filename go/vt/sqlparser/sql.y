@@ -35,6 +35,19 @@ func skipToEnd(yylex yyLexer) {
   yylex.(*Tokenizer).SkipToEnd = true
 }
 
+// routineBody scans the remainder of the statement as the body of a stored
+// routine, trigger or event. lookahead reports whether the parser has already
+// read the first token of the body, and params whether it starts with the
+// parameter list of a stored routine.
+func routineBody(yylex yyLexer, lookahead, params bool) (string, bool) {
+  body, err := yylex.(*Tokenizer).scanRoutineBody(lookahead, params)
+  if err != nil {
+    yylex.Error(err.Error())
+    return "", false
+  }
+  return body, true
+}
+
 func markBindVariable(yylex yyLexer, bvar string) {
   yylex.(*Tokenizer).BindVars[bvar] = struct{}{}
 }
@@ -101,6 +114,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
   createDatabase  *CreateDatabase
   alterDatabase  *AlterDatabase
   createTable      *CreateTable
+  createTrigger    *CreateTrigger
   tableAndLockType *TableAndLockType
   alterTable       *AlterTable
   tableOption      *TableOption
@@ -297,7 +311,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %token <str> SCHEMA TABLE INDEX VIEW TO IGNORE IF PRIMARY COLUMN SPATIAL FULLTEXT KEY_BLOCK_SIZE CHECK INDEXES
 %token <str> ACTION CASCADE CONSTRAINT FOREIGN NO REFERENCES RESTRICT
 %token <str> SHOW DESCRIBE EXPLAIN DATE ESCAPE REPAIR OPTIMIZE TRUNCATE COALESCE EXCHANGE REBUILD PARTITIONING REMOVE PREPARE EXECUTE
-%token <str> MAXVALUE PARTITION REORGANIZE LESS THAN PROCEDURE TRIGGER
+%token <str> MAXVALUE PARTITION REORGANIZE LESS THAN PROCEDURE TRIGGER EACH FOLLOWS PRECEDES
 %token <str> VINDEX VINDEXES DIRECTORY NAME UPGRADE
 %token <str> STATUS VARIABLES WARNINGS CASCADED DEFINER OPTION SQL UNDEFINED
 %token <str> SEQUENCE MERGE TEMPORARY TEMPTABLE INVOKER SECURITY FIRST AFTER LAST
@@ -411,6 +425,8 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %type <ctes> with_list
 %type <renameTablePairs> rename_list
 %type <createTable> create_table_prefix
+%type <createTrigger> create_trigger_prefix
+%type <str> trigger_time trigger_event routine_body routine_params_body
 %type <alterTable> alter_table_prefix
 %type <alterOption> alter_option alter_commands_modifier lock_index algorithm_index
 %type <alterOptions> alter_options alter_commands_list alter_commands_modifier_list algorithm_lock_opt
@@ -434,7 +450,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %type <framePoint> frame_point
 %type <frameClause> frame_clause frame_clause_opt
 %type <windowSpecification> window_spec
-%type <overClause> over_clause
+%type <overClause> over_clause over_clause_opt
 %type <nullTreatmentType> null_treatment_type
 %type <nullTreatmentClause> null_treatment_clause null_treatment_clause_opt
 %type <fromFirstLastType> from_first_last_type
@@ -442,8 +458,6 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %type <firstOrLastValueExprType> first_or_last_value_expr_type
 %type <lagLeadExprType> lag_lead_expr_type
 %type <windowDefinition> window_definition
-%type <windowDefinitions> window_definition_list
-%type <namedWindow> named_window
 %type <namedWindows> named_windows_list named_windows_list_opt
 %type <insertAction> insert_or_replace
 %type <str> explain_synonyms
@@ -1154,6 +1168,107 @@ create_statement:
     $1.CreateOptions = $2
     $$ = $1
   }
+| create_trigger_prefix routine_body
+  {
+    $1.Body = $2
+    $$ = $1
+  }
+| create_trigger_prefix FOLLOWS table_id routine_body
+  {
+    $1.Order = "follows"
+    $1.OrderTrigger = $3
+    $1.Body = $4
+    $$ = $1
+  }
+| create_trigger_prefix PRECEDES table_id routine_body
+  {
+    $1.Order = "precedes"
+    $1.OrderTrigger = $3
+    $1.Body = $4
+    $$ = $1
+  }
+| CREATE comment_opt replace_opt algorithm_view definer_opt PROCEDURE not_exists_opt table_name routine_params_body
+  {
+    // OR REPLACE and ALGORITHM are only allowed for views; they are part of this rule to share its prefix with
+    // CREATE VIEW.
+    if $3 || $4 != "" {
+      yylex.Error("syntax error")
+      return 1
+    }
+    $$ = &CreateRoutine{Comments: Comments($2).Parsed(), Type: ProcedureType, Definer: $5, IfNotExists: $7, Name: $8, Definition: $9}
+  }
+| CREATE comment_opt replace_opt algorithm_view definer_opt FUNCTION not_exists_opt table_name routine_params_body
+  {
+    if $3 || $4 != "" {
+      yylex.Error("syntax error")
+      return 1
+    }
+    $$ = &CreateRoutine{Comments: Comments($2).Parsed(), Type: FunctionType, Definer: $5, IfNotExists: $7, Name: $8, Definition: $9}
+  }
+| CREATE comment_opt replace_opt algorithm_view definer_opt EVENT not_exists_opt table_name routine_body
+  {
+    if $3 || $4 != "" {
+      yylex.Error("syntax error")
+      return 1
+    }
+    $$ = &CreateEvent{Comments: Comments($2).Parsed(), Definer: $5, IfNotExists: $7, Name: $8, Definition: $9}
+  }
+
+create_trigger_prefix:
+  CREATE comment_opt replace_opt algorithm_view definer_opt TRIGGER not_exists_opt table_name trigger_time trigger_event ON table_name FOR EACH ROW
+  {
+    if $3 || $4 != "" {
+      yylex.Error("syntax error")
+      return 1
+    }
+    $$ = &CreateTrigger{Comments: Comments($2).Parsed(), Definer: $5, IfNotExists: $7, Name: $8, Timing: $9, Event: $10, Table: $12}
+  }
+
+trigger_time:
+  BEFORE
+  {
+    $$ = "before"
+  }
+| AFTER
+  {
+    $$ = "after"
+  }
+
+trigger_event:
+  INSERT
+  {
+    $$ = "insert"
+  }
+| UPDATE
+  {
+    $$ = "update"
+  }
+| DELETE
+  {
+    $$ = "delete"
+  }
+
+// routine_body captures the rest of the statement as raw text. The parser may or may not have read the first token
+// of the body as lookahead when reducing this rule; either way the tokenizer consumes the whole body, and so any
+// lookahead is discarded.
+routine_body:
+  {
+    var ok bool
+    if $$, ok = routineBody(yylex, yyrcvr.char >= 0, false); !ok {
+      return 1
+    }
+    yyrcvr.char, yytoken = -1, -1
+  }
+
+// routine_params_body is a routine_body that starts with the parameter list of a stored procedure or function.
+routine_params_body:
+  {
+    var ok bool
+    if $$, ok = routineBody(yylex, yyrcvr.char >= 0, true); !ok {
+      return 1
+    }
+    yyrcvr.char, yytoken = -1, -1
+  }
 
 replace_opt:
   {
@@ -3952,6 +4067,22 @@ drop_statement:
   {
     $$ = &DropDatabase{Comments: Comments($2).Parsed(), DBName: $5, IfExists: $4}
   }
+| DROP comment_opt TRIGGER exists_opt table_name
+  {
+    $$ = &DropTrigger{Comments: Comments($2).Parsed(), IfExists: $4, Name: $5}
+  }
+| DROP comment_opt PROCEDURE exists_opt table_name
+  {
+    $$ = &DropRoutine{Comments: Comments($2).Parsed(), Type: ProcedureType, IfExists: $4, Name: $5}
+  }
+| DROP comment_opt FUNCTION exists_opt table_name
+  {
+    $$ = &DropRoutine{Comments: Comments($2).Parsed(), Type: FunctionType, IfExists: $4, Name: $5}
+  }
+| DROP comment_opt EVENT exists_opt table_name
+  {
+    $$ = &DropEvent{Comments: Comments($2).Parsed(), IfExists: $4, Name: $5}
+  }
 
 truncate_statement:
   TRUNCATE TABLE table_name
@@ -5629,6 +5760,7 @@ window_partition_clause_opt:
 
 sql_id_opt:
   {
+    $$ = IdentifierCI{}
   }
 | sql_id
   {
@@ -5641,6 +5773,15 @@ sql_id_opt window_partition_clause_opt order_by_opt frame_clause_opt
     $$ = &WindowSpecification{ Name: $1, PartitionClause: $2, OrderClause: $3, FrameClause: $4}
   }
 
+over_clause_opt:
+  {
+    $$ = nil
+  }
+| over_clause
+  {
+    $$ = $1
+  }
+
 over_clause:
   OVER openb window_spec closeb
   {
@@ -5721,16 +5862,6 @@ window_definition:
     $$ = &WindowDefinition{Name:$1, WindowSpec:$4}
   }
 
-window_definition_list:
-  window_definition
-  {
-    $$ = WindowDefinitions{$1}
-  }
-| window_definition_list ',' window_definition
-  {
-    $$ = append($1,$3)
-  }
-
 default_opt:
   /* empty */
   {
@@ -5929,29 +6060,29 @@ UTC_DATE func_paren_opt
   {
     $$ = &CurTimeFuncExpr{Name:NewIdentifierCI("current_time"), Fsp: $2}
   }
-| COUNT openb '*' closeb
+| COUNT openb '*' closeb over_clause_opt
   {
-    $$ = &CountStar{}
+    $$ = &CountStar{OverClause: $5}
   }
-| COUNT openb distinct_opt expression_list closeb
+| COUNT openb distinct_opt expression_list closeb over_clause_opt
   {
-    $$ = &Count{Distinct:$3, Args:$4}
+    $$ = &Count{Distinct:$3, Args:$4, OverClause: $6}
   }
-| MAX openb distinct_opt expression closeb
+| MAX openb distinct_opt expression closeb over_clause_opt
   {
-    $$ = &Max{Distinct:$3, Arg:$4}
+    $$ = &Max{Distinct:$3, Arg:$4, OverClause: $6}
   }
-| MIN openb distinct_opt expression closeb
+| MIN openb distinct_opt expression closeb over_clause_opt
   {
-    $$ = &Min{Distinct:$3, Arg:$4}
+    $$ = &Min{Distinct:$3, Arg:$4, OverClause: $6}
   }
-| SUM openb distinct_opt expression closeb
+| SUM openb distinct_opt expression closeb over_clause_opt
   {
-    $$ = &Sum{Distinct:$3, Arg:$4}
+    $$ = &Sum{Distinct:$3, Arg:$4, OverClause: $6}
   }
-| AVG openb distinct_opt expression closeb
+| AVG openb distinct_opt expression closeb over_clause_opt
   {
-    $$ = &Avg{Distinct:$3, Arg:$4}
+    $$ = &Avg{Distinct:$3, Arg:$4, OverClause: $6}
   }
 | BIT_AND openb expression closeb
   {
@@ -7245,20 +7376,22 @@ having_opt:
     $$ = $2
   }
 
-named_window:
-  WINDOW window_definition_list %prec WINDOW_EXPR
+// named_windows_list accepts the definitions of a WINDOW clause separated by commas, and also a WINDOW keyword
+// repeated after a comma, which starts a new NamedWindow.
+named_windows_list:
+  WINDOW window_definition
   {
-    $$ = &NamedWindow{$2}
+    $$ = NamedWindows{&NamedWindow{WindowDefinitions{$2}}}
   }
-
-named_windows_list:
-  named_window
+| named_windows_list ',' window_definition
   {
-    $$ = NamedWindows{$1}
+    last := $1[len($1)-1]
+    last.Windows = append(last.Windows, $3)
+    $$ = $1
   }
-| named_windows_list ',' named_window
+| named_windows_list ',' WINDOW window_definition
   {
-    $$ = append($1,$3)
+    $$ = append($1, &NamedWindow{WindowDefinitions{$4}})
   }
 
 named_windows_list_opt:
@@ -8180,6 +8313,7 @@ non_reserved_keyword:
 | FIXED
 | FLUSH
 | FOLLOWING
+| FOLLOWS
 | FORMAT
 | FORMAT_BYTES %prec FUNCTION_CALL_NON_KEYWORD
 | FORMAT_PICO_TIME %prec FUNCTION_CALL_NON_KEYWORD
@@ -8316,6 +8450,7 @@ non_reserved_keyword:
 | PERSIST
 | PERSIST_ONLY
 | PLAN
+| PRECEDES
 | PRECEDING
 | PREPARE
 | PRIVILEGE_CHECKS_USER
@@ -39,6 +39,7 @@ type Tokenizer struct {
 	BindVars            map[string]struct{}
 
 	lastToken      string
+	lastTokenPos   int
 	posVarIndex    int
 	partialDDL     Statement
 	multi          bool
@@ -124,6 +125,7 @@ func (tkn *Tokenizer) Scan() (int, string) {
 	}
 
 	tkn.skipBlank()
+	tkn.lastTokenPos = tkn.Pos
 	switch ch := tkn.cur(); {
 	case ch == '@':
 		tokenID := AT_ID
@@ -304,6 +306,124 @@ func (tkn *Tokenizer) skipStatement() int {
 	}
 }
 
+// scanRoutineBody scans the body of a CREATE TRIGGER, PROCEDURE, FUNCTION or
+// EVENT statement up to the end of the statement, and returns its text with
+// whitespace and comments collapsed into single spaces. The body is not
+// parsed; compound statements (BEGIN ... END, IF ... END IF, etc.) are only
+// tracked so that semicolons within them do not end the statement.
+// If fromLastToken is set, the body starts at the last scanned token, which
+// the parser has already consumed as lookahead. If params is set, the body
+// starts with the parameter list of a stored routine, which must be followed
+// by the actual body.
+func (tkn *Tokenizer) scanRoutineBody(fromLastToken, params bool) (string, error) {
+	if tkn.specialComment != nil {
+		return "", fmt.Errorf("unsupported routine body in MySQL specific comment")
+	}
+	if fromLastToken {
+		tkn.Pos = tkn.lastTokenPos
+	}
+
+	var (
+		body strings.Builder
+		end  = tkn.Pos
+		prev string
+		// blocks is the stack of open compound statements. "case" blocks are
+		// tracked separately for CASE expressions, in which THEN and ELSE
+		// are not followed by statements.
+		blocks []string
+		// parenDepth is the nesting depth within the parameter list, and
+		// hasBody is set once a token follows it.
+		parenDepth int
+		hasBody    = !params
+	)
+	for {
+		tkn.skipBlank()
+		start := tkn.Pos
+
+		var token string
+		switch ch := tkn.cur(); {
+		case ch == ';':
+			// Scan treats semicolons as EOF in multi-statement mode, so we
+			// handle them here.
+			if len(blocks) == 0 {
+				return routineBodyString(&body, hasBody)
+			}
+			tkn.skip(1)
+			token = ";"
+		case ch == ':' && (tkn.peek(1) == ' ' || tkn.peek(1) == '\t' || tkn.peek(1) == '\n' || tkn.peek(1) == '\r'):
+			// Labels, e.g. "l1: LOOP"
+			tkn.skip(1)
+			token = ":"
+		default:
+			typ, _ := tkn.Scan()
+			switch {
+			case tkn.specialComment != nil:
+				return "", fmt.Errorf("unsupported MySQL specific comment in routine body")
+			case typ == LEX_ERROR:
+				return "", fmt.Errorf("syntax error in routine body at position %d", start+1)
+			case typ == 0:
+				if len(blocks) > 0 {
+					return "", fmt.Errorf("unterminated %s block in routine body", strings.ToUpper(blocks[len(blocks)-1]))
+				}
+				return routineBodyString(&body, hasBody)
+			case typ == COMMENT:
+				continue
+			}
+			token = strings.ToLower(tkn.buf[start:tkn.Pos])
+		}
+
+		inCaseExpr := len(blocks) > 0 && blocks[len(blocks)-1] == "case expression"
+		statementStart := prev == "" || prev == ";" || prev == ":" || prev == "begin" || prev == "do" ||
+			prev == "loop" || prev == "repeat" || ((prev == "then" || prev == "else") && !inCaseExpr)
+		switch token {
+		case "begin":
+			blocks = append(blocks, token)
+		case "case":
+			if prev == "end" {
+				// END CASE closes the block popped by END.
+				break
+			}
+			if statementStart {
+				blocks = append(blocks, token)
+			} else {
+				blocks = append(blocks, "case expression")
+			}
+		case "if", "loop", "repeat", "while":
+			if statementStart {
+				blocks = append(blocks, token)
+			}
+		case "end":
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+		}
+		if !hasBody {
+			switch {
+			case token == "(" && (body.Len() == 0 || parenDepth > 0):
+				parenDepth++
+			case token == ")" && parenDepth > 0:
+				parenDepth--
+			case parenDepth == 0:
+				hasBody = true
+			}
+		}
+		prev = token
+
+		if body.Len() > 0 && start > end {
+			body.WriteByte(' ')
+		}
+		body.WriteString(tkn.buf[start:tkn.Pos])
+		end = tkn.Pos
+	}
+}
+
+func routineBodyString(body *strings.Builder, hasBody bool) (string, error) {
+	if body.Len() == 0 || !hasBody {
+		return "", fmt.Errorf("missing routine body")
+	}
+	return body.String(), nil
+}
+
 // skipBlank skips the cursor while it finds whitespace
 func (tkn *Tokenizer) skipBlank() {
 	ch := tkn.cur()
@@ -0,0 +1,78 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"path"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	mysqlctlpb "vitess.io/vitess/go/vt/proto/mysqlctl"
+)
+
+// GetBackupRetentionPolicyPath returns the node path of the backup retention
+// policy of a shard, or of a keyspace if shard is empty.
+func GetBackupRetentionPolicyPath(keyspace, shard string) string {
+	if shard == "" {
+		return path.Join(KeyspacesPath, keyspace, BackupRetentionPolicyFile)
+	}
+	return path.Join(KeyspacesPath, keyspace, ShardsPath, shard, BackupRetentionPolicyFile)
+}
+
+// SaveBackupRetentionPolicy creates or replaces the backup retention policy
+// of a shard, or of a keyspace if shard is empty.
+func (ts *Server) SaveBackupRetentionPolicy(ctx context.Context, keyspace, shard string, policy *mysqlctlpb.BackupRetentionPolicy) error {
+	data, err := policy.MarshalVT()
+	if err != nil {
+		return err
+	}
+	_, err = ts.globalCell.Update(ctx, GetBackupRetentionPolicyPath(keyspace, shard), data, nil)
+	return err
+}
+
+// GetBackupRetentionPolicy returns the backup retention policy of a shard,
+// or of a keyspace if shard is empty. It returns a NoNode error if no policy
+// was saved there.
+func (ts *Server) GetBackupRetentionPolicy(ctx context.Context, keyspace, shard string) (*mysqlctlpb.BackupRetentionPolicy, error) {
+	data, _, err := ts.globalCell.Get(ctx, GetBackupRetentionPolicyPath(keyspace, shard))
+	if err != nil {
+		return nil, err
+	}
+	policy := &mysqlctlpb.BackupRetentionPolicy{}
+	if err := policy.UnmarshalVT(data); err != nil {
+		return nil, vterrors.Wrap(err, "bad backup retention policy data")
+	}
+	return policy, nil
+}
+
+// DeleteBackupRetentionPolicy deletes the backup retention policy of a
+// shard, or of a keyspace if shard is empty.
+func (ts *Server) DeleteBackupRetentionPolicy(ctx context.Context, keyspace, shard string) error {
+	return ts.globalCell.Delete(ctx, GetBackupRetentionPolicyPath(keyspace, shard), nil)
+}
+
+// FindBackupRetentionPolicy returns the backup retention policy that applies
+// to a shard: the policy of the shard if there is one, or else the policy of
+// its keyspace. It returns a NoNode error if neither has a policy.
+func (ts *Server) FindBackupRetentionPolicy(ctx context.Context, keyspace, shard string) (*mysqlctlpb.BackupRetentionPolicy, error) {
+	policy, err := ts.GetBackupRetentionPolicy(ctx, keyspace, shard)
+	if !IsErrType(err, NoNode) {
+		return policy, err
+	}
+	return ts.GetBackupRetentionPolicy(ctx, keyspace, "")
+}
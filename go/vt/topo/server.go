@@ -67,29 +67,31 @@ const (
 
 // Filenames for all object types.
 const (
-	CellInfoFile          = "CellInfo"
-	CellsAliasFile        = "CellsAlias"
-	KeyspaceFile          = "Keyspace"
-	ShardFile             = "Shard"
-	VSchemaFile           = "VSchema"
-	ShardReplicationFile  = "ShardReplication"
-	TabletFile            = "Tablet"
-	SrvVSchemaFile        = "SrvVSchema"
-	SrvKeyspaceFile       = "SrvKeyspace"
-	RoutingRulesFile      = "RoutingRules"
-	ExternalClustersFile  = "ExternalClusters"
-	ShardRoutingRulesFile = "ShardRoutingRules"
+	CellInfoFile              = "CellInfo"
+	CellsAliasFile            = "CellsAlias"
+	KeyspaceFile              = "Keyspace"
+	ShardFile                 = "Shard"
+	VSchemaFile               = "VSchema"
+	ShardReplicationFile      = "ShardReplication"
+	TabletFile                = "Tablet"
+	SrvVSchemaFile            = "SrvVSchema"
+	SrvKeyspaceFile           = "SrvKeyspace"
+	RoutingRulesFile          = "RoutingRules"
+	ExternalClustersFile      = "ExternalClusters"
+	ShardRoutingRulesFile     = "ShardRoutingRules"
+	BackupRetentionPolicyFile = "BackupRetentionPolicy"
 )
 
 // Path for all object types.
 const (
-	CellsPath             = "cells"
-	CellsAliasesPath      = "cells_aliases"
-	KeyspacesPath         = "keyspaces"
-	ShardsPath            = "shards"
-	TabletsPath           = "tablets"
-	MetadataPath          = "metadata"
-	ExternalClusterVitess = "vitess"
+	CellsPath                = "cells"
+	CellsAliasesPath         = "cells_aliases"
+	KeyspacesPath            = "keyspaces"
+	ShardsPath               = "shards"
+	TabletsPath              = "tablets"
+	MetadataPath             = "metadata"
+	ExternalClusterVitess    = "vitess"
+	VStreamSubscriptionsPath = "vstream_subscriptions"
 )
 
 // Factory is a factory interface to create Conn objects.
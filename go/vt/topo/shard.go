@@ -390,6 +390,9 @@ func (ts *Server) DeleteShard(ctx context.Context, keyspace, shard string) error
 	if err := ts.globalCell.Delete(ctx, shardPath, nil); err != nil {
 		return err
 	}
+	if err := ts.DeleteBackupRetentionPolicy(ctx, keyspace, shard); err != nil && !IsErrType(err, NoNode) {
+		return err
+	}
 	event.Dispatch(&events.ShardChange{
 		KeyspaceName: keyspace,
 		ShardName:    shard,
@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestVStreamSubscriptions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+
+	names, err := ts.GetVStreamSubscriptionNames(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	sub := &vtctldatapb.VStreamSubscription{
+		Name:   "orders",
+		Filter: &binlogdatapb.Filter{Rules: []*binlogdatapb.Rule{{Match: "/.*"}}},
+		Vgtid:  &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{{Keyspace: "ks", Shard: "0", Gtid: "current"}}},
+	}
+	require.NoError(t, ts.CreateVStreamSubscription(ctx, sub))
+	err = ts.CreateVStreamSubscription(ctx, sub)
+	assert.True(t, topo.IsErrType(err, topo.NodeExists), "unexpected error: %v", err)
+	err = ts.CreateVStreamSubscription(ctx, &vtctldatapb.VStreamSubscription{Name: "a/b"})
+	assert.Error(t, err)
+
+	vgtid := &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{{Keyspace: "ks", Shard: "0", Gtid: "MySQL56/a:1-10"}}}
+	err = ts.UpdateVStreamSubscriptionFields(ctx, "orders", func(s *vtctldatapb.VStreamSubscription) error {
+		s.Vgtid = vgtid
+		return nil
+	})
+	require.NoError(t, err)
+	err = ts.UpdateVStreamSubscriptionFields(ctx, "orders", func(s *vtctldatapb.VStreamSubscription) error {
+		return topo.NewError(topo.NoUpdateNeeded, s.Name)
+	})
+	require.NoError(t, err)
+
+	si, err := ts.GetVStreamSubscription(ctx, "orders")
+	require.NoError(t, err)
+	utils.MustMatch(t, vgtid, si.Vgtid)
+	utils.MustMatch(t, sub.Filter, si.Filter)
+
+	names, err = ts.GetVStreamSubscriptionNames(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders"}, names)
+
+	require.NoError(t, ts.DeleteVStreamSubscription(ctx, "orders"))
+	_, err = ts.GetVStreamSubscription(ctx, "orders")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "unexpected error: %v", err)
+	err = ts.UpdateVStreamSubscriptionFields(ctx, "orders", func(*vtctldatapb.VStreamSubscription) error { return nil })
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "unexpected error: %v", err)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"path"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// VStreamSubscriptionInfo is a meta struct that contains the version of a
+// VStream subscription record, so it can be updated safely.
+type VStreamSubscriptionInfo struct {
+	version Version
+	*vtctldatapb.VStreamSubscription
+}
+
+// GetVStreamSubscriptionPath returns the node path of the named VStream subscription.
+func GetVStreamSubscriptionPath(name string) string {
+	return path.Join(VStreamSubscriptionsPath, name)
+}
+
+// CreateVStreamSubscription creates the topo record of a VStream subscription.
+// It returns a NodeExists error if the subscription already exists.
+func (ts *Server) CreateVStreamSubscription(ctx context.Context, subscription *vtctldatapb.VStreamSubscription) error {
+	if err := validateObjectName(subscription.Name); err != nil {
+		return err
+	}
+	data, err := subscription.MarshalVT()
+	if err != nil {
+		return err
+	}
+	_, err = ts.globalCell.Create(ctx, GetVStreamSubscriptionPath(subscription.Name), data)
+	return err
+}
+
+// GetVStreamSubscription returns the topo record of the named VStream subscription.
+func (ts *Server) GetVStreamSubscription(ctx context.Context, name string) (*VStreamSubscriptionInfo, error) {
+	data, version, err := ts.globalCell.Get(ctx, GetVStreamSubscriptionPath(name))
+	if err != nil {
+		return nil, err
+	}
+	subscription := &vtctldatapb.VStreamSubscription{}
+	if err := subscription.UnmarshalVT(data); err != nil {
+		return nil, vterrors.Wrap(err, "bad vstream subscription data")
+	}
+	return &VStreamSubscriptionInfo{
+		version:             version,
+		VStreamSubscription: subscription,
+	}, nil
+}
+
+// UpdateVStreamSubscriptionFields reads the named VStream subscription,
+// applies the update function and writes it back. It retries if the record
+// was modified concurrently. If the update function returns a NoUpdateNeeded
+// error, nothing is written.
+func (ts *Server) UpdateVStreamSubscriptionFields(ctx context.Context, name string, update func(*vtctldatapb.VStreamSubscription) error) error {
+	for {
+		si, err := ts.GetVStreamSubscription(ctx, name)
+		if err != nil {
+			return err
+		}
+		if err := update(si.VStreamSubscription); err != nil {
+			if IsErrType(err, NoUpdateNeeded) {
+				return nil
+			}
+			return err
+		}
+		data, err := si.VStreamSubscription.MarshalVT()
+		if err != nil {
+			return err
+		}
+		if _, err = ts.globalCell.Update(ctx, GetVStreamSubscriptionPath(name), data, si.version); !IsErrType(err, BadVersion) {
+			return err
+		}
+	}
+}
+
+// DeleteVStreamSubscription deletes the topo record of the named VStream subscription.
+func (ts *Server) DeleteVStreamSubscription(ctx context.Context, name string) error {
+	return ts.globalCell.Delete(ctx, GetVStreamSubscriptionPath(name), nil)
+}
+
+// GetVStreamSubscriptionNames returns the names of all the VStream subscriptions.
+func (ts *Server) GetVStreamSubscriptionNames(ctx context.Context) ([]string, error) {
+	children, err := ts.globalCell.ListDir(ctx, VStreamSubscriptionsPath, false /*full*/)
+	switch {
+	case err == nil:
+		return DirEntriesToStringArray(children), nil
+	case IsErrType(err, NoNode):
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
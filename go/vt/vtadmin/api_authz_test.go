@@ -43,6 +43,148 @@ import (
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
+func TestCancelSchemaMigration(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "SchemaMigration",
+					Actions:  []string{"cancel_schema_migration"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.CancelSchemaMigration(ctx, &vtadminpb.CancelSchemaMigrationRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.CancelSchemaMigrationRequest{
+				Keyspace: "test",
+				Uuid:     "test-migration",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to CancelSchemaMigration", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to CancelSchemaMigration", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.CancelSchemaMigration(ctx, &vtadminpb.CancelSchemaMigrationRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.CancelSchemaMigrationRequest{
+				Keyspace: "test",
+				Uuid:     "test-migration",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to CancelSchemaMigration", actor)
+	})
+}
+
+func TestCompleteSchemaMigration(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "SchemaMigration",
+					Actions:  []string{"complete_schema_migration"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.CompleteSchemaMigration(ctx, &vtadminpb.CompleteSchemaMigrationRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.CompleteSchemaMigrationRequest{
+				Keyspace: "test",
+				Uuid:     "test-migration",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to CompleteSchemaMigration", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to CompleteSchemaMigration", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.CompleteSchemaMigration(ctx, &vtadminpb.CompleteSchemaMigrationRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.CompleteSchemaMigrationRequest{
+				Keyspace: "test",
+				Uuid:     "test-migration",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to CompleteSchemaMigration", actor)
+	})
+}
+
 func TestCreateKeyspace(t *testing.T) {
 	t.Parallel()
 
@@ -1213,6 +1355,101 @@ func TestGetSchema(t *testing.T) {
 	})
 }
 
+func TestGetSchemaMigrations(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "SchemaMigration",
+					Actions:  []string{"get"},
+					Subjects: []string{"user:allowed-all"},
+					Clusters: []string{"*"},
+				},
+				{
+					Resource: "SchemaMigration",
+					Actions:  []string{"get"},
+					Subjects: []string{"user:allowed-other"},
+					Clusters: []string{"other"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "unauthorized"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.GetSchemaMigrations(ctx, &vtadminpb.GetSchemaMigrationsRequest{
+			ClusterRequests: []*vtadminpb.GetSchemaMigrationsRequest_ClusterRequest{
+				{ClusterId: "test", Request: &vtctldatapb.GetSchemaMigrationsRequest{Keyspace: "test"}},
+				{ClusterId: "other", Request: &vtctldatapb.GetSchemaMigrationsRequest{Keyspace: "otherks"}},
+			},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, resp.SchemaMigrations, "actor %+v should not be permitted to GetSchemaMigrations", actor)
+	})
+
+	t.Run("partial access", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed-other"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, _ := api.GetSchemaMigrations(ctx, &vtadminpb.GetSchemaMigrationsRequest{
+			ClusterRequests: []*vtadminpb.GetSchemaMigrationsRequest_ClusterRequest{
+				{ClusterId: "test", Request: &vtctldatapb.GetSchemaMigrationsRequest{Keyspace: "test"}},
+				{ClusterId: "other", Request: &vtctldatapb.GetSchemaMigrationsRequest{Keyspace: "otherks"}},
+			},
+		})
+		assert.NotEmpty(t, resp.SchemaMigrations, "actor %+v should be permitted to GetSchemaMigrations", actor)
+		assert.Len(t, resp.SchemaMigrations, 1, "actor %+v should be permitted to GetSchemaMigrations", actor)
+		assert.Equal(t, "other-migration", resp.SchemaMigrations[0].SchemaMigration.Uuid, "actor %+v should be permitted to GetSchemaMigrations", actor)
+	})
+
+	t.Run("full access", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed-all"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, _ := api.GetSchemaMigrations(ctx, &vtadminpb.GetSchemaMigrationsRequest{
+			ClusterRequests: []*vtadminpb.GetSchemaMigrationsRequest_ClusterRequest{
+				{ClusterId: "test", Request: &vtctldatapb.GetSchemaMigrationsRequest{Keyspace: "test"}},
+				{ClusterId: "other", Request: &vtctldatapb.GetSchemaMigrationsRequest{Keyspace: "otherks"}},
+			},
+		})
+		assert.NotEmpty(t, resp.SchemaMigrations, "actor %+v should be permitted to GetSchemaMigrations", actor)
+		assert.Len(t, resp.SchemaMigrations, 2, "actor %+v should be permitted to GetSchemaMigrations", actor)
+	})
+}
+
 func TestGetSchemas(t *testing.T) {
 	t.Parallel()
 
@@ -2132,6 +2369,77 @@ func TestGetWorkflows(t *testing.T) {
 	})
 }
 
+func TestLaunchSchemaMigration(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "SchemaMigration",
+					Actions:  []string{"launch_schema_migration"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.LaunchSchemaMigration(ctx, &vtadminpb.LaunchSchemaMigrationRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.LaunchSchemaMigrationRequest{
+				Keyspace: "test",
+				Uuid:     "test-migration",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to LaunchSchemaMigration", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to LaunchSchemaMigration", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.LaunchSchemaMigration(ctx, &vtadminpb.LaunchSchemaMigrationRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.LaunchSchemaMigrationRequest{
+				Keyspace: "test",
+				Uuid:     "test-migration",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to LaunchSchemaMigration", actor)
+	})
+}
+
 func TestPingTablet(t *testing.T) {
 	t.Parallel()
 
@@ -2502,7 +2810,7 @@ func TestReloadSchemas(t *testing.T) {
 	})
 }
 
-func TestRunHealthCheck(t *testing.T) {
+func TestRetrySchemaMigration(t *testing.T) {
 	t.Parallel()
 
 	opts := vtadmin.Options{
@@ -2514,8 +2822,8 @@ func TestRunHealthCheck(t *testing.T) {
 				Clusters []string
 			}{
 				{
-					Resource: "Tablet",
-					Actions:  []string{"get"},
+					Resource: "SchemaMigration",
+					Actions:  []string{"retry_schema_migration"},
 					Subjects: []string{"user:allowed"},
 					Clusters: []string{"*"},
 				},
@@ -2541,8 +2849,79 @@ func TestRunHealthCheck(t *testing.T) {
 			ctx = rbac.NewContext(ctx, actor)
 		}
 
-		resp, err := api.RunHealthCheck(ctx, &vtadminpb.RunHealthCheckRequest{
-			Alias: &topodatapb.TabletAlias{
+		resp, err := api.RetrySchemaMigration(ctx, &vtadminpb.RetrySchemaMigrationRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.RetrySchemaMigrationRequest{
+				Keyspace: "test",
+				Uuid:     "test-migration",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to RetrySchemaMigration", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to RetrySchemaMigration", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.RetrySchemaMigration(ctx, &vtadminpb.RetrySchemaMigrationRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.RetrySchemaMigrationRequest{
+				Keyspace: "test",
+				Uuid:     "test-migration",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to RetrySchemaMigration", actor)
+	})
+}
+
+func TestRunHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Tablet",
+					Actions:  []string{"get"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.RunHealthCheck(ctx, &vtadminpb.RunHealthCheckRequest{
+			Alias: &topodatapb.TabletAlias{
 				Cell: "zone1",
 				Uid:  100,
 			},
@@ -2778,6 +3157,73 @@ func TestStartReplication(t *testing.T) {
 	})
 }
 
+func TestStartWorkflow(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Workflow",
+					Actions:  []string{"manage_workflow"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.StartWorkflow(ctx, &vtadminpb.StartWorkflowRequest{
+			ClusterId: "test",
+			Keyspace:  "test",
+			Workflow:  "testworkflow",
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to StartWorkflow", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to StartWorkflow", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.StartWorkflow(ctx, &vtadminpb.StartWorkflowRequest{
+			ClusterId: "test",
+			Keyspace:  "test",
+			Workflow:  "testworkflow",
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to StartWorkflow", actor)
+	})
+}
+
 func TestStopReplication(t *testing.T) {
 	t.Parallel()
 
@@ -2847,6 +3293,73 @@ func TestStopReplication(t *testing.T) {
 	})
 }
 
+func TestStopWorkflow(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Workflow",
+					Actions:  []string{"manage_workflow"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.StopWorkflow(ctx, &vtadminpb.StopWorkflowRequest{
+			ClusterId: "test",
+			Keyspace:  "test",
+			Workflow:  "testworkflow",
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to StopWorkflow", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to StopWorkflow", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.StopWorkflow(ctx, &vtadminpb.StopWorkflowRequest{
+			ClusterId: "test",
+			Keyspace:  "test",
+			Workflow:  "testworkflow",
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to StopWorkflow", actor)
+	})
+}
+
 func TestTabletExternallyPromoted(t *testing.T) {
 	t.Parallel()
 
@@ -2916,6 +3429,217 @@ func TestTabletExternallyPromoted(t *testing.T) {
 	})
 }
 
+func TestThrottleSchemaMigrations(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "SchemaMigration",
+					Actions:  []string{"throttle_schema_migrations"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.ThrottleSchemaMigrations(ctx, &vtadminpb.ThrottleSchemaMigrationsRequest{
+			ClusterId: "test",
+			Keyspace:  "test",
+			Ratio:     0.5,
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to ThrottleSchemaMigrations", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to ThrottleSchemaMigrations", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.ThrottleSchemaMigrations(ctx, &vtadminpb.ThrottleSchemaMigrationsRequest{
+			ClusterId: "test",
+			Keyspace:  "test",
+			Ratio:     0.5,
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to ThrottleSchemaMigrations", actor)
+	})
+}
+
+func TestVDiffCreate(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "VDiff",
+					Actions:  []string{"create"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.VDiffCreate(ctx, &vtadminpb.VDiffCreateRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.VDiffCreateRequest{
+				TargetKeyspace: "test",
+				Workflow:       "testworkflow",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to VDiffCreate", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to VDiffCreate", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.VDiffCreate(ctx, &vtadminpb.VDiffCreateRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.VDiffCreateRequest{
+				TargetKeyspace: "test",
+				Workflow:       "testworkflow",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to VDiffCreate", actor)
+	})
+}
+
+func TestVDiffShow(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "VDiff",
+					Actions:  []string{"get"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.VDiffShow(ctx, &vtadminpb.VDiffShowRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.VDiffShowRequest{
+				TargetKeyspace: "test",
+				Workflow:       "testworkflow",
+				Arg:            "last",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to VDiffShow", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to VDiffShow", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.VDiffShow(ctx, &vtadminpb.VDiffShowRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.VDiffShowRequest{
+				TargetKeyspace: "test",
+				Workflow:       "testworkflow",
+				Arg:            "last",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to VDiffShow", actor)
+	})
+}
+
 func TestVTExplain(t *testing.T) {
 	t.Parallel()
 
@@ -3183,6 +3907,77 @@ func TestValidateVersionKeyspace(t *testing.T) {
 	})
 }
 
+func TestWorkflowSwitchTraffic(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Workflow",
+					Actions:  []string{"switch_workflow_traffic"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.WorkflowSwitchTraffic(ctx, &vtadminpb.WorkflowSwitchTrafficRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.WorkflowSwitchTrafficRequest{
+				Keyspace: "test",
+				Workflow: "testworkflow",
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to WorkflowSwitchTraffic", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to WorkflowSwitchTraffic", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.WorkflowSwitchTraffic(ctx, &vtadminpb.WorkflowSwitchTrafficRequest{
+			ClusterId: "test",
+			Request: &vtctldatapb.WorkflowSwitchTrafficRequest{
+				Keyspace: "test",
+				Workflow: "testworkflow",
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to WorkflowSwitchTraffic", actor)
+	})
+}
+
 func testClusters(t testing.TB) []*cluster.Cluster {
 	configs := []testutil.TestClusterConfig{
 		{
@@ -3191,6 +3986,22 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 				Name: "test",
 			},
 			VtctldClient: &fakevtctldclient.VtctldClient{
+				CancelSchemaMigrationResults: map[string]struct {
+					Response *vtctldatapb.CancelSchemaMigrationResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.CancelSchemaMigrationResponse{},
+					},
+				},
+				CompleteSchemaMigrationResults: map[string]struct {
+					Response *vtctldatapb.CompleteSchemaMigrationResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.CompleteSchemaMigrationResponse{},
+					},
+				},
 				DeleteShardsResults: map[string]error{
 					"test/-": nil,
 				},
@@ -3279,6 +4090,18 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 						},
 					},
 				},
+				GetSchemaMigrationsResults: map[string]struct {
+					Response *vtctldatapb.GetSchemaMigrationsResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.GetSchemaMigrationsResponse{
+							Migrations: []*vtctldatapb.SchemaMigration{
+								{Uuid: "test-migration", Keyspace: "test"},
+							},
+						},
+					},
+				},
 				GetSchemaResults: map[string]struct {
 					Response *vtctldatapb.GetSchemaResponse
 					Error    error
@@ -3348,6 +4171,14 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 							},
 						}},
 				},
+				LaunchSchemaMigrationResults: map[string]struct {
+					Response *vtctldatapb.LaunchSchemaMigrationResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.LaunchSchemaMigrationResponse{},
+					},
+				},
 				PingTabletResults: map[string]error{
 					"zone1-0000000100": nil,
 				},
@@ -3379,6 +4210,14 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 						Response: &vtctldatapb.ReparentTabletResponse{},
 					},
 				},
+				RetrySchemaMigrationResults: map[string]struct {
+					Response *vtctldatapb.RetrySchemaMigrationResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.RetrySchemaMigrationResponse{},
+					},
+				},
 				RunHealthCheckResults: map[string]error{
 					"zone1-0000000100": nil,
 				},
@@ -3407,6 +4246,30 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 						Response: &vtctldatapb.TabletExternallyReparentedResponse{},
 					},
 				},
+				UpdateThrottlerConfigResults: map[string]struct {
+					Response *vtctldatapb.UpdateThrottlerConfigResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.UpdateThrottlerConfigResponse{},
+					},
+				},
+				VDiffCreateResults: map[string]struct {
+					Response *vtctldatapb.VDiffCreateResponse
+					Error    error
+				}{
+					"test/testworkflow": {
+						Response: &vtctldatapb.VDiffCreateResponse{},
+					},
+				},
+				VDiffShowResults: map[string]struct {
+					Response *vtctldatapb.VDiffShowResponse
+					Error    error
+				}{
+					"test/testworkflow": {
+						Response: &vtctldatapb.VDiffShowResponse{},
+					},
+				},
 				ValidateKeyspaceResults: map[string]struct {
 					Response *vtctldatapb.ValidateKeyspaceResponse
 					Error    error
@@ -3431,6 +4294,22 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 						Response: &vtctldatapb.ValidateVersionKeyspaceResponse{},
 					},
 				},
+				WorkflowSwitchTrafficResults: map[string]struct {
+					Response *vtctldatapb.WorkflowSwitchTrafficResponse
+					Error    error
+				}{
+					"test/testworkflow": {
+						Response: &vtctldatapb.WorkflowSwitchTrafficResponse{},
+					},
+				},
+				WorkflowUpdateResults: map[string]struct {
+					Response *vtctldatapb.WorkflowUpdateResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.WorkflowUpdateResponse{},
+					},
+				},
 			},
 			Tablets: []*vtadminpb.Tablet{
 				{
@@ -3516,6 +4395,18 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 						},
 					},
 				},
+				GetSchemaMigrationsResults: map[string]struct {
+					Response *vtctldatapb.GetSchemaMigrationsResponse
+					Error    error
+				}{
+					"otherks": {
+						Response: &vtctldatapb.GetSchemaMigrationsResponse{
+							Migrations: []*vtctldatapb.SchemaMigration{
+								{Uuid: "other-migration", Keyspace: "otherks"},
+							},
+						},
+					},
+				},
 				GetSchemaResults: map[string]struct {
 					Response *vtctldatapb.GetSchemaResponse
 					Error    error
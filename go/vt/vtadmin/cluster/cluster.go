@@ -46,12 +46,23 @@ import (
 	"vitess.io/vitess/go/vt/vtadmin/vtadminproto"
 	"vitess.io/vitess/go/vt/vtadmin/vtctldclient"
 	"vitess.io/vitess/go/vt/vtadmin/vtsql"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
 
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
+const (
+	// defaultSchemaMigrationsThrottleRatio and
+	// defaultSchemaMigrationsThrottleDuration mirror the tablet throttler's
+	// defaults for throttled app rules.
+	defaultSchemaMigrationsThrottleRatio    = 1.0
+	defaultSchemaMigrationsThrottleDuration = time.Hour
+)
+
 // Cluster is the self-contained unit of services required for vtadmin to talk
 // to a vitess cluster. This consists of a discovery service, a database
 // connection, and a vtctl client.
@@ -221,7 +232,14 @@ func (c *Cluster) Close() error {
 	// concurrently, like we do with the proxies).
 	rec.RecordError(c.schemaCache.Close())
 
-	for _, closer := range []io.Closer{c.DB, c.Vtctld} {
+	closers := []io.Closer{c.DB, c.Vtctld}
+	// Some discovery implementations (e.g. k8s) hold open watches that need
+	// to be stopped along with the cluster.
+	if closer, ok := c.Discovery.(io.Closer); ok {
+		closers = append(closers, closer)
+	}
+
+	for _, closer := range closers {
 		wg.Add(1)
 		go func(closer io.Closer) {
 			defer wg.Done()
@@ -351,6 +369,38 @@ func (c *Cluster) parseTablet(rows *sql.Rows) (*vtadminpb.Tablet, error) {
 	return tablet, nil
 }
 
+// CancelSchemaMigration cancels one or all schema migrations in the given
+// keyspace, proxying a CancelSchemaMigrationRequest to a vtctld in this
+// cluster.
+func (c *Cluster) CancelSchemaMigration(ctx context.Context, req *vtctldatapb.CancelSchemaMigrationRequest) (*vtctldatapb.CancelSchemaMigrationResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.CancelSchemaMigration")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+
+	if err := validateSchemaMigrationRequest(span, req); err != nil {
+		return nil, err
+	}
+
+	return c.Vtctld.CancelSchemaMigration(ctx, req)
+}
+
+// CompleteSchemaMigration completes one or all schema migrations in the given
+// keyspace that were started with --postpone-completion, proxying a
+// CompleteSchemaMigrationRequest to a vtctld in this cluster.
+func (c *Cluster) CompleteSchemaMigration(ctx context.Context, req *vtctldatapb.CompleteSchemaMigrationRequest) (*vtctldatapb.CompleteSchemaMigrationResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.CompleteSchemaMigration")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+
+	if err := validateSchemaMigrationRequest(span, req); err != nil {
+		return nil, err
+	}
+
+	return c.Vtctld.CompleteSchemaMigration(ctx, req)
+}
+
 // CreateKeyspace creates a keyspace in the given cluster, proxying a
 // CreateKeyspaceRequest to a vtctld in that cluster.
 func (c *Cluster) CreateKeyspace(ctx context.Context, req *vtctldatapb.CreateKeyspaceRequest) (*vtadminpb.Keyspace, error) {
@@ -1705,6 +1755,50 @@ func (c *Cluster) getTabletsToQueryForSchemas(ctx context.Context, keyspace stri
 	return []*vtadminpb.Tablet{randomServingTablet}, nil
 }
 
+// GetSchemaMigrations returns the schema migrations in the given keyspace
+// matching the request filters.
+func (c *Cluster) GetSchemaMigrations(ctx context.Context, req *vtctldatapb.GetSchemaMigrationsRequest) ([]*vtadminpb.SchemaMigration, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.GetSchemaMigrations")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+
+	if req == nil {
+		return nil, fmt.Errorf("%w: request cannot be nil", errors.ErrInvalidRequest)
+	}
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("uuid", req.Uuid)
+	span.Annotate("migration_context", req.MigrationContext)
+	span.Annotate("status", req.Status.String())
+	span.Annotate("limit", req.Limit)
+	span.Annotate("skip", req.Skip)
+
+	if req.Keyspace == "" {
+		return nil, fmt.Errorf("%w: keyspace name is required", errors.ErrInvalidRequest)
+	}
+
+	if err := c.schemaReadPool.Acquire(ctx); err != nil {
+		return nil, fmt.Errorf("GetSchemaMigrations(%+v) failed to acquire schemaReadPool: %w", req, err)
+	}
+	defer c.schemaReadPool.Release()
+
+	resp, err := c.Vtctld.GetSchemaMigrations(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]*vtadminpb.SchemaMigration, 0, len(resp.Migrations))
+	for _, m := range resp.Migrations {
+		migrations = append(migrations, &vtadminpb.SchemaMigration{
+			Cluster:         c.ToProto(),
+			SchemaMigration: m,
+		})
+	}
+
+	return migrations, nil
+}
+
 // GetShardReplicationPositions returns a ClusterShardReplicationPosition object
 // for each keyspace/shard in the cluster.
 func (c *Cluster) GetShardReplicationPositions(ctx context.Context, req *vtadminpb.GetShardReplicationPositionsRequest) ([]*vtadminpb.ClusterShardReplicationPosition, error) {
@@ -1956,6 +2050,22 @@ func (c *Cluster) GetWorkflows(ctx context.Context, keyspaces []string, opts Get
 	})
 }
 
+// LaunchSchemaMigration launches one or all schema migrations in the given
+// keyspace that were started with --postpone-launch, proxying a
+// LaunchSchemaMigrationRequest to a vtctld in this cluster.
+func (c *Cluster) LaunchSchemaMigration(ctx context.Context, req *vtctldatapb.LaunchSchemaMigrationRequest) (*vtctldatapb.LaunchSchemaMigrationResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.LaunchSchemaMigration")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+
+	if err := validateSchemaMigrationRequest(span, req); err != nil {
+		return nil, err
+	}
+
+	return c.Vtctld.LaunchSchemaMigration(ctx, req)
+}
+
 // PlannedFailoverShard fails over the shard either to a new primary or away
 // from an old primary. Both the current and candidate primaries must be
 // reachable and running.
@@ -2297,6 +2407,22 @@ func (c *Cluster) reloadTabletSchemas(ctx context.Context, req *vtadminpb.Reload
 	return results, nil
 }
 
+// RetrySchemaMigration marks a failed or cancelled schema migration in the
+// given keyspace for retry, proxying a RetrySchemaMigrationRequest to a vtctld
+// in this cluster.
+func (c *Cluster) RetrySchemaMigration(ctx context.Context, req *vtctldatapb.RetrySchemaMigrationRequest) (*vtctldatapb.RetrySchemaMigrationResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.RetrySchemaMigration")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+
+	if err := validateSchemaMigrationRequest(span, req); err != nil {
+		return nil, err
+	}
+
+	return c.Vtctld.RetrySchemaMigration(ctx, req)
+}
+
 // SetWritable toggles the writability of a tablet, setting it to either
 // read-write or read-only.
 func (c *Cluster) SetWritable(ctx context.Context, req *vtctldatapb.SetWritableRequest) error {
@@ -2365,6 +2491,171 @@ func (c *Cluster) ToggleTabletReplication(ctx context.Context, tablet *vtadminpb
 	return err
 }
 
+// ThrottleSchemaMigrations adds (or, if req.Unthrottle is set, expires) a
+// throttler rule for the online DDL app in the given keyspace.
+func (c *Cluster) ThrottleSchemaMigrations(ctx context.Context, req *vtadminpb.ThrottleSchemaMigrationsRequest) (*vtctldatapb.UpdateThrottlerConfigResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.ThrottleSchemaMigrations")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+
+	if req == nil {
+		return nil, fmt.Errorf("%w: request cannot be nil", errors.ErrInvalidRequest)
+	}
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("unthrottle", req.Unthrottle)
+
+	if req.Keyspace == "" {
+		return nil, fmt.Errorf("%w: keyspace name is required", errors.ErrInvalidRequest)
+	}
+
+	rule := &topodatapb.ThrottledAppRule{
+		Name: throttlerapp.OnlineDDLName.String(),
+	}
+
+	if req.Unthrottle {
+		rule.ExpiresAt = protoutil.TimeToProto(time.Now())
+	} else {
+		if req.Ratio < 0 || req.Ratio > 1 {
+			return nil, fmt.Errorf("%w: ratio must be in the range [0, 1], got %v", errors.ErrInvalidRequest, req.Ratio)
+		}
+
+		rule.Ratio = req.Ratio
+		if rule.Ratio == 0 {
+			rule.Ratio = defaultSchemaMigrationsThrottleRatio
+		}
+
+		duration, ok, err := protoutil.DurationFromProto(req.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid duration: %s", errors.ErrInvalidRequest, err)
+		}
+
+		if !ok || duration <= 0 {
+			duration = defaultSchemaMigrationsThrottleDuration
+		}
+
+		rule.ExpiresAt = protoutil.TimeToProto(time.Now().Add(duration))
+
+		span.Annotate("ratio", rule.Ratio)
+		span.Annotate("duration", duration.String())
+	}
+
+	return c.Vtctld.UpdateThrottlerConfig(ctx, &vtctldatapb.UpdateThrottlerConfigRequest{
+		Keyspace:     req.Keyspace,
+		ThrottledApp: rule,
+	})
+}
+
+// ToggleWorkflow either starts or stops the given workflow on all of its
+// target primaries. Only the workflow state is changed; all other workflow
+// settings are left as they are.
+func (c *Cluster) ToggleWorkflow(ctx context.Context, keyspace string, workflow string, start bool) (*vtctldatapb.WorkflowUpdateResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.ToggleWorkflow")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("workflow", workflow)
+	span.Annotate("start", start)
+	span.Annotate("stop", !start)
+
+	if keyspace == "" {
+		return nil, fmt.Errorf("%w: keyspace name is required", errors.ErrInvalidRequest)
+	}
+
+	if workflow == "" {
+		return nil, fmt.Errorf("%w: workflow name is required", errors.ErrInvalidRequest)
+	}
+
+	state := binlogdatapb.VReplicationWorkflowState_Stopped
+	if start {
+		state = binlogdatapb.VReplicationWorkflowState_Running
+	}
+
+	return c.Vtctld.WorkflowUpdate(ctx, &vtctldatapb.WorkflowUpdateRequest{
+		Keyspace: keyspace,
+		TabletRequest: &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
+			Workflow:    workflow,
+			Cells:       textutil.SimulatedNullStringSlice,
+			TabletTypes: []topodatapb.TabletType{topodatapb.TabletType(textutil.SimulatedNullInt)},
+			OnDdl:       binlogdatapb.OnDDLAction(textutil.SimulatedNullInt),
+			State:       state,
+		},
+	})
+}
+
+// VDiffCreate starts a VDiff for a workflow, proxying a VDiffCreateRequest to
+// a vtctld in this cluster.
+func (c *Cluster) VDiffCreate(ctx context.Context, req *vtctldatapb.VDiffCreateRequest) (*vtctldatapb.VDiffCreateResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.VDiffCreate")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+
+	if err := validateVDiffRequest(span, req.GetTargetKeyspace(), req.GetWorkflow()); err != nil {
+		return nil, err
+	}
+
+	span.Annotate("uuid", req.Uuid)
+
+	return c.Vtctld.VDiffCreate(ctx, req)
+}
+
+// VDiffShow returns the status of one or more VDiffs for a workflow, proxying
+// a VDiffShowRequest to a vtctld in this cluster.
+func (c *Cluster) VDiffShow(ctx context.Context, req *vtctldatapb.VDiffShowRequest) (*vtctldatapb.VDiffShowResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.VDiffShow")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+
+	if err := validateVDiffRequest(span, req.GetTargetKeyspace(), req.GetWorkflow()); err != nil {
+		return nil, err
+	}
+
+	span.Annotate("arg", req.Arg)
+
+	if req.Arg == "" {
+		return nil, fmt.Errorf("%w: arg is required (one of 'all', 'last', or a VDiff UUID)", errors.ErrInvalidRequest)
+	}
+
+	if err := c.workflowReadPool.Acquire(ctx); err != nil {
+		return nil, fmt.Errorf("VDiffShow(%+v) failed to acquire workflowReadPool: %w", req, err)
+	}
+	defer c.workflowReadPool.Release()
+
+	return c.Vtctld.VDiffShow(ctx, req)
+}
+
+// WorkflowSwitchTraffic switches traffic for a workflow, proxying a
+// WorkflowSwitchTrafficRequest to a vtctld in this cluster.
+func (c *Cluster) WorkflowSwitchTraffic(ctx context.Context, req *vtctldatapb.WorkflowSwitchTrafficRequest) (*vtctldatapb.WorkflowSwitchTrafficResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.WorkflowSwitchTraffic")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+
+	if req == nil {
+		return nil, fmt.Errorf("%w: request cannot be nil", errors.ErrInvalidRequest)
+	}
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("direction", req.Direction)
+	span.Annotate("dry_run", req.DryRun)
+
+	if req.Keyspace == "" {
+		return nil, fmt.Errorf("%w: keyspace name is required", errors.ErrInvalidRequest)
+	}
+
+	if req.Workflow == "" {
+		return nil, fmt.Errorf("%w: workflow name is required", errors.ErrInvalidRequest)
+	}
+
+	return c.Vtctld.WorkflowSwitchTraffic(ctx, req)
+}
+
 // Debug returns a map of debug information for a cluster.
 func (c *Cluster) Debug() map[string]any {
 	m := map[string]any{
@@ -2436,3 +2727,40 @@ func equalAddresses(list1 []string, list2 []string) bool {
 
 	return true
 }
+
+// schemaMigrationRequest is implemented by the vtctldata requests that act on
+// one (or "all") schema migrations in a keyspace.
+type schemaMigrationRequest interface {
+	GetKeyspace() string
+	GetUuid() string
+}
+
+func validateSchemaMigrationRequest(span trace.Span, req schemaMigrationRequest) error {
+	span.Annotate("keyspace", req.GetKeyspace())
+	span.Annotate("uuid", req.GetUuid())
+
+	if req.GetKeyspace() == "" {
+		return fmt.Errorf("%w: keyspace name is required", errors.ErrInvalidRequest)
+	}
+
+	if req.GetUuid() == "" {
+		return fmt.Errorf("%w: migration uuid (or \"all\") is required", errors.ErrInvalidRequest)
+	}
+
+	return nil
+}
+
+func validateVDiffRequest(span trace.Span, keyspace string, workflow string) error {
+	span.Annotate("target_keyspace", keyspace)
+	span.Annotate("workflow", workflow)
+
+	if keyspace == "" {
+		return fmt.Errorf("%w: target keyspace name is required", errors.ErrInvalidRequest)
+	}
+
+	if workflow == "" {
+		return fmt.Errorf("%w: workflow name is required", errors.ErrInvalidRequest)
+	}
+
+	return nil
+}
@@ -27,11 +27,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 
 	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/sets"
 	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/textutil"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vtadmin/cluster"
 	vtadminerrors "vitess.io/vitess/go/vt/vtadmin/errors"
@@ -39,6 +41,7 @@ import (
 	"vitess.io/vitess/go/vt/vtadmin/vtctldclient/fakevtctldclient"
 	"vitess.io/vitess/go/vt/vtctl/vtctldclient"
 
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -2722,6 +2725,121 @@ func TestGetSchema(t *testing.T) {
 	})
 }
 
+func TestGetSchemaMigrations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		cfg       testutil.TestClusterConfig
+		req       *vtctldatapb.GetSchemaMigrationsRequest
+		expected  []*vtadminpb.SchemaMigration
+		shouldErr bool
+		errIs     error
+	}{
+		{
+			name: "ok",
+			cfg: testutil.TestClusterConfig{
+				Cluster: &vtadminpb.Cluster{
+					Id:   "c1",
+					Name: "cluster1",
+				},
+				VtctldClient: &fakevtctldclient.VtctldClient{
+					GetSchemaMigrationsResults: map[string]struct {
+						Response *vtctldatapb.GetSchemaMigrationsResponse
+						Error    error
+					}{
+						"ks1": {
+							Response: &vtctldatapb.GetSchemaMigrationsResponse{
+								Migrations: []*vtctldatapb.SchemaMigration{
+									{Uuid: "uuid1", Keyspace: "ks1"},
+									{Uuid: "uuid2", Keyspace: "ks1"},
+								},
+							},
+						},
+					},
+				},
+			},
+			req: &vtctldatapb.GetSchemaMigrationsRequest{
+				Keyspace: "ks1",
+			},
+			expected: []*vtadminpb.SchemaMigration{
+				{
+					Cluster: &vtadminpb.Cluster{
+						Id:   "c1",
+						Name: "cluster1",
+					},
+					SchemaMigration: &vtctldatapb.SchemaMigration{Uuid: "uuid1", Keyspace: "ks1"},
+				},
+				{
+					Cluster: &vtadminpb.Cluster{
+						Id:   "c1",
+						Name: "cluster1",
+					},
+					SchemaMigration: &vtctldatapb.SchemaMigration{Uuid: "uuid2", Keyspace: "ks1"},
+				},
+			},
+		},
+		{
+			name: "missing keyspace",
+			cfg: testutil.TestClusterConfig{
+				Cluster: &vtadminpb.Cluster{
+					Id:   "c1",
+					Name: "cluster1",
+				},
+				VtctldClient: &fakevtctldclient.VtctldClient{},
+			},
+			req:       &vtctldatapb.GetSchemaMigrationsRequest{},
+			shouldErr: true,
+			errIs:     vtadminerrors.ErrInvalidRequest,
+		},
+		{
+			name: "vtctld error",
+			cfg: testutil.TestClusterConfig{
+				Cluster: &vtadminpb.Cluster{
+					Id:   "c1",
+					Name: "cluster1",
+				},
+				VtctldClient: &fakevtctldclient.VtctldClient{
+					GetSchemaMigrationsResults: map[string]struct {
+						Response *vtctldatapb.GetSchemaMigrationsResponse
+						Error    error
+					}{
+						"ks1": {
+							Error: assert.AnError,
+						},
+					},
+				},
+			},
+			req: &vtctldatapb.GetSchemaMigrationsRequest{
+				Keyspace: "ks1",
+			},
+			shouldErr: true,
+			errIs:     assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := testutil.BuildCluster(t, tt.cfg)
+			defer c.Close()
+
+			migrations, err := c.GetSchemaMigrations(ctx, tt.req)
+			if tt.shouldErr {
+				assert.ErrorIs(t, err, tt.errIs)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, migrations)
+		})
+	}
+}
+
 func TestGetShardReplicationPositions(t *testing.T) {
 	t.Parallel()
 
@@ -3595,3 +3713,211 @@ func TestToggleTabletReplication(t *testing.T) {
 		})
 	}
 }
+
+// throttlerConfigRecorder records the requests made to UpdateThrottlerConfig
+// so tests can assert on the throttled app rule sent to vtctld.
+type throttlerConfigRecorder struct {
+	*fakevtctldclient.VtctldClient
+	reqs []*vtctldatapb.UpdateThrottlerConfigRequest
+}
+
+func (r *throttlerConfigRecorder) UpdateThrottlerConfig(ctx context.Context, req *vtctldatapb.UpdateThrottlerConfigRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateThrottlerConfigResponse, error) {
+	r.reqs = append(r.reqs, req)
+	return r.VtctldClient.UpdateThrottlerConfig(ctx, req, opts...)
+}
+
+func TestThrottleSchemaMigrations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tests := []struct {
+		name             string
+		req              *vtadminpb.ThrottleSchemaMigrationsRequest
+		expectedRatio    float64
+		expectedDuration time.Duration
+		shouldErr        bool
+	}{
+		{
+			name: "defaults",
+			req: &vtadminpb.ThrottleSchemaMigrationsRequest{
+				Keyspace: "ks1",
+			},
+			expectedRatio:    1.0,
+			expectedDuration: time.Hour,
+		},
+		{
+			name: "ratio and duration",
+			req: &vtadminpb.ThrottleSchemaMigrationsRequest{
+				Keyspace: "ks1",
+				Ratio:    0.25,
+				Duration: protoutil.DurationToProto(10 * time.Minute),
+			},
+			expectedRatio:    0.25,
+			expectedDuration: 10 * time.Minute,
+		},
+		{
+			name: "unthrottle",
+			req: &vtadminpb.ThrottleSchemaMigrationsRequest{
+				Keyspace:   "ks1",
+				Ratio:      0.5,
+				Unthrottle: true,
+			},
+			expectedRatio:    0,
+			expectedDuration: 0,
+		},
+		{
+			name: "invalid ratio",
+			req: &vtadminpb.ThrottleSchemaMigrationsRequest{
+				Keyspace: "ks1",
+				Ratio:    1.5,
+			},
+			shouldErr: true,
+		},
+		{
+			name:      "missing keyspace",
+			req:       &vtadminpb.ThrottleSchemaMigrationsRequest{},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := &throttlerConfigRecorder{
+				VtctldClient: &fakevtctldclient.VtctldClient{
+					UpdateThrottlerConfigResults: map[string]struct {
+						Response *vtctldatapb.UpdateThrottlerConfigResponse
+						Error    error
+					}{
+						"ks1": {
+							Response: &vtctldatapb.UpdateThrottlerConfigResponse{},
+						},
+					},
+				},
+			}
+			c := testutil.BuildCluster(t, testutil.TestClusterConfig{
+				Cluster: &vtadminpb.Cluster{
+					Id:   "c1",
+					Name: "cluster1",
+				},
+				VtctldClient: recorder,
+			})
+			defer c.Close()
+
+			start := time.Now()
+			_, err := c.ThrottleSchemaMigrations(ctx, tt.req)
+			if tt.shouldErr {
+				assert.ErrorIs(t, err, vtadminerrors.ErrInvalidRequest)
+				assert.Empty(t, recorder.reqs, "vtctld should not be called for an invalid request")
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, recorder.reqs, 1)
+
+			rule := recorder.reqs[0].ThrottledApp
+			require.NotNil(t, rule)
+			assert.Equal(t, "ks1", recorder.reqs[0].Keyspace)
+			assert.Equal(t, "online-ddl", rule.Name)
+			assert.Equal(t, tt.expectedRatio, rule.Ratio)
+
+			expiresAt := protoutil.TimeFromProto(rule.ExpiresAt)
+			assert.WithinDuration(t, start.Add(tt.expectedDuration), expiresAt, 5*time.Second)
+		})
+	}
+}
+
+// workflowUpdateRecorder records the requests made to WorkflowUpdate so tests
+// can assert on the tablet request sent to vtctld.
+type workflowUpdateRecorder struct {
+	*fakevtctldclient.VtctldClient
+	reqs []*vtctldatapb.WorkflowUpdateRequest
+}
+
+func (r *workflowUpdateRecorder) WorkflowUpdate(ctx context.Context, req *vtctldatapb.WorkflowUpdateRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowUpdateResponse, error) {
+	r.reqs = append(r.reqs, req)
+	return r.VtctldClient.WorkflowUpdate(ctx, req, opts...)
+}
+
+func TestToggleWorkflow(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tests := []struct {
+		name          string
+		keyspace      string
+		workflow      string
+		start         bool
+		expectedState binlogdatapb.VReplicationWorkflowState
+		shouldErr     bool
+	}{
+		{
+			name:          "start",
+			keyspace:      "ks1",
+			workflow:      "wf1",
+			start:         true,
+			expectedState: binlogdatapb.VReplicationWorkflowState_Running,
+		},
+		{
+			name:          "stop",
+			keyspace:      "ks1",
+			workflow:      "wf1",
+			start:         false,
+			expectedState: binlogdatapb.VReplicationWorkflowState_Stopped,
+		},
+		{
+			name:      "missing workflow",
+			keyspace:  "ks1",
+			start:     true,
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := &workflowUpdateRecorder{
+				VtctldClient: &fakevtctldclient.VtctldClient{
+					WorkflowUpdateResults: map[string]struct {
+						Response *vtctldatapb.WorkflowUpdateResponse
+						Error    error
+					}{
+						"ks1": {
+							Response: &vtctldatapb.WorkflowUpdateResponse{},
+						},
+					},
+				},
+			}
+			c := testutil.BuildCluster(t, testutil.TestClusterConfig{
+				Cluster: &vtadminpb.Cluster{
+					Id:   "c1",
+					Name: "cluster1",
+				},
+				VtctldClient: recorder,
+			})
+			defer c.Close()
+
+			_, err := c.ToggleWorkflow(ctx, tt.keyspace, tt.workflow, tt.start)
+			if tt.shouldErr {
+				assert.ErrorIs(t, err, vtadminerrors.ErrInvalidRequest)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, recorder.reqs, 1)
+
+			treq := recorder.reqs[0].TabletRequest
+			assert.Equal(t, tt.workflow, treq.Workflow)
+			assert.Equal(t, tt.expectedState, treq.State)
+			// Only the state should change; everything else is left as-is.
+			assert.Equal(t, textutil.SimulatedNullStringSlice, treq.Cells)
+			assert.Equal(t, binlogdatapb.OnDDLAction(textutil.SimulatedNullInt), treq.OnDdl)
+		})
+	}
+}
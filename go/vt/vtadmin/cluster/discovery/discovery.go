@@ -127,4 +127,5 @@ func init() { // nolint:gochecknoinits
 	Register("consul", NewConsul)
 	Register("staticfile", NewStaticFile)
 	Register("dynamic", NewDynamic)
+	Register("k8s", NewK8s)
 }
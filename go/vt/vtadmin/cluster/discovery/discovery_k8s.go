@@ -0,0 +1,577 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"vitess.io/vitess/go/textutil"
+	"vitess.io/vitess/go/trace"
+
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+)
+
+// ErrK8sCacheNotSynced is returned when a K8sDiscovery is queried before its
+// pod watches have completed their initial listing, and the caller's context
+// expires while waiting for them to do so.
+var ErrK8sCacheNotSynced = errors.New("kubernetes pod cache not synced")
+
+// K8sDiscovery implements the Discovery interface for Kubernetes. VTGates and
+// Vtctlds are discovered from the pods matching a label selector for each
+// component. Pods are watched, rather than listed on every request, so
+// discovery calls are served from a local cache that is kept up-to-date as
+// pods come and go.
+//
+// Tags passed to the discovery methods are label selector expressions (e.g.
+// "cell=zone1" or "pool in (pool1,pool2)") that further restrict the set of
+// matched pods. For compatibility with the consul implementation, tags of the
+// form "key:value" are treated as "key=value".
+type K8sDiscovery struct {
+	cluster   *vtadminpb.Cluster
+	client    kubernetes.Interface
+	namespace string
+	resync    time.Duration
+
+	/* misc options */
+	readyOnly bool
+
+	/* vtgate options */
+	vtgateSelector                   string
+	vtgateCellLabel                  string
+	vtgatePoolLabel                  string
+	vtgateKeyspacesToWatchAnnotation string
+	vtgateAddrTmpl                   *template.Template
+	vtgateFQDNTmpl                   *template.Template
+
+	/* vtctld options */
+	vtctldSelector string
+	vtctldAddrTmpl *template.Template
+	vtctldFQDNTmpl *template.Template
+
+	startOnce sync.Once
+	closeOnce sync.Once
+	stop      chan struct{}
+
+	vtgates      corelisters.PodLister
+	vtgateSynced cache.InformerSynced
+	vtctlds      corelisters.PodLister
+	vtctldSynced cache.InformerSynced
+}
+
+// NewK8s returns a K8sDiscovery for the given cluster. Args are a slice of
+// command-line flags (e.g. "-key=value") that are parsed by a k8s-specific
+// flag set.
+//
+// If no kubeconfig is given, the in-cluster configuration of the pod VTAdmin
+// is running in is used.
+func NewK8s(cluster *vtadminpb.Cluster, flags *pflag.FlagSet, args []string) (Discovery, error) {
+	kubeconfig := flags.String("kubeconfig", "",
+		"path to a kubeconfig file. If empty, the in-cluster configuration is used.")
+	kubecontext := flags.String("context", "", "kubeconfig context to use. If empty, the current context is used.")
+
+	disco, err := newK8sDiscovery(cluster, nil, flags, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg *rest.Config
+	if *kubeconfig == "" {
+		cfg, err = rest.InClusterConfig()
+	} else {
+		cfg, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: *kubeconfig},
+			&clientcmd.ConfigOverrides{CurrentContext: *kubecontext},
+		).ClientConfig()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubernetes client config: %w", err)
+	}
+
+	disco.client, err = kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	return disco, nil
+}
+
+// newK8sDiscovery parses the k8s discovery flags and returns a K8sDiscovery
+// using the given client. It is split out from NewK8s so tests can provide a
+// fake clientset.
+func newK8sDiscovery(cluster *vtadminpb.Cluster, client kubernetes.Interface, flags *pflag.FlagSet, args []string) (*K8sDiscovery, error) { // nolint:funlen
+	disco := &K8sDiscovery{
+		cluster: cluster,
+		client:  client,
+		stop:    make(chan struct{}),
+	}
+
+	flags.StringVar(&disco.namespace, "namespace", metav1.NamespaceAll,
+		"namespace to watch pods in. If empty, pods in all namespaces are watched.")
+	flags.DurationVar(&disco.resync, "resync-period", time.Minute*10,
+		"how often the pod watches relist their full contents. Set to 0 to disable resyncs.")
+	flags.BoolVar(&disco.readyOnly, "ready-only", true, "whether to include only pods that are ready")
+
+	/* vtgate discovery config options */
+	flags.StringVar(&disco.vtgateSelector, "vtgate-label-selector", "app.kubernetes.io/component=vtgate",
+		"label selector matching vtgate pods")
+	flags.StringVar(&disco.vtgateCellLabel, "vtgate-cell-label", "cell", "pod label to group vtgates by cell")
+	flags.StringVar(&disco.vtgatePoolLabel, "vtgate-pool-label", "pool", "pod label to group vtgates by pool")
+	flags.StringVar(&disco.vtgateKeyspacesToWatchAnnotation, "vtgate-keyspaces-to-watch-annotation", "keyspaces",
+		"pod annotation identifying -keyspaces_to_watch for vtgates")
+
+	vtgateAddrTmplStr := flags.String("vtgate-addr-tmpl", "{{ .Hostname }}",
+		"Go template string to produce a dialable address from a *vtadminpb.VTGate. "+
+			"The pod IP is provided as .Hostname. "+
+			"NOTE: the .FQDN field will never be set in the addr template context.")
+	vtgateFQDNTmplStr := flags.String("vtgate-fqdn-tmpl", "",
+		"Optional Go template string to produce an FQDN to access the vtgate from a browser. "+
+			"E.g. \"{{ .Hostname }}.example.com\".")
+
+	/* vtctld discovery config options */
+	flags.StringVar(&disco.vtctldSelector, "vtctld-label-selector", "app.kubernetes.io/component=vtctld",
+		"label selector matching vtctld pods")
+
+	vtctldAddrTmplStr := flags.String("vtctld-addr-tmpl", "{{ .Hostname }}",
+		"Go template string to produce a dialable address from a *vtadminpb.Vtctld. "+
+			"The pod IP is provided as .Hostname. "+
+			"NOTE: the .FQDN field will never be set in the addr template context.")
+	vtctldFQDNTmplStr := flags.String("vtctld-fqdn-tmpl", "",
+		"Optional Go template string to produce an FQDN to access the vtctld from a browser. "+
+			"E.g. \"{{ .Hostname }}.example.com\".")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	var err error
+
+	/* gates options */
+	if _, err := labels.Parse(disco.vtgateSelector); err != nil {
+		return nil, fmt.Errorf("failed to parse vtgate label selector %s: %w", disco.vtgateSelector, err)
+	}
+
+	if *vtgateFQDNTmplStr != "" {
+		disco.vtgateFQDNTmpl, err = template.New("k8s-vtgate-fqdn-template-" + cluster.Id).Parse(*vtgateFQDNTmplStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse vtgate FQDN template %s: %w", *vtgateFQDNTmplStr, err)
+		}
+	}
+
+	disco.vtgateAddrTmpl, err = template.New("k8s-vtgate-address-template-" + cluster.Id).Parse(*vtgateAddrTmplStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse vtgate host address template %s: %w", *vtgateAddrTmplStr, err)
+	}
+
+	/* vtctld options */
+	if _, err := labels.Parse(disco.vtctldSelector); err != nil {
+		return nil, fmt.Errorf("failed to parse vtctld label selector %s: %w", disco.vtctldSelector, err)
+	}
+
+	if *vtctldFQDNTmplStr != "" {
+		disco.vtctldFQDNTmpl, err = template.New("k8s-vtctld-fqdn-template-" + cluster.Id).Parse(*vtctldFQDNTmplStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse vtctld FQDN template %s: %w", *vtctldFQDNTmplStr, err)
+		}
+	}
+
+	disco.vtctldAddrTmpl, err = template.New("k8s-vtctld-address-template-" + cluster.Id).Parse(*vtctldAddrTmplStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse vtctld host address template %s: %w", *vtctldAddrTmplStr, err)
+	}
+
+	return disco, nil
+}
+
+// start lazily begins watching vtgate and vtctld pods. The watches run until
+// the discovery is closed.
+func (k *K8sDiscovery) start() {
+	k.startOnce.Do(func() {
+		newInformerFactory := func(selector string) informers.SharedInformerFactory {
+			return informers.NewSharedInformerFactoryWithOptions(k.client, k.resync,
+				informers.WithNamespace(k.namespace),
+				informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+					opts.LabelSelector = selector
+				}),
+			)
+		}
+
+		vtgateFactory := newInformerFactory(k.vtgateSelector)
+		vtgatePods := vtgateFactory.Core().V1().Pods()
+		k.vtgates = vtgatePods.Lister()
+		k.vtgateSynced = vtgatePods.Informer().HasSynced
+
+		vtctldFactory := newInformerFactory(k.vtctldSelector)
+		vtctldPods := vtctldFactory.Core().V1().Pods()
+		k.vtctlds = vtctldPods.Lister()
+		k.vtctldSynced = vtctldPods.Informer().HasSynced
+
+		vtgateFactory.Start(k.stop)
+		vtctldFactory.Start(k.stop)
+	})
+}
+
+// Close stops watching pods. It is safe to call multiple times.
+func (k *K8sDiscovery) Close() error {
+	k.closeOnce.Do(func() {
+		close(k.stop)
+	})
+
+	return nil
+}
+
+// DiscoverVTGate is part of the Discovery interface.
+func (k *K8sDiscovery) DiscoverVTGate(ctx context.Context, tags []string) (*vtadminpb.VTGate, error) {
+	span, ctx := trace.NewSpan(ctx, "K8sDiscovery.DiscoverVTGate")
+	defer span.Finish()
+
+	executeFQDNTemplate := true
+
+	return k.discoverVTGate(ctx, tags, executeFQDNTemplate)
+}
+
+// discoverVTGate calls discoverVTGates and then returns a random VTGate from
+// the result. see discoverVTGates for further documentation.
+func (k *K8sDiscovery) discoverVTGate(ctx context.Context, tags []string, executeFQDNTemplate bool) (*vtadminpb.VTGate, error) {
+	vtgates, err := k.discoverVTGates(ctx, tags, executeFQDNTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vtgates) == 0 {
+		return nil, ErrNoVTGates
+	}
+
+	return vtgates[rand.Intn(len(vtgates))], nil
+}
+
+// DiscoverVTGateAddr is part of the Discovery interface.
+func (k *K8sDiscovery) DiscoverVTGateAddr(ctx context.Context, tags []string) (string, error) {
+	span, ctx := trace.NewSpan(ctx, "K8sDiscovery.DiscoverVTGateAddr")
+	defer span.Finish()
+
+	executeFQDNTemplate := false
+
+	vtgate, err := k.discoverVTGate(ctx, tags, executeFQDNTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	addr, err := textutil.ExecuteTemplate(k.vtgateAddrTmpl, vtgate)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute vtgate address template for %v: %w", vtgate, err)
+	}
+
+	return addr, nil
+}
+
+// DiscoverVTGateAddrs is part of the Discovery interface.
+func (k *K8sDiscovery) DiscoverVTGateAddrs(ctx context.Context, tags []string) ([]string, error) {
+	span, ctx := trace.NewSpan(ctx, "K8sDiscovery.DiscoverVTGateAddrs")
+	defer span.Finish()
+
+	executeFQDNTemplate := false
+
+	vtgates, err := k.discoverVTGates(ctx, tags, executeFQDNTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]string, len(vtgates))
+	for i, vtgate := range vtgates {
+		addr, err := textutil.ExecuteTemplate(k.vtgateAddrTmpl, vtgate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute vtgate address template for %v: %w", vtgate, err)
+		}
+
+		addrs[i] = addr
+	}
+
+	return addrs, nil
+}
+
+// DiscoverVTGates is part of the Discovery interface.
+func (k *K8sDiscovery) DiscoverVTGates(ctx context.Context, tags []string) ([]*vtadminpb.VTGate, error) {
+	span, ctx := trace.NewSpan(ctx, "K8sDiscovery.DiscoverVTGates")
+	defer span.Finish()
+
+	executeFQDNTemplate := true
+
+	return k.discoverVTGates(ctx, tags, executeFQDNTemplate)
+}
+
+// discoverVTGates does the actual work of discovering VTGate hosts from the
+// watched vtgate pods. executeFQDNTemplate is boolean to allow an optimization
+// for DiscoverVTGateAddr (the only function that sets the boolean to false).
+func (k *K8sDiscovery) discoverVTGates(ctx context.Context, tags []string, executeFQDNTemplate bool) ([]*vtadminpb.VTGate, error) {
+	k.start()
+
+	pods, err := k.listPods(ctx, k.vtgates, k.vtgateSynced, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	vtgates := make([]*vtadminpb.VTGate, len(pods))
+
+	for i, pod := range pods {
+		vtgate := &vtadminpb.VTGate{
+			Hostname: pod.Status.PodIP,
+			Cluster: &vtadminpb.Cluster{
+				Id:   k.cluster.Id,
+				Name: k.cluster.Name,
+			},
+			Cell: pod.Labels[k.vtgateCellLabel],
+			Pool: pod.Labels[k.vtgatePoolLabel],
+		}
+
+		if keyspaces, ok := pod.Annotations[k.vtgateKeyspacesToWatchAnnotation]; ok && keyspaces != "" {
+			vtgate.Keyspaces = strings.Split(keyspaces, ",")
+		}
+
+		if executeFQDNTemplate {
+			if k.vtgateFQDNTmpl != nil {
+				vtgate.FQDN, err = textutil.ExecuteTemplate(k.vtgateFQDNTmpl, vtgate)
+				if err != nil {
+					return nil, fmt.Errorf("failed to execute vtgate fqdn template for %v: %w", vtgate, err)
+				}
+			}
+		}
+
+		vtgates[i] = vtgate
+	}
+
+	return vtgates, nil
+}
+
+// DiscoverVtctld is part of the Discovery interface.
+func (k *K8sDiscovery) DiscoverVtctld(ctx context.Context, tags []string) (*vtadminpb.Vtctld, error) {
+	span, ctx := trace.NewSpan(ctx, "K8sDiscovery.DiscoverVtctld")
+	defer span.Finish()
+
+	executeFQDNTemplate := true
+
+	return k.discoverVtctld(ctx, tags, executeFQDNTemplate)
+}
+
+// discoverVtctld calls discoverVtctlds and then returns a random vtctld from
+// the result. see discoverVtctlds for further documentation.
+func (k *K8sDiscovery) discoverVtctld(ctx context.Context, tags []string, executeFQDNTemplate bool) (*vtadminpb.Vtctld, error) {
+	vtctlds, err := k.discoverVtctlds(ctx, tags, executeFQDNTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vtctlds) == 0 {
+		return nil, ErrNoVtctlds
+	}
+
+	return vtctlds[rand.Intn(len(vtctlds))], nil
+}
+
+// DiscoverVtctldAddr is part of the Discovery interface.
+func (k *K8sDiscovery) DiscoverVtctldAddr(ctx context.Context, tags []string) (string, error) {
+	span, ctx := trace.NewSpan(ctx, "K8sDiscovery.DiscoverVtctldAddr")
+	defer span.Finish()
+
+	executeFQDNTemplate := false
+
+	vtctld, err := k.discoverVtctld(ctx, tags, executeFQDNTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	addr, err := textutil.ExecuteTemplate(k.vtctldAddrTmpl, vtctld)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute vtctld address template for %v: %w", vtctld, err)
+	}
+
+	return addr, nil
+}
+
+// DiscoverVtctldAddrs is part of the Discovery interface.
+func (k *K8sDiscovery) DiscoverVtctldAddrs(ctx context.Context, tags []string) ([]string, error) {
+	span, ctx := trace.NewSpan(ctx, "K8sDiscovery.DiscoverVtctldAddrs")
+	defer span.Finish()
+
+	executeFQDNTemplate := false
+
+	vtctlds, err := k.discoverVtctlds(ctx, tags, executeFQDNTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]string, len(vtctlds))
+	for i, vtctld := range vtctlds {
+		addr, err := textutil.ExecuteTemplate(k.vtctldAddrTmpl, vtctld)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute vtctld address template for %v: %w", vtctld, err)
+		}
+
+		addrs[i] = addr
+	}
+
+	return addrs, nil
+}
+
+// DiscoverVtctlds is part of the Discovery interface.
+func (k *K8sDiscovery) DiscoverVtctlds(ctx context.Context, tags []string) ([]*vtadminpb.Vtctld, error) {
+	span, ctx := trace.NewSpan(ctx, "K8sDiscovery.DiscoverVtctlds")
+	defer span.Finish()
+
+	executeFQDNTemplate := true
+
+	return k.discoverVtctlds(ctx, tags, executeFQDNTemplate)
+}
+
+// discoverVtctlds does the actual work of discovering Vtctld hosts from the
+// watched vtctld pods. executeFQDNTemplate is boolean to allow an optimization
+// for DiscoverVtctldAddr (the only function that sets the boolean to false).
+func (k *K8sDiscovery) discoverVtctlds(ctx context.Context, tags []string, executeFQDNTemplate bool) ([]*vtadminpb.Vtctld, error) {
+	k.start()
+
+	pods, err := k.listPods(ctx, k.vtctlds, k.vtctldSynced, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	vtctlds := make([]*vtadminpb.Vtctld, len(pods))
+
+	for i, pod := range pods {
+		vtctld := &vtadminpb.Vtctld{
+			Cluster: &vtadminpb.Cluster{
+				Id:   k.cluster.Id,
+				Name: k.cluster.Name,
+			},
+			Hostname: pod.Status.PodIP,
+		}
+
+		if executeFQDNTemplate {
+			if k.vtctldFQDNTmpl != nil {
+				vtctld.FQDN, err = textutil.ExecuteTemplate(k.vtctldFQDNTmpl, vtctld)
+				if err != nil {
+					return nil, fmt.Errorf("failed to execute vtctld fqdn template for %v: %w", vtctld, err)
+				}
+			}
+		}
+
+		vtctlds[i] = vtctld
+	}
+
+	return vtctlds, nil
+}
+
+// listPods waits for the given pod cache to sync, then returns the pods in it
+// matching the tags. Pods without an IP, or which are not ready (when
+// readyOnly is set), are omitted. The result is sorted by pod name.
+func (k *K8sDiscovery) listPods(ctx context.Context, lister corelisters.PodLister, synced cache.InformerSynced, tags []string) ([]*corev1.Pod, error) {
+	selector, err := k8sTagSelector(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cache.WaitForCacheSync(ctx.Done(), synced) {
+		return nil, fmt.Errorf("%w: %s", ErrK8sCacheNotSynced, ctx.Err())
+	}
+
+	var pods []*corev1.Pod
+	if k.namespace == metav1.NamespaceAll {
+		pods, err = lister.List(selector)
+	} else {
+		pods, err = lister.Pods(k.namespace).List(selector)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]*corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if pod.Status.PodIP == "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		if k.readyOnly && !isPodReady(pod) {
+			continue
+		}
+
+		filtered = append(filtered, pod)
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		if filtered[i].Namespace != filtered[j].Namespace {
+			return filtered[i].Namespace < filtered[j].Namespace
+		}
+
+		return filtered[i].Name < filtered[j].Name
+	})
+
+	return filtered, nil
+}
+
+// k8sTagSelector converts discovery tags to a label selector. Tags of the form
+// "key:value" are rewritten to "key=value"; all other tags are parsed as label
+// selector expressions.
+func k8sTagSelector(tags []string) (labels.Selector, error) {
+	exprs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag == "" {
+			continue
+		}
+
+		if !strings.ContainsAny(tag, "=!() ") {
+			if name, value, ok := strings.Cut(tag, ":"); ok {
+				tag = name + "=" + value
+			}
+		}
+
+		exprs = append(exprs, tag)
+	}
+
+	selector, err := labels.Parse(strings.Join(exprs, ","))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse tags %v as label selector: %w", tags, err)
+	}
+
+	return selector, nil
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+)
+
+func k8sPod(namespace string, name string, ip string, ready bool, labels map[string]string, annotations map[string]string) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   namespace,
+			Name:        name,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Status: corev1.PodStatus{
+			PodIP: ip,
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: status},
+			},
+		},
+	}
+}
+
+func newTestK8sDiscovery(t *testing.T, args []string, objects ...runtime.Object) (*K8sDiscovery, *fake.Clientset) {
+	t.Helper()
+
+	client := fake.NewSimpleClientset(objects...)
+	disco, err := newK8sDiscovery(&vtadminpb.Cluster{
+		Id:   "cid",
+		Name: "cluster",
+	}, client, pflag.NewFlagSet("k8s", pflag.ContinueOnError), args)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { disco.Close() })
+
+	return disco, client
+}
+
+func TestK8sDiscoverVTGates(t *testing.T) {
+	t.Parallel()
+
+	vtgateLabels := func(cell string, pool string) map[string]string {
+		return map[string]string{
+			"app.kubernetes.io/component": "vtgate",
+			"cell":                        cell,
+			"pool":                        pool,
+		}
+	}
+
+	pods := []runtime.Object{
+		k8sPod("vitess", "vtgate-zone1-abc", "10.0.0.1", true, vtgateLabels("zone1", "pool1"), map[string]string{"keyspaces": "ks1,ks2"}),
+		k8sPod("vitess", "vtgate-zone2-def", "10.0.0.2", true, vtgateLabels("zone2", "pool1"), nil),
+		k8sPod("vitess", "vtgate-zone2-ghi", "10.0.0.3", false, vtgateLabels("zone2", "pool2"), nil),
+		k8sPod("vitess", "vtgate-zone3-jkl", "", true, vtgateLabels("zone3", "pool1"), nil),
+		k8sPod("other", "vtgate-zone1-xyz", "10.1.0.1", true, vtgateLabels("zone1", "pool1"), nil),
+		k8sPod("vitess", "vtctld-zone1-abc", "10.0.1.1", true, map[string]string{"app.kubernetes.io/component": "vtctld"}, nil),
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		tags      []string
+		expected  []*vtadminpb.VTGate
+		shouldErr bool
+	}{
+		{
+			name: "all gates",
+			args: []string{"--namespace=vitess"},
+			tags: []string{},
+			expected: []*vtadminpb.VTGate{
+				{
+					Cluster: &vtadminpb.Cluster{
+						Id:   "cid",
+						Name: "cluster",
+					},
+					Hostname:  "10.0.0.1",
+					Cell:      "zone1",
+					Pool:      "pool1",
+					Keyspaces: []string{"ks1", "ks2"},
+				},
+				{
+					Cluster: &vtadminpb.Cluster{
+						Id:   "cid",
+						Name: "cluster",
+					},
+					Hostname: "10.0.0.2",
+					Cell:     "zone2",
+					Pool:     "pool1",
+				},
+			},
+		},
+		{
+			name: "all namespaces",
+			args: []string{},
+			tags: []string{"cell:zone1"},
+			expected: []*vtadminpb.VTGate{
+				{
+					Cluster: &vtadminpb.Cluster{
+						Id:   "cid",
+						Name: "cluster",
+					},
+					Hostname: "10.1.0.1",
+					Cell:     "zone1",
+					Pool:     "pool1",
+				},
+				{
+					Cluster: &vtadminpb.Cluster{
+						Id:   "cid",
+						Name: "cluster",
+					},
+					Hostname:  "10.0.0.1",
+					Cell:      "zone1",
+					Pool:      "pool1",
+					Keyspaces: []string{"ks1", "ks2"},
+				},
+			},
+		},
+		{
+			name: "include unready",
+			args: []string{"--namespace=vitess", "--ready-only=false"},
+			tags: []string{"cell=zone2"},
+			expected: []*vtadminpb.VTGate{
+				{
+					Cluster: &vtadminpb.Cluster{
+						Id:   "cid",
+						Name: "cluster",
+					},
+					Hostname: "10.0.0.2",
+					Cell:     "zone2",
+					Pool:     "pool1",
+				},
+				{
+					Cluster: &vtadminpb.Cluster{
+						Id:   "cid",
+						Name: "cluster",
+					},
+					Hostname: "10.0.0.3",
+					Cell:     "zone2",
+					Pool:     "pool2",
+				},
+			},
+		},
+		{
+			name: "set-based tags",
+			args: []string{"--namespace=vitess", "--ready-only=false"},
+			tags: []string{"pool in (pool2)"},
+			expected: []*vtadminpb.VTGate{
+				{
+					Cluster: &vtadminpb.Cluster{
+						Id:   "cid",
+						Name: "cluster",
+					},
+					Hostname: "10.0.0.3",
+					Cell:     "zone2",
+					Pool:     "pool2",
+				},
+			},
+		},
+		{
+			name: "fqdn template",
+			args: []string{"--namespace=vitess", "--vtgate-fqdn-tmpl={{ .Cell }}-{{ .Pool }}.example.com"},
+			tags: []string{"cell:zone2"},
+			expected: []*vtadminpb.VTGate{
+				{
+					Cluster: &vtadminpb.Cluster{
+						Id:   "cid",
+						Name: "cluster",
+					},
+					Hostname: "10.0.0.2",
+					Cell:     "zone2",
+					Pool:     "pool1",
+					FQDN:     "zone2-pool1.example.com",
+				},
+			},
+		},
+		{
+			name:      "invalid tags",
+			args:      []string{"--namespace=vitess"},
+			tags:      []string{"cell in zone1"},
+			shouldErr: true,
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			disco, _ := newTestK8sDiscovery(t, tt.args, pods...)
+
+			gates, err := disco.DiscoverVTGates(ctx, tt.tags)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, gates)
+		})
+	}
+}
+
+func TestK8sDiscoverVtctldAddrs(t *testing.T) {
+	t.Parallel()
+
+	vtctldLabels := map[string]string{"app.kubernetes.io/component": "vtctld"}
+	disco, _ := newTestK8sDiscovery(t,
+		[]string{"--namespace=vitess", "--vtctld-addr-tmpl={{ .Hostname }}:15999"},
+		k8sPod("vitess", "vtctld-b", "10.0.1.2", true, vtctldLabels, nil),
+		k8sPod("vitess", "vtctld-a", "10.0.1.1", true, vtctldLabels, nil),
+		k8sPod("vitess", "vtgate-a", "10.0.0.1", true, map[string]string{"app.kubernetes.io/component": "vtgate"}, nil),
+	)
+
+	addrs, err := disco.DiscoverVtctldAddrs(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.1.1:15999", "10.0.1.2:15999"}, addrs)
+}
+
+func TestK8sDiscoveryWatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	vtgateLabels := map[string]string{"app.kubernetes.io/component": "vtgate"}
+
+	disco, client := newTestK8sDiscovery(t, []string{"--namespace=vitess"})
+
+	_, err := disco.DiscoverVTGate(ctx, nil)
+	assert.ErrorIs(t, err, ErrNoVTGates)
+
+	pod := k8sPod("vitess", "vtgate-a", "10.0.0.1", true, vtgateLabels, nil)
+	_, err = client.CoreV1().Pods("vitess").Create(ctx, pod, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		addr, err := disco.DiscoverVTGateAddr(ctx, nil)
+		return err == nil && addr == "10.0.0.1"
+	}, 5*time.Second, 10*time.Millisecond, "vtgate pod creation was not observed")
+
+	pod = pod.DeepCopy()
+	pod.Status.Conditions[0].Status = corev1.ConditionFalse
+	_, err = client.CoreV1().Pods("vitess").UpdateStatus(ctx, pod, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		_, err := disco.DiscoverVTGate(ctx, nil)
+		return err == ErrNoVTGates
+	}, 5*time.Second, 10*time.Millisecond, "vtgate pod becoming unready was not observed")
+}
+
+func TestK8sDiscoveryCacheNotSynced(t *testing.T) {
+	t.Parallel()
+
+	disco, _ := newTestK8sDiscovery(t, nil)
+	disco.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// With the watches stopped before they start, the cache can never sync, so
+	// we return once the context is done.
+	_, err := disco.DiscoverVtctlds(ctx, nil)
+	assert.ErrorIs(t, err, ErrK8sCacheNotSynced)
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/vtadmin/errors"
+
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// CancelSchemaMigration implements the http wrapper for
+// PUT /migration/{cluster_id}/{keyspace}/{uuid}/cancel.
+//
+// The uuid may be "all" to cancel all pending migrations in the keyspace.
+func CancelSchemaMigration(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+
+	resp, err := api.server.CancelSchemaMigration(ctx, &vtadminpb.CancelSchemaMigrationRequest{
+		ClusterId: vars["cluster_id"],
+		Request: &vtctldatapb.CancelSchemaMigrationRequest{
+			Keyspace: vars["keyspace"],
+			Uuid:     vars["uuid"],
+		},
+	})
+
+	return NewJSONResponse(resp, err)
+}
+
+// CompleteSchemaMigration implements the http wrapper for
+// PUT /migration/{cluster_id}/{keyspace}/{uuid}/complete.
+//
+// The uuid may be "all" to complete all migrations in the keyspace that are
+// ready to complete.
+func CompleteSchemaMigration(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+
+	resp, err := api.server.CompleteSchemaMigration(ctx, &vtadminpb.CompleteSchemaMigrationRequest{
+		ClusterId: vars["cluster_id"],
+		Request: &vtctldatapb.CompleteSchemaMigrationRequest{
+			Keyspace: vars["keyspace"],
+			Uuid:     vars["uuid"],
+		},
+	})
+
+	return NewJSONResponse(resp, err)
+}
+
+// GetSchemaMigrations implements the http wrapper for POST /migrations.
+//
+// Query params: none
+//
+// POST body is unmarshalled as vtadminpb.GetSchemaMigrationsRequest, with
+// one request per cluster/keyspace pair to list migrations for.
+func GetSchemaMigrations(ctx context.Context, r Request, api *API) *JSONResponse {
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	var req vtadminpb.GetSchemaMigrationsRequest
+	if err := decoder.Decode(&req); err != nil {
+		return NewJSONResponse(nil, &errors.BadRequest{
+			Err: err,
+		})
+	}
+
+	resp, err := api.server.GetSchemaMigrations(ctx, &req)
+	return NewJSONResponse(resp, err)
+}
+
+// LaunchSchemaMigration implements the http wrapper for
+// PUT /migration/{cluster_id}/{keyspace}/{uuid}/launch.
+//
+// The uuid may be "all" to launch all postponed migrations in the keyspace.
+func LaunchSchemaMigration(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+
+	resp, err := api.server.LaunchSchemaMigration(ctx, &vtadminpb.LaunchSchemaMigrationRequest{
+		ClusterId: vars["cluster_id"],
+		Request: &vtctldatapb.LaunchSchemaMigrationRequest{
+			Keyspace: vars["keyspace"],
+			Uuid:     vars["uuid"],
+		},
+	})
+
+	return NewJSONResponse(resp, err)
+}
+
+// RetrySchemaMigration implements the http wrapper for
+// PUT /migration/{cluster_id}/{keyspace}/{uuid}/retry.
+func RetrySchemaMigration(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+
+	resp, err := api.server.RetrySchemaMigration(ctx, &vtadminpb.RetrySchemaMigrationRequest{
+		ClusterId: vars["cluster_id"],
+		Request: &vtctldatapb.RetrySchemaMigrationRequest{
+			Keyspace: vars["keyspace"],
+			Uuid:     vars["uuid"],
+		},
+	})
+
+	return NewJSONResponse(resp, err)
+}
+
+// ThrottleSchemaMigrations implements the http wrapper for
+// PUT /migrations/{cluster_id}/{keyspace}/throttle.
+//
+// Query params: none
+//
+// Body params:
+// - ratio: float64, in the range [0, 1]; defaults to 1 (fully throttled)
+// - duration: string, a Go duration such as "30m"; defaults to 1h
+func ThrottleSchemaMigrations(ctx context.Context, r Request, api *API) *JSONResponse {
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	var params struct {
+		Ratio    float64 `json:"ratio"`
+		Duration string  `json:"duration"`
+	}
+
+	if err := decoder.Decode(&params); err != nil {
+		return NewJSONResponse(nil, &errors.BadRequest{
+			Err: err,
+		})
+	}
+
+	vars := r.Vars()
+	req := &vtadminpb.ThrottleSchemaMigrationsRequest{
+		ClusterId: vars["cluster_id"],
+		Keyspace:  vars["keyspace"],
+		Ratio:     params.Ratio,
+	}
+
+	if params.Duration != "" {
+		duration, err := time.ParseDuration(params.Duration)
+		if err != nil {
+			return NewJSONResponse(nil, &errors.BadRequest{
+				Err: err,
+			})
+		}
+
+		req.Duration = protoutil.DurationToProto(duration)
+	}
+
+	resp, err := api.server.ThrottleSchemaMigrations(ctx, req)
+	return NewJSONResponse(resp, err)
+}
+
+// UnthrottleSchemaMigrations implements the http wrapper for
+// PUT /migrations/{cluster_id}/{keyspace}/unthrottle.
+func UnthrottleSchemaMigrations(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+
+	resp, err := api.server.ThrottleSchemaMigrations(ctx, &vtadminpb.ThrottleSchemaMigrationsRequest{
+		ClusterId:  vars["cluster_id"],
+		Keyspace:   vars["keyspace"],
+		Unthrottle: true,
+	})
+
+	return NewJSONResponse(resp, err)
+}
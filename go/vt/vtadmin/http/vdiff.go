@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+
+	"vitess.io/vitess/go/vt/vtadmin/errors"
+
+	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// VDiffCreate implements the http wrapper for
+// POST /vdiff/{cluster_id}/{keyspace}/{workflow}.
+//
+// Query params: none
+//
+// POST body is unmarshalled as vtctldatapb.VDiffCreateRequest, but the
+// TargetKeyspace and Workflow fields are ignored (coming instead from the
+// route).
+func VDiffCreate(ctx context.Context, r Request, api *API) *JSONResponse {
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	var req vtctldatapb.VDiffCreateRequest
+	if err := decoder.Decode(&req); err != nil {
+		return NewJSONResponse(nil, &errors.BadRequest{
+			Err: err,
+		})
+	}
+
+	vars := r.Vars()
+	req.TargetKeyspace = vars["keyspace"]
+	req.Workflow = vars["workflow"]
+
+	resp, err := api.server.VDiffCreate(ctx, &vtadminpb.VDiffCreateRequest{
+		ClusterId: vars["cluster_id"],
+		Request:   &req,
+	})
+	return NewJSONResponse(resp, err)
+}
+
+// VDiffShow implements the http wrapper for
+// GET /vdiff/{cluster_id}/{keyspace}/{workflow}/{arg}.
+//
+// The arg is one of "all", "last", or the UUID of a single VDiff.
+func VDiffShow(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+
+	resp, err := api.server.VDiffShow(ctx, &vtadminpb.VDiffShowRequest{
+		ClusterId: vars["cluster_id"],
+		Request: &vtctldatapb.VDiffShowRequest{
+			TargetKeyspace: vars["keyspace"],
+			Workflow:       vars["workflow"],
+			Arg:            vars["arg"],
+		},
+	})
+
+	return NewJSONResponse(resp, err)
+}
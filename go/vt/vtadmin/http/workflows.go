@@ -18,8 +18,12 @@ package http
 
 import (
 	"context"
+	"encoding/json"
+
+	"vitess.io/vitess/go/vt/vtadmin/errors"
 
 	vtadminpb "vitess.io/vitess/go/vt/proto/vtadmin"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 // GetWorkflow implements the http wrapper for the VTAdminServer.GetWorkflow
@@ -69,3 +73,61 @@ func GetWorkflows(ctx context.Context, r Request, api *API) *JSONResponse {
 
 	return NewJSONResponse(workflows, err)
 }
+
+// StartWorkflow implements the http wrapper for
+// PUT /workflow/{cluster_id}/{keyspace}/{name}/start.
+func StartWorkflow(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+
+	resp, err := api.server.StartWorkflow(ctx, &vtadminpb.StartWorkflowRequest{
+		ClusterId: vars["cluster_id"],
+		Keyspace:  vars["keyspace"],
+		Workflow:  vars["name"],
+	})
+
+	return NewJSONResponse(resp, err)
+}
+
+// StopWorkflow implements the http wrapper for
+// PUT /workflow/{cluster_id}/{keyspace}/{name}/stop.
+func StopWorkflow(ctx context.Context, r Request, api *API) *JSONResponse {
+	vars := r.Vars()
+
+	resp, err := api.server.StopWorkflow(ctx, &vtadminpb.StopWorkflowRequest{
+		ClusterId: vars["cluster_id"],
+		Keyspace:  vars["keyspace"],
+		Workflow:  vars["name"],
+	})
+
+	return NewJSONResponse(resp, err)
+}
+
+// WorkflowSwitchTraffic implements the http wrapper for
+// POST /workflow/{cluster_id}/{keyspace}/{name}/switch_traffic.
+//
+// Query params: none
+//
+// POST body is unmarshalled as vtctldatapb.WorkflowSwitchTrafficRequest, but
+// the Keyspace and Workflow fields are ignored (coming instead from the
+// route).
+func WorkflowSwitchTraffic(ctx context.Context, r Request, api *API) *JSONResponse {
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	var req vtctldatapb.WorkflowSwitchTrafficRequest
+	if err := decoder.Decode(&req); err != nil {
+		return NewJSONResponse(nil, &errors.BadRequest{
+			Err: err,
+		})
+	}
+
+	vars := r.Vars()
+	req.Keyspace = vars["keyspace"]
+	req.Workflow = vars["name"]
+
+	resp, err := api.server.WorkflowSwitchTraffic(ctx, &vtadminpb.WorkflowSwitchTrafficRequest{
+		ClusterId: vars["cluster_id"],
+		Request:   &req,
+	})
+	return NewJSONResponse(resp, err)
+}
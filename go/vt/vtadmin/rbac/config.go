@@ -181,6 +181,13 @@ func DefaultConfig() *Config {
 		string(ManageTabletReplicationAction),
 		string(ManageTabletWritabilityAction),
 		string(RefreshTabletReplicationSourceAction),
+		string(CancelSchemaMigrationAction),
+		string(CompleteSchemaMigrationAction),
+		string(LaunchSchemaMigrationAction),
+		string(RetrySchemaMigrationAction),
+		string(ThrottleSchemaMigrationsAction),
+		string(ManageWorkflowAction),
+		string(SwitchWorkflowTrafficAction),
 	}
 	subjects := []string{"*"}
 	clusters := []string{"*"}
@@ -83,6 +83,19 @@ const (
 	ManageTabletReplicationAction        Action = "manage_tablet_replication" // Start/Stop Replication
 	ManageTabletWritabilityAction        Action = "manage_tablet_writability" // SetRead{Only,Write}
 	RefreshTabletReplicationSourceAction Action = "refresh_tablet_replication_source"
+
+	/* schema-migration-specific actions */
+
+	CancelSchemaMigrationAction    Action = "cancel_schema_migration"
+	CompleteSchemaMigrationAction  Action = "complete_schema_migration"
+	LaunchSchemaMigrationAction    Action = "launch_schema_migration"
+	RetrySchemaMigrationAction     Action = "retry_schema_migration"
+	ThrottleSchemaMigrationsAction Action = "throttle_schema_migrations" // Throttle and unthrottle online DDL in a keyspace.
+
+	/* workflow-specific actions */
+
+	ManageWorkflowAction        Action = "manage_workflow" // Start/Stop Workflow
+	SwitchWorkflowTrafficAction Action = "switch_workflow_traffic"
 )
 
 // Resource is an enum representing all resources managed by vtadmin.
@@ -116,7 +129,9 @@ const (
 
 	BackupResource                   Resource = "Backup"
 	SchemaResource                   Resource = "Schema"
+	SchemaMigrationResource          Resource = "SchemaMigration"
 	ShardReplicationPositionResource Resource = "ShardReplicationPosition"
+	VDiffResource                    Resource = "VDiff"
 	WorkflowResource                 Resource = "Workflow"
 
 	VTExplainResource Resource = "VTExplain"
@@ -38,6 +38,14 @@ import (
 type VtctldClient struct {
 	vtctldclient.VtctldClient
 
+	CancelSchemaMigrationResults map[string]struct {
+		Response *vtctldatapb.CancelSchemaMigrationResponse
+		Error    error
+	}
+	CompleteSchemaMigrationResults map[string]struct {
+		Response *vtctldatapb.CompleteSchemaMigrationResponse
+		Error    error
+	}
 	CreateKeyspaceShouldErr bool
 	CreateShardShouldErr    bool
 	DeleteKeyspaceShouldErr bool
@@ -77,6 +85,10 @@ type VtctldClient struct {
 		Keyspaces []*vtctldatapb.Keyspace
 		Error     error
 	}
+	GetSchemaMigrationsResults map[string]struct {
+		Response *vtctldatapb.GetSchemaMigrationsResponse
+		Error    error
+	}
 	GetSchemaResults map[string]struct {
 		Response *vtctldatapb.GetSchemaResponse
 		Error    error
@@ -93,6 +105,10 @@ type VtctldClient struct {
 		Response *vtctldatapb.GetWorkflowsResponse
 		Error    error
 	}
+	LaunchSchemaMigrationResults map[string]struct {
+		Response *vtctldatapb.LaunchSchemaMigrationResponse
+		Error    error
+	}
 	PingTabletResults           map[string]error
 	PlannedReparentShardResults map[string]struct {
 		Response *vtctldatapb.PlannedReparentShardResponse
@@ -115,6 +131,10 @@ type VtctldClient struct {
 		Response *vtctldatapb.ReparentTabletResponse
 		Error    error
 	}
+	RetrySchemaMigrationResults map[string]struct {
+		Response *vtctldatapb.RetrySchemaMigrationResponse
+		Error    error
+	}
 	RunHealthCheckResults            map[string]error
 	SetWritableResults               map[string]error
 	ShardReplicationPositionsResults map[string]struct {
@@ -127,6 +147,20 @@ type VtctldClient struct {
 		Response *vtctldatapb.TabletExternallyReparentedResponse
 		Error    error
 	}
+	UpdateThrottlerConfigResults map[string]struct {
+		Response *vtctldatapb.UpdateThrottlerConfigResponse
+		Error    error
+	}
+	// Keyed by <target_keyspace>/<workflow>.
+	VDiffCreateResults map[string]struct {
+		Response *vtctldatapb.VDiffCreateResponse
+		Error    error
+	}
+	// Keyed by <target_keyspace>/<workflow>.
+	VDiffShowResults map[string]struct {
+		Response *vtctldatapb.VDiffShowResponse
+		Error    error
+	}
 	ValidateKeyspaceResults map[string]struct {
 		Response *vtctldatapb.ValidateKeyspaceResponse
 		Error    error
@@ -139,6 +173,11 @@ type VtctldClient struct {
 		Response *vtctldatapb.ValidateVersionKeyspaceResponse
 		Error    error
 	}
+	// Keyed by <keyspace>/<workflow>.
+	WorkflowSwitchTrafficResults map[string]struct {
+		Response *vtctldatapb.WorkflowSwitchTrafficResponse
+		Error    error
+	}
 	WorkflowUpdateResults map[string]struct {
 		Response *vtctldatapb.WorkflowUpdateResponse
 		Error    error
@@ -152,6 +191,34 @@ var _ vtctldclient.VtctldClient = (*VtctldClient)(nil)
 // Close is part of the vtctldclient.VtctldClient interface.
 func (fake *VtctldClient) Close() error { return nil }
 
+// CancelSchemaMigration is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) CancelSchemaMigration(ctx context.Context, req *vtctldatapb.CancelSchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.CancelSchemaMigrationResponse, error) {
+	if fake.CancelSchemaMigrationResults == nil {
+		return nil, fmt.Errorf("%w: CancelSchemaMigrationResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	key := req.Keyspace
+	if result, ok := fake.CancelSchemaMigrationResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
+}
+
+// CompleteSchemaMigration is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) CompleteSchemaMigration(ctx context.Context, req *vtctldatapb.CompleteSchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.CompleteSchemaMigrationResponse, error) {
+	if fake.CompleteSchemaMigrationResults == nil {
+		return nil, fmt.Errorf("%w: CompleteSchemaMigrationResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	key := req.Keyspace
+	if result, ok := fake.CompleteSchemaMigrationResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
+}
+
 // CreateKeyspace is part of the vtctldclient.VtctldClient interface.
 func (fake *VtctldClient) CreateKeyspace(ctx context.Context, req *vtctldatapb.CreateKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.CreateKeyspaceResponse, error) {
 	if fake.CreateKeyspaceShouldErr {
@@ -365,6 +432,20 @@ func (fake *VtctldClient) GetSchema(ctx context.Context, req *vtctldatapb.GetSch
 	return nil, fmt.Errorf("%w: no result set for tablet alias %s", assert.AnError, key)
 }
 
+// GetSchemaMigrations is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) GetSchemaMigrations(ctx context.Context, req *vtctldatapb.GetSchemaMigrationsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetSchemaMigrationsResponse, error) {
+	if fake.GetSchemaMigrationsResults == nil {
+		return nil, fmt.Errorf("%w: GetSchemaMigrationsResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	key := req.Keyspace
+	if result, ok := fake.GetSchemaMigrationsResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
+}
+
 // GetSrvVSchema is part of the vtctldclient.VtctldClient interface.
 func (fake *VtctldClient) GetSrvVSchema(ctx context.Context, req *vtctldatapb.GetSrvVSchemaRequest, opts ...grpc.CallOption) (*vtctldatapb.GetSrvVSchemaResponse, error) {
 	if fake.GetSrvVSchemaResults == nil {
@@ -429,6 +510,20 @@ func (fake *VtctldClient) GetWorkflows(ctx context.Context, req *vtctldatapb.Get
 	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, req.Keyspace)
 }
 
+// LaunchSchemaMigration is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) LaunchSchemaMigration(ctx context.Context, req *vtctldatapb.LaunchSchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.LaunchSchemaMigrationResponse, error) {
+	if fake.LaunchSchemaMigrationResults == nil {
+		return nil, fmt.Errorf("%w: LaunchSchemaMigrationResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	key := req.Keyspace
+	if result, ok := fake.LaunchSchemaMigrationResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
+}
+
 // PingTablet is part of the vtctldclient.VtctldClient interface.
 func (fake *VtctldClient) PingTablet(ctx context.Context, req *vtctldatapb.PingTabletRequest, opts ...grpc.CallOption) (*vtctldatapb.PingTabletResponse, error) {
 	if fake.PingTabletResults == nil {
@@ -534,6 +629,20 @@ func (fake *VtctldClient) ReparentTablet(ctx context.Context, req *vtctldatapb.R
 	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
 }
 
+// RetrySchemaMigration is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) RetrySchemaMigration(ctx context.Context, req *vtctldatapb.RetrySchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.RetrySchemaMigrationResponse, error) {
+	if fake.RetrySchemaMigrationResults == nil {
+		return nil, fmt.Errorf("%w: RetrySchemaMigrationResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	key := req.Keyspace
+	if result, ok := fake.RetrySchemaMigrationResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
+}
+
 // RunHealthCheck is part of the vtctldclient.VtctldClient interface.
 func (fake *VtctldClient) RunHealthCheck(ctx context.Context, req *vtctldatapb.RunHealthCheckRequest, opts ...grpc.CallOption) (*vtctldatapb.RunHealthCheckResponse, error) {
 	if fake.RunHealthCheckResults == nil {
@@ -634,6 +743,48 @@ func (fake *VtctldClient) TabletExternallyReparented(ctx context.Context, req *v
 	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
 }
 
+// UpdateThrottlerConfig is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) UpdateThrottlerConfig(ctx context.Context, req *vtctldatapb.UpdateThrottlerConfigRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateThrottlerConfigResponse, error) {
+	if fake.UpdateThrottlerConfigResults == nil {
+		return nil, fmt.Errorf("%w: UpdateThrottlerConfigResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	key := req.Keyspace
+	if result, ok := fake.UpdateThrottlerConfigResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
+}
+
+// VDiffCreate is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) VDiffCreate(ctx context.Context, req *vtctldatapb.VDiffCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffCreateResponse, error) {
+	if fake.VDiffCreateResults == nil {
+		return nil, fmt.Errorf("%w: VDiffCreateResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	key := fmt.Sprintf("%s/%s", req.TargetKeyspace, req.Workflow)
+	if result, ok := fake.VDiffCreateResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
+}
+
+// VDiffShow is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) VDiffShow(ctx context.Context, req *vtctldatapb.VDiffShowRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffShowResponse, error) {
+	if fake.VDiffShowResults == nil {
+		return nil, fmt.Errorf("%w: VDiffShowResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	key := fmt.Sprintf("%s/%s", req.TargetKeyspace, req.Workflow)
+	if result, ok := fake.VDiffShowResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
+}
+
 // ValidateKeyspace is part of the vtctldclient.VtctldClient interface.
 func (fake *VtctldClient) ValidateKeyspace(ctx context.Context, req *vtctldatapb.ValidateKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateKeyspaceResponse, error) {
 	if fake.ValidateKeyspaceResults == nil {
@@ -676,6 +827,20 @@ func (fake *VtctldClient) ValidateVersionKeyspace(ctx context.Context, req *vtct
 	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
 }
 
+// WorkflowSwitchTraffic is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) WorkflowSwitchTraffic(ctx context.Context, req *vtctldatapb.WorkflowSwitchTrafficRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowSwitchTrafficResponse, error) {
+	if fake.WorkflowSwitchTrafficResults == nil {
+		return nil, fmt.Errorf("%w: WorkflowSwitchTrafficResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	key := fmt.Sprintf("%s/%s", req.Keyspace, req.Workflow)
+	if result, ok := fake.WorkflowSwitchTrafficResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
+}
+
 // WorkflowUpdate is part of the vtctldclient.VtctldClient interface.
 func (fake *VtctldClient) WorkflowUpdate(ctx context.Context, req *vtctldatapb.WorkflowUpdateRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowUpdateResponse, error) {
 	if fake.WorkflowUpdateResults == nil {
@@ -0,0 +1,230 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtcdc
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/json2"
+	"vitess.io/vitess/go/sqltypes"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// Debezium operation codes.
+const (
+	OpCreate = "c"
+	OpUpdate = "u"
+	OpDelete = "d"
+	OpRead   = "r"
+)
+
+// Source describes where a change comes from, like the source block of
+// the Debezium Vitess connector.
+type Source struct {
+	Version   string `json:"version"`
+	Connector string `json:"connector"`
+	Name      string `json:"name"`
+	TsMs      int64  `json:"ts_ms"`
+	Snapshot  string `json:"snapshot"`
+	Db        string `json:"db"`
+	Keyspace  string `json:"keyspace"`
+	Shard     string `json:"shard"`
+	Table     string `json:"table,omitempty"`
+	// Vgtid is the position the stream can be resumed from to receive this event again.
+	Vgtid string `json:"vgtid"`
+}
+
+// ChangeEvent is the Debezium envelope of a row change.
+type ChangeEvent struct {
+	Before map[string]any `json:"before"`
+	After  map[string]any `json:"after"`
+	Source *Source        `json:"source"`
+	Op     string         `json:"op"`
+	TsMs   int64          `json:"ts_ms"`
+}
+
+// SchemaChangeEvent is the Debezium envelope of a DDL.
+type SchemaChangeEvent struct {
+	Source       *Source `json:"source"`
+	DatabaseName string  `json:"databaseName"`
+	DDL          string  `json:"ddl"`
+	TsMs         int64   `json:"ts_ms"`
+}
+
+// Converter converts the events of a VStream into Debezium envelopes.
+// It keeps the fields of the tables and the transaction state of the shards,
+// so it must see all the events of the stream in order.
+type Converter struct {
+	// Name is the logical name of the source, used in the source block of the envelopes.
+	Name string
+	// Version is the version of the producer, used in the source block of the envelopes.
+	Version string
+
+	fields map[string][]*querypb.Field
+	// inTransaction is keyed by keyspace/shard. Rows received outside of a transaction
+	// are sent while copying the existing rows of a table, and are reported as reads.
+	inTransaction map[string]bool
+	vgtid         string
+}
+
+// NewConverter returns a Converter for a VStream starting at vgtid.
+func NewConverter(name, version string, vgtid *binlogdatapb.VGtid) (*Converter, error) {
+	c := &Converter{
+		Name:          name,
+		Version:       version,
+		fields:        make(map[string][]*querypb.Field),
+		inTransaction: make(map[string]bool),
+	}
+	if err := c.setVgtid(vgtid); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Converter) setVgtid(vgtid *binlogdatapb.VGtid) error {
+	if vgtid == nil {
+		c.vgtid = ""
+		return nil
+	}
+	b, err := json2.MarshalPB(vgtid)
+	if err != nil {
+		return err
+	}
+	c.vgtid = string(b)
+	return nil
+}
+
+// Convert returns the envelopes for an event. Events that don't produce
+// any envelope, like BEGIN or FIELD, only update the state of the Converter.
+func (c *Converter) Convert(ev *binlogdatapb.VEvent) ([]any, error) {
+	shardKey := ev.Keyspace + "/" + ev.Shard
+	switch ev.Type {
+	case binlogdatapb.VEventType_BEGIN:
+		c.inTransaction[shardKey] = true
+	case binlogdatapb.VEventType_COMMIT, binlogdatapb.VEventType_ROLLBACK:
+		delete(c.inTransaction, shardKey)
+	case binlogdatapb.VEventType_VGTID:
+		if err := c.setVgtid(ev.Vgtid); err != nil {
+			return nil, err
+		}
+	case binlogdatapb.VEventType_FIELD:
+		c.fields[ev.FieldEvent.TableName] = ev.FieldEvent.Fields
+	case binlogdatapb.VEventType_ROW:
+		return c.convertRows(ev, c.inTransaction[shardKey])
+	case binlogdatapb.VEventType_DDL:
+		return []any{&SchemaChangeEvent{
+			Source:       c.source(ev, "", true),
+			DatabaseName: ev.Keyspace,
+			DDL:          ev.Statement,
+			TsMs:         ev.CurrentTime / 1e6,
+		}}, nil
+	}
+	return nil, nil
+}
+
+func (c *Converter) convertRows(ev *binlogdatapb.VEvent, inTransaction bool) ([]any, error) {
+	fields, ok := c.fields[ev.RowEvent.TableName]
+	if !ok {
+		return nil, fmt.Errorf("no field event received for table %s", ev.RowEvent.TableName)
+	}
+	_, table, _ := strings.Cut(ev.RowEvent.TableName, ".")
+	source := c.source(ev, table, inTransaction)
+
+	envelopes := make([]any, 0, len(ev.RowEvent.RowChanges))
+	for _, change := range ev.RowEvent.RowChanges {
+		envelope := &ChangeEvent{
+			Source: source,
+			TsMs:   ev.CurrentTime / 1e6,
+		}
+		switch {
+		case change.Before == nil:
+			envelope.Op = OpCreate
+			if !inTransaction {
+				envelope.Op = OpRead
+			}
+		case change.After == nil:
+			envelope.Op = OpDelete
+		default:
+			envelope.Op = OpUpdate
+		}
+		if change.Before != nil {
+			envelope.Before = rowToMap(fields, change.Before)
+		}
+		if change.After != nil {
+			envelope.After = rowToMap(fields, change.After)
+		}
+		envelopes = append(envelopes, envelope)
+	}
+	return envelopes, nil
+}
+
+func (c *Converter) source(ev *binlogdatapb.VEvent, table string, inTransaction bool) *Source {
+	snapshot := "false"
+	if !inTransaction {
+		snapshot = "true"
+	}
+	return &Source{
+		Version:   c.Version,
+		Connector: "vitess",
+		Name:      c.Name,
+		TsMs:      ev.Timestamp * 1000,
+		Snapshot:  snapshot,
+		Db:        ev.Keyspace,
+		Keyspace:  ev.Keyspace,
+		Shard:     ev.Shard,
+		Table:     table,
+		Vgtid:     c.vgtid,
+	}
+}
+
+func rowToMap(fields []*querypb.Field, row *querypb.Row) map[string]any {
+	values := sqltypes.MakeRowTrusted(fields, row)
+	m := make(map[string]any, len(values))
+	for i, value := range values {
+		m[fields[i].Name] = jsonValue(value)
+	}
+	return m
+}
+
+// jsonValue converts a value the way Debezium does with its default settings:
+// numbers are JSON numbers, except decimals which are strings to keep their
+// precision, and binary values are base64 encoded.
+func jsonValue(v sqltypes.Value) any {
+	switch {
+	case v.IsNull():
+		return nil
+	case v.IsSigned():
+		if i, err := v.ToInt64(); err == nil {
+			return i
+		}
+	case v.IsUnsigned():
+		if u, err := v.ToUint64(); err == nil {
+			return u
+		}
+	case v.IsFloat():
+		if f, err := v.ToFloat64(); err == nil {
+			return f
+		}
+	case v.IsBinary() || v.Type() == sqltypes.Bit || v.Type() == sqltypes.Geometry:
+		return base64.StdEncoding.EncodeToString(v.Raw())
+	}
+	return v.ToString()
+}
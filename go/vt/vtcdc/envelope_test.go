@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtcdc
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/json2"
+	"vitess.io/vitess/go/sqltypes"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+)
+
+var testFields = sqltypes.MakeTestFields("id|name|price|data", "int64|varchar|decimal|varbinary")
+
+func rowEvent(keyspace, shard string, changes ...*binlogdatapb.RowChange) *binlogdatapb.VEvent {
+	return &binlogdatapb.VEvent{
+		Type:        binlogdatapb.VEventType_ROW,
+		Keyspace:    keyspace,
+		Shard:       shard,
+		Timestamp:   1700000000,
+		CurrentTime: 1700000001 * 1e9,
+		RowEvent: &binlogdatapb.RowEvent{
+			TableName:  keyspace + ".product",
+			Keyspace:   keyspace,
+			Shard:      shard,
+			RowChanges: changes,
+		},
+	}
+}
+
+func fieldEvent(keyspace, shard string) *binlogdatapb.VEvent {
+	return &binlogdatapb.VEvent{
+		Type:     binlogdatapb.VEventType_FIELD,
+		Keyspace: keyspace,
+		Shard:    shard,
+		FieldEvent: &binlogdatapb.FieldEvent{
+			TableName: keyspace + ".product",
+			Fields:    testFields,
+			Keyspace:  keyspace,
+			Shard:     shard,
+		},
+	}
+}
+
+func TestConvertRowEvents(t *testing.T) {
+	vgtid := &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{{Keyspace: "ks", Shard: "-80", Gtid: "MySQL56/a:1-10"}}}
+	c, err := NewConverter("cdc", "19.0.0", vgtid)
+	require.NoError(t, err)
+
+	row1 := sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar("keyboard"), sqltypes.MakeTrusted(sqltypes.Decimal, []byte("10.50")), sqltypes.MakeTrusted(sqltypes.VarBinary, []byte{0xff, 0x00})})
+	row2 := sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar("keyboard"), sqltypes.MakeTrusted(sqltypes.Decimal, []byte("12.00")), sqltypes.NULL})
+
+	events := []*binlogdatapb.VEvent{
+		fieldEvent("ks", "-80"),
+		// rows sent outside of a transaction are copied rows
+		rowEvent("ks", "-80", &binlogdatapb.RowChange{After: row1}),
+		{Type: binlogdatapb.VEventType_BEGIN, Keyspace: "ks", Shard: "-80"},
+		rowEvent("ks", "-80",
+			&binlogdatapb.RowChange{After: row1},
+			&binlogdatapb.RowChange{Before: row1, After: row2},
+			&binlogdatapb.RowChange{Before: row2},
+		),
+		{Type: binlogdatapb.VEventType_COMMIT, Keyspace: "ks", Shard: "-80"},
+	}
+	var envelopes []any
+	for _, ev := range events {
+		out, err := c.Convert(ev)
+		require.NoError(t, err)
+		envelopes = append(envelopes, out...)
+	}
+	require.Len(t, envelopes, 4)
+
+	var ops []string
+	for _, envelope := range envelopes {
+		ops = append(ops, envelope.(*ChangeEvent).Op)
+	}
+	assert.Equal(t, []string{OpRead, OpCreate, OpUpdate, OpDelete}, ops)
+
+	update, err := json.Marshal(envelopes[2])
+	require.NoError(t, err)
+	position, err := json2.MarshalPB(vgtid)
+	require.NoError(t, err)
+	assert.JSONEq(t, fmt.Sprintf(`{
+		"before": {"id": 1, "name": "keyboard", "price": "10.50", "data": "/wA="},
+		"after": {"id": 1, "name": "keyboard", "price": "12.00", "data": null},
+		"source": {
+			"version": "19.0.0",
+			"connector": "vitess",
+			"name": "cdc",
+			"ts_ms": 1700000000000,
+			"snapshot": "false",
+			"db": "ks",
+			"keyspace": "ks",
+			"shard": "-80",
+			"table": "product",
+			"vgtid": %q
+		},
+		"op": "u",
+		"ts_ms": 1700000001000
+	}`, position), string(update))
+
+	assert.Equal(t, "true", envelopes[0].(*ChangeEvent).Source.Snapshot)
+}
+
+func TestConvertVGtidAndDDL(t *testing.T) {
+	c, err := NewConverter("cdc", "", nil)
+	require.NoError(t, err)
+
+	vgtid := &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{{Keyspace: "ks", Shard: "0", Gtid: "MySQL56/a:1-11"}}}
+	out, err := c.Convert(&binlogdatapb.VEvent{Type: binlogdatapb.VEventType_VGTID, Vgtid: vgtid})
+	require.NoError(t, err)
+	require.Empty(t, out)
+
+	out, err = c.Convert(&binlogdatapb.VEvent{
+		Type:      binlogdatapb.VEventType_DDL,
+		Keyspace:  "ks",
+		Shard:     "0",
+		Statement: "alter table product add column weight int",
+	})
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	ddl := out[0].(*SchemaChangeEvent)
+	assert.Equal(t, "ks", ddl.DatabaseName)
+	assert.Equal(t, "alter table product add column weight int", ddl.DDL)
+	assert.Contains(t, ddl.Source.Vgtid, "MySQL56/a:1-11")
+
+	_, err = c.Convert(rowEvent("ks", "0", &binlogdatapb.RowChange{}))
+	require.EqualError(t, err, "no field event received for table ks.product")
+}
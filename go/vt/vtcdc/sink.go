@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vtcdc converts the events of a VStream into Debezium-compatible
+// change envelopes, and writes them as JSON lines while checkpointing the
+// position of the stream.
+package vtcdc
+
+import (
+	"encoding/json"
+	"io"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+)
+
+// EventReader is the reader returned by vtgateconn.VStream.
+type EventReader interface {
+	Recv() ([]*binlogdatapb.VEvent, error)
+}
+
+// CheckpointFunc saves the position of the stream. timestamp is the commit
+// time of the last event before vgtid, in seconds, or zero if unknown.
+type CheckpointFunc func(vgtid *binlogdatapb.VGtid, timestamp int64) error
+
+// FileCheckpoint returns a CheckpointFunc saving the position of the stream
+// in path.
+func FileCheckpoint(path string) CheckpointFunc {
+	return func(vgtid *binlogdatapb.VGtid, _ int64) error {
+		return SaveCheckpoint(path, vgtid)
+	}
+}
+
+// Sink writes the envelopes of the events it reads, and saves the VGTID of
+// the stream once all the envelopes before it are flushed. Envelopes may be
+// written again after a restart, but are never lost.
+type Sink struct {
+	converter  *Converter
+	writer     Writer
+	encoder    *json.Encoder
+	checkpoint CheckpointFunc
+}
+
+// NewSink returns a Sink writing to writer. If checkpoint is nil, the
+// position of the stream is not saved.
+func NewSink(converter *Converter, writer Writer, checkpoint CheckpointFunc) *Sink {
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	return &Sink{
+		converter:  converter,
+		writer:     writer,
+		encoder:    encoder,
+		checkpoint: checkpoint,
+	}
+}
+
+// Run processes the events of reader until it ends or fails.
+func (s *Sink) Run(reader EventReader) error {
+	for {
+		events, err := reader.Recv()
+		if err != nil {
+			if flushErr := s.writer.Flush(); flushErr != nil {
+				return flushErr
+			}
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		for _, ev := range events {
+			if err := s.process(ev); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Sink) process(ev *binlogdatapb.VEvent) error {
+	envelopes, err := s.converter.Convert(ev)
+	if err != nil {
+		return err
+	}
+	for _, envelope := range envelopes {
+		if err := s.encoder.Encode(envelope); err != nil {
+			return err
+		}
+	}
+	if ev.Type != binlogdatapb.VEventType_VGTID || s.checkpoint == nil {
+		return nil
+	}
+	if err := s.writer.Flush(); err != nil {
+		return err
+	}
+	return s.checkpoint(ev.Vgtid, ev.Timestamp)
+}
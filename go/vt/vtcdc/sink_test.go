@@ -0,0 +1,148 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtcdc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+)
+
+type fakeReader struct {
+	batches [][]*binlogdatapb.VEvent
+	err     error
+}
+
+func (fr *fakeReader) Recv() ([]*binlogdatapb.VEvent, error) {
+	if len(fr.batches) == 0 {
+		return nil, fr.err
+	}
+	batch := fr.batches[0]
+	fr.batches = fr.batches[1:]
+	return batch, nil
+}
+
+func transaction(id int64, gtid string) []*binlogdatapb.VEvent {
+	row := sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(id), sqltypes.NewVarChar("name"), sqltypes.NULL, sqltypes.NULL})
+	return []*binlogdatapb.VEvent{
+		{Type: binlogdatapb.VEventType_BEGIN, Keyspace: "ks", Shard: "0"},
+		fieldEvent("ks", "0"),
+		rowEvent("ks", "0", &binlogdatapb.RowChange{After: row}),
+		{Type: binlogdatapb.VEventType_VGTID, Vgtid: &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{{Keyspace: "ks", Shard: "0", Gtid: gtid}}}},
+		{Type: binlogdatapb.VEventType_COMMIT, Keyspace: "ks", Shard: "0"},
+	}
+}
+
+func countLines(t *testing.T, data []byte) int {
+	lines := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lines++
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+func TestSinkWritesAndCheckpoints(t *testing.T) {
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+	vgtid, err := LoadCheckpoint(checkpoint)
+	require.NoError(t, err)
+	require.Nil(t, vgtid)
+
+	converter, err := NewConverter("cdc", "", vgtid)
+	require.NoError(t, err)
+	var out bytes.Buffer
+	reader := &fakeReader{
+		batches: [][]*binlogdatapb.VEvent{transaction(1, "MySQL56/a:1"), transaction(2, "MySQL56/a:1-2")},
+		err:     io.EOF,
+	}
+	require.NoError(t, NewSink(converter, NewStreamWriter(&out), FileCheckpoint(checkpoint)).Run(reader))
+	assert.Equal(t, 2, countLines(t, out.Bytes()))
+
+	vgtid, err = LoadCheckpoint(checkpoint)
+	require.NoError(t, err)
+	utils.MustMatch(t, &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{{Keyspace: "ks", Shard: "0", Gtid: "MySQL56/a:1-2"}}}, vgtid)
+
+	// the checkpoint is not moved past events that could not be written
+	converter, err = NewConverter("cdc", "", vgtid)
+	require.NoError(t, err)
+	reader = &fakeReader{
+		batches: [][]*binlogdatapb.VEvent{transaction(3, "MySQL56/a:1-3")[:3]},
+		err:     errors.New("stream broken"),
+	}
+	require.EqualError(t, NewSink(converter, NewStreamWriter(&out), FileCheckpoint(checkpoint)).Run(reader), "stream broken")
+	vgtid, err = LoadCheckpoint(checkpoint)
+	require.NoError(t, err)
+	assert.Equal(t, "MySQL56/a:1-2", vgtid.ShardGtids[0].Gtid)
+}
+
+func TestRotatingWriter(t *testing.T) {
+	dir := t.TempDir()
+	rw, err := NewRotatingWriter(dir, 100, time.Hour)
+	require.NoError(t, err)
+	now := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	rw.now = func() time.Time { return now }
+
+	line := []byte(`{"op":"c","after":{"id":1,"name":"some name"}}` + "\n")
+	for i := 0; i < 5; i++ {
+		_, err := rw.Write(line)
+		require.NoError(t, err)
+	}
+	// rotate on age
+	now = now.Add(time.Hour)
+	_, err = rw.Write(line)
+	require.NoError(t, err)
+	require.NoError(t, rw.Close())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{
+		"vtcdc-20231001T000000-000001.jsonl",
+		"vtcdc-20231001T000000-000002.jsonl",
+		"vtcdc-20231001T000000-000003.jsonl",
+		"vtcdc-20231001T010000-000004.jsonl",
+	}, names)
+
+	// lines are never split across files
+	total := 0
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		require.True(t, bytes.HasSuffix(data, []byte("\n")))
+		total += countLines(t, data)
+	}
+	assert.Equal(t, 6, total)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtcdc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Writer writes the envelopes as JSON lines.
+type Writer interface {
+	io.Writer
+	// Flush makes the written envelopes durable. The checkpoint is only
+	// saved once the envelopes of the events before it are flushed.
+	Flush() error
+	Close() error
+}
+
+type streamWriter struct {
+	w *bufio.Writer
+}
+
+// NewStreamWriter returns a Writer that writes to w, like stdout.
+func NewStreamWriter(w io.Writer) Writer {
+	return &streamWriter{w: bufio.NewWriter(w)}
+}
+
+func (sw *streamWriter) Write(p []byte) (int, error) {
+	return sw.w.Write(p)
+}
+
+func (sw *streamWriter) Flush() error {
+	return sw.w.Flush()
+}
+
+func (sw *streamWriter) Close() error {
+	return sw.w.Flush()
+}
+
+// RotatingWriter writes to files in a directory, starting a new file when
+// the current one exceeds a size or an age. The files are named after the
+// time they were created, so that sorting their names sorts the events.
+type RotatingWriter struct {
+	dir      string
+	maxSize  int64
+	maxAge   time.Duration
+	now      func() time.Time
+	file     *os.File
+	w        *bufio.Writer
+	size     int64
+	openedAt time.Time
+	seq      int
+}
+
+// NewRotatingWriter returns a RotatingWriter writing to dir. A maxSize or
+// maxAge of zero disables the rotation on size or age.
+func NewRotatingWriter(dir string, maxSize int64, maxAge time.Duration) (*RotatingWriter, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &RotatingWriter{
+		dir:     dir,
+		maxSize: maxSize,
+		maxAge:  maxAge,
+		now:     time.Now,
+	}, nil
+}
+
+// Write writes p to the current file, rotating it first if needed. It expects
+// p to be whole lines, so that an envelope is never split across files.
+func (rw *RotatingWriter) Write(p []byte) (int, error) {
+	if rw.file != nil && rw.shouldRotate(int64(len(p))) {
+		if err := rw.closeFile(); err != nil {
+			return 0, err
+		}
+	}
+	if rw.file == nil {
+		if err := rw.openFile(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := rw.w.Write(p)
+	rw.size += int64(n)
+	return n, err
+}
+
+func (rw *RotatingWriter) shouldRotate(n int64) bool {
+	if rw.maxSize > 0 && rw.size > 0 && rw.size+n > rw.maxSize {
+		return true
+	}
+	return rw.maxAge > 0 && rw.now().Sub(rw.openedAt) >= rw.maxAge
+}
+
+func (rw *RotatingWriter) openFile() error {
+	rw.openedAt = rw.now()
+	rw.seq++
+	name := fmt.Sprintf("vtcdc-%s-%06d.jsonl", rw.openedAt.UTC().Format("20060102T150405"), rw.seq)
+	file, err := os.OpenFile(filepath.Join(rw.dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	rw.file = file
+	rw.w = bufio.NewWriter(file)
+	rw.size = 0
+	return nil
+}
+
+func (rw *RotatingWriter) closeFile() error {
+	if err := rw.Flush(); err != nil {
+		return err
+	}
+	err := rw.file.Close()
+	rw.file = nil
+	rw.w = nil
+	return err
+}
+
+// Flush writes the buffered envelopes and syncs the current file.
+func (rw *RotatingWriter) Flush() error {
+	if rw.file == nil {
+		return nil
+	}
+	if err := rw.w.Flush(); err != nil {
+		return err
+	}
+	return rw.file.Sync()
+}
+
+// Close flushes and closes the current file.
+func (rw *RotatingWriter) Close() error {
+	if rw.file == nil {
+		return nil
+	}
+	return rw.closeFile()
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcvtctldserver
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// maxDeadMessagesPerShard caps the number of dead messages read from, or
+// requeued on, a single shard by one request.
+const maxDeadMessagesPerShard = 10_000
+
+// deadMessageTable describes where the dead messages of a message table
+// are kept on a tablet. If deadLetterTable is empty, dead messages stay in
+// the message table, marked failed by a NULL time_next.
+type deadMessageTable struct {
+	table           sqlparser.IdentifierCS
+	deadLetterTable sqlparser.IdentifierCS
+	columns         []string
+}
+
+// getDeadMessageTable reads the definition of the message table from the
+// tablet and extracts its dead-letter settings from the table comment.
+func (s *VtctldServer) getDeadMessageTable(ctx context.Context, tablet *topodatapb.Tablet, table string) (*deadMessageTable, error) {
+	sd, err := s.tmc.GetSchema(ctx, tablet, &tabletmanagerdatapb.GetSchemaRequest{Tables: []string{table}})
+	if err != nil {
+		return nil, err
+	}
+
+	var td *tabletmanagerdatapb.TableDefinition
+	for _, def := range sd.TableDefinitions {
+		if def.Name == table {
+			td = def
+			break
+		}
+	}
+	if td == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found on tablet %s", table, topoproto.TabletAliasString(tablet.Alias))
+	}
+
+	stmt, err := sqlparser.ParseStrictDDL(td.Schema)
+	if err != nil {
+		return nil, err
+	}
+	create, ok := stmt.(*sqlparser.CreateTable)
+	if !ok || create.TableSpec == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s is not a message table", table)
+	}
+
+	var comment string
+	for _, opt := range create.TableSpec.Options {
+		if strings.EqualFold(opt.Name, "comment") && opt.Value != nil {
+			comment = opt.Value.Val
+		}
+	}
+	if !strings.HasPrefix(comment, "vitess_message") {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s is not a message table", table)
+	}
+
+	// This mirrors how the tablet parses the message table comment.
+	keyvals := make(map[string]string)
+	for _, input := range strings.Split(comment, ",") {
+		kv := strings.Split(input, "=")
+		if len(kv) != 2 {
+			continue
+		}
+		keyvals[kv[0]] = kv[1]
+	}
+	if keyvals["vt_max_attempts"] == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "message table %s does not set vt_max_attempts, so it has no dead messages", table)
+	}
+
+	return &deadMessageTable{
+		table:           sqlparser.NewIdentifierCS(table),
+		deadLetterTable: sqlparser.NewIdentifierCS(keyvals["vt_dead_letter_table"]),
+		columns:         td.Columns,
+	}, nil
+}
+
+// idsBindVariable returns the tuple bind variable for the given message ids.
+func idsBindVariable(ids []string) map[string]*querypb.BindVariable {
+	bv := &querypb.BindVariable{Type: querypb.Type_TUPLE}
+	for _, id := range ids {
+		bv.Values = append(bv.Values, &querypb.Value{Type: querypb.Type_VARBINARY, Value: []byte(id)})
+	}
+	return map[string]*querypb.BindVariable{"ids": bv}
+}
+
+// selectQuery returns the query that reads up to limit dead messages.
+func (dmt *deadMessageTable) selectQuery(limit uint64) string {
+	if dmt.deadLetterTable.IsEmpty() {
+		return sqlparser.BuildParsedQuery("select * from %v where time_acked is null and time_next is null limit %d",
+			dmt.table, limit).Query
+	}
+	return sqlparser.BuildParsedQuery("select * from %v limit %d", dmt.deadLetterTable, limit).Query
+}
+
+// selectIDsQuery returns the query that reads the ids of up to limit dead
+// messages.
+func (dmt *deadMessageTable) selectIDsQuery(limit uint64) string {
+	if dmt.deadLetterTable.IsEmpty() {
+		return sqlparser.BuildParsedQuery("select id from %v where time_acked is null and time_next is null limit %d",
+			dmt.table, limit).Query
+	}
+	return sqlparser.BuildParsedQuery("select id from %v limit %d", dmt.deadLetterTable, limit).Query
+}
+
+// purgeQuery returns the query that deletes the dead messages with the
+// given ids, or all of them if ids is empty.
+func (dmt *deadMessageTable) purgeQuery(ids []string) (string, error) {
+	var pq *sqlparser.ParsedQuery
+	switch {
+	case dmt.deadLetterTable.IsEmpty() && len(ids) == 0:
+		pq = sqlparser.BuildParsedQuery("delete from %v where time_acked is null and time_next is null", dmt.table)
+	case dmt.deadLetterTable.IsEmpty():
+		pq = sqlparser.BuildParsedQuery("delete from %v where time_acked is null and time_next is null and id in %a", dmt.table, "::ids")
+	case len(ids) == 0:
+		pq = sqlparser.BuildParsedQuery("delete from %v", dmt.deadLetterTable)
+	default:
+		pq = sqlparser.BuildParsedQuery("delete from %v where id in %a", dmt.deadLetterTable, "::ids")
+	}
+	return pq.GenerateQuery(idsBindVariable(ids), nil)
+}
+
+// requeueQueries returns the queries that make the dead messages with the
+// given ids deliverable again, with time_next and epoch reset. They must be
+// executed in order. Messages are copied back before being deleted from
+// the dead letter table, so a failure in between cannot lose them.
+func (dmt *deadMessageTable) requeueQueries(ids []string) ([]string, error) {
+	bvs := idsBindVariable(ids)
+	if dmt.deadLetterTable.IsEmpty() {
+		query, err := sqlparser.BuildParsedQuery(
+			"update %v set time_next = 0, epoch = 0 where time_acked is null and time_next is null and id in %a",
+			dmt.table, "::ids").GenerateQuery(bvs, nil)
+		if err != nil {
+			return nil, err
+		}
+		return []string{query}, nil
+	}
+
+	columns := sqlparser.NewTrackedBuffer(nil)
+	values := sqlparser.NewTrackedBuffer(nil)
+	for i, col := range dmt.columns {
+		if i > 0 {
+			columns.WriteString(", ")
+			values.WriteString(", ")
+		}
+		columns.Myprintf("%v", sqlparser.NewIdentifierCI(col))
+		switch strings.ToLower(col) {
+		case "time_next", "epoch":
+			values.WriteString("0")
+		default:
+			values.Myprintf("%v", sqlparser.NewIdentifierCI(col))
+		}
+	}
+	insert, err := sqlparser.BuildParsedQuery("insert into %v (%s) select %s from %v where id in %a",
+		dmt.table, columns.String(), values.String(), dmt.deadLetterTable, "::ids").GenerateQuery(bvs, nil)
+	if err != nil {
+		return nil, err
+	}
+	del, err := sqlparser.BuildParsedQuery("delete from %v where id in %a",
+		dmt.deadLetterTable, "::ids").GenerateQuery(bvs, nil)
+	if err != nil {
+		return nil, err
+	}
+	return []string{insert, del}, nil
+}
+
+// forEachMessagePrimary runs f concurrently against the primary tablet of
+// every shard in the keyspace, along with its view of the message table.
+func (s *VtctldServer) forEachMessagePrimary(ctx context.Context, keyspace string, table string, f func(tablet *topodatapb.Tablet, dmt *deadMessageTable) error) error {
+	tabletsResp, err := s.GetTablets(ctx, &vtctldatapb.GetTabletsRequest{
+		Keyspace:   keyspace,
+		TabletType: topodatapb.TabletType_PRIMARY,
+	})
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg  sync.WaitGroup
+		rec concurrency.AllErrorRecorder
+	)
+	for _, tablet := range tabletsResp.Tablets {
+		wg.Add(1)
+		go func(tablet *topodatapb.Tablet) {
+			defer wg.Done()
+
+			dmt, err := s.getDeadMessageTable(ctx, tablet, table)
+			if err != nil {
+				rec.RecordError(err)
+				return
+			}
+			if err := f(tablet, dmt); err != nil {
+				rec.RecordError(err)
+			}
+		}(tablet)
+	}
+	wg.Wait()
+
+	return rec.Error()
+}
+
+// executeDeadMessageQuery runs the query as DBA on the tablet.
+func (s *VtctldServer) executeDeadMessageQuery(ctx context.Context, tablet *topodatapb.Tablet, query string) (*sqltypes.Result, error) {
+	resp, err := s.ExecuteFetchAsDBA(ctx, &vtctldatapb.ExecuteFetchAsDBARequest{
+		TabletAlias: tablet.Alias,
+		Query:       query,
+		MaxRows:     maxDeadMessagesPerShard,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sqltypes.Proto3ToResult(resp.Result), nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcvtctldserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestDeadMessageTableQueries(t *testing.T) {
+	t.Parallel()
+
+	markFailed := &deadMessageTable{
+		table:   sqlparser.NewIdentifierCS("msg"),
+		columns: []string{"id", "priority", "time_next", "epoch", "time_acked", "message"},
+	}
+	deadLetter := &deadMessageTable{
+		table:           sqlparser.NewIdentifierCS("msg"),
+		deadLetterTable: sqlparser.NewIdentifierCS("msg_dlq"),
+		columns:         markFailed.columns,
+	}
+
+	assert.Equal(t, "select * from msg where time_acked is null and time_next is null limit 10", markFailed.selectQuery(10))
+	assert.Equal(t, "select * from msg_dlq limit 10", deadLetter.selectQuery(10))
+	assert.Equal(t, "select id from msg where time_acked is null and time_next is null limit 10", markFailed.selectIDsQuery(10))
+	assert.Equal(t, "select id from msg_dlq limit 10", deadLetter.selectIDsQuery(10))
+
+	query, err := markFailed.purgeQuery(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "delete from msg where time_acked is null and time_next is null", query)
+	query, err = markFailed.purgeQuery([]string{"1", "2"})
+	require.NoError(t, err)
+	assert.Equal(t, "delete from msg where time_acked is null and time_next is null and id in ('1', '2')", query)
+	query, err = deadLetter.purgeQuery(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "delete from msg_dlq", query)
+	query, err = deadLetter.purgeQuery([]string{"1"})
+	require.NoError(t, err)
+	assert.Equal(t, "delete from msg_dlq where id in ('1')", query)
+
+	queries, err := markFailed.requeueQueries([]string{"1", "2"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"update msg set time_next = 0, epoch = 0 where time_acked is null and time_next is null and id in ('1', '2')",
+	}, queries)
+	queries, err = deadLetter.requeueQueries([]string{"1", "2"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"insert into msg (id, priority, time_next, epoch, time_acked, message) select id, priority, 0, 0, time_acked, message from msg_dlq where id in ('1', '2')",
+		"delete from msg_dlq where id in ('1', '2')",
+	}, queries)
+}
@@ -28,6 +28,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/sync/semaphore"
@@ -42,6 +43,7 @@ import (
 	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/dtids"
 	hk "vitess.io/vitess/go/vt/hook"
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/log"
@@ -51,6 +53,7 @@ import (
 	"vitess.io/vitess/go/vt/mysqlctl/mysqlctlproto"
 	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
 	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/schemalint"
 	"vitess.io/vitess/go/vt/schemamanager"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo"
@@ -62,6 +65,7 @@ import (
 	"vitess.io/vitess/go/vt/vtctl/workflow"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
 	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
@@ -228,6 +232,7 @@ func (s *VtctldServer) ApplySchema(ctx context.Context, req *vtctldatapb.ApplySc
 
 	span.Annotate("keyspace", req.Keyspace)
 	span.Annotate("ddl_strategy", req.DdlStrategy)
+	span.Annotate("lint", req.Lint)
 
 	if len(req.Sql) == 0 {
 		err = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "Sql must be a non-empty array")
@@ -282,6 +287,15 @@ func (s *VtctldServer) ApplySchema(ctx context.Context, req *vtctldatapb.ApplySc
 		}
 	}
 
+	if req.Lint {
+		var linter *schemalint.Linter
+		if linter, err = schemalint.NewLinter(req.LintRules); err != nil {
+			err = vterrors.Wrapf(err, "invalid LintRules: %s", req.LintRules)
+			return resp, err
+		}
+		executor.SetLinter(linter)
+	}
+
 	execResult, err := schemamanager.Run(
 		ctx,
 		schemamanager.NewPlainController(req.Sql, req.Keyspace),
@@ -295,6 +309,7 @@ func (s *VtctldServer) ApplySchema(ctx context.Context, req *vtctldatapb.ApplySc
 	resp = &vtctldatapb.ApplySchemaResponse{
 		UuidList:            execResult.UUIDs,
 		RowsAffectedByShard: make(map[string]uint64, len(execResult.SuccessShards)),
+		LintFindings:        lintFindingsToProto(execResult.LintFindings),
 	}
 
 	for _, shard := range execResult.SuccessShards {
@@ -1063,6 +1078,22 @@ func (s *VtctldServer) DeleteTablets(ctx context.Context, req *vtctldatapb.Delet
 	return &vtctldatapb.DeleteTabletsResponse{}, nil
 }
 
+// DeleteVStreamSubscription is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) DeleteVStreamSubscription(ctx context.Context, req *vtctldatapb.DeleteVStreamSubscriptionRequest) (resp *vtctldatapb.DeleteVStreamSubscriptionResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.DeleteVStreamSubscription")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("name", req.Name)
+
+	if err = s.ts.DeleteVStreamSubscription(ctx, req.Name); err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.DeleteVStreamSubscriptionResponse{}, nil
+}
+
 // EmergencyReparentShard is part of the vtctldservicepb.VtctldServer interface.
 func (s *VtctldServer) EmergencyReparentShard(ctx context.Context, req *vtctldatapb.EmergencyReparentShardRequest) (resp *vtctldatapb.EmergencyReparentShardResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.EmergencyReparentShard")
@@ -1256,6 +1287,36 @@ func (s *VtctldServer) FindAllShardsInKeyspace(ctx context.Context, req *vtctlda
 	}, nil
 }
 
+// GetBackupRetentionPolicy is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetBackupRetentionPolicy(ctx context.Context, req *vtctldatapb.GetBackupRetentionPolicyRequest) (resp *vtctldatapb.GetBackupRetentionPolicyResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetBackupRetentionPolicy")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+
+	resp = &vtctldatapb.GetBackupRetentionPolicyResponse{}
+	resp.Policy, err = s.ts.GetBackupRetentionPolicy(ctx, req.Keyspace, req.Shard)
+	if topo.IsErrType(err, topo.NoNode) && req.Shard != "" {
+		resp.Inherited = true
+		resp.Policy, err = s.ts.GetBackupRetentionPolicy(ctx, req.Keyspace, "")
+	}
+	if topo.IsErrType(err, topo.NoNode) {
+		if req.Shard == "" {
+			err = vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "no backup retention policy is set for keyspace %s", req.Keyspace)
+		} else {
+			err = vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "no backup retention policy is set for %s or its keyspace", topoproto.KeyspaceShardString(req.Keyspace, req.Shard))
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 // GetBackups is part of the vtctldservicepb.VtctldServer interface.
 func (s *VtctldServer) GetBackups(ctx context.Context, req *vtctldatapb.GetBackupsRequest) (resp *vtctldatapb.GetBackupsResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetBackups")
@@ -1377,6 +1438,47 @@ func (s *VtctldServer) GetCellsAliases(ctx context.Context, req *vtctldatapb.Get
 	return &vtctldatapb.GetCellsAliasesResponse{Aliases: aliases}, nil
 }
 
+// GetDeadMessages is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetDeadMessages(ctx context.Context, req *vtctldatapb.GetDeadMessagesRequest) (resp *vtctldatapb.GetDeadMessagesResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetDeadMessages")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("table", req.Table)
+	span.Annotate("limit", req.Limit)
+
+	limit := req.Limit
+	if limit == 0 || limit > maxDeadMessagesPerShard {
+		limit = maxDeadMessagesPerShard
+	}
+
+	var (
+		m       sync.Mutex
+		results = map[string]*sqltypes.Result{}
+	)
+	err = s.forEachMessagePrimary(ctx, req.Keyspace, req.Table, func(tablet *topodatapb.Tablet, dmt *deadMessageTable) error {
+		qr, err := s.executeDeadMessageQuery(ctx, tablet, dmt.selectQuery(limit))
+		if err != nil {
+			return err
+		}
+
+		m.Lock()
+		defer m.Unlock()
+
+		results[topoproto.TabletAliasString(tablet.Alias)] = qr
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.GetDeadMessagesResponse{
+		Result: sqltypes.ResultToProto3(queryResultForTabletResults(results)),
+	}, nil
+}
+
 // GetFullStatus is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetFullStatus(ctx context.Context, req *vtctldatapb.GetFullStatusRequest) (resp *vtctldatapb.GetFullStatusResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetFullStatus")
@@ -2152,6 +2254,67 @@ func (s *VtctldServer) GetTopologyPath(ctx context.Context, req *vtctldatapb.Get
 	}, nil
 }
 
+// GetUnresolvedTransactions is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetUnresolvedTransactions(ctx context.Context, req *vtctldatapb.GetUnresolvedTransactionsRequest) (resp *vtctldatapb.GetUnresolvedTransactionsResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetUnresolvedTransactions")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("abandon_age", req.AbandonAge)
+
+	tabletsResp, err := s.GetTablets(ctx, &vtctldatapb.GetTabletsRequest{
+		Keyspace:   req.Keyspace,
+		TabletType: topodatapb.TabletType_PRIMARY,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		m            sync.Mutex
+		wg           sync.WaitGroup
+		rec          concurrency.AllErrorRecorder
+		transactions []*querypb.TransactionMetadata
+	)
+	for _, tablet := range tabletsResp.Tablets {
+		wg.Add(1)
+		go func(tablet *topodatapb.Tablet) {
+			defer wg.Done()
+
+			err := s.withTabletConn(ctx, tablet, func(conn queryservice.QueryService) error {
+				txs, err := conn.UnresolvedTransactions(ctx, primaryTarget(tablet.Keyspace, tablet.Shard), req.AbandonAge)
+				if err != nil {
+					return err
+				}
+
+				m.Lock()
+				defer m.Unlock()
+
+				transactions = append(transactions, txs...)
+				return nil
+			})
+			if err != nil {
+				rec.RecordError(err)
+			}
+		}(tablet)
+	}
+	wg.Wait()
+
+	if rec.HasErrors() {
+		return nil, rec.Error()
+	}
+
+	sort.Slice(transactions, func(i, j int) bool {
+		return transactions[i].Dtid < transactions[j].Dtid
+	})
+
+	return &vtctldatapb.GetUnresolvedTransactionsResponse{
+		Transactions: transactions,
+	}, nil
+}
+
 // GetVersion returns the version of a tablet from its debug vars
 func (s *VtctldServer) GetVersion(ctx context.Context, req *vtctldatapb.GetVersionRequest) (resp *vtctldatapb.GetVersionResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetVersion")
@@ -2192,6 +2355,43 @@ func (s *VtctldServer) GetVSchema(ctx context.Context, req *vtctldatapb.GetVSche
 	}, nil
 }
 
+// GetVStreamSubscriptions is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetVStreamSubscriptions(ctx context.Context, req *vtctldatapb.GetVStreamSubscriptionsRequest) (resp *vtctldatapb.GetVStreamSubscriptionsResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetVStreamSubscriptions")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("names", strings.Join(req.Names, ","))
+
+	names := req.Names
+	if len(names) == 0 {
+		names, err = s.ts.GetVStreamSubscriptionNames(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	subscriptions := make([]*vtctldatapb.VStreamSubscription, 0, len(names))
+	for _, name := range names {
+		si, err := s.ts.GetVStreamSubscription(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		subscription := si.VStreamSubscription
+		subscription.LagSeconds = -1
+		if subscription.EventTime != nil {
+			subscription.LagSeconds = int64(now.Sub(protoutil.TimeFromProto(subscription.EventTime)).Seconds())
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return &vtctldatapb.GetVStreamSubscriptionsResponse{
+		Subscriptions: subscriptions,
+	}, nil
+}
+
 // GetWorkflows is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetWorkflows(ctx context.Context, req *vtctldatapb.GetWorkflowsRequest) (resp *vtctldatapb.GetWorkflowsResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetWorkflows")
@@ -2516,6 +2716,91 @@ func (s *VtctldServer) LaunchSchemaMigration(ctx context.Context, req *vtctldata
 	return resp, nil
 }
 
+// LintSchema is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) LintSchema(ctx context.Context, req *vtctldatapb.LintSchemaRequest) (resp *vtctldatapb.LintSchemaResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.LintSchema")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("rules", req.Rules)
+
+	linter, err := schemalint.NewLinter(req.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	stmts := make([]sqlparser.Statement, 0, len(req.Sql))
+	for _, sql := range req.Sql {
+		stmt, err := sqlparser.Parse(sql)
+		if err != nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "failed to parse sql: %s, got error: %v", sql, err)
+		}
+		stmts = append(stmts, stmt)
+	}
+
+	// The schema is the same on all shards, so we read it from the first shard with a primary.
+	shards, err := s.ts.GetShardNames(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+	var primary *topodatapb.TabletAlias
+	for _, shard := range shards {
+		si, err := s.ts.GetShard(ctx, req.Keyspace, shard)
+		if err != nil {
+			return nil, err
+		}
+		if si.HasPrimary() {
+			primary = si.PrimaryAlias
+			break
+		}
+	}
+	if primary == nil {
+		err = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "keyspace %s does not have any shard with a primary", req.Keyspace)
+		return nil, err
+	}
+
+	sd, err := schematools.GetSchema(ctx, s.ts, s.tmc, primary, &tabletmanagerdatapb.GetSchemaRequest{IncludeViews: true, TableSchemaOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	currentSchema, err := schemalint.NewSchemaFromDefinition(sd)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &schemalint.Env{Keyspace: req.Keyspace}
+	if env.VSchema, err = s.ts.GetVSchema(ctx, req.Keyspace); err != nil && !topo.IsErrType(err, topo.NoNode) {
+		return nil, err
+	}
+
+	var findings schemalint.Findings
+	if len(stmts) == 0 {
+		findings = linter.LintSchema(env, currentSchema)
+	} else if findings, err = linter.LintStatements(env, currentSchema, stmts); err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.LintSchemaResponse{Findings: lintFindingsToProto(findings)}, nil
+}
+
+func lintFindingsToProto(findings schemalint.Findings) []*vtctldatapb.LintFinding {
+	if len(findings) == 0 {
+		return nil
+	}
+	pbFindings := make([]*vtctldatapb.LintFinding, 0, len(findings))
+	for _, finding := range findings {
+		pbFindings = append(pbFindings, &vtctldatapb.LintFinding{
+			Rule:     finding.Rule,
+			Severity: finding.Severity.String(),
+			Entity:   finding.Entity,
+			Message:  finding.Message,
+		})
+	}
+	return pbFindings
+}
+
 // LookupVindexCreate is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) LookupVindexCreate(ctx context.Context, req *vtctldatapb.LookupVindexCreateRequest) (resp *vtctldatapb.LookupVindexCreateResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.LookupVindexCreate")
@@ -2760,6 +3045,114 @@ func (s *VtctldServer) PlannedReparentShard(ctx context.Context, req *vtctldatap
 	return resp, err
 }
 
+// PruneBackups is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) PruneBackups(ctx context.Context, req *vtctldatapb.PruneBackupsRequest) (resp *vtctldatapb.PruneBackupsResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.PruneBackups")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	bucket := mysqlctl.GetBackupDir(req.Keyspace, req.Shard)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("bucket", bucket)
+	span.Annotate("dry_run", req.DryRun)
+
+	policypb := req.Policy
+	if policypb == nil {
+		policypb, err = s.ts.FindBackupRetentionPolicy(ctx, req.Keyspace, req.Shard)
+		if topo.IsErrType(err, topo.NoNode) {
+			err = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no backup retention policy was given, and none is set for %s or its keyspace", topoproto.KeyspaceShardString(req.Keyspace, req.Shard))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	policy, err := validateBackupRetentionPolicy(policypb)
+	if err != nil {
+		return nil, err
+	}
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+
+	plan, err := mysqlctl.PruneBackups(ctx, logutil.NewConsoleLogger(), bs, bucket, policy, req.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.PruneBackupsResponse{}
+	for _, bh := range plan.Pruned {
+		bi := mysqlctlproto.BackupHandleToProto(bh)
+		bi.Keyspace = req.Keyspace
+		bi.Shard = req.Shard
+		resp.RemovedBackups = append(resp.RemovedBackups, bi)
+	}
+	for _, bh := range plan.Retained {
+		bi := mysqlctlproto.BackupHandleToProto(bh)
+		bi.Keyspace = req.Keyspace
+		bi.Shard = req.Shard
+		resp.RetainedBackups = append(resp.RetainedBackups, bi)
+	}
+
+	return resp, nil
+}
+
+// validateBackupRetentionPolicy returns the mysqlctl.BackupRetentionPolicy
+// of a proto policy, or an error if the policy is inconsistent or has no
+// rule set. An empty policy would prune all but the most recent full backup,
+// which is never the intent of a policy that was left unset.
+func validateBackupRetentionPolicy(policypb *mysqlctlpb.BackupRetentionPolicy) (*mysqlctl.BackupRetentionPolicy, error) {
+	policy, err := mysqlctlproto.BackupRetentionPolicyFromProto(policypb)
+	if err != nil {
+		return nil, err
+	}
+	if policy.IsEmpty() {
+		return nil, vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "the backup retention policy has no rule set")
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// PurgeDeadMessages is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) PurgeDeadMessages(ctx context.Context, req *vtctldatapb.PurgeDeadMessagesRequest) (resp *vtctldatapb.PurgeDeadMessagesResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.PurgeDeadMessages")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("table", req.Table)
+	span.Annotate("ids", strings.Join(req.Ids, ","))
+
+	var count atomic.Uint64
+	err = s.forEachMessagePrimary(ctx, req.Keyspace, req.Table, func(tablet *topodatapb.Tablet, dmt *deadMessageTable) error {
+		query, err := dmt.purgeQuery(req.Ids)
+		if err != nil {
+			return err
+		}
+		qr, err := s.executeDeadMessageQuery(ctx, tablet, query)
+		if err != nil {
+			return err
+		}
+
+		count.Add(qr.RowsAffected)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.PurgeDeadMessagesResponse{Count: count.Load()}, nil
+}
+
 // RebuildKeyspaceGraph is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) RebuildKeyspaceGraph(ctx context.Context, req *vtctldatapb.RebuildKeyspaceGraphRequest) (resp *vtctldatapb.RebuildKeyspaceGraphResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.RebuildKeyspaceGraph")
@@ -3129,6 +3522,57 @@ func (s *VtctldServer) ReparentTablet(ctx context.Context, req *vtctldatapb.Repa
 	}, nil
 }
 
+// RequeueDeadMessages is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) RequeueDeadMessages(ctx context.Context, req *vtctldatapb.RequeueDeadMessagesRequest) (resp *vtctldatapb.RequeueDeadMessagesResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.RequeueDeadMessages")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("table", req.Table)
+	span.Annotate("ids", strings.Join(req.Ids, ","))
+
+	var count atomic.Uint64
+	err = s.forEachMessagePrimary(ctx, req.Keyspace, req.Table, func(tablet *topodatapb.Tablet, dmt *deadMessageTable) error {
+		ids := req.Ids
+		if len(ids) == 0 {
+			// Resolve the ids up front, so that messages dead-lettered
+			// while we requeue are not deleted without being requeued.
+			qr, err := s.executeDeadMessageQuery(ctx, tablet, dmt.selectIDsQuery(maxDeadMessagesPerShard))
+			if err != nil {
+				return err
+			}
+			for _, row := range qr.Rows {
+				ids = append(ids, row[0].ToString())
+			}
+			if len(ids) == 0 {
+				return nil
+			}
+		}
+
+		queries, err := dmt.requeueQueries(ids)
+		if err != nil {
+			return err
+		}
+		for i, query := range queries {
+			qr, err := s.executeDeadMessageQuery(ctx, tablet, query)
+			if err != nil {
+				return err
+			}
+			if i == 0 {
+				count.Add(qr.RowsAffected)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.RequeueDeadMessagesResponse{Count: count.Load()}, nil
+}
+
 // ReshardCreate is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ReshardCreate(ctx context.Context, req *vtctldatapb.ReshardCreateRequest) (resp *vtctldatapb.WorkflowStatusResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ReshardCreate")
@@ -3147,6 +3591,75 @@ func (s *VtctldServer) ReshardCreate(ctx context.Context, req *vtctldatapb.Resha
 	resp, err = s.ws.ReshardCreate(ctx, req)
 	return resp, err
 }
+
+// ResolveTransaction is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ResolveTransaction(ctx context.Context, req *vtctldatapb.ResolveTransactionRequest) (resp *vtctldatapb.ResolveTransactionResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ResolveTransaction")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("dtid", req.Dtid)
+	span.Annotate("action", req.Action.String())
+
+	abandonAge := req.AbandonAge
+	if abandonAge <= 0 {
+		abandonAge = defaultResolveTransactionAbandonAge
+	}
+	span.Annotate("abandon_age", abandonAge)
+
+	mmShard, err := dtids.ShardSession(req.Dtid)
+	if err != nil {
+		return nil, err
+	}
+
+	// The metadata of the transaction is kept on the primary of the shard
+	// that created it, and all the steps of its resolution go through it.
+	err = s.withShardPrimaryConn(ctx, mmShard.Target, func(mm queryservice.QueryService) error {
+		// Only the transactions abandoned by vtgate are resolved, so the
+		// transaction is read through the list of unresolved transactions
+		// older than the abandon age.
+		transaction, err := findUnresolvedTransaction(ctx, mm, mmShard.Target, req.Dtid, abandonAge)
+		if err != nil {
+			return err
+		}
+		if err := checkResolveAction(transaction, req.Action); err != nil {
+			return err
+		}
+
+		resp = &vtctldatapb.ResolveTransactionResponse{State: transaction.State}
+		switch transaction.State {
+		case querypb.TransactionState_PREPARE:
+			// Record the decision to roll back first, so that nothing can
+			// commit the transaction while it is being rolled back.
+			if err := mm.SetRollback(ctx, mmShard.Target, req.Dtid, mmShard.TransactionId); err != nil {
+				return err
+			}
+			fallthrough
+		case querypb.TransactionState_ROLLBACK:
+			err = s.forEachParticipant(ctx, transaction, func(conn queryservice.QueryService, target *querypb.Target) error {
+				return conn.RollbackPrepared(ctx, target, req.Dtid, 0)
+			})
+		case querypb.TransactionState_COMMIT:
+			// Only a transaction whose commit decision was recorded is rolled forward.
+			err = s.forEachParticipant(ctx, transaction, func(conn queryservice.QueryService, target *querypb.Target) error {
+				return conn.CommitPrepared(ctx, target, req.Dtid)
+			})
+		default:
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "transaction %s is in invalid state %v", req.Dtid, transaction.State)
+		}
+		if err != nil {
+			return err
+		}
+
+		return mm.ConcludeTransaction(ctx, mmShard.Target, req.Dtid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
 func (s *VtctldServer) RestoreFromBackup(req *vtctldatapb.RestoreFromBackupRequest, stream vtctlservicepb.Vtctld_RestoreFromBackupServer) (err error) {
 	span, ctx := trace.NewSpan(stream.Context(), "VtctldServer.RestoreFromBackup")
 	defer span.Finish()
@@ -3277,6 +3790,56 @@ func (s *VtctldServer) RunHealthCheck(ctx context.Context, req *vtctldatapb.RunH
 	return &vtctldatapb.RunHealthCheckResponse{}, nil
 }
 
+// SetBackupRetentionPolicy is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) SetBackupRetentionPolicy(ctx context.Context, req *vtctldatapb.SetBackupRetentionPolicyRequest) (resp *vtctldatapb.SetBackupRetentionPolicyResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetBackupRetentionPolicy")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("clear", req.Clear)
+
+	if req.Shard == "" {
+		_, err = s.ts.GetKeyspace(ctx, req.Keyspace)
+	} else {
+		_, err = s.ts.GetShard(ctx, req.Keyspace, req.Shard)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Clear {
+		if req.Policy != nil {
+			err = vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "cannot both set and clear a backup retention policy")
+			return nil, err
+		}
+
+		err = s.ts.DeleteBackupRetentionPolicy(ctx, req.Keyspace, req.Shard)
+		if err != nil && !topo.IsErrType(err, topo.NoNode) {
+			return nil, err
+		}
+
+		return &vtctldatapb.SetBackupRetentionPolicyResponse{}, nil
+	}
+
+	if req.Policy == nil {
+		err = vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "a backup retention policy is required")
+		return nil, err
+	}
+
+	if _, err = validateBackupRetentionPolicy(req.Policy); err != nil {
+		return nil, err
+	}
+
+	if err = s.ts.SaveBackupRetentionPolicy(ctx, req.Keyspace, req.Shard, req.Policy); err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.SetBackupRetentionPolicyResponse{}, nil
+}
+
 // SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) SetKeyspaceDurabilityPolicy(ctx context.Context, req *vtctldatapb.SetKeyspaceDurabilityPolicyRequest) (resp *vtctldatapb.SetKeyspaceDurabilityPolicyResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetKeyspaceDurabilityPolicy")
@@ -4895,6 +5458,61 @@ func (s *VtctldServer) VDiffStop(ctx context.Context, req *vtctldatapb.VDiffStop
 	return resp, err
 }
 
+// VerifyBackup is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) VerifyBackup(req *vtctldatapb.VerifyBackupRequest, stream vtctlservicepb.Vtctld_VerifyBackupServer) (err error) {
+	span, ctx := trace.NewSpan(stream.Context(), "VtctldServer.VerifyBackup")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("tablet_alias", topoproto.TabletAliasString(req.TabletAlias))
+	span.Annotate("backup_name", req.BackupName)
+
+	ti, err := s.ts.GetTablet(ctx, req.TabletAlias)
+	if err != nil {
+		return err
+	}
+
+	span.Annotate("keyspace", ti.Keyspace)
+	span.Annotate("shard", ti.Shard)
+
+	r := &tabletmanagerdatapb.VerifyBackupRequest{
+		BackupName:  req.BackupName,
+		Concurrency: req.Concurrency,
+	}
+	tmStream, err := s.tmc.VerifyBackup(ctx, ti.Tablet, r)
+	if err != nil {
+		return err
+	}
+
+	logger := logutil.NewConsoleLogger()
+
+	for {
+		var tmResp *tabletmanagerdatapb.VerifyBackupResponse
+		tmResp, err = tmStream.Recv()
+		switch err {
+		case nil:
+			if tmResp.Event != nil {
+				logutil.LogEvent(logger, tmResp.Event)
+			}
+			resp := &vtctldatapb.VerifyBackupResponse{
+				TabletAlias:  req.TabletAlias,
+				Keyspace:     ti.Keyspace,
+				Shard:        ti.Shard,
+				Event:        tmResp.Event,
+				Verification: tmResp.Verification,
+			}
+			if err = stream.Send(resp); err != nil {
+				logger.Errorf("failed to send stream response %+v: %v", resp, err)
+			}
+		case io.EOF:
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
 // WorkflowDelete is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) WorkflowDelete(ctx context.Context, req *vtctldatapb.WorkflowDeleteRequest) (resp *vtctldatapb.WorkflowDeleteResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.WorkflowDelete")
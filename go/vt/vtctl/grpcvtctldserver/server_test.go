@@ -24,6 +24,7 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -33,10 +34,12 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/grpcclient"
 	hk "vitess.io/vitess/go/vt/hook"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
 	"vitess.io/vitess/go/vt/proto/vttime"
@@ -46,9 +49,15 @@ import (
 	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver/testutil"
 	"vitess.io/vitess/go/vt/vtctl/localvtctldclient"
 	"vitess.io/vitess/go/vt/vtctl/schematools"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
+	"vitess.io/vitess/go/vt/vttablet/sandboxconn"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+	"vitess.io/vitess/go/vt/vttablet/tabletconntest"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 	"vitess.io/vitess/go/vt/vttablet/tmclienttest"
 
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
 	mysqlctlpb "vitess.io/vitess/go/vt/proto/mysqlctl"
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -58,6 +67,7 @@ import (
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 func init() {
@@ -74,6 +84,31 @@ func init() {
 	tmclient.RegisterTabletManagerClientFactory("grpcvtctldserver.test", func() tmclient.TabletManagerClient {
 		return nil
 	})
+
+	// Tests that talk to the query service of tablets register their
+	// connections in testTabletConns.
+	tabletconntest.SetProtocol("go.vt.vtctl.grpcvtctldserver", "grpcvtctldserver.test")
+	tabletconn.RegisterDialer("grpcvtctldserver.test", func(tablet *topodatapb.Tablet, failFast grpcclient.FailFast) (queryservice.QueryService, error) {
+		conn, ok := testTabletConns.Load(tablet.Keyspace + "/" + tablet.Shard)
+		if !ok {
+			return nil, fmt.Errorf("no connection for tablet %s", topoproto.TabletAliasString(tablet.Alias))
+		}
+		return conn.(queryservice.QueryService), nil
+	})
+}
+
+// testTabletConns holds the query service connections returned by the test
+// tablet dialer, keyed by "keyspace/shard".
+var testTabletConns sync.Map
+
+// addTestTabletConns makes the test tablet dialer return the given
+// connections, keyed by shard, for the primaries of the keyspace.
+func addTestTabletConns(t *testing.T, keyspace string, conns map[string]*sandboxconn.SandboxConn) {
+	for shard, conn := range conns {
+		key := keyspace + "/" + shard
+		testTabletConns.Store(key, conn)
+		t.Cleanup(func() { testTabletConns.Delete(key) })
+	}
 }
 
 func TestPanicHandler(t *testing.T) {
@@ -4023,6 +4058,29 @@ func TestDeleteTablets(t *testing.T) {
 	}
 }
 
+func TestDeleteVStreamSubscription(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	err := ts.CreateVStreamSubscription(ctx, &vtctldatapb.VStreamSubscription{Name: "orders"})
+	require.NoError(t, err)
+
+	_, err = vtctld.DeleteVStreamSubscription(ctx, &vtctldatapb.DeleteVStreamSubscriptionRequest{Name: "orders"})
+	require.NoError(t, err)
+	names, err := ts.GetVStreamSubscriptionNames(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	_, err = vtctld.DeleteVStreamSubscription(ctx, &vtctldatapb.DeleteVStreamSubscriptionRequest{Name: "orders"})
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "unexpected error: %v", err)
+}
+
 func TestEmergencyReparentShard(t *testing.T) {
 	t.Parallel()
 
@@ -4705,6 +4763,58 @@ func TestFindAllShardsInKeyspace(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestGetBackupRetentionPolicy(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx)
+	testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{Keyspace: "testkeyspace", Name: "-80"}, &vtctldatapb.Shard{Keyspace: "testkeyspace", Name: "80-"})
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	_, err := vtctld.GetBackupRetentionPolicy(ctx, &vtctldatapb.GetBackupRetentionPolicyRequest{Keyspace: "testkeyspace"})
+	assert.Equal(t, vtrpcpb.Code_NOT_FOUND, vterrors.Code(err))
+	assert.ErrorContains(t, err, "no backup retention policy is set for keyspace testkeyspace")
+
+	keyspacePolicy := &mysqlctlpb.BackupRetentionPolicy{KeepFullBackups: 3}
+	shardPolicy := &mysqlctlpb.BackupRetentionPolicy{MinAge: protoutil.DurationToProto(time.Hour)}
+	require.NoError(t, ts.SaveBackupRetentionPolicy(ctx, "testkeyspace", "", keyspacePolicy))
+	require.NoError(t, ts.SaveBackupRetentionPolicy(ctx, "testkeyspace", "-80", shardPolicy))
+
+	tests := []struct {
+		name     string
+		req      *vtctldatapb.GetBackupRetentionPolicyRequest
+		expected *vtctldatapb.GetBackupRetentionPolicyResponse
+	}{
+		{
+			name:     "keyspace",
+			req:      &vtctldatapb.GetBackupRetentionPolicyRequest{Keyspace: "testkeyspace"},
+			expected: &vtctldatapb.GetBackupRetentionPolicyResponse{Policy: keyspacePolicy},
+		},
+		{
+			name:     "shard policy",
+			req:      &vtctldatapb.GetBackupRetentionPolicyRequest{Keyspace: "testkeyspace", Shard: "-80"},
+			expected: &vtctldatapb.GetBackupRetentionPolicyResponse{Policy: shardPolicy},
+		},
+		{
+			name:     "inherited keyspace policy",
+			req:      &vtctldatapb.GetBackupRetentionPolicyRequest{Keyspace: "testkeyspace", Shard: "80-"},
+			expected: &vtctldatapb.GetBackupRetentionPolicyResponse{Policy: keyspacePolicy, Inherited: true},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := vtctld.GetBackupRetentionPolicy(ctx, tt.req)
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
 func TestGetBackups(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -4940,6 +5050,124 @@ func TestGetCellsAliases(t *testing.T) {
 	assert.Error(t, err)
 }
 
+// newDeadMessagesTMC returns a fake tablet manager client whose tablet
+// zone1-100 has a message table msg with the given table comment, and
+// answers every query with the given result.
+func newDeadMessagesTMC(comment string, result *querypb.QueryResult) *testutil.TabletManagerClient {
+	return &testutil.TabletManagerClient{
+		GetSchemaResults: map[string]struct {
+			Schema *tabletmanagerdatapb.SchemaDefinition
+			Error  error
+		}{
+			"zone1-0000000100": {
+				Schema: &tabletmanagerdatapb.SchemaDefinition{
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+						Name:    "msg",
+						Schema:  fmt.Sprintf("create table msg (id bigint, priority bigint, time_next bigint, epoch bigint, time_acked bigint, message varbinary(128), primary key (id)) comment '%s'", comment),
+						Columns: []string{"id", "priority", "time_next", "epoch", "time_acked", "message"},
+					}},
+				},
+			},
+		},
+		ExecuteFetchAsDbaResults: map[string]struct {
+			Response *querypb.QueryResult
+			Error    error
+		}{
+			"zone1-0000000100": {
+				Response: result,
+			},
+		},
+	}
+}
+
+var deadMessagesTablet = &topodatapb.Tablet{
+	Alias: &topodatapb.TabletAlias{
+		Cell: "zone1",
+		Uid:  100,
+	},
+	Keyspace: "ks",
+	Shard:    "-",
+	Type:     topodatapb.TabletType_PRIMARY,
+}
+
+func TestGetDeadMessages(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		comment   string
+		req       *vtctldatapb.GetDeadMessagesRequest
+		expected  *vtctldatapb.GetDeadMessagesResponse
+		shouldErr bool
+	}{
+		{
+			name:    "dead letter table",
+			comment: "vitess_message,vt_max_attempts=3,vt_dead_letter_table=msg_dlq",
+			req: &vtctldatapb.GetDeadMessagesRequest{
+				Keyspace: "ks",
+				Table:    "msg",
+			},
+			expected: &vtctldatapb.GetDeadMessagesResponse{
+				Result: &querypb.QueryResult{
+					Fields: []*querypb.Field{
+						{Name: "Tablet", Type: querypb.Type_VARBINARY, Charset: collations.CollationBinaryID, Flags: uint32(querypb.MySqlFlag_BINARY_FLAG)},
+						{Name: "id", Type: querypb.Type_INT64},
+						{Name: "message", Type: querypb.Type_VARBINARY},
+					},
+					Rows: []*querypb.Row{{Lengths: []int64{16, 1, 5}, Values: []byte("zone1-00000001001hello")}},
+				},
+			},
+		},
+		{
+			name:    "not a message table",
+			comment: "a regular table",
+			req: &vtctldatapb.GetDeadMessagesRequest{
+				Keyspace: "ks",
+				Table:    "msg",
+			},
+			shouldErr: true,
+		},
+		{
+			name:    "no max attempts",
+			comment: "vitess_message,vt_ack_wait=30",
+			req: &vtctldatapb.GetDeadMessagesRequest{
+				Keyspace: "ks",
+				Table:    "msg",
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			tmc := newDeadMessagesTMC(test.comment, sqltypes.ResultToProto3(sqltypes.MakeTestResult(
+				sqltypes.MakeTestFields("id|message", "int64|varbinary"),
+				"1|hello",
+			)))
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{AlsoSetShardPrimary: true}, deadMessagesTablet)
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(ts)
+			})
+
+			resp, err := vtctld.GetDeadMessages(ctx, test.req)
+			if test.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, test.expected, resp)
+		})
+	}
+}
+
 func TestGetFullStatus(t *testing.T) {
 	t.Parallel()
 
@@ -7083,6 +7311,66 @@ func TestGetTopologyPath(t *testing.T) {
 	}
 }
 
+func TestGetUnresolvedTransactions(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	keyspace := "ksunresolved"
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{AlsoSetShardPrimary: true}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		Keyspace: keyspace,
+		Shard:    "-80",
+		Type:     topodatapb.TabletType_PRIMARY,
+	}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 200},
+		Keyspace: keyspace,
+		Shard:    "80-",
+		Type:     topodatapb.TabletType_PRIMARY,
+	})
+
+	tx1 := &querypb.TransactionMetadata{
+		Dtid:         keyspace + ":-80:1",
+		State:        querypb.TransactionState_PREPARE,
+		TimeCreated:  1,
+		Participants: []*querypb.Target{{Keyspace: keyspace, Shard: "80-", TabletType: topodatapb.TabletType_PRIMARY}},
+	}
+	tx2 := &querypb.TransactionMetadata{
+		Dtid:         keyspace + ":80-:2",
+		State:        querypb.TransactionState_COMMIT,
+		TimeCreated:  2,
+		Participants: []*querypb.Target{{Keyspace: keyspace, Shard: "-80", TabletType: topodatapb.TabletType_PRIMARY}},
+	}
+	conn1 := sandboxconn.NewSandboxConn(nil)
+	conn1.UnresolvedTransactionsResult = []*querypb.TransactionMetadata{tx1}
+	conn2 := sandboxconn.NewSandboxConn(nil)
+	conn2.UnresolvedTransactionsResult = []*querypb.TransactionMetadata{tx2}
+	addTestTabletConns(t, keyspace, map[string]*sandboxconn.SandboxConn{"-80": conn1, "80-": conn2})
+
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	resp, err := vtctld.GetUnresolvedTransactions(ctx, &vtctldatapb.GetUnresolvedTransactionsRequest{
+		Keyspace:   keyspace,
+		AbandonAge: 30,
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, &vtctldatapb.GetUnresolvedTransactionsResponse{
+		Transactions: []*querypb.TransactionMetadata{tx1, tx2},
+	}, resp)
+	assert.EqualValues(t, 1, conn1.UnresolvedTransactionsCount.Load())
+	assert.EqualValues(t, 1, conn2.UnresolvedTransactionsCount.Load())
+
+	conn2.MustFailCodes[vtrpcpb.Code_UNAVAILABLE] = 1
+	_, err = vtctld.GetUnresolvedTransactions(ctx, &vtctldatapb.GetUnresolvedTransactionsRequest{
+		Keyspace: keyspace,
+	})
+	assert.Error(t, err)
+}
+
 func TestGetVSchema(t *testing.T) {
 	t.Parallel()
 
@@ -7132,6 +7420,51 @@ func TestGetVSchema(t *testing.T) {
 	})
 }
 
+func TestGetVStreamSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	vgtid := &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{{Keyspace: "ks", Shard: "0", Gtid: "MySQL56/a:1-10"}}}
+	subscriptions := []*vtctldatapb.VStreamSubscription{
+		{
+			Name:  "customers",
+			Vgtid: vgtid,
+		},
+		{
+			Name:           "orders",
+			Vgtid:          vgtid,
+			AcknowledgedAt: protoutil.TimeToProto(time.Now()),
+			EventTime:      protoutil.TimeToProto(time.Now().Add(-time.Hour)),
+		},
+	}
+	for _, subscription := range subscriptions {
+		require.NoError(t, ts.CreateVStreamSubscription(ctx, subscription))
+	}
+
+	resp, err := vtctld.GetVStreamSubscriptions(ctx, &vtctldatapb.GetVStreamSubscriptionsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Subscriptions, 2)
+	assert.Equal(t, "customers", resp.Subscriptions[0].Name)
+	assert.EqualValues(t, -1, resp.Subscriptions[0].LagSeconds)
+	assert.Equal(t, "orders", resp.Subscriptions[1].Name)
+	assert.InDelta(t, 3600, resp.Subscriptions[1].LagSeconds, 60)
+	utils.MustMatch(t, vgtid, resp.Subscriptions[1].Vgtid)
+
+	resp, err = vtctld.GetVStreamSubscriptions(ctx, &vtctldatapb.GetVStreamSubscriptionsRequest{Names: []string{"orders"}})
+	require.NoError(t, err)
+	require.Len(t, resp.Subscriptions, 1)
+	assert.Equal(t, "orders", resp.Subscriptions[0].Name)
+
+	_, err = vtctld.GetVStreamSubscriptions(ctx, &vtctldatapb.GetVStreamSubscriptionsRequest{Names: []string{"doesnotexist"}})
+	assert.Error(t, err)
+}
+
 func TestLaunchSchemaMigration(t *testing.T) {
 	t.Parallel()
 
@@ -7336,91 +7669,235 @@ func TestLaunchSchemaMigration(t *testing.T) {
 	}
 }
 
-func TestPingTablet(t *testing.T) {
+func TestLintSchema(t *testing.T) {
 	t.Parallel()
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	ts := memorytopo.NewServer(ctx, "zone1")
-	testutil.AddTablet(ctx, t, ts, &topodatapb.Tablet{
+	primary := &topodatapb.Tablet{
+		Keyspace: "ks",
+		Shard:    "-80",
 		Alias: &topodatapb.TabletAlias{
 			Cell: "zone1",
 			Uid:  100,
 		},
-		Keyspace: "testkeyspace",
-		Shard:    "-",
-	}, nil)
+		Type: topodatapb.TabletType_PRIMARY,
+	}
+	schema := &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{
+				Name:   "t1",
+				Schema: "CREATE TABLE `t1` (`id` int NOT NULL, `customer_id` bigint, `name` varchar(64), PRIMARY KEY (`id`)) ENGINE=InnoDB",
+				Type:   "BASE TABLE",
+			},
+			{
+				Name:   "t2",
+				Schema: "CREATE TABLE `t2` (`id` int NOT NULL) ENGINE=MyISAM",
+				Type:   "BASE TABLE",
+			},
+			{
+				Name:   "v1",
+				Schema: "CREATE VIEW `v1` AS select `id`, `name` from `t1`",
+				Type:   "VIEW",
+			},
+		},
+	}
+	vschema := &vschemapb.Keyspace{
+		Sharded: true,
+		Tables: map[string]*vschemapb.Table{
+			"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Name: "hash", Column: "customer_id"}}},
+		},
+	}
 
 	tests := []struct {
 		name      string
-		tmc       testutil.TabletManagerClient
-		req       *vtctldatapb.PingTabletRequest
-		expected  *vtctldatapb.PingTabletResponse
+		tablets   []*topodatapb.Tablet
+		req       *vtctldatapb.LintSchemaRequest
+		expected  *vtctldatapb.LintSchemaResponse
 		shouldErr bool
 	}{
 		{
-			name: "ok",
-			tmc: testutil.TabletManagerClient{
-				PingResults: map[string]error{
-					"zone1-0000000100": nil,
-				},
+			name:    "current schema",
+			tablets: []*topodatapb.Tablet{primary},
+			req: &vtctldatapb.LintSchemaRequest{
+				Keyspace: "ks",
 			},
-			req: &vtctldatapb.PingTabletRequest{
-				TabletAlias: &topodatapb.TabletAlias{
-					Cell: "zone1",
-					Uid:  100,
+			expected: &vtctldatapb.LintSchemaResponse{
+				Findings: []*vtctldatapb.LintFinding{
+					{Rule: "unindexed-sharding-key", Severity: "error", Entity: "t1", Message: "primary vindex hash column(s) customer_id are not the leftmost columns of any index"},
+					{Rule: "missing-primary-key", Severity: "error", Entity: "t2", Message: "table has no PRIMARY KEY"},
+					{Rule: "non-innodb-engine", Severity: "error", Entity: "t2", Message: "table uses the MyISAM storage engine"},
 				},
 			},
-			expected: &vtctldatapb.PingTabletResponse{},
 		},
 		{
-			name: "tablet not found",
-			tmc: testutil.TabletManagerClient{
-				PingResults: map[string]error{
-					"zone1-0000000100": nil,
+			name:    "statements with rule overrides",
+			tablets: []*topodatapb.Tablet{primary},
+			req: &vtctldatapb.LintSchemaRequest{
+				Keyspace: "ks",
+				Sql: []string{
+					"alter table t1 drop column name",
+					"create table t3 (id int)",
 				},
+				Rules: "missing-primary-key=warning",
 			},
-			req: &vtctldatapb.PingTabletRequest{
-				TabletAlias: &topodatapb.TabletAlias{
-					Cell: "zone2",
-					Uid:  404,
+			expected: &vtctldatapb.LintSchemaResponse{
+				Findings: []*vtctldatapb.LintFinding{
+					{Rule: "drop-view-referenced-column", Severity: "error", Entity: "t1", Message: "column name is referenced by view v1"},
+					{Rule: "missing-primary-key", Severity: "warning", Entity: "t3", Message: "table has no PRIMARY KEY"},
 				},
 			},
+		},
+		{
+			name:    "invalid rules",
+			tablets: []*topodatapb.Tablet{primary},
+			req: &vtctldatapb.LintSchemaRequest{
+				Keyspace: "ks",
+				Rules:    "no-such-rule=off",
+			},
 			shouldErr: true,
 		},
 		{
-			name: "ping rpc error",
-			tmc: testutil.TabletManagerClient{
-				PingResults: map[string]error{
-					"zone1-0000000100": assert.AnError,
+			name: "no shard primary",
+			tablets: []*topodatapb.Tablet{
+				{
+					Keyspace: "ks",
+					Shard:    "-80",
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+					Type: topodatapb.TabletType_REPLICA,
 				},
 			},
-			req: &vtctldatapb.PingTabletRequest{
-				TabletAlias: &topodatapb.TabletAlias{
-					Cell: "zone1",
-					Uid:  100,
-				},
+			req: &vtctldatapb.LintSchemaRequest{
+				Keyspace: "ks",
 			},
 			shouldErr: true,
 		},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &tt.tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
-				return NewVtctldServer(ts)
-			})
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
 
-			resp, err := vtctld.PingTablet(ctx, tt.req)
-			if tt.shouldErr {
-				assert.Error(t, err)
-				assert.Nil(t, resp)
-				return
-			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ts := memorytopo.NewServer(ctx, "zone1")
 
-			require.NoError(t, err)
-			assert.Equal(t, tt.expected, resp)
-		})
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+				AlsoSetShardPrimary: true,
+			}, test.tablets...)
+			require.NoError(t, ts.SaveVSchema(ctx, "ks", vschema))
+
+			tmc := &testutil.TabletManagerClient{
+				GetSchemaResults: map[string]struct {
+					Schema *tabletmanagerdatapb.SchemaDefinition
+					Error  error
+				}{
+					"zone1-0000000100": {Schema: schema},
+				},
+			}
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(ts)
+			})
+
+			resp, err := vtctld.LintSchema(ctx, test.req)
+			if test.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, test.expected, resp)
+		})
+	}
+}
+
+func TestPingTablet(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddTablet(ctx, t, ts, &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  100,
+		},
+		Keyspace: "testkeyspace",
+		Shard:    "-",
+	}, nil)
+
+	tests := []struct {
+		name      string
+		tmc       testutil.TabletManagerClient
+		req       *vtctldatapb.PingTabletRequest
+		expected  *vtctldatapb.PingTabletResponse
+		shouldErr bool
+	}{
+		{
+			name: "ok",
+			tmc: testutil.TabletManagerClient{
+				PingResults: map[string]error{
+					"zone1-0000000100": nil,
+				},
+			},
+			req: &vtctldatapb.PingTabletRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			},
+			expected: &vtctldatapb.PingTabletResponse{},
+		},
+		{
+			name: "tablet not found",
+			tmc: testutil.TabletManagerClient{
+				PingResults: map[string]error{
+					"zone1-0000000100": nil,
+				},
+			},
+			req: &vtctldatapb.PingTabletRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone2",
+					Uid:  404,
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "ping rpc error",
+			tmc: testutil.TabletManagerClient{
+				PingResults: map[string]error{
+					"zone1-0000000100": assert.AnError,
+				},
+			},
+			req: &vtctldatapb.PingTabletRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &tt.tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(ts)
+			})
+
+			resp, err := vtctld.PingTablet(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				assert.Nil(t, resp)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resp)
+		})
 	}
 }
 
@@ -7622,6 +8099,165 @@ func TestPlannedReparentShard(t *testing.T) {
 	}
 }
 
+func TestPruneBackups(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx)
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	setup := func() {
+		testutil.BackupStorage.Backups = map[string][]string{
+			"testkeyspace/-": {
+				"2021-06-01.000000.zone1-101",
+				"2021-06-02.000000.zone1-101",
+				"2021-06-10.000000.zone1-101",
+				"2021-06-11.000000.zone1-101",
+			},
+		}
+		testutil.BackupStorage.Manifests = map[string]string{
+			"testkeyspace/-/2021-06-01.000000.zone1-101": `{"BackupMethod": "builtin", "Position": "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-100", "BackupTime": "2021-06-01T00:00:00Z"}`,
+			"testkeyspace/-/2021-06-02.000000.zone1-101": `{"BackupMethod": "builtin", "Position": "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-200", "FromPosition": "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-100", "Incremental": true, "BackupTime": "2021-06-02T00:00:00Z"}`,
+			"testkeyspace/-/2021-06-10.000000.zone1-101": `{"BackupMethod": "builtin", "Position": "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-1000", "BackupTime": "2021-06-10T00:00:00Z"}`,
+		}
+	}
+	defer func() { testutil.BackupStorage.Manifests = nil }()
+
+	getBackupNames := func(t *testing.T) []string {
+		resp, err := vtctld.GetBackups(ctx, &vtctldatapb.GetBackupsRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		})
+		require.NoError(t, err)
+
+		var backupNames []string
+		for _, bi := range resp.Backups {
+			backupNames = append(backupNames, bi.Name)
+		}
+		return backupNames
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		setup()
+		resp, err := vtctld.PruneBackups(ctx, &vtctldatapb.PruneBackupsRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Policy:   &mysqlctlpb.BackupRetentionPolicy{KeepFullBackups: 1},
+			DryRun:   true,
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.RemovedBackups, 2)
+		assert.Equal(t, "2021-06-01.000000.zone1-101", resp.RemovedBackups[0].Name)
+		assert.Equal(t, "2021-06-02.000000.zone1-101", resp.RemovedBackups[1].Name)
+		assert.Equal(t, "testkeyspace", resp.RemovedBackups[0].Keyspace)
+		assert.Len(t, resp.RetainedBackups, 2)
+		assert.Len(t, getBackupNames(t), 4, "expected no backup to be removed")
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		setup()
+		_, err := vtctld.PruneBackups(ctx, &vtctldatapb.PruneBackupsRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Policy:   &mysqlctlpb.BackupRetentionPolicy{KeepFullBackups: 1},
+		})
+		require.NoError(t, err)
+		// The last backup has no MANIFEST, and may still be in progress.
+		utils.MustMatch(t, []string{"2021-06-10.000000.zone1-101", "2021-06-11.000000.zone1-101"}, getBackupNames(t))
+	})
+
+	t.Run("missing policy", func(t *testing.T) {
+		setup()
+		_, err := vtctld.PruneBackups(ctx, &vtctldatapb.PruneBackupsRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		})
+		assert.Equal(t, vtrpcpb.Code_FAILED_PRECONDITION, vterrors.Code(err))
+		assert.ErrorContains(t, err, "no backup retention policy was given, and none is set for testkeyspace/-")
+		assert.Len(t, getBackupNames(t), 4, "expected no backup to be removed")
+	})
+
+	t.Run("empty policy", func(t *testing.T) {
+		setup()
+		_, err := vtctld.PruneBackups(ctx, &vtctldatapb.PruneBackupsRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Policy:   &mysqlctlpb.BackupRetentionPolicy{},
+		})
+		assert.ErrorContains(t, err, "the backup retention policy has no rule set")
+		assert.Len(t, getBackupNames(t), 4, "expected no backup to be removed")
+	})
+
+	t.Run("stored policy", func(t *testing.T) {
+		setup()
+		require.NoError(t, ts.SaveBackupRetentionPolicy(ctx, "testkeyspace", "", &mysqlctlpb.BackupRetentionPolicy{KeepFullBackups: 2}))
+		defer ts.DeleteBackupRetentionPolicy(ctx, "testkeyspace", "")
+
+		resp, err := vtctld.PruneBackups(ctx, &vtctldatapb.PruneBackupsRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			DryRun:   true,
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.RemovedBackups, 1)
+		assert.Equal(t, "2021-06-02.000000.zone1-101", resp.RemovedBackups[0].Name)
+
+		// The policy of the shard overrides the one of the keyspace.
+		require.NoError(t, ts.SaveBackupRetentionPolicy(ctx, "testkeyspace", "-", &mysqlctlpb.BackupRetentionPolicy{KeepFullBackups: 1}))
+		defer ts.DeleteBackupRetentionPolicy(ctx, "testkeyspace", "-")
+
+		resp, err = vtctld.PruneBackups(ctx, &vtctldatapb.PruneBackupsRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			DryRun:   true,
+		})
+		require.NoError(t, err)
+		assert.Len(t, resp.RemovedBackups, 2)
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		setup()
+		_, err := vtctld.PruneBackups(ctx, &vtctldatapb.PruneBackupsRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Policy: &mysqlctlpb.BackupRetentionPolicy{
+				PointInTimeWindow: protoutil.DurationToProto(48 * time.Hour),
+				MaxAge:            protoutil.DurationToProto(24 * time.Hour),
+			},
+		})
+		assert.ErrorContains(t, err, "is larger than max age")
+	})
+}
+
+func TestPurgeDeadMessages(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tmc := newDeadMessagesTMC("vitess_message,vt_max_attempts=3", &querypb.QueryResult{RowsAffected: 2})
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{AlsoSetShardPrimary: true}, deadMessagesTablet)
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	resp, err := vtctld.PurgeDeadMessages(ctx, &vtctldatapb.PurgeDeadMessagesRequest{
+		Keyspace: "ks",
+		Table:    "msg",
+		Ids:      []string{"1", "2"},
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, &vtctldatapb.PurgeDeadMessagesResponse{Count: 2}, resp)
+
+	_, err = vtctld.PurgeDeadMessages(ctx, &vtctldatapb.PurgeDeadMessagesRequest{
+		Keyspace: "ks",
+		Table:    "nonexistent",
+	})
+	assert.Error(t, err)
+}
+
 func TestRebuildKeyspaceGraph(t *testing.T) {
 	t.Parallel()
 
@@ -9520,6 +10156,232 @@ func TestReparentTablet(t *testing.T) {
 	}
 }
 
+func TestRequeueDeadMessages(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		comment  string
+		req      *vtctldatapb.RequeueDeadMessagesRequest
+		result   *querypb.QueryResult
+		expected *vtctldatapb.RequeueDeadMessagesResponse
+	}{
+		{
+			name:    "mark failed",
+			comment: "vitess_message,vt_max_attempts=3",
+			req: &vtctldatapb.RequeueDeadMessagesRequest{
+				Keyspace: "ks",
+				Table:    "msg",
+				Ids:      []string{"1"},
+			},
+			result:   &querypb.QueryResult{RowsAffected: 1},
+			expected: &vtctldatapb.RequeueDeadMessagesResponse{Count: 1},
+		},
+		{
+			name:    "all from dead letter table",
+			comment: "vitess_message,vt_max_attempts=3,vt_dead_letter_table=msg_dlq",
+			req: &vtctldatapb.RequeueDeadMessagesRequest{
+				Keyspace: "ks",
+				Table:    "msg",
+			},
+			result: &querypb.QueryResult{
+				Fields:       []*querypb.Field{{Name: "id", Type: querypb.Type_INT64}},
+				Rows:         []*querypb.Row{{Lengths: []int64{1}, Values: []byte("1")}},
+				RowsAffected: 1,
+			},
+			expected: &vtctldatapb.RequeueDeadMessagesResponse{Count: 1},
+		},
+		{
+			name:    "nothing to requeue",
+			comment: "vitess_message,vt_max_attempts=3,vt_dead_letter_table=msg_dlq",
+			req: &vtctldatapb.RequeueDeadMessagesRequest{
+				Keyspace: "ks",
+				Table:    "msg",
+			},
+			result:   &querypb.QueryResult{Fields: []*querypb.Field{{Name: "id", Type: querypb.Type_INT64}}},
+			expected: &vtctldatapb.RequeueDeadMessagesResponse{},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			tmc := newDeadMessagesTMC(test.comment, test.result)
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{AlsoSetShardPrimary: true}, deadMessagesTablet)
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(ts)
+			})
+
+			resp, err := vtctld.RequeueDeadMessages(ctx, test.req)
+			require.NoError(t, err)
+			utils.MustMatch(t, test.expected, resp)
+		})
+	}
+}
+
+func TestResolveTransaction(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		state  querypb.TransactionState
+		action vtctldatapb.ResolveTransactionRequest_Action
+		// notFound makes the transaction metadata missing.
+		notFound bool
+		// inFlight makes the transaction younger than the abandon age.
+		inFlight bool
+		// The expected number of calls on the metadata manager and the
+		// participant.
+		setRollback      int64
+		rollbackPrepared int64
+		commitPrepared   int64
+		concluded        int64
+		shouldErr        bool
+	}{
+		{
+			name:             "prepared, auto",
+			state:            querypb.TransactionState_PREPARE,
+			action:           vtctldatapb.ResolveTransactionRequest_AUTO,
+			setRollback:      1,
+			rollbackPrepared: 1,
+			concluded:        1,
+		},
+		{
+			name:             "prepared, rollback",
+			state:            querypb.TransactionState_PREPARE,
+			action:           vtctldatapb.ResolveTransactionRequest_ROLLBACK,
+			setRollback:      1,
+			rollbackPrepared: 1,
+			concluded:        1,
+		},
+		{
+			name:      "prepared, commit",
+			state:     querypb.TransactionState_PREPARE,
+			action:    vtctldatapb.ResolveTransactionRequest_COMMIT,
+			shouldErr: true,
+		},
+		{
+			name:             "rolling back, auto",
+			state:            querypb.TransactionState_ROLLBACK,
+			action:           vtctldatapb.ResolveTransactionRequest_AUTO,
+			rollbackPrepared: 1,
+			concluded:        1,
+		},
+		{
+			name:           "committing, auto",
+			state:          querypb.TransactionState_COMMIT,
+			action:         vtctldatapb.ResolveTransactionRequest_AUTO,
+			commitPrepared: 1,
+			concluded:      1,
+		},
+		{
+			name:           "committing, commit",
+			state:          querypb.TransactionState_COMMIT,
+			action:         vtctldatapb.ResolveTransactionRequest_COMMIT,
+			commitPrepared: 1,
+			concluded:      1,
+		},
+		{
+			name:      "committing, rollback",
+			state:     querypb.TransactionState_COMMIT,
+			action:    vtctldatapb.ResolveTransactionRequest_ROLLBACK,
+			shouldErr: true,
+		},
+		{
+			name:      "not found",
+			notFound:  true,
+			shouldErr: true,
+		},
+		{
+			name:      "committing, in flight",
+			state:     querypb.TransactionState_COMMIT,
+			action:    vtctldatapb.ResolveTransactionRequest_AUTO,
+			inFlight:  true,
+			shouldErr: true,
+		},
+		{
+			name:      "prepared, in flight",
+			state:     querypb.TransactionState_PREPARE,
+			action:    vtctldatapb.ResolveTransactionRequest_ROLLBACK,
+			inFlight:  true,
+			shouldErr: true,
+		},
+	}
+
+	for i, test := range tests {
+		i, test := i, test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// Each test case uses its own keyspace, as the connections of the
+			// test tablet dialer are keyed by keyspace and shard.
+			keyspace := fmt.Sprintf("ksresolve%d", i)
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{AlsoSetShardPrimary: true}, &topodatapb.Tablet{
+				Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+				Keyspace: keyspace,
+				Shard:    "-80",
+				Type:     topodatapb.TabletType_PRIMARY,
+			}, &topodatapb.Tablet{
+				Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 200},
+				Keyspace: keyspace,
+				Shard:    "80-",
+				Type:     topodatapb.TabletType_PRIMARY,
+			})
+
+			dtid := keyspace + ":-80:1234"
+			mm := sandboxconn.NewSandboxConn(nil)
+			if !test.notFound {
+				transaction := &querypb.TransactionMetadata{
+					Dtid:         dtid,
+					State:        test.state,
+					Participants: []*querypb.Target{{Keyspace: keyspace, Shard: "80-", TabletType: topodatapb.TabletType_PRIMARY}},
+				}
+				mm.ReadTransactionResults = []*querypb.TransactionMetadata{transaction}
+				if !test.inFlight {
+					mm.UnresolvedTransactionsResult = []*querypb.TransactionMetadata{transaction}
+				}
+			}
+			participant := sandboxconn.NewSandboxConn(nil)
+			addTestTabletConns(t, keyspace, map[string]*sandboxconn.SandboxConn{"-80": mm, "80-": participant})
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(ts)
+			})
+
+			resp, err := vtctld.ResolveTransaction(ctx, &vtctldatapb.ResolveTransactionRequest{
+				Dtid:   dtid,
+				Action: test.action,
+			})
+			switch {
+			case test.inFlight:
+				require.Error(t, err)
+				assert.Equal(t, vtrpcpb.Code_FAILED_PRECONDITION, vterrors.Code(err))
+				assert.Contains(t, err.Error(), "still in flight")
+			case test.shouldErr:
+				assert.Error(t, err)
+			default:
+				require.NoError(t, err)
+				utils.MustMatch(t, &vtctldatapb.ResolveTransactionResponse{State: test.state}, resp)
+			}
+
+			assert.Equal(t, test.setRollback, mm.SetRollbackCount.Load(), "SetRollback")
+			assert.Equal(t, test.rollbackPrepared, participant.RollbackPreparedCount.Load(), "RollbackPrepared")
+			assert.Equal(t, test.commitPrepared, participant.CommitPreparedCount.Load(), "CommitPrepared")
+			assert.Equal(t, test.concluded, mm.ConcludeTransactionCount.Load(), "ConcludeTransaction")
+		})
+	}
+}
+
 func TestRestoreFromBackup(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -9967,6 +10829,105 @@ func TestRunHealthCheck(t *testing.T) {
 	}
 }
 
+func TestSetBackupRetentionPolicy(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx)
+	testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{Keyspace: "testkeyspace", Name: "-"})
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	policy := &mysqlctlpb.BackupRetentionPolicy{
+		KeepFullBackups:   2,
+		PointInTimeWindow: protoutil.DurationToProto(24 * time.Hour),
+	}
+	_, err := vtctld.SetBackupRetentionPolicy(ctx, &vtctldatapb.SetBackupRetentionPolicyRequest{
+		Keyspace: "testkeyspace",
+		Shard:    "-",
+		Policy:   policy,
+	})
+	require.NoError(t, err)
+
+	stored, err := ts.GetBackupRetentionPolicy(ctx, "testkeyspace", "-")
+	require.NoError(t, err)
+	utils.MustMatch(t, policy, stored)
+
+	_, err = vtctld.SetBackupRetentionPolicy(ctx, &vtctldatapb.SetBackupRetentionPolicyRequest{
+		Keyspace: "testkeyspace",
+		Shard:    "-",
+		Clear:    true,
+	})
+	require.NoError(t, err)
+
+	_, err = ts.GetBackupRetentionPolicy(ctx, "testkeyspace", "-")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "expected the policy to be cleared, got %v", err)
+
+	tests := []struct {
+		name        string
+		req         *vtctldatapb.SetBackupRetentionPolicyRequest
+		expectedErr string
+	}{
+		{
+			name: "missing shard",
+			req: &vtctldatapb.SetBackupRetentionPolicyRequest{
+				Keyspace: "testkeyspace",
+				Shard:    "-80",
+				Policy:   policy,
+			},
+			expectedErr: "node doesn't exist: keyspaces/testkeyspace/shards/-80",
+		},
+		{
+			name: "missing policy",
+			req: &vtctldatapb.SetBackupRetentionPolicyRequest{
+				Keyspace: "testkeyspace",
+			},
+			expectedErr: "a backup retention policy is required",
+		},
+		{
+			name: "empty policy",
+			req: &vtctldatapb.SetBackupRetentionPolicyRequest{
+				Keyspace: "testkeyspace",
+				Policy:   &mysqlctlpb.BackupRetentionPolicy{},
+			},
+			expectedErr: "the backup retention policy has no rule set",
+		},
+		{
+			name: "invalid policy",
+			req: &vtctldatapb.SetBackupRetentionPolicyRequest{
+				Keyspace: "testkeyspace",
+				Policy: &mysqlctlpb.BackupRetentionPolicy{
+					PointInTimeWindow: protoutil.DurationToProto(48 * time.Hour),
+					MaxAge:            protoutil.DurationToProto(24 * time.Hour),
+				},
+			},
+			expectedErr: "is larger than max age",
+		},
+		{
+			name: "set and clear",
+			req: &vtctldatapb.SetBackupRetentionPolicyRequest{
+				Keyspace: "testkeyspace",
+				Policy:   policy,
+				Clear:    true,
+			},
+			expectedErr: "cannot both set and clear a backup retention policy",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := vtctld.SetBackupRetentionPolicy(ctx, tt.req)
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+
+	_, err = ts.GetBackupRetentionPolicy(ctx, "testkeyspace", "")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "expected no policy to be stored, got %v", err)
+}
+
 func TestSetKeyspaceDurabilityPolicy(t *testing.T) {
 	t.Parallel()
 
@@ -13409,6 +14370,127 @@ func TestValidateShard(t *testing.T) {
 		})
 	}
 }
+func TestVerifyBackup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	verification := &mysqlctlpb.BackupVerification{
+		BackupName: "2023-06-01.000000.zone1-100",
+		Ok:         true,
+	}
+	tests := []struct {
+		name      string
+		tmc       *testutil.TabletManagerClient
+		req       *vtctldatapb.VerifyBackupRequest
+		expected  []*vtctldatapb.VerifyBackupResponse
+		shouldErr bool
+	}{
+		{
+			name: "ok",
+			tmc: &testutil.TabletManagerClient{
+				VerifyBackupResults: map[string]struct {
+					Responses []*tabletmanagerdatapb.VerifyBackupResponse
+					Error     error
+				}{
+					"zone1-0000000100": {
+						Responses: []*tabletmanagerdatapb.VerifyBackupResponse{
+							{Event: &logutilpb.Event{Value: "restoring"}},
+							{Verification: verification},
+						},
+					},
+				},
+			},
+			req: &vtctldatapb.VerifyBackupRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			},
+			expected: []*vtctldatapb.VerifyBackupResponse{
+				{
+					TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+					Keyspace:    "ks",
+					Shard:       "-",
+					Event:       &logutilpb.Event{Value: "restoring"},
+				},
+				{
+					TabletAlias:  &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+					Keyspace:     "ks",
+					Shard:        "-",
+					Verification: verification,
+				},
+			},
+		},
+		{
+			name: "tablet error",
+			tmc: &testutil.TabletManagerClient{
+				VerifyBackupResults: map[string]struct {
+					Responses []*tabletmanagerdatapb.VerifyBackupResponse
+					Error     error
+				}{
+					"zone1-0000000100": {
+						Error: assert.AnError,
+					},
+				},
+			},
+			req: &vtctldatapb.VerifyBackupRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "no such tablet",
+			tmc:  &testutil.TabletManagerClient{},
+			req: &vtctldatapb.VerifyBackupRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone404",
+					Uid:  404,
+				},
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddTablets(ctx, t, ts, nil, &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				Keyspace: "ks",
+				Shard:    "-",
+				Type:     topodatapb.TabletType_REPLICA,
+			})
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tt.tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(ts)
+			})
+			client := localvtctldclient.New(vtctld)
+			stream, err := client.VerifyBackup(ctx, tt.req)
+			require.NoError(t, err)
+
+			var responses []*vtctldatapb.VerifyBackupResponse
+			for {
+				resp, err := stream.Recv()
+				if err != nil {
+					if tt.shouldErr {
+						assert.NotErrorIs(t, err, io.EOF)
+					} else {
+						assert.ErrorIs(t, err, io.EOF)
+					}
+					break
+				}
+				responses = append(responses, resp)
+			}
+			utils.MustMatch(t, tt.expected, responses)
+		})
+	}
+}
+
 func TestMain(m *testing.M) {
 	_flag.ParseFlagsForTest()
 	os.Exit(m.Run())
@@ -19,6 +19,7 @@ package testutil
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"sync"
@@ -347,6 +348,12 @@ type TabletManagerClient struct {
 		Result *querypb.QueryResult
 		Error  error
 	}
+	// keyed by tablet alias. The responses are streamed in order, followed by
+	// the error, if any.
+	VerifyBackupResults map[string]struct {
+		Responses []*tabletmanagerdatapb.VerifyBackupResponse
+		Error     error
+	}
 	// keyed by tablet alias.
 	WaitForPositionDelays map[string]time.Duration
 	// keyed by tablet alias. injects a sleep to the end of the function
@@ -1348,6 +1355,37 @@ func (fake *TabletManagerClient) UndoDemotePrimary(ctx context.Context, tablet *
 	return assert.AnError
 }
 
+type verifyBackupStream struct {
+	responses []*tabletmanagerdatapb.VerifyBackupResponse
+	err       error
+}
+
+func (stream *verifyBackupStream) Recv() (*tabletmanagerdatapb.VerifyBackupResponse, error) {
+	if len(stream.responses) == 0 {
+		if stream.err != nil {
+			return nil, stream.err
+		}
+		return nil, io.EOF
+	}
+	resp := stream.responses[0]
+	stream.responses = stream.responses[1:]
+	return resp, nil
+}
+
+// VerifyBackup is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) VerifyBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.VerifyBackupRequest) (tmclient.VerifyBackupStream, error) {
+	key := topoproto.TabletAliasString(tablet.Alias)
+	testdata, ok := fake.VerifyBackupResults[key]
+	if !ok {
+		return nil, fmt.Errorf("no VerifyBackup fake result set for %s", key)
+	}
+
+	return &verifyBackupStream{
+		responses: testdata.Responses,
+		err:       testdata.Error,
+	}, nil
+}
+
 // VReplicationExec is part of the tmclient.TabletManagerCLient interface.
 func (fake *TabletManagerClient) VReplicationExec(ctx context.Context, tablet *topodatapb.Tablet, query string) (*querypb.QueryResult, error) {
 	if fake.VReplicationExecResults == nil {
@@ -0,0 +1,138 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcvtctldserver
+
+import (
+	"context"
+	"sync"
+
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// defaultResolveTransactionAbandonAge is the age in seconds a distributed
+// transaction must have reached to be resolved, if the request sets none.
+const defaultResolveTransactionAbandonAge = 60
+
+// primaryTarget returns the query target of the primary of a shard.
+func primaryTarget(keyspace string, shard string) *querypb.Target {
+	return &querypb.Target{
+		Keyspace:   keyspace,
+		Shard:      shard,
+		TabletType: topodatapb.TabletType_PRIMARY,
+	}
+}
+
+// withShardPrimaryConn dials the query service of the primary tablet of
+// the target's shard and calls f with it.
+func (s *VtctldServer) withShardPrimaryConn(ctx context.Context, target *querypb.Target, f func(conn queryservice.QueryService) error) error {
+	si, err := s.ts.GetShard(ctx, target.Keyspace, target.Shard)
+	if err != nil {
+		return err
+	}
+	if !si.HasPrimary() {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard %s/%s has no primary", target.Keyspace, target.Shard)
+	}
+	ti, err := s.ts.GetTablet(ctx, si.PrimaryAlias)
+	if err != nil {
+		return err
+	}
+	return s.withTabletConn(ctx, ti.Tablet, f)
+}
+
+// withTabletConn dials the query service of the tablet and calls f with it.
+func (s *VtctldServer) withTabletConn(ctx context.Context, tablet *topodatapb.Tablet, f func(conn queryservice.QueryService) error) error {
+	conn, err := tabletconn.GetDialer()(tablet, grpcclient.FailFast(false))
+	if err != nil {
+		return vterrors.Wrapf(err, "failed to connect to tablet %s", topoproto.TabletAliasString(tablet.Alias))
+	}
+	defer conn.Close(ctx)
+	return f(conn)
+}
+
+// forEachParticipant runs f concurrently with a connection to the primary
+// of every participant of the transaction.
+func (s *VtctldServer) forEachParticipant(ctx context.Context, transaction *querypb.TransactionMetadata, f func(conn queryservice.QueryService, target *querypb.Target) error) error {
+	var (
+		wg  sync.WaitGroup
+		rec concurrency.AllErrorRecorder
+	)
+	for _, participant := range transaction.Participants {
+		wg.Add(1)
+		go func(target *querypb.Target) {
+			defer wg.Done()
+
+			err := s.withShardPrimaryConn(ctx, target, func(conn queryservice.QueryService) error {
+				return f(conn, target)
+			})
+			if err != nil {
+				rec.RecordError(err)
+			}
+		}(primaryTarget(participant.Keyspace, participant.Shard))
+	}
+	wg.Wait()
+
+	return rec.Error()
+}
+
+// findUnresolvedTransaction returns the metadata of the transaction from the
+// unresolved transactions older than abandonAge seconds of its metadata
+// manager. A younger transaction may still be in flight in vtgate, so it is
+// refused.
+func findUnresolvedTransaction(ctx context.Context, mm queryservice.QueryService, target *querypb.Target, dtid string, abandonAge int64) (*querypb.TransactionMetadata, error) {
+	transactions, err := mm.UnresolvedTransactions(ctx, target, abandonAge)
+	if err != nil {
+		return nil, err
+	}
+	for _, transaction := range transactions {
+		if transaction.Dtid == dtid {
+			return transaction, nil
+		}
+	}
+
+	transaction, err := mm.ReadTransaction(ctx, target, dtid)
+	if err != nil {
+		return nil, err
+	}
+	if transaction == nil || transaction.Dtid == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "transaction %s not found, it may have been resolved already", dtid)
+	}
+	return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "transaction %s is still in flight: it is not older than the abandon age of %ds", dtid, abandonAge)
+}
+
+// checkResolveAction returns an error if the action requested to resolve a
+// transaction contradicts the decision recorded for it. A transaction
+// whose commit decision was recorded can only be committed, and any other
+// transaction can only be rolled back.
+func checkResolveAction(transaction *querypb.TransactionMetadata, action vtctldatapb.ResolveTransactionRequest_Action) error {
+	switch {
+	case transaction.State == querypb.TransactionState_COMMIT && action == vtctldatapb.ResolveTransactionRequest_ROLLBACK:
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "transaction %s cannot be rolled back: its commit decision was recorded", transaction.Dtid)
+	case transaction.State != querypb.TransactionState_COMMIT && action == vtctldatapb.ResolveTransactionRequest_COMMIT:
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "transaction %s cannot be committed: it is in state %s, and only transactions whose commit decision was recorded can be committed", transaction.Dtid, transaction.State)
+	}
+	return nil
+}
@@ -396,7 +396,7 @@ func (erp *EmergencyReparenter) findMostAdvanced(
 	}
 
 	// sort the tablets for finding the best intermediate source in ERS
-	err = sortTabletsForReparent(validTablets, tabletPositions, opts.durability)
+	err = SortTabletsForReparent(validTablets, tabletPositions, opts.durability)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -82,9 +82,9 @@ func (rs *reparentSorter) Less(i, j int) bool {
 	return !jPromotionRule.BetterThan(iPromotionRule)
 }
 
-// sortTabletsForReparent sorts the tablets, given their positions for emergency reparent shard and planned reparent shard.
+// SortTabletsForReparent sorts the tablets, given their positions for emergency reparent shard and planned reparent shard.
 // Tablets are sorted first by their replication positions, with ties broken by the promotion rules.
-func sortTabletsForReparent(tablets []*topodatapb.Tablet, positions []replication.Position, durability Durabler) error {
+func SortTabletsForReparent(tablets []*topodatapb.Tablet, positions []replication.Position, durability Durabler) error {
 	// throw an error internal error in case of unequal number of tablets and positions
 	// fail-safe code prevents panic in sorting in case the lengths are unequal
 	if len(tablets) != len(positions) {
@@ -260,6 +260,14 @@ func (t *explainTablet) ReadTransaction(ctx context.Context, target *querypb.Tar
 	return t.tsv.ReadTransaction(ctx, target, dtid)
 }
 
+// UnresolvedTransactions is part of the QueryService interface.
+func (t *explainTablet) UnresolvedTransactions(ctx context.Context, target *querypb.Target, abandonAge int64) (transactions []*querypb.TransactionMetadata, err error) {
+	t.mu.Lock()
+	t.currentTime = t.vte.batchTime.Wait()
+	t.mu.Unlock()
+	return t.tsv.UnresolvedTransactions(ctx, target, abandonAge)
+}
+
 // BeginExecute is part of the QueryService interface.
 func (t *explainTablet) BeginExecute(ctx context.Context, target *querypb.Target, preQueries []string, sql string, bindVariables map[string]*querypb.BindVariable, reservedID int64, options *querypb.ExecuteOptions) (queryservice.TransactionState, *sqltypes.Result, error) {
 	t.mu.Lock()
@@ -42,6 +42,10 @@ type AggregateParams struct {
 	WCol   int
 	Type   evalengine.Type
 
+	// UnorderedDistinct is set for distinct aggregations when the input is not sorted by the distinct column.
+	// Instead of comparing each value with the previous one, the values seen are then kept in memory.
+	UnorderedDistinct bool
+
 	Alias    string `json:",omitempty"`
 	Expr     sqlparser.Expr
 	Original *sqlparser.AliasedExpr
@@ -77,6 +81,9 @@ func (ap *AggregateParams) String() string {
 	if sqltypes.IsText(ap.Type.Type) && ap.Type.Coll != collations.Unknown {
 		keyCol += " COLLATE " + collations.Local().LookupName(ap.Type.Coll)
 	}
+	if ap.UnorderedDistinct {
+		keyCol += " UNORDERED"
+	}
 	dispOrigOp := ""
 	if ap.OrigOpcode != AggregateUnassigned && ap.OrigOpcode != ap.Opcode {
 		dispOrigOp = "_" + ap.OrigOpcode.String()
@@ -104,9 +111,15 @@ type aggregatorDistinct struct {
 	column int
 	last   sqltypes.Value
 	coll   collations.ID
+
+	// seen is used instead of last when the input is not sorted by the distinct column
+	seen *probeTable
 }
 
 func (a *aggregatorDistinct) shouldReturn(row []sqltypes.Value) (bool, error) {
+	if a.column >= 0 && a.seen != nil {
+		return a.seen.exists(sqltypes.Row{row[a.column]})
+	}
 	if a.column >= 0 {
 		last := a.last
 		next := row[a.column]
@@ -128,6 +141,20 @@ func (a *aggregatorDistinct) shouldReturn(row []sqltypes.Value) (bool, error) {
 
 func (a *aggregatorDistinct) reset() {
 	a.last = sqltypes.NULL
+	if a.seen != nil {
+		clear(a.seen.seenRows)
+	}
+}
+
+func newAggregatorDistinct(column int, typ evalengine.Type, unordered bool) aggregatorDistinct {
+	ad := aggregatorDistinct{
+		column: column,
+		coll:   typ.Coll,
+	}
+	if unordered && column >= 0 {
+		ad.seen = newProbeTable([]CheckCol{{Col: 0, Type: typ}})
+	}
+	return ad
 }
 
 type aggregatorCount struct {
@@ -378,11 +405,8 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (agg
 
 		case AggregateCount, AggregateCountDistinct:
 			ag = &aggregatorCount{
-				from: aggr.Col,
-				distinct: aggregatorDistinct{
-					column: distinct,
-					coll:   aggr.Type.Coll,
-				},
+				from:     aggr.Col,
+				distinct: newAggregatorDistinct(distinct, aggr.Type, aggr.UnorderedDistinct),
 			}
 
 		case AggregateSum, AggregateSumDistinct:
@@ -395,12 +419,9 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (agg
 			}
 
 			ag = &aggregatorSum{
-				from: aggr.Col,
-				sum:  sum,
-				distinct: aggregatorDistinct{
-					column: distinct,
-					coll:   aggr.Type.Coll,
-				},
+				from:     aggr.Col,
+				sum:      sum,
+				distinct: newAggregatorDistinct(distinct, aggr.Type, aggr.UnorderedDistinct),
 			}
 
 		case AggregateMin:
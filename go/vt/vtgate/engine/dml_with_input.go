@@ -0,0 +1,129 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+var _ Primitive = (*DMLWithInput)(nil)
+
+// DMLWithInput is a primitive that first executes the Input primitive to find the primary keys
+// of the rows that are going to be modified, and then executes the DML primitive for exactly those rows.
+// It is used for multi-shard UPDATE and DELETE statements with ORDER BY and LIMIT,
+// where the ordering and limit have to be applied across all the shards.
+type DMLWithInput struct {
+	// Input is the Primitive that returns the primary keys of the rows to modify.
+	Input Primitive
+	// DML is the Primitive that modifies the rows, using the values collected from Input.
+	DML Primitive
+
+	// OutputCols are the column offsets of the Input result that are passed to the DML.
+	OutputCols []int
+	// BVName is the name of the list bind variable that contains the values from the Input.
+	BVName string
+
+	txNeeded
+}
+
+// RouteType implements the Primitive interface.
+func (dml *DMLWithInput) RouteType() string {
+	return "DMLWithInput"
+}
+
+// GetKeyspaceName implements the Primitive interface.
+func (dml *DMLWithInput) GetKeyspaceName() string {
+	return dml.DML.GetKeyspaceName()
+}
+
+// GetTableName implements the Primitive interface.
+func (dml *DMLWithInput) GetTableName() string {
+	return dml.DML.GetTableName()
+}
+
+// GetFields implements the Primitive interface.
+func (dml *DMLWithInput) GetFields(context.Context, VCursor, map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "[BUG] GetFields should not be called")
+}
+
+// TryExecute implements the Primitive interface.
+func (dml *DMLWithInput) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
+	inputRes, err := vcursor.ExecutePrimitive(ctx, dml.Input, bindVars, false)
+	if err != nil {
+		return nil, err
+	}
+	// If no rows are found, there is nothing to modify.
+	if len(inputRes.Rows) == 0 {
+		return &sqltypes.Result{}, nil
+	}
+
+	bv := &querypb.BindVariable{
+		Type: querypb.Type_TUPLE,
+	}
+	for _, row := range inputRes.Rows {
+		if len(dml.OutputCols) == 1 {
+			bv.Values = append(bv.Values, sqltypes.ValueToProto(row[dml.OutputCols[0]]))
+			continue
+		}
+		var tupleValues []sqltypes.Value
+		for _, colIdx := range dml.OutputCols {
+			tupleValues = append(tupleValues, row[colIdx])
+		}
+		bv.Values = append(bv.Values, sqltypes.TupleToProto(tupleValues))
+	}
+
+	newBv := make(map[string]*querypb.BindVariable, len(bindVars)+1)
+	for k, v := range bindVars {
+		newBv[k] = v
+	}
+	newBv[dml.BVName] = bv
+	return vcursor.ExecutePrimitive(ctx, dml.DML, newBv, false)
+}
+
+// TryStreamExecute implements the Primitive interface.
+func (dml *DMLWithInput) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	res, err := dml.TryExecute(ctx, vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	return callback(res)
+}
+
+// Inputs implements the Primitive interface.
+func (dml *DMLWithInput) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{dml.Input, dml.DML}, []map[string]any{{
+		inputName: "Input",
+	}, {
+		inputName: "DML",
+	}}
+}
+
+func (dml *DMLWithInput) description() PrimitiveDescription {
+	other := map[string]any{
+		"BvName":     dml.BVName,
+		"OutputCols": dml.OutputCols,
+	}
+	return PrimitiveDescription{
+		OperatorType: "DMLWithInput",
+		Other:        other,
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// TestDeleteWithInputSingleColumn tests that DMLWithInput passes the values of a single column primary key to the DML.
+func TestDeleteWithInputSingleColumn(t *testing.T) {
+	input := &fakePrimitive{results: []*sqltypes.Result{
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1", "2", "3"),
+	}}
+
+	del := &DMLWithInput{
+		Input: input,
+		DML: &Delete{
+			DML: &DML{
+				Query: "delete from t1 where id in ::dml_vals",
+				RoutingParameters: &RoutingParameters{
+					Opcode:   Unsharded,
+					Keyspace: &vindexes.Keyspace{Name: "ks"},
+				},
+			},
+		},
+		OutputCols: []int{0},
+		BVName:     "dml_vals",
+	}
+
+	vc := newDMLTestVCursor("0")
+	_, err := del.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.0: delete from t1 where id in ::dml_vals {dml_vals: type:TUPLE values:{type:INT64 value:"1"} values:{type:INT64 value:"2"} values:{type:INT64 value:"3"}} true true`,
+	})
+
+	vc.Rewind()
+	input.rewind()
+	err = del.TryStreamExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false, func(result *sqltypes.Result) error { return nil })
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.0: delete from t1 where id in ::dml_vals {dml_vals: type:TUPLE values:{type:INT64 value:"1"} values:{type:INT64 value:"2"} values:{type:INT64 value:"3"}} true true`,
+	})
+}
+
+// TestUpdateWithInputMultiColumn tests that DMLWithInput passes the values of a multi column primary key as tuples to the DML.
+func TestUpdateWithInputMultiColumn(t *testing.T) {
+	input := &fakePrimitive{results: []*sqltypes.Result{
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("id|name", "int64|varchar"), "1|a", "2|b"),
+	}}
+
+	upd := &DMLWithInput{
+		Input: input,
+		DML: &Update{
+			DML: &DML{
+				Query: "update t1 set col = 1 where (id, name) in ::dml_vals",
+				RoutingParameters: &RoutingParameters{
+					Opcode:   Unsharded,
+					Keyspace: &vindexes.Keyspace{Name: "ks"},
+				},
+			},
+		},
+		OutputCols: []int{0, 1},
+		BVName:     "dml_vals",
+	}
+
+	vc := newDMLTestVCursor("0")
+	_, err := upd.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.0: update t1 set col = 1 where (id, name) in ::dml_vals {dml_vals: type:TUPLE values:{type:TUPLE value:"\x89\x02\x011\x950\x01a"} values:{type:TUPLE value:"\x89\x02\x012\x950\x01b"}} true true`,
+	})
+}
+
+// TestDMLWithInputNoRows tests that DMLWithInput does not execute the DML when the input returns no rows.
+func TestDMLWithInputNoRows(t *testing.T) {
+	input := &fakePrimitive{results: []*sqltypes.Result{
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64")),
+	}}
+
+	del := &DMLWithInput{
+		Input: input,
+		DML: &Delete{
+			DML: &DML{
+				Query: "delete from t1 where id in ::dml_vals",
+				RoutingParameters: &RoutingParameters{
+					Opcode:   Unsharded,
+					Keyspace: &vindexes.Keyspace{Name: "ks"},
+				},
+			},
+		},
+		OutputCols: []int{0},
+		BVName:     "dml_vals",
+	}
+
+	vc := newDMLTestVCursor("0")
+	qr, err := del.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, qr.RowsAffected)
+	vc.ExpectLog(t, nil)
+}
@@ -318,6 +318,14 @@ func (t *noopVCursor) MaxMemoryRows() int {
 	return testMaxMemoryRows
 }
 
+func (t *noopVCursor) HashJoinMemoryLimit() int64 {
+	return 0
+}
+
+func (t *noopVCursor) HashJoinSpillDir() string {
+	return ""
+}
+
 func (t *noopVCursor) ExceedsMaxMemoryRows(numRows int) bool {
 	return !testIgnoreMaxMemoryRows && numRows > testMaxMemoryRows
 }
@@ -25,7 +25,6 @@ import (
 	"vitess.io/vitess/go/sqltypes"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/sqlparser"
-	"vitess.io/vitess/go/vt/vtgate/evalengine"
 )
 
 var _ Primitive = (*HashJoin)(nil)
@@ -71,10 +70,14 @@ func (hj *HashJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars ma
 	}
 
 	// build the probe table from the LHS result
-	probeTable, err := hj.buildProbeTable(lresult)
-	if err != nil {
-		return nil, err
+	probeTable := hj.newProbeTable(vcursor)
+	defer probeTable.close()
+	for _, current := range lresult.Rows {
+		if err := probeTable.add(current); err != nil {
+			return nil, err
+		}
 	}
+	lfields := lresult.Fields
 
 	rresult, err := vcursor.ExecutePrimitive(ctx, hj.Right, bindVars, wantfields)
 	if err != nil {
@@ -82,72 +85,39 @@ func (hj *HashJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars ma
 	}
 
 	result := &sqltypes.Result{
-		Fields: joinFields(lresult.Fields, rresult.Fields, hj.Cols),
+		Fields: joinFields(lfields, rresult.Fields, hj.Cols),
+	}
+	addRow := func(lhsRow, rhsRow sqltypes.Row) error {
+		result.Rows = append(result.Rows, joinRows(lhsRow, rhsRow, hj.Cols))
+		return nil
 	}
 
 	for _, currentRHSRow := range rresult.Rows {
-		joinVal := currentRHSRow[hj.RHSKey]
-		if joinVal.IsNull() {
-			continue
-		}
-		hashcode, err := evalengine.NullsafeHashcode(joinVal, hj.Collation, hj.ComparisonType)
-		if err != nil {
+		if err := probeTable.probe(currentRHSRow, addRow); err != nil {
 			return nil, err
 		}
-		lftRows := probeTable[hashcode]
-		for _, currentLHSRow := range lftRows {
-			lhsVal := currentLHSRow[hj.LHSKey]
-			// hash codes can give false positives, so we need to check with a real comparison as well
-			cmp, err := evalengine.NullsafeCompare(joinVal, lhsVal, hj.Collation)
-			if err != nil {
-				return nil, err
-			}
-
-			if cmp == 0 {
-				// we have a match!
-				result.Rows = append(result.Rows, joinRows(currentLHSRow, currentRHSRow, hj.Cols))
-			}
-		}
+	}
+	if err := probeTable.finish(addRow); err != nil {
+		return nil, err
 	}
 
 	return result, nil
 }
 
-func (hj *HashJoin) buildProbeTable(lresult *sqltypes.Result) (map[evalengine.HashCode][]sqltypes.Row, error) {
-	probeTable := map[evalengine.HashCode][]sqltypes.Row{}
-	for _, current := range lresult.Rows {
-		joinVal := current[hj.LHSKey]
-		if joinVal.IsNull() {
-			continue
-		}
-		hashcode, err := evalengine.NullsafeHashcode(joinVal, hj.Collation, hj.ComparisonType)
-		if err != nil {
-			return nil, err
-		}
-		probeTable[hashcode] = append(probeTable[hashcode], current)
-	}
-	return probeTable, nil
-}
-
 // TryStreamExecute implements the Primitive interface
 func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
 	// build the probe table from the LHS result
-	probeTable := map[evalengine.HashCode][]sqltypes.Row{}
+	probeTable := hj.newProbeTable(vcursor)
+	defer probeTable.close()
 	var lfields []*querypb.Field
 	err := vcursor.StreamExecutePrimitive(ctx, hj.Left, bindVars, wantfields, func(result *sqltypes.Result) error {
 		if len(lfields) == 0 && len(result.Fields) != 0 {
 			lfields = result.Fields
 		}
 		for _, current := range result.Rows {
-			joinVal := current[hj.LHSKey]
-			if joinVal.IsNull() {
-				continue
-			}
-			hashcode, err := evalengine.NullsafeHashcode(joinVal, hj.Collation, hj.ComparisonType)
-			if err != nil {
+			if err := probeTable.add(current); err != nil {
 				return err
 			}
-			probeTable[hashcode] = append(probeTable[hashcode], current)
 		}
 		return nil
 	})
@@ -155,7 +125,7 @@ func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 		return err
 	}
 
-	return vcursor.StreamExecutePrimitive(ctx, hj.Right, bindVars, wantfields, func(result *sqltypes.Result) error {
+	err = vcursor.StreamExecutePrimitive(ctx, hj.Right, bindVars, wantfields, func(result *sqltypes.Result) error {
 		// compare the results coming from the RHS with the probe-table
 		res := &sqltypes.Result{}
 		if len(result.Fields) != 0 {
@@ -164,34 +134,41 @@ func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 			}
 		}
 		for _, currentRHSRow := range result.Rows {
-			joinVal := currentRHSRow[hj.RHSKey]
-			if joinVal.IsNull() {
-				continue
-			}
-			hashcode, err := evalengine.NullsafeHashcode(joinVal, hj.Collation, hj.ComparisonType)
+			err := probeTable.probe(currentRHSRow, func(lhsRow, rhsRow sqltypes.Row) error {
+				res.Rows = append(res.Rows, joinRows(lhsRow, rhsRow, hj.Cols))
+				return nil
+			})
 			if err != nil {
 				return err
 			}
-			lftRows := probeTable[hashcode]
-			for _, currentLHSRow := range lftRows {
-				lhsVal := currentLHSRow[hj.LHSKey]
-				// hash codes can give false positives, so we need to check with a real comparison as well
-				cmp, err := evalengine.NullsafeCompare(joinVal, lhsVal, hj.Collation)
-				if err != nil {
-					return err
-				}
-
-				if cmp == 0 {
-					// we have a match!
-					res.Rows = append(res.Rows, joinRows(currentLHSRow, currentRHSRow, hj.Cols))
-				}
-			}
 		}
 		if len(res.Rows) != 0 || len(res.Fields) != 0 {
 			return callback(res)
 		}
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	// if the probe table spilled to disk, the rows are joined partition by partition now
+	res := &sqltypes.Result{}
+	err = probeTable.finish(func(lhsRow, rhsRow sqltypes.Row) error {
+		res.Rows = append(res.Rows, joinRows(lhsRow, rhsRow, hj.Cols))
+		if len(res.Rows) < hashJoinStreamBatchSize {
+			return nil
+		}
+		err := callback(res)
+		res = &sqltypes.Result{}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if len(res.Rows) != 0 {
+		return callback(res)
+	}
+	return nil
 }
 
 // RouteType implements the Primitive interface
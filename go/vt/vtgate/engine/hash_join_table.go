@@ -0,0 +1,364 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+const (
+	// hashJoinPartitionBits is the number of bits of the hash code used to pick the partition of a row.
+	hashJoinPartitionBits = 4
+	hashJoinPartitions    = 1 << hashJoinPartitionBits
+
+	// hashJoinMaxSpillLevel is how many times a partition that still uses too much memory is split
+	// into smaller partitions before it is joined in memory regardless of the memory limit.
+	hashJoinMaxSpillLevel = 3
+
+	// hashJoinStreamBatchSize is the number of rows sent at once when streaming the
+	// rows joined after spilling to disk.
+	hashJoinStreamBatchSize = 1000
+)
+
+// hashJoinProbeTable holds the rows of the LHS of a HashJoin, hashed by their join value.
+//
+// While the rows fit in the memory limit, they are kept in memory and the rows of the RHS are
+// joined with them as they come. Once the limit is exceeded, the table spills to disk: the rows
+// of both sides are partitioned by the hash code of their join value into temporary files, and
+// every pair of partitions is joined in memory when all the rows of the RHS have been read.
+// Since rows with the same join value always land in the same partition, no match is missed.
+type hashJoinProbeTable struct {
+	hj          *HashJoin
+	memoryLimit int64
+	dir         string
+
+	rows   map[evalengine.HashCode][]sqltypes.Row
+	memory int64
+
+	// partitions is only set once the table spilled to disk
+	partitions []*hashJoinPartition
+	// files are all the temporary files created, removed by close
+	files []*os.File
+}
+
+// hashJoinPartition is a pair of temporary files holding the rows of both sides
+// whose hash codes fall in the same partition.
+type hashJoinPartition struct {
+	lhs, rhs *hashJoinSpillFile
+}
+
+type hashJoinSpillFile struct {
+	file *os.File
+	w    *bufio.Writer
+	rows int
+	// memory is the memory the rows would use if loaded
+	memory int64
+}
+
+// newProbeTable creates the probe table of the join, using the memory limit of the vcursor.
+func (hj *HashJoin) newProbeTable(vcursor VCursor) *hashJoinProbeTable {
+	return &hashJoinProbeTable{
+		hj:          hj,
+		memoryLimit: vcursor.HashJoinMemoryLimit(),
+		dir:         vcursor.HashJoinSpillDir(),
+		rows:        map[evalengine.HashCode][]sqltypes.Row{},
+	}
+}
+
+// add adds a row from the LHS to the table.
+func (pt *hashJoinProbeTable) add(row sqltypes.Row) error {
+	joinVal := row[pt.hj.LHSKey]
+	if joinVal.IsNull() {
+		return nil
+	}
+	hashcode, err := evalengine.NullsafeHashcode(joinVal, pt.hj.Collation, pt.hj.ComparisonType)
+	if err != nil {
+		return err
+	}
+	if pt.partitions != nil {
+		return pt.partitions[partitionOf(hashcode, 0)].lhs.write(row)
+	}
+
+	pt.rows[hashcode] = append(pt.rows[hashcode], row)
+	pt.memory += rowMemory(row)
+	if pt.memoryLimit > 0 && pt.memory > pt.memoryLimit {
+		return pt.spill()
+	}
+	return nil
+}
+
+// probe joins a row from the RHS with the rows of the LHS, or keeps it for later if the table spilled to disk.
+func (pt *hashJoinProbeTable) probe(row sqltypes.Row, onMatch func(lhsRow, rhsRow sqltypes.Row) error) error {
+	joinVal := row[pt.hj.RHSKey]
+	if joinVal.IsNull() {
+		return nil
+	}
+	hashcode, err := evalengine.NullsafeHashcode(joinVal, pt.hj.Collation, pt.hj.ComparisonType)
+	if err != nil {
+		return err
+	}
+	if pt.partitions != nil {
+		return pt.partitions[partitionOf(hashcode, 0)].rhs.write(row)
+	}
+	return pt.match(pt.rows[hashcode], row, onMatch)
+}
+
+func (pt *hashJoinProbeTable) match(lhsRows []sqltypes.Row, rhsRow sqltypes.Row, onMatch func(lhsRow, rhsRow sqltypes.Row) error) error {
+	joinVal := rhsRow[pt.hj.RHSKey]
+	for _, lhsRow := range lhsRows {
+		lhsVal := lhsRow[pt.hj.LHSKey]
+		// hash codes can give false positives, so we need to check with a real comparison as well
+		cmp, err := evalengine.NullsafeCompare(joinVal, lhsVal, pt.hj.Collation)
+		if err != nil {
+			return err
+		}
+		if cmp == 0 {
+			// we have a match!
+			if err := onMatch(lhsRow, rhsRow); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// finish joins the partitions spilled to disk, if any. It must be called once all the rows of the RHS have been probed.
+func (pt *hashJoinProbeTable) finish(onMatch func(lhsRow, rhsRow sqltypes.Row) error) error {
+	for _, partition := range pt.partitions {
+		if err := pt.joinPartition(partition, 0, onMatch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// close removes the temporary files.
+func (pt *hashJoinProbeTable) close() {
+	for _, file := range pt.files {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}
+	pt.files = nil
+}
+
+// spill moves the rows of the table to partitions on disk.
+func (pt *hashJoinProbeTable) spill() error {
+	partitions, err := pt.newPartitions()
+	if err != nil {
+		return err
+	}
+	for hashcode, rows := range pt.rows {
+		lhs := partitions[partitionOf(hashcode, 0)].lhs
+		for _, row := range rows {
+			if err := lhs.write(row); err != nil {
+				return err
+			}
+		}
+	}
+	pt.partitions = partitions
+	pt.rows = nil
+	pt.memory = 0
+	return nil
+}
+
+func (pt *hashJoinProbeTable) newPartitions() ([]*hashJoinPartition, error) {
+	partitions := make([]*hashJoinPartition, hashJoinPartitions)
+	for i := range partitions {
+		lhs, err := pt.newSpillFile()
+		if err != nil {
+			return nil, err
+		}
+		rhs, err := pt.newSpillFile()
+		if err != nil {
+			return nil, err
+		}
+		partitions[i] = &hashJoinPartition{lhs: lhs, rhs: rhs}
+	}
+	return partitions, nil
+}
+
+func (pt *hashJoinProbeTable) newSpillFile() (*hashJoinSpillFile, error) {
+	file, err := os.CreateTemp(pt.dir, "vtgate-hashjoin-")
+	if err != nil {
+		return nil, err
+	}
+	pt.files = append(pt.files, file)
+	return &hashJoinSpillFile{file: file, w: bufio.NewWriter(file)}, nil
+}
+
+// joinPartition joins the rows of a partition in memory, or splits it into smaller partitions
+// if its LHS rows would not fit in the memory limit.
+func (pt *hashJoinProbeTable) joinPartition(partition *hashJoinPartition, level int, onMatch func(lhsRow, rhsRow sqltypes.Row) error) error {
+	defer pt.removePartition(partition)
+	if partition.lhs.rows == 0 || partition.rhs.rows == 0 {
+		return nil
+	}
+
+	if partition.lhs.memory > pt.memoryLimit && level < hashJoinMaxSpillLevel {
+		subPartitions, err := pt.newPartitions()
+		if err != nil {
+			return err
+		}
+		err = pt.repartition(partition.lhs, subPartitions, level+1, pt.hj.LHSKey, func(p *hashJoinPartition) *hashJoinSpillFile { return p.lhs })
+		if err != nil {
+			return err
+		}
+		err = pt.repartition(partition.rhs, subPartitions, level+1, pt.hj.RHSKey, func(p *hashJoinPartition) *hashJoinSpillFile { return p.rhs })
+		if err != nil {
+			return err
+		}
+		pt.removePartition(partition)
+		for _, subPartition := range subPartitions {
+			if err := pt.joinPartition(subPartition, level+1, onMatch); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	rows := map[evalengine.HashCode][]sqltypes.Row{}
+	err := partition.lhs.read(func(row sqltypes.Row) error {
+		hashcode, err := evalengine.NullsafeHashcode(row[pt.hj.LHSKey], pt.hj.Collation, pt.hj.ComparisonType)
+		if err != nil {
+			return err
+		}
+		rows[hashcode] = append(rows[hashcode], row)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return partition.rhs.read(func(row sqltypes.Row) error {
+		hashcode, err := evalengine.NullsafeHashcode(row[pt.hj.RHSKey], pt.hj.Collation, pt.hj.ComparisonType)
+		if err != nil {
+			return err
+		}
+		return pt.match(rows[hashcode], row, onMatch)
+	})
+}
+
+func (pt *hashJoinProbeTable) repartition(from *hashJoinSpillFile, partitions []*hashJoinPartition, level int, key int, side func(*hashJoinPartition) *hashJoinSpillFile) error {
+	return from.read(func(row sqltypes.Row) error {
+		hashcode, err := evalengine.NullsafeHashcode(row[key], pt.hj.Collation, pt.hj.ComparisonType)
+		if err != nil {
+			return err
+		}
+		return side(partitions[partitionOf(hashcode, level)]).write(row)
+	})
+}
+
+// removePartition removes the files of a partition that has been joined, to free the disk space early.
+func (pt *hashJoinProbeTable) removePartition(partition *hashJoinPartition) {
+	for _, spillFile := range []*hashJoinSpillFile{partition.lhs, partition.rhs} {
+		for i, file := range pt.files {
+			if file == spillFile.file {
+				_ = file.Close()
+				_ = os.Remove(file.Name())
+				pt.files = append(pt.files[:i], pt.files[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// partitionOf returns the partition of a hash code. Every level uses different bits of
+// the hash code, so that the rows of a partition are spread when it is split.
+func partitionOf(hashcode evalengine.HashCode, level int) int {
+	return int(hashcode>>(level*hashJoinPartitionBits)) & (hashJoinPartitions - 1)
+}
+
+// rowMemory estimates the memory used by a row.
+func rowMemory(row sqltypes.Row) int64 {
+	size := int64(24)
+	for i := range row {
+		size += row[i].CachedSize(true)
+	}
+	return size
+}
+
+// write appends the row to the file. Each value is written as its type,
+// the length of its raw bytes and the raw bytes.
+func (sf *hashJoinSpillFile) write(row sqltypes.Row) error {
+	var buf [binary.MaxVarintLen64]byte
+	if _, err := sf.w.Write(binary.AppendUvarint(buf[:0], uint64(len(row)))); err != nil {
+		return err
+	}
+	for _, value := range row {
+		if _, err := sf.w.Write(binary.AppendUvarint(buf[:0], uint64(value.Type()))); err != nil {
+			return err
+		}
+		raw := value.Raw()
+		if _, err := sf.w.Write(binary.AppendUvarint(buf[:0], uint64(len(raw)))); err != nil {
+			return err
+		}
+		if _, err := sf.w.Write(raw); err != nil {
+			return err
+		}
+	}
+	sf.rows++
+	sf.memory += rowMemory(row)
+	return nil
+}
+
+// read calls onRow for all the rows written to the file.
+func (sf *hashJoinSpillFile) read(onRow func(sqltypes.Row) error) error {
+	if err := sf.w.Flush(); err != nil {
+		return err
+	}
+	if _, err := sf.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	r := bufio.NewReader(sf.file)
+	for {
+		columns, err := binary.ReadUvarint(r)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		row := make(sqltypes.Row, columns)
+		for i := range row {
+			typ, err := binary.ReadUvarint(r)
+			if err != nil {
+				return err
+			}
+			length, err := binary.ReadUvarint(r)
+			if err != nil {
+				return err
+			}
+			var raw []byte
+			if length > 0 {
+				raw = make([]byte, length)
+				if _, err := io.ReadFull(r, raw); err != nil {
+					return err
+				}
+			}
+			row[i] = sqltypes.MakeTrusted(sqltypes.Type(typ), raw)
+		}
+		if err := onRow(row); err != nil {
+			return err
+		}
+	}
+}
@@ -18,6 +18,9 @@ package engine
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -145,3 +148,74 @@ func TestHashJoinExecuteDifferentType(t *testing.T) {
 		"5|c| 5.0toto|g",
 	))
 }
+
+type spillingVCursor struct {
+	noopVCursor
+	dir string
+}
+
+func (t *spillingVCursor) HashJoinMemoryLimit() int64 {
+	return 256
+}
+
+func (t *spillingVCursor) HashJoinSpillDir() string {
+	return t.dir
+}
+
+func TestHashJoinSpillToDisk(t *testing.T) {
+	lhs := []string{}
+	for i := 0; i < 200; i++ {
+		lhs = append(lhs, fmt.Sprintf("%d|left_%d", i%50, i))
+	}
+	lhs = append(lhs, "null|left_null")
+	rhs := []string{}
+	for i := 0; i < 100; i++ {
+		rhs = append(rhs, fmt.Sprintf("%d|right_%d", i%60, i))
+	}
+	rhs = append(rhs, "null|right_null")
+
+	newJoin := func() *HashJoin {
+		return &HashJoin{
+			Opcode: InnerJoin,
+			Left: &fakePrimitive{
+				results: []*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("col1|col2", "int64|varchar"), lhs...)},
+			},
+			Right: &fakePrimitive{
+				results: []*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("col3|col4", "int64|varchar"), rhs...)},
+			},
+			Cols:           []int{-1, -2, 2},
+			LHSKey:         0,
+			RHSKey:         0,
+			ComparisonType: querypb.Type_INT64,
+		}
+	}
+	sortedRows := func(qr *sqltypes.Result) []string {
+		var rows []string
+		for _, row := range qr.Rows {
+			rows = append(rows, fmt.Sprintf("%v", row))
+		}
+		sort.Strings(rows)
+		return rows
+	}
+
+	want, err := newJoin().TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	require.Len(t, want.Rows, 360)
+
+	vcursor := &spillingVCursor{dir: t.TempDir()}
+
+	got, err := newJoin().TryExecute(context.Background(), vcursor, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	require.Equal(t, want.Fields, got.Fields)
+	require.Equal(t, sortedRows(want), sortedRows(got))
+
+	got, err = wrapStreamExecute(newJoin(), vcursor, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	require.Equal(t, want.Fields, got.Fields)
+	require.Equal(t, sortedRows(want), sortedRows(got))
+
+	// the spill files are removed once the join is done
+	entries, err := os.ReadDir(vcursor.dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
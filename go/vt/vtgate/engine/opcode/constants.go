@@ -186,3 +186,89 @@ func (code AggregateOpcode) IsDistinct() bool {
 		return false
 	}
 }
+
+// WindowOpcode is the opcode for the functions evaluated by the Window primitive.
+type WindowOpcode int
+
+// These constants list the possible window opcodes.
+const (
+	WindowUnassigned = WindowOpcode(iota)
+	WindowRowNumber
+	WindowRank
+	WindowDenseRank
+	WindowLag
+	WindowLead
+	WindowCount
+	WindowCountStar
+	WindowSum
+	WindowAvg
+	WindowMin
+	WindowMax
+)
+
+var WindowName = map[WindowOpcode]string{
+	WindowRowNumber: "row_number",
+	WindowRank:      "rank",
+	WindowDenseRank: "dense_rank",
+	WindowLag:       "lag",
+	WindowLead:      "lead",
+	WindowCount:     "count",
+	WindowCountStar: "count_star",
+	WindowSum:       "sum",
+	WindowAvg:       "avg",
+	WindowMin:       "min",
+	WindowMax:       "max",
+}
+
+func (code WindowOpcode) String() string {
+	name := WindowName[code]
+	if name == "" {
+		name = "ERROR"
+	}
+	return name
+}
+
+// MarshalJSON serializes the WindowOpcode as a JSON string.
+// It's used for testing and diagnostics.
+func (code WindowOpcode) MarshalJSON() ([]byte, error) {
+	return ([]byte)(fmt.Sprintf("\"%s\"", code.String())), nil
+}
+
+// IsRanking returns true for the window functions that only depend on the position
+// of the row in its partition, and not on the value of an argument or on the frame.
+func (code WindowOpcode) IsRanking() bool {
+	switch code {
+	case WindowRowNumber, WindowRank, WindowDenseRank:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsAggregation returns true for the aggregation functions evaluated over a window frame.
+func (code WindowOpcode) IsAggregation() bool {
+	switch code {
+	case WindowCount, WindowCountStar, WindowSum, WindowAvg, WindowMin, WindowMax:
+		return true
+	default:
+		return false
+	}
+}
+
+// Type returns the sql type produced by the window function, given the type of its argument
+func (code WindowOpcode) Type(typ querypb.Type) querypb.Type {
+	switch code {
+	case WindowRowNumber, WindowRank, WindowDenseRank:
+		return sqltypes.Uint64
+	case WindowCount, WindowCountStar:
+		return sqltypes.Int64
+	case WindowSum:
+		return AggregateSum.Type(typ)
+	case WindowAvg:
+		return AggregateAvg.Type(typ)
+	case WindowMin, WindowMax, WindowLag, WindowLead:
+		return typ
+	default:
+		return sqltypes.Null
+	}
+}
@@ -884,6 +884,75 @@ func TestMultiDistinct(t *testing.T) {
 	utils.MustMatch(t, want, results)
 }
 
+func TestMultiDistinctUnordered(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"c1|c2|c3",
+		"int64|int64|int64",
+	)
+	// the input is only sorted by c1 and c2, so the distinct values of c3 have to be tracked in memory
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			fields,
+			"10|1|3",
+			"10|1|1",
+			"10|2|3",
+			"10|2|null",
+			"10|3|1",
+			"20|1|2",
+			"20|2|2",
+			"20|3|5",
+		)},
+	}
+
+	unordered := NewAggregateParam(AggregateSumDistinct, 2, "sum(distinct c3)")
+	unordered.UnorderedDistinct = true
+	countUnordered := NewAggregateParam(AggregateCountDistinct, 2, "count(distinct c3)")
+	countUnordered.UnorderedDistinct = true
+	oa := &OrderedAggregate{
+		Aggregates: []*AggregateParams{
+			NewAggregateParam(AggregateCountDistinct, 1, "count(distinct c2)"),
+			unordered,
+		},
+		GroupByKeys: []*GroupByParams{{KeyCol: 0}},
+		Input:       fp,
+	}
+
+	want := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"c1|count(distinct c2)|sum(distinct c3)",
+			"int64|int64|decimal",
+		),
+		`10|3|4`,
+		`20|3|7`,
+	)
+
+	qr, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	utils.MustMatch(t, want, qr)
+
+	fp.rewind()
+	results := &sqltypes.Result{}
+	err = oa.TryStreamExecute(context.Background(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+		if qr.Fields != nil {
+			results.Fields = qr.Fields
+		}
+		results.Rows = append(results.Rows, qr.Rows...)
+		return nil
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, want, results)
+
+	// count distinct on the unordered column, null values are not counted
+	fp.rewind()
+	oa.Aggregates = []*AggregateParams{
+		NewAggregateParam(AggregateCountDistinct, 1, "count(distinct c2)"),
+		countUnordered,
+	}
+	qr, err = oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, `[[INT64(10) INT64(3) INT64(2)] [INT64(20) INT64(3) INT64(2)]]`, fmt.Sprintf("%v", qr.Rows))
+}
+
 func TestOrderedAggregateCollate(t *testing.T) {
 	fields := sqltypes.MakeTestFields(
 		"col|count(*)",
@@ -55,6 +55,14 @@ type (
 		// if the max memory rows override directive is set to true
 		ExceedsMaxMemoryRows(numRows int) bool
 
+		// HashJoinMemoryLimit returns the maximum memory in bytes a hash join can use for the rows of its LHS
+		// before spilling them to disk. Zero disables spilling.
+		HashJoinMemoryLimit() int64
+
+		// HashJoinSpillDir returns the directory of the temporary files of the hash joins spilling to disk.
+		// If empty, the default directory for temporary files is used.
+		HashJoinSpillDir() string
+
 		Execute(ctx context.Context, method string, query string, bindVars map[string]*querypb.BindVariable, rollbackOnError bool, co vtgatepb.CommitOrder) (*sqltypes.Result, error)
 		AutocommitApproval() bool
 
@@ -0,0 +1,200 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sysvars"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+var _ Primitive = (*RecurseCTE)(nil)
+
+// defaultMaxRecursionDepth is the default value of @@cte_max_recursion_depth in MySQL
+const defaultMaxRecursionDepth = 1000
+
+// RecurseCTE evaluates a recursive common table expression.
+// The Seed produces the first rows of the result. Every row of the previous
+// iteration is then sent to the Term through bind variables, and the rows the
+// Term produces make up the next iteration. This goes on until an iteration
+// produces no rows, or until the recursion is deeper than @@cte_max_recursion_depth.
+type RecurseCTE struct {
+	// Seed is the non-recursive part of the CTE
+	Seed Primitive
+	// Term is the recursive part of the CTE
+	Term Primitive
+
+	// Vars defines the columns of the rows from the previous iteration
+	// that need to be sent as bind variables to the Term
+	Vars map[string]int `json:",omitempty"`
+}
+
+// TryExecute performs a non-streaming exec.
+func (r *RecurseCTE) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	res, err := vcursor.ExecutePrimitive(ctx, r.Seed, bindVars, wantfields)
+	if err != nil {
+		return nil, err
+	}
+
+	maxDepth := maxRecursionDepth(vcursor)
+	joinVars := make(map[string]*querypb.BindVariable)
+	// recurseRows contains the rows the next iteration is built from
+	recurseRows := res.Rows
+	for iteration := 1; len(recurseRows) > 0; iteration++ {
+		var nextRows [][]sqltypes.Value
+		for _, row := range recurseRows {
+			for k, col := range r.Vars {
+				joinVars[k] = sqltypes.ValueBindVariable(row[col])
+			}
+			rresult, err := vcursor.ExecutePrimitive(ctx, r.Term, combineVars(bindVars, joinVars), false)
+			if err != nil {
+				return nil, err
+			}
+			nextRows = append(nextRows, rresult.Rows...)
+		}
+		if len(nextRows) > 0 && iteration > maxDepth {
+			return nil, vterrors.VT09020(iteration)
+		}
+		res.Rows = append(res.Rows, nextRows...)
+		if vcursor.ExceedsMaxMemoryRows(len(res.Rows)) {
+			return nil, fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+		}
+		recurseRows = nextRows
+	}
+	return res, nil
+}
+
+// TryStreamExecute performs a streaming exec.
+func (r *RecurseCTE) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	var mu sync.Mutex
+
+	var recurseRows [][]sqltypes.Value
+	err := vcursor.StreamExecutePrimitive(ctx, r.Seed, bindVars, wantfields, func(qr *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		recurseRows = append(recurseRows, qr.Rows...)
+		return callback(qr)
+	})
+	if err != nil {
+		return err
+	}
+
+	maxDepth := maxRecursionDepth(vcursor)
+	joinVars := make(map[string]*querypb.BindVariable)
+	// totalRows is the number of rows held in memory so far, across all the iterations
+	totalRows := len(recurseRows)
+	for iteration := 1; len(recurseRows) > 0; iteration++ {
+		// the rows of an iteration are only sent once the whole iteration
+		// is known to be within the recursion depth
+		var nextRows [][]sqltypes.Value
+		for _, row := range recurseRows {
+			for k, col := range r.Vars {
+				joinVars[k] = sqltypes.ValueBindVariable(row[col])
+			}
+			err := vcursor.StreamExecutePrimitive(ctx, r.Term, combineVars(bindVars, joinVars), false, func(qr *sqltypes.Result) error {
+				if len(qr.Rows) == 0 {
+					return nil
+				}
+				if iteration > maxDepth {
+					return vterrors.VT09020(iteration)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				nextRows = append(nextRows, qr.Rows...)
+				if vcursor.ExceedsMaxMemoryRows(totalRows + len(nextRows)) {
+					return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if len(nextRows) > 0 {
+			if err := callback(&sqltypes.Result{Rows: nextRows}); err != nil {
+				return err
+			}
+		}
+		totalRows += len(nextRows)
+		recurseRows = nextRows
+	}
+	return nil
+}
+
+// maxRecursionDepth returns the number of iterations a recursive CTE is allowed to do in the current session
+func maxRecursionDepth(vcursor VCursor) int {
+	depth := defaultMaxRecursionDepth
+	vcursor.Session().GetSystemVariables(func(k string, v string) {
+		if k != sysvars.CTEMaxRecursionDepth.Name {
+			return
+		}
+		if val, err := strconv.Atoi(v); err == nil {
+			depth = val
+		}
+	})
+	return depth
+}
+
+// GetFields fetches the field info.
+func (r *RecurseCTE) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return r.Seed.GetFields(ctx, vcursor, bindVars)
+}
+
+// Inputs returns the input primitives for this recursive CTE
+func (r *RecurseCTE) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{r.Seed, r.Term}, nil
+}
+
+// RouteType returns a description of the query routing type used by the primitive
+func (r *RecurseCTE) RouteType() string {
+	return "RecurseCTE"
+}
+
+// GetKeyspaceName specifies the Keyspace that this primitive routes to.
+func (r *RecurseCTE) GetKeyspaceName() string {
+	if r.Seed.GetKeyspaceName() == r.Term.GetKeyspaceName() {
+		return r.Seed.GetKeyspaceName()
+	}
+	return r.Seed.GetKeyspaceName() + "_" + r.Term.GetKeyspaceName()
+}
+
+// GetTableName specifies the table that this primitive routes to.
+func (r *RecurseCTE) GetTableName() string {
+	return r.Seed.GetTableName()
+}
+
+// NeedsTransaction implements the Primitive interface
+func (r *RecurseCTE) NeedsTransaction() bool {
+	return r.Seed.NeedsTransaction() || r.Term.NeedsTransaction()
+}
+
+func (r *RecurseCTE) description() PrimitiveDescription {
+	other := map[string]any{}
+	if len(r.Vars) > 0 {
+		other["JoinVars"] = orderedStringIntMap(r.Vars)
+	}
+	return PrimitiveDescription{
+		OperatorType: "RecurseCTE",
+		Other:        other,
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// sysVarVCursor is a vcursor with a fixed set of session system variables
+type sysVarVCursor struct {
+	noopVCursor
+	sysVars map[string]string
+}
+
+func (s *sysVarVCursor) Session() SessionActions {
+	return s
+}
+
+func (s *sysVarVCursor) GetSystemVariables(f func(k string, v string)) {
+	for k, v := range s.sysVars {
+		f(k, v)
+	}
+}
+
+func orgChartCTE() (*RecurseCTE, *fakePrimitive) {
+	fields := sqltypes.MakeTestFields("id|name", "int64|varchar")
+	seed := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "1|ceo"),
+		},
+	}
+	term := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "2|cto", "3|cfo"),
+			sqltypes.MakeTestResult(fields, "4|dev"),
+			sqltypes.MakeTestResult(fields),
+			sqltypes.MakeTestResult(fields),
+		},
+	}
+	return &RecurseCTE{
+		Seed: seed,
+		Term: term,
+		Vars: map[string]int{"manager_id": 0},
+	}, term
+}
+
+func TestRecurseCTEExecute(t *testing.T) {
+	rcte, term := orgChartCTE()
+	bv := map[string]*querypb.BindVariable{}
+
+	r, err := rcte.TryExecute(context.Background(), &sysVarVCursor{}, bv, true)
+	require.NoError(t, err)
+
+	term.ExpectLog(t, []string{
+		`Execute manager_id: type:INT64 value:"1" false`,
+		`Execute manager_id: type:INT64 value:"2" false`,
+		`Execute manager_id: type:INT64 value:"3" false`,
+		`Execute manager_id: type:INT64 value:"4" false`,
+	})
+	expectResult(t, "rcte.Execute", r, sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("id|name", "int64|varchar"),
+		"1|ceo",
+		"2|cto",
+		"3|cfo",
+		"4|dev",
+	))
+}
+
+func TestRecurseCTEStreamExecute(t *testing.T) {
+	rcte, term := orgChartCTE()
+	bv := map[string]*querypb.BindVariable{}
+
+	r, err := wrapStreamExecute(rcte, &sysVarVCursor{}, bv, true)
+	require.NoError(t, err)
+
+	term.ExpectLog(t, []string{
+		`StreamExecute manager_id: type:INT64 value:"1" false`,
+		`StreamExecute manager_id: type:INT64 value:"2" false`,
+		`StreamExecute manager_id: type:INT64 value:"3" false`,
+		`StreamExecute manager_id: type:INT64 value:"4" false`,
+	})
+	expectResult(t, "rcte.StreamExecute", r, sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("id|name", "int64|varchar"),
+		"1|ceo",
+		"2|cto",
+		"3|cfo",
+		"4|dev",
+	))
+}
+
+func TestRecurseCTEMaxRecursionDepth(t *testing.T) {
+	vc := &sysVarVCursor{sysVars: map[string]string{"cte_max_recursion_depth": "1"}}
+	bv := map[string]*querypb.BindVariable{}
+
+	rcte, _ := orgChartCTE()
+	_, err := rcte.TryExecute(context.Background(), vc, bv, true)
+	require.EqualError(t, err, "VT09020: Recursive query aborted after 2 iterations. Try increasing @@cte_max_recursion_depth to a larger value")
+
+	rcte, _ = orgChartCTE()
+	_, err = wrapStreamExecute(rcte, vc, bv, true)
+	require.EqualError(t, err, "VT09020: Recursive query aborted after 2 iterations. Try increasing @@cte_max_recursion_depth to a larger value")
+
+	// the recursion stops by itself after two iterations
+	vc.sysVars["cte_max_recursion_depth"] = "2"
+	rcte, _ = orgChartCTE()
+	r, err := rcte.TryExecute(context.Background(), vc, bv, true)
+	require.NoError(t, err)
+	require.Len(t, r.Rows, 4)
+}
+
+func TestRecurseCTEStreamExecuteLimits(t *testing.T) {
+	bv := map[string]*querypb.BindVariable{}
+	streamedRows := func(vc VCursor) ([][]sqltypes.Value, error) {
+		rcte, _ := orgChartCTE()
+		var rows [][]sqltypes.Value
+		err := rcte.TryStreamExecute(context.Background(), vc, bv, true, func(qr *sqltypes.Result) error {
+			rows = append(rows, qr.Rows...)
+			return nil
+		})
+		return rows, err
+	}
+
+	// the rows of the iteration going over the recursion depth are not sent
+	rows, err := streamedRows(&sysVarVCursor{sysVars: map[string]string{"cte_max_recursion_depth": "1"}})
+	require.EqualError(t, err, "VT09020: Recursive query aborted after 2 iterations. Try increasing @@cte_max_recursion_depth to a larger value")
+	require.Len(t, rows, 3)
+
+	// no iteration has more than 2 rows, but all the iterations together have 4
+	saveMax := testMaxMemoryRows
+	testMaxMemoryRows = 3
+	defer func() {
+		testMaxMemoryRows = saveMax
+	}()
+	rows, err = streamedRows(&sysVarVCursor{})
+	require.EqualError(t, err, "in-memory row count exceeded allowed limit of 3")
+	require.Len(t, rows, 3)
+
+	rcte, _ := orgChartCTE()
+	_, err = rcte.TryExecute(context.Background(), &sysVarVCursor{}, bv, true)
+	require.EqualError(t, err, "in-memory row count exceeded allowed limit of 3")
+}
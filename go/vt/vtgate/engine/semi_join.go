@@ -23,11 +23,17 @@ import (
 
 	"vitess.io/vitess/go/sqltypes"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 )
 
 var _ Primitive = (*SemiJoin)(nil)
 
 // SemiJoin specifies the parameters for a SemiJoin primitive.
+// By default, a row from the LHS is returned if the RHS returns any rows for it.
+// When Predicate is set or ProjectResult is true, the RHS is a correlated subquery
+// that is evaluated once per LHS row, and its result is used to filter or extend the row.
 type SemiJoin struct {
 	// Left and Right are the LHS and RHS primitives
 	// of the SemiJoin. They can be any primitive.
@@ -45,6 +51,22 @@ type SemiJoin struct {
 	// be built from the LHS result before invoking
 	// the RHS subqquery.
 	Vars map[string]int `json:",omitempty"`
+
+	// Opcode, SubqueryResult and HasValues describe how the result of the RHS
+	// is bound to bind variables when the subquery is evaluated for every LHS row.
+	// This works the same way as for UncorrelatedSubquery.
+	Opcode         PulloutOpcode `json:",omitempty"`
+	SubqueryResult string        `json:",omitempty"`
+	HasValues      string        `json:",omitempty"`
+
+	// Predicate is evaluated against every LHS row, using the bind variables
+	// produced by the RHS for that row. Only the rows for which it is true are returned.
+	Predicate    evalengine.Expr `json:",omitempty"`
+	ASTPredicate sqlparser.Expr  `json:",omitempty"`
+
+	// ProjectResult makes the SemiJoin return all LHS rows,
+	// with the value of the subquery added as the first column.
+	ProjectResult bool `json:",omitempty"`
 }
 
 // TryExecute performs a non-streaming exec.
@@ -55,6 +77,11 @@ func (jn *SemiJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars ma
 		return nil, err
 	}
 	result := &sqltypes.Result{Fields: projectFields(lresult.Fields, jn.Cols)}
+	if jn.ProjectResult && wantfields {
+		if result.Fields, err = jn.addProjectedField(ctx, vcursor, bindVars, result.Fields); err != nil {
+			return nil, err
+		}
+	}
 	for _, lrow := range lresult.Rows {
 		for k, col := range jn.Vars {
 			joinVars[k] = sqltypes.ValueBindVariable(lrow[col])
@@ -63,8 +90,12 @@ func (jn *SemiJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars ma
 		if err != nil {
 			return nil, err
 		}
-		if len(rresult.Rows) > 0 {
-			result.Rows = append(result.Rows, projectRows(lrow, jn.Cols))
+		row, err := jn.applyRow(ctx, vcursor, bindVars, lrow, rresult)
+		if err != nil {
+			return nil, err
+		}
+		if row != nil {
+			result.Rows = append(result.Rows, row)
 		}
 	}
 	return result, nil
@@ -72,6 +103,9 @@ func (jn *SemiJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars ma
 
 // TryStreamExecute performs a streaming exec.
 func (jn *SemiJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	if jn.isApply() {
+		return jn.streamApply(ctx, vcursor, bindVars, wantfields, callback)
+	}
 	joinVars := make(map[string]*querypb.BindVariable)
 	err := vcursor.StreamExecutePrimitive(ctx, jn.Left, bindVars, wantfields, func(lresult *sqltypes.Result) error {
 		result := &sqltypes.Result{Fields: projectFields(lresult.Fields, jn.Cols)}
@@ -96,9 +130,133 @@ func (jn *SemiJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 	return err
 }
 
+// streamApply streams the LHS, and evaluates the subquery for every row of it
+func (jn *SemiJoin) streamApply(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	joinVars := make(map[string]*querypb.BindVariable)
+	return vcursor.StreamExecutePrimitive(ctx, jn.Left, bindVars, wantfields, func(lresult *sqltypes.Result) error {
+		result := &sqltypes.Result{Fields: projectFields(lresult.Fields, jn.Cols)}
+		if jn.ProjectResult && result.Fields != nil {
+			var err error
+			if result.Fields, err = jn.addProjectedField(ctx, vcursor, bindVars, result.Fields); err != nil {
+				return err
+			}
+		}
+		for _, lrow := range lresult.Rows {
+			for k, col := range jn.Vars {
+				joinVars[k] = sqltypes.ValueBindVariable(lrow[col])
+			}
+			rresult := &sqltypes.Result{}
+			err := vcursor.StreamExecutePrimitive(ctx, jn.Right, combineVars(bindVars, joinVars), false, func(qr *sqltypes.Result) error {
+				rresult.Rows = append(rresult.Rows, qr.Rows...)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			row, err := jn.applyRow(ctx, vcursor, bindVars, lrow, rresult)
+			if err != nil {
+				return err
+			}
+			if row != nil {
+				result.Rows = append(result.Rows, row)
+			}
+		}
+		return callback(result)
+	})
+}
+
+func (jn *SemiJoin) isApply() bool {
+	return jn.Predicate != nil || jn.ProjectResult
+}
+
+// applyRow uses the result of the RHS for a single LHS row to produce the output row.
+// It returns nil if the row should be filtered out.
+func (jn *SemiJoin) applyRow(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, lrow []sqltypes.Value, rresult *sqltypes.Result) ([]sqltypes.Value, error) {
+	switch {
+	case jn.ProjectResult:
+		value, err := jn.subqueryValue(rresult)
+		if err != nil {
+			return nil, err
+		}
+		return append([]sqltypes.Value{value}, projectRows(lrow, jn.Cols)...), nil
+	case jn.Predicate != nil:
+		subqueryVars := make(map[string]*querypb.BindVariable, len(bindVars)+2)
+		for k, v := range bindVars {
+			subqueryVars[k] = v
+		}
+		if err := bindSubqueryResult(jn.Opcode, jn.SubqueryResult, jn.HasValues, rresult, subqueryVars); err != nil {
+			return nil, err
+		}
+		env := evalengine.NewExpressionEnv(ctx, subqueryVars, vcursor)
+		env.Row = lrow
+		evalResult, err := env.Evaluate(jn.Predicate)
+		if err != nil {
+			return nil, err
+		}
+		if !evalResult.ToBoolean() {
+			return nil, nil
+		}
+		return projectRows(lrow, jn.Cols), nil
+	default:
+		if len(rresult.Rows) == 0 {
+			return nil, nil
+		}
+		return projectRows(lrow, jn.Cols), nil
+	}
+}
+
+// subqueryValue returns the value a subquery in the SELECT list evaluates to
+func (jn *SemiJoin) subqueryValue(rresult *sqltypes.Result) (sqltypes.Value, error) {
+	if jn.Opcode == PulloutExists {
+		if len(rresult.Rows) == 0 {
+			return sqltypes.NewInt64(0), nil
+		}
+		return sqltypes.NewInt64(1), nil
+	}
+	switch len(rresult.Rows) {
+	case 0:
+		return sqltypes.NULL, nil
+	case 1:
+		if len(rresult.Rows[0]) != 1 {
+			return sqltypes.NULL, errSqColumn
+		}
+		return rresult.Rows[0][0], nil
+	default:
+		return sqltypes.NULL, errSqRow
+	}
+}
+
+// addProjectedField adds the field of the projected subquery value in front of the LHS fields
+func (jn *SemiJoin) addProjectedField(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, fields []*querypb.Field) ([]*querypb.Field, error) {
+	field := &querypb.Field{Name: "exists", Type: sqltypes.Int64}
+	if jn.Opcode != PulloutExists {
+		joinVars := make(map[string]*querypb.BindVariable, len(jn.Vars))
+		for k := range jn.Vars {
+			joinVars[k] = sqltypes.NullBindVariable
+		}
+		rresult, err := jn.Right.GetFields(ctx, vcursor, combineVars(bindVars, joinVars))
+		if err != nil {
+			return nil, err
+		}
+		if len(rresult.Fields) != 1 {
+			return nil, errSqColumn
+		}
+		field = rresult.Fields[0]
+	}
+	return append([]*querypb.Field{field}, fields...), nil
+}
+
 // GetFields fetches the field info.
 func (jn *SemiJoin) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
-	return jn.Left.GetFields(ctx, vcursor, bindVars)
+	res, err := jn.Left.GetFields(ctx, vcursor, bindVars)
+	if err != nil || !jn.ProjectResult {
+		return res, err
+	}
+	res.Fields, err = jn.addProjectedField(ctx, vcursor, bindVars, res.Fields)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
 // Inputs returns the input primitives for this SemiJoin
@@ -141,8 +299,31 @@ func (jn *SemiJoin) description() PrimitiveDescription {
 	if len(jn.Vars) > 0 {
 		other["JoinVars"] = orderedStringIntMap(jn.Vars)
 	}
+	if !jn.isApply() {
+		return PrimitiveDescription{
+			OperatorType: "SemiJoin",
+			Other:        other,
+		}
+	}
+	if jn.ASTPredicate != nil {
+		other["Predicate"] = sqlparser.String(jn.ASTPredicate)
+	}
+	var pulloutVars []string
+	if jn.HasValues != "" {
+		pulloutVars = append(pulloutVars, jn.HasValues)
+	}
+	if jn.SubqueryResult != "" {
+		pulloutVars = append(pulloutVars, jn.SubqueryResult)
+	}
+	if len(pulloutVars) > 0 {
+		other["PulloutVars"] = pulloutVars
+	}
+	if jn.ProjectResult {
+		other["ProjectResult"] = true
+	}
 	return PrimitiveDescription{
 		OperatorType: "SemiJoin",
+		Variant:      jn.Opcode.String(),
 		Other:        other,
 	}
 }
@@ -20,6 +20,7 @@ import (
 	"context"
 	"testing"
 
+	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/test/utils"
 
 	"github.com/stretchr/testify/require"
@@ -27,6 +28,9 @@ import (
 	"vitess.io/vitess/go/sqltypes"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 )
 
 func TestSemiJoinExecute(t *testing.T) {
@@ -161,3 +165,111 @@ func TestSemiJoinStreamExecute(t *testing.T) {
 		"4|d|dd",
 	))
 }
+
+func TestSemiJoinApplyPredicate(t *testing.T) {
+	leftFields := sqltypes.MakeTestFields("id|col", "int64|int64")
+	leftPrim := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(leftFields, "1|10", "2|20", "3|30"),
+		},
+	}
+	rightFields := sqltypes.MakeTestFields("c", "int64")
+	rightPrim := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(rightFields, "5", "10"),
+			sqltypes.MakeTestResult(rightFields, "5"),
+			sqltypes.MakeTestResult(rightFields),
+		},
+	}
+
+	// id in (select c from t where t.x = :col)
+	predicate := sqlparser.AndExpressions(
+		sqlparser.NewArgument("__sq_has_values"),
+		&sqlparser.ComparisonExpr{
+			Operator: sqlparser.InOp,
+			Left:     sqlparser.NewColName("col"),
+			Right:    sqlparser.ListArg("__sq1"),
+		},
+	)
+	pred, err := evalengine.Translate(predicate, &evalengine.Config{
+		Collation:     collations.CollationUtf8mb4ID,
+		ResolveColumn: evalengine.FieldResolver(leftFields).Column,
+	})
+	require.NoError(t, err)
+
+	jn := &SemiJoin{
+		Left:           leftPrim,
+		Right:          rightPrim,
+		Vars:           map[string]int{"col": 1},
+		Opcode:         opcode.PulloutIn,
+		SubqueryResult: "__sq1",
+		HasValues:      "__sq_has_values",
+		Predicate:      pred,
+		ASTPredicate:   predicate,
+	}
+
+	r, err := jn.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	rightPrim.ExpectLog(t, []string{
+		`Execute col: type:INT64 value:"10" false`,
+		`Execute col: type:INT64 value:"20" false`,
+		`Execute col: type:INT64 value:"30" false`,
+	})
+	expectResult(t, "jn.Execute", r, sqltypes.MakeTestResult(leftFields, "1|10"))
+
+	leftPrim.rewind()
+	rightPrim.rewind()
+	r, err = wrapStreamExecute(jn, &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	expectResult(t, "jn.StreamExecute", r, sqltypes.MakeTestResult(leftFields, "1|10"))
+}
+
+func TestSemiJoinProjectResult(t *testing.T) {
+	leftFields := sqltypes.MakeTestFields("id|col", "int64|int64")
+	leftPrim := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(leftFields, "1|10", "2|20"),
+		},
+	}
+	rightFields := sqltypes.MakeTestFields("c", "int64")
+	rightPrim := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(rightFields), // used for GetFields
+			sqltypes.MakeTestResult(rightFields, "100"),
+			sqltypes.MakeTestResult(rightFields),
+		},
+	}
+
+	jn := &SemiJoin{
+		Left:          leftPrim,
+		Right:         rightPrim,
+		Vars:          map[string]int{"col": 1},
+		Opcode:        opcode.PulloutValue,
+		ProjectResult: true,
+	}
+
+	r, err := jn.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	rightPrim.ExpectLog(t, []string{
+		`GetFields col: `,
+		`Execute col:  true`,
+		`Execute col: type:INT64 value:"10" false`,
+		`Execute col: type:INT64 value:"20" false`,
+	})
+	expectResult(t, "jn.Execute", r, sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("c|id|col", "int64|int64|int64"),
+		"100|1|10",
+		"null|2|20",
+	))
+
+	// a scalar subquery can't return more than one row
+	leftPrim.rewind()
+	rightPrim = &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(rightFields, "100", "200"),
+		},
+	}
+	jn.Right = rightPrim
+	_, err = jn.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, false)
+	require.EqualError(t, err, "subquery returned more than one row")
+}
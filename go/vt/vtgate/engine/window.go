@@ -0,0 +1,610 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+var _ Primitive = (*Window)(nil)
+
+// Window is a primitive that evaluates window functions at the vtgate level.
+// It expects the underlying primitive to feed results sorted by the
+// partition keys and then by the window ordering. One partition is buffered
+// at a time; once it's complete, the window functions are evaluated for all its
+// rows, and the result of each function replaces the value found in its column.
+type Window struct {
+	// PartitionBy specifies the input values that split the rows into partitions.
+	PartitionBy []*GroupByParams
+
+	// OrderBy is the ordering of the rows inside a partition. It is used
+	// to decide which rows are peers of each other.
+	OrderBy evalengine.Comparison
+
+	// Functions specifies the window functions to evaluate and the columns they read from and write to.
+	Functions []*WindowFuncParams
+
+	// TruncateColumnCount specifies the number of columns to return
+	// in the final result. Rest of the columns are truncated
+	// from the result received. If 0, no truncation happens.
+	TruncateColumnCount int `json:",omitempty"`
+
+	// Input is the primitive that will feed into this Primitive.
+	Input Primitive
+}
+
+// WindowFuncParams specify the parameters for each window function.
+type WindowFuncParams struct {
+	Opcode WindowOpcode
+	// Col is the input column holding the argument of the function.
+	// The result of the function is written back into the same column.
+	Col int
+
+	// Frame is the frame used by aggregation functions. If nil, the default frame is used.
+	Frame *WindowFrame `json:",omitempty"`
+
+	// Offset and Default are only used by LAG and LEAD.
+	Offset  int64           `json:",omitempty"`
+	Default evalengine.Expr `json:",omitempty"`
+
+	Type  evalengine.Type
+	Alias string `json:",omitempty"`
+}
+
+// WindowFrame is the frame of rows a window function is evaluated over.
+// RANGE frames are only supported with unbounded or CURRENT ROW bounds.
+type WindowFrame struct {
+	Unit  sqlparser.FrameUnitType
+	Start WindowFrameBound
+	End   WindowFrameBound
+}
+
+// WindowFrameBound is one end of a WindowFrame. N is only used by
+// the PRECEDING and FOLLOWING bounds with an offset.
+type WindowFrameBound struct {
+	Type sqlparser.FramePointType
+	N    int
+}
+
+// defaultWindowFrame is the frame used when no frame clause is given:
+// all the rows from the start of the partition up to the last peer of the current row.
+// Without any ordering, all rows are peers, so this covers the whole partition.
+var defaultWindowFrame = &WindowFrame{
+	Unit:  sqlparser.FrameRangeType,
+	Start: WindowFrameBound{Type: sqlparser.UnboundedPrecedingType},
+	End:   WindowFrameBound{Type: sqlparser.CurrentRowType},
+}
+
+// String returns a string. Used for plan descriptions
+func (wf *WindowFrame) String() string {
+	unit := "rows"
+	if wf.Unit == sqlparser.FrameRangeType {
+		unit = "range"
+	}
+	return fmt.Sprintf("%s between %s and %s", unit, wf.Start.String(), wf.End.String())
+}
+
+// String returns a string. Used for plan descriptions
+func (b WindowFrameBound) String() string {
+	switch b.Type {
+	case sqlparser.UnboundedPrecedingType:
+		return "unbounded preceding"
+	case sqlparser.UnboundedFollowingType:
+		return "unbounded following"
+	case sqlparser.ExprPrecedingType:
+		return strconv.Itoa(b.N) + " preceding"
+	case sqlparser.ExprFollowingType:
+		return strconv.Itoa(b.N) + " following"
+	default:
+		return "current row"
+	}
+}
+
+// bounds returns the [lo, hi) range of rows in the partition that are part of the frame of row i.
+// peerStart and peerEnd give the [start, end) range of the peers of row i.
+func (wf *WindowFrame) bounds(i, size, peerStart, peerEnd int) (lo, hi int) {
+	switch wf.Start.Type {
+	case sqlparser.UnboundedPrecedingType:
+		lo = 0
+	case sqlparser.UnboundedFollowingType:
+		lo = size
+	case sqlparser.ExprPrecedingType:
+		lo = max(i-wf.Start.N, 0)
+	case sqlparser.ExprFollowingType:
+		lo = min(i+wf.Start.N, size)
+	default:
+		lo = i
+		if wf.Unit == sqlparser.FrameRangeType {
+			lo = peerStart
+		}
+	}
+
+	switch wf.End.Type {
+	case sqlparser.UnboundedPrecedingType:
+		hi = 0
+	case sqlparser.UnboundedFollowingType:
+		hi = size
+	case sqlparser.ExprPrecedingType:
+		hi = max(i-wf.End.N+1, 0)
+	case sqlparser.ExprFollowingType:
+		hi = min(i+wf.End.N+1, size)
+	default:
+		hi = i + 1
+		if wf.Unit == sqlparser.FrameRangeType {
+			hi = peerEnd
+		}
+	}
+
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
+
+func (wp *WindowFuncParams) frame() *WindowFrame {
+	if wp.Frame == nil {
+		return defaultWindowFrame
+	}
+	return wp.Frame
+}
+
+func (wp *WindowFuncParams) String() string {
+	var args []string
+	if wp.Opcode != WindowCountStar && !wp.Opcode.IsRanking() {
+		args = append(args, strconv.Itoa(wp.Col))
+	}
+	if wp.Opcode == WindowLag || wp.Opcode == WindowLead {
+		args = append(args, strconv.FormatInt(wp.Offset, 10))
+		if wp.Default != nil {
+			args = append(args, sqlparser.String(wp.Default))
+		}
+	}
+	out := fmt.Sprintf("%s(%s)", wp.Opcode.String(), strings.Join(args, ", "))
+	if wp.Frame != nil {
+		out += " " + wp.Frame.String()
+	}
+	if wp.Alias != "" {
+		out += " AS " + wp.Alias
+	}
+	return out
+}
+
+// RouteType returns a description of the query routing type used by the primitive
+func (w *Window) RouteType() string {
+	return w.Input.RouteType()
+}
+
+// GetKeyspaceName specifies the Keyspace that this primitive routes to.
+func (w *Window) GetKeyspaceName() string {
+	return w.Input.GetKeyspaceName()
+}
+
+// GetTableName specifies the table that this primitive routes to.
+func (w *Window) GetTableName() string {
+	return w.Input.GetTableName()
+}
+
+// SetTruncateColumnCount sets the truncate column count.
+func (w *Window) SetTruncateColumnCount(count int) {
+	w.TruncateColumnCount = count
+}
+
+// TryExecute is a Primitive function.
+func (w *Window) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
+	result, err := vcursor.ExecutePrimitive(
+		ctx,
+		w.Input,
+		bindVars,
+		true, /*wantFields - we need the input fields types to correctly calculate the output types*/
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := w.newWindowState(ctx, vcursor, bindVars, result.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &sqltypes.Result{
+		Fields: state.fields,
+		Rows:   make([][]sqltypes.Value, 0, len(result.Rows)),
+	}
+	emit := func(rows []sqltypes.Row) error {
+		out.Rows = append(out.Rows, rows...)
+		return nil
+	}
+	for _, row := range result.Rows {
+		if err := state.add(row, emit); err != nil {
+			return nil, err
+		}
+	}
+	if err := state.flush(emit); err != nil {
+		return nil, err
+	}
+	return out.Truncate(w.TruncateColumnCount), nil
+}
+
+// TryStreamExecute is a Primitive function.
+func (w *Window) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool, callback func(*sqltypes.Result) error) error {
+	cb := func(qr *sqltypes.Result) error {
+		return callback(qr.Truncate(w.TruncateColumnCount))
+	}
+	emit := func(rows []sqltypes.Row) error {
+		return cb(&sqltypes.Result{Rows: rows})
+	}
+
+	var state *windowState
+	visitor := func(qr *sqltypes.Result) error {
+		var err error
+		if state == nil && len(qr.Fields) != 0 {
+			state, err = w.newWindowState(ctx, vcursor, bindVars, qr.Fields)
+			if err != nil {
+				return err
+			}
+			if err = cb(&sqltypes.Result{Fields: state.fields}); err != nil {
+				return err
+			}
+		}
+		for _, row := range qr.Rows {
+			if err := state.add(row, emit); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	/* we need the input fields types to correctly calculate the output types */
+	err := vcursor.StreamExecutePrimitive(ctx, w.Input, bindVars, true, visitor)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return nil
+	}
+	return state.flush(emit)
+}
+
+// GetFields is a Primitive function.
+func (w *Window) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	qr, err := w.Input.GetFields(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	qr = &sqltypes.Result{Fields: w.fields(qr.Fields)}
+	return qr.Truncate(w.TruncateColumnCount), nil
+}
+
+// Inputs returns the Primitive input for this window
+func (w *Window) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{w.Input}, nil
+}
+
+// NeedsTransaction implements the Primitive interface
+func (w *Window) NeedsTransaction() bool {
+	return w.Input.NeedsTransaction()
+}
+
+func (w *Window) fields(input []*querypb.Field) []*querypb.Field {
+	fields := slice.Map(input, func(from *querypb.Field) *querypb.Field { return from.CloneVT() })
+	for _, wf := range w.Functions {
+		fields[wf.Col].Type = wf.Opcode.Type(fields[wf.Col].Type)
+		if wf.Alias != "" {
+			fields[wf.Col].Name = wf.Alias
+		}
+	}
+	return fields
+}
+
+func windowFuncParamsToString(in any) string {
+	return in.(*WindowFuncParams).String()
+}
+
+func (w *Window) description() PrimitiveDescription {
+	other := map[string]any{
+		"Functions": GenericJoin(w.Functions, windowFuncParamsToString),
+	}
+	if len(w.PartitionBy) > 0 {
+		other["PartitionBy"] = GenericJoin(w.PartitionBy, groupByParamsToString)
+	}
+	if len(w.OrderBy) > 0 {
+		other["OrderBy"] = GenericJoin(w.OrderBy, orderByParamsToString)
+	}
+	if w.TruncateColumnCount > 0 {
+		other["ResultColumns"] = w.TruncateColumnCount
+	}
+	return PrimitiveDescription{
+		OperatorType: "Window",
+		Other:        other,
+	}
+}
+
+// windowState buffers the rows of the current partition and evaluates the
+// window functions over them once the partition is complete
+type windowState struct {
+	w         *Window
+	input     []*querypb.Field
+	fields    []*querypb.Field
+	defaults  []sqltypes.Value
+	partition []sqltypes.Row
+}
+
+func (w *Window) newWindowState(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, fields []*querypb.Field) (*windowState, error) {
+	state := &windowState{
+		w:        w,
+		input:    fields,
+		fields:   w.fields(fields),
+		defaults: make([]sqltypes.Value, len(w.Functions)),
+	}
+
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	for i, wf := range w.Functions {
+		if wf.Default == nil {
+			state.defaults[i] = sqltypes.NULL
+			continue
+		}
+		res, err := env.Evaluate(wf.Default)
+		if err != nil {
+			return nil, err
+		}
+		state.defaults[i] = res.Value(vcursor.ConnCollation())
+	}
+	return state, nil
+}
+
+// add adds a row to the current partition. If the row starts a new partition,
+// the rows of the previous one are evaluated and handed to emit
+func (ws *windowState) add(row sqltypes.Row, emit func([]sqltypes.Row) error) error {
+	if len(ws.partition) > 0 {
+		newPartition, err := ws.w.nextPartition(ws.partition[0], row)
+		if err != nil {
+			return err
+		}
+		if newPartition {
+			if err := ws.flush(emit); err != nil {
+				return err
+			}
+		}
+	}
+	ws.partition = append(ws.partition, row)
+	return nil
+}
+
+// flush evaluates the window functions over the current partition and emits its rows
+func (ws *windowState) flush(emit func([]sqltypes.Row) error) error {
+	if len(ws.partition) == 0 {
+		return nil
+	}
+	rows := ws.partition
+	ws.partition = nil
+	if err := ws.evaluate(rows); err != nil {
+		return err
+	}
+	return emit(rows)
+}
+
+func (ws *windowState) evaluate(rows []sqltypes.Row) (err error) {
+	defer evalengine.PanicHandler(&err)
+
+	peerStart, peerEnd := ws.w.peers(rows)
+
+	// the results are only written once all functions have been evaluated,
+	// since some functions read the column of other rows than the current one
+	results := make([][]sqltypes.Value, len(ws.w.Functions))
+	for idx, wf := range ws.w.Functions {
+		var res []sqltypes.Value
+		switch {
+		case wf.Opcode.IsRanking():
+			res = evalRanking(wf.Opcode, peerStart)
+		case wf.Opcode == WindowLag || wf.Opcode == WindowLead:
+			res = evalLagLead(wf, rows, ws.defaults[idx])
+		case wf.Opcode.IsAggregation():
+			res, err = ws.evalAggregation(wf, rows, peerStart, peerEnd)
+			if err != nil {
+				return err
+			}
+		default:
+			return vterrors.VT13001(fmt.Sprintf("unexpected window function: %s", wf.Opcode.String()))
+		}
+		results[idx] = res
+	}
+
+	for idx, wf := range ws.w.Functions {
+		for i, row := range rows {
+			row[wf.Col] = results[idx][i]
+		}
+	}
+	return nil
+}
+
+func evalRanking(code WindowOpcode, peerStart []int) []sqltypes.Value {
+	res := make([]sqltypes.Value, len(peerStart))
+	var dense uint64
+	for i := range peerStart {
+		if peerStart[i] == i {
+			dense++
+		}
+		switch code {
+		case WindowRowNumber:
+			res[i] = sqltypes.NewUint64(uint64(i + 1))
+		case WindowRank:
+			res[i] = sqltypes.NewUint64(uint64(peerStart[i] + 1))
+		case WindowDenseRank:
+			res[i] = sqltypes.NewUint64(dense)
+		}
+	}
+	return res
+}
+
+func evalLagLead(wf *WindowFuncParams, rows []sqltypes.Row, dflt sqltypes.Value) []sqltypes.Value {
+	res := make([]sqltypes.Value, len(rows))
+	offset := int(wf.Offset)
+	if wf.Opcode == WindowLag {
+		offset = -offset
+	}
+	for i := range rows {
+		j := i + offset
+		if j < 0 || j >= len(rows) {
+			res[i] = dflt
+			continue
+		}
+		res[i] = rows[j][wf.Col]
+	}
+	return res
+}
+
+func (ws *windowState) evalAggregation(wf *WindowFuncParams, rows []sqltypes.Row, peerStart, peerEnd []int) ([]sqltypes.Value, error) {
+	agg := newWindowAggregator(wf, ws.input[wf.Col].Type)
+	frame := wf.frame()
+	res := make([]sqltypes.Value, len(rows))
+
+	// when the frame starts at the beginning of the partition, the frame only
+	// grows from one row to the next, and we can keep aggregating incrementally
+	incremental := frame.Start.Type == sqlparser.UnboundedPrecedingType
+	added := 0
+	for i := range rows {
+		lo, hi := frame.bounds(i, len(rows), peerStart[i], peerEnd[i])
+		if !incremental {
+			agg.reset()
+			added = lo
+		}
+		for ; added < hi; added++ {
+			if err := agg.add(rows[added]); err != nil {
+				return nil, err
+			}
+		}
+		v, err := agg.result()
+		if err != nil {
+			return nil, err
+		}
+		res[i] = v
+	}
+	return res, nil
+}
+
+// windowAggregator evaluates an aggregation function over the rows of a window frame
+type windowAggregator struct {
+	agg   aggregator
+	count *aggregatorCount
+}
+
+func newWindowAggregator(wf *WindowFuncParams, sourceType querypb.Type) *windowAggregator {
+	noDistinct := aggregatorDistinct{column: -1}
+	wa := &windowAggregator{}
+	switch wf.Opcode {
+	case WindowCountStar:
+		wa.agg = &aggregatorCountStar{}
+	case WindowCount:
+		wa.agg = &aggregatorCount{from: wf.Col, distinct: noDistinct}
+	case WindowSum:
+		wa.agg = &aggregatorSum{from: wf.Col, sum: evalengine.NewAggregationSum(sourceType), distinct: noDistinct}
+	case WindowAvg:
+		// AVG is evaluated as the SUM of the values divided by their COUNT
+		wa.agg = &aggregatorSum{from: wf.Col, sum: evalengine.NewAggregationSum(sourceType), distinct: noDistinct}
+		wa.count = &aggregatorCount{from: wf.Col, distinct: noDistinct}
+	case WindowMin:
+		wa.agg = &aggregatorMin{aggregatorMinMax{from: wf.Col, minmax: evalengine.NewAggregationMinMax(sourceType, wf.Type.Coll)}}
+	case WindowMax:
+		wa.agg = &aggregatorMax{aggregatorMinMax{from: wf.Col, minmax: evalengine.NewAggregationMinMax(sourceType, wf.Type.Coll)}}
+	default:
+		panic("BUG: unexpected window aggregation opcode")
+	}
+	return wa
+}
+
+func (wa *windowAggregator) add(row sqltypes.Row) error {
+	if wa.count != nil {
+		if err := wa.count.add(row); err != nil {
+			return err
+		}
+	}
+	return wa.agg.add(row)
+}
+
+func (wa *windowAggregator) result() (sqltypes.Value, error) {
+	if wa.count == nil {
+		return wa.agg.finish(), nil
+	}
+	if wa.count.n == 0 {
+		return sqltypes.NULL, nil
+	}
+	return evalengine.Divide(wa.agg.finish(), wa.count.finish())
+}
+
+func (wa *windowAggregator) reset() {
+	if wa.count != nil {
+		wa.count.reset()
+	}
+	wa.agg.reset()
+}
+
+// peers returns, for each row of the partition, the [start, end) range of rows
+// that are peers of it according to the window ordering
+func (w *Window) peers(rows []sqltypes.Row) (peerStart, peerEnd []int) {
+	peerStart = make([]int, len(rows))
+	peerEnd = make([]int, len(rows))
+	start := 0
+	for i := 1; i <= len(rows); i++ {
+		if i < len(rows) && w.OrderBy.Compare(rows[start], rows[i]) == 0 {
+			continue
+		}
+		for j := start; j < i; j++ {
+			peerStart[j] = start
+			peerEnd[j] = i
+		}
+		start = i
+	}
+	return
+}
+
+func (w *Window) nextPartition(current, next sqltypes.Row) (bool, error) {
+	for _, gb := range w.PartitionBy {
+		v1 := current[gb.KeyCol]
+		v2 := next[gb.KeyCol]
+		if v1.TinyWeightCmp(v2) != 0 {
+			return true, nil
+		}
+
+		cmp, err := evalengine.NullsafeCompare(v1, v2, gb.Type.Coll)
+		if err != nil {
+			_, isCollationErr := err.(evalengine.UnsupportedCollationError)
+			if !isCollationErr || gb.WeightStringCol == -1 {
+				return false, err
+			}
+			gb.KeyCol = gb.WeightStringCol
+			cmp, err = evalengine.NullsafeCompare(current[gb.WeightStringCol], next[gb.WeightStringCol], gb.Type.Coll)
+			if err != nil {
+				return false, err
+			}
+		}
+		if cmp != 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
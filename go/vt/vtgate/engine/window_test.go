@@ -0,0 +1,218 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/sqlparser"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+func TestWindowRanking(t *testing.T) {
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"col|val|rn|rk|drk",
+				"varbinary|int64|int64|int64|int64",
+			),
+			"a|1|1|1|1",
+			"a|1|1|1|1",
+			"a|2|1|1|1",
+			"b|3|1|1|1",
+			"b|3|1|1|1",
+		)},
+	}
+
+	w := &Window{
+		PartitionBy: []*GroupByParams{{KeyCol: 0, WeightStringCol: -1}},
+		OrderBy:     evalengine.Comparison{{Col: 1, WeightStringCol: -1}},
+		Functions: []*WindowFuncParams{
+			{Opcode: WindowRowNumber, Col: 2},
+			{Opcode: WindowRank, Col: 3},
+			{Opcode: WindowDenseRank, Col: 4},
+		},
+		Input: fp,
+	}
+
+	result, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+
+	wantResult := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"col|val|rn|rk|drk",
+			"varbinary|int64|uint64|uint64|uint64",
+		),
+		"a|1|1|1|1",
+		"a|1|2|1|1",
+		"a|2|3|3|2",
+		"b|3|1|1|1",
+		"b|3|2|1|1",
+	)
+	utils.MustMatch(t, wantResult, result)
+}
+
+func TestWindowLagLead(t *testing.T) {
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"col|val|lag|lead",
+				"varbinary|int64|int64|int64",
+			),
+			"a|1|1|1",
+			"a|2|2|2",
+			"a|3|3|3",
+			"b|4|4|4",
+		)},
+	}
+
+	w := &Window{
+		PartitionBy: []*GroupByParams{{KeyCol: 0, WeightStringCol: -1}},
+		OrderBy:     evalengine.Comparison{{Col: 1, WeightStringCol: -1}},
+		Functions: []*WindowFuncParams{
+			{Opcode: WindowLag, Col: 2, Offset: 1},
+			{Opcode: WindowLead, Col: 3, Offset: 2, Default: evalengine.NewLiteralInt(0)},
+		},
+		Input: fp,
+	}
+
+	result, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+
+	wantResult := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"col|val|lag|lead",
+			"varbinary|int64|int64|int64",
+		),
+		"a|1|null|3",
+		"a|2|1|0",
+		"a|3|2|0",
+		"b|4|null|0",
+	)
+	utils.MustMatch(t, wantResult, result)
+}
+
+func TestWindowAggregations(t *testing.T) {
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"col|val|running|moving|mn|mx|cnt|total",
+				"varbinary|int64|int64|int64|int64|int64|int64|int64",
+			),
+			"a|1|1|1|1|1|1|1",
+			"a|2|2|2|2|2|2|2",
+			"a|2|2|2|2|2|2|2",
+			"a|4|4|4|4|4|4|4",
+			"b|5|5|5|5|5|null|5",
+		)},
+	}
+
+	w := &Window{
+		PartitionBy: []*GroupByParams{{KeyCol: 0, WeightStringCol: -1}},
+		OrderBy:     evalengine.Comparison{{Col: 1, WeightStringCol: -1}},
+		Functions: []*WindowFuncParams{
+			// default frame: from the start of the partition to the last peer of the current row
+			{Opcode: WindowSum, Col: 2},
+			// rows between 1 preceding and current row
+			{Opcode: WindowSum, Col: 3, Frame: &WindowFrame{
+				Unit:  sqlparser.FrameRowsType,
+				Start: WindowFrameBound{Type: sqlparser.ExprPrecedingType, N: 1},
+				End:   WindowFrameBound{Type: sqlparser.CurrentRowType},
+			}},
+			// rows between current row and 1 following
+			{Opcode: WindowMin, Col: 4, Frame: &WindowFrame{
+				Unit:  sqlparser.FrameRowsType,
+				Start: WindowFrameBound{Type: sqlparser.CurrentRowType},
+				End:   WindowFrameBound{Type: sqlparser.ExprFollowingType, N: 1},
+			}},
+			{Opcode: WindowMax, Col: 5},
+			{Opcode: WindowCount, Col: 6, Frame: &WindowFrame{
+				Unit:  sqlparser.FrameRowsType,
+				Start: WindowFrameBound{Type: sqlparser.UnboundedPrecedingType},
+				End:   WindowFrameBound{Type: sqlparser.UnboundedFollowingType},
+			}},
+			{Opcode: WindowAvg, Col: 7, Frame: &WindowFrame{
+				Unit:  sqlparser.FrameRangeType,
+				Start: WindowFrameBound{Type: sqlparser.CurrentRowType},
+				End:   WindowFrameBound{Type: sqlparser.UnboundedFollowingType},
+			}},
+		},
+		Input: fp,
+	}
+
+	result, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+
+	wantResult := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"col|val|running|moving|mn|mx|cnt|total",
+			"varbinary|int64|decimal|decimal|int64|int64|int64|decimal",
+		),
+		"a|1|1|1|1|1|4|2.2500",
+		"a|2|5|3|2|2|4|2.6667",
+		"a|2|5|4|2|2|4|2.6667",
+		"a|4|9|6|4|4|4|4.0000",
+		"b|5|5|5|5|5|0|5.0000",
+	)
+	utils.MustMatch(t, wantResult, result)
+}
+
+func TestWindowStreamExecuteTruncate(t *testing.T) {
+	fp := &fakePrimitive{
+		results: sqltypes.MakeTestStreamingResults(
+			sqltypes.MakeTestFields(
+				"val|rn|col",
+				"int64|int64|varbinary",
+			),
+			"1|1|a",
+			"2|1|a",
+			"---",
+			"3|1|a",
+			"1|1|b",
+		),
+		allResultsInOneCall: true,
+	}
+
+	w := &Window{
+		PartitionBy:         []*GroupByParams{{KeyCol: 2, WeightStringCol: -1}},
+		OrderBy:             evalengine.Comparison{{Col: 0, WeightStringCol: -1}},
+		Functions:           []*WindowFuncParams{{Opcode: WindowRowNumber, Col: 1, Alias: "rn"}},
+		TruncateColumnCount: 2,
+		Input:               fp,
+	}
+
+	result, err := wrapStreamExecute(w, &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+
+	wantResult := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"val|rn",
+			"int64|uint64",
+		),
+		"1|1",
+		"2|2",
+		"3|3",
+		"1|1",
+	)
+	utils.MustMatch(t, wantResult, result)
+}
@@ -58,6 +58,8 @@ import (
 	"vitess.io/vitess/go/vt/vtgate/logstats"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/queryrules"
+	"vitess.io/vitess/go/vt/vtgate/resultcache"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/vtgate/vschemaacl"
 	"vitess.io/vitess/go/vt/vtgate/vtgateservice"
@@ -122,6 +124,13 @@ type Executor struct {
 
 	warmingReadsPercent int
 	warmingReadsChannel chan bool
+
+	// queryRules are the query rules evaluated against the plan of every query.
+	queryRules atomic.Pointer[queryrules.Rules]
+
+	// resultCache caches the results of the read-only queries that opt in. It is nil if disabled.
+	resultCache            *resultcache.Cache
+	resultCacheInvalidator *resultCacheInvalidator
 }
 
 var executorOnce sync.Once
@@ -129,6 +138,7 @@ var executorOnce sync.Once
 const pathQueryPlans = "/debug/query_plans"
 const pathScatterStats = "/debug/scatter_stats"
 const pathVSchema = "/debug/vschema"
+const pathQueryRules = "/debug/query_rules"
 
 type PlanCacheKey = theine.HashKey256
 type PlanCache = theine.Store[PlanCacheKey, *engine.Plan]
@@ -202,6 +212,7 @@ func NewExecutor(
 		servenv.HTTPHandle(pathQueryPlans, e)
 		servenv.HTTPHandle(pathScatterStats, e)
 		servenv.HTTPHandle(pathVSchema, e)
+		servenv.HTTPHandle(pathQueryRules, e)
 	})
 	return e
 }
@@ -404,7 +415,7 @@ func (e *Executor) execute(ctx context.Context, mysqlCtx vtgateservice.MySQLConn
 	var stmtType sqlparser.StatementType
 	err = e.newExecute(ctx, mysqlCtx, safeSession, sql, bindVars, logStats, func(ctx context.Context, plan *engine.Plan, vc *vcursorImpl, bindVars map[string]*querypb.BindVariable, time time.Time) error {
 		stmtType = plan.Type
-		qr, err = e.executePlan(ctx, safeSession, plan, vc, bindVars, logStats, time)
+		qr, err = e.executePlanWithResultCache(ctx, safeSession, plan, vc, bindVars, logStats, time)
 		return err
 	}, func(typ sqlparser.StatementType, result *sqltypes.Result) error {
 		stmtType = typ
@@ -991,6 +1002,9 @@ func (e *Executor) SaveVSchema(vschema *vindexes.VSchema, stats *VSchemaStats) {
 	}
 	e.vschemaStats = stats
 	e.ClearPlans()
+	if e.resultCacheInvalidator != nil && e.vschema != nil {
+		e.resultCacheInvalidator.update(e.vschema)
+	}
 
 	if vschemaCounters != nil {
 		vschemaCounters.Add("Reload", 1)
@@ -1042,6 +1056,12 @@ func (e *Executor) getPlan(
 	vcursor.SetIgnoreMaxMemoryRows(sqlparser.IgnoreMaxMaxMemoryRowsDirective(stmt))
 	vcursor.SetConsolidator(sqlparser.Consolidator(stmt))
 	vcursor.SetWorkloadName(sqlparser.GetWorkloadNameFromStatement(stmt))
+	vcursor.setResultCache(sqlparser.ResultCacheDirective(stmt))
+	vcursor.setResultCacheTTL(sqlparser.ResultCacheTTLDirective(stmt))
+	if e.resultCache != nil {
+		// The statement must be checked before the rewriting replaces functions and variables with bind variables.
+		vcursor.setResultUncacheable(resultUncacheable(stmt))
+	}
 	priority, err := sqlparser.GetPriorityFromStatement(stmt)
 	if err != nil {
 		return nil, err
@@ -1190,6 +1210,8 @@ func (e *Executor) ServeHTTP(response http.ResponseWriter, request *http.Request
 		returnAsJSON(response, e.VSchema())
 	case pathScatterStats:
 		e.WriteScatterStats(response)
+	case pathQueryRules:
+		returnAsJSON(response, e.QueryRules())
 	default:
 		response.WriteHeader(http.StatusNotFound)
 	}
@@ -1538,4 +1560,8 @@ func (e *Executor) Close() {
 	}
 	topo.Close()
 	e.plans.Close()
+	if e.resultCache != nil {
+		e.resultCacheInvalidator.close()
+		e.resultCache.Close()
+	}
 }
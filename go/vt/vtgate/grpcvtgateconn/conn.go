@@ -50,6 +50,7 @@ func init() {
 
 	for _, cmd := range []string{
 		"vtbench",
+		"vtcdc",
 		"vtclient",
 		"vtcombo",
 		"vtctl",
@@ -274,6 +275,17 @@ func (conn *vtgateConn) VStream(ctx context.Context, tabletType topodatapb.Table
 	}, nil
 }
 
+func (conn *vtgateConn) VStreamAck(ctx context.Context, subscription string, vgtid *binlogdatapb.VGtid, timestamp int64) error {
+	request := &vtgatepb.VStreamAckRequest{
+		CallerId:     callerid.EffectiveCallerIDFromContext(ctx),
+		Subscription: subscription,
+		Vgtid:        vgtid,
+		Timestamp:    timestamp,
+	}
+	_, err := conn.c.VStreamAck(ctx, request)
+	return vterrors.FromGRPC(err)
+}
+
 func (conn *vtgateConn) Close() {
 	conn.cc.Close()
 }
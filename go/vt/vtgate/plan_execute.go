@@ -144,6 +144,12 @@ func (e *Executor) newExecute(
 			return err
 		}
 
+		release, err := e.checkQueryRules(ctx, vcursor, plan, query, comments, bindVars)
+		if err != nil {
+			logStats.Error = err
+			return err
+		}
+
 		// 5: Execute the plan and retry if needed
 		if plan.Instructions.NeedsTransaction() {
 			err = e.insideTransaction(ctx, safeSession, logStats,
@@ -153,6 +159,7 @@ func (e *Executor) newExecute(
 		} else {
 			err = execPlan(ctx, plan, vcursor, bindVars, execStart)
 		}
+		release()
 
 		if err == nil || safeSession.InTransaction() {
 			return err
@@ -220,6 +220,9 @@ func createInstructionFor(ctx context.Context, query string, stmt sqlparser.Stat
 		return buildFlushPlan(stmt, vschema)
 	case *sqlparser.CallProc:
 		return buildCallProcPlan(stmt, vschema)
+	case *sqlparser.CreateTrigger, *sqlparser.DropTrigger, *sqlparser.CreateRoutine,
+		*sqlparser.DropRoutine, *sqlparser.CreateEvent, *sqlparser.DropEvent:
+		return nil, vterrors.VT12001("trigger, stored routine and event DDL")
 	case *sqlparser.Stream:
 		return buildStreamPlan(stmt, vschema)
 	case *sqlparser.VStream:
@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+var _ logicalPlan = (*hashJoin)(nil)
+
+// hashJoin is used to build a HashJoin primitive.
+type hashJoin struct {
+	// Left and Right are the nodes for the join.
+	Left, Right logicalPlan
+
+	// These are the columns that will be produced by this plan.
+	// Negative offsets come from the LHS, and positive from the RHS
+	Cols []int
+
+	// LHSKey and RHSKey are the offsets of the join values in the inputs
+	LHSKey, RHSKey int
+
+	// Predicate is the join condition, used for plan descriptions
+	Predicate sqlparser.Expr
+
+	// ComparisonType and Collation are used to hash and compare the join values
+	ComparisonType querypb.Type
+	Collation      collations.ID
+}
+
+// Primitive implements the logicalPlan interface
+func (hj *hashJoin) Primitive() engine.Primitive {
+	return &engine.HashJoin{
+		Opcode:         engine.InnerJoin,
+		Left:           hj.Left.Primitive(),
+		Right:          hj.Right.Primitive(),
+		Cols:           hj.Cols,
+		LHSKey:         hj.LHSKey,
+		RHSKey:         hj.RHSKey,
+		ASTPred:        hj.Predicate,
+		Collation:      hj.Collation,
+		ComparisonType: hj.ComparisonType,
+	}
+}
@@ -42,8 +42,12 @@ func transformToLogicalPlan(ctx *plancontext.PlanningContext, op ops.Operator) (
 		return transformRoutePlan(ctx, op)
 	case *operators.ApplyJoin:
 		return transformApplyJoinPlan(ctx, op)
+	case *operators.HashJoin:
+		return transformHashJoin(ctx, op)
 	case *operators.Union:
 		return transformUnionPlan(ctx, op)
+	case *operators.RecurseCTE:
+		return transformRecurseCTE(ctx, op)
 	case *operators.Vindex:
 		return transformVindexPlan(ctx, op)
 	case *operators.SubQuery:
@@ -60,12 +64,16 @@ func transformToLogicalPlan(ctx *plancontext.PlanningContext, op ops.Operator) (
 		return transformOrdering(ctx, op)
 	case *operators.Aggregator:
 		return transformAggregator(ctx, op)
+	case *operators.Window:
+		return transformWindow(ctx, op)
 	case *operators.Distinct:
 		return transformDistinct(ctx, op)
 	case *operators.FkCascade:
 		return transformFkCascade(ctx, op)
 	case *operators.FkVerify:
 		return transformFkVerify(ctx, op)
+	case *operators.DMLWithInput:
+		return transformDMLWithInput(ctx, op)
 	case *operators.InsertSelection:
 		return transformInsertionSelection(ctx, op)
 	}
@@ -148,6 +156,27 @@ func transformFkCascade(ctx *plancontext.PlanningContext, fkc *operators.FkCasca
 	return newFkCascade(parentLP, selLP, children), nil
 }
 
+// transformDMLWithInput transforms a DMLWithInput operator into a logical plan.
+func transformDMLWithInput(_ *plancontext.PlanningContext, op *operators.DMLWithInput) (logicalPlan, error) {
+	// The input and the DML were planned with their own planning contexts, so we use those here
+	input, err := transformToLogicalPlan(op.SourceCtx, op.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	dml, err := transformToLogicalPlan(op.DMLCtx, op.DML)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dmlWithInput{
+		input:      input,
+		dml:        dml,
+		outputCols: op.Offsets,
+		bvName:     op.BvName,
+	}, nil
+}
+
 func transformSubQuery(ctx *plancontext.PlanningContext, op *operators.SubQuery) (logicalPlan, error) {
 	outer, err := transformToLogicalPlan(ctx, op.Outer)
 	if err != nil {
@@ -172,7 +201,16 @@ func transformSubQuery(ctx *plancontext.PlanningContext, op *operators.SubQuery)
 	if err != nil {
 		return nil, err
 	}
-	return newSemiJoin(outer, inner, op.Vars, lhsCols), nil
+	sj := newSemiJoin(outer, inner, op.Vars, lhsCols)
+	if op.ApplyPredicate != nil || op.ApplyProjection {
+		sj.opcode = op.FilterType
+		sj.subqueryResult = op.SubqueryValueName
+		sj.hasValues = op.HasValuesName
+		sj.predicate = op.ApplyPredicateWithOffsets
+		sj.astPredicate = op.ApplyPredicate
+		sj.projectResult = op.ApplyProjection
+	}
+	return sj, nil
 }
 
 // transformFkVerify transforms a FkVerify operator into a logical plan.
@@ -222,6 +260,11 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 		aggrParam.OrigOpcode = aggr.OriginalOpCode
 		aggrParam.WCol = aggr.WSOffset
 		aggrParam.Type = aggr.GetTypeCollation(ctx)
+		// Only the first distinct expression is part of the ordering of the input,
+		// distinct aggregations on any other expression have to track the values they have seen
+		if aggr.OpCode.IsDistinct() && op.DistinctExpr != nil {
+			aggrParam.UnorderedDistinct = !ctx.SemTable.EqualsExpr(aggr.Func.GetArg(), op.DistinctExpr)
+		}
 		oa.aggregates = append(oa.aggregates, aggrParam)
 	}
 	for _, groupBy := range op.Grouping {
@@ -241,6 +284,58 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 	return oa, nil
 }
 
+func transformWindow(ctx *plancontext.PlanningContext, op *operators.Window) (logicalPlan, error) {
+	plan, err := transformToLogicalPlan(ctx, op.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	primitive := &engine.Window{
+		TruncateColumnCount: op.ResultColumns,
+	}
+	for _, p := range op.PartitionBy {
+		typ, _ := ctx.SemTable.TypeForExpr(p.Expr)
+		primitive.PartitionBy = append(primitive.PartitionBy, &engine.GroupByParams{
+			KeyCol:          p.ColOffset,
+			WeightStringCol: p.WSOffset,
+			Expr:            p.Expr,
+			Type:            typ,
+		})
+	}
+	for idx, order := range op.OrderBy {
+		typ, _ := ctx.SemTable.TypeForExpr(order.SimplifiedExpr)
+		primitive.OrderBy = append(primitive.OrderBy, evalengine.OrderByParams{
+			Col:             op.OrderOffsets[idx],
+			WeightStringCol: op.OrderWOffsets[idx],
+			Desc:            order.Inner.Direction == sqlparser.DescOrder,
+			Type:            typ,
+		})
+	}
+	for _, wf := range op.Functions {
+		typ, _ := ctx.SemTable.TypeForExpr(wf.Func)
+		param := &engine.WindowFuncParams{
+			Opcode: wf.OpCode,
+			Col:    wf.ColOffset,
+			Frame:  wf.Frame,
+			Offset: wf.Offset,
+			Type:   typ,
+			Alias:  wf.Original.ColumnName(),
+		}
+		if wf.Default != nil {
+			param.Default, err = evalengine.Translate(wf.Default, nil)
+			if err != nil {
+				return nil, err
+			}
+		}
+		primitive.Functions = append(primitive.Functions, param)
+	}
+
+	return &window{
+		resultsBuilder: newResultsBuilder(plan, primitive),
+		eWindow:        primitive,
+	}, nil
+}
+
 func transformDistinct(ctx *plancontext.PlanningContext, op *operators.Distinct) (logicalPlan, error) {
 	src, err := transformToLogicalPlan(ctx, op.Source)
 	if err != nil {
@@ -406,6 +501,28 @@ func transformApplyJoinPlan(ctx *plancontext.PlanningContext, n *operators.Apply
 	}, nil
 }
 
+func transformHashJoin(ctx *plancontext.PlanningContext, op *operators.HashJoin) (logicalPlan, error) {
+	lhs, err := transformToLogicalPlan(ctx, op.LHS)
+	if err != nil {
+		return nil, err
+	}
+	rhs, err := transformToLogicalPlan(ctx, op.RHS)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hashJoin{
+		Left:           lhs,
+		Right:          rhs,
+		Cols:           op.ColumnOffsets,
+		LHSKey:         op.LHSOffset,
+		RHSKey:         op.RHSOffset,
+		Predicate:      op.Predicate,
+		ComparisonType: op.ComparisonType,
+		Collation:      op.Collation,
+	}, nil
+}
+
 func routeToEngineRoute(ctx *plancontext.PlanningContext, op *operators.Route, hints *queryHints) (*engine.Route, error) {
 	tableNames, err := getAllTableNames(op)
 	if err != nil {
@@ -763,6 +880,25 @@ func transformUnionPlan(ctx *plancontext.PlanningContext, op *operators.Union) (
 
 }
 
+func transformRecurseCTE(ctx *plancontext.PlanningContext, op *operators.RecurseCTE) (logicalPlan, error) {
+	if op.Distinct {
+		return nil, vterrors.VT12001("UNION DISTINCT in a recursive common table expression that spans multiple shards")
+	}
+	seed, err := transformToLogicalPlan(ctx, op.Seed)
+	if err != nil {
+		return nil, err
+	}
+	term, err := transformToLogicalPlan(ctx, op.Term)
+	if err != nil {
+		return nil, err
+	}
+	return &recurseCTE{
+		seed: seed,
+		term: term,
+		vars: op.Vars,
+	}, nil
+}
+
 func transformLimit(ctx *plancontext.PlanningContext, op *operators.Limit) (logicalPlan, error) {
 	plan, err := transformToLogicalPlan(ctx, op.Source)
 	if err != nil {
@@ -48,10 +48,66 @@ func ToSQL(ctx *plancontext.PlanningContext, op ops.Operator) (_ sqlparser.State
 	buildQuery(op, q)
 	if ctx.SemTable != nil {
 		q.sortTables()
+		HoistRecursiveCTEs(ctx.SemTable, q.stmt)
 	}
 	return q.stmt, q.dmlOperator, nil
 }
 
+// HoistRecursiveCTEs turns the recursive CTEs that were inlined as derived tables back into a WITH RECURSIVE clause.
+// MySQL only allows a CTE to reference itself when it has been declared in a WITH clause.
+func HoistRecursiveCTEs(semTable *semantics.SemTable, stmt sqlparser.Statement) {
+	sel, ok := stmt.(sqlparser.SelectStatement)
+	if !ok || len(semTable.RecursiveCTEs) == 0 {
+		return
+	}
+
+	var ctes []*sqlparser.CommonTableExpr
+	declared := map[string]any{}
+	var hoist func(node sqlparser.SQLNode)
+	hoist = func(node sqlparser.SQLNode) {
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			tbl, ok := node.(*sqlparser.AliasedTableExpr)
+			if !ok {
+				return true, nil
+			}
+			dt, ok := tbl.Expr.(*sqlparser.DerivedTable)
+			if !ok {
+				return true, nil
+			}
+			union, ok := dt.Select.(*sqlparser.Union)
+			if !ok {
+				return true, nil
+			}
+			def, isRecursive := semTable.RecursiveCTEs[union]
+			if !isRecursive {
+				return true, nil
+			}
+
+			// the body of the CTE can contain other recursive CTEs
+			hoist(union)
+			if _, found := declared[def.Name]; !found {
+				declared[def.Name] = nil
+				ctes = append(ctes, &sqlparser.CommonTableExpr{
+					ID:       sqlparser.NewIdentifierCS(def.Name),
+					Columns:  tbl.Columns,
+					Subquery: &sqlparser.Subquery{Select: union},
+				})
+			}
+			tbl.Expr = sqlparser.NewTableName(def.Name)
+			tbl.Columns = nil
+			if tbl.As.String() == def.Name {
+				tbl.As = sqlparser.NewIdentifierCS("")
+			}
+			return false, nil
+		}, node)
+	}
+	hoist(sel)
+
+	if len(ctes) > 0 {
+		sel.SetWith(&sqlparser.With{Recursive: true, CTEs: ctes})
+	}
+}
+
 func (qb *queryBuilder) addTable(db, tableName, alias string, tableID semantics.TableSet, hints sqlparser.IndexHints) {
 	tableExpr := sqlparser.TableName{
 		Name:      sqlparser.NewIdentifierCS(tableName),
@@ -371,6 +427,8 @@ func buildQuery(op ops.Operator, qb *queryBuilder) {
 		buildAggregation(op, qb)
 	case *Union:
 		buildUnion(op, qb)
+	case *RecurseCTE:
+		buildRecurseCTE(op, qb)
 	case *Distinct:
 		buildQuery(op.Source, qb)
 		qb.asSelectStatement().MakeDistinct()
@@ -535,6 +593,25 @@ func buildUnion(op *Union, qb *queryBuilder) {
 	}
 }
 
+func buildRecurseCTE(op *RecurseCTE, qb *queryBuilder) {
+	buildQuery(op.Seed, qb)
+
+	qbTerm := &queryBuilder{ctx: qb.ctx}
+	buildQuery(op.Term, qbTerm)
+	term, ok := qbTerm.stmt.(*sqlparser.Select)
+	if !ok {
+		panic(vterrors.VT13001(fmt.Sprintf("expected the recursive part of the CTE to be a SELECT, got %T", qbTerm.stmt)))
+	}
+
+	union := &sqlparser.Union{
+		Left:     qb.asSelectStatement(),
+		Right:    op.restoreTerm(term),
+		Distinct: op.Distinct,
+	}
+	qb.ctx.SemTable.RecursiveCTEs[union] = op.Def
+	qb.stmt = union
+}
+
 func buildFilter(op *Filter, qb *queryBuilder) {
 	buildQuery(op.Source, qb)
 
@@ -169,12 +169,11 @@ func pushAggregationThroughRoute(
 
 // pushAggregations splits aggregations between the original aggregator and the one we are pushing down
 func pushAggregations(ctx *plancontext.PlanningContext, aggregator *Aggregator, aggrBelowRoute *Aggregator) error {
-	canPushDistinctAggr, distinctExpr, err := checkIfWeCanPush(ctx, aggregator)
-	if err != nil {
-		return err
-	}
+	canPushDistinctAggr, distinctExpr := checkIfWeCanPush(ctx, aggregator)
 
-	distinctAggrGroupByAdded := false
+	// We keep track of the distinct expressions that have been added to the group by,
+	// so that we only add each expression once, even when it is used by multiple distinct aggregations.
+	var distinctAggrGroupByAdded []sqlparser.Expr
 
 	for i, aggr := range aggregator.Aggregations {
 		if !aggr.Distinct || canPushDistinctAggr {
@@ -185,18 +184,19 @@ func pushAggregations(ctx *plancontext.PlanningContext, aggregator *Aggregator,
 
 		// We handle a distinct aggregation by turning it into a group by and
 		// doing the aggregating on the vtgate level instead
-		aeDistinctExpr := aeWrap(distinctExpr)
+		innerExpr := aggr.Func.GetArg()
+		aeDistinctExpr := aeWrap(innerExpr)
 		aggrBelowRoute.Columns[aggr.ColOffset] = aeDistinctExpr
 
-		// We handle a distinct aggregation by turning it into a group by and
-		// doing the aggregating on the vtgate level instead
-		// Adding to group by can be done only once even though there are multiple distinct aggregation with same expression.
-		if !distinctAggrGroupByAdded {
-			groupBy := NewGroupBy(distinctExpr, distinctExpr, aeDistinctExpr)
-			groupBy.ColOffset = aggr.ColOffset
-			aggrBelowRoute.Grouping = append(aggrBelowRoute.Grouping, groupBy)
-			distinctAggrGroupByAdded = true
+		if slices.ContainsFunc(distinctAggrGroupByAdded, func(e sqlparser.Expr) bool {
+			return ctx.SemTable.EqualsExpr(e, innerExpr)
+		}) {
+			continue
 		}
+		groupBy := NewGroupBy(innerExpr, innerExpr, aeDistinctExpr)
+		groupBy.ColOffset = aggr.ColOffset
+		aggrBelowRoute.Grouping = append(aggrBelowRoute.Grouping, groupBy)
+		distinctAggrGroupByAdded = append(distinctAggrGroupByAdded, innerExpr)
 	}
 
 	if !canPushDistinctAggr {
@@ -206,10 +206,12 @@ func pushAggregations(ctx *plancontext.PlanningContext, aggregator *Aggregator,
 	return nil
 }
 
-func checkIfWeCanPush(ctx *plancontext.PlanningContext, aggregator *Aggregator) (bool, sqlparser.Expr, error) {
+// checkIfWeCanPush returns true if all the distinct aggregations can be pushed down, which is the case
+// when the distinct expressions have unique vindexes. It also returns the first distinct expression,
+// which is the one the input will be ordered by when the distinct aggregations are evaluated at the vtgate level.
+func checkIfWeCanPush(ctx *plancontext.PlanningContext, aggregator *Aggregator) (bool, sqlparser.Expr) {
 	canPush := true
 	var distinctExpr sqlparser.Expr
-	var differentExpr *sqlparser.AliasedExpr
 
 	for _, aggr := range aggregator.Aggregations {
 		if !aggr.Distinct {
@@ -223,16 +225,9 @@ func checkIfWeCanPush(ctx *plancontext.PlanningContext, aggregator *Aggregator)
 		if distinctExpr == nil {
 			distinctExpr = innerExpr
 		}
-		if !ctx.SemTable.EqualsExpr(distinctExpr, innerExpr) {
-			differentExpr = aggr.Original
-		}
 	}
 
-	if !canPush && differentExpr != nil {
-		return false, nil, vterrors.VT12001(fmt.Sprintf("only one DISTINCT aggregation is allowed in a SELECT: %s", sqlparser.String(differentExpr)))
-	}
-
-	return canPush, distinctExpr, nil
+	return canPush, distinctExpr
 }
 
 func pushAggregationThroughFilter(
@@ -507,10 +502,7 @@ func splitAggrColumnsToLeftAndRight(
 		outerJoin: join.LeftJoin,
 	}
 
-	canPushDistinctAggr, distinctExpr, err := checkIfWeCanPush(ctx, aggregator)
-	if err != nil {
-		return nil, nil, err
-	}
+	canPushDistinctAggr, distinctExpr := checkIfWeCanPush(ctx, aggregator)
 
 	// Distinct aggregation cannot be pushed down in the join.
 	// We keep node of the distinct aggregation expression to be used later for ordering.
@@ -833,13 +825,9 @@ func splitAvgAggregations(ctx *plancontext.PlanningContext, aggr *Aggregator) (o
 			continue
 		}
 
-		if avg.Distinct {
-			panic(vterrors.VT12001("AVG(distinct <>)"))
-		}
-
-		// We have an AVG that we need to split
-		sumExpr := &sqlparser.Sum{Arg: avg.Arg}
-		countExpr := &sqlparser.Count{Args: []sqlparser.Expr{avg.Arg}}
+		// We have an AVG that we need to split. AVG(DISTINCT x) is split into SUM(DISTINCT x)/COUNT(DISTINCT x)
+		sumExpr := &sqlparser.Sum{Arg: avg.Arg, Distinct: avg.Distinct}
+		countExpr := &sqlparser.Count{Args: []sqlparser.Expr{avg.Arg}, Distinct: avg.Distinct}
 		calcExpr := &sqlparser.BinaryExpr{
 			Operator: sqlparser.DivOp,
 			Left:     sumExpr,
@@ -857,10 +845,15 @@ func splitAvgAggregations(ctx *plancontext.PlanningContext, aggr *Aggregator) (o
 		for aggrOffset, aggregation := range aggr.Aggregations {
 			if offset == aggregation.ColOffset {
 				// We have found the AVG column. We'll change it to SUM, and then we add a COUNT as well
-				aggr.Aggregations[aggrOffset].OpCode = opcode.AggregateSum
+				sumCode, countCode := opcode.AggregateSum, opcode.AggregateCount
+				if avg.Distinct {
+					sumCode, countCode = opcode.AggregateSumDistinct, opcode.AggregateCountDistinct
+				}
+				aggr.Aggregations[aggrOffset].OpCode = sumCode
 
 				countExprAlias := aeWrap(countExpr)
-				countAggr := NewAggr(opcode.AggregateCount, countExpr, countExprAlias, sqlparser.String(countExpr))
+				countAggr := NewAggr(countCode, countExpr, countExprAlias, sqlparser.String(countExpr))
+				countAggr.Distinct = avg.Distinct
 				countAggr.ColOffset = len(aggr.Columns) + len(columns)
 				aggregations = append(aggregations, countAggr)
 				columns = append(columns, countExprAlias)
@@ -41,9 +41,11 @@ type (
 		Grouping     []GroupBy
 		Aggregations []Aggr
 
-		// We support a single distinct aggregation per aggregator. It is stored here.
+		// DistinctExpr is the expression of the first distinct aggregation that has to be evaluated at the vtgate level.
 		// When planning the ordering that the OrderedAggregate will require,
-		// this needs to be the last ORDER BY expression
+		// this needs to be the last ORDER BY expression.
+		// Distinct aggregations on other expressions are not helped by this ordering,
+		// so they track the values they have seen in memory instead.
 		DistinctExpr sqlparser.Expr
 
 		// Pushed will be set to true once this aggregation has been pushed deeper in the tree
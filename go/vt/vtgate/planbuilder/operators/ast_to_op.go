@@ -27,7 +27,10 @@ import (
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
 
-const foreignKeyConstraintValues = "fkc_vals"
+const (
+	foreignKeyConstraintValues = "fkc_vals"
+	dmlVals                    = "dml_vals"
+)
 
 // translateQueryToOp creates an operator tree that represents the input SELECT or UNION query
 func translateQueryToOp(ctx *plancontext.PlanningContext, selStmt sqlparser.Statement) (op ops.Operator, err error) {
@@ -176,6 +179,10 @@ func (jpc *joinPredicateCollector) inspectPredicate(
 }
 
 func createOperatorFromUnion(ctx *plancontext.PlanningContext, node *sqlparser.Union) (ops.Operator, error) {
+	if def, isRecursive := ctx.SemTable.RecursiveCTEs[node]; isRecursive {
+		return createRecurseCTE(ctx, node, def)
+	}
+
 	opLHS, err := translateQueryToOp(ctx, node.Left)
 	if err != nil {
 		return nil, err
@@ -203,10 +210,17 @@ func createOperatorFromUnion(ctx *plancontext.PlanningContext, node *sqlparser.U
 //  2. fkToIgnore: The foreign key constraint to specifically ignore while planning the statement. This field is used in UPDATE CASCADE planning, wherein while planning the child update
 //     query, we need to ignore the parent foreign key constraint that caused the cascade in question.
 func createOpFromStmt(ctx *plancontext.PlanningContext, stmt sqlparser.Statement, verifyAllFKs bool, fkToIgnore string) (ops.Operator, error) {
+	op, _, err := createOpAndContextFromStmt(ctx, stmt, verifyAllFKs, fkToIgnore)
+	return op, err
+}
+
+// createOpAndContextFromStmt works like createOpFromStmt, but also returns the planning context
+// that was created for the statement, for when it is needed later when building the primitives.
+func createOpAndContextFromStmt(ctx *plancontext.PlanningContext, stmt sqlparser.Statement, verifyAllFKs bool, fkToIgnore string) (ops.Operator, *plancontext.PlanningContext, error) {
 	var err error
 	ctx, err = plancontext.CreatePlanningContext(stmt, ctx.ReservedVars, ctx.VSchema, ctx.PlannerVersion)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// TODO (@GuptaManan100, @harshit-gangal): When we add cross-shard foreign keys support,
@@ -218,7 +232,7 @@ func createOpFromStmt(ctx *plancontext.PlanningContext, stmt sqlparser.Statement
 	// From all the parent foreign keys involved, we should remove the one that we need to ignore.
 	err = ctx.SemTable.RemoveParentForeignKey(fkToIgnore)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Now, we can filter the foreign keys further based on the planning context, specifically whether we are running
@@ -232,10 +246,11 @@ func createOpFromStmt(ctx *plancontext.PlanningContext, stmt sqlparser.Statement
 		err = ctx.SemTable.RemoveNonRequiredForeignKeys(ctx.VerifyAllFKs, vindexes.DeleteAction)
 	}
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return PlanQuery(ctx, stmt)
+	op, err := PlanQuery(ctx, stmt)
+	return op, ctx, err
 }
 
 func getOperatorFromTableExpr(ctx *plancontext.PlanningContext, tableExpr sqlparser.TableExpr, onlyTable bool) (ops.Operator, error) {
@@ -21,7 +21,6 @@ import (
 
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
-	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/operators/ops"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 	"vitess.io/vitess/go/vt/vtgate/semantics"
@@ -91,7 +90,8 @@ func createOperatorFromDelete(ctx *plancontext.PlanningContext, deleteStmt *sqlp
 		return nil, err
 	}
 
-	if deleteStmt.Comments != nil {
+	// A DMLWithInput operator already has the comments on the DML it sends to the tablets
+	if _, isDMLWithInput := delOp.(*DMLWithInput); deleteStmt.Comments != nil && !isDMLWithInput {
 		delOp = &LockAndComment{
 			Source:   delOp,
 			Comments: deleteStmt.Comments,
@@ -162,14 +162,27 @@ func createDeleteOperator(
 		}
 	}
 
-	if routing.OpCode() == engine.Scatter && deleteStmt.Limit != nil {
-		// TODO systay: we should probably check for other op code types - IN could also hit multiple shards (2022-04-07)
-		return nil, vterrors.VT12001("multi shard DELETE with LIMIT")
+	if needsDMLWithInput(routing, deleteStmt.Limit) {
+		return createDeleteWithInput(ctx, deleteStmt, vindexTable)
 	}
 
 	return sqc.getRootOperator(route, nil), nil
 }
 
+// createDeleteWithInput plans a multi-shard DELETE with LIMIT by first selecting
+// the primary keys of the rows to delete, and then deleting exactly those rows.
+func createDeleteWithInput(ctx *plancontext.PlanningContext, deleteStmt *sqlparser.Delete, vindexTable *vindexes.Table) (ops.Operator, error) {
+	return createDMLWithInput(ctx, vindexTable, deleteStmt.TableExprs, deleteStmt.Where, deleteStmt.OrderBy, deleteStmt.Limit,
+		func(where *sqlparser.Where) sqlparser.Statement {
+			return &sqlparser.Delete{
+				Comments:   deleteStmt.Comments,
+				Ignore:     deleteStmt.Ignore,
+				TableExprs: sqlparser.CloneTableExprs(deleteStmt.TableExprs),
+				Where:      where,
+			}
+		})
+}
+
 func createFkCascadeOpForDelete(ctx *plancontext.PlanningContext, parentOp ops.Operator, delStmt *sqlparser.Delete, childFks []vindexes.ChildFKInfo) (ops.Operator, error) {
 	var fkChildren []*FkChild
 	var selectExprs []sqlparser.SelectExpr
@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"slices"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/operators/ops"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// DMLWithInput is used to represent a DML operator that gets the rows it has to modify
+// from a Source operator. This is used for multi-shard UPDATE and DELETE with ORDER BY and LIMIT,
+// where the Source finds the primary keys of the rows across all the shards,
+// and the DML then modifies exactly those rows.
+type DMLWithInput struct {
+	Source ops.Operator
+	DML    ops.Operator
+
+	Offsets []int
+	BvName  string
+
+	// The Source and the DML are planned separately from the original statement,
+	// so we keep their planning contexts around for when they are turned into primitives.
+	SourceCtx, DMLCtx *plancontext.PlanningContext
+
+	noColumns
+	noPredicates
+}
+
+var _ ops.Operator = (*DMLWithInput)(nil)
+
+// Inputs implements the Operator interface
+func (d *DMLWithInput) Inputs() []ops.Operator {
+	return []ops.Operator{d.Source, d.DML}
+}
+
+// SetInputs implements the Operator interface
+func (d *DMLWithInput) SetInputs(inputs []ops.Operator) {
+	if len(inputs) != 2 {
+		panic("unexpected number of inputs for DMLWithInput operator")
+	}
+	d.Source = inputs[0]
+	d.DML = inputs[1]
+}
+
+// Clone implements the Operator interface
+func (d *DMLWithInput) Clone(inputs []ops.Operator) ops.Operator {
+	return &DMLWithInput{
+		Source:    inputs[0],
+		DML:       inputs[1],
+		Offsets:   slices.Clone(d.Offsets),
+		BvName:    d.BvName,
+		SourceCtx: d.SourceCtx,
+		DMLCtx:    d.DMLCtx,
+	}
+}
+
+// GetOrdering implements the Operator interface
+func (d *DMLWithInput) GetOrdering(*plancontext.PlanningContext) []ops.OrderBy {
+	return nil
+}
+
+// ShortDescription implements the Operator interface
+func (d *DMLWithInput) ShortDescription() string {
+	return d.BvName
+}
+
+// needsDMLWithInput returns true if the DML has a LIMIT and can be sent to more than one shard.
+// MySQL would apply the ordering and limit on every shard separately, so we have to do it at the vtgate level.
+func needsDMLWithInput(routing Routing, limit *sqlparser.Limit) bool {
+	if limit == nil {
+		return false
+	}
+	switch routing.OpCode() {
+	case engine.Scatter, engine.Equal, engine.IN, engine.MultiEqual:
+		return true
+	}
+	return false
+}
+
+// createDMLWithInput plans a multi-shard UPDATE or DELETE with LIMIT as two steps.
+// First, the primary keys of the rows to modify are selected across the shards, using the
+// WHERE, ORDER BY and LIMIT of the original statement. Then the DML is sent for exactly those rows.
+func createDMLWithInput(
+	ctx *plancontext.PlanningContext,
+	vTbl *vindexes.Table,
+	tableExprs sqlparser.TableExprs,
+	where *sqlparser.Where,
+	orderBy sqlparser.OrderBy,
+	limit *sqlparser.Limit,
+	dmlFor func(where *sqlparser.Where) sqlparser.Statement,
+) (ops.Operator, error) {
+	if len(vTbl.PrimaryKey) == 0 {
+		return nil, vterrors.VT09015()
+	}
+
+	var selectExprs sqlparser.SelectExprs
+	var offsets []int
+	var pkTuple sqlparser.ValTuple
+	for idx, col := range vTbl.PrimaryKey {
+		selectExprs = append(selectExprs, aeWrap(sqlparser.NewColName(col.String())))
+		offsets = append(offsets, idx)
+		pkTuple = append(pkTuple, sqlparser.NewColName(col.String()))
+	}
+
+	selectionStmt := &sqlparser.Select{
+		SelectExprs: selectExprs,
+		From:        sqlparser.CloneTableExprs(tableExprs),
+		Where:       sqlparser.CloneRefOfWhere(where),
+		OrderBy:     sqlparser.CloneOrderBy(orderBy),
+		Limit:       sqlparser.CloneRefOfLimit(limit),
+		Lock:        sqlparser.ForUpdateLock,
+	}
+	source, sourceCtx, err := createOpAndContextFromStmt(ctx, selectionStmt, false /* verifyAllFKs */, "" /* fkToIgnore */)
+	if err != nil {
+		return nil, err
+	}
+
+	bvName := ctx.ReservedVars.ReserveVariable(dmlVals)
+	var lhs sqlparser.Expr = pkTuple
+	if len(pkTuple) == 1 {
+		lhs = pkTuple[0]
+	}
+	dmlWhere := &sqlparser.Where{
+		Type: sqlparser.WhereClause,
+		Expr: sqlparser.NewComparisonExpr(sqlparser.InOp, lhs, sqlparser.NewListArg(bvName), nil),
+	}
+	dml, dmlCtx, err := createOpAndContextFromStmt(ctx, dmlFor(dmlWhere), false /* verifyAllFKs */, "" /* fkToIgnore */)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DMLWithInput{
+		Source:    source,
+		DML:       dml,
+		Offsets:   offsets,
+		BvName:    bvName,
+		SourceCtx: sourceCtx,
+		DMLCtx:    dmlCtx,
+	}, nil
+}
@@ -0,0 +1,441 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/operators/ops"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
+)
+
+type (
+	// HashJoin is an inner join that fetches the rows of both inputs once, builds a hash table
+	// with the rows of the LHS and probes it with the rows of the RHS.
+	// Unlike ApplyJoin, no values are sent from the LHS to the RHS, so the RHS is only executed once.
+	HashJoin struct {
+		LHS, RHS ops.Operator
+
+		// Predicate is the join predicate, comparing LHSKey and RHSKey
+		Predicate      sqlparser.Expr
+		LHSKey, RHSKey sqlparser.Expr
+
+		// ComparisonType and Collation are used to hash and compare the join values
+		ComparisonType sqltypes.Type
+		Collation      collations.ID
+
+		// Before offset planning
+
+		// columns are the expressions produced by this operator.
+		// The engine primitive can only pass through columns from its inputs, so the
+		// expressions using columns from both sides are evaluated in a Projection on top
+		columns []hashJoinColumn
+
+		// After offset planning
+
+		// ColumnOffsets stores the column indexes of the columns coming from the left and right side
+		// negative value comes from LHS and positive from RHS
+		ColumnOffsets []int
+
+		// LHSOffset and RHSOffset are the offsets of the join values in the inputs
+		LHSOffset, RHSOffset int
+
+		offsetsPlanned bool
+	}
+
+	hashJoinColumn struct {
+		expr    *sqlparser.AliasedExpr
+		groupBy bool
+	}
+)
+
+var _ ops.Operator = (*HashJoin)(nil)
+
+func newHashJoin(lhs, rhs ops.Operator, predicate, lhsKey, rhsKey sqlparser.Expr, typ evalengine.Type) *HashJoin {
+	return &HashJoin{
+		LHS:            lhs,
+		RHS:            rhs,
+		Predicate:      predicate,
+		LHSKey:         lhsKey,
+		RHSKey:         rhsKey,
+		ComparisonType: typ.Type,
+		Collation:      typ.Coll,
+	}
+}
+
+// Clone implements the Operator interface
+func (hj *HashJoin) Clone(inputs []ops.Operator) ops.Operator {
+	kopy := *hj
+	kopy.LHS = inputs[0]
+	kopy.RHS = inputs[1]
+	kopy.columns = slices.Clone(hj.columns)
+	kopy.ColumnOffsets = slices.Clone(hj.ColumnOffsets)
+	return &kopy
+}
+
+// Inputs implements the Operator interface
+func (hj *HashJoin) Inputs() []ops.Operator {
+	return []ops.Operator{hj.LHS, hj.RHS}
+}
+
+// SetInputs implements the Operator interface
+func (hj *HashJoin) SetInputs(inputs []ops.Operator) {
+	hj.LHS, hj.RHS = inputs[0], inputs[1]
+}
+
+// AddPredicate implements the Operator interface.
+// Predicates using only one side are pushed to that side, and the others are evaluated after the join.
+func (hj *HashJoin) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparser.Expr) ops.Operator {
+	deps := ctx.SemTable.RecursiveDeps(expr)
+	switch {
+	case deps.IsSolvedBy(TableID(hj.LHS)):
+		hj.LHS = hj.LHS.AddPredicate(ctx, expr)
+		return hj
+	case deps.IsSolvedBy(TableID(hj.RHS)):
+		hj.RHS = hj.RHS.AddPredicate(ctx, expr)
+		return hj
+	}
+	return newFilter(hj, expr)
+}
+
+// AddColumn implements the Operator interface
+func (hj *HashJoin) AddColumn(ctx *plancontext.PlanningContext, reuse bool, groupBy bool, expr *sqlparser.AliasedExpr) int {
+	if reuse {
+		offset := hj.FindCol(ctx, expr.Expr, false)
+		if offset != -1 {
+			return offset
+		}
+	}
+	hj.columns = append(hj.columns, hashJoinColumn{expr: expr, groupBy: groupBy})
+	return len(hj.columns) - 1
+}
+
+// FindCol implements the Operator interface
+func (hj *HashJoin) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, _ bool) int {
+	offset, found := canReuseColumn(ctx, hj.columns, expr, func(col hashJoinColumn) sqlparser.Expr {
+		return col.expr.Expr
+	})
+	if !found {
+		return -1
+	}
+	return offset
+}
+
+// GetColumns implements the Operator interface
+func (hj *HashJoin) GetColumns(*plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	return slice.Map(hj.columns, func(col hashJoinColumn) *sqlparser.AliasedExpr {
+		return col.expr
+	})
+}
+
+// GetSelectExprs implements the Operator interface
+func (hj *HashJoin) GetSelectExprs(ctx *plancontext.PlanningContext) sqlparser.SelectExprs {
+	return transformColumnsToSelectExprs(ctx, hj)
+}
+
+// GetOrdering implements the Operator interface.
+// The rows are produced in the order of the RHS, unless the join spills to disk.
+func (hj *HashJoin) GetOrdering(*plancontext.PlanningContext) []ops.OrderBy {
+	return nil
+}
+
+// ShortDescription implements the Operator interface
+func (hj *HashJoin) ShortDescription() string {
+	columns := slice.Map(hj.columns, func(col hashJoinColumn) string {
+		return sqlparser.String(col.expr)
+	})
+	return fmt.Sprintf("on %s columns: %s", sqlparser.String(hj.Predicate), strings.Join(columns, ", "))
+}
+
+// planOffsets fetches the join values and the columns from the inputs. The columns that
+// need values from both sides are evaluated by a Projection, which is returned to replace
+// the join in the operator tree.
+func (hj *HashJoin) planOffsets(ctx *plancontext.PlanningContext) (ops.Operator, error) {
+	if hj.offsetsPlanned {
+		return hj, nil
+	}
+	hj.offsetsPlanned = true
+
+	hj.LHSOffset = hj.LHS.AddColumn(ctx, true, false, aeWrap(hj.LHSKey))
+	hj.RHSOffset = hj.RHS.AddColumn(ctx, true, false, aeWrap(hj.RHSKey))
+
+	proj := newAliasedProjection(hj)
+	for _, col := range hj.columns {
+		pe, err := hj.projectColumn(ctx, col)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := proj.addProjExpr(pe); err != nil {
+			return nil, err
+		}
+	}
+	return proj, nil
+}
+
+// projectColumn returns the projection of the column. Expressions using a single side are
+// fetched from that side, and the others are evaluated using the values fetched from both sides.
+func (hj *HashJoin) projectColumn(ctx *plancontext.PlanningContext, col hashJoinColumn) (*ProjExpr, error) {
+	if offset, ok := hj.fetchFromInput(ctx, col.expr.Expr, col.groupBy); ok {
+		return &ProjExpr{
+			Original: col.expr,
+			EvalExpr: col.expr.Expr,
+			ColExpr:  col.expr.Expr,
+			Info:     Offset(offset),
+		}, nil
+	}
+
+	var offsetExpr *sqlparser.Offset
+	pre := func(node, _ sqlparser.SQLNode) bool {
+		e, ok := node.(sqlparser.Expr)
+		if !ok || ctx.SemTable.RecursiveDeps(e).IsEmpty() {
+			return true
+		}
+		offset, ok := hj.fetchFromInput(ctx, e, col.groupBy)
+		if !ok {
+			return true
+		}
+		offsetExpr = sqlparser.NewOffset(offset, e)
+		return false
+	}
+	// the cursor can't replace nodes while walking down, so the replacement happens on the way up
+	post := func(cursor *sqlparser.CopyOnWriteCursor) {
+		if offsetExpr != nil {
+			cursor.Replace(offsetExpr)
+			offsetExpr = nil
+		}
+	}
+	rewritten := sqlparser.CopyOnRewrite(col.expr.Expr, pre, post, ctx.SemTable.CopySemanticInfo).(sqlparser.Expr)
+
+	eexpr, err := evalengine.Translate(rewritten, &evalengine.Config{
+		ResolveType: ctx.SemTable.TypeForExpr,
+		Collation:   ctx.SemTable.Collation,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ProjExpr{
+		Original: col.expr,
+		EvalExpr: rewritten,
+		ColExpr:  col.expr.Expr,
+		Info:     &EvalEngine{EExpr: eexpr},
+	}, nil
+}
+
+// fetchFromInput adds the expression to the input that can produce it, if any,
+// and returns its offset in the output of the join.
+func (hj *HashJoin) fetchFromInput(ctx *plancontext.PlanningContext, expr sqlparser.Expr, groupBy bool) (int, bool) {
+	deps := ctx.SemTable.RecursiveDeps(expr)
+	var offset int
+	switch {
+	case deps.IsSolvedBy(TableID(hj.LHS)):
+		offset = -hj.LHS.AddColumn(ctx, true, groupBy, aeWrap(expr)) - 1
+	case deps.IsSolvedBy(TableID(hj.RHS)):
+		offset = hj.RHS.AddColumn(ctx, true, groupBy, aeWrap(expr)) + 1
+	default:
+		return 0, false
+	}
+
+	if idx := slices.Index(hj.ColumnOffsets, offset); idx != -1 {
+		return idx, true
+	}
+	hj.ColumnOffsets = append(hj.ColumnOffsets, offset)
+	return len(hj.ColumnOffsets) - 1, true
+}
+
+// hashJoinKeys returns the expressions compared by the predicate, if it is an equality between
+// an expression of the LHS and one of the RHS, and the type used to compare them.
+func hashJoinKeys(ctx *plancontext.PlanningContext, predicate sqlparser.Expr, lhs, rhs semantics.TableSet) (lhsKey, rhsKey sqlparser.Expr, typ evalengine.Type, ok bool) {
+	cmp, isCmp := predicate.(*sqlparser.ComparisonExpr)
+	if !isCmp || cmp.Operator != sqlparser.EqualOp {
+		return nil, nil, typ, false
+	}
+	left, right := cmp.Left, cmp.Right
+	switch {
+	case ctx.SemTable.RecursiveDeps(left).IsSolvedBy(lhs) && ctx.SemTable.RecursiveDeps(right).IsSolvedBy(rhs):
+	case ctx.SemTable.RecursiveDeps(right).IsSolvedBy(lhs) && ctx.SemTable.RecursiveDeps(left).IsSolvedBy(rhs):
+		left, right = right, left
+	default:
+		return nil, nil, typ, false
+	}
+	if ctx.SemTable.RecursiveDeps(left).IsEmpty() || ctx.SemTable.RecursiveDeps(right).IsEmpty() {
+		return nil, nil, typ, false
+	}
+
+	ltyp, lfound := ctx.SemTable.TypeForExpr(left)
+	rtyp, rfound := ctx.SemTable.TypeForExpr(right)
+	if !lfound || !rfound {
+		return nil, nil, typ, false
+	}
+	typ, ok = hashJoinComparisonType(ltyp, rtyp)
+	return left, right, typ, ok
+}
+
+// hashJoinComparisonType returns the type to hash the values of both types with, so that
+// equal values have the same hash code. Only types we are sure to compare like MySQL are supported.
+func hashJoinComparisonType(ltyp, rtyp evalengine.Type) (evalengine.Type, bool) {
+	switch {
+	case sqltypes.IsIntegral(ltyp.Type) && sqltypes.IsIntegral(rtyp.Type):
+		switch {
+		case sqltypes.IsSigned(ltyp.Type) && sqltypes.IsSigned(rtyp.Type):
+			return evalengine.Type{Type: sqltypes.Int64}, true
+		case sqltypes.IsUnsigned(ltyp.Type) && sqltypes.IsUnsigned(rtyp.Type):
+			return evalengine.Type{Type: sqltypes.Uint64}, true
+		}
+		// equal values of mixed signedness have the same float value, and the
+		// hash collisions this can lead to are removed by the comparison
+		return evalengine.Type{Type: sqltypes.Float64}, true
+	case sqltypes.IsText(ltyp.Type) && sqltypes.IsText(rtyp.Type):
+		if ltyp.Coll == collations.Unknown || ltyp.Coll != rtyp.Coll {
+			return evalengine.Type{}, false
+		}
+		return evalengine.Type{Type: sqltypes.VarChar, Coll: ltyp.Coll}, true
+	case ltyp.Type == rtyp.Type && sqltypes.IsBinary(ltyp.Type):
+		return evalengine.Type{Type: ltyp.Type, Coll: collations.CollationBinaryID}, true
+	case ltyp.Type == rtyp.Type && (sqltypes.IsDate(ltyp.Type) || ltyp.Type == sqltypes.Decimal):
+		return evalengine.Type{Type: ltyp.Type}, true
+	}
+	return evalengine.Type{}, false
+}
+
+// The costs of joins are estimated in rows read, with every query sent to a shard costing
+// roundTripCost rows. Without statistics about the tables, the number of rows of a route
+// is only estimated from how it is targeted.
+const (
+	// estimatedRowsPerValue is the number of rows expected for a value looked up by a non-unique vindex,
+	// or by the predicate pushed to the RHS of a nested loop join
+	estimatedRowsPerValue = 10
+	// estimatedRowsPerINValues is the number of rows expected for the values of an IN predicate looked up by a vindex
+	estimatedRowsPerINValues = 100
+	// estimatedTableRows is the number of rows expected from a route reading a whole table
+	estimatedTableRows = 10000
+	// estimatedShards is the number of shards expected to be reached by a scatter route
+	estimatedShards = 16
+	// estimatedINShards is the number of shards expected to be reached by a route looking up several values
+	estimatedINShards = 4
+	roundTripCost     = 100
+
+	// maxEstimate keeps the estimates from overflowing
+	maxEstimate = 1 << 40
+)
+
+// tryHashJoin returns a HashJoin of the inputs if the join can use one, and if it is estimated
+// to be cheaper than the nested loop join, unless the query asks to prefer hash joins.
+func tryHashJoin(ctx *plancontext.PlanningContext, lhs, rhs ops.Operator, joinPredicates []sqlparser.Expr, applyJoin ops.Operator) ops.Operator {
+	if (!ctx.AllowHashJoin && !ctx.PreferHashJoin) || len(joinPredicates) != 1 {
+		return nil
+	}
+	lhsKey, rhsKey, typ, ok := hashJoinKeys(ctx, joinPredicates[0], TableID(lhs), TableID(rhs))
+	if !ok {
+		return nil
+	}
+	if !ctx.PreferHashJoin && estimateCost(applyJoin) <= addEstimates(estimateCost(lhs), estimateCost(rhs)) {
+		return nil
+	}
+
+	// the hash table is built with the rows of the LHS, so we want the smaller input there
+	if estimateRows(rhs) < estimateRows(lhs) {
+		lhs, rhs = rhs, lhs
+		lhsKey, rhsKey = rhsKey, lhsKey
+	}
+	return newHashJoin(Clone(lhs), Clone(rhs), joinPredicates[0], lhsKey, rhsKey, typ)
+}
+
+// estimateCost estimates the cost of executing the operator.
+func estimateCost(op ops.Operator) int {
+	switch op := op.(type) {
+	case *Route:
+		return addEstimates(routeShards(op)*roundTripCost, estimateRows(op))
+	case *ApplyJoin:
+		return addEstimates(estimateCost(op.LHS), mulEstimates(estimateRows(op.LHS), lookupCost(op.RHS)))
+	}
+	cost := 0
+	for _, input := range op.Inputs() {
+		cost = addEstimates(cost, estimateCost(input))
+	}
+	return cost
+}
+
+// lookupCost estimates the cost of executing the RHS of a nested loop join for one row of the LHS.
+func lookupCost(op ops.Operator) int {
+	if route, ok := op.(*Route); ok {
+		return routeShards(route)*roundTripCost + min(estimateRows(route), estimatedRowsPerValue)
+	}
+	return estimateCost(op)
+}
+
+// estimateRows estimates the number of rows produced by the operator.
+func estimateRows(op ops.Operator) int {
+	switch op := op.(type) {
+	case *Route:
+		return routeRows(op)
+	case *ApplyJoin:
+		return mulEstimates(estimateRows(op.LHS), max(1, min(estimateRows(op.RHS), estimatedRowsPerValue)))
+	case *HashJoin:
+		return max(estimateRows(op.LHS), estimateRows(op.RHS))
+	}
+	inputs := op.Inputs()
+	if len(inputs) == 0 {
+		return 1
+	}
+	return estimateRows(inputs[0])
+}
+
+func routeRows(route *Route) int {
+	switch route.Routing.OpCode() {
+	case engine.None:
+		return 0
+	case engine.EqualUnique, engine.Next, engine.DBA:
+		return 1
+	case engine.Equal, engine.SubShard:
+		return estimatedRowsPerValue
+	case engine.IN, engine.MultiEqual:
+		return estimatedRowsPerINValues
+	default:
+		return estimatedTableRows
+	}
+}
+
+func routeShards(route *Route) int {
+	switch route.Routing.OpCode() {
+	case engine.Scatter:
+		return estimatedShards
+	case engine.IN, engine.MultiEqual, engine.SubShard:
+		return estimatedINShards
+	default:
+		return 1
+	}
+}
+
+func addEstimates(a, b int) int {
+	return min(a+b, maxEstimate)
+}
+
+func mulEstimates(a, b int) int {
+	if a != 0 && b > maxEstimate/a {
+		return maxEstimate
+	}
+	return min(a*b, maxEstimate)
+}
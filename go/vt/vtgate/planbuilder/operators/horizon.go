@@ -92,6 +92,13 @@ func (h *Horizon) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparser.
 		h.Source = h.Source.AddPredicate(ctx, expr)
 		return h
 	}
+	if h.isRecursiveCTE(ctx) {
+		// predicates can't be pushed into a recursive CTE, since that would change the rows the next iteration starts from
+		return &Filter{
+			Source:     h,
+			Predicates: []sqlparser.Expr{expr},
+		}
+	}
 	tableInfo, err := ctx.SemTable.TableInfoForExpr(expr)
 	if err != nil {
 		if errors.Is(err, semantics.ErrNotSingleTable) {
@@ -104,13 +111,22 @@ func (h *Horizon) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparser.
 	}
 
 	newExpr := semantics.RewriteDerivedTableExpression(expr, tableInfo)
-	if sqlparser.ContainsAggregation(newExpr) {
+	if sqlparser.ContainsAggregation(newExpr) || sqlparser.ContainsWindowFunc(newExpr) {
 		return &Filter{Source: h, Predicates: []sqlparser.Expr{expr}}
 	}
 	h.Source = h.Source.AddPredicate(ctx, newExpr)
 	return h
 }
 
+func (h *Horizon) isRecursiveCTE(ctx *plancontext.PlanningContext) bool {
+	union, ok := h.Query.(*sqlparser.Union)
+	if !ok {
+		return false
+	}
+	_, isRecursive := ctx.SemTable.RecursiveCTEs[union]
+	return isRecursive
+}
+
 func (h *Horizon) AddColumn(ctx *plancontext.PlanningContext, reuse bool, _ bool, expr *sqlparser.AliasedExpr) int {
 	if !reuse {
 		panic(errNoNewColumns)
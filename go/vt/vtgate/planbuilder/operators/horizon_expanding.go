@@ -144,6 +144,10 @@ func createProjectionFromSelect(ctx *plancontext.PlanningContext, horizon *Horiz
 		}
 	}
 
+	if qp.HasWindow && !canPushWindows(ctx, horizon) {
+		return createProjectionForWindows(ctx, horizon, qp, dt)
+	}
+
 	if !qp.NeedsAggregation() {
 		projX := createProjectionWithoutAggr(ctx, qp, horizon.src())
 		projX.DT = dt
@@ -231,6 +235,43 @@ func createProjectionForComplexAggregation(a *Aggregator, qp *QueryProjection) o
 	return p
 }
 
+// canPushWindows returns true if the window functions can be sent to MySQL as part of the projection
+func canPushWindows(ctx *plancontext.PlanningContext, horizon *Horizon) bool {
+	rb, isRoute := horizon.src().(*Route)
+	if !isRoute {
+		return false
+	}
+	sel, isSel := horizon.selectStatement().(*sqlparser.Select)
+	return isSel && (rb.IsSingleShard() || windowsPartitionedByVindex(ctx, sel, rb))
+}
+
+func createProjectionForWindows(ctx *plancontext.PlanningContext, horizon *Horizon, qp *QueryProjection, dt *DerivedTable) ops.Operator {
+	if qp.NeedsAggregation() {
+		panic(vterrors.VT12001("window functions together with aggregation in a cross-shard query"))
+	}
+	if qp.HasStar {
+		panic(vterrors.VT09015())
+	}
+	sel := horizon.selectStatement().(*sqlparser.Select)
+	w := createWindow(ctx, sel, horizon.src())
+	w.DT = dt
+
+	p := newAliasedProjection(w)
+	p.DT = dt
+	for _, expr := range qp.SelectExprs {
+		ae, err := expr.GetAliasedExpr()
+		if err != nil {
+			panic(err)
+		}
+
+		_, err = p.addProjExpr(newProjExpr(ae))
+		if err != nil {
+			panic(err)
+		}
+	}
+	return p
+}
+
 func createProjectionWithoutAggr(ctx *plancontext.PlanningContext, qp *QueryProjection, src ops.Operator) *Projection {
 	// first we need to check if we have all columns or there are still unexpanded stars
 	aes, err := slice.MapWithError(qp.SelectExprs, func(from SelectExpr) (*sqlparser.AliasedExpr, error) {
@@ -38,6 +38,13 @@ func planOffsets(ctx *plancontext.PlanningContext, root ops.Operator) (ops.Opera
 		switch op := in.(type) {
 		case *Horizon:
 			return nil, nil, vterrors.VT13001(fmt.Sprintf("should not see %T here", in))
+		case *HashJoin:
+			// the hash join is replaced by a projection evaluating its columns
+			newOp, err := op.planOffsets(ctx)
+			if err != nil || newOp == in {
+				return in, rewrite.SameTree, err
+			}
+			return newOp, rewrite.NewTree("added projection on top of hash join", newOp), nil
 		case offsettable:
 			op.planOffsets(ctx)
 		}
@@ -47,7 +54,16 @@ func planOffsets(ctx *plancontext.PlanningContext, root ops.Operator) (ops.Opera
 		return in, rewrite.SameTree, nil
 	}
 
-	return rewrite.TopDown(root, TableID, visitor, stopAtRoute)
+	return rewrite.TopDown(root, TableID, visitor, stopAtRouteOrDMLWithInput)
+}
+
+// stopAtRouteOrDMLWithInput stops at routes, and at DMLWithInput operators,
+// since their inputs have already been fully planned using their own planning context
+func stopAtRouteOrDMLWithInput(operator ops.Operator) rewrite.VisitRule {
+	if _, isDMLWithInput := operator.(*DMLWithInput); isDMLWithInput {
+		return rewrite.SkipChildren
+	}
+	return stopAtRoute(operator)
 }
 
 func fetchByOffset(e sqlparser.SQLNode) bool {
@@ -153,6 +153,10 @@ func tryTruncateColumnsAt(op ops.Operator, truncateAt int) bool {
 	case *Limit:
 		return tryTruncateColumnsAt(op.Source, truncateAt)
 	case *SubQuery:
+		if op.ApplyProjection {
+			// the columns of the outer are shifted by the value of the subquery
+			return false
+		}
 		for _, offset := range op.Vars {
 			if offset >= truncateAt {
 				return false
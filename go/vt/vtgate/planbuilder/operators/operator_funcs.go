@@ -72,6 +72,22 @@ func RemovePredicate(ctx *plancontext.PlanningContext, expr sqlparser.Expr, op o
 
 		op.Predicate = ctx.SemTable.AndExpressions(keep...)
 		return op, nil
+	case *HashJoin:
+		deps := ctx.SemTable.RecursiveDeps(expr)
+		var err error
+		switch {
+		case deps.IsSolvedBy(TableID(op.LHS)):
+			op.LHS, err = RemovePredicate(ctx, expr, op.LHS)
+		case deps.IsSolvedBy(TableID(op.RHS)):
+			op.RHS, err = RemovePredicate(ctx, expr, op.RHS)
+		default:
+			// the hash join only evaluates its join predicate, the other predicates are filtered above
+			err = vterrors.VT12001(fmt.Sprintf("remove '%s' predicate on cross-shard join query", sqlparser.String(expr)))
+		}
+		if err != nil {
+			return nil, err
+		}
+		return op, nil
 	case *Filter:
 		idx := -1
 		for i, predicate := range op.Predicates {
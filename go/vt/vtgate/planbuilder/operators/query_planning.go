@@ -111,6 +111,8 @@ func runRewriters(ctx *plancontext.PlanningContext, root ops.Operator) (ops.Oper
 			return tryPushDistinct(in)
 		case *Union:
 			return tryPushUnion(ctx, in)
+		case *RecurseCTE:
+			return tryMergeRecurseCTE(ctx, in)
 		case *SubQueryContainer:
 			return pushOrMergeSubQueryContainer(ctx, in)
 		case *QueryGraph:
@@ -187,6 +189,7 @@ func pushOrExpandHorizon(ctx *plancontext.PlanningContext, in *Horizon) (ops.Ope
 		!hasHaving &&
 		!needsOrdering &&
 		!qp.NeedsAggregation() &&
+		(!qp.HasWindow || windowsPartitionedByVindex(ctx, sel, rb)) &&
 		!in.selectStatement().IsDistinct() &&
 		in.selectStatement().GetLimit() == nil
 
@@ -238,6 +241,12 @@ func pushProjectionToOuter(ctx *plancontext.PlanningContext, p *Projection, sq *
 		return p, rewrite.SameTree, nil
 	}
 
+	if sq.ApplyProjection {
+		// the value of the subquery is only available on top of the subquery,
+		// so the projection has to stay here
+		return p, rewrite.SameTree, nil
+	}
+
 	outer := TableID(sq.Outer)
 	for _, pe := range ap {
 		_, isOffset := pe.Info.(*Offset)
@@ -511,9 +520,12 @@ func setUpperLimit(in *Limit) (ops.Operator, *rewrite.ApplyResult, error) {
 	var result *rewrite.ApplyResult
 	shouldVisit := func(op ops.Operator) rewrite.VisitRule {
 		switch op := op.(type) {
-		case *Join, *ApplyJoin, *SubQueryContainer, *SubQuery:
+		case *Join, *ApplyJoin, *HashJoin, *SubQueryContainer, *SubQuery:
 			// we can't push limits down on either side
 			return rewrite.SkipChildren
+		case *Window:
+			// the window functions need to see all the rows of their partitions
+			return rewrite.SkipChildren
 		case *Route:
 			newSrc := &Limit{
 				Source: op.Source,
@@ -578,7 +590,7 @@ func tryPushOrdering(ctx *plancontext.PlanningContext, in *Ordering) (ops.Operat
 		outerTableID := TableID(src.Outer)
 		for _, order := range in.Order {
 			deps := ctx.SemTable.RecursiveDeps(order.Inner.Expr)
-			if !deps.IsSolvedBy(outerTableID) {
+			if !deps.IsSolvedBy(outerTableID) || src.dependsOnApplyProjection(order.Inner.Expr) {
 				return in, rewrite.SameTree, nil
 			}
 		}
@@ -714,7 +726,7 @@ func tryPushFilter(ctx *plancontext.PlanningContext, in *Filter) (ops.Operator,
 		outerTableID := TableID(src.Outer)
 		for _, pred := range in.Predicates {
 			deps := ctx.SemTable.RecursiveDeps(pred)
-			if !deps.IsSolvedBy(outerTableID) {
+			if !deps.IsSolvedBy(outerTableID) || src.dependsOnApplyProjection(pred) {
 				return in, rewrite.SameTree, nil
 			}
 		}
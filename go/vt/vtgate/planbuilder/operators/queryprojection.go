@@ -50,6 +50,9 @@ type (
 		OrderExprs   []ops.OrderBy
 		HasStar      bool
 
+		// HasWindow is true if the select expressions contain window functions
+		HasWindow bool
+
 		// AddedColumn keeps a counter for expressions added to solve HAVING expressions the user is not selecting
 		AddedColumn int
 
@@ -297,6 +300,9 @@ func (qp *QueryProjection) addSelectExpressions(sel *sqlparser.Select) error {
 				col.Aggr = true
 				qp.HasAggr = true
 			}
+			if sqlparser.ContainsWindowFunc(selExp.Expr) {
+				qp.HasWindow = true
+			}
 
 			qp.SelectExprs = append(qp.SelectExprs, col)
 		case *sqlparser.StarExpr:
@@ -320,6 +326,10 @@ func containsAggr(e sqlparser.SQLNode) (hasAggr bool) {
 			// so we don't need to worry about aggregation in the original
 			return false, nil
 		case sqlparser.AggrFunc:
+			if sqlparser.IsWindowFunc(node) {
+				// aggregations with an OVER clause are evaluated by the Window operator
+				return true, nil
+			}
 			hasAggr = true
 			return false, io.EOF
 		case *sqlparser.Subquery:
@@ -0,0 +1,425 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"fmt"
+	"slices"
+
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/operators/ops"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/operators/rewrite"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
+)
+
+// RecurseCTE is used to represent a recursive common table expression.
+// The Seed produces the first rows of the CTE, and the Term is executed once for every row
+// of the previous iteration, until no more rows are produced.
+// The columns of the CTE the Term reads are replaced by bind variables, so the Term
+// does not depend on the CTE table any longer, and can be planned like any other query.
+// If both sides end up in the same route, the CTE is turned back into SQL and sent to MySQL as a whole.
+type RecurseCTE struct {
+	Seed, Term ops.Operator
+
+	Def *semantics.RecursiveCTE
+	// CTEID is the table set of the CTE table the Term reads from
+	CTEID semantics.TableSet
+
+	// Vars are the columns of the previous iteration that are sent to the Term.
+	// The string is the bind variable name used in the Term, and the number is the offset of the column
+	Vars map[string]int
+
+	// These are the select expressions coming from the Seed and the Term
+	Selects  []sqlparser.SelectExprs
+	Distinct bool
+
+	// varCols keeps the original column for every bind variable, so the Term can be turned back into SQL
+	varCols map[string]*sqlparser.ColName
+	// termFromDual is true when the CTE was the only table in the FROM clause of the Term
+	termFromDual bool
+
+	columns            sqlparser.SelectExprs
+	columnsAsAliasedEs []*sqlparser.AliasedExpr
+}
+
+func createRecurseCTE(ctx *plancontext.PlanningContext, node *sqlparser.Union, def *semantics.RecursiveCTE) (ops.Operator, error) {
+	seed, err := translateQueryToOp(ctx, node.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	rcte := &RecurseCTE{
+		Def:      def,
+		Vars:     map[string]int{},
+		varCols:  map[string]*sqlparser.ColName{},
+		Distinct: node.Distinct,
+		columns:  ctx.SemTable.SelectExprs(node),
+	}
+
+	term, err := rcte.rewriteTerm(ctx)
+	if err != nil {
+		return nil, err
+	}
+	termOp, err := translateQueryToOp(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+
+	rcte.Seed = seed
+	rcte.Term = termOp
+	rcte.Selects = []sqlparser.SelectExprs{ctx.SemTable.SelectExprs(node.Left), term.SelectExprs}
+	return newHorizon(rcte, node), nil
+}
+
+// rewriteTerm returns a copy of the recursive part of the CTE, where the columns coming from the
+// CTE have been replaced by bind variables, and where the CTE itself has been removed from the FROM clause
+func (r *RecurseCTE) rewriteTerm(ctx *plancontext.PlanningContext) (*sqlparser.Select, error) {
+	cteID := ctx.SemTable.TableSetFor(r.Def.SelfRef)
+	r.CTEID = cteID
+	tableInfo, err := ctx.SemTable.TableInfoFor(cteID)
+	if err != nil {
+		return nil, err
+	}
+	cteTable, ok := tableInfo.(*semantics.CTETable)
+	if !ok {
+		return nil, vterrors.VT13001(fmt.Sprintf("expected the recursive reference to be a CTE table, got %T", tableInfo))
+	}
+
+	argFor := map[string]string{}
+	term := sqlparser.CopyOnRewrite(r.Def.Term, nil, func(cursor *sqlparser.CopyOnWriteCursor) {
+		col, ok := cursor.Node().(*sqlparser.ColName)
+		if !ok || ctx.SemTable.DirectDeps(col) != cteID {
+			return
+		}
+		name := col.Name.Lowered()
+		argName, found := argFor[name]
+		if !found {
+			offset := cteTable.ColumnOffset(name)
+			if offset < 0 {
+				err = vterrors.VT13001(fmt.Sprintf("could not find the column '%s' on the recursive CTE", sqlparser.String(col)))
+				cursor.StopTreeWalk()
+				return
+			}
+			argName = ctx.ReservedVars.ReserveColName(col)
+			argFor[name] = argName
+			r.Vars[argName] = offset
+			r.varCols[argName] = col
+		}
+		cursor.Replace(sqlparser.NewArgument(argName))
+	}, nil).(*sqlparser.Select)
+	if err != nil {
+		return nil, err
+	}
+
+	from, predicates, err := removeCTEReference(term.From, r.Def.SelfRef)
+	if err != nil {
+		return nil, err
+	}
+	if len(from) == 0 {
+		// the CTE was the only table used by the recursive part, so it now reads from dual
+		dual := &sqlparser.AliasedTableExpr{Expr: sqlparser.NewTableName("dual")}
+		ctx.SemTable.ReplaceTableSetFor(cteID, dual)
+		from = sqlparser.TableExprs{dual}
+		r.termFromDual = true
+	}
+
+	// we don't want to change the original AST, so we make a shallow copy of the SELECT before changing it
+	newTerm := *term
+	newTerm.From = from
+	if len(predicates) > 0 {
+		if term.Where != nil {
+			predicates = append([]sqlparser.Expr{term.Where.Expr}, predicates...)
+		}
+		newTerm.Where = sqlparser.NewWhere(sqlparser.WhereClause, sqlparser.AndExpressions(predicates...))
+	}
+	return &newTerm, nil
+}
+
+// removeCTEReference removes the reference to the CTE from the FROM clause of the recursive part.
+// The join conditions of the joins the CTE was part of are returned, so they can be added to the WHERE clause
+func removeCTEReference(exprs sqlparser.TableExprs, ref *sqlparser.AliasedTableExpr) (result sqlparser.TableExprs, predicates []sqlparser.Expr, err error) {
+	for _, expr := range exprs {
+		tbl, preds, err := removeCTEReferenceFrom(expr, ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		predicates = append(predicates, preds...)
+		if tbl != nil {
+			result = append(result, tbl)
+		}
+	}
+	return result, predicates, nil
+}
+
+func removeCTEReferenceFrom(expr sqlparser.TableExpr, ref *sqlparser.AliasedTableExpr) (sqlparser.TableExpr, []sqlparser.Expr, error) {
+	switch expr := expr.(type) {
+	case *sqlparser.AliasedTableExpr:
+		if expr == ref {
+			return nil, nil, nil
+		}
+	case *sqlparser.ParenTableExpr:
+		exprs, predicates, err := removeCTEReference(expr.Exprs, ref)
+		if err != nil || len(exprs) == 0 {
+			return nil, predicates, err
+		}
+		return &sqlparser.ParenTableExpr{Exprs: exprs}, predicates, nil
+	case *sqlparser.JoinTableExpr:
+		lhs, lhsPreds, err := removeCTEReferenceFrom(expr.LeftExpr, ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		rhs, rhsPreds, err := removeCTEReferenceFrom(expr.RightExpr, ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		predicates := append(lhsPreds, rhsPreds...)
+		if lhs != nil && rhs != nil {
+			if lhs == expr.LeftExpr && rhs == expr.RightExpr {
+				return expr, predicates, nil
+			}
+			return &sqlparser.JoinTableExpr{LeftExpr: lhs, Join: expr.Join, RightExpr: rhs, Condition: expr.Condition}, predicates, nil
+		}
+
+		// one side of this join was the CTE
+		if expr.Join != sqlparser.NormalJoinType && expr.Join != sqlparser.StraightJoinType {
+			return nil, nil, vterrors.VT12001("outer join with the recursive reference of a common table expression")
+		}
+		if expr.Condition != nil {
+			if len(expr.Condition.Using) > 0 {
+				return nil, nil, vterrors.VT12001("join with USING and the recursive reference of a common table expression")
+			}
+			if expr.Condition.On != nil {
+				predicates = append(predicates, expr.Condition.On)
+			}
+		}
+		if lhs != nil {
+			return lhs, predicates, nil
+		}
+		return rhs, predicates, nil
+	}
+	return expr, nil, nil
+}
+
+// restoreTerm turns the recursive part of the CTE back into a query reading from the CTE
+func (r *RecurseCTE) restoreTerm(term *sqlparser.Select) *sqlparser.Select {
+	restored := sqlparser.CopyOnRewrite(term, nil, func(cursor *sqlparser.CopyOnWriteCursor) {
+		arg, ok := cursor.Node().(*sqlparser.Argument)
+		if !ok {
+			return
+		}
+		if col, found := r.varCols[arg.Name]; found {
+			cursor.Replace(col)
+		}
+	}, nil).(*sqlparser.Select)
+
+	selfRef := sqlparser.CloneRefOfAliasedTableExpr(r.Def.SelfRef)
+	if r.termFromDual {
+		restored.From = sqlparser.TableExprs{selfRef}
+	} else {
+		restored.From = append(restored.From, selfRef)
+	}
+	return restored
+}
+
+// tryMergeRecurseCTE merges the Seed and the Term into a single route when both sides
+// are sent to the same place, and the recursion can be done by MySQL on its own
+func tryMergeRecurseCTE(ctx *plancontext.PlanningContext, op *RecurseCTE) (ops.Operator, *rewrite.ApplyResult, error) {
+	seedRoute, termRoute := operatorsToRoutes(op.Seed, op.Term)
+	if seedRoute == nil {
+		return op, rewrite.SameTree, nil
+	}
+	seedRoute, termRoute, seedRouting, termRouting, sameKeyspace := getRoutesOrAlternates(seedRoute, termRoute)
+	seedType, termType := getRoutingType(seedRouting), getRoutingType(termRouting)
+
+	switch {
+	case termType == dual || (termType == anyShard && sameKeyspace):
+		// the recursive part can be evaluated on every shard the seed is sent to.
+		// with UNION DISTINCT, the result from the different shards would have to be deduplicated again
+		if !op.Distinct || seedRoute.IsSingleShard() {
+			return op.merge(seedRoute, termRoute, seedRouting)
+		}
+	case seedType == dual && termType == anyShard:
+		return op.merge(seedRoute, termRoute, termRouting)
+	case seedType == sharded && termType == sharded && sameKeyspace:
+		tblA := seedRouting.(*ShardedRouting)
+		tblB := termRouting.(*ShardedRouting)
+		if tblA.RouteOpCode != engine.EqualUnique || tblB.RouteOpCode != engine.EqualUnique {
+			break
+		}
+		// both sides are sent to the same single shard, so all the rows of the CTE live there
+		if tblA.SelectedVindex() == tblB.SelectedVindex() && gen4ValuesEqual(ctx, tblA.VindexExpressions(), tblB.VindexExpressions()) {
+			return op.merge(seedRoute, termRoute, seedRouting)
+		}
+	}
+	return op, rewrite.SameTree, nil
+}
+
+func (r *RecurseCTE) merge(seed, term *Route, routing Routing) (ops.Operator, *rewrite.ApplyResult, error) {
+	r.Seed = seed.Source
+	r.Term = term.Source
+	route := &Route{
+		Source:     r,
+		MergedWith: []*Route{term},
+		Routing:    routing,
+	}
+	return route, rewrite.NewTree("merged recursive CTE", route), nil
+}
+
+// Clone implements the Operator interface
+func (r *RecurseCTE) Clone(inputs []ops.Operator) ops.Operator {
+	kopy := *r
+	kopy.Seed = inputs[0]
+	kopy.Term = inputs[1]
+	kopy.Selects = slices.Clone(r.Selects)
+	return &kopy
+}
+
+// Inputs implements the Operator interface
+func (r *RecurseCTE) Inputs() []ops.Operator {
+	return []ops.Operator{r.Seed, r.Term}
+}
+
+// SetInputs implements the Operator interface
+func (r *RecurseCTE) SetInputs(operators []ops.Operator) {
+	r.Seed = operators[0]
+	r.Term = operators[1]
+}
+
+func (r *RecurseCTE) GetOrdering(*plancontext.PlanningContext) []ops.OrderBy {
+	return nil
+}
+
+// AddPredicate implements the Operator interface.
+// Predicates can't be pushed into the CTE, since that would change the rows the next iteration starts from
+func (r *RecurseCTE) AddPredicate(_ *plancontext.PlanningContext, expr sqlparser.Expr) ops.Operator {
+	return &Filter{
+		Source:     r,
+		Predicates: []sqlparser.Expr{expr},
+	}
+}
+
+func (r *RecurseCTE) AddColumn(ctx *plancontext.PlanningContext, reuse bool, gb bool, expr *sqlparser.AliasedExpr) int {
+	if reuse {
+		offset := r.FindCol(ctx, expr.Expr, false)
+		if offset >= 0 {
+			return offset
+		}
+	}
+	cols := r.GetColumns(ctx)
+
+	switch e := expr.Expr.(type) {
+	case *sqlparser.ColName:
+		offset := slices.IndexFunc(cols, func(expr *sqlparser.AliasedExpr) bool {
+			return e.Name.EqualString(expr.ColumnName())
+		})
+		if offset == -1 {
+			panic(vterrors.VT13001(fmt.Sprintf("could not find the column '%s' on the recursive CTE", sqlparser.String(e))))
+		}
+		return offset
+	case *sqlparser.WeightStringFuncExpr:
+		argIdx := slices.IndexFunc(cols, func(expr *sqlparser.AliasedExpr) bool {
+			return ctx.SemTable.EqualsExprWithDeps(e.Expr, expr.Expr)
+		})
+		if argIdx == -1 {
+			panic(vterrors.VT13001(fmt.Sprintf("could not find the argument to the weight_string function: %s", sqlparser.String(e.Expr))))
+		}
+		return r.addWeightStringToOffset(ctx, argIdx, gb)
+	default:
+		panic(vterrors.VT13001(fmt.Sprintf("only weight_string function is expected - got %s", sqlparser.String(expr))))
+	}
+}
+
+func (r *RecurseCTE) addWeightStringToOffset(ctx *plancontext.PlanningContext, argIdx int, addToGroupBy bool) (outputOffset int) {
+	for i, src := range r.Inputs() {
+		ae, ok := r.Selects[i][argIdx].(*sqlparser.AliasedExpr)
+		if !ok {
+			panic(vterrors.VT09015())
+		}
+		thisOffset := src.AddColumn(ctx, false, addToGroupBy, aeWrap(weightStringFor(ae.Expr)))
+
+		// all offsets for the newly added ws need to line up
+		if i == 0 {
+			outputOffset = thisOffset
+		} else if thisOffset != outputOffset {
+			panic(vterrors.VT12001("weight_string offsets did not line up for the recursive CTE"))
+		}
+	}
+	return
+}
+
+func (r *RecurseCTE) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, _ bool) int {
+	for idx, col := range r.GetColumns(ctx) {
+		if ctx.SemTable.EqualsExprWithDeps(expr, col.Expr) {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (r *RecurseCTE) GetColumns(ctx *plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	if r.columnsAsAliasedEs == nil {
+		allOk := true
+		r.columnsAsAliasedEs = slice.Map(r.columns, func(from sqlparser.SelectExpr) *sqlparser.AliasedExpr {
+			expr, ok := from.(*sqlparser.AliasedExpr)
+			allOk = allOk && ok
+			return expr
+		})
+		if !allOk {
+			panic(vterrors.VT09015())
+		}
+	}
+
+	// if the inputs have more columns that we expect, we want to show them on top of the CTE,
+	// so the results can be truncated to the expected result columns
+	for _, src := range r.Inputs() {
+		columns := src.GetColumns(ctx)
+		for len(columns) > len(r.columnsAsAliasedEs) {
+			r.columnsAsAliasedEs = append(r.columnsAsAliasedEs, aeWrap(sqlparser.NewIntLiteral("0")))
+		}
+	}
+	return r.columnsAsAliasedEs
+}
+
+func (r *RecurseCTE) GetSelectExprs(ctx *plancontext.PlanningContext) sqlparser.SelectExprs {
+	for _, src := range r.Inputs() {
+		columns := src.GetSelectExprs(ctx)
+		for len(columns) > len(r.columns) {
+			r.columns = append(r.columns, aeWrap(sqlparser.NewIntLiteral("0")))
+		}
+	}
+	return r.columns
+}
+
+// introducesTableID implements the tableIDIntroducer interface.
+// The columns of the CTE depend on the CTE table, so it has to be part of the tables the operator solves
+func (r *RecurseCTE) introducesTableID() semantics.TableSet {
+	return r.CTEID
+}
+
+// NoLHSTableSet is used to signal that the Term does not use any of the tables of the Seed
+func (r *RecurseCTE) NoLHSTableSet() {}
+
+func (r *RecurseCTE) ShortDescription() string {
+	if r.Distinct {
+		return fmt.Sprintf("%s DISTINCT %v", r.Def.Name, r.Vars)
+	}
+	return fmt.Sprintf("%s %v", r.Def.Name, r.Vars)
+}
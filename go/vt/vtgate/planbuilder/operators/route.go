@@ -584,6 +584,10 @@ type selectExpressions interface {
 func addMultipleColumnsToInput(ctx *plancontext.PlanningContext, operator ops.Operator, reuse bool, addToGroupBy []bool, exprs []*sqlparser.AliasedExpr) (ops.Operator, bool, []int) {
 	switch op := operator.(type) {
 	case *SubQuery:
+		if op.ApplyProjection {
+			// the columns of the outer are shifted by the value of the subquery
+			return op, false, nil
+		}
 		src, added, offset := addMultipleColumnsToInput(ctx, op.Outer, reuse, addToGroupBy, exprs)
 		if added {
 			op.Outer = src
@@ -390,6 +390,11 @@ func mergeOrJoin(ctx *plancontext.PlanningContext, lhs, rhs ops.Operator, joinPr
 	if err != nil {
 		return nil, nil, err
 	}
+	if inner {
+		if hashJoin := tryHashJoin(ctx, lhs, rhs, joinPredicates, newOp); hashJoin != nil {
+			return hashJoin, rewrite.NewTree("logical join to hashJoin", hashJoin), nil
+		}
+	}
 	return newOp, rewrite.NewTree("logical join to applyJoin ", newOp), nil
 }
 
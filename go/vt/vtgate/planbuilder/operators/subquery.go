@@ -18,6 +18,7 @@ package operators
 
 import (
 	"fmt"
+	"io"
 	"maps"
 	"slices"
 
@@ -25,6 +26,7 @@ import (
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/operators/ops"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 	"vitess.io/vitess/go/vt/vtgate/semantics"
@@ -51,6 +53,12 @@ type SubQuery struct {
 	Vars    map[string]int // Arguments copied from outer to inner, set during offset planning.
 	outerID semantics.TableSet
 
+	// Fields related to correlated subqueries that can't be merged with the outer query,
+	// and have to be evaluated at the vtgate level for every row of the outer:
+	ApplyPredicate            sqlparser.Expr  // Predicate using the values produced by the subquery to filter the outer rows.
+	ApplyPredicateWithOffsets evalengine.Expr // ApplyPredicate with outer columns turned into offsets, set during offset planning.
+	ApplyProjection           bool            // The value of the subquery is added as the first column of the outer rows.
+
 	IsProjection bool
 }
 
@@ -66,6 +74,19 @@ func (sq *SubQuery) planOffsets(ctx *plancontext.PlanningContext) {
 			sq.Vars[lhsExpr.Name] = offset
 		}
 	}
+
+	if sq.ApplyPredicate == nil {
+		return
+	}
+	rewritten := useOffsets(ctx, sq.ApplyPredicate, sq)
+	eexpr, err := evalengine.Translate(rewritten, &evalengine.Config{
+		ResolveType: ctx.SemTable.TypeForExpr,
+		Collation:   ctx.SemTable.Collation,
+	})
+	if err != nil {
+		panic(err)
+	}
+	sq.ApplyPredicateWithOffsets = eexpr
 }
 
 func (sq *SubQuery) OuterExpressionsNeeded(ctx *plancontext.PlanningContext, outer ops.Operator) (result []*sqlparser.ColName, err error) {
@@ -164,6 +185,9 @@ func (sq *SubQuery) ShortDescription() string {
 		preds := append(sq.Predicates, sq.OuterPredicate)
 		pred = " MERGE ON " + sqlparser.String(sqlparser.AndExpressions(preds...))
 	}
+	if sq.ApplyPredicate != nil {
+		pred += " APPLY " + sqlparser.String(sq.ApplyPredicate)
+	}
 	return fmt.Sprintf("%s %v%s", typ, sq.FilterType.String(), pred)
 }
 
@@ -173,19 +197,65 @@ func (sq *SubQuery) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparse
 }
 
 func (sq *SubQuery) AddColumn(ctx *plancontext.PlanningContext, reuseExisting bool, addToGroupBy bool, exprs *sqlparser.AliasedExpr) int {
-	return sq.Outer.AddColumn(ctx, reuseExisting, addToGroupBy, exprs)
+	if !sq.ApplyProjection {
+		return sq.Outer.AddColumn(ctx, reuseExisting, addToGroupBy, exprs)
+	}
+	if sq.isApplyProjectionColumn(exprs.Expr) {
+		return 0
+	}
+	return sq.Outer.AddColumn(ctx, reuseExisting, addToGroupBy, exprs) + 1
 }
 
 func (sq *SubQuery) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, underRoute bool) int {
-	return sq.Outer.FindCol(ctx, expr, underRoute)
+	if !sq.ApplyProjection {
+		return sq.Outer.FindCol(ctx, expr, underRoute)
+	}
+	if sq.isApplyProjectionColumn(expr) {
+		return 0
+	}
+	offset := sq.Outer.FindCol(ctx, expr, underRoute)
+	if offset < 0 {
+		return offset
+	}
+	return offset + 1
 }
 
 func (sq *SubQuery) GetColumns(ctx *plancontext.PlanningContext) []*sqlparser.AliasedExpr {
-	return sq.Outer.GetColumns(ctx)
+	if !sq.ApplyProjection {
+		return sq.Outer.GetColumns(ctx)
+	}
+	return append([]*sqlparser.AliasedExpr{aeWrap(sqlparser.NewColName(sq.ArgName))}, sq.Outer.GetColumns(ctx)...)
 }
 
 func (sq *SubQuery) GetSelectExprs(ctx *plancontext.PlanningContext) sqlparser.SelectExprs {
-	return sq.Outer.GetSelectExprs(ctx)
+	if !sq.ApplyProjection {
+		return sq.Outer.GetSelectExprs(ctx)
+	}
+	return transformColumnsToSelectExprs(ctx, sq)
+}
+
+// dependsOnApplyProjection returns true if the expression uses the value
+// of this subquery that is only available on top of the SubQuery operator
+func (sq *SubQuery) dependsOnApplyProjection(expr sqlparser.Expr) bool {
+	if !sq.ApplyProjection {
+		return false
+	}
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if e, ok := node.(sqlparser.Expr); ok && sq.isApplyProjectionColumn(e) {
+			found = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, expr)
+	return found
+}
+
+// isApplyProjectionColumn returns true if the expression is the column
+// used in projections to refer to the value of this subquery
+func (sq *SubQuery) isApplyProjectionColumn(expr sqlparser.Expr) bool {
+	col, ok := expr.(*sqlparser.ColName)
+	return ok && col.Qualifier.IsEmpty() && col.Name.String() == sq.ArgName
 }
 
 // GetMergePredicates returns the predicates that we can use to try to merge this subquery with the outer query.
@@ -203,7 +273,7 @@ func (sq *SubQuery) settle(ctx *plancontext.PlanningContext, outer ops.Operator)
 	if sq.IsProjection {
 		if len(sq.GetMergePredicates()) > 0 {
 			// this means that we have a correlated subquery on our hands
-			return nil, correlatedSubqueryErr
+			return sq.settleCorrelatedProjection(ctx, outer)
 		}
 		sq.SubqueryValueName = sq.ArgName
 		return outer, nil
@@ -211,22 +281,88 @@ func (sq *SubQuery) settle(ctx *plancontext.PlanningContext, outer ops.Operator)
 	return sq.settleFilter(ctx, outer)
 }
 
-var correlatedSubqueryErr = vterrors.VT12001("correlated subquery is only supported for EXISTS")
-var subqueryNotAtTopErr = vterrors.VT12001("unmergable subquery can not be inside complex expression")
+// settleCorrelatedProjection is used for correlated subqueries in the SELECT list that could not be merged.
+// The subquery is evaluated for every row of the outer, and its value is added to the row.
+func (sq *SubQuery) settleCorrelatedProjection(ctx *plancontext.PlanningContext, outer ops.Operator) (ops.Operator, error) {
+	switch sq.FilterType {
+	case opcode.PulloutValue, opcode.PulloutExists:
+	default:
+		return nil, correlatedInProjectionErr
+	}
+	if err := sq.checkCorrelationColumns(ctx, outer); err != nil {
+		return nil, err
+	}
+	sq.ApplyProjection = true
+	return outer, nil
+}
 
-func (sq *SubQuery) settleFilter(ctx *plancontext.PlanningContext, outer ops.Operator) (ops.Operator, error) {
-	if len(sq.Predicates) > 0 {
-		if sq.FilterType != opcode.PulloutExists {
-			return nil, correlatedSubqueryErr
-		}
-		return outer, nil
+// settleCorrelatedFilter is used for correlated subqueries in predicates that could not be merged,
+// and that are not simple EXISTS checks. The subquery is evaluated for every row of the outer,
+// and the predicate using the result of the subquery is evaluated at the vtgate level.
+func (sq *SubQuery) settleCorrelatedFilter(ctx *plancontext.PlanningContext, outer ops.Operator) (ops.Operator, error) {
+	if err := sq.checkCorrelationColumns(ctx, outer); err != nil {
+		return nil, err
 	}
 
-	hasValuesArg := func() string {
-		s := ctx.ReservedVars.ReserveVariable(string(sqlparser.HasValueSubQueryBaseName))
-		sq.HasValuesName = s
-		return s
+	hasValuesArg := func() sqlparser.Expr {
+		sq.HasValuesName = ctx.ReservedVars.ReserveVariable(string(sqlparser.HasValueSubQueryBaseName))
+		return sqlparser.NewArgument(sq.HasValuesName)
+	}
+	rhsPred := sq.rewriteOriginalToArguments(ctx)
+
+	switch sq.FilterType {
+	case opcode.PulloutNotExists:
+		sq.FilterType = opcode.PulloutExists
+		sq.ApplyPredicate = sqlparser.NewNotExpr(hasValuesArg())
+	case opcode.PulloutIn:
+		sq.ApplyPredicate = sqlparser.AndExpressions(hasValuesArg(), rhsPred)
+		sq.SubqueryValueName = sq.ArgName
+	case opcode.PulloutNotIn:
+		sq.ApplyPredicate = &sqlparser.OrExpr{Left: sqlparser.NewNotExpr(hasValuesArg()), Right: rhsPred}
+		sq.SubqueryValueName = sq.ArgName
+	case opcode.PulloutValue:
+		sq.ApplyPredicate = rhsPred
+		sq.SubqueryValueName = sq.ArgName
+	default:
+		return nil, vterrors.VT13001(fmt.Sprintf("unexpected correlated subquery type: %s", sq.FilterType.String()))
 	}
+	return outer, nil
+}
+
+// checkCorrelationColumns makes sure that the values the subquery needs from the outer
+// can be fetched for every row of the outer query
+func (sq *SubQuery) checkCorrelationColumns(ctx *plancontext.PlanningContext, outer ops.Operator) error {
+	joinColumns, err := sq.GetJoinColumns(ctx, outer)
+	if err != nil {
+		return err
+	}
+	for _, jc := range joinColumns {
+		for _, lhsExpr := range jc.LHSExprs {
+			if sqlparser.ContainsAggregation(lhsExpr.Expr) {
+				// aggregations from the outer query can't be evaluated row by row
+				return correlatedAggregationErr
+			}
+		}
+	}
+
+	// every column coming from outside the subquery has to be provided by the outer side
+	innerID := TableID(sq.Subquery)
+	outerID := TableID(outer)
+	return sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		col, ok := node.(*sqlparser.ColName)
+		if !ok {
+			return true, nil
+		}
+		deps := ctx.SemTable.RecursiveDeps(col)
+		if deps.IsSolvedBy(innerID) || deps.IsSolvedBy(outerID) {
+			return true, nil
+		}
+		return false, correlatedOuterColumnErr
+	}, sq.originalSubquery.Select)
+}
+
+// rewriteOriginalToArguments returns the original expression with the subquery replaced by its argument
+func (sq *SubQuery) rewriteOriginalToArguments(ctx *plancontext.PlanningContext) sqlparser.Expr {
 	post := func(cursor *sqlparser.CopyOnWriteCursor) {
 		node := cursor.Node()
 		if _, ok := node.(*sqlparser.Subquery); !ok {
@@ -241,7 +377,28 @@ func (sq *SubQuery) settleFilter(ctx *plancontext.PlanningContext, outer ops.Ope
 		}
 		cursor.Replace(arg)
 	}
-	rhsPred := sqlparser.CopyOnRewrite(sq.Original, dontEnterSubqueries, post, ctx.SemTable.CopySemanticInfo).(sqlparser.Expr)
+	return sqlparser.CopyOnRewrite(sq.Original, dontEnterSubqueries, post, ctx.SemTable.CopySemanticInfo).(sqlparser.Expr)
+}
+
+var correlatedInProjectionErr = vterrors.VT12001("correlated IN subquery in the SELECT list")
+var correlatedAggregationErr = vterrors.VT12001("correlated subquery using an aggregation from the outer query")
+var correlatedOuterColumnErr = vterrors.VT12001("correlated subquery using columns that are not available in its outer query")
+var subqueryNotAtTopErr = vterrors.VT12001("unmergable subquery can not be inside complex expression")
+
+func (sq *SubQuery) settleFilter(ctx *plancontext.PlanningContext, outer ops.Operator) (ops.Operator, error) {
+	if len(sq.Predicates) > 0 {
+		if sq.FilterType != opcode.PulloutExists {
+			return sq.settleCorrelatedFilter(ctx, outer)
+		}
+		return outer, nil
+	}
+
+	hasValuesArg := func() string {
+		s := ctx.ReservedVars.ReserveVariable(string(sqlparser.HasValueSubQueryBaseName))
+		sq.HasValuesName = s
+		return s
+	}
+	rhsPred := sq.rewriteOriginalToArguments(ctx)
 
 	var predicates []sqlparser.Expr
 	switch sq.FilterType {
@@ -44,6 +44,11 @@ func isMergeable(ctx *plancontext.PlanningContext, query sqlparser.SelectStateme
 
 	switch node := query.(type) {
 	case *sqlparser.Select:
+		if sqlparser.ContainsWindowFunc(node.SelectExprs) && !windowsPartitionedByVindex(ctx, node, op) {
+			// the partitions of the window functions might span more than one shard
+			return false
+		}
+
 		if len(node.GroupBy) > 0 {
 			// iff we are grouping, we need to check that we can perform the grouping inside a single shard, and we check that
 			// by checking that one of the grouping expressions used is a unique single column vindex.
@@ -559,11 +564,43 @@ func pushOrMerge(ctx *plancontext.PlanningContext, outer ops.Operator, inner *Su
 			return outer, rewrite.SameTree, nil
 		}
 		return join, applyResult, nil
+	case *HashJoin:
+		join, applyResult := tryPushSubQueryInHashJoin(ctx, inner, o)
+		if join == nil {
+			return outer, rewrite.SameTree, nil
+		}
+		return join, applyResult, nil
 	default:
 		return outer, rewrite.SameTree, nil
 	}
 }
 
+// tryPushSubQueryInHashJoin pushes a SubQuery down to the side of a HashJoin its merge
+// predicates depend on. Unlike with an ApplyJoin, each side of a hash join is executed
+// once, and no values flow between them, so the subquery can't depend on both sides.
+func tryPushSubQueryInHashJoin(ctx *plancontext.PlanningContext, inner *SubQuery, outer *HashJoin) (ops.Operator, *rewrite.ApplyResult) {
+	if _, ok := inner.Subquery.(*Projection); ok {
+		// see tryPushSubQueryInJoin
+		return nil, rewrite.SameTree
+	}
+
+	deps := semantics.EmptyTableSet()
+	for _, predicate := range inner.GetMergePredicates() {
+		deps = deps.Merge(ctx.SemTable.RecursiveDeps(predicate))
+	}
+	deps = deps.Remove(TableID(inner.Subquery))
+
+	switch {
+	case deps.IsSolvedBy(TableID(outer.LHS)):
+		outer.LHS = addSubQuery(outer.LHS, inner)
+		return outer, rewrite.NewTree("push subquery into LHS of hash join", inner)
+	case deps.IsSolvedBy(TableID(outer.RHS)):
+		outer.RHS = addSubQuery(outer.RHS, inner)
+		return outer, rewrite.NewTree("push subquery into RHS of hash join", inner)
+	}
+	return nil, rewrite.SameTree
+}
+
 type subqueryRouteMerger struct {
 	outer    *Route
 	original sqlparser.Expr
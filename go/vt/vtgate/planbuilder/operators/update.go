@@ -171,9 +171,8 @@ func createUpdateOperator(ctx *plancontext.PlanningContext, updStmt *sqlparser.U
 		}
 	}
 
-	if routing.OpCode() == engine.Scatter && updStmt.Limit != nil {
-		// TODO systay: we should probably check for other op code types - IN could also hit multiple shards (2022-04-07)
-		return nil, vterrors.VT12001("multi shard UPDATE with LIMIT")
+	if needsDMLWithInput(routing, updStmt.Limit) {
+		return createUpdateWithInput(ctx, updStmt, vindexTable)
 	}
 
 	route := &Route{
@@ -202,6 +201,21 @@ func createUpdateOperator(ctx *plancontext.PlanningContext, updStmt *sqlparser.U
 	return sqc.getRootOperator(route, decorator), nil
 }
 
+// createUpdateWithInput plans a multi-shard UPDATE with LIMIT by first selecting
+// the primary keys of the rows to update, and then updating exactly those rows.
+func createUpdateWithInput(ctx *plancontext.PlanningContext, updStmt *sqlparser.Update, vindexTable *vindexes.Table) (ops.Operator, error) {
+	return createDMLWithInput(ctx, vindexTable, updStmt.TableExprs, updStmt.Where, updStmt.OrderBy, updStmt.Limit,
+		func(where *sqlparser.Where) sqlparser.Statement {
+			return &sqlparser.Update{
+				Comments:   updStmt.Comments,
+				Ignore:     updStmt.Ignore,
+				TableExprs: sqlparser.CloneTableExprs(updStmt.TableExprs),
+				Exprs:      sqlparser.CloneUpdateExprs(updStmt.Exprs),
+				Where:      where,
+			}
+		})
+}
+
 func buildFkOperator(ctx *plancontext.PlanningContext, updOp ops.Operator, updClone *sqlparser.Update, parentFks []vindexes.ParentFKInfo, childFks []vindexes.ChildFKInfo, updatedTable *vindexes.Table) (ops.Operator, error) {
 	// We only support simple expressions in update queries for foreign key handling.
 	if isNonLiteral(updClone.Exprs, parentFks, childFks) {
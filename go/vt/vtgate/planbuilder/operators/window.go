@@ -0,0 +1,533 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/operators/ops"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
+)
+
+type (
+	// Window evaluates window functions at the vtgate level.
+	// All the window functions evaluated by one Window share the same partitioning and ordering,
+	// and the input is expected to be sorted by the partition expressions followed by the window ordering.
+	// Like the Aggregator, the columns of the Window map one to one to the columns of its source:
+	// the source produces the argument of each window function, and the Window replaces it with the result.
+	Window struct {
+		Source  ops.Operator
+		Columns []*sqlparser.AliasedExpr
+
+		PartitionBy []WindowPartition
+		OrderBy     []ops.OrderBy
+		Functions   []WindowFunction
+
+		// the offsets point to columns on the same window, and are used to compare the rows of a partition
+		OrderOffsets  []int
+		OrderWOffsets []int
+
+		offsetPlanned bool
+		ResultColumns int
+
+		DT *DerivedTable
+	}
+
+	// WindowPartition is one of the PARTITION BY expressions shared by the window functions
+	WindowPartition struct {
+		Expr sqlparser.Expr
+
+		ColOffset int
+		WSOffset  int
+	}
+
+	// WindowFunction encodes all information needed to evaluate a window function
+	WindowFunction struct {
+		Original *sqlparser.AliasedExpr
+		Func     sqlparser.WindowFunc
+		OpCode   opcode.WindowOpcode
+
+		// Frame is nil when the function uses the default frame
+		Frame *engine.WindowFrame
+
+		// Offset and Default are used by LAG and LEAD
+		Offset  int64
+		Default sqlparser.Expr
+
+		// ColOffset points to the column on the same window
+		ColOffset int
+	}
+)
+
+func (w *Window) Clone(inputs []ops.Operator) ops.Operator {
+	kopy := *w
+	kopy.Source = inputs[0]
+	kopy.Columns = slices.Clone(w.Columns)
+	kopy.PartitionBy = slices.Clone(w.PartitionBy)
+	kopy.OrderBy = slices.Clone(w.OrderBy)
+	kopy.Functions = slices.Clone(w.Functions)
+	kopy.OrderOffsets = slices.Clone(w.OrderOffsets)
+	kopy.OrderWOffsets = slices.Clone(w.OrderWOffsets)
+	return &kopy
+}
+
+func (w *Window) Inputs() []ops.Operator {
+	return []ops.Operator{w.Source}
+}
+
+func (w *Window) SetInputs(operators []ops.Operator) {
+	if len(operators) != 1 {
+		panic(fmt.Sprintf("unexpected number of operators as input in window: %d", len(operators)))
+	}
+	w.Source = operators[0]
+}
+
+func (w *Window) AddPredicate(_ *plancontext.PlanningContext, expr sqlparser.Expr) ops.Operator {
+	// predicates can't be pushed under the window, since they would change the partitions
+	return &Filter{
+		Source:     w,
+		Predicates: []sqlparser.Expr{expr},
+	}
+}
+
+func (w *Window) AddColumn(ctx *plancontext.PlanningContext, reuse bool, _ bool, ae *sqlparser.AliasedExpr) int {
+	// the window functions have to be pushed to the source before anything else,
+	// so the offsets of the window and of its source stay aligned
+	w.planOffsets(ctx)
+
+	ae = &sqlparser.AliasedExpr{
+		Expr: w.DT.RewriteExpression(ctx, ae.Expr),
+		As:   ae.As,
+	}
+
+	if reuse {
+		offset := w.FindCol(ctx, ae.Expr, false)
+		if offset >= 0 {
+			return offset
+		}
+	}
+
+	if sqlparser.ContainsWindowFunc(ae.Expr) {
+		panic(vterrors.VT12001(fmt.Sprintf("window function not found in the window operator: %s", sqlparser.String(ae))))
+	}
+
+	columns := w.GetColumns(ctx)
+	offset := len(columns)
+	w.Columns = append(w.Columns, ae)
+	incomingOffset := w.Source.AddColumn(ctx, false, false, ae)
+	if offset != incomingOffset {
+		panic(errFailedToPlanWindow(ae))
+	}
+
+	return offset
+}
+
+func (w *Window) FindCol(ctx *plancontext.PlanningContext, in sqlparser.Expr, _ bool) int {
+	expr := w.DT.RewriteExpression(ctx, in)
+	if offset, found := canReuseColumn(ctx, w.Columns, expr, extractExpr); found {
+		return offset
+	}
+	return -1
+}
+
+func (w *Window) GetColumns(ctx *plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	if !w.offsetPlanned {
+		return w.Columns
+	}
+
+	// the window passes through all the columns of its source,
+	// so we pick up any column that was added below us
+	columns := w.Source.GetColumns(ctx)
+	if len(columns) > len(w.Columns) {
+		w.Columns = append(w.Columns, columns[len(w.Columns):]...)
+	}
+
+	return w.Columns
+}
+
+func (w *Window) GetSelectExprs(ctx *plancontext.PlanningContext) sqlparser.SelectExprs {
+	return transformColumnsToSelectExprs(ctx, w)
+}
+
+func (w *Window) ShortDescription() string {
+	funcs := slice.Map(w.Functions, func(from WindowFunction) string {
+		return sqlparser.String(from.Original)
+	})
+	var partitions []string
+	for _, p := range w.PartitionBy {
+		partitions = append(partitions, sqlparser.String(p.Expr))
+	}
+	var orders []string
+	for _, o := range w.OrderBy {
+		orders = append(orders, sqlparser.String(o.Inner))
+	}
+
+	if w.DT != nil {
+		funcs = append([]string{w.DT.String()}, funcs...)
+	}
+
+	desc := strings.Join(funcs, ", ")
+	if len(partitions) > 0 {
+		desc += " partition by " + strings.Join(partitions, ", ")
+	}
+	if len(orders) > 0 {
+		desc += " order by " + strings.Join(orders, ", ")
+	}
+	return desc
+}
+
+func (w *Window) GetOrdering(ctx *plancontext.PlanningContext) []ops.OrderBy {
+	return w.Source.GetOrdering(ctx)
+}
+
+func (w *Window) planOffsets(ctx *plancontext.PlanningContext) {
+	if w.offsetPlanned {
+		return
+	}
+	w.offsetPlanned = true
+
+	for _, wf := range w.Functions {
+		offset := w.Source.AddColumn(ctx, false, false, aeWrap(wf.getPushColumn()))
+		if offset != wf.ColOffset {
+			panic(errFailedToPlanWindow(wf.Original))
+		}
+	}
+
+	for idx, p := range w.PartitionBy {
+		w.PartitionBy[idx].ColOffset = w.addSourceColumn(ctx, p.Expr)
+		if ctx.SemTable.NeedsWeightString(p.Expr) {
+			w.PartitionBy[idx].WSOffset = w.addSourceColumn(ctx, weightStringFor(p.Expr))
+		}
+	}
+
+	for _, order := range w.OrderBy {
+		w.OrderOffsets = append(w.OrderOffsets, w.addSourceColumn(ctx, order.SimplifiedExpr))
+		wsOffset := -1
+		if ctx.SemTable.NeedsWeightString(order.SimplifiedExpr) {
+			wsOffset = w.addSourceColumn(ctx, weightStringFor(order.SimplifiedExpr))
+		}
+		w.OrderWOffsets = append(w.OrderWOffsets, wsOffset)
+	}
+}
+
+// addSourceColumn fetches a column the window needs for its own use from the source
+func (w *Window) addSourceColumn(ctx *plancontext.PlanningContext, expr sqlparser.Expr) int {
+	offset := w.Source.AddColumn(ctx, true, false, aeWrap(expr))
+	w.GetColumns(ctx)
+	return offset
+}
+
+func (w *Window) setTruncateColumnCount(offset int) {
+	w.ResultColumns = offset
+}
+
+func (w *Window) introducesTableID() semantics.TableSet {
+	return w.DT.introducesTableID()
+}
+
+func (wf WindowFunction) getPushColumn() sqlparser.Expr {
+	switch fn := wf.Func.(type) {
+	case *sqlparser.LagLeadExpr:
+		return fn.Expr
+	case sqlparser.AggrFunc:
+		if wf.OpCode != opcode.WindowCountStar {
+			return fn.GetArg()
+		}
+	}
+	return sqlparser.NewIntLiteral("1")
+}
+
+func errFailedToPlanWindow(original *sqlparser.AliasedExpr) *vterrors.VitessError {
+	return vterrors.VT12001(fmt.Sprintf("failed to plan window function on: %s", sqlparser.String(original)))
+}
+
+// createWindow creates the Window operator for the window functions found in the select expressions.
+// All the window functions must use the same partitioning and ordering.
+func createWindow(ctx *plancontext.PlanningContext, sel *sqlparser.Select, src ops.Operator) *Window {
+	w := &Window{}
+	var spec *sqlparser.WindowSpecification
+	for _, se := range sel.SelectExprs {
+		ae, ok := se.(*sqlparser.AliasedExpr)
+		if !ok {
+			continue
+		}
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			if _, isSubq := node.(*sqlparser.Subquery); isSubq {
+				return false, nil
+			}
+			if !sqlparser.IsWindowFunc(node) {
+				return true, nil
+			}
+			fn := node.(sqlparser.WindowFunc)
+			fnSpec, err := resolveWindowSpec(sel, fn.GetOverClause())
+			if err != nil {
+				panic(err)
+			}
+			if spec == nil {
+				spec = fnSpec
+			} else if !sameWindow(ctx, spec, fnSpec) {
+				panic(vterrors.VT12001("window functions using different windows in a cross-shard query"))
+			}
+
+			for _, existing := range w.Functions {
+				if ctx.SemTable.EqualsExprWithDeps(existing.Func, fn) {
+					return false, nil
+				}
+			}
+
+			wf := newWindowFunction(fn, fnSpec)
+			wf.ColOffset = len(w.Columns)
+			wf.Original = ae
+			if ctx.SemTable.EqualsExprWithDeps(ae.Expr, fn) {
+				w.Columns = append(w.Columns, ae)
+			} else {
+				wf.Original = aeWrap(fn)
+				w.Columns = append(w.Columns, wf.Original)
+			}
+			w.Functions = append(w.Functions, wf)
+			return false, nil
+		}, ae.Expr)
+	}
+
+	var order []ops.OrderBy
+	for _, expr := range spec.PartitionClause {
+		w.PartitionBy = append(w.PartitionBy, WindowPartition{Expr: expr, ColOffset: -1, WSOffset: -1})
+		order = append(order, ops.OrderBy{
+			Inner:          &sqlparser.Order{Expr: expr, Direction: sqlparser.AscOrder},
+			SimplifiedExpr: expr,
+		})
+	}
+	for _, by := range spec.OrderClause {
+		orderBy := ops.OrderBy{Inner: by, SimplifiedExpr: by.Expr}
+		w.OrderBy = append(w.OrderBy, orderBy)
+		order = append(order, orderBy)
+	}
+
+	w.Source = src
+	if len(order) > 0 {
+		w.Source = &Ordering{
+			Source: src,
+			Order:  order,
+		}
+	}
+	return w
+}
+
+func newWindowFunction(fn sqlparser.WindowFunc, spec *sqlparser.WindowSpecification) WindowFunction {
+	wf := WindowFunction{Func: fn}
+	switch fn := fn.(type) {
+	case *sqlparser.ArgumentLessWindowExpr:
+		switch fn.Type {
+		case sqlparser.RowNumberExprType:
+			wf.OpCode = opcode.WindowRowNumber
+		case sqlparser.RankExprType:
+			wf.OpCode = opcode.WindowRank
+		case sqlparser.DenseRankExprType:
+			wf.OpCode = opcode.WindowDenseRank
+		}
+	case *sqlparser.LagLeadExpr:
+		wf.OpCode = opcode.WindowLag
+		if fn.Type == sqlparser.LeadExprType {
+			wf.OpCode = opcode.WindowLead
+		}
+		if fn.NullTreatmentClause != nil && fn.NullTreatmentClause.Type == sqlparser.IgnoreNullsType {
+			panic(vterrors.VT12001("IGNORE NULLS in window functions"))
+		}
+		wf.Offset = 1
+		if fn.N != nil {
+			wf.Offset = int64(windowOffsetValue(fn.N))
+		}
+		wf.Default = fn.Default
+		if fn.Default != nil && !sqlparser.IsValue(fn.Default) && !sqlparser.IsNull(fn.Default) {
+			panic(vterrors.VT12001(fmt.Sprintf("non-constant default value in a cross-shard query: %s", sqlparser.String(fn))))
+		}
+	case *sqlparser.CountStar:
+		wf.OpCode = opcode.WindowCountStar
+	case *sqlparser.Count:
+		wf.OpCode = opcode.WindowCount
+		if len(fn.Args) != 1 {
+			panic(vterrors.VT03001(sqlparser.String(fn)))
+		}
+	case *sqlparser.Sum:
+		wf.OpCode = opcode.WindowSum
+	case *sqlparser.Avg:
+		wf.OpCode = opcode.WindowAvg
+	case *sqlparser.Min:
+		wf.OpCode = opcode.WindowMin
+	case *sqlparser.Max:
+		wf.OpCode = opcode.WindowMax
+	}
+	if wf.OpCode == opcode.WindowUnassigned {
+		panic(vterrors.VT12001(fmt.Sprintf("window function in a cross-shard query: %s", sqlparser.String(fn))))
+	}
+	if distinct, ok := fn.(sqlparser.DistinctableAggr); ok && distinct.IsDistinct() {
+		panic(vterrors.VT12001(fmt.Sprintf("DISTINCT in window functions: %s", sqlparser.String(fn))))
+	}
+
+	if wf.OpCode.IsAggregation() && spec.FrameClause != nil {
+		wf.Frame = createWindowFrame(spec.FrameClause)
+	}
+	return wf
+}
+
+func createWindowFrame(fc *sqlparser.FrameClause) *engine.WindowFrame {
+	frame := &engine.WindowFrame{
+		Unit:  fc.Unit,
+		Start: createWindowFrameBound(fc.Unit, fc.Start),
+		End:   engine.WindowFrameBound{Type: sqlparser.CurrentRowType},
+	}
+	if fc.End != nil {
+		frame.End = createWindowFrameBound(fc.Unit, fc.End)
+	}
+	return frame
+}
+
+func createWindowFrameBound(unit sqlparser.FrameUnitType, fp *sqlparser.FramePoint) engine.WindowFrameBound {
+	bound := engine.WindowFrameBound{Type: fp.Type}
+	if fp.Type != sqlparser.ExprPrecedingType && fp.Type != sqlparser.ExprFollowingType {
+		return bound
+	}
+	if unit == sqlparser.FrameRangeType {
+		panic(vterrors.VT12001("RANGE frames with an offset in a cross-shard query"))
+	}
+	bound.N = windowOffsetValue(fp.Expr)
+	return bound
+}
+
+func windowOffsetValue(expr sqlparser.Expr) int {
+	lit, ok := expr.(*sqlparser.Literal)
+	if ok && lit.Type == sqlparser.IntVal {
+		n, err := strconv.Atoi(lit.Val)
+		if err == nil && n >= 0 {
+			return n
+		}
+	}
+	panic(vterrors.VT12001(fmt.Sprintf("window offset that is not a non-negative integer literal: %s", sqlparser.String(expr))))
+}
+
+// resolveWindowSpec returns the window specification used by the OVER clause,
+// with any reference to a named window from the WINDOW clause resolved.
+func resolveWindowSpec(sel *sqlparser.Select, over *sqlparser.OverClause) (*sqlparser.WindowSpecification, error) {
+	if !over.WindowName.IsEmpty() {
+		return findNamedWindow(sel, over.WindowName, 0)
+	}
+	return resolveNamedWindowRefs(sel, over.WindowSpec, 0)
+}
+
+func resolveNamedWindowRefs(sel *sqlparser.Select, spec *sqlparser.WindowSpecification, depth int) (*sqlparser.WindowSpecification, error) {
+	if spec.Name.IsEmpty() {
+		return spec, nil
+	}
+	base, err := findNamedWindow(sel, spec.Name, depth)
+	if err != nil {
+		return nil, err
+	}
+	// a window that refers to a named window inherits its partitioning, and its ordering if it has none of its own
+	res := &sqlparser.WindowSpecification{
+		PartitionClause: base.PartitionClause,
+		OrderClause:     spec.OrderClause,
+		FrameClause:     spec.FrameClause,
+	}
+	if len(res.OrderClause) == 0 {
+		res.OrderClause = base.OrderClause
+	}
+	if res.FrameClause == nil {
+		res.FrameClause = base.FrameClause
+	}
+	return res, nil
+}
+
+// findNamedWindow returns the resolved specification of the named window. depth is the number of
+// window definitions already visited, which can't exceed the number of definitions unless the
+// references have a cycle.
+func findNamedWindow(sel *sqlparser.Select, name sqlparser.IdentifierCI, depth int) (*sqlparser.WindowSpecification, error) {
+	definitions := 0
+	for _, nw := range sel.Windows {
+		definitions += len(nw.Windows)
+	}
+	if depth >= definitions {
+		return nil, vterrors.VT03027(name.String())
+	}
+	for _, nw := range sel.Windows {
+		for _, def := range nw.Windows {
+			if def.Name.Equal(name) {
+				return resolveNamedWindowRefs(sel, def.WindowSpec, depth+1)
+			}
+		}
+	}
+	return nil, vterrors.VT03027(name.String())
+}
+
+func sameWindow(ctx *plancontext.PlanningContext, a, b *sqlparser.WindowSpecification) bool {
+	if len(a.PartitionClause) != len(b.PartitionClause) || len(a.OrderClause) != len(b.OrderClause) {
+		return false
+	}
+	for i, expr := range a.PartitionClause {
+		if !ctx.SemTable.EqualsExprWithDeps(expr, b.PartitionClause[i]) {
+			return false
+		}
+	}
+	for i, order := range a.OrderClause {
+		other := b.OrderClause[i]
+		if order.Direction != other.Direction || !ctx.SemTable.EqualsExprWithDeps(order.Expr, other.Expr) {
+			return false
+		}
+	}
+	return true
+}
+
+// windowsPartitionedByVindex returns true when every window used in the query is partitioned
+// by a column with a unique vindex. All the rows of such partitions live in the same shard,
+// so the window functions can be evaluated by MySQL.
+func windowsPartitionedByVindex(ctx *plancontext.PlanningContext, sel *sqlparser.Select, op ops.Operator) bool {
+	partitioned := true
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if _, isSubq := node.(*sqlparser.Subquery); isSubq {
+			return false, nil
+		}
+		if !sqlparser.IsWindowFunc(node) {
+			return true, nil
+		}
+		spec, err := resolveWindowSpec(sel, node.(sqlparser.WindowFunc).GetOverClause())
+		if err != nil || !partitionedByVindex(ctx, spec, op) {
+			partitioned = false
+			return false, io.EOF
+		}
+		return false, nil
+	}, sel.SelectExprs)
+	return partitioned
+}
+
+func partitionedByVindex(ctx *plancontext.PlanningContext, spec *sqlparser.WindowSpecification, op ops.Operator) bool {
+	for _, expr := range spec.PartitionClause {
+		vindex := findColumnVindex(ctx, op, expr)
+		if vindex != nil && vindex.IsUnique() {
+			return true
+		}
+	}
+	return false
+}
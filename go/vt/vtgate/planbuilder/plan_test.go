@@ -97,6 +97,20 @@ func TestPlan(t *testing.T) {
 	testFile(t, "vexplain_cases.json", testOutputTempDir, vschemaWrapper, false)
 	testFile(t, "misc_cases.json", testOutputTempDir, vschemaWrapper, false)
 	testFile(t, "cte_cases.json", testOutputTempDir, vschemaWrapper, false)
+	testFile(t, "window_cases.json", testOutputTempDir, vschemaWrapper, false)
+}
+
+// TestHashJoinPlanning tests the planning of cross-shard joins when vtgate is allowed to use hash joins.
+func TestHashJoinPlanning(t *testing.T) {
+	vschemaWrapper := &vschemawrapper.VSchemaWrapper{
+		V:           loadSchema(t, "vschemas/schema.json", true),
+		TabletType_: topodatapb.TabletType_PRIMARY,
+		TestBuilder: TestBuilder,
+	}
+
+	testOutputTempDir := makeTestOutput(t)
+
+	testFile(t, "hash_join_cases.json", testOutputTempDir, vschemaWrapper, false)
 }
 
 // TestForeignKeyPlanning tests the planning of foreign keys in a managed mode by Vitess.
@@ -494,6 +508,11 @@ func loadSchema(t testing.TB, filename string, setCollation bool) *vindexes.VSch
 				"select user.id, user_extra.col from user join user_extra on user.id = user_extra.user_id"); err != nil {
 				t.Fatal(err)
 			}
+
+			// adding primary keys, which are normally provided by the schema tracker
+			_ = vschema.AddPrimaryKey(ks.Keyspace.Name, "user", []string{"id"})
+			_ = vschema.AddPrimaryKey(ks.Keyspace.Name, "user_extra", []string{"user_id", "extra_id"})
+			_ = vschema.AddPrimaryKey(ks.Keyspace.Name, "music", []string{"id"})
 		}
 
 		// setting a default value to all the text columns in the tables of this keyspace
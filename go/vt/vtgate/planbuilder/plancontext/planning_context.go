@@ -49,6 +49,11 @@ type PlanningContext struct {
 	// CurrentPhase keeps track of how far we've gone in the planning process
 	// The type should be operators.Phase, but depending on that would lead to circular dependencies
 	CurrentPhase int
+
+	// AllowHashJoin tells whether hash joins can be used when they are estimated to be cheaper than nested loop joins,
+	// and PreferHashJoin whether the query asked to use hash joins whenever possible with the ALLOW_HASH_JOIN directive
+	AllowHashJoin  bool
+	PreferHashJoin bool
 }
 
 func CreatePlanningContext(stmt sqlparser.Statement,
@@ -78,9 +83,19 @@ func CreatePlanningContext(stmt sqlparser.Statement,
 		SkipPredicates:    map[sqlparser.Expr]any{},
 		PlannerVersion:    version,
 		ReservedArguments: map[sqlparser.Expr]string{},
+		AllowHashJoin:     vschema.IsHashJoinEnabled(),
+		PreferHashJoin:    allowHashJoinDirective(stmt),
 	}, nil
 }
 
+func allowHashJoinDirective(stmt sqlparser.Statement) bool {
+	cmt, ok := stmt.(sqlparser.Commented)
+	if !ok {
+		return false
+	}
+	return cmt.GetParsedComments().Directives().IsSet(sqlparser.DirectiveAllowHashJoin)
+}
+
 func (ctx *PlanningContext) GetReservedArgumentFor(expr sqlparser.Expr) string {
 	for key, name := range ctx.ReservedArguments {
 		if ctx.SemTable.EqualsExpr(key, expr) {
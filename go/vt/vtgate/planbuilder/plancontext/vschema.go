@@ -75,6 +75,9 @@ type VSchema interface {
 	// IsViewsEnabled returns true if Vitess manages the views.
 	IsViewsEnabled() bool
 
+	// IsHashJoinEnabled returns true if the planner can choose hash joins over nested loop joins.
+	IsHashJoinEnabled() bool
+
 	// GetUDV returns user defined value from the variable passed.
 	GetUDV(name string) *querypb.BindVariable
 
@@ -19,6 +19,8 @@ package planbuilder
 import (
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 )
 
 var _ logicalPlan = (*semiJoin)(nil)
@@ -36,6 +38,14 @@ type semiJoin struct {
 	// LHSColumns are the columns from the LHS used for the join.
 	// These are the same columns pushed on the LHS that are now used in the vars field
 	LHSColumns []*sqlparser.ColName
+
+	// the fields below are used when the subquery is evaluated for every row of the LHS
+	opcode         opcode.PulloutOpcode
+	subqueryResult string
+	hasValues      string
+	predicate      evalengine.Expr
+	astPredicate   sqlparser.Expr
+	projectResult  bool
 }
 
 // newSemiJoin builds a new semiJoin.
@@ -51,9 +61,15 @@ func newSemiJoin(lhs, rhs logicalPlan, vars map[string]int, lhsCols []*sqlparser
 // Primitive implements the logicalPlan interface
 func (ps *semiJoin) Primitive() engine.Primitive {
 	return &engine.SemiJoin{
-		Left:  ps.lhs.Primitive(),
-		Right: ps.rhs.Primitive(),
-		Vars:  ps.vars,
-		Cols:  ps.cols,
+		Left:           ps.lhs.Primitive(),
+		Right:          ps.rhs.Primitive(),
+		Vars:           ps.vars,
+		Cols:           ps.cols,
+		Opcode:         ps.opcode,
+		SubqueryResult: ps.subqueryResult,
+		HasValues:      ps.hasValues,
+		Predicate:      ps.predicate,
+		ASTPredicate:   ps.astPredicate,
+		ProjectResult:  ps.projectResult,
 	}
 }
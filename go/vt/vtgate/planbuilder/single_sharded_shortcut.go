@@ -42,6 +42,7 @@ func selectUnshardedShortcut(ctx *plancontext.PlanningContext, stmt sqlparser.Se
 		}
 		return true
 	})
+	operators.HoistRecursiveCTEs(ctx.SemTable, stmt)
 
 	tableNames, err := getTableNames(ctx.SemTable)
 	if err != nil {
@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"net/url"
 	"os"
 	"path"
 	"strings"
@@ -229,6 +230,22 @@ func TestSpanContextPassedInEvenAroundOtherComments(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestTraceparentPassedIn(t *testing.T) {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	queries := []string{
+		"/*traceparent='" + traceparent + "'*/SELECT 1",
+		"SELECT 1 /*application='app',traceparent='" + url.QueryEscape(traceparent) + "'*/",
+		"/*VT_SPAN_CONTEXT=" + traceparent + "*/SELECT 1 /*traceparent='00-other-other-01'*/",
+	}
+	for _, query := range queries {
+		_, _, err := startSpanTestable(context.Background(), query, "someLabel", newSpanFail(t), newFromStringExpect(t, traceparent))
+		assert.NoError(t, err)
+	}
+
+	_, _, err := startSpanTestable(context.Background(), "SELECT '/*traceparent=''"+traceparent+"''*/' FROM dual", "someLabel", newSpanOK, newFromStringFail(t))
+	assert.NoError(t, err)
+}
+
 func TestSpanContextNotParsable(t *testing.T) {
 	hasRun := false
 	_, _, err := startSpanTestable(context.Background(), "/*VT_SPAN_CONTEXT=123*/SQL QUERY", "someLabel",
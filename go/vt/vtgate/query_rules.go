@@ -0,0 +1,275 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/callinfo"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/queryrules"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/rules"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+var (
+	queryRuleHits        = stats.NewCountersWithSingleLabel("VtgateQueryRuleHits", "Number of queries matched by each vtgate query rule", "Rule")
+	queryRuleWaitTimings = stats.NewTimings("VtgateQueryRuleWaits", "Time spent waiting for throttling and concurrency limiting vtgate query rules", "Rule")
+)
+
+// QueryRulesTemplate is the status page template for the vtgate query rules.
+const QueryRulesTemplate = `
+<style>
+  table {
+    border-collapse: collapse;
+  }
+  td, th {
+    border: 1px solid #999;
+    padding: 0.2rem;
+  }
+</style>
+<table>
+  <tr>
+    <th>Name</th>
+    <th>Description</th>
+    <th>Rule</th>
+    <th>Hits</th>
+  </tr>
+  {{range .List}}
+  <tr>
+    <td>{{.Name}}</td>
+    <td>{{.Description}}</td>
+    <td><code>{{.String}}</code></td>
+    <td>{{.Hits}}</td>
+  </tr>
+  {{else}}
+  <tr><td colspan="4">No query rules</td></tr>
+  {{end}}
+</table>
+`
+
+// SetQueryRules sets the query rules evaluated against the plan of every query. It fails,
+// keeping the current rules, if a rule has an action vtgate query rules don't support.
+func (e *Executor) SetQueryRules(qrs *queryrules.Rules) error {
+	if err := qrs.Validate(); err != nil {
+		return err
+	}
+	e.queryRules.Store(qrs)
+	return nil
+}
+
+// QueryRules returns the query rules evaluated against the plan of every query.
+func (e *Executor) QueryRules() *queryrules.Rules {
+	return e.queryRules.Load()
+}
+
+// checkQueryRules performs the action of the first query rule matching the query and its plan.
+// It returns the function to call once the query is done.
+func (e *Executor) checkQueryRules(
+	ctx context.Context,
+	vcursor *vcursorImpl,
+	plan *engine.Plan,
+	query string,
+	marginComments sqlparser.MarginComments,
+	bindVars map[string]*querypb.BindVariable,
+) (release func(), err error) {
+	release = func() {}
+	qrs := e.queryRules.Load()
+	if len(qrs.List()) == 0 || plan.Instructions == nil {
+		return release, nil
+	}
+
+	remoteAddr := ""
+	username := ""
+	if ci, ok := callinfo.FromContext(ctx); ok {
+		remoteAddr = ci.RemoteAddr()
+		username = ci.Username()
+	}
+	if callerID := callerid.ImmediateCallerIDFromContext(ctx); callerID != nil && callerID.Username != "" {
+		username = callerID.Username
+	}
+
+	info := queryRulesPlanInfo(ctx, vcursor, plan, bindVars)
+	qr := qrs.GetMatchingRule(query, info, remoteAddr, username, bindVars, marginComments)
+	if qr == nil {
+		return release, nil
+	}
+	queryRuleHits.Add(qr.Name, 1)
+
+	switch qr.Action() {
+	case rules.QRFail:
+		return release, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "disallowed due to rule: %s", qr.Description)
+	case rules.QRFailRetry:
+		return release, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "disallowed due to rule: %s", qr.Description)
+	case rules.QRThrottle:
+		startTime := time.Now()
+		err := qr.Throttle(ctx)
+		queryRuleWaitTimings.Record(qr.Name, startTime)
+		if err != nil {
+			return release, vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "throttled due to rule: %s: %v", qr.Description, err)
+		}
+	case rules.QRLimitConcurrency:
+		startTime := time.Now()
+		slotRelease, err := qr.AcquireConcurrencySlot(ctx)
+		queryRuleWaitTimings.Record(qr.Name, startTime)
+		if err != nil {
+			return release, vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "concurrency limited due to rule: %s: %v", qr.Description, err)
+		}
+		release = slotRelease
+	default:
+		// SetQueryRules rejects the other actions, so this can't happen.
+		return release, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unsupported action of rule %s", qr.Name)
+	}
+	return release, nil
+}
+
+// queryRulesPlanInfo describes the plan to the query rules, lazily. The estimated number of shards
+// is the sum over all routes of the plan, without resolving any lookup vindex.
+func queryRulesPlanInfo(ctx context.Context, vcursor *vcursorImpl, plan *engine.Plan, bindVars map[string]*querypb.BindVariable) *queryrules.LazyPlanInfo {
+	describe := func() *queryrules.PlanInfo {
+		info := &queryrules.PlanInfo{
+			StatementType: plan.Type.String(),
+			Tables:        plan.TablesUsed,
+		}
+		keyspaces := map[string]bool{}
+		visitRoutes(plan.Instructions, func(keyspace string, primitive engine.Primitive) {
+			if !keyspaces[keyspace] {
+				keyspaces[keyspace] = true
+				info.Keyspaces = append(info.Keyspaces, keyspace)
+			}
+			info.Opcodes = append(info.Opcodes, primitive.RouteType())
+		})
+		return info
+	}
+	estimateShards := func() int {
+		shards := 0
+		visitRoutes(plan.Instructions, func(keyspace string, primitive engine.Primitive) {
+			switch primitive := primitive.(type) {
+			case *engine.Route:
+				shards += estimateRouteShards(ctx, vcursor, primitive.RoutingParameters, bindVars)
+			case *engine.Update:
+				shards += estimateRouteShards(ctx, vcursor, primitive.RoutingParameters, bindVars)
+			case *engine.Delete:
+				shards += estimateRouteShards(ctx, vcursor, primitive.RoutingParameters, bindVars)
+			case *engine.Insert:
+				shards += estimateInsertShards(ctx, vcursor, primitive)
+			case *engine.Send:
+				shards += countShards(ctx, vcursor, keyspace, primitive.TargetDestination)
+			}
+		})
+		return shards
+	}
+	return queryrules.NewLazyPlanInfo(describe, estimateShards)
+}
+
+// visitRoutes calls visit with every primitive of the plan that sends queries to the tablets
+// of a keyspace.
+func visitRoutes(primitive engine.Primitive, visit func(keyspace string, primitive engine.Primitive)) {
+	keyspace := ""
+	switch primitive := primitive.(type) {
+	case *engine.Route:
+		keyspace = keyspaceName(primitive.Keyspace)
+	case *engine.Update:
+		keyspace = keyspaceName(primitive.Keyspace)
+	case *engine.Delete:
+		keyspace = keyspaceName(primitive.Keyspace)
+	case *engine.Insert:
+		keyspace = keyspaceName(primitive.Keyspace)
+	case *engine.Send:
+		keyspace = keyspaceName(primitive.Keyspace)
+	}
+	if keyspace != "" {
+		visit(keyspace, primitive)
+	}
+	inputs, _ := primitive.Inputs()
+	for _, input := range inputs {
+		visitRoutes(input, visit)
+	}
+}
+
+func keyspaceName(keyspace *vindexes.Keyspace) string {
+	if keyspace == nil {
+		return ""
+	}
+	return keyspace.Name
+}
+
+func estimateRouteShards(ctx context.Context, vcursor *vcursorImpl, rp *engine.RoutingParameters, bindVars map[string]*querypb.BindVariable) int {
+	if rp.Keyspace == nil {
+		return 0
+	}
+	switch rp.Opcode {
+	case engine.None:
+		return 0
+	case engine.Unsharded, engine.EqualUnique, engine.Equal, engine.Next, engine.DBA, engine.Reference:
+		return 1
+	case engine.IN, engine.MultiEqual:
+		allShards := countShards(ctx, vcursor, rp.Keyspace.Name, key.DestinationAllShards{})
+		if len(rp.Values) == 0 {
+			return allShards
+		}
+		env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+		value, err := env.Evaluate(rp.Values[0])
+		if err != nil {
+			return allShards
+		}
+		return min(len(value.TupleValues()), allShards)
+	case engine.ByDestination:
+		return countShards(ctx, vcursor, rp.Keyspace.Name, rp.TargetDestination)
+	}
+	// Scatter, SubShard
+	return countShards(ctx, vcursor, rp.Keyspace.Name, key.DestinationAllShards{})
+}
+
+func estimateInsertShards(ctx context.Context, vcursor *vcursorImpl, ins *engine.Insert) int {
+	switch ins.Opcode {
+	case engine.InsertUnsharded:
+		return 1
+	case engine.InsertSharded:
+		allShards := countShards(ctx, vcursor, ins.Keyspace.Name, key.DestinationAllShards{})
+		if len(ins.VindexValues) == 0 || len(ins.VindexValues[0]) == 0 {
+			return allShards
+		}
+		// one shard per row at most
+		return min(len(ins.VindexValues[0][0]), allShards)
+	}
+	// InsertSelect
+	return countShards(ctx, vcursor, ins.Keyspace.Name, key.DestinationAllShards{})
+}
+
+// countShards returns the number of shards the destination resolves to in the keyspace, or
+// one if it cannot be resolved.
+func countShards(ctx context.Context, vcursor *vcursorImpl, keyspace string, dest key.Destination) int {
+	if dest == nil {
+		dest = key.DestinationAllShards{}
+	}
+	rss, _, err := vcursor.ResolveDestinations(ctx, keyspace, nil, []key.Destination{dest})
+	if err != nil || len(rss) == 0 {
+		return 1
+	}
+	return len(rss)
+}
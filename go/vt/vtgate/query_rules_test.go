@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/queryrules"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/rules"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestExecutorQueryRules(t *testing.T) {
+	executor, sbc1, _, sbclookup, ctx := createExecutorEnv(t)
+
+	qrs := queryrules.New()
+	require.NoError(t, qrs.UnmarshalJSON([]byte(`[
+		{
+			"Name": "no_scatter_from_batch",
+			"Description": "batch must not scatter",
+			"User": "batch",
+			"Opcodes": ["Scatter"],
+			"Action": "FAIL"
+		},
+		{
+			"Name": "wide_in",
+			"Description": "too many shards",
+			"Keyspaces": ["TestExecutor"],
+			"Opcodes": ["IN"],
+			"MinShards": 3,
+			"Action": "FAIL_RETRY"
+		},
+		{
+			"Name": "no_main1_deletes",
+			"Description": "main1 is append only",
+			"TableNames": ["main1"],
+			"StatementTypes": ["DELETE"],
+			"Action": "FAIL"
+		}
+	]`)))
+	require.NoError(t, executor.SetQueryRules(qrs))
+	defer executor.SetQueryRules(nil)
+
+	batchCtx := callerid.NewContext(ctx, &vtrpcpb.CallerID{}, &querypb.VTGateCallerID{Username: "batch"})
+	appCtx := callerid.NewContext(ctx, &vtrpcpb.CallerID{}, &querypb.VTGateCallerID{Username: "app"})
+
+	testcases := []struct {
+		name string
+		user string
+		sql  string
+		code vtrpcpb.Code
+		err  string
+	}{{
+		name: "scatter from batch",
+		user: "batch",
+		sql:  "select id from `user`",
+		code: vtrpcpb.Code_INVALID_ARGUMENT,
+		err:  "disallowed due to rule: batch must not scatter",
+	}, {
+		name: "scatter from app",
+		user: "app",
+		sql:  "select id from `user`",
+	}, {
+		name: "single shard from batch",
+		user: "batch",
+		sql:  "select id from `user` where id = 1",
+	}, {
+		name: "in on few shards",
+		user: "app",
+		sql:  "select id from `user` where id in (1, 2)",
+	}, {
+		name: "in on many shards",
+		user: "app",
+		sql:  "select id from `user` where id in (1, 2, 3, 4)",
+		code: vtrpcpb.Code_FAILED_PRECONDITION,
+		err:  "disallowed due to rule: too many shards",
+	}, {
+		name: "delete on unsharded table",
+		user: "app",
+		sql:  "delete from main1",
+		code: vtrpcpb.Code_INVALID_ARGUMENT,
+		err:  "disallowed due to rule: main1 is append only",
+	}, {
+		name: "select on unsharded table",
+		user: "app",
+		sql:  "select id from main1",
+	}}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			execCtx := appCtx
+			if tc.user == "batch" {
+				execCtx = batchCtx
+			}
+			sbc1.Queries = nil
+			sbclookup.Queries = nil
+
+			session := &vtgatepb.Session{TargetString: "@primary"}
+			_, err := executorExec(execCtx, executor, session, tc.sql, nil)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.err)
+			assert.Equal(t, tc.code, vterrors.Code(err))
+			// The query must not have been sent to any tablet.
+			assert.Empty(t, sbc1.Queries)
+			assert.Empty(t, sbclookup.Queries)
+		})
+	}
+	assert.EqualValues(t, 1, qrs.Find("no_scatter_from_batch").Hits())
+	assert.EqualValues(t, 1, qrs.Find("wide_in").Hits())
+}
+
+func TestSetQueryRulesRejectsUnsupportedActions(t *testing.T) {
+	executor, _, _, _, _ := createExecutorEnv(t)
+
+	qrs := queryrules.New()
+	require.NoError(t, qrs.UnmarshalJSON([]byte(`[{"Name": "r1", "Action": "FAIL"}]`)))
+	require.NoError(t, executor.SetQueryRules(qrs))
+	defer executor.SetQueryRules(nil)
+
+	hint := queryrules.New()
+	hint.Add(queryrules.NewQueryRule("add a hint", "r2", rules.QRHint))
+	err := executor.SetQueryRules(hint)
+	require.Error(t, err)
+	assert.Equal(t, vtrpcpb.Code_INVALID_ARGUMENT, vterrors.Code(err))
+	// The current rules are kept.
+	assert.Same(t, qrs, executor.queryRules.Load())
+}
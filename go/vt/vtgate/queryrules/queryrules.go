@@ -0,0 +1,590 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package queryrules implements query rules evaluated by vtgate against its query plans.
+
+A vtgate query rule supports the request conditions and actions of vttablet query rules
+(RequestIP, User, LeadingComment, TrailingComment, BindVarConds, and the FAIL, FAIL_RETRY,
+THROTTLE and LIMIT_CONCURRENCY actions), and adds conditions on the vtgate plan: the Query,
+the Keyspaces and TableNames it accesses, the StatementTypes, the route Opcodes, such as
+Scatter, and MinShards, a lower bound on the estimated number of shards the query is sent to.
+Since the rules are evaluated before the query is routed, they can for example block scatter
+queries from a given user before they fan out to all shards:
+
+	[{
+		"Name": "no_scatter_from_batch",
+		"Description": "Batch jobs must not scatter",
+		"User": "batch",
+		"Opcodes": ["Scatter"],
+		"Action": "FAIL"
+	}]
+*/
+package queryrules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/rules"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// PlanInfo describes the vtgate plan of a query, as matched by the rules.
+type PlanInfo struct {
+	// StatementType is the type of the statement, e.g. SELECT.
+	StatementType string
+	// Keyspaces are the keyspaces the query is routed to.
+	Keyspaces []string
+	// Tables are the keyspace qualified tables the query accesses.
+	Tables []string
+	// Opcodes are the route opcodes of the plan, e.g. Scatter or EqualUnique.
+	Opcodes []string
+}
+
+// LazyPlanInfo computes the description of a plan only when a rule needs it. The PlanInfo
+// is only computed for rules with plan conditions, and the estimated number of shards the
+// query is sent to, which resolves the destinations of the plan, only for rules with a
+// MinShards condition. Each is computed at most once.
+type LazyPlanInfo struct {
+	describe       func() *PlanInfo
+	estimateShards func() int
+
+	info            *PlanInfo
+	estimatedShards int
+	shardsEstimated bool
+}
+
+// NewLazyPlanInfo creates a LazyPlanInfo from the functions computing the PlanInfo and the
+// estimated number of shards of a plan.
+func NewLazyPlanInfo(describe func() *PlanInfo, estimateShards func() int) *LazyPlanInfo {
+	return &LazyPlanInfo{describe: describe, estimateShards: estimateShards}
+}
+
+// Info returns the PlanInfo of the plan.
+func (lpi *LazyPlanInfo) Info() *PlanInfo {
+	if lpi.info == nil {
+		lpi.info = lpi.describe()
+	}
+	return lpi.info
+}
+
+// EstimatedShards returns the estimated number of shards the query is sent to.
+func (lpi *LazyPlanInfo) EstimatedShards() int {
+	if !lpi.shardsEstimated {
+		lpi.estimatedShards = lpi.estimateShards()
+		lpi.shardsEstimated = true
+	}
+	return lpi.estimatedShards
+}
+
+// Rules is a list of vtgate query rules.
+type Rules struct {
+	rules []*Rule
+}
+
+// New creates a new Rules.
+func New() *Rules {
+	return &Rules{}
+}
+
+// Add adds a Rule to Rules. It does not check for duplicates.
+func (qrs *Rules) Add(qr *Rule) {
+	qrs.rules = append(qrs.rules, qr)
+}
+
+// Find finds the first occurrence of a Rule by matching
+// the Name field. It returns nil if the rule was not found.
+func (qrs *Rules) Find(name string) *Rule {
+	for _, qr := range qrs.rules {
+		if qr.Name == name {
+			return qr
+		}
+	}
+	return nil
+}
+
+// List returns the rules, in evaluation order.
+func (qrs *Rules) List() []*Rule {
+	if qrs == nil {
+		return nil
+	}
+	return qrs.rules
+}
+
+// Equal returns true if other is equal to this object, otherwise false.
+func (qrs *Rules) Equal(other *Rules) bool {
+	if qrs == nil || other == nil {
+		return qrs == nil && other == nil
+	}
+	if len(qrs.rules) != len(other.rules) {
+		return false
+	}
+	for i := range qrs.rules {
+		if !qrs.rules[i].Equal(other.rules[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// UnmarshalJSON unmarshals Rules.
+func (qrs *Rules) UnmarshalJSON(data []byte) (err error) {
+	var rulesInfo []map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	err = dec.Decode(&rulesInfo)
+	if err != nil {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v", err)
+	}
+	for _, ruleInfo := range rulesInfo {
+		qr, err := BuildQueryRule(ruleInfo)
+		if err != nil {
+			return err
+		}
+		qrs.rules = append(qrs.rules, qr)
+	}
+	return nil
+}
+
+// MarshalJSON marshals to JSON.
+func (qrs *Rules) MarshalJSON() ([]byte, error) {
+	if qrs == nil || len(qrs.rules) == 0 {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(qrs.rules)
+}
+
+// GetMatchingRule runs the query and its plan against the rules, and returns the first rule
+// whose action is not QRContinue, or nil if there is none.
+func (qrs *Rules) GetMatchingRule(
+	query string,
+	info *LazyPlanInfo,
+	ip,
+	user string,
+	bindVars map[string]*querypb.BindVariable,
+	marginComments sqlparser.MarginComments,
+) *Rule {
+	if qrs == nil {
+		return nil
+	}
+	for _, qr := range qrs.rules {
+		if qr.matches(query, info, ip, user, bindVars, marginComments) {
+			qr.hits.Add(1)
+			return qr
+		}
+	}
+	return nil
+}
+
+//-----------------------------------------------
+
+// Rule represents one vtgate query rule (conditions-action).
+// For a Rule to fire, all conditions of the Rule have to match.
+type Rule struct {
+	Name        string
+	Description string
+
+	// cond holds the request conditions and the action, which are shared with vttablet query rules.
+	cond *rules.Rule
+
+	// Plan conditions. Empty conditions are ignored (TRUE).
+	query          *regexp.Regexp
+	keyspaces      []string
+	tableNames     []string
+	statementTypes []string
+	opcodes        []string
+	minShards      int
+
+	// hits counts how many times the rule fired.
+	hits atomic.Int64
+}
+
+// NewQueryRule creates a new Rule.
+func NewQueryRule(description, name string, act rules.Action) *Rule {
+	return &Rule{Description: description, Name: name, cond: rules.NewQueryRule(description, name, act)}
+}
+
+// Cond returns the vttablet query rule holding the request conditions and the action of the rule.
+// It can be used to set those conditions, e.g. SetUserCond or AddBindVarCond.
+func (qr *Rule) Cond() *rules.Rule {
+	return qr.cond
+}
+
+// Action returns the action of the rule.
+func (qr *Rule) Action() rules.Action {
+	return qr.cond.Action()
+}
+
+// Hits returns how many times the rule fired.
+func (qr *Rule) Hits() int64 {
+	return qr.hits.Load()
+}
+
+// Throttle blocks until a THROTTLE rule lets the query through.
+func (qr *Rule) Throttle(ctx context.Context) error {
+	return qr.cond.Throttle(ctx)
+}
+
+// AcquireConcurrencySlot blocks until a LIMIT_CONCURRENCY rule lets the query execute,
+// and returns the function that must be called once the query is done.
+func (qr *Rule) AcquireConcurrencySlot(ctx context.Context) (release func(), err error) {
+	return qr.cond.AcquireConcurrencySlot(ctx)
+}
+
+// SetQueryCond adds a regular expression condition for the query.
+func (qr *Rule) SetQueryCond(pattern string) (err error) {
+	qr.query, err = regexp.Compile(fmt.Sprintf("^%s$", pattern))
+	return err
+}
+
+// AddKeyspaceCond adds to the list of keyspaces that can be matched for the rule to fire.
+// This function acts as an OR: Any keyspace match is considered a match.
+func (qr *Rule) AddKeyspaceCond(keyspace string) {
+	qr.keyspaces = append(qr.keyspaces, keyspace)
+}
+
+// AddTableCond adds to the list of tables that can be matched for the rule to fire.
+// The table name may be qualified by its keyspace.
+// This function acts as an OR: Any table match is considered a match.
+func (qr *Rule) AddTableCond(tableName string) {
+	qr.tableNames = append(qr.tableNames, tableName)
+}
+
+// AddStatementTypeCond adds to the list of statement types, e.g. SELECT, that can be matched
+// for the rule to fire.
+// This function acts as an OR: Any statement type match is considered a match.
+func (qr *Rule) AddStatementTypeCond(statementType string) {
+	qr.statementTypes = append(qr.statementTypes, strings.ToUpper(statementType))
+}
+
+// AddOpcodeCond adds to the list of route opcodes, e.g. Scatter, that can be matched for the
+// rule to fire.
+// This function acts as an OR: Any opcode match is considered a match.
+func (qr *Rule) AddOpcodeCond(opcode string) error {
+	if !validOpcodes[opcode] {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid opcode: %s", opcode)
+	}
+	qr.opcodes = append(qr.opcodes, opcode)
+	return nil
+}
+
+// SetMinShardsCond sets the minimum estimated number of shards the query must be sent to
+// for the rule to fire.
+func (qr *Rule) SetMinShardsCond(minShards int) {
+	qr.minShards = minShards
+}
+
+// Equal returns true if other is equal to this Rule, otherwise false.
+func (qr *Rule) Equal(other *Rule) bool {
+	if qr == nil || other == nil {
+		return qr == nil && other == nil
+	}
+	return qr.Name == other.Name &&
+		qr.Description == other.Description &&
+		qr.cond.Equal(other.cond) &&
+		regexpString(qr.query) == regexpString(other.query) &&
+		stringsEqual(qr.keyspaces, other.keyspaces) &&
+		stringsEqual(qr.tableNames, other.tableNames) &&
+		stringsEqual(qr.statementTypes, other.statementTypes) &&
+		stringsEqual(qr.opcodes, other.opcodes) &&
+		qr.minShards == other.minShards
+}
+
+// MarshalJSON marshals to JSON.
+func (qr *Rule) MarshalJSON() ([]byte, error) {
+	// Start from the vttablet rule, which has the request conditions and the action,
+	// and add the plan conditions to it.
+	condJSON, err := qr.cond.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	ruleInfo := map[string]any{}
+	if err := json.Unmarshal(condJSON, &ruleInfo); err != nil {
+		return nil, err
+	}
+	ruleInfo["Name"] = qr.Name
+	ruleInfo["Description"] = qr.Description
+	if qr.query != nil {
+		ruleInfo["Query"] = strings.TrimSuffix(strings.TrimPrefix(qr.query.String(), "^"), "$")
+	}
+	if qr.keyspaces != nil {
+		ruleInfo["Keyspaces"] = qr.keyspaces
+	}
+	if qr.tableNames != nil {
+		ruleInfo["TableNames"] = qr.tableNames
+	}
+	if qr.statementTypes != nil {
+		ruleInfo["StatementTypes"] = qr.statementTypes
+	}
+	if qr.opcodes != nil {
+		ruleInfo["Opcodes"] = qr.opcodes
+	}
+	if qr.minShards != 0 {
+		ruleInfo["MinShards"] = qr.minShards
+	}
+	return json.Marshal(ruleInfo)
+}
+
+// String returns the JSON representation of the rule.
+func (qr *Rule) String() string {
+	b, err := qr.MarshalJSON()
+	if err != nil {
+		return err.Error()
+	}
+	return string(b)
+}
+
+// matches returns true if the query and its plan match all the conditions of the rule. The
+// request conditions are checked first, so that the plan is only described if they match.
+func (qr *Rule) matches(
+	query string,
+	lazyInfo *LazyPlanInfo,
+	ip,
+	user string,
+	bindVars map[string]*querypb.BindVariable,
+	marginComments sqlparser.MarginComments,
+) bool {
+	if qr.cond.GetAction(ip, user, bindVars, marginComments) == rules.QRContinue {
+		return false
+	}
+	if qr.query != nil && !qr.query.MatchString(query) {
+		return false
+	}
+	if qr.hasPlanConds() {
+		info := lazyInfo.Info()
+		if !anyMatch(qr.keyspaces, info.Keyspaces, strings.EqualFold) {
+			return false
+		}
+		if !anyMatch(qr.tableNames, info.Tables, tableMatch) {
+			return false
+		}
+		if qr.statementTypes != nil && !anyMatch(qr.statementTypes, []string{info.StatementType}, strings.EqualFold) {
+			return false
+		}
+		if !anyMatch(qr.opcodes, info.Opcodes, func(a, b string) bool { return a == b }) {
+			return false
+		}
+	}
+	return qr.minShards == 0 || lazyInfo.EstimatedShards() >= qr.minShards
+}
+
+// hasPlanConds returns true if the rule has conditions on the PlanInfo of the query.
+func (qr *Rule) hasPlanConds() bool {
+	return qr.keyspaces != nil || qr.tableNames != nil || qr.statementTypes != nil || qr.opcodes != nil
+}
+
+// anyMatch returns true if there are no conditions, or if any of the values matches any of the conditions.
+func anyMatch(conds []string, values []string, match func(cond, value string) bool) bool {
+	if conds == nil {
+		return true
+	}
+	for _, cond := range conds {
+		for _, value := range values {
+			if match(cond, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tableMatch matches a table condition, which may or may not be qualified by a keyspace,
+// against a keyspace qualified table.
+func tableMatch(cond, table string) bool {
+	if strings.Contains(cond, ".") {
+		return cond == table
+	}
+	_, name, ok := strings.Cut(table, ".")
+	if !ok {
+		name = table
+	}
+	return cond == name
+}
+
+func regexpString(re *regexp.Regexp) string {
+	if re == nil {
+		return ""
+	}
+	return re.String()
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) || (a == nil) != (b == nil) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// validOpcodes are the route types of the primitives that send queries to tablets.
+var validOpcodes = func() map[string]bool {
+	opcodes := map[string]bool{}
+	for op := engine.Unsharded; op <= engine.ByDestination; op++ {
+		opcodes[op.String()] = true
+	}
+	for _, op := range []engine.InsertOpcode{engine.InsertUnsharded, engine.InsertSharded, engine.InsertSelect} {
+		opcodes[(&engine.Insert{Opcode: op}).RouteType()] = true
+	}
+	opcodes[(&engine.Send{}).RouteType()] = true
+	opcodes[(&engine.Send{IsDML: true}).RouteType()] = true
+	return opcodes
+}()
+
+// tabletRuleTags are the tags of a vttablet query rule that apply to vtgate query rules.
+var tabletRuleTags = map[string]bool{
+	"Name":            true,
+	"Description":     true,
+	"RequestIP":       true,
+	"User":            true,
+	"LeadingComment":  true,
+	"TrailingComment": true,
+	"BindVarConds":    true,
+	"Action":          true,
+	"MaxQPS":          true,
+	"MaxConcurrency":  true,
+	"MaxWait":         true,
+}
+
+// supportedActions are the actions of vttablet query rules that vtgate query rules support.
+// The other actions, such as BUFFER, HINT and REWRITE, only make sense on the tablet.
+var supportedActions = map[rules.Action]bool{
+	rules.QRFail:             true,
+	rules.QRFailRetry:        true,
+	rules.QRThrottle:         true,
+	rules.QRLimitConcurrency: true,
+}
+
+// Validate returns an error if a rule has an action that vtgate query rules don't support.
+// Rules built from JSON are always valid, but rules created with NewQueryRule may not be.
+func (qrs *Rules) Validate() error {
+	for _, qr := range qrs.List() {
+		if !supportedActions[qr.Action()] {
+			action, _ := qr.Action().MarshalJSON()
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Action %s of rule %s is not supported by vtgate query rules", strings.Trim(string(action), `"`), qr.Name)
+		}
+	}
+	return nil
+}
+
+// BuildQueryRule builds a query rule from a ruleInfo.
+func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
+	if action, ok := ruleInfo["Action"]; ok {
+		switch action {
+		case "FAIL", "FAIL_RETRY", "THROTTLE", "LIMIT_CONCURRENCY":
+		default:
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Action %v is not supported by vtgate query rules", action)
+		}
+	}
+	condInfo := map[string]any{}
+	for k, v := range ruleInfo {
+		if tabletRuleTags[k] {
+			condInfo[k] = v
+		}
+	}
+	cond, err := rules.BuildQueryRule(condInfo)
+	if err != nil {
+		return nil, err
+	}
+	qr = &Rule{Name: cond.Name, Description: cond.Description, cond: cond}
+
+	for k, v := range ruleInfo {
+		if tabletRuleTags[k] {
+			continue
+		}
+		var sv string
+		var lv []string
+		switch k {
+		case "Query":
+			var ok bool
+			if sv, ok = v.(string); !ok {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want string for %s", k)
+			}
+		case "Keyspaces", "TableNames", "StatementTypes", "Opcodes":
+			if lv, err = stringList(k, v); err != nil {
+				return nil, err
+			}
+		case "MinShards":
+		default:
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unrecognized tag %s", k)
+		}
+		switch k {
+		case "Query":
+			if err = qr.SetQueryCond(sv); err != nil {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "could not set Query condition: %v", sv)
+			}
+		case "Keyspaces":
+			for _, keyspace := range lv {
+				qr.AddKeyspaceCond(keyspace)
+			}
+		case "TableNames":
+			for _, tableName := range lv {
+				qr.AddTableCond(tableName)
+			}
+		case "StatementTypes":
+			for _, statementType := range lv {
+				qr.AddStatementTypeCond(statementType)
+			}
+		case "Opcodes":
+			for _, opcode := range lv {
+				if err = qr.AddOpcodeCond(opcode); err != nil {
+					return nil, err
+				}
+			}
+		case "MinShards":
+			num, ok := v.(json.Number)
+			if !ok {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want number for MinShards")
+			}
+			minShards, err := num.Int64()
+			if err != nil || minShards <= 0 {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want positive integer for MinShards: %s", num)
+			}
+			qr.SetMinShardsCond(int(minShards))
+		}
+	}
+	return qr, nil
+}
+
+func stringList(tag string, v any) ([]string, error) {
+	lv, ok := v.([]any)
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want list for %s", tag)
+	}
+	strs := make([]string, 0, len(lv))
+	for _, elem := range lv {
+		s, ok := elem.(string)
+		if !ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want string for %s", tag)
+		}
+		strs = append(strs, s)
+	}
+	return strs, nil
+}
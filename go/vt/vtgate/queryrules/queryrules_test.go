@@ -0,0 +1,274 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queryrules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/rules"
+)
+
+const testRules = `[
+	{
+		"Name": "no_scatter_from_batch",
+		"Description": "Batch jobs must not scatter",
+		"User": "batch",
+		"Opcodes": ["Scatter"],
+		"Action": "FAIL"
+	},
+	{
+		"Name": "wide_deletes",
+		"Description": "Deletes on many shards",
+		"StatementTypes": ["delete"],
+		"MinShards": 4,
+		"Action": "FAIL_RETRY"
+	},
+	{
+		"Name": "throttle_orders",
+		"Description": "Throttle queries on orders",
+		"Keyspaces": ["commerce"],
+		"TableNames": ["orders"],
+		"Query": "select .*",
+		"MaxQPS": 10,
+		"Action": "THROTTLE"
+	}
+]`
+
+func TestUnmarshalJSON(t *testing.T) {
+	qrs := New()
+	require.NoError(t, qrs.UnmarshalJSON([]byte(testRules)))
+	require.Len(t, qrs.List(), 3)
+
+	qr := qrs.Find("no_scatter_from_batch")
+	require.NotNil(t, qr)
+	assert.Equal(t, rules.QRFail, qr.Action())
+	assert.Equal(t, []string{"Scatter"}, qr.opcodes)
+	assert.Equal(t, []string{"DELETE"}, qrs.Find("wide_deletes").statementTypes)
+	assert.Equal(t, 4, qrs.Find("wide_deletes").minShards)
+	assert.Nil(t, qrs.Find("unknown"))
+
+	// The rules survive a round trip through JSON.
+	data, err := qrs.MarshalJSON()
+	require.NoError(t, err)
+	other := New()
+	require.NoError(t, other.UnmarshalJSON(data))
+	assert.True(t, qrs.Equal(other), "%s", data)
+
+	other.rules = other.rules[1:]
+	assert.False(t, qrs.Equal(other))
+
+	data, err = New().MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, "[]", string(data))
+}
+
+func TestUnmarshalJSONErrors(t *testing.T) {
+	testcases := []struct {
+		rules string
+		err   string
+	}{{
+		rules: `[{"Name": "r", "Action": "HINT", "Hint": "SET_VAR(sort_buffer_size = 16M)"}]`,
+		err:   "Action HINT is not supported by vtgate query rules",
+	}, {
+		rules: `[{"Name": "r", "Plans": ["Select"]}]`,
+		err:   "unrecognized tag Plans",
+	}, {
+		rules: `[{"Name": "r", "Opcodes": ["Everywhere"]}]`,
+		err:   "invalid opcode: Everywhere",
+	}, {
+		rules: `[{"Name": "r", "MinShards": 0}]`,
+		err:   "MinShards",
+	}, {
+		rules: `[{"Name": "r", "Keyspaces": "commerce"}]`,
+		err:   "Keyspaces",
+	}, {
+		rules: `[{"Name": "r", "Query": "("}]`,
+		err:   "Query",
+	}, {
+		rules: `[{"Name": "r", "Action": "THROTTLE"}]`,
+		err:   "MaxQPS",
+	}}
+	for _, tc := range testcases {
+		t.Run(tc.rules, func(t *testing.T) {
+			err := New().UnmarshalJSON([]byte(tc.rules))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestGetMatchingRule(t *testing.T) {
+	qrs := New()
+	require.NoError(t, qrs.UnmarshalJSON([]byte(testRules)))
+
+	testcases := []struct {
+		name   string
+		query  string
+		info   *PlanInfo
+		shards int
+		user   string
+		want   string
+	}{{
+		name:   "scatter from batch",
+		query:  "select * from users",
+		info:   &PlanInfo{StatementType: "SELECT", Keyspaces: []string{"user"}, Tables: []string{"user.users"}, Opcodes: []string{"Scatter"}},
+		shards: 256,
+		user:   "batch",
+		want:   "no_scatter_from_batch",
+	}, {
+		name:   "scatter from another user",
+		query:  "select * from users",
+		info:   &PlanInfo{StatementType: "SELECT", Keyspaces: []string{"user"}, Tables: []string{"user.users"}, Opcodes: []string{"Scatter"}},
+		shards: 256,
+		user:   "app",
+	}, {
+		name:   "single shard from batch",
+		query:  "select * from users where id = 1",
+		info:   &PlanInfo{StatementType: "SELECT", Keyspaces: []string{"user"}, Tables: []string{"user.users"}, Opcodes: []string{"EqualUnique"}},
+		shards: 1,
+		user:   "batch",
+	}, {
+		name:   "wide delete",
+		query:  "delete from users where name = 'x'",
+		info:   &PlanInfo{StatementType: "DELETE", Keyspaces: []string{"user"}, Tables: []string{"user.users"}, Opcodes: []string{"Scatter"}},
+		shards: 4,
+		user:   "app",
+		want:   "wide_deletes",
+	}, {
+		name:   "narrow delete",
+		query:  "delete from users where id in (1, 2)",
+		info:   &PlanInfo{StatementType: "DELETE", Keyspaces: []string{"user"}, Tables: []string{"user.users"}, Opcodes: []string{"IN"}},
+		shards: 2,
+		user:   "app",
+	}, {
+		name:   "orders",
+		query:  "select * from orders",
+		info:   &PlanInfo{StatementType: "SELECT", Keyspaces: []string{"commerce"}, Tables: []string{"commerce.orders"}, Opcodes: []string{"Unsharded"}},
+		shards: 1,
+		user:   "app",
+		want:   "throttle_orders",
+	}, {
+		name:   "orders in another keyspace",
+		query:  "select * from orders",
+		info:   &PlanInfo{StatementType: "SELECT", Keyspaces: []string{"archive"}, Tables: []string{"archive.orders"}, Opcodes: []string{"Unsharded"}},
+		shards: 1,
+		user:   "app",
+	}, {
+		name:   "insert into orders",
+		query:  "insert into orders values (1)",
+		info:   &PlanInfo{StatementType: "INSERT", Keyspaces: []string{"commerce"}, Tables: []string{"commerce.orders"}, Opcodes: []string{"InsertUnsharded"}},
+		shards: 1,
+		user:   "app",
+	}}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			info := staticPlanInfo(tc.info, tc.shards)
+			qr := qrs.GetMatchingRule(tc.query, info, "", tc.user, nil, sqlparser.MarginComments{})
+			if tc.want == "" {
+				assert.Nil(t, qr)
+				return
+			}
+			require.NotNil(t, qr)
+			assert.Equal(t, tc.want, qr.Name)
+		})
+	}
+	assert.EqualValues(t, 1, qrs.Find("no_scatter_from_batch").Hits())
+	assert.EqualValues(t, 1, qrs.Find("wide_deletes").Hits())
+
+	var nilRules *Rules
+	assert.Nil(t, nilRules.GetMatchingRule("select 1", staticPlanInfo(&PlanInfo{}, 0), "", "", nil, sqlparser.MarginComments{}))
+}
+
+func TestGetMatchingRuleLazyPlanInfo(t *testing.T) {
+	qrs := New()
+	require.NoError(t, qrs.UnmarshalJSON([]byte(`[
+		{"Name": "batch_scatter", "User": "batch", "Opcodes": ["Scatter"], "Action": "FAIL"},
+		{"Name": "wide", "Query": "delete .*", "MinShards": 3, "Action": "FAIL"},
+		{"Name": "reports", "Query": "select .* from reports", "Action": "FAIL"}
+	]`)))
+
+	described, estimated := 0, 0
+	newInfo := func() *LazyPlanInfo {
+		described, estimated = 0, 0
+		return NewLazyPlanInfo(func() *PlanInfo {
+			described++
+			return &PlanInfo{StatementType: "SELECT", Opcodes: []string{"Scatter"}}
+		}, func() int {
+			estimated++
+			return 4
+		})
+	}
+
+	// None of the rules with plan conditions matches the user or the query.
+	assert.Nil(t, qrs.GetMatchingRule("select * from users", newInfo(), "", "app", nil, sqlparser.MarginComments{}))
+	assert.Zero(t, described)
+	assert.Zero(t, estimated)
+
+	// A rule without plan conditions doesn't need the plan.
+	qr := qrs.GetMatchingRule("select * from reports", newInfo(), "", "app", nil, sqlparser.MarginComments{})
+	require.NotNil(t, qr)
+	assert.Equal(t, "reports", qr.Name)
+	assert.Zero(t, described)
+	assert.Zero(t, estimated)
+
+	// The plan is described for the opcodes, but its shards are only estimated for MinShards.
+	qr = qrs.GetMatchingRule("select * from users", newInfo(), "", "batch", nil, sqlparser.MarginComments{})
+	require.NotNil(t, qr)
+	assert.Equal(t, "batch_scatter", qr.Name)
+	assert.Equal(t, 1, described)
+	assert.Zero(t, estimated)
+
+	qr = qrs.GetMatchingRule("delete from users", newInfo(), "", "app", nil, sqlparser.MarginComments{})
+	require.NotNil(t, qr)
+	assert.Equal(t, "wide", qr.Name)
+	assert.Zero(t, described)
+	assert.Equal(t, 1, estimated)
+}
+
+func TestValidate(t *testing.T) {
+	qrs := New()
+	require.NoError(t, qrs.UnmarshalJSON([]byte(testRules)))
+	require.NoError(t, qrs.Validate())
+
+	var nilRules *Rules
+	require.NoError(t, nilRules.Validate())
+
+	for _, action := range []rules.Action{rules.QRBuffer, rules.QRHint, rules.QRRewrite} {
+		qrs := New()
+		qrs.Add(NewQueryRule("unsupported", "r", action))
+		err := qrs.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not supported by vtgate query rules")
+	}
+}
+
+// staticPlanInfo returns a LazyPlanInfo for an already computed description of a plan.
+func staticPlanInfo(info *PlanInfo, shards int) *LazyPlanInfo {
+	return NewLazyPlanInfo(func() *PlanInfo { return info }, func() int { return shards })
+}
+
+func TestTableMatch(t *testing.T) {
+	assert.True(t, tableMatch("orders", "commerce.orders"))
+	assert.True(t, tableMatch("commerce.orders", "commerce.orders"))
+	assert.False(t, tableMatch("archive.orders", "commerce.orders"))
+	assert.False(t, tableMatch("order", "commerce.orders"))
+	assert.True(t, tableMatch("orders", "orders"))
+}
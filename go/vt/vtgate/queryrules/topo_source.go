@@ -0,0 +1,175 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queryrules
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// sleepDuringTopoFailure is how long to sleep before retrying in case of error.
+// (it's a var not a const so the test can change the value).
+var sleepDuringTopoFailure = 30 * time.Second
+
+// TopoSource watches a file in the topo service holding vtgate query rules in JSON,
+// and applies them every time the file changes.
+type TopoSource struct {
+	// conn is the topo connection. Set at construction time.
+	conn topo.Conn
+
+	// filePath is the file to read from.
+	filePath string
+
+	// apply is called with every new set of rules.
+	apply func(*Rules) error
+
+	// qrs is the current rule set that we read.
+	qrs *Rules
+
+	// mu protects the following variables.
+	mu sync.Mutex
+
+	// cancel is the function to call to cancel the current watch, if any.
+	cancel func()
+
+	// stopped is set when Stop() is called. It is a protection for race conditions.
+	stopped bool
+}
+
+// NewTopoSource creates a TopoSource watching the given file in the given cell.
+// apply is called with the rules every time they change.
+func NewTopoSource(ts *topo.Server, cell, filePath string, apply func(*Rules) error) (*TopoSource, error) {
+	conn, err := ts.ConnForCell(context.Background(), cell)
+	if err != nil {
+		return nil, err
+	}
+	return &TopoSource{
+		conn:     conn,
+		filePath: filePath,
+		apply:    apply,
+	}, nil
+}
+
+// Start starts watching the rules in the background.
+func (src *TopoSource) Start() {
+	go func() {
+		for {
+			if err := src.oneWatch(); err != nil {
+				log.Warningf("Background watch of vtgate query rules failed: %v", err)
+			}
+
+			src.mu.Lock()
+			stopped := src.stopped
+			src.mu.Unlock()
+
+			if stopped {
+				log.Warningf("Watch of vtgate query rules was terminated")
+				return
+			}
+
+			log.Warningf("Sleeping for %v before trying again", sleepDuringTopoFailure)
+			time.Sleep(sleepDuringTopoFailure)
+		}
+	}()
+}
+
+// Stop stops watching the rules.
+func (src *TopoSource) Stop() {
+	src.mu.Lock()
+	if src.cancel != nil {
+		src.cancel()
+	}
+	src.stopped = true
+	src.mu.Unlock()
+}
+
+func (src *TopoSource) update(wd *topo.WatchData) error {
+	qrs := New()
+	if err := qrs.UnmarshalJSON(wd.Contents); err != nil {
+		return fmt.Errorf("error unmarshaling vtgate query rules: %v, original data '%s' version %v", err, wd.Contents, wd.Version)
+	}
+
+	if !src.qrs.Equal(qrs) {
+		if err := src.apply(qrs); err != nil {
+			return fmt.Errorf("error applying vtgate query rules: %v, original data '%s' version %v", err, wd.Contents, wd.Version)
+		}
+		src.qrs = qrs
+		log.Infof("Query rules version %v fetched from topo and applied to vtgate", wd.Version)
+	}
+
+	return nil
+}
+
+func (src *TopoSource) oneWatch() error {
+	defer func() {
+		// Whatever happens, cancel() won't be valid after this function exits.
+		src.mu.Lock()
+		src.cancel = nil
+		src.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	current, wdChannel, err := src.conn.Watch(ctx, src.filePath)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	src.mu.Lock()
+	if src.stopped {
+		// We're not interested in the result any more.
+		src.mu.Unlock()
+		cancel()
+		for range wdChannel {
+		}
+		return topo.NewError(topo.Interrupted, "watch")
+	}
+	src.cancel = cancel
+	src.mu.Unlock()
+
+	if err := src.update(current); err != nil {
+		// Cancel the watch, drain channel.
+		cancel()
+		for range wdChannel {
+		}
+		return err
+	}
+
+	for wd := range wdChannel {
+		if wd.Err != nil {
+			// Last error value, we're done.
+			// wdChannel will be closed right after
+			// this, no need to do anything.
+			return wd.Err
+		}
+
+		if err := src.update(wd); err != nil {
+			// Cancel the watch, drain channel.
+			cancel()
+			for range wdChannel {
+			}
+			return err
+		}
+	}
+
+	return fmt.Errorf("watch terminated with no error")
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queryrules
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+const (
+	topoRules1 = `[{"Name": "r1", "Description": "no scatter", "Opcodes": ["Scatter"]}]`
+	topoRules2 = `[{"Name": "r2", "Description": "no deletes", "StatementTypes": ["DELETE"]}]`
+)
+
+func TestTopoSource(t *testing.T) {
+	rules1 := New()
+	require.NoError(t, rules1.UnmarshalJSON([]byte(topoRules1)))
+	rules2 := New()
+	require.NoError(t, rules2.UnmarshalJSON([]byte(topoRules2)))
+
+	cell := "cell1"
+	filePath := "/vtgate/QueryRules"
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, cell)
+	sleepDuringTopoFailure = time.Millisecond
+
+	var current atomic.Pointer[Rules]
+	src, err := NewTopoSource(ts, cell, filePath, func(qrs *Rules) error {
+		current.Store(qrs)
+		return nil
+	})
+	require.NoError(t, err)
+	src.Start()
+	defer src.Stop()
+
+	waitForRules := func(expected *Rules) {
+		require.Eventually(t, func() bool {
+			return current.Load().Equal(expected)
+		}, 10*time.Second, 10*time.Millisecond)
+	}
+
+	// Set a value, wait until we get it.
+	conn, err := ts.ConnForCell(ctx, cell)
+	require.NoError(t, err)
+	_, err = conn.Create(ctx, filePath, []byte(topoRules1))
+	require.NoError(t, err)
+	waitForRules(rules1)
+
+	// Update the value, wait until we get it.
+	_, err = conn.Update(ctx, filePath, []byte(topoRules2), nil)
+	require.NoError(t, err)
+	waitForRules(rules2)
+}
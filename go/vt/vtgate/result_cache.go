@@ -0,0 +1,380 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/logstats"
+	"vitess.io/vitess/go/vt/vtgate/resultcache"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vthash"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+var (
+	resultCacheHits          = stats.NewCounter("ResultCacheHits", "Number of queries served from the result cache")
+	resultCacheMisses        = stats.NewCounter("ResultCacheMisses", "Number of cacheable queries not found in the result cache")
+	resultCacheInvalidations = stats.NewCountersWithSingleLabel("ResultCacheInvalidations", "Number of row events invalidating the result cache, by table", "Table")
+
+	resultCacheStatsOnce sync.Once
+
+	// resultCacheStreamRetryDelay is the time to wait before restarting a failed invalidation stream.
+	resultCacheStreamRetryDelay = 5 * time.Second
+)
+
+// vstreamFunc is the signature of vstreamManager.VStream.
+type vstreamFunc func(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid,
+	filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func(events []*binlogdatapb.VEvent) error) error
+
+// enableResultCache makes the executor cache the results of the queries that opt in, and
+// invalidate them with the row events streamed by vstream.
+func (e *Executor) enableResultCache(cache *resultcache.Cache, vstream vstreamFunc) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.resultCache = cache
+	e.resultCacheInvalidator = newResultCacheInvalidator(cache, vstream)
+	if e.vschema != nil {
+		e.resultCacheInvalidator.update(e.vschema)
+	}
+
+	resultCacheStatsOnce.Do(func() {
+		stats.NewGaugeFunc("ResultCacheLength", "Result cache length", func() int64 {
+			return int64(e.resultCache.Len())
+		})
+		stats.NewGaugeFunc("ResultCacheSize", "Result cache size", func() int64 {
+			return int64(e.resultCache.UsedCapacity())
+		})
+		stats.NewGaugeFunc("ResultCacheCapacity", "Result cache capacity", func() int64 {
+			return int64(e.resultCache.MaxCapacity())
+		})
+		stats.NewCounterFunc("ResultCacheEvictions", "Result cache evictions", func() int64 {
+			return e.resultCache.Evictions()
+		})
+	})
+}
+
+// executePlanWithResultCache executes the plan, or serves its result from the result cache
+// if the query opted in and its result is cached.
+func (e *Executor) executePlanWithResultCache(
+	ctx context.Context,
+	safeSession *SafeSession,
+	plan *engine.Plan,
+	vcursor *vcursorImpl,
+	bindVars map[string]*querypb.BindVariable,
+	logStats *logstats.LogStats,
+	execStart time.Time,
+) (*sqltypes.Result, error) {
+	ttl := e.resultCacheTTL(safeSession, plan, vcursor)
+	if ttl == 0 {
+		return e.executePlan(ctx, safeSession, plan, vcursor, bindVars, logStats, execStart)
+	}
+
+	key := resultCacheKey(ctx, vcursor, plan, bindVars)
+	if qr, ok := e.resultCache.Get(key); ok {
+		resultCacheHits.Add(1)
+		e.setLogStats(logStats, plan, vcursor, execStart, nil, qr)
+		return qr, nil
+	}
+	resultCacheMisses.Add(1)
+
+	snapshot := e.resultCache.Snapshot(plan.TablesUsed)
+	qr, err := e.executePlan(ctx, safeSession, plan, vcursor, bindVars, logStats, execStart)
+	// Do not cache partial results, e.g. of scatter queries with errors as warnings.
+	if err == nil && len(safeSession.GetWarnings()) == 0 {
+		e.resultCache.Set(key, snapshot, qr, ttl)
+	}
+	return qr, err
+}
+
+// resultCacheTTL returns how long the result of the plan can be cached, or zero if it cannot be.
+// A query opts in with the RESULT_CACHE directive, or by only reading tables that have the result
+// cache enabled in the VSchema.
+func (e *Executor) resultCacheTTL(safeSession *SafeSession, plan *engine.Plan, vcursor *vcursorImpl) time.Duration {
+	if e.resultCache == nil || plan.Type != sqlparser.StmtSelect || len(plan.TablesUsed) == 0 {
+		return 0
+	}
+	// Transactions and reserved connections can see changes that are not committed yet.
+	if safeSession.InTransaction() || safeSession.InReservedConn() {
+		return 0
+	}
+	// Not even the directive or the VSchema can make these results cacheable.
+	if vcursor.resultUncacheable {
+		return 0
+	}
+	if vcursor.resultCacheSet {
+		if !vcursor.resultCache {
+			return 0
+		}
+	} else if !resultCacheEnabled(vcursor.vschema, plan.TablesUsed) {
+		return 0
+	}
+	if vcursor.resultCacheTTL > 0 {
+		return vcursor.resultCacheTTL
+	}
+	return resultCacheTTL
+}
+
+// uncacheableFuncs are the functions whose result depends on the session, on the time of the
+// query, or on nothing at all. The current time functions are parsed as CurTimeFuncExpr.
+var uncacheableFuncs = map[string]bool{
+	"rand":           true,
+	"uuid":           true,
+	"uuid_short":     true,
+	"last_insert_id": true,
+	"found_rows":     true,
+	"row_count":      true,
+	"connection_id":  true,
+	"database":       true,
+	"schema":         true,
+	"user":           true,
+	"current_user":   true,
+	"session_user":   true,
+	"system_user":    true,
+	"curdate":        true,
+	"current_date":   true,
+	"curtime":        true,
+	"current_time":   true,
+	"unix_timestamp": true,
+	"sleep":          true,
+	"get_lock":       true,
+	"release_lock":   true,
+	"is_free_lock":   true,
+	"is_used_lock":   true,
+}
+
+// resultUncacheable returns true if the result of the statement must never be cached: if it
+// uses non-deterministic or session dependent functions, user defined or system variables,
+// or if it is a locking read.
+func resultUncacheable(stmt sqlparser.Statement) bool {
+	uncacheable := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.Select:
+			uncacheable = uncacheable || node.Lock != sqlparser.NoLock
+		case *sqlparser.Variable, *sqlparser.CurTimeFuncExpr:
+			uncacheable = true
+		case *sqlparser.FuncExpr:
+			uncacheable = uncacheable || uncacheableFuncs[node.Name.Lowered()]
+		}
+		return !uncacheable, nil
+	}, stmt)
+	return uncacheable
+}
+
+// resultCacheEnabled returns true if all the keyspace qualified tables have the result cache
+// enabled in the VSchema.
+func resultCacheEnabled(vschema *vindexes.VSchema, tables []string) bool {
+	if vschema == nil {
+		return false
+	}
+	for _, table := range tables {
+		keyspace, name, _ := strings.Cut(table, ".")
+		ks := vschema.Keyspaces[keyspace]
+		if ks == nil || ks.Tables[name] == nil || !ks.Tables[name].ResultCache {
+			return false
+		}
+	}
+	return true
+}
+
+// resultCacheKey hashes everything the result of the plan depends on.
+func resultCacheKey(ctx context.Context, vcursor *vcursorImpl, plan *engine.Plan, bindVars map[string]*querypb.BindVariable) resultcache.Key {
+	hasher := vthash.New256()
+	// Cached results are only served to the user who read them, as users can have
+	// different table ACLs in the tablets.
+	_, _ = hasher.WriteString(callerid.ImmediateCallerIDFromContext(ctx).GetUsername())
+	_, _ = hasher.WriteString("+")
+	vcursor.keyForPlan(ctx, plan.Original, hasher)
+
+	// The session system variables, e.g. sql_mode or time_zone, can change the result.
+	var sysVars []string
+	vcursor.safeSession.GetSystemVariables(func(name, value string) {
+		sysVars = append(sysVars, name+"="+value)
+	})
+	sort.Strings(sysVars)
+	for _, sysVar := range sysVars {
+		_, _ = hasher.WriteString("+SysVar:")
+		_, _ = hasher.WriteString(strconv.Itoa(len(sysVar)))
+		_, _ = hasher.WriteString(sysVar)
+	}
+
+	names := make([]string, 0, len(bindVars))
+	for name := range bindVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		bv, _ := bindVars[name].MarshalVT()
+		_, _ = hasher.WriteString("+")
+		_, _ = hasher.WriteString(name)
+		_, _ = hasher.WriteString(":")
+		_, _ = hasher.WriteString(strconv.Itoa(len(bv)))
+		_, _ = hasher.Write(bv)
+	}
+
+	var key resultcache.Key
+	hasher.Sum(key[:0])
+	return key
+}
+
+// resultCacheInvalidator invalidates the result cache with the row events of the tables that have
+// the result cache enabled in the VSchema. It runs one VStream per keyspace, from the primaries.
+type resultCacheInvalidator struct {
+	cache   *resultcache.Cache
+	vstream vstreamFunc
+
+	mu      sync.Mutex
+	streams map[string]*resultCacheStream
+}
+
+type resultCacheStream struct {
+	tables []string
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newResultCacheInvalidator(cache *resultcache.Cache, vstream vstreamFunc) *resultCacheInvalidator {
+	return &resultCacheInvalidator{
+		cache:   cache,
+		vstream: vstream,
+		streams: make(map[string]*resultCacheStream),
+	}
+}
+
+// update starts and stops the streams to follow the tables that have the result cache enabled in the VSchema.
+func (inv *resultCacheInvalidator) update(vschema *vindexes.VSchema) {
+	tablesByKeyspace := make(map[string][]string)
+	for keyspace, ks := range vschema.Keyspaces {
+		for name, table := range ks.Tables {
+			if table.ResultCache {
+				tablesByKeyspace[keyspace] = append(tablesByKeyspace[keyspace], name)
+			}
+		}
+	}
+
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	for keyspace, stream := range inv.streams {
+		tables := tablesByKeyspace[keyspace]
+		sort.Strings(tables)
+		if !slices.Equal(stream.tables, tables) {
+			stream.cancel()
+			delete(inv.streams, keyspace)
+		}
+	}
+	for keyspace, tables := range tablesByKeyspace {
+		if _, ok := inv.streams[keyspace]; ok {
+			continue
+		}
+		sort.Strings(tables)
+		ctx, cancel := context.WithCancel(context.Background())
+		stream := &resultCacheStream{
+			tables: tables,
+			cancel: cancel,
+			done:   make(chan struct{}),
+		}
+		inv.streams[keyspace] = stream
+		go func(keyspace string) {
+			defer close(stream.done)
+			inv.run(ctx, keyspace, stream.tables)
+		}(keyspace)
+	}
+}
+
+// close stops all the streams.
+func (inv *resultCacheInvalidator) close() {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	for keyspace, stream := range inv.streams {
+		stream.cancel()
+		<-stream.done
+		delete(inv.streams, keyspace)
+	}
+}
+
+// run streams the row events of the tables of the keyspace until the context is done.
+func (inv *resultCacheInvalidator) run(ctx context.Context, keyspace string, tables []string) {
+	qualified := make([]string, len(tables))
+	patterns := make([]string, len(tables))
+	for i, table := range tables {
+		qualified[i] = keyspace + "." + table
+		patterns[i] = regexp.QuoteMeta(table)
+	}
+	vgtid := &binlogdatapb.VGtid{
+		ShardGtids: []*binlogdatapb.ShardGtid{{
+			Keyspace: keyspace,
+			Gtid:     "current",
+		}},
+	}
+	filter := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match: "/^(" + strings.Join(patterns, "|") + ")$",
+		}},
+	}
+
+	for {
+		positioned := false
+		err := inv.vstream(ctx, topodatapb.TabletType_PRIMARY, vgtid, filter, nil, func(events []*binlogdatapb.VEvent) error {
+			for _, event := range events {
+				switch event.Type {
+				case binlogdatapb.VEventType_VGTID:
+					// Results cached before the stream was positioned may have missed row events.
+					if !positioned {
+						positioned = true
+						inv.cache.Invalidate(qualified...)
+					}
+				case binlogdatapb.VEventType_ROW:
+					// The vstream manager qualifies the table name with the keyspace.
+					inv.cache.Invalidate(event.RowEvent.TableName)
+					resultCacheInvalidations.Add(event.RowEvent.TableName, 1)
+				case binlogdatapb.VEventType_DDL:
+					inv.cache.Invalidate(qualified...)
+				}
+			}
+			return nil
+		})
+		// Row events may be missed until the stream is restarted.
+		inv.cache.Invalidate(qualified...)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warningf("Result cache invalidation stream for keyspace %s ended, restarting in %v: %v", keyspace, resultCacheStreamRetryDelay, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resultCacheStreamRetryDelay):
+		}
+	}
+}
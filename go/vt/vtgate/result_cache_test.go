@@ -0,0 +1,220 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/vtgate/resultcache"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// fakeResultCacheVStream hands the send function of every stream to the test.
+func fakeResultCacheVStream(t *testing.T, sends chan<- func([]*binlogdatapb.VEvent) error) vstreamFunc {
+	return func(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid,
+		filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func(events []*binlogdatapb.VEvent) error) error {
+		assert.Equal(t, topodatapb.TabletType_PRIMARY, tabletType)
+		assert.Equal(t, KsTestUnsharded, vgtid.ShardGtids[0].Keyspace)
+		assert.Equal(t, "/^(main1)$", filter.Rules[0].Match)
+		sends <- send
+		<-ctx.Done()
+		return ctx.Err()
+	}
+}
+
+func TestExecutorResultCache(t *testing.T) {
+	executor, sbc1, _, sbclookup, ctx := createExecutorEnv(t)
+	executor.VSchema().Keyspaces[KsTestUnsharded].Tables["main1"].ResultCache = true
+	sends := make(chan func([]*binlogdatapb.VEvent) error, 1)
+	executor.enableResultCache(resultcache.New(1024*1024), fakeResultCacheVStream(t, sends))
+
+	var send func([]*binlogdatapb.VEvent) error
+	select {
+	case send = <-sends:
+	case <-time.After(10 * time.Second):
+		t.Fatal("invalidation stream not started")
+	}
+	require.NoError(t, send([]*binlogdatapb.VEvent{{Type: binlogdatapb.VEventType_VGTID}}))
+
+	session := &vtgatepb.Session{TargetString: "@primary", Autocommit: true}
+	exec := func(ctx context.Context, sql string) {
+		t.Helper()
+		_, err := executorExec(ctx, executor, session, sql, nil)
+		require.NoError(t, err)
+	}
+
+	// main1 has the result cache enabled in the VSchema.
+	exec(ctx, "select id from main1 where id = 1")
+	exec(ctx, "select id from main1 where id = 1")
+	assert.EqualValues(t, 1, sbclookup.ExecCount.Load())
+
+	// Results are cached per bind variable values.
+	exec(ctx, "select id from main1 where id = 2")
+	assert.EqualValues(t, 2, sbclookup.ExecCount.Load())
+
+	// and per user.
+	otherCtx := callerid.NewContext(ctx, &vtrpcpb.CallerID{}, &querypb.VTGateCallerID{Username: "other"})
+	exec(otherCtx, "select id from main1 where id = 1")
+	assert.EqualValues(t, 3, sbclookup.ExecCount.Load())
+
+	// A row event invalidates the results of the table.
+	require.NoError(t, send([]*binlogdatapb.VEvent{{
+		Type:     binlogdatapb.VEventType_ROW,
+		RowEvent: &binlogdatapb.RowEvent{TableName: KsTestUnsharded + ".main1"},
+	}}))
+	exec(ctx, "select id from main1 where id = 1")
+	exec(ctx, "select id from main1 where id = 1")
+	assert.EqualValues(t, 4, sbclookup.ExecCount.Load())
+
+	// The directive can opt out of the result cache.
+	exec(ctx, "select /*vt+ RESULT_CACHE=false */ id from main1 where id = 1")
+	assert.EqualValues(t, 5, sbclookup.ExecCount.Load())
+
+	// Transactions do not use the result cache.
+	exec(ctx, "begin")
+	exec(ctx, "select id from main1 where id = 1")
+	exec(ctx, "rollback")
+	assert.EqualValues(t, 6, sbclookup.ExecCount.Load())
+
+	// Tables without the result cache enabled opt in with the directive.
+	exec(ctx, "select id from `user` where id = 1")
+	exec(ctx, "select id from `user` where id = 1")
+	assert.EqualValues(t, 2, sbc1.ExecCount.Load())
+	exec(ctx, "select /*vt+ RESULT_CACHE */ id from `user` where id = 1")
+	exec(ctx, "select /*vt+ RESULT_CACHE */ id from `user` where id = 1")
+	assert.EqualValues(t, 3, sbc1.ExecCount.Load())
+
+	// Results expire after their time to live.
+	exec(ctx, "select /*vt+ RESULT_CACHE RESULT_CACHE_TTL_MS=1 */ id from `user` where id = 1")
+	time.Sleep(5 * time.Millisecond)
+	exec(ctx, "select /*vt+ RESULT_CACHE RESULT_CACHE_TTL_MS=1 */ id from `user` where id = 1")
+	assert.EqualValues(t, 5, sbc1.ExecCount.Load())
+
+	// DML is never cached.
+	exec(ctx, "update main1 set id = 1 where id = 1")
+	exec(ctx, "update main1 set id = 1 where id = 1")
+	assert.EqualValues(t, 8, sbclookup.ExecCount.Load())
+}
+
+func TestResultCacheInvalidatorUpdate(t *testing.T) {
+	executor, _, _, _, _ := createExecutorEnv(t)
+	sends := make(chan func([]*binlogdatapb.VEvent) error, 1)
+	executor.enableResultCache(resultcache.New(1024*1024), fakeResultCacheVStream(t, sends))
+
+	inv := executor.resultCacheInvalidator
+	inv.mu.Lock()
+	assert.Empty(t, inv.streams)
+	inv.mu.Unlock()
+
+	// Enabling the result cache on a table in the VSchema starts the stream of its keyspace.
+	vschema := executor.VSchema()
+	vschema.Keyspaces[KsTestUnsharded].Tables["main1"].ResultCache = true
+	executor.SaveVSchema(vschema, &VSchemaStats{})
+	select {
+	case <-sends:
+	case <-time.After(10 * time.Second):
+		t.Fatal("invalidation stream not started")
+	}
+	inv.mu.Lock()
+	require.Contains(t, inv.streams, KsTestUnsharded)
+	assert.Equal(t, []string{"main1"}, inv.streams[KsTestUnsharded].tables)
+	stream := inv.streams[KsTestUnsharded]
+	inv.mu.Unlock()
+
+	// and disabling it stops the stream.
+	vschema.Keyspaces[KsTestUnsharded].Tables["main1"].ResultCache = false
+	executor.SaveVSchema(vschema, &VSchemaStats{})
+	select {
+	case <-stream.done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("invalidation stream not stopped")
+	}
+	inv.mu.Lock()
+	assert.Empty(t, inv.streams)
+	inv.mu.Unlock()
+}
+
+func TestExecutorResultCacheUncacheable(t *testing.T) {
+	executor, sbc1, _, sbclookup, ctx := createExecutorEnv(t)
+	executor.VSchema().Keyspaces[KsTestUnsharded].Tables["main1"].ResultCache = true
+	sends := make(chan func([]*binlogdatapb.VEvent) error, 1)
+	executor.enableResultCache(resultcache.New(1024*1024), fakeResultCacheVStream(t, sends))
+
+	session := &vtgatepb.Session{TargetString: "@primary", Autocommit: true}
+	testcases := []string{
+		"select now() from main1",
+		"select id from main1 where id = rand()",
+		"select uuid() from main1",
+		"select last_insert_id() from main1",
+		"select id from main1 where id = @id",
+		"select @@time_zone from main1",
+		"select id from main1 where id = 1 union select id from main1 where id = database()",
+		"select id from main1 where id = 1 for update",
+		"select id from main1 where id = 1 lock in share mode",
+	}
+	for _, sql := range testcases {
+		t.Run(sql, func(t *testing.T) {
+			// Neither the VSchema nor the directive make the result cacheable.
+			for _, query := range []string{sql, "select /*vt+ RESULT_CACHE */" + sql[len("select"):]} {
+				count := sbclookup.ExecCount.Load()
+				for i := 0; i < 2; i++ {
+					_, err := executorExec(ctx, executor, session, query, nil)
+					require.NoError(t, err)
+				}
+				assert.EqualValues(t, count+2, sbclookup.ExecCount.Load(), query)
+			}
+		})
+	}
+	assert.Zero(t, sbc1.ExecCount.Load())
+}
+
+func TestExecutorResultCacheSystemVariables(t *testing.T) {
+	executor, _, _, sbclookup, ctx := createExecutorEnv(t)
+	executor.VSchema().Keyspaces[KsTestUnsharded].Tables["main1"].ResultCache = true
+	sends := make(chan func([]*binlogdatapb.VEvent) error, 1)
+	executor.enableResultCache(resultcache.New(1024*1024), fakeResultCacheVStream(t, sends))
+
+	exec := func(sysVars map[string]string) {
+		t.Helper()
+		session := &vtgatepb.Session{TargetString: "@primary", Autocommit: true, SystemVariables: sysVars}
+		_, err := executorExec(ctx, executor, session, "select id from main1 where id = 1", nil)
+		require.NoError(t, err)
+	}
+
+	exec(nil)
+	exec(nil)
+	assert.EqualValues(t, 1, sbclookup.ExecCount.Load())
+
+	// Results are cached per session system variables.
+	exec(map[string]string{"time_zone": "'+01:00'"})
+	exec(map[string]string{"time_zone": "'+01:00'"})
+	assert.EqualValues(t, 2, sbclookup.ExecCount.Load())
+	exec(map[string]string{"time_zone": "'+02:00'"})
+	exec(map[string]string{"time_zone": "'+01:00'", "sql_mode": "''"})
+	assert.EqualValues(t, 4, sbclookup.ExecCount.Load())
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by Sizegen. DO NOT EDIT.
+
+package resultcache
+
+import hack "vitess.io/vitess/go/hack"
+
+func (cached *Snapshot) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(48)
+	}
+	// field tables []string
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.tables)) * int64(16))
+		for _, elem := range cached.tables {
+			size += hack.RuntimeAllocSize(int64(len(elem)))
+		}
+	}
+	// field generations []uint64
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.generations)) * int64(8))
+	}
+	return size
+}
+func (cached *entry) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(80)
+	}
+	// field result *vitess.io/vitess/go/sqltypes.Result
+	size += cached.result.CachedSize(true)
+	// field snapshot vitess.io/vitess/go/vt/vtgate/resultcache.Snapshot
+	size += cached.snapshot.CachedSize(false)
+	return size
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resultcache implements the memory bounded cache of query results used by vtgate.
+//
+// Every cached result records the generation of the tables its query read when the query
+// started. Invalidating a table bumps its generation, which makes all the results that read
+// it stale without having to find them. Results also expire after their time to live.
+package resultcache
+
+import (
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/cache/theine"
+	"vitess.io/vitess/go/sqltypes"
+)
+
+// Key identifies a cached result. It is typically the hash of the query, its bind variables,
+// and of anything else the result depends on.
+type Key = theine.HashKey256
+
+// Cache is a memory bounded cache of query results, invalidated by table or by time to live.
+type Cache struct {
+	store *theine.Store[Key, *entry]
+
+	mu          sync.RWMutex
+	generations map[string]uint64
+}
+
+type entry struct {
+	result   *sqltypes.Result
+	expires  time.Time
+	snapshot Snapshot
+}
+
+// Snapshot is the generation of a set of tables at some point in time.
+type Snapshot struct {
+	tables      []string
+	generations []uint64
+}
+
+// New creates a Cache using at most maxMemory bytes for the results.
+func New(maxMemory int64) *Cache {
+	return &Cache{
+		store:       theine.NewStore[Key, *entry](maxMemory, false),
+		generations: make(map[string]uint64),
+	}
+}
+
+// Snapshot returns the current generation of the tables. It must be taken before
+// running the query whose result is then passed to Set, so that an invalidation
+// happening while the query runs makes the result stale.
+func (c *Cache) Snapshot(tables []string) Snapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	snapshot := Snapshot{
+		tables:      tables,
+		generations: make([]uint64, len(tables)),
+	}
+	for i, table := range tables {
+		snapshot.generations[i] = c.generations[table]
+	}
+	return snapshot
+}
+
+// Get returns the result cached under the key, if it has neither expired nor been invalidated.
+// The returned result must not be modified.
+func (c *Cache) Get(key Key) (*sqltypes.Result, bool) {
+	e, ok := c.store.Get(key, 0)
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) || !c.isCurrent(e.snapshot) {
+		c.store.Delete(key)
+		return nil, false
+	}
+	return e.result.ShallowCopy(), true
+}
+
+// Set caches the result under the key for the given time to live. The snapshot must have
+// been taken before the query producing the result started.
+func (c *Cache) Set(key Key, snapshot Snapshot, result *sqltypes.Result, ttl time.Duration) {
+	if ttl <= 0 || !c.isCurrent(snapshot) {
+		return
+	}
+	e := &entry{
+		result:   result.ShallowCopy(),
+		expires:  time.Now().Add(ttl),
+		snapshot: snapshot,
+	}
+	c.store.Set(key, e, e.CachedSize(true), 0)
+}
+
+// Invalidate makes all the cached results that read any of the tables stale.
+func (c *Cache) Invalidate(tables ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, table := range tables {
+		c.generations[table]++
+	}
+}
+
+func (c *Cache) isCurrent(snapshot Snapshot) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for i, table := range snapshot.tables {
+		if c.generations[table] != snapshot.generations[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Len returns the number of cached results.
+func (c *Cache) Len() int {
+	return c.store.Len()
+}
+
+// UsedCapacity returns the memory used by the cached results, in bytes.
+func (c *Cache) UsedCapacity() int {
+	return c.store.UsedCapacity()
+}
+
+// MaxCapacity returns the maximum memory used by the cached results, in bytes.
+func (c *Cache) MaxCapacity() int {
+	return c.store.MaxCapacity()
+}
+
+// Evictions returns the number of results evicted to stay within the memory bound.
+func (c *Cache) Evictions() int64 {
+	return c.store.Metrics.Evicted()
+}
+
+// Close closes the cache.
+func (c *Cache) Close() {
+	c.store.Close()
+}
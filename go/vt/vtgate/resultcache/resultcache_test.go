@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resultcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+)
+
+func TestCache(t *testing.T) {
+	c := New(1024 * 1024)
+	defer c.Close()
+
+	result := sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1", "2")
+	tables := []string{"ks.t1", "ks.t2"}
+	key := Key{1}
+
+	_, ok := c.Get(key)
+	assert.False(t, ok)
+
+	c.Set(key, c.Snapshot(tables), result, time.Minute)
+	got, ok := c.Get(key)
+	require.True(t, ok)
+	assert.True(t, result.Equal(got))
+
+	// Invalidating another table does not affect the result.
+	c.Invalidate("ks.t3")
+	_, ok = c.Get(key)
+	assert.True(t, ok)
+
+	// Invalidating any of the tables of the result does.
+	c.Invalidate("ks.t2")
+	_, ok = c.Get(key)
+	assert.False(t, ok)
+
+	// A result read before an invalidation is not cached.
+	snapshot := c.Snapshot(tables)
+	c.Invalidate("ks.t1")
+	c.Set(key, snapshot, result, time.Minute)
+	_, ok = c.Get(key)
+	assert.False(t, ok)
+
+	// Results expire.
+	c.Set(key, c.Snapshot(tables), result, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, ok = c.Get(key)
+	assert.False(t, ok)
+
+	// A zero time to live disables caching.
+	c.Set(key, c.Snapshot(tables), result, 0)
+	_, ok = c.Get(key)
+	assert.False(t, ok)
+}
+
+func TestCacheReturnsCopies(t *testing.T) {
+	c := New(1024 * 1024)
+	defer c.Close()
+
+	result := sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1")
+	key := Key{1}
+	c.Set(key, c.Snapshot([]string{"ks.t1"}), result, time.Minute)
+	result.RowsAffected = 10
+
+	got, ok := c.Get(key)
+	require.True(t, ok)
+	got.Info = "modified"
+
+	got, ok = c.Get(key)
+	require.True(t, ok)
+	assert.Zero(t, got.RowsAffected)
+	assert.Empty(t, got.Info)
+}
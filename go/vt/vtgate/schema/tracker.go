@@ -293,7 +293,8 @@ func (t *Tracker) updateTables(keyspace string, res map[string]string) {
 
 		cols := getColumns(ddl.TableSpec)
 		fks := getForeignKeys(ddl.TableSpec)
-		t.tables.set(keyspace, tableName, cols, fks)
+		pk := getPrimaryKey(ddl.TableSpec)
+		t.tables.set(keyspace, tableName, cols, fks, pk)
 	}
 }
 
@@ -328,6 +329,26 @@ func getForeignKeys(tblSpec *sqlparser.TableSpec) []*sqlparser.ForeignKeyDefinit
 	return fks
 }
 
+// getPrimaryKey returns the primary key columns of the table, if it has a primary key.
+func getPrimaryKey(tblSpec *sqlparser.TableSpec) sqlparser.Columns {
+	for _, idx := range tblSpec.Indexes {
+		if idx.Info.Type != sqlparser.IndexTypePrimary {
+			continue
+		}
+		var cols sqlparser.Columns
+		for _, col := range idx.Columns {
+			cols = append(cols, col.Column)
+		}
+		return cols
+	}
+	for _, column := range tblSpec.Columns {
+		if column.Type.Options != nil && column.Type.Options.KeyOpt == sqlparser.ColKeyPrimary {
+			return sqlparser.Columns{column.Name}
+		}
+	}
+	return nil
+}
+
 func getTableCollation(tblSpec *sqlparser.TableSpec) string {
 	if tblSpec.Options == nil {
 		return ""
@@ -403,13 +424,13 @@ type tableMap struct {
 	m map[keyspaceStr]map[tableNameStr]*vindexes.TableInfo
 }
 
-func (tm *tableMap) set(ks, tbl string, cols []vindexes.Column, fks []*sqlparser.ForeignKeyDefinition) {
+func (tm *tableMap) set(ks, tbl string, cols []vindexes.Column, fks []*sqlparser.ForeignKeyDefinition, pk sqlparser.Columns) {
 	m := tm.m[ks]
 	if m == nil {
 		m = make(map[tableNameStr]*vindexes.TableInfo)
 		tm.m[ks] = m
 	}
-	m[tbl] = &vindexes.TableInfo{Columns: cols, ForeignKeys: fks}
+	m[tbl] = &vindexes.TableInfo{Columns: cols, ForeignKeys: fks, PrimaryKey: pk}
 }
 
 func (tm *tableMap) get(ks, tbl string) *vindexes.TableInfo {
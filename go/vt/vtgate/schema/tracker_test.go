@@ -322,6 +322,30 @@ func TestTableInfoRetrieval(t *testing.T) {
 	testTracker(t, schemaDefResult, testcases)
 }
 
+func TestGetPrimaryKey(t *testing.T) {
+	tcases := []struct {
+		tableDef string
+		expected string
+	}{{
+		tableDef: "create table t1(id bigint primary key, name varchar(50))",
+		expected: "(id)",
+	}, {
+		tableDef: "create table t2(id bigint, name varchar(50), primary key (name, id))",
+		expected: "(`name`, id)",
+	}, {
+		tableDef: "create table t3(id bigint, name varchar(50), unique key (id))",
+		expected: "",
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.tableDef, func(t *testing.T) {
+			stmt, err := sqlparser.Parse(tcase.tableDef)
+			require.NoError(t, err)
+			pk := getPrimaryKey(stmt.(*sqlparser.CreateTable).TableSpec)
+			require.Equal(t, tcase.expected, sqlparser.String(pk))
+		})
+	}
+}
+
 type testCases struct {
 	testName string
 
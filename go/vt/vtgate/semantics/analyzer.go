@@ -61,7 +61,11 @@ func newAnalyzer(dbName string, si SchemaInformation) *analyzer {
 		scoper:          s,
 		binder:          b,
 		expandedColumns: map[sqlparser.TableName][]*sqlparser.ColName{},
+		recursiveDefs:   map[*sqlparser.CommonTableExpr]any{},
+		recursiveCTEs:   map[*sqlparser.Union]*RecursiveCTE{},
+		cteRefs:         map[*sqlparser.AliasedTableExpr]*RecursiveCTE{},
 	}
+	a.tables.cteRefs = a.rewriter.cteRefs
 	s.binder = b
 	return a
 }
@@ -125,6 +129,7 @@ func (a *analyzer) newSemTable(statement sqlparser.Statement, coll collations.ID
 		ColumnEqualities:          map[columnName][]sqlparser.Expr{},
 		Collation:                 coll,
 		ExpandedColumns:           a.rewriter.expandedColumns,
+		RecursiveCTEs:             a.rewriter.recursiveCTEs,
 		columns:                   columns,
 		StatementIDs:              a.scoper.statementIDs,
 		QuerySignature:            a.sig,
@@ -312,7 +317,10 @@ func (a *analyzer) noteQuerySignature(node sqlparser.SQLNode) {
 			a.sig.Aggregation = true
 		}
 	case sqlparser.AggrFunc:
-		a.sig.Aggregation = true
+		// aggregations with an OVER clause don't group the rows of the query
+		if !sqlparser.IsWindowFunc(node) {
+			a.sig.Aggregation = true
+		}
 	}
 }
 
@@ -832,8 +832,20 @@ func TestInvalidQueries(t *testing.T) {
 		sql:  "select 1 from t1 where (id, id) in (select 1, 2, 3)",
 		serr: "Operand should contain 2 column(s)",
 	}, {
-		sql:  "WITH RECURSIVE cte (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM cte WHERE n < 5) SELECT * FROM cte",
-		serr: "VT12001: unsupported: recursive common table expression",
+		sql:  "with recursive cte (n) as (select n from cte) select * from cte",
+		serr: "VT12001: unsupported: recursive common table expression without UNION",
+	}, {
+		sql:  "with recursive cte (n) as (select n from cte union all select 1) select * from cte",
+		serr: "VT12001: unsupported: recursive common table expression with a recursive reference outside of the last SELECT",
+	}, {
+		sql:  "with recursive cte (n) as (select 1 union all select c1.n + c2.n from cte c1, cte c2) select * from cte",
+		serr: "VT12001: unsupported: recursive common table expression that does not reference itself exactly once in the FROM clause",
+	}, {
+		sql:  "with recursive cte (n) as (select 1 union all (select n + 1 from cte union select 2)) select * from cte",
+		serr: "VT12001: unsupported: recursive common table expression with a recursive part that is not a SELECT",
+	}, {
+		sql:  "with recursive cte (n) as (select 1 union all select distinct n + 1 from cte where n < 5) select * from cte",
+		serr: "VT12001: unsupported: aggregation, window functions, DISTINCT, ORDER BY or LIMIT in the recursive part of a common table expression",
 	}, {
 		sql:  "with x as (select 1), x as (select 1) select * from x",
 		serr: "VT03013: not unique table/alias: 'x'",
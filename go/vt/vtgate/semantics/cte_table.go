@@ -0,0 +1,164 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semantics
+
+import (
+	"strings"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+type (
+	// RecursiveCTE contains the information about a recursive common table expression
+	// that has been inlined into the query as a derived table.
+	// The derived table is a UNION where the last SELECT, the recursive term,
+	// is the only one referencing the CTE itself.
+	RecursiveCTE struct {
+		// Name is the name the CTE was declared with
+		Name string
+		// Columns are the column names declared with the CTE, if any
+		Columns sqlparser.Columns
+		// Seed is the non-recursive part of the CTE
+		Seed sqlparser.SelectStatement
+		// Term is the recursive part of the CTE
+		Term *sqlparser.Select
+		// SelfRef is the table expression in the FROM clause of the term that references the CTE
+		SelfRef *sqlparser.AliasedTableExpr
+	}
+
+	// CTETable is the table that the recursive term of a recursive CTE reads from.
+	// It contains the rows produced by the previous iteration of the CTE,
+	// and so it has the same columns as the seed of the CTE.
+	CTETable struct {
+		tableName       string
+		ASTNode         *sqlparser.AliasedTableExpr
+		CTE             *RecursiveCTE
+		columnNames     []string
+		types           []evalengine.Type
+		isAuthoritative bool
+	}
+)
+
+var _ TableInfo = (*CTETable)(nil)
+
+func newCTETable(node *sqlparser.AliasedTableExpr, cte *RecursiveCTE, seed *sqlparser.Select, org originable) *CTETable {
+	tableName := node.As.String()
+	if node.As.IsEmpty() {
+		tableName = cte.Name
+	}
+	tbl := &CTETable{
+		tableName:       tableName,
+		ASTNode:         node,
+		CTE:             cte,
+		isAuthoritative: true,
+	}
+	for i, selectExpr := range seed.SelectExprs {
+		ae, ok := selectExpr.(*sqlparser.AliasedExpr)
+		if !ok {
+			tbl.isAuthoritative = false
+			continue
+		}
+		name := ae.ColumnName()
+		if len(cte.Columns) > i {
+			name = cte.Columns[i].String()
+		}
+		_, _, typ := org.depsForExpr(ae.Expr)
+		tbl.columnNames = append(tbl.columnNames, name)
+		tbl.types = append(tbl.types, typ)
+	}
+	return tbl
+}
+
+// dependencies implements the TableInfo interface
+func (c *CTETable) dependencies(colName string, org originable) (dependencies, error) {
+	ts := org.tableSetFor(c.ASTNode)
+	for i, name := range c.columnNames {
+		if strings.EqualFold(name, colName) {
+			return createCertain(ts, ts, c.types[i]), nil
+		}
+	}
+
+	if c.authoritative() {
+		return &nothing{}, nil
+	}
+	return createUncertain(ts, ts), nil
+}
+
+// IsInfSchema implements the TableInfo interface
+func (c *CTETable) IsInfSchema() bool {
+	return false
+}
+
+func (c *CTETable) matches(name sqlparser.TableName) bool {
+	return c.tableName == name.Name.String() && name.Qualifier.IsEmpty()
+}
+
+func (c *CTETable) authoritative() bool {
+	return c.isAuthoritative
+}
+
+// Name implements the TableInfo interface
+func (c *CTETable) Name() (sqlparser.TableName, error) {
+	return c.ASTNode.TableName()
+}
+
+func (c *CTETable) getAliasedTableExpr() *sqlparser.AliasedTableExpr {
+	return c.ASTNode
+}
+
+func (c *CTETable) canShortCut() shortCut {
+	return canShortCut
+}
+
+// GetVindexTable implements the TableInfo interface
+func (c *CTETable) GetVindexTable() *vindexes.Table {
+	return nil
+}
+
+func (c *CTETable) getColumns() []ColumnInfo {
+	cols := make([]ColumnInfo, 0, len(c.columnNames))
+	for i, col := range c.columnNames {
+		cols = append(cols, ColumnInfo{
+			Name: col,
+			Type: c.types[i],
+		})
+	}
+	return cols
+}
+
+// getTableSet implements the TableInfo interface
+func (c *CTETable) getTableSet(org originable) TableSet {
+	return org.tableSetFor(c.ASTNode)
+}
+
+// getExprFor implements the TableInfo interface
+func (c *CTETable) getExprFor(s string) (sqlparser.Expr, error) {
+	return nil, vterrors.VT03022(s, "field list")
+}
+
+// ColumnOffset returns the offset of the named column in the rows of the CTE, or -1 if it is unknown
+func (c *CTETable) ColumnOffset(name string) int {
+	for i, col := range c.columnNames {
+		if strings.EqualFold(col, name) {
+			return i
+		}
+	}
+	return -1
+}
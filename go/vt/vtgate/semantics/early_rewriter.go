@@ -33,6 +33,13 @@ type earlyRewriter struct {
 	clause          string
 	warning         string
 	expandedColumns map[sqlparser.TableName][]*sqlparser.ColName
+
+	// recursiveDefs holds the CTEs that reference themselves
+	recursiveDefs map[*sqlparser.CommonTableExpr]any
+	// recursiveCTEs holds the information about every recursive CTE we have inlined as a derived table
+	recursiveCTEs map[*sqlparser.Union]*RecursiveCTE
+	// cteRefs holds the references recursive CTEs make to themselves, so we don't inline them
+	cteRefs map[*sqlparser.AliasedTableExpr]*RecursiveCTE
 }
 
 func (r *earlyRewriter) down(cursor *sqlparser.Cursor) error {
@@ -75,11 +82,19 @@ func (r *earlyRewriter) handleAliasedTable(node *sqlparser.AliasedTableExpr) err
 	if cte == nil {
 		return nil
 	}
+	if _, isSelfRef := r.cteRefs[node]; isSelfRef {
+		// this is the recursive term reading from the CTE it belongs to
+		return nil
+	}
 	if node.As.IsEmpty() {
 		node.As = tbl.Name
 	}
+	sel := cte.Subquery.Select
+	if _, isRecursive := r.recursiveDefs[cte]; isRecursive {
+		sel = r.inlineRecursiveCTE(cte)
+	}
 	node.Expr = &sqlparser.DerivedTable{
-		Select: cte.Subquery.Select,
+		Select: sel,
 	}
 	if len(cte.Columns) > 0 {
 		node.Columns = cte.Columns
@@ -87,10 +102,34 @@ func (r *earlyRewriter) handleAliasedTable(node *sqlparser.AliasedTableExpr) err
 	return nil
 }
 
+// inlineRecursiveCTE creates a copy of the recursive CTE body for every place the CTE is used,
+// so that each copy can get its own reference from the recursive term back to the CTE
+func (r *earlyRewriter) inlineRecursiveCTE(cte *sqlparser.CommonTableExpr) *sqlparser.Union {
+	union := sqlparser.CloneRefOfUnion(cte.Subquery.Select.(*sqlparser.Union))
+	term := union.Right.(*sqlparser.Select)
+	info := &RecursiveCTE{
+		Name:    cte.ID.String(),
+		Columns: cte.Columns,
+		Seed:    union.Left,
+		Term:    term,
+		SelfRef: findCTEReference(term.From, cte.ID.String()),
+	}
+	r.recursiveCTEs[union] = info
+	r.cteRefs[info.SelfRef] = info
+	return union
+}
+
 func (r *earlyRewriter) handleWith(node *sqlparser.With) error {
 	scope := r.scoper.currentScope()
 	for _, cte := range node.CTEs {
-		err := scope.addCTE(cte)
+		recursive := node.Recursive && countCTEReferences(cte.Subquery.Select, cte.ID.String()) > 0
+		if recursive {
+			if err := checkRecursiveCTE(cte); err != nil {
+				return err
+			}
+			r.recursiveDefs[cte] = nil
+		}
+		err := scope.addCTE(cte, recursive)
 		if err != nil {
 			return err
 		}
@@ -99,6 +138,70 @@ func (r *earlyRewriter) handleWith(node *sqlparser.With) error {
 	return nil
 }
 
+// checkRecursiveCTE makes sure that a CTE referencing itself has the shape of a recursive CTE:
+// a UNION where only the last SELECT references the CTE, exactly once and in its FROM clause
+func checkRecursiveCTE(cte *sqlparser.CommonTableExpr) error {
+	name := cte.ID.String()
+	union, ok := cte.Subquery.Select.(*sqlparser.Union)
+	if !ok {
+		return vterrors.VT12001("recursive common table expression without UNION")
+	}
+	if countCTEReferences(union.Left, name) > 0 {
+		return vterrors.VT12001("recursive common table expression with a recursive reference outside of the last SELECT")
+	}
+	term, ok := union.Right.(*sqlparser.Select)
+	if !ok {
+		return vterrors.VT12001("recursive common table expression with a recursive part that is not a SELECT")
+	}
+	if countCTEReferences(term, name) > 1 || findCTEReference(term.From, name) == nil {
+		return vterrors.VT12001("recursive common table expression that does not reference itself exactly once in the FROM clause")
+	}
+	if len(term.GroupBy) > 0 || term.Distinct || len(term.OrderBy) > 0 || term.Limit != nil ||
+		sqlparser.ContainsAggregation(term.SelectExprs) || sqlparser.ContainsWindowFunc(term.SelectExprs) {
+		return vterrors.VT12001("aggregation, window functions, DISTINCT, ORDER BY or LIMIT in the recursive part of a common table expression")
+	}
+	return nil
+}
+
+// countCTEReferences returns the number of times the given CTE name is used as a table in the statement
+func countCTEReferences(node sqlparser.SQLNode, name string) (count int) {
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		aliasedTable, ok := node.(*sqlparser.AliasedTableExpr)
+		if !ok {
+			return true, nil
+		}
+		tbl, ok := aliasedTable.Expr.(sqlparser.TableName)
+		if ok && tbl.Qualifier.IsEmpty() && tbl.Name.String() == name {
+			count++
+		}
+		return true, nil
+	}, node)
+	return
+}
+
+// findCTEReference looks for the reference to the CTE in the tables of a FROM clause,
+// without looking inside derived tables or subqueries
+func findCTEReference(exprs sqlparser.TableExprs, name string) *sqlparser.AliasedTableExpr {
+	for _, expr := range exprs {
+		switch expr := expr.(type) {
+		case *sqlparser.AliasedTableExpr:
+			tbl, ok := expr.Expr.(sqlparser.TableName)
+			if ok && tbl.Qualifier.IsEmpty() && tbl.Name.String() == name {
+				return expr
+			}
+		case *sqlparser.JoinTableExpr:
+			if ref := findCTEReference(sqlparser.TableExprs{expr.LeftExpr, expr.RightExpr}, name); ref != nil {
+				return ref
+			}
+		case *sqlparser.ParenTableExpr:
+			if ref := findCTEReference(expr.Exprs, name); ref != nil {
+				return ref
+			}
+		}
+	}
+	return nil
+}
+
 func rewriteNotExpr(cursor *sqlparser.Cursor, node *sqlparser.NotExpr) {
 	cmp, ok := node.Expr.(*sqlparser.ComparisonExpr)
 	if !ok {
@@ -562,6 +562,9 @@ func TestCTEToDerivedTableRewrite(t *testing.T) {
 	}, {
 		sql:    "with x(id) as (select 1) select * from x",
 		expSQL: "select id from (select 1 from dual) as x(id)",
+	}, {
+		sql:    "with recursive x(n) as (select 1 union all select n + 1 from x where n < 5) select * from x",
+		expSQL: "select n from (select 1 from dual union all select n + 1 from x where n < 5) as x(n)",
 	}}
 	for _, tcase := range tcases {
 		t.Run(tcase.sql, func(t *testing.T) {
@@ -303,12 +303,17 @@ func newScope(parent *scope) *scope {
 	}
 }
 
-func (s *scope) addCTE(cte *sqlparser.CommonTableExpr) error {
+func (s *scope) addCTE(cte *sqlparser.CommonTableExpr, recursive bool) error {
 	name := cte.ID.String()
 	_, exists := s.ctes[name]
 	if exists {
 		return vterrors.VT03013(name)
 	}
+	if recursive {
+		// a recursive CTE is supposed to use its own alias. its shape has already been checked
+		s.ctes[name] = cte
+		return nil
+	}
 	if err := checkForInvalidAliasUse(cte, name); err != nil {
 		return err
 	}
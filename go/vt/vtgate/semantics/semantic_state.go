@@ -123,6 +123,10 @@ type (
 		// The columns were added because of the use of `*` in the query
 		ExpandedColumns map[sqlparser.TableName][]*sqlparser.ColName
 
+		// RecursiveCTEs contains the recursive common table expressions of the query,
+		// keyed by the UNION they have been inlined as
+		RecursiveCTEs map[*sqlparser.Union]*RecursiveCTE
+
 		columns map[*sqlparser.Union]sqlparser.SelectExprs
 
 		comparator *sqlparser.Comparator
@@ -448,6 +452,8 @@ func (st *SemTable) ReplaceTableSetFor(id TableSet, t *sqlparser.AliasedTableExp
 		tbl.ASTNode = t
 	case *DerivedTable:
 		tbl.ASTNode = t
+	case *CTETable:
+		tbl.ASTNode = t
 	}
 }
 
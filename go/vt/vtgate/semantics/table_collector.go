@@ -33,6 +33,7 @@ type tableCollector struct {
 	currentDb string
 	org       originable
 	unionInfo map[*sqlparser.Union]unionInfo
+	cteRefs   map[*sqlparser.AliasedTableExpr]*RecursiveCTE
 }
 
 func newTableCollector(scoper *scoper, si SchemaInformation, currentDb string) *tableCollector {
@@ -102,6 +103,10 @@ func (tc *tableCollector) visitAliasedTableExpr(node *sqlparser.AliasedTableExpr
 }
 
 func (tc *tableCollector) handleTableName(node *sqlparser.AliasedTableExpr, t sqlparser.TableName) error {
+	if cte, isCTERef := tc.cteRefs[node]; isCTERef {
+		return tc.addCTETable(node, cte)
+	}
+
 	var tbl *vindexes.Table
 	var vindex vindexes.Vindex
 	isInfSchema := sqlparser.SystemSchema(t.Qualifier.String())
@@ -122,6 +127,13 @@ func (tc *tableCollector) handleTableName(node *sqlparser.AliasedTableExpr, t sq
 	return scope.addTable(tableInfo)
 }
 
+func (tc *tableCollector) addCTETable(node *sqlparser.AliasedTableExpr, cte *RecursiveCTE) error {
+	tableInfo := newCTETable(node, cte, sqlparser.GetFirstSelect(cte.Seed), tc.org)
+	tc.Tables = append(tc.Tables, tableInfo)
+	scope := tc.scoper.currentScope()
+	return scope.addTable(tableInfo)
+}
+
 func (tc *tableCollector) handleDerivedTable(node *sqlparser.AliasedTableExpr, t *sqlparser.DerivedTable) error {
 	switch sel := t.Select.(type) {
 	case *sqlparser.Select:
@@ -66,6 +66,14 @@ func (t *typer) up(cursor *sqlparser.Cursor) error {
 		}
 		type_ := code.Type(inputType)
 		t.m[node] = evalengine.Type{Type: type_, Coll: collations.DefaultCollationForType(type_)}
+	case *sqlparser.ArgumentLessWindowExpr:
+		type_ := sqltypes.Uint64
+		if node.Type == sqlparser.CumeDistExprType || node.Type == sqlparser.PercentRankExprType {
+			type_ = sqltypes.Float64
+		}
+		t.m[node] = evalengine.Type{Type: type_, Coll: collations.DefaultCollationForType(type_)}
+	case *sqlparser.NtileExpr:
+		t.m[node] = evalengine.Type{Type: sqltypes.Uint64, Coll: collations.DefaultCollationForType(sqltypes.Uint64)}
 	}
 	return nil
 }
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"vitess.io/vitess/go/vt/concurrency"
 	"vitess.io/vitess/go/vt/dtids"
@@ -359,6 +360,30 @@ func (txc *TxConn) Resolve(ctx context.Context, dtid string) error {
 	return nil
 }
 
+// ResolveTransactions resolves the 2PC transactions older than abandonAge
+// whose metadata is managed by the specified target. It returns the number
+// of transactions that were resolved.
+func (txc *TxConn) ResolveTransactions(ctx context.Context, target *querypb.Target, abandonAge time.Duration) (int, error) {
+	if err := validateTxResolverAbandonAge(abandonAge); err != nil {
+		return 0, err
+	}
+	transactions, err := txc.tabletGateway.UnresolvedTransactions(ctx, target, int64(abandonAge.Seconds()))
+	if err != nil {
+		return 0, err
+	}
+
+	resolved := 0
+	allErrors := new(concurrency.AllErrorRecorder)
+	for _, transaction := range transactions {
+		if err := txc.Resolve(ctx, transaction.Dtid); err != nil {
+			allErrors.RecordError(vterrors.Wrapf(err, "failed to resolve %s", transaction.Dtid))
+			continue
+		}
+		resolved++
+	}
+	return resolved, allErrors.AggrError(vterrors.Aggregate)
+}
+
 func (txc *TxConn) resumeRollback(ctx context.Context, target *querypb.Target, transaction *querypb.TransactionMetadata) error {
 	err := txc.runTargets(transaction.Participants, func(t *querypb.Target) error {
 		return txc.tabletGateway.RollbackPrepared(ctx, t, transaction.Dtid, 0)
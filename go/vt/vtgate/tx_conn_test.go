@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -1095,6 +1096,37 @@ func TestTxConnResolveOnCommit(t *testing.T) {
 	assert.EqualValues(t, 1, sbc0.ConcludeTransactionCount.Load(), "sbc0.ConcludeTransactionCount")
 }
 
+func TestTxConnResolveTransactions(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	sc, sbc0, sbc1, _, _, _ := newTestTxConnEnv(t, ctx, "TestTxConn")
+
+	dtid := "TestTxConn:0:1234"
+	sbc0.UnresolvedTransactionsResult = []*querypb.TransactionMetadata{{
+		Dtid:  dtid,
+		State: querypb.TransactionState_COMMIT,
+	}, {
+		Dtid:  "abcd",
+		State: querypb.TransactionState_PREPARE,
+	}}
+	sbc0.ReadTransactionResults = []*querypb.TransactionMetadata{{
+		Dtid:  dtid,
+		State: querypb.TransactionState_COMMIT,
+		Participants: []*querypb.Target{{
+			Keyspace:   "TestTxConn",
+			Shard:      "1",
+			TabletType: topodatapb.TabletType_PRIMARY,
+		}},
+	}}
+	target := &querypb.Target{Keyspace: "TestTxConn", Shard: "0", TabletType: topodatapb.TabletType_PRIMARY}
+	resolved, err := sc.txConn.ResolveTransactions(ctx, target, time.Minute)
+	require.EqualError(t, err, "failed to resolve abcd: invalid parts in dtid: abcd")
+	assert.Equal(t, 1, resolved)
+	assert.EqualValues(t, 1, sbc0.UnresolvedTransactionsCount.Load(), "sbc0.UnresolvedTransactionsCount")
+	assert.EqualValues(t, 1, sbc1.CommitPreparedCount.Load(), "sbc1.CommitPreparedCount")
+	assert.EqualValues(t, 1, sbc0.ConcludeTransactionCount.Load(), "sbc0.ConcludeTransactionCount")
+}
+
 func TestTxConnResolveInvalidDTID(t *testing.T) {
 	ctx := utils.LeakCheckContext(t)
 
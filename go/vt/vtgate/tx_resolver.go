@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/timer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// minTxResolverAbandonAge is the smallest abandon age accepted by the
+// transaction resolver. Younger transactions may still be in flight on a
+// vtgate, and resolving them would commit or roll them back under its feet.
+const minTxResolverAbandonAge = time.Minute
+
+var (
+	txResolverResolved = stats.NewCounter("VtgateTxResolverResolved", "Number of abandoned 2PC transactions resolved by the vtgate transaction resolver")
+	txResolverErrors   = stats.NewCounter("VtgateTxResolverErrors", "Number of errors of the vtgate transaction resolver")
+)
+
+// validateTxResolverAbandonAge returns an error if abandonAge is too small
+// for transactions older than it to be safely considered abandoned.
+func validateTxResolverAbandonAge(abandonAge time.Duration) error {
+	if abandonAge < minTxResolverAbandonAge {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "abandon age must be at least %v, got %v", minTxResolverAbandonAge, abandonAge)
+	}
+	return nil
+}
+
+// txResolver periodically resolves the abandoned 2PC transactions of
+// every primary tablet known to the gateway.
+type txResolver struct {
+	txConn *TxConn
+	ticks  *timer.Timer
+}
+
+func newTxResolver(txConn *TxConn) *txResolver {
+	return &txResolver{
+		txConn: txConn,
+		ticks:  timer.NewTimer(txResolverInterval),
+	}
+}
+
+// Start starts resolving transactions in the background.
+func (txr *txResolver) Start() {
+	txr.ticks.Start(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), txResolverInterval)
+		defer cancel()
+		txr.resolve(ctx)
+	})
+}
+
+// Stop stops resolving transactions.
+func (txr *txResolver) Stop() {
+	txr.ticks.Stop()
+}
+
+// resolve resolves the abandoned transactions of all the primaries.
+func (txr *txResolver) resolve(ctx context.Context) {
+	for _, target := range txr.primaryTargets() {
+		resolved, err := txr.txConn.ResolveTransactions(ctx, target, txResolverAbandonAge)
+		txResolverResolved.Add(int64(resolved))
+		if resolved > 0 {
+			log.Infof("Resolved %d abandoned transactions of %s/%s", resolved, target.Keyspace, target.Shard)
+		}
+		if err != nil {
+			txResolverErrors.Add(1)
+			log.Warningf("Failed to resolve abandoned transactions of %s/%s: %v", target.Keyspace, target.Shard, err)
+		}
+	}
+}
+
+// primaryTargets returns the primary target of every shard known to the
+// gateway, each shard listed once.
+func (txr *txResolver) primaryTargets() []*querypb.Target {
+	var targets []*querypb.Target
+	seen := make(map[string]bool)
+	for _, tcs := range txr.txConn.tabletGateway.hc.CacheStatus() {
+		if tcs.Target == nil || tcs.Target.TabletType != topodatapb.TabletType_PRIMARY {
+			continue
+		}
+		key := fmt.Sprintf("%s/%s", tcs.Target.Keyspace, tcs.Target.Shard)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		targets = append(targets, &querypb.Target{
+			Keyspace:   tcs.Target.Keyspace,
+			Shard:      tcs.Target.Shard,
+			TabletType: topodatapb.TabletType_PRIMARY,
+		})
+	}
+	return targets
+}
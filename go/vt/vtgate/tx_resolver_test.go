@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestTxResolver(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	sc, sbc0, sbc1, _, _, _ := newTestTxConnEnv(t, ctx, "TestTxResolver")
+
+	dtid := "TestTxResolver:0:1234"
+	sbc0.UnresolvedTransactionsResult = []*querypb.TransactionMetadata{{
+		Dtid:  dtid,
+		State: querypb.TransactionState_PREPARE,
+	}}
+	sbc0.ReadTransactionResults = []*querypb.TransactionMetadata{{
+		Dtid:  dtid,
+		State: querypb.TransactionState_PREPARE,
+		Participants: []*querypb.Target{{
+			Keyspace:   "TestTxResolver",
+			Shard:      "1",
+			TabletType: topodatapb.TabletType_PRIMARY,
+		}},
+	}}
+
+	txr := newTxResolver(sc.txConn)
+	utils.MustMatch(t, []*querypb.Target{{
+		Keyspace:   "TestTxResolver",
+		Shard:      "0",
+		TabletType: topodatapb.TabletType_PRIMARY,
+	}, {
+		Keyspace:   "TestTxResolver",
+		Shard:      "1",
+		TabletType: topodatapb.TabletType_PRIMARY,
+	}}, txr.primaryTargets())
+
+	before := txResolverResolved.Get()
+	txr.resolve(ctx)
+	assert.EqualValues(t, 1, txResolverResolved.Get()-before)
+	assert.EqualValues(t, 1, sbc0.UnresolvedTransactionsCount.Load(), "sbc0.UnresolvedTransactionsCount")
+	assert.EqualValues(t, 1, sbc1.UnresolvedTransactionsCount.Load(), "sbc1.UnresolvedTransactionsCount")
+	assert.EqualValues(t, 1, sbc0.SetRollbackCount.Load(), "sbc0.SetRollbackCount")
+	assert.EqualValues(t, 1, sbc1.RollbackPreparedCount.Load(), "sbc1.RollbackPreparedCount")
+	assert.EqualValues(t, 1, sbc0.ConcludeTransactionCount.Load(), "sbc0.ConcludeTransactionCount")
+}
+
+func TestTxResolverAbandonAge(t *testing.T) {
+	for _, abandonAge := range []time.Duration{-time.Second, 0, 500 * time.Millisecond, time.Second, 59 * time.Second} {
+		err := validateTxResolverAbandonAge(abandonAge)
+		require.ErrorContains(t, err, "abandon age must be at least 1m0s", "abandon age %v", abandonAge)
+		assert.Equal(t, vtrpcpb.Code_INVALID_ARGUMENT, vterrors.Code(err))
+	}
+	for _, abandonAge := range []time.Duration{time.Minute, 5 * time.Minute} {
+		assert.NoError(t, validateTxResolverAbandonAge(abandonAge), "abandon age %v", abandonAge)
+	}
+
+	ctx := utils.LeakCheckContext(t)
+	sc, sbc0, _, _, _, _ := newTestTxConnEnv(t, ctx, "TestTxResolverAbandonAge")
+	target := &querypb.Target{Keyspace: "TestTxResolverAbandonAge", Shard: "0", TabletType: topodatapb.TabletType_PRIMARY}
+	_, err := sc.txConn.ResolveTransactions(ctx, target, 0)
+	require.ErrorContains(t, err, "abandon age must be at least 1m0s")
+	assert.EqualValues(t, 0, sbc0.UnresolvedTransactionsCount.Load(), "sbc0.UnresolvedTransactionsCount")
+}
@@ -116,6 +116,15 @@ type vcursorImpl struct {
 
 	warmingReadsPercent int
 	warmingReadsChannel chan bool
+
+	// resultCache is set if the query asks for its result to be cached, and resultCacheSet
+	// if it uses the RESULT_CACHE directive at all, e.g. to opt out of the result cache.
+	resultCache    bool
+	resultCacheSet bool
+	resultCacheTTL time.Duration
+	// resultUncacheable is set if the result of the query must never be cached, e.g. because
+	// it depends on the session or on the time of the query.
+	resultUncacheable bool
 }
 
 // newVcursorImpl creates a vcursorImpl. Before creating this object, you have to separate out any marginComments that came with
@@ -216,11 +225,37 @@ func (vc *vcursorImpl) ExceedsMaxMemoryRows(numRows int) bool {
 	return !vc.ignoreMaxMemoryRows && numRows > maxMemoryRows
 }
 
+// HashJoinMemoryLimit implements the VCursor interface
+func (vc *vcursorImpl) HashJoinMemoryLimit() int64 {
+	return hashJoinMemoryLimit
+}
+
+// HashJoinSpillDir implements the VCursor interface
+func (vc *vcursorImpl) HashJoinSpillDir() string {
+	return hashJoinSpillDir
+}
+
 // SetIgnoreMaxMemoryRows sets the ignoreMaxMemoryRows value.
 func (vc *vcursorImpl) SetIgnoreMaxMemoryRows(ignoreMaxMemoryRows bool) {
 	vc.ignoreMaxMemoryRows = ignoreMaxMemoryRows
 }
 
+// setResultCache sets the value of the result cache directive, and whether the directive is set.
+func (vc *vcursorImpl) setResultCache(resultCache, isSet bool) {
+	vc.resultCache = resultCache
+	vc.resultCacheSet = isSet
+}
+
+// setResultUncacheable sets whether the result of the query must never be cached.
+func (vc *vcursorImpl) setResultUncacheable(uncacheable bool) {
+	vc.resultUncacheable = uncacheable
+}
+
+// setResultCacheTTL sets the time to live of the result in the result cache.
+func (vc *vcursorImpl) setResultCacheTTL(ttl time.Duration) {
+	vc.resultCacheTTL = ttl
+}
+
 // RecordWarning stores the given warning in the current session
 func (vc *vcursorImpl) RecordWarning(warning *querypb.QueryWarning) {
 	vc.safeSession.RecordWarning(warning)
@@ -1272,6 +1307,11 @@ func (vc *vcursorImpl) IsViewsEnabled() bool {
 	return enableViews
 }
 
+// IsHashJoinEnabled implements the VSchema interface
+func (vc *vcursorImpl) IsHashJoinEnabled() bool {
+	return enableHashJoin
+}
+
 func (vc *vcursorImpl) GetUDV(name string) *querypb.BindVariable {
 	return vc.safeSession.GetUDV(name)
 }
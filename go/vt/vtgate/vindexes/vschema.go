@@ -106,6 +106,12 @@ type Table struct {
 	Columns                 []Column               `json:"columns,omitempty"`
 	Pinned                  []byte                 `json:"pinned,omitempty"`
 	ColumnListAuthoritative bool                   `json:"column_list_authoritative,omitempty"`
+	// ResultCache is set to true if vtgate can cache the results of read-only
+	// queries on this table.
+	ResultCache bool `json:"result_cache,omitempty"`
+	// PrimaryKey contains the primary key columns of the table, if known.
+	// It is populated by the schema tracker.
+	PrimaryKey sqlparser.Columns `json:"primary_key,omitempty"`
 	// ReferencedBy is an inverse mapping of tables in other keyspaces that
 	// reference this table via Source.
 	//
@@ -148,6 +154,7 @@ type ColumnVindex struct {
 type TableInfo struct {
 	Columns     []Column
 	ForeignKeys []*sqlparser.ForeignKeyDefinition
+	PrimaryKey  sqlparser.Columns
 }
 
 // IsUnique is used to tell whether the ColumnVindex
@@ -373,6 +380,20 @@ func (vschema *VSchema) AddView(ksname string, viewName, query string) error {
 	return nil
 }
 
+// AddPrimaryKey is for testing only.
+func (vschema *VSchema) AddPrimaryKey(ksname, tblName string, cols []string) error {
+	ks, ok := vschema.Keyspaces[ksname]
+	if !ok {
+		return fmt.Errorf("keyspace %s not found in vschema", ksname)
+	}
+	tbl, ok := ks.Tables[tblName]
+	if !ok {
+		return fmt.Errorf("table %s not found in keyspace %s", tblName, ksname)
+	}
+	tbl.PrimaryKey = sqlparser.MakeColumns(cols...)
+	return nil
+}
+
 func buildGlobalTables(source *vschemapb.SrvVSchema, vschema *VSchema) {
 	for ksname, ks := range source.Keyspaces {
 		ksvschema := vschema.Keyspaces[ksname]
@@ -544,6 +565,7 @@ func buildTables(ks *vschemapb.Keyspace, vschema *VSchema, ksvschema *KeyspaceSc
 			Name:                    sqlparser.NewIdentifierCS(tname),
 			Keyspace:                keyspace,
 			ColumnListAuthoritative: table.ColumnListAuthoritative,
+			ResultCache:             table.ResultCache,
 		}
 		switch table.Type {
 		case "":
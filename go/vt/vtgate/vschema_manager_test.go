@@ -29,6 +29,7 @@ func TestVSchemaUpdate(t *testing.T) {
 	tblCol1 := &vindexes.Table{Name: sqlparser.NewIdentifierCS("tbl"), Keyspace: ks, Columns: cols1, ColumnListAuthoritative: true}
 	tblCol2 := &vindexes.Table{Name: sqlparser.NewIdentifierCS("tbl"), Keyspace: ks, Columns: cols2, ColumnListAuthoritative: true}
 	tblCol2NA := &vindexes.Table{Name: sqlparser.NewIdentifierCS("tbl"), Keyspace: ks, Columns: cols2}
+	tblCol1PK := &vindexes.Table{Name: sqlparser.NewIdentifierCS("tbl"), Keyspace: ks, Columns: cols1, ColumnListAuthoritative: true, PrimaryKey: sqlparser.MakeColumns("id")}
 
 	vindexTable_multicol_t1 := &vindexes.Table{
 		Name:                    sqlparser.NewIdentifierCS("multicol_t1"),
@@ -102,6 +103,11 @@ func TestVSchemaUpdate(t *testing.T) {
 		srvVschema: makeTestSrvVSchema("ks", false, nil),
 		schema:     map[string]*vindexes.TableInfo{"tbl": {Columns: cols1}},
 		expected:   makeTestVSchema("ks", false, map[string]*vindexes.Table{"tbl": tblCol1}),
+	}, {
+		name:       "1 Schematracking with primary key - 0 srvVSchema",
+		srvVschema: makeTestSrvVSchema("ks", false, nil),
+		schema:     map[string]*vindexes.TableInfo{"tbl": {Columns: cols1, PrimaryKey: sqlparser.MakeColumns("id")}},
+		expected:   makeTestVSchema("ks", false, map[string]*vindexes.Table{"tbl": tblCol1PK}),
 	}, {
 		name:       "1 Schematracking - 1 srvVSchema (no columns) not authoritative",
 		srvVschema: makeTestSrvVSchema("ks", false, map[string]*vschemapb.Table{"tbl": {}}),
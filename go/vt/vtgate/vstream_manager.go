@@ -146,10 +146,28 @@ func newVStreamManager(resolver *srvtopo.Resolver, serv srvtopo.Server, cell str
 
 func (vsm *vstreamManager) VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid,
 	filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func(events []*binlogdatapb.VEvent) error) error {
+	subscription := flags.GetSubscription()
+	newSubscription := false
+	if subscription != "" {
+		sub, err := vsm.getSubscription(ctx, subscription)
+		if err != nil {
+			return err
+		}
+		if sub != nil {
+			vgtid, filter = sub.Vgtid, sub.Filter
+		} else {
+			newSubscription = true
+		}
+	}
 	vgtid, filter, flags, err := vsm.resolveParams(ctx, tabletType, vgtid, filter, flags)
 	if err != nil {
 		return err
 	}
+	if newSubscription {
+		if err := vsm.createSubscription(ctx, subscription, vgtid, filter); err != nil {
+			return err
+		}
+	}
 	ts, err := vsm.toposerv.GetTopoServer()
 	if err != nil {
 		return err
@@ -743,10 +761,11 @@ func (vs *vstream) sendAll(ctx context.Context, sgtid *binlogdatapb.ShardGtid, e
 				// Update the VGtid and send that instead.
 				sgtid.Gtid = event.Gtid
 				events[j] = &binlogdatapb.VEvent{
-					Type:     binlogdatapb.VEventType_VGTID,
-					Vgtid:    vs.vgtid.CloneVT(),
-					Keyspace: event.Keyspace,
-					Shard:    event.Shard,
+					Type:      binlogdatapb.VEventType_VGTID,
+					Vgtid:     vs.vgtid.CloneVT(),
+					Keyspace:  event.Keyspace,
+					Shard:     event.Shard,
+					Timestamp: event.Timestamp,
 				}
 			} else if event.Type == binlogdatapb.VEventType_LASTPK {
 				var foundIndex = -1
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// A VStream subscription is a named VStream whose position is stored in the
+// global topo. The first VStream of a subscription creates it with the
+// position and filter of the request. The consumer then records the
+// positions it has processed with VStreamAck, and any later VStream of the
+// subscription resumes from the last acknowledged position, so the consumer
+// does not have to persist the position itself.
+
+// getSubscription returns the named subscription, or nil if it does not exist.
+func (vsm *vstreamManager) getSubscription(ctx context.Context, name string) (*vtctldatapb.VStreamSubscription, error) {
+	ts, err := vsm.toposerv.GetTopoServer()
+	if err != nil {
+		return nil, err
+	}
+	si, err := ts.GetVStreamSubscription(ctx, name)
+	switch {
+	case err == nil:
+		return si.VStreamSubscription, nil
+	case topo.IsErrType(err, topo.NoNode):
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+// createSubscription creates the named subscription, starting at vgtid.
+func (vsm *vstreamManager) createSubscription(ctx context.Context, name string, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter) error {
+	ts, err := vsm.toposerv.GetTopoServer()
+	if err != nil {
+		return err
+	}
+	err = ts.CreateVStreamSubscription(ctx, &vtctldatapb.VStreamSubscription{
+		Name:      name,
+		Filter:    filter,
+		Vgtid:     vgtid,
+		CreatedAt: protoutil.TimeToProto(time.Now()),
+	})
+	if topo.IsErrType(err, topo.NodeExists) {
+		return vterrors.Errorf(vtrpcpb.Code_ALREADY_EXISTS, "vstream subscription %s was created concurrently", name)
+	}
+	return err
+}
+
+// VStreamAck records vgtid as the position the consumer of the named
+// subscription processed the events up to. timestamp is the commit time of
+// the last processed event, in seconds, and is used to report the lag of the
+// subscription. It is ignored if zero.
+func (vsm *vstreamManager) VStreamAck(ctx context.Context, name string, vgtid *binlogdatapb.VGtid, timestamp int64) error {
+	if name == "" {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "subscription name is required")
+	}
+	if vgtid == nil || len(vgtid.ShardGtids) == 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "vgtid must have at least one value with a position")
+	}
+	ts, err := vsm.toposerv.GetTopoServer()
+	if err != nil {
+		return err
+	}
+	err = ts.UpdateVStreamSubscriptionFields(ctx, name, func(sub *vtctldatapb.VStreamSubscription) error {
+		sub.Vgtid = vgtid
+		sub.AcknowledgedAt = protoutil.TimeToProto(time.Now())
+		if timestamp > 0 {
+			sub.EventTime = protoutil.TimeToProto(time.Unix(timestamp, 0))
+		}
+		return nil
+	})
+	if topo.IsErrType(err, topo.NoNode) {
+		return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "vstream subscription %s not found", name)
+	}
+	return err
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestVStreamSubscription(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cell := "aa"
+	ks := "TestVStream"
+	_ = createSandbox(ks)
+	hc := discovery.NewFakeHealthCheck(nil)
+	st := getSandboxTopo(ctx, cell, ks, []string{"-20"})
+
+	vsm := newTestVStreamManager(ctx, hc, st, cell)
+	sbc0 := hc.AddTestTablet(cell, "1.1.1.1", 1001, ks, "-20", topodatapb.TabletType_PRIMARY, true, 1, nil)
+	addTabletToSandboxTopo(t, ctx, st, ks, "-20", sbc0.Tablet())
+	ts, err := st.GetTopoServer()
+	require.NoError(t, err)
+
+	flags := &vtgatepb.VStreamFlags{Subscription: "orders"}
+	vgtid := func(gtid string) *binlogdatapb.VGtid {
+		return &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{{Keyspace: ks, Shard: "-20", Gtid: gtid}}}
+	}
+
+	// The first stream creates the subscription at the requested position.
+	sbc0.AddVStreamEvents([]*binlogdatapb.VEvent{
+		{Type: binlogdatapb.VEventType_GTID, Gtid: "gtid01", Timestamp: 10},
+		{Type: binlogdatapb.VEventType_COMMIT, Timestamp: 10},
+	}, nil)
+	streamCtx, streamCancel := context.WithCancel(ctx)
+	ch := startVStream(streamCtx, t, vsm, vgtid("pos"), flags)
+	resp := <-ch
+	streamCancel()
+	require.Len(t, resp.Events, 2)
+	assert.Equal(t, binlogdatapb.VEventType_VGTID, resp.Events[0].Type)
+	assert.EqualValues(t, 10, resp.Events[0].Timestamp)
+
+	si, err := ts.GetVStreamSubscription(ctx, "orders")
+	require.NoError(t, err)
+	utils.MustMatch(t, vgtid("pos"), si.Vgtid)
+	assert.NotNil(t, si.CreatedAt)
+	assert.Nil(t, si.AcknowledgedAt)
+
+	// The consumer acknowledges the events it processed.
+	require.NoError(t, vsm.VStreamAck(ctx, "orders", resp.Events[0].Vgtid, resp.Events[0].Timestamp))
+	si, err = ts.GetVStreamSubscription(ctx, "orders")
+	require.NoError(t, err)
+	utils.MustMatch(t, vgtid("gtid01"), si.Vgtid)
+	assert.NotNil(t, si.AcknowledgedAt)
+	assert.EqualValues(t, 10, si.EventTime.Seconds)
+
+	// The next stream resumes from the acknowledged position, ignoring the
+	// position of the request.
+	sbc0.StartPos = "gtid01"
+	sbc0.AddVStreamEvents([]*binlogdatapb.VEvent{
+		{Type: binlogdatapb.VEventType_GTID, Gtid: "gtid02"},
+		{Type: binlogdatapb.VEventType_DDL},
+	}, nil)
+	ch = startVStream(ctx, t, vsm, vgtid("pos"), flags)
+	verifyEvents(t, ch, &binlogdatapb.VStreamResponse{Events: []*binlogdatapb.VEvent{
+		{Type: binlogdatapb.VEventType_VGTID, Vgtid: vgtid("gtid02")},
+		{Type: binlogdatapb.VEventType_DDL},
+	}})
+
+	err = vsm.VStreamAck(ctx, "doesnotexist", vgtid("gtid02"), 0)
+	assert.Equal(t, vtrpcpb.Code_NOT_FOUND, vterrors.Code(err), "unexpected error: %v", err)
+	err = vsm.VStreamAck(ctx, "orders", nil, 0)
+	assert.Equal(t, vtrpcpb.Code_INVALID_ARGUMENT, vterrors.Code(err), "unexpected error: %v", err)
+}
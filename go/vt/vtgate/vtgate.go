@@ -51,6 +51,8 @@ import (
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/queryrules"
+	"vitess.io/vitess/go/vt/vtgate/resultcache"
 	vtschema "vitess.io/vitess/go/vt/vtgate/schema"
 	"vitess.io/vitess/go/vt/vtgate/vtgateservice"
 )
@@ -115,6 +117,23 @@ var (
 	warmingReadsPercent      = 0
 	warmingReadsQueryTimeout = 5 * time.Second
 	warmingReadsConcurrency  = 500
+
+	// query rules related flags
+	queryRulesTopoCell = "global"
+	queryRulesTopoPath string
+
+	// result cache related flags
+	resultCacheMemory int64 = 32 * 1024 * 1024 // 32mb
+	resultCacheTTL          = 10 * time.Second
+
+	// hash join related flags
+	enableHashJoin            = true
+	hashJoinMemoryLimit int64 = 64 * 1024 * 1024 // 64mb
+	hashJoinSpillDir    string
+
+	// 2pc transaction resolver related flags
+	txResolverInterval   = time.Minute
+	txResolverAbandonAge = 5 * time.Minute
 )
 
 func registerFlags(fs *pflag.FlagSet) {
@@ -151,6 +170,15 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&warmingReadsPercent, "warming-reads-percent", 0, "Percentage of reads on the primary to forward to replicas. Useful for keeping buffer pools warm")
 	fs.IntVar(&warmingReadsConcurrency, "warming-reads-concurrency", 500, "Number of concurrent warming reads allowed")
 	fs.DurationVar(&warmingReadsQueryTimeout, "warming-reads-query-timeout", 5*time.Second, "Timeout of warming read queries")
+	fs.StringVar(&queryRulesTopoCell, "query-rules-topo-cell", queryRulesTopoCell, "Topo cell of the query rules file.")
+	fs.StringVar(&queryRulesTopoPath, "query-rules-topo-path", queryRulesTopoPath, "Path of the query rules file in the topo, evaluated against the plan of every query. Disabled if empty.")
+	fs.Int64Var(&resultCacheMemory, "result-cache-memory", resultCacheMemory, "Maximum memory in bytes of the result cache, used by the read-only queries with the RESULT_CACHE comment directive or on tables with result_cache set in the VSchema. 0 disables the result cache.")
+	fs.DurationVar(&resultCacheTTL, "result-cache-ttl", resultCacheTTL, "Time to live of the results in the result cache. Can be overridden by comment directive (RESULT_CACHE_TTL_MS)")
+	fs.BoolVar(&enableHashJoin, "enable-hash-join", enableHashJoin, "Let the planner use hash joins for cross-shard joins when they are estimated to be cheaper than nested loop joins. Queries with the ALLOW_HASH_JOIN comment directive use hash joins whenever possible.")
+	fs.Int64Var(&hashJoinMemoryLimit, "hash-join-memory-limit", hashJoinMemoryLimit, "Maximum memory in bytes a hash join can use for the rows of its build side before spilling them to temporary files. 0 disables spilling.")
+	fs.StringVar(&hashJoinSpillDir, "hash-join-spill-dir", hashJoinSpillDir, "Directory of the temporary files of the hash joins spilling to disk. Defaults to the directory for temporary files of the system.")
+	fs.DurationVar(&txResolverInterval, "tx-resolver-interval", txResolverInterval, "Interval at which vtgate looks for abandoned 2PC transactions on the primary tablets and resolves them. Only used with --transaction_mode=TWOPC. 0 disables the resolver.")
+	fs.DurationVar(&txResolverAbandonAge, "tx-resolver-abandon-age", txResolverAbandonAge, "Age after which an unresolved 2PC transaction is considered abandoned and resolved by the vtgate transaction resolver. Must be at least 1m0s.")
 
 	_ = fs.String("schema_change_signal_user", "", "User to be used to send down query to vttablet to retrieve schema changes")
 	_ = fs.MarkDeprecated("schema_change_signal_user", "schema tracking uses an internal api and does not require a user to be specified")
@@ -338,6 +366,28 @@ func Init(
 		st.RegisterSignalReceiver(executor.vm.Rebuild)
 	}
 
+	if queryRulesTopoPath != "" {
+		src, err := queryrules.NewTopoSource(ts, queryRulesTopoCell, queryRulesTopoPath, executor.SetQueryRules)
+		if err != nil {
+			log.Fatalf("Unable to watch query rules: %v", err)
+		}
+		servenv.OnRun(src.Start)
+		servenv.OnTerm(src.Stop)
+	}
+
+	if resultCacheMemory > 0 {
+		executor.enableResultCache(resultcache.New(resultCacheMemory), vsm.VStream)
+	}
+
+	if tc.mode == vtgatepb.TransactionMode_TWOPC && txResolverInterval > 0 {
+		if err := validateTxResolverAbandonAge(txResolverAbandonAge); err != nil {
+			log.Fatalf("invalid --tx-resolver-abandon-age: %v", err)
+		}
+		txr := newTxResolver(tc)
+		servenv.OnRun(txr.Start)
+		servenv.OnTerm(txr.Stop)
+	}
+
 	// TODO: call serv.WatchSrvVSchema here
 
 	vtgateInst := newVTGate(executor, resolver, vsm, tc, gw)
@@ -583,6 +633,12 @@ func (vtg *VTGate) VStream(ctx context.Context, tabletType topodatapb.TabletType
 	return vtg.vsm.VStream(ctx, tabletType, vgtid, filter, flags, send)
 }
 
+// VStreamAck records the position the consumer of a VStream subscription
+// processed the events up to.
+func (vtg *VTGate) VStreamAck(ctx context.Context, subscription string, vgtid *binlogdatapb.VGtid, timestamp int64) error {
+	return vtg.vsm.VStreamAck(ctx, subscription, vgtid, timestamp)
+}
+
 // GetGatewayCacheStatus returns a displayable version of the Gateway cache.
 func (vtg *VTGate) GetGatewayCacheStatus() TabletCacheStatusList {
 	return vtg.gw.CacheStatus()
@@ -593,6 +649,11 @@ func (vtg *VTGate) VSchemaStats() *VSchemaStats {
 	return vtg.executor.VSchemaStats()
 }
 
+// QueryRules returns the query rules evaluated against the plan of every query.
+func (vtg *VTGate) QueryRules() *queryrules.Rules {
+	return vtg.executor.QueryRules()
+}
+
 func truncateErrorStrings(data map[string]any) map[string]any {
 	ret := map[string]any{}
 	if terseErrors {
@@ -63,6 +63,7 @@ var (
 	recoveryPollDuration           = 1 * time.Second
 	ersEnabled                     = true
 	convertTabletsWithErrantGTIDs  = false
+	dryRun                         = false
 )
 
 // RegisterFlags registers the flags required by VTOrc
@@ -82,6 +83,7 @@ func RegisterFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&recoveryPollDuration, "recovery-poll-duration", recoveryPollDuration, "Timer duration on which VTOrc polls its database to run a recovery")
 	fs.BoolVar(&ersEnabled, "allow-emergency-reparent", ersEnabled, "Whether VTOrc should be allowed to run emergency reparent operation when it detects a dead primary")
 	fs.BoolVar(&convertTabletsWithErrantGTIDs, "change-tablets-with-errant-gtid-to-drained", convertTabletsWithErrantGTIDs, "Whether VTOrc should be changing the type of tablets with errant GTIDs to DRAINED")
+	fs.BoolVar(&dryRun, "dry-run", dryRun, "Whether VTOrc should only record the recoveries it would run, instead of running them. The intended actions are available at /api/dry-run-recoveries")
 }
 
 // Configuration makes for vtorc configuration input, which can be provided by user via JSON formatted file.
@@ -153,6 +155,16 @@ func SetConvertTabletWithErrantGTIDs(val bool) {
 	convertTabletsWithErrantGTIDs = val
 }
 
+// DryRun reports whether VTOrc should only record the recoveries it would run, instead of running them.
+func DryRun() bool {
+	return dryRun
+}
+
+// SetDryRun sets the value for the dryRun variable. This should only be used from tests.
+func SetDryRun(val bool) {
+	dryRun = val
+}
+
 // LogConfigValues is used to log the config values.
 func LogConfigValues() {
 	b, _ := json.MarshalIndent(Config, "", "\t")
@@ -384,6 +384,24 @@ CREATE TABLE vitess_shard (
 	PRIMARY KEY (keyspace, shard)
 )`,
 	`
+DROP TABLE IF EXISTS dry_run_recovery
+`,
+	`
+CREATE TABLE dry_run_recovery (
+	dry_run_recovery_id integer,
+	detected_at timestamp not null default (''),
+	alias varchar(256) NOT NULL,
+	keyspace varchar(128) NOT NULL,
+	shard varchar(128) NOT NULL,
+	analysis varchar(128) NOT NULL,
+	recovery_name varchar(128) NOT NULL,
+	approximate_primary_candidate varchar(256) NOT NULL,
+	replicas_to_repoint text NOT NULL,
+	errant_gtids text NOT NULL,
+	actions text NOT NULL,
+	PRIMARY KEY (dry_run_recovery_id)
+)`,
+	`
 CREATE INDEX source_host_port_idx_database_instance_database_instance on database_instance (source_host, source_port)
 	`,
 	`
@@ -414,6 +432,9 @@ CREATE INDEX uid_idx_topology_recovery ON topology_recovery(uid)
 CREATE INDEX recovery_uid_idx_topology_recovery_steps ON topology_recovery_steps(recovery_uid)
 	`,
 	`
+CREATE INDEX keyspace_shard_idx_dry_run_recovery ON dry_run_recovery(keyspace, shard)
+	`,
+	`
 CREATE UNIQUE INDEX alias_active_recoverable_uidx_topology_failure_detection ON topology_failure_detection (alias, in_active_period, end_active_period_unixtime, is_actionable)
 	`,
 }
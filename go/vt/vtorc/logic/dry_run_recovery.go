@@ -0,0 +1,232 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/external/golib/sqlutils"
+	"vitess.io/vitess/go/vt/log"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil/promotionrule"
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/db"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+)
+
+// DryRunRecovery represents an entry in the dry_run_recovery table. It describes
+// a recovery that VTOrc would have run, and the actions the recovery would have taken,
+// based on the information VTOrc has in its database.
+type DryRunRecovery struct {
+	ID           int64
+	DetectedAt   string
+	TabletAlias  string
+	Keyspace     string
+	Shard        string
+	Analysis     inst.AnalysisCode
+	RecoveryName string
+	// ApproximatePrimaryCandidate is the tablet a reparent would likely promote. It is chosen from the
+	// replication positions VTOrc last read, so the actual reparent may promote another tablet.
+	ApproximatePrimaryCandidate string
+	ReplicasToRepoint           []string
+	ErrantGTIDs                 string
+	Actions                     []string
+}
+
+func (dryRunRecovery *DryRunRecovery) addAction(format string, args ...any) {
+	dryRunRecovery.Actions = append(dryRunRecovery.Actions, fmt.Sprintf(format, args...))
+}
+
+// recordDryRunRecovery computes and records the actions the given recovery would take, instead of running it.
+func recordDryRunRecovery(analysisEntry *inst.ReplicationAnalysis, recoveryFunctionCode recoveryFunction) error {
+	dryRunRecovery, err := planDryRunRecovery(analysisEntry, recoveryFunctionCode)
+	if err != nil {
+		return err
+	}
+	log.Infof("executeCheckAndRecoverFunction: dry-run: Analysis: %+v, Tablet: %+v: would run %v: %v",
+		analysisEntry.Analysis, analysisEntry.AnalyzedInstanceAlias, dryRunRecovery.RecoveryName, dryRunRecovery.Actions)
+	return writeDryRunRecovery(dryRunRecovery)
+}
+
+// planDryRunRecovery computes the actions the given recovery would take. It only reads VTOrc's database,
+// so the plan may differ from what the recovery would do after refreshing the information of the shard.
+func planDryRunRecovery(analysisEntry *inst.ReplicationAnalysis, recoveryFunctionCode recoveryFunction) (*DryRunRecovery, error) {
+	dryRunRecovery := &DryRunRecovery{
+		TabletAlias:       analysisEntry.AnalyzedInstanceAlias,
+		Keyspace:          analysisEntry.AnalyzedKeyspace,
+		Shard:             analysisEntry.AnalyzedShard,
+		Analysis:          analysisEntry.Analysis,
+		RecoveryName:      getRecoverFunctionName(recoveryFunctionCode),
+		ReplicasToRepoint: []string{},
+	}
+
+	switch recoveryFunctionCode {
+	case recoverDeadPrimaryFunc, recoverPrimaryTabletDeletedFunc, electNewPrimaryFunc:
+		if err := planReparent(dryRunRecovery, recoveryFunctionCode); err != nil {
+			return nil, err
+		}
+	case recoverPrimaryHasPrimaryFunc:
+		dryRunRecovery.addAction("reset replication parameters on %v", dryRunRecovery.TabletAlias)
+	case fixPrimaryFunc:
+		dryRunRecovery.addAction("undo demote primary on %v, setting it read-write and fixing its semi-sync settings", dryRunRecovery.TabletAlias)
+	case fixReplicaFunc:
+		primaryTablet, err := shardPrimary(dryRunRecovery.Keyspace, dryRunRecovery.Shard)
+		if err != nil {
+			return nil, err
+		}
+		primaryAlias := topoproto.TabletAliasString(primaryTablet.Alias)
+		dryRunRecovery.ReplicasToRepoint = append(dryRunRecovery.ReplicasToRepoint, dryRunRecovery.TabletAlias)
+		dryRunRecovery.addAction("set %v read-only", dryRunRecovery.TabletAlias)
+		dryRunRecovery.addAction("set the replication source of %v to %v", dryRunRecovery.TabletAlias, primaryAlias)
+	case recoverErrantGTIDDetectedFunc:
+		instance, _, err := inst.ReadInstance(dryRunRecovery.TabletAlias)
+		if err != nil {
+			return nil, err
+		}
+		if instance != nil {
+			dryRunRecovery.ErrantGTIDs = instance.GtidErrant
+		}
+		dryRunRecovery.addAction("change the type of %v to %v because of errant GTIDs %v", dryRunRecovery.TabletAlias, topodatapb.TabletType_DRAINED, dryRunRecovery.ErrantGTIDs)
+	default:
+		dryRunRecovery.addAction("no action")
+	}
+	return dryRunRecovery, nil
+}
+
+// planReparent computes the approximate primary candidate and the replicas to repoint for ERS and PRS based recoveries.
+func planReparent(dryRunRecovery *DryRunRecovery, recoveryFunctionCode recoveryFunction) error {
+	tablets, err := readShardTablets(dryRunRecovery.Keyspace, dryRunRecovery.Shard)
+	if err != nil {
+		return err
+	}
+	durabilityPolicy, err := inst.GetDurabilityPolicy(dryRunRecovery.Keyspace)
+	if err != nil {
+		return err
+	}
+
+	reparentName := "PlannedReparentShard"
+	var oldPrimary *topodatapb.Tablet
+	if recoveryFunctionCode != electNewPrimaryFunc {
+		reparentName = "EmergencyReparentShard"
+		for _, tablet := range tablets {
+			if topoproto.TabletAliasString(tablet.Alias) == dryRunRecovery.TabletAlias {
+				oldPrimary = tablet
+			}
+		}
+	}
+
+	candidate, err := choosePrimaryCandidate(tablets, oldPrimary, durabilityPolicy)
+	if err != nil {
+		return err
+	}
+	if candidate == nil {
+		dryRunRecovery.addAction("%v would fail: no valid candidate to promote in %v/%v", reparentName, dryRunRecovery.Keyspace, dryRunRecovery.Shard)
+		return nil
+	}
+	dryRunRecovery.ApproximatePrimaryCandidate = topoproto.TabletAliasString(candidate.Alias)
+	dryRunRecovery.addAction("run %v on %v/%v, likely promoting %v", reparentName, dryRunRecovery.Keyspace, dryRunRecovery.Shard, dryRunRecovery.ApproximatePrimaryCandidate)
+
+	for _, tablet := range tablets {
+		alias := topoproto.TabletAliasString(tablet.Alias)
+		if alias == dryRunRecovery.ApproximatePrimaryCandidate || (oldPrimary != nil && alias == dryRunRecovery.TabletAlias) {
+			continue
+		}
+		dryRunRecovery.ReplicasToRepoint = append(dryRunRecovery.ReplicasToRepoint, alias)
+		dryRunRecovery.addAction("set the replication source of %v to %v", alias, dryRunRecovery.ApproximatePrimaryCandidate)
+	}
+	return nil
+}
+
+// choosePrimaryCandidate approximates the tablet a reparent would promote, among the reachable replicas of the shard
+// that the durability policy allows to be promoted and that have no errant GTIDs. The candidates are ranked with
+// reparentutil.SortTabletsForReparent, as ERS and PRS do, but on the executed GTID sets VTOrc last read. Unlike the
+// reparent, it doesn't stop replication and wait for the relay logs to be applied, nor run ERS's checks on the
+// intermediate source, so the reparent may pick another tablet.
+// When oldPrimary is set, it is not considered, and candidates in other cells are skipped
+// if VTOrc is configured to prevent cross cell failovers.
+func choosePrimaryCandidate(tablets []*topodatapb.Tablet, oldPrimary *topodatapb.Tablet, durabilityPolicy reparentutil.Durabler) (*topodatapb.Tablet, error) {
+	var (
+		candidates []*topodatapb.Tablet
+		positions  []replication.Position
+	)
+	for _, tablet := range tablets {
+		if tablet.Type != topodatapb.TabletType_REPLICA {
+			continue
+		}
+		if oldPrimary != nil {
+			if topoproto.TabletAliasEqual(tablet.Alias, oldPrimary.Alias) {
+				continue
+			}
+			if config.Config.PreventCrossDataCenterPrimaryFailover && tablet.Alias.Cell != oldPrimary.Alias.Cell {
+				continue
+			}
+		}
+		if reparentutil.PromotionRule(durabilityPolicy, tablet) == promotionrule.MustNot {
+			continue
+		}
+		instance, found, err := inst.ReadInstance(topoproto.TabletAliasString(tablet.Alias))
+		if err != nil && instance == nil {
+			return nil, err
+		}
+		if !found || !instance.IsLastCheckValid || instance.GtidErrant != "" {
+			continue
+		}
+		gtidSet, err := replication.ParseMysql56GTIDSet(instance.ExecutedGtidSet)
+		if err != nil {
+			log.Warningf("dry-run: cannot parse the executed GTID set of %v, skipping it: %v", instance.InstanceAlias, err)
+			continue
+		}
+		candidates = append(candidates, tablet)
+		positions = append(positions, replication.Position{GTIDSet: gtidSet})
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	if err := reparentutil.SortTabletsForReparent(candidates, positions, durabilityPolicy); err != nil {
+		return nil, err
+	}
+	return candidates[0], nil
+}
+
+// readShardTablets reads the tablets of the given keyspace-shard from the vtorc backend, ordered by alias.
+func readShardTablets(keyspace string, shard string) ([]*topodatapb.Tablet, error) {
+	query := `SELECT
+		info
+	FROM
+		vitess_tablet
+	WHERE
+		keyspace = ? AND shard = ?
+	ORDER BY
+		alias
+`
+	var tablets []*topodatapb.Tablet
+	err := db.QueryVTOrc(query, sqlutils.Args(keyspace, shard), func(m sqlutils.RowMap) error {
+		tablet := &topodatapb.Tablet{}
+		opts := prototext.UnmarshalOptions{DiscardUnknown: true}
+		if err := opts.Unmarshal([]byte(m.GetString("info")), tablet); err != nil {
+			return err
+		}
+		tablets = append(tablets, tablet)
+		return nil
+	})
+	return tablets, err
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"encoding/json"
+
+	"vitess.io/vitess/go/vt/external/golib/sqlutils"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/db"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+)
+
+// writeDryRunRecovery writes down a recovery that VTOrc would have run.
+func writeDryRunRecovery(dryRunRecovery *DryRunRecovery) error {
+	replicasToRepoint, err := json.Marshal(dryRunRecovery.ReplicasToRepoint)
+	if err != nil {
+		return err
+	}
+	actions, err := json.Marshal(dryRunRecovery.Actions)
+	if err != nil {
+		return err
+	}
+	sqlResult, err := db.ExecVTOrc(`
+			insert
+				into dry_run_recovery (
+					dry_run_recovery_id,
+					detected_at,
+					alias,
+					keyspace,
+					shard,
+					analysis,
+					recovery_name,
+					approximate_primary_candidate,
+					replicas_to_repoint,
+					errant_gtids,
+					actions
+				) values (?, now(), ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`,
+		sqlutils.NilIfZero(dryRunRecovery.ID),
+		dryRunRecovery.TabletAlias,
+		dryRunRecovery.Keyspace,
+		dryRunRecovery.Shard,
+		string(dryRunRecovery.Analysis),
+		dryRunRecovery.RecoveryName,
+		dryRunRecovery.ApproximatePrimaryCandidate,
+		string(replicasToRepoint),
+		dryRunRecovery.ErrantGTIDs,
+		string(actions),
+	)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	dryRunRecovery.ID, err = sqlResult.LastInsertId()
+	if err != nil {
+		log.Error(err)
+	}
+	return err
+}
+
+// ReadDryRunRecoveries reads the recoveries recorded in dry-run mode, most recent first.
+// It supports filtering by keyspace and shard, and paging.
+func ReadDryRunRecoveries(keyspace string, shard string, page int) ([]*DryRunRecovery, error) {
+	query := `
+		select
+			dry_run_recovery_id,
+			detected_at,
+			alias,
+			keyspace,
+			shard,
+			analysis,
+			recovery_name,
+			approximate_primary_candidate,
+			replicas_to_repoint,
+			errant_gtids,
+			actions
+		from
+			dry_run_recovery
+		where
+			keyspace LIKE (CASE WHEN ? = '' THEN '%' ELSE ? END)
+			and shard LIKE (CASE WHEN ? = '' THEN '%' ELSE ? END)
+		order by
+			dry_run_recovery_id desc
+		limit ?
+		offset ?
+		`
+	args := sqlutils.Args(keyspace, keyspace, shard, shard, config.AuditPageSize, page*config.AuditPageSize)
+	var res []*DryRunRecovery
+	err := db.QueryVTOrc(query, args, func(m sqlutils.RowMap) error {
+		dryRunRecovery := &DryRunRecovery{
+			ID:                          m.GetInt64("dry_run_recovery_id"),
+			DetectedAt:                  m.GetString("detected_at"),
+			TabletAlias:                 m.GetString("alias"),
+			Keyspace:                    m.GetString("keyspace"),
+			Shard:                       m.GetString("shard"),
+			Analysis:                    inst.AnalysisCode(m.GetString("analysis")),
+			RecoveryName:                m.GetString("recovery_name"),
+			ApproximatePrimaryCandidate: m.GetString("approximate_primary_candidate"),
+			ErrantGTIDs:                 m.GetString("errant_gtids"),
+		}
+		if err := json.Unmarshal([]byte(m.GetString("replicas_to_repoint")), &dryRunRecovery.ReplicasToRepoint); err != nil {
+			return err
+		}
+		if err := json.Unmarshal([]byte(m.GetString("actions")), &dryRunRecovery.Actions); err != nil {
+			return err
+		}
+		res = append(res, dryRunRecovery)
+		return nil
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return res, err
+}
+
+// ExpireDryRunRecoveryHistory removes old rows from the dry_run_recovery table
+func ExpireDryRunRecoveryHistory() error {
+	return inst.ExpireTableData("dry_run_recovery", "detected_at")
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/db"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+)
+
+// saveDryRunShard saves a keyspace with the given durability policy, the given tablets and instances
+// with the given executed GTID sets in the VTOrc database.
+func saveDryRunShard(t *testing.T, durabilityPolicy string, tablets []*topodatapb.Tablet, executedGtidSets map[string]string) {
+	t.Helper()
+	keyspaceInfo := &topo.KeyspaceInfo{
+		Keyspace: &topodatapb.Keyspace{DurabilityPolicy: durabilityPolicy},
+	}
+	keyspaceInfo.SetKeyspaceName(keyspace)
+	require.NoError(t, inst.SaveKeyspace(keyspaceInfo))
+	for _, tablet := range tablets {
+		require.NoError(t, inst.SaveTablet(tablet))
+		alias := topoproto.TabletAliasString(tablet.Alias)
+		gtidSet, ok := executedGtidSets[alias]
+		if !ok {
+			continue
+		}
+		_, err := db.ExecVTOrc(`insert into database_instance (
+				alias, hostname, port, last_checked, last_seen, executed_gtid_set, server_id, version, binlog_format, log_bin, log_replica_updates,
+				binary_log_file, binary_log_pos, source_host, source_port, replica_sql_running, replica_io_running,
+				source_log_file, read_source_log_pos, relay_source_log_file, exec_source_log_pos
+			) values (?, ?, ?, now(), now(), ?, 0, '', '', 1, 1, '', 0, '', 0, 1, 1, '', 0, '', 0)`,
+			alias, tablet.MysqlHostname, tablet.MysqlPort, gtidSet)
+		require.NoError(t, err)
+	}
+}
+
+func TestPlanDryRunRecovery(t *testing.T) {
+	defer db.ClearVTOrcDatabase()
+
+	tab104 := proto.Clone(tab101).(*topodatapb.Tablet)
+	tab104.Alias.Uid = 104
+	tab104.MysqlPort = 104
+	tab105 := proto.Clone(tab101).(*topodatapb.Tablet)
+	tab105.Alias.Cell = "zone-2"
+	tab105.Alias.Uid = 105
+	tab105.MysqlPort = 105
+	saveDryRunShard(t, "semi_sync", []*topodatapb.Tablet{tab100, tab101, tab102, tab104, tab105}, map[string]string{
+		"zone-1-0000000101": "00000000-0000-0000-0000-000000000001:1-10",
+		"zone-1-0000000102": "00000000-0000-0000-0000-000000000001:1-20",
+		"zone-1-0000000104": "00000000-0000-0000-0000-000000000001:1-15",
+		"zone-2-0000000105": "00000000-0000-0000-0000-000000000001:1-18",
+	})
+
+	tests := []struct {
+		name                     string
+		analysisEntry            *inst.ReplicationAnalysis
+		recoveryFunctionCode     recoveryFunction
+		preventCrossCellFailover bool
+		wantCandidate            string
+		wantReplicasToRepoint    []string
+		wantActions              []string
+	}{
+		{
+			name: "dead primary promotes the most advanced promotable replica",
+			analysisEntry: &inst.ReplicationAnalysis{
+				AnalyzedInstanceAlias: "zone-1-0000000100",
+				Analysis:              inst.DeadPrimary,
+			},
+			recoveryFunctionCode:  recoverDeadPrimaryFunc,
+			wantCandidate:         "zone-2-0000000105",
+			wantReplicasToRepoint: []string{"zone-1-0000000101", "zone-1-0000000102", "zone-1-0000000104"},
+			wantActions: []string{
+				"run EmergencyReparentShard on ks/0, likely promoting zone-2-0000000105",
+				"set the replication source of zone-1-0000000101 to zone-2-0000000105",
+				"set the replication source of zone-1-0000000102 to zone-2-0000000105",
+				"set the replication source of zone-1-0000000104 to zone-2-0000000105",
+			},
+		}, {
+			name: "dead primary with cross cell failovers prevented",
+			analysisEntry: &inst.ReplicationAnalysis{
+				AnalyzedInstanceAlias: "zone-1-0000000100",
+				Analysis:              inst.DeadPrimary,
+			},
+			recoveryFunctionCode:     recoverDeadPrimaryFunc,
+			preventCrossCellFailover: true,
+			wantCandidate:            "zone-1-0000000104",
+			wantReplicasToRepoint:    []string{"zone-1-0000000101", "zone-1-0000000102", "zone-2-0000000105"},
+			wantActions: []string{
+				"run EmergencyReparentShard on ks/0, likely promoting zone-1-0000000104",
+				"set the replication source of zone-1-0000000101 to zone-1-0000000104",
+				"set the replication source of zone-1-0000000102 to zone-1-0000000104",
+				"set the replication source of zone-2-0000000105 to zone-1-0000000104",
+			},
+		}, {
+			name: "fix replica repoints it to the shard primary",
+			analysisEntry: &inst.ReplicationAnalysis{
+				AnalyzedInstanceAlias: "zone-1-0000000101",
+				Analysis:              inst.ConnectedToWrongPrimary,
+			},
+			recoveryFunctionCode:  fixReplicaFunc,
+			wantReplicasToRepoint: []string{"zone-1-0000000101"},
+			wantActions: []string{
+				"set zone-1-0000000101 read-only",
+				"set the replication source of zone-1-0000000101 to zone-1-0000000100",
+			},
+		}, {
+			name: "primary has primary",
+			analysisEntry: &inst.ReplicationAnalysis{
+				AnalyzedInstanceAlias: "zone-1-0000000100",
+				Analysis:              inst.PrimaryHasPrimary,
+			},
+			recoveryFunctionCode:  recoverPrimaryHasPrimaryFunc,
+			wantReplicasToRepoint: []string{},
+			wantActions:           []string{"reset replication parameters on zone-1-0000000100"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldPreventCrossCellFailover := config.Config.PreventCrossDataCenterPrimaryFailover
+			defer func() {
+				config.Config.PreventCrossDataCenterPrimaryFailover = oldPreventCrossCellFailover
+			}()
+			config.Config.PreventCrossDataCenterPrimaryFailover = tt.preventCrossCellFailover
+			tt.analysisEntry.AnalyzedKeyspace = keyspace
+			tt.analysisEntry.AnalyzedShard = shard
+
+			dryRunRecovery, err := planDryRunRecovery(tt.analysisEntry, tt.recoveryFunctionCode)
+			require.NoError(t, err)
+			require.Equal(t, getRecoverFunctionName(tt.recoveryFunctionCode), dryRunRecovery.RecoveryName)
+			require.Equal(t, tt.wantCandidate, dryRunRecovery.ApproximatePrimaryCandidate)
+			require.Equal(t, tt.wantReplicasToRepoint, dryRunRecovery.ReplicasToRepoint)
+			require.Equal(t, tt.wantActions, dryRunRecovery.Actions)
+		})
+	}
+}
+
+func TestPlanDryRunRecoverySkipsErrantGTIDs(t *testing.T) {
+	defer db.ClearVTOrcDatabase()
+
+	tab104 := proto.Clone(tab101).(*topodatapb.Tablet)
+	tab104.Alias.Uid = 104
+	tab104.MysqlPort = 104
+	saveDryRunShard(t, "semi_sync", []*topodatapb.Tablet{tab100, tab101, tab104}, map[string]string{
+		"zone-1-0000000101": "00000000-0000-0000-0000-000000000001:1-10",
+		"zone-1-0000000104": "00000000-0000-0000-0000-000000000001:1-10,00000000-0000-0000-0000-000000000002:1",
+	})
+	// Like ERS, the dry run doesn't promote a replica with errant GTIDs, even if it is the most advanced.
+	_, err := db.ExecVTOrc(`update database_instance set gtid_errant = ? where alias = ?`, "00000000-0000-0000-0000-000000000002:1", "zone-1-0000000104")
+	require.NoError(t, err)
+
+	dryRunRecovery, err := planDryRunRecovery(&inst.ReplicationAnalysis{
+		AnalyzedInstanceAlias: "zone-1-0000000100",
+		AnalyzedKeyspace:      keyspace,
+		AnalyzedShard:         shard,
+		Analysis:              inst.DeadPrimary,
+	}, recoverDeadPrimaryFunc)
+	require.NoError(t, err)
+	require.Equal(t, "zone-1-0000000101", dryRunRecovery.ApproximatePrimaryCandidate)
+}
+
+func TestDryRunRecoveryRecorded(t *testing.T) {
+	defer db.ClearVTOrcDatabase()
+	oldDryRun := config.DryRun()
+	defer config.SetDryRun(oldDryRun)
+	config.SetDryRun(true)
+
+	saveDryRunShard(t, "none", []*topodatapb.Tablet{tab100, tab101}, nil)
+	analysisEntry := &inst.ReplicationAnalysis{
+		AnalyzedInstanceAlias: "zone-1-0000000101",
+		AnalyzedKeyspace:      keyspace,
+		AnalyzedShard:         shard,
+		ClusterDetails: inst.ClusterInfo{
+			Keyspace: keyspace,
+			Shard:    shard,
+		},
+		Analysis: inst.ReplicationStopped,
+	}
+
+	// The shard isn't locked and the recovery isn't run in dry-run mode, so
+	// this doesn't need a topo server or tablet manager.
+	require.NoError(t, executeCheckAndRecoverFunction(analysisEntry))
+	// The second time around, the failure detection is already registered, so nothing is recorded.
+	require.NoError(t, executeCheckAndRecoverFunction(analysisEntry))
+
+	recoveries, err := ReadDryRunRecoveries(keyspace, shard, 0)
+	require.NoError(t, err)
+	require.Len(t, recoveries, 1)
+	require.Greater(t, recoveries[0].ID, int64(0))
+	require.Equal(t, "zone-1-0000000101", recoveries[0].TabletAlias)
+	require.Equal(t, inst.ReplicationStopped, recoveries[0].Analysis)
+	require.Equal(t, FixReplicaRecoveryName, recoveries[0].RecoveryName)
+	require.Equal(t, []string{"zone-1-0000000101"}, recoveries[0].ReplicasToRepoint)
+	require.Len(t, recoveries[0].Actions, 2)
+
+	recoveries, err = ReadDryRunRecoveries("other", "", 0)
+	require.NoError(t, err)
+	require.Empty(t, recoveries)
+}
@@ -34,6 +34,7 @@ import (
 	"vitess.io/vitess/go/vt/vtctl/reparentutil"
 	"vitess.io/vitess/go/vt/vtorc/config"
 	"vitess.io/vitess/go/vt/vtorc/inst"
+	"vitess.io/vitess/go/vt/vtorc/notify"
 	"vitess.io/vitess/go/vt/vtorc/util"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 )
@@ -392,6 +393,7 @@ func checkAndExecuteFailureDetectionProcesses(analysisEntry *inst.ReplicationAna
 		return false, false, nil
 	}
 	log.Infof("topology_recovery: detected %+v failure on %+v", analysisEntry.Analysis, analysisEntry.AnalyzedInstanceAlias)
+	notify.Notify(newNotifyEvent(notify.AnalysisDetected, analysisEntry))
 	return true, false, nil
 }
 
@@ -572,6 +574,10 @@ func runEmergentOperations(analysisEntry *inst.ReplicationAnalysis) {
 		go emergentlyReadTopologyInstance(analysisEntry.AnalyzedInstanceAlias, analysisEntry.Analysis)
 		go emergentlyReadTopologyInstanceReplicas(analysisEntry.AnalyzedInstanceHostname, analysisEntry.AnalyzedInstancePort, analysisEntry.Analysis)
 	case inst.UnreachablePrimaryWithLaggingReplicas:
+		// Restarting replication changes the state of the replicas, which isn't allowed in dry-run mode.
+		if config.DryRun() {
+			return
+		}
 		go emergentlyRestartReplicationOnTopologyInstanceReplicas(analysisEntry.AnalyzedInstanceHostname, analysisEntry.AnalyzedInstancePort, analysisEntry.AnalyzedInstanceAlias, analysisEntry.Analysis)
 	case inst.LockedSemiSyncPrimaryHypothesis:
 		go emergentlyReadTopologyInstance(analysisEntry.AnalyzedInstanceAlias, analysisEntry.Analysis)
@@ -613,7 +619,7 @@ func executeCheckAndRecoverFunction(analysisEntry *inst.ReplicationAnalysis) (er
 	// At this point we have validated there's a failure scenario for which we have a recovery path.
 
 	// Initiate detection:
-	_, _, err = checkAndExecuteFailureDetectionProcesses(analysisEntry)
+	detectionRegistrationSuccess, _, err := checkAndExecuteFailureDetectionProcesses(analysisEntry)
 	if err != nil {
 		log.Errorf("executeCheckAndRecoverFunction: error on failure detection: %+v", err)
 		return err
@@ -621,6 +627,15 @@ func executeCheckAndRecoverFunction(analysisEntry *inst.ReplicationAnalysis) (er
 	// We don't mind whether detection really executed the processes or not
 	// (it may have been silenced due to previous detection). We only care there's no error.
 
+	// In dry-run mode, we only record what the recovery would do. We record it once per detection,
+	// otherwise the same recovery would be recorded on every recovery poll.
+	if config.DryRun() {
+		if isActionableRecovery && detectionRegistrationSuccess {
+			return recordDryRunRecovery(analysisEntry, checkAndRecoverFunctionCode)
+		}
+		return nil
+	}
+
 	// We're about to embark on recovery shortly...
 
 	// Check for recovery being disabled globally
@@ -716,6 +731,7 @@ func executeCheckAndRecoverFunction(analysisEntry *inst.ReplicationAnalysis) (er
 	} else {
 		recoveriesSuccessfulCounter.Add(recoveryName, 1)
 	}
+	notifyRecoveryCompletion(analysisEntry, recoveryName, topologyRecovery, err)
 	if topologyRecovery == nil {
 		return err
 	}
@@ -739,6 +755,45 @@ func executeCheckAndRecoverFunction(analysisEntry *inst.ReplicationAnalysis) (er
 	return err
 }
 
+// newNotifyEvent creates a notification event describing the given analysis.
+func newNotifyEvent(eventType notify.EventType, analysisEntry *inst.ReplicationAnalysis) *notify.Event {
+	return &notify.Event{
+		Type:        eventType,
+		Analysis:    string(analysisEntry.Analysis),
+		TabletAlias: analysisEntry.AnalyzedInstanceAlias,
+		Keyspace:    analysisEntry.AnalyzedKeyspace,
+		Shard:       analysisEntry.AnalyzedShard,
+	}
+}
+
+// notifyRecoveryStarted sends a notification for a recovery that was just registered.
+func notifyRecoveryStarted(topologyRecovery *TopologyRecovery) {
+	analysisEntry := &topologyRecovery.AnalysisEntry
+	ev := newNotifyEvent(notify.RecoveryStarted, analysisEntry)
+	ev.RecoveryName = getRecoverFunctionName(getCheckAndRecoverFunctionCode(analysisEntry.Analysis, analysisEntry.AnalyzedInstanceAlias))
+	ev.RecoveryUID = topologyRecovery.UID
+	notify.Notify(ev)
+}
+
+// notifyRecoveryCompletion sends a notification for a recovery that was attempted, with its outcome.
+func notifyRecoveryCompletion(analysisEntry *inst.ReplicationAnalysis, recoveryName string, topologyRecovery *TopologyRecovery, err error) {
+	eventType := notify.RecoverySucceeded
+	if err != nil {
+		eventType = notify.RecoveryFailed
+	}
+	ev := newNotifyEvent(eventType, analysisEntry)
+	ev.RecoveryName = recoveryName
+	if topologyRecovery != nil {
+		ev.RecoveryUID = topologyRecovery.UID
+		ev.SuccessorAlias = topologyRecovery.SuccessorAlias
+		ev.Errors = append(ev.Errors, topologyRecovery.AllErrors...)
+	}
+	if err != nil {
+		ev.Errors = append(ev.Errors, err.Error())
+	}
+	notify.Notify(ev)
+}
+
 // checkIfAlreadyFixed checks whether the problem that the analysis entry represents has already been fixed by another agent or not
 func checkIfAlreadyFixed(analysisEntry *inst.ReplicationAnalysis) (bool, error) {
 	// Run a replication analysis again. We will check if the problem persisted
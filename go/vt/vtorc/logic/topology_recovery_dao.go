@@ -216,6 +216,7 @@ func AttemptRecoveryRegistration(analysisEntry *inst.ReplicationAnalysis, failIf
 		log.Error(err)
 		return nil, err
 	}
+	notifyRecoveryStarted(topologyRecovery)
 	return topologyRecovery, nil
 }
 
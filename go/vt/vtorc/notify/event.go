@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notify sends structured events about the problems VTOrc detects
+// and the recoveries it runs to external sinks, such as HTTP webhooks and
+// local files.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/template"
+	"time"
+)
+
+// EventType is the kind of event being notified.
+type EventType string
+
+const (
+	// AnalysisDetected is sent when VTOrc registers a new failure detection.
+	AnalysisDetected EventType = "AnalysisDetected"
+	// RecoveryStarted is sent when VTOrc registers a recovery and is about to run it.
+	RecoveryStarted EventType = "RecoveryStarted"
+	// RecoverySucceeded is sent when a recovery completes without errors.
+	RecoverySucceeded EventType = "RecoverySucceeded"
+	// RecoveryFailed is sent when a recovery completes with an error.
+	RecoveryFailed EventType = "RecoveryFailed"
+)
+
+// AllEventTypes lists every event type, in the order they happen.
+var AllEventTypes = []EventType{
+	AnalysisDetected,
+	RecoveryStarted,
+	RecoverySucceeded,
+	RecoveryFailed,
+}
+
+// Event is a single notification. It is marshalled as JSON when no template
+// is configured for a sink, and is the data passed to the template otherwise.
+type Event struct {
+	Type        EventType `json:"type"`
+	Time        time.Time `json:"time"`
+	Hostname    string    `json:"hostname,omitempty"`
+	Analysis    string    `json:"analysis"`
+	TabletAlias string    `json:"tablet_alias"`
+	Keyspace    string    `json:"keyspace"`
+	Shard       string    `json:"shard"`
+	// The fields below are only set on recovery events.
+	RecoveryName   string   `json:"recovery_name,omitempty"`
+	RecoveryUID    string   `json:"recovery_uid,omitempty"`
+	SuccessorAlias string   `json:"successor_alias,omitempty"`
+	Errors         []string `json:"errors,omitempty"`
+}
+
+// payloadFormatter renders an event into the bytes sent to a sink.
+type payloadFormatter struct {
+	tmpl *template.Template
+}
+
+// newPayloadFormatter returns a formatter using the text/template in the
+// given file, or JSON if the file name is empty.
+func newPayloadFormatter(templateFile string) (*payloadFormatter, error) {
+	if templateFile == "" {
+		return &payloadFormatter{}, nil
+	}
+	data, err := os.ReadFile(templateFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read notification template %v: %w", templateFile, err)
+	}
+	return newPayloadFormatterFromString(templateFile, string(data))
+}
+
+func newPayloadFormatterFromString(name, text string) (*payloadFormatter, error) {
+	tmpl, err := template.New(name).Funcs(template.FuncMap{"json": toJSON}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse notification template %v: %w", name, err)
+	}
+	return &payloadFormatter{tmpl: tmpl}, nil
+}
+
+func (f *payloadFormatter) format(ev *Event) ([]byte, error) {
+	if f.tmpl == nil {
+		return json.Marshal(ev)
+	}
+	var buf bytes.Buffer
+	if err := f.tmpl.Execute(&buf, ev); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// toJSON is available in templates as "json", to quote values safely in
+// JSON payloads.
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vtorc/process"
+)
+
+const (
+	// queueSize is the number of events buffered per sink. Events are dropped
+	// when a sink falls this far behind.
+	queueSize = 1000
+	// maxRetryBackoff caps the exponential backoff between attempts.
+	maxRetryBackoff = time.Minute
+	// closeTimeout bounds the time spent flushing queued events on shutdown.
+	closeTimeout = 10 * time.Second
+)
+
+var (
+	webhookURLs          []string
+	webhookTemplateFile  = ""
+	webhookTimeout       = 10 * time.Second
+	fileSinkPath         = ""
+	fileSinkTemplateFile = ""
+	notifiedEvents       []string
+	maxAttempts          = 5
+	retryBackoff         = 1 * time.Second
+)
+
+var (
+	notificationsSent    = stats.NewCountersWithSingleLabel("NotificationsSent", "Count of the notifications delivered to a sink", "EventType")
+	notificationsFailed  = stats.NewCountersWithSingleLabel("NotificationsFailed", "Count of the notifications that could not be delivered to a sink after all attempts", "EventType")
+	notificationsDropped = stats.NewCountersWithSingleLabel("NotificationsDropped", "Count of the notifications dropped because a sink queue was full", "EventType")
+)
+
+// RegisterFlags registers the flags required by the notification sinks.
+func RegisterFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar(&webhookURLs, "notification-webhook-url", webhookURLs, "Comma-separated list of HTTP endpoints that VTOrc POSTs event notifications to")
+	fs.StringVar(&webhookTemplateFile, "notification-webhook-template", webhookTemplateFile, "File with a Go text/template used to render the webhook payload. Defaults to the event as JSON")
+	fs.DurationVar(&webhookTimeout, "notification-webhook-timeout", webhookTimeout, "Timeout of a single webhook request")
+	fs.StringVar(&fileSinkPath, "notification-file", fileSinkPath, "File that VTOrc appends event notifications to, one per line")
+	fs.StringVar(&fileSinkTemplateFile, "notification-file-template", fileSinkTemplateFile, "File with a Go text/template used to render each line of --notification-file. Defaults to the event as JSON")
+	fs.StringSliceVar(&notifiedEvents, "notification-events", notifiedEvents, "Comma-separated list of the events to notify, out of AnalysisDetected, RecoveryStarted, RecoverySucceeded and RecoveryFailed. Defaults to all of them")
+	fs.IntVar(&maxAttempts, "notification-max-attempts", maxAttempts, "Maximum number of times delivery of a notification to a sink is attempted")
+	fs.DurationVar(&retryBackoff, "notification-retry-backoff", retryBackoff, "Time to wait before retrying a failed notification. Doubled after every attempt")
+}
+
+// Notifier fans events out to a set of sinks. Each sink has its own queue and
+// goroutine, so that a slow sink doesn't hold up the others or the caller.
+type Notifier struct {
+	hostname string
+	events   map[EventType]bool
+	workers  []*sinkWorker
+	wg       sync.WaitGroup
+}
+
+// sinkWorker delivers the events queued for a single sink, retrying with
+// exponential backoff.
+type sinkWorker struct {
+	sink        Sink
+	queue       chan *Event
+	maxAttempts int
+	backoff     time.Duration
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+// NewNotifier creates a Notifier sending the given event types to the sinks.
+// All event types are sent when events is empty.
+func NewNotifier(sinks []Sink, events []EventType, maxAttempts int, backoff time.Duration) *Notifier {
+	n := &Notifier{
+		hostname: process.ThisHostname,
+		events:   make(map[EventType]bool),
+	}
+	if len(events) == 0 {
+		events = AllEventTypes
+	}
+	for _, eventType := range events {
+		n.events[eventType] = true
+	}
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	for _, sink := range sinks {
+		ctx, cancel := context.WithCancel(context.Background())
+		w := &sinkWorker{
+			sink:        sink,
+			queue:       make(chan *Event, queueSize),
+			maxAttempts: maxAttempts,
+			backoff:     backoff,
+			ctx:         ctx,
+			cancel:      cancel,
+		}
+		n.workers = append(n.workers, w)
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			w.run()
+		}()
+	}
+	return n
+}
+
+// Notify queues the event for every sink. It never blocks: if a sink queue
+// is full, the event is dropped for that sink.
+func (n *Notifier) Notify(ev *Event) {
+	if n == nil || !n.events[ev.Type] {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	if ev.Hostname == "" {
+		ev.Hostname = n.hostname
+	}
+	for _, w := range n.workers {
+		select {
+		case w.queue <- ev:
+		default:
+			notificationsDropped.Add(string(ev.Type), 1)
+			log.Warningf("notify: queue of %v is full, dropping %v event for %v", w.sink.Name(), ev.Type, ev.TabletAlias)
+		}
+	}
+}
+
+// Close stops accepting events and waits for the queued ones to be
+// delivered, up to the given timeout. Retries in progress are abandoned when
+// the timeout expires.
+func (n *Notifier) Close(timeout time.Duration) {
+	if n == nil {
+		return
+	}
+	for _, w := range n.workers {
+		close(w.queue)
+	}
+	done := make(chan struct{})
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		log.Warningf("notify: timed out waiting for queued notifications to be sent")
+		for _, w := range n.workers {
+			w.cancel()
+		}
+		<-done
+	}
+}
+
+func (w *sinkWorker) run() {
+	defer w.cancel()
+	for ev := range w.queue {
+		if err := w.send(ev); err != nil {
+			notificationsFailed.Add(string(ev.Type), 1)
+			log.Errorf("notify: failed to send %v event for %v to %v: %v", ev.Type, ev.TabletAlias, w.sink.Name(), err)
+			continue
+		}
+		notificationsSent.Add(string(ev.Type), 1)
+	}
+}
+
+// send delivers a single event, retrying transient errors.
+func (w *sinkWorker) send(ev *Event) error {
+	backoff := w.backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = w.sink.Send(w.ctx, ev); err == nil || isPermanent(err) || attempt >= w.maxAttempts {
+			return err
+		}
+		log.Warningf("notify: attempt %d to send %v event to %v failed, retrying in %v: %v", attempt, ev.Type, w.sink.Name(), backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-w.ctx.Done():
+			return err
+		}
+		backoff = min(2*backoff, maxRetryBackoff)
+	}
+}
+
+var defaultNotifier *Notifier
+
+// Init creates the notifier configured by the flags. It is a noop when no
+// sink is configured.
+func Init() error {
+	var events []EventType
+	for _, name := range notifiedEvents {
+		eventType, err := parseEventType(name)
+		if err != nil {
+			return err
+		}
+		events = append(events, eventType)
+	}
+
+	var sinks []Sink
+	if len(webhookURLs) > 0 {
+		formatter, err := newPayloadFormatter(webhookTemplateFile)
+		if err != nil {
+			return err
+		}
+		for _, url := range webhookURLs {
+			sinks = append(sinks, newWebhookSink(url, webhookTimeout, formatter))
+		}
+	}
+	if fileSinkPath != "" {
+		formatter, err := newPayloadFormatter(fileSinkTemplateFile)
+		if err != nil {
+			return err
+		}
+		sinks = append(sinks, newFileSink(fileSinkPath, formatter))
+	}
+	if len(sinks) == 0 {
+		return nil
+	}
+
+	defaultNotifier = NewNotifier(sinks, events, maxAttempts, retryBackoff)
+	for _, sink := range sinks {
+		log.Infof("notify: sending events to %v", sink.Name())
+	}
+	return nil
+}
+
+// Notify sends the event to the sinks configured by the flags, if any.
+func Notify(ev *Event) {
+	defaultNotifier.Notify(ev)
+}
+
+// Close flushes the events queued by Notify.
+func Close() {
+	defaultNotifier.Close(closeTimeout)
+}
+
+func parseEventType(name string) (EventType, error) {
+	for _, eventType := range AllEventTypes {
+		if string(eventType) == name {
+			return eventType, nil
+		}
+	}
+	return "", fmt.Errorf("unknown notification event %q, expected one of %v", name, AllEventTypes)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink records the events it receives, failing the first
+// failures attempts.
+type recordingSink struct {
+	mu       sync.Mutex
+	failures int
+	attempts int
+	events   []*Event
+}
+
+func (s *recordingSink) Name() string {
+	return "recording"
+}
+
+func (s *recordingSink) Send(ctx context.Context, ev *Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts++
+	if s.attempts <= s.failures {
+		return errors.New("transient failure")
+	}
+	s.events = append(s.events, ev)
+	return nil
+}
+
+func TestNotifierRetries(t *testing.T) {
+	sink := &recordingSink{failures: 2}
+	n := NewNotifier([]Sink{sink}, nil, 3, time.Millisecond)
+	n.Notify(&Event{Type: RecoveryStarted, TabletAlias: "zone1-0000000100"})
+	n.Close(time.Second)
+
+	assert.Equal(t, 3, sink.attempts)
+	require.Len(t, sink.events, 1)
+	assert.Equal(t, "zone1-0000000100", sink.events[0].TabletAlias)
+	assert.False(t, sink.events[0].Time.IsZero())
+}
+
+func TestNotifierGivesUp(t *testing.T) {
+	sink := &recordingSink{failures: 10}
+	n := NewNotifier([]Sink{sink}, nil, 2, time.Millisecond)
+	n.Notify(&Event{Type: RecoveryFailed})
+	n.Close(time.Second)
+
+	assert.Equal(t, 2, sink.attempts)
+	assert.Empty(t, sink.events)
+}
+
+func TestNotifierFiltersEvents(t *testing.T) {
+	sink := &recordingSink{}
+	n := NewNotifier([]Sink{sink}, []EventType{RecoverySucceeded, RecoveryFailed}, 1, time.Millisecond)
+	n.Notify(&Event{Type: AnalysisDetected})
+	n.Notify(&Event{Type: RecoveryStarted})
+	n.Notify(&Event{Type: RecoveryFailed})
+	n.Close(time.Second)
+
+	require.Len(t, sink.events, 1)
+	assert.Equal(t, RecoveryFailed, sink.events[0].Type)
+}
+
+func TestWebhookSink(t *testing.T) {
+	var calls atomic.Int32
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	formatter, err := newPayloadFormatterFromString("test", `{"text": {{ json (printf "%s on %s/%s" .Analysis .Keyspace .Shard) }}}`)
+	require.NoError(t, err)
+	n := NewNotifier([]Sink{newWebhookSink(server.URL+"/hooks/secret", time.Second, formatter)}, nil, 3, time.Millisecond)
+	n.Notify(&Event{Type: AnalysisDetected, Analysis: "DeadPrimary", Keyspace: "ks", Shard: "-80"})
+	n.Close(time.Second)
+
+	assert.EqualValues(t, 2, calls.Load())
+	assert.JSONEq(t, `{"text": "DeadPrimary on ks/-80"}`, string(body))
+}
+
+func TestWebhookSinkClientError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sink := newWebhookSink(server.URL+"/hooks/secret", time.Second, &payloadFormatter{})
+	err := sink.Send(context.Background(), &Event{Type: RecoveryStarted})
+	require.Error(t, err)
+	assert.True(t, isPermanent(err))
+	assert.NotContains(t, err.Error(), "secret")
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+	sink := newFileSink(path, &payloadFormatter{})
+	n := NewNotifier([]Sink{sink}, nil, 1, time.Millisecond)
+	n.Notify(&Event{Type: RecoveryStarted, RecoveryUID: "uid"})
+	n.Notify(&Event{Type: RecoverySucceeded, RecoveryUID: "uid", SuccessorAlias: "zone1-0000000101"})
+	n.Close(time.Second)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	var ev Event
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &ev))
+	assert.Equal(t, RecoverySucceeded, ev.Type)
+	assert.Equal(t, "zone1-0000000101", ev.SuccessorAlias)
+}
+
+func TestParseEventType(t *testing.T) {
+	eventType, err := parseEventType("RecoveryFailed")
+	require.NoError(t, err)
+	assert.Equal(t, RecoveryFailed, eventType)
+
+	_, err = parseEventType("RecoveryExploded")
+	assert.ErrorContains(t, err, "unknown notification event")
+}
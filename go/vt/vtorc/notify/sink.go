@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// Sink is a destination for events.
+type Sink interface {
+	// Name identifies the sink in logs and stats.
+	Name() string
+	// Send delivers a single event. Errors wrapped by permanentError are not
+	// retried.
+	Send(ctx context.Context, ev *Event) error
+}
+
+// permanentError marks a failure that retrying will not fix.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+func isPermanent(err error) bool {
+	var perr *permanentError
+	return errors.As(err, &perr)
+}
+
+// webhookSink posts events to an HTTP endpoint.
+type webhookSink struct {
+	url       string
+	client    *http.Client
+	formatter *payloadFormatter
+}
+
+func newWebhookSink(url string, timeout time.Duration, formatter *payloadFormatter) *webhookSink {
+	return &webhookSink{
+		url:       url,
+		client:    &http.Client{Timeout: timeout},
+		formatter: formatter,
+	}
+}
+
+// Name is part of the Sink interface. Only the scheme and host of the URL
+// are used, since the path or query often carry a secret token.
+func (s *webhookSink) Name() string {
+	u, err := url.Parse(s.url)
+	if err != nil {
+		return "webhook"
+	}
+	return "webhook:" + u.Scheme + "://" + u.Host
+}
+
+// Send is part of the Sink interface. Server errors, rate limiting and
+// transport errors are retried, other client errors are not.
+func (s *webhookSink) Send(ctx context.Context, ev *Event) error {
+	payload, err := s.formatter.format(ev)
+	if err != nil {
+		return &permanentError{err: err}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return &permanentError{err: err}
+	}
+	// Templates are expected to render JSON too, as most webhook receivers
+	// (chat, paging and incident tools) require it.
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// Don't let the full URL end up in the logs.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return fmt.Errorf("%v: %v", s.Name(), err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("%v returned %v", s.Name(), resp.Status)
+	default:
+		return &permanentError{err: fmt.Errorf("%v returned %v", s.Name(), resp.Status)}
+	}
+}
+
+// fileSink appends one line per event to a local file.
+type fileSink struct {
+	path      string
+	formatter *payloadFormatter
+
+	mu sync.Mutex
+}
+
+func newFileSink(path string, formatter *payloadFormatter) *fileSink {
+	return &fileSink{
+		path:      path,
+		formatter: formatter,
+	}
+}
+
+// Name is part of the Sink interface.
+func (s *fileSink) Name() string {
+	return "file:" + s.path
+}
+
+// Send is part of the Sink interface.
+func (s *fileSink) Send(ctx context.Context, ev *Event) error {
+	payload, err := s.formatter.format(ev)
+	if err != nil {
+		return &permanentError{err: err}
+	}
+	payload = append(bytes.TrimRight(payload, "\n"), '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// The file is reopened for every event so that it can be rotated.
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0640)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(payload); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -45,11 +45,13 @@ const (
 	disableGlobalRecoveriesAPI    = "/api/disable-global-recoveries"
 	enableGlobalRecoveriesAPI     = "/api/enable-global-recoveries"
 	replicationAnalysisAPI        = "/api/replication-analysis"
+	dryRunRecoveriesAPI           = "/api/dry-run-recoveries"
 	healthAPI                     = "/debug/health"
 	AggregatedDiscoveryMetricsAPI = "/api/aggregated-discovery-metrics"
 
 	shardWithoutKeyspaceFilteringErrorStr = "Filtering by shard without keyspace isn't supported"
 	notAValidValueForSeconds              = "Invalid value for seconds"
+	notAValidValueForPage                 = "Invalid value for page"
 )
 
 var (
@@ -60,6 +62,7 @@ var (
 		disableGlobalRecoveriesAPI,
 		enableGlobalRecoveriesAPI,
 		replicationAnalysisAPI,
+		dryRunRecoveriesAPI,
 		healthAPI,
 		AggregatedDiscoveryMetricsAPI,
 	}
@@ -86,6 +89,8 @@ func (v *vtorcAPI) ServeHTTP(response http.ResponseWriter, request *http.Request
 		errantGTIDsAPIHandler(response, request)
 	case replicationAnalysisAPI:
 		replicationAnalysisAPIHandler(response, request)
+	case dryRunRecoveriesAPI:
+		dryRunRecoveriesAPIHandler(response, request)
 	case AggregatedDiscoveryMetricsAPI:
 		AggregatedDiscoveryMetricsAPIHandler(response, request)
 	default:
@@ -102,7 +107,7 @@ func getACLPermissionLevelForAPI(apiEndpoint string) string {
 		return acl.MONITORING
 	case disableGlobalRecoveriesAPI, enableGlobalRecoveriesAPI:
 		return acl.ADMIN
-	case replicationAnalysisAPI:
+	case replicationAnalysisAPI, dryRunRecoveriesAPI:
 		return acl.MONITORING
 	case healthAPI:
 		return acl.MONITORING
@@ -231,6 +236,32 @@ func replicationAnalysisAPIHandler(response http.ResponseWriter, request *http.R
 	returnAsJSON(response, http.StatusOK, analysis)
 }
 
+// dryRunRecoveriesAPIHandler is the handler for the dryRunRecoveriesAPI endpoint
+func dryRunRecoveriesAPIHandler(response http.ResponseWriter, request *http.Request) {
+	// This api also supports filtering by shard and keyspace provided, and paging.
+	shard := request.URL.Query().Get("shard")
+	keyspace := request.URL.Query().Get("keyspace")
+	if shard != "" && keyspace == "" {
+		http.Error(response, shardWithoutKeyspaceFilteringErrorStr, http.StatusBadRequest)
+		return
+	}
+	page := 0
+	if qPage := request.URL.Query().Get("page"); qPage != "" {
+		var err error
+		page, err = strconv.Atoi(qPage)
+		if err != nil || page < 0 {
+			http.Error(response, notAValidValueForPage, http.StatusBadRequest)
+			return
+		}
+	}
+	recoveries, err := logic.ReadDryRunRecoveries(keyspace, shard, page)
+	if err != nil {
+		http.Error(response, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	returnAsJSON(response, http.StatusOK, recoveries)
+}
+
 // healthAPIHandler is the handler for the healthAPI endpoint
 func healthAPIHandler(response http.ResponseWriter, request *http.Request) {
 	health, err := process.HealthTest()
@@ -28,6 +28,9 @@ func TestGetACLPermissionLevelForAPI(t *testing.T) {
 		}, {
 			apiEndpoint: replicationAnalysisAPI,
 			want:        acl.MONITORING,
+		}, {
+			apiEndpoint: dryRunRecoveriesAPI,
+			want:        acl.MONITORING,
 		}, {
 			apiEndpoint: healthAPI,
 			want:        acl.MONITORING,
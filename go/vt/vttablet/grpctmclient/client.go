@@ -1047,6 +1047,38 @@ func (client *Client) RestoreFromBackup(ctx context.Context, tablet *topodatapb.
 	}, nil
 }
 
+type verifyBackupStreamAdapter struct {
+	stream tabletmanagerservicepb.TabletManager_VerifyBackupClient
+	closer io.Closer
+}
+
+func (e *verifyBackupStreamAdapter) Recv() (*tabletmanagerdatapb.VerifyBackupResponse, error) {
+	br, err := e.stream.Recv()
+	if err != nil {
+		e.closer.Close()
+		return nil, err
+	}
+	return br, nil
+}
+
+// VerifyBackup is part of the tmclient.TabletManagerClient interface.
+func (client *Client) VerifyBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.VerifyBackupRequest) (tmclient.VerifyBackupStream, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := c.VerifyBackup(ctx, req)
+	if err != nil {
+		closer.Close()
+		return nil, err
+	}
+	return &verifyBackupStreamAdapter{
+		stream: stream,
+		closer: closer,
+	}, nil
+}
+
 // Close is part of the tmclient.TabletManagerClient interface.
 func (client *Client) Close() {
 	client.dialer.Close()
@@ -544,6 +544,30 @@ func (s *server) RestoreFromBackup(request *tabletmanagerdatapb.RestoreFromBacku
 	return s.tm.RestoreFromBackup(ctx, logger, request)
 }
 
+func (s *server) VerifyBackup(request *tabletmanagerdatapb.VerifyBackupRequest, stream tabletmanagerservicepb.TabletManager_VerifyBackupServer) (err error) {
+	ctx := stream.Context()
+	defer s.tm.HandleRPCPanic(ctx, "VerifyBackup", request, nil, true /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+
+	// create a logger, send the result back to the caller
+	logger := logutil.NewCallbackLogger(func(e *logutilpb.Event) {
+		// If the client disconnects, we will just fail
+		// to send the log events, but won't interrupt
+		// the verification.
+		stream.Send(&tabletmanagerdatapb.VerifyBackupResponse{
+			Event: e,
+		})
+	})
+
+	verification, err := s.tm.VerifyBackup(ctx, logger, request)
+	if err != nil {
+		return err
+	}
+	return stream.Send(&tabletmanagerdatapb.VerifyBackupResponse{
+		Verification: verification,
+	})
+}
+
 func (s *server) CheckThrottler(ctx context.Context, request *tabletmanagerdatapb.CheckThrottlerRequest) (response *tabletmanagerdatapb.CheckThrottlerResponse, err error) {
 	defer s.tm.HandleRPCPanic(ctx, "CheckThrottler", request, response, false /*verbose*/, &err)
 	ctx = callinfo.GRPCCallInfo(ctx)
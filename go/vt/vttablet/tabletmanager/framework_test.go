@@ -258,6 +258,11 @@ func (ftc *fakeTabletConn) ReadTransaction(ctx context.Context, target *querypb.
 	return nil, nil
 }
 
+// fakeTabletConn implements the QueryService interface.
+func (ftc *fakeTabletConn) UnresolvedTransactions(ctx context.Context, target *querypb.Target, abandonAge int64) (transactions []*querypb.TransactionMetadata, err error) {
+	return nil, nil
+}
+
 // fakeTabletConn implements the QueryService interface.
 func (ftc *fakeTabletConn) Execute(ctx context.Context, target *querypb.Target, sql string, bindVariables map[string]*querypb.BindVariable, transactionID, reservedID int64, options *querypb.ExecuteOptions) (*sqltypes.Result, error) {
 	return nil, nil
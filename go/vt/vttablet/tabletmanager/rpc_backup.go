@@ -27,9 +27,11 @@ import (
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/mysqlctlproto"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
 
+	mysqlctlpb "vitess.io/vitess/go/vt/proto/mysqlctl"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
@@ -197,6 +199,46 @@ func (tm *TabletManager) RestoreFromBackup(ctx context.Context, logger logutil.L
 	return err
 }
 
+// VerifyBackup restores a backup of the tablet's shard into a scratch mysqld
+// next to the tablet's own, and checks the restored data. The tablet keeps
+// serving, and its mysqld is left untouched.
+func (tm *TabletManager) VerifyBackup(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.VerifyBackupRequest) (*mysqlctlpb.BackupVerification, error) {
+	if tm.Cnf == nil {
+		return nil, fmt.Errorf("cannot verify backups without my.cnf, please restart vttablet with a my.cnf file specified")
+	}
+
+	// Create the logger: tee to console and source.
+	l := logutil.NewTeeLogger(logutil.NewConsoleLogger(), logger)
+
+	mysqld, cnf, cleanup, err := mysqlctl.NewScratchMysqld(ctx, tm.DBConfigs, "")
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	concurrency := int(request.Concurrency)
+	if concurrency <= 0 {
+		concurrency = restoreConcurrency
+	}
+	tablet := tm.Tablet()
+	verification, err := mysqlctl.VerifyBackup(ctx, mysqlctl.VerifyBackupParams{
+		Cnf:          cnf,
+		Mysqld:       mysqld,
+		Logger:       l,
+		Concurrency:  concurrency,
+		HookExtraEnv: tm.hookExtraEnv(),
+		DbName:       topoproto.TabletDbName(tablet),
+		Keyspace:     tablet.Keyspace,
+		Shard:        tablet.Shard,
+		BackupName:   request.BackupName,
+		Stats:        backupstats.RestoreStats(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mysqlctlproto.BackupVerificationToProto(verification), nil
+}
+
 func (tm *TabletManager) beginBackup(backupMode string) error {
 	tm.mutex.Lock()
 	defer tm.mutex.Unlock()
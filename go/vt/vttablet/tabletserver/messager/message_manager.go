@@ -53,6 +53,7 @@ type QueryGenerator interface {
 	GenerateAckQuery(ids []string) (string, map[string]*querypb.BindVariable)
 	GeneratePostponeQuery(ids []string) (string, map[string]*querypb.BindVariable)
 	GeneratePurgeQuery(timeCutoff int64) (string, map[string]*querypb.BindVariable)
+	GenerateDeadLetterQueries(ids []string) []*querypb.BoundQuery
 }
 
 type messageReceiver struct {
@@ -176,6 +177,7 @@ type messageManager struct {
 	purgeAfter   time.Duration
 	minBackoff   time.Duration
 	maxBackoff   time.Duration
+	maxAttempts  int64
 	batchSize    int
 	pollerTicks  *timer.Timer
 	purgeTicks   *timer.Timer
@@ -236,6 +238,7 @@ type messageManager struct {
 	ackQuery                  *sqlparser.ParsedQuery
 	postponeQuery             *sqlparser.ParsedQuery
 	purgeQuery                *sqlparser.ParsedQuery
+	deadLetterQueries         []*sqlparser.ParsedQuery
 }
 
 // newMessageManager creates a new message manager.
@@ -253,6 +256,7 @@ func newMessageManager(tsv TabletService, vs VStreamer, table *schema.Table, pos
 		purgeAfter:      table.MessageInfo.PurgeAfterDuration,
 		minBackoff:      table.MessageInfo.MinBackoff,
 		maxBackoff:      table.MessageInfo.MaxBackoff,
+		maxAttempts:     int64(table.MessageInfo.MaxAttempts),
 		batchSize:       table.MessageInfo.BatchSize,
 		cache:           newCache(table.MessageInfo.CacheSize),
 		pollerTicks:     timer.NewTimer(table.MessageInfo.PollInterval),
@@ -282,10 +286,33 @@ func newMessageManager(tsv TabletService, vs VStreamer, table *schema.Table, pos
 		"delete from %v where time_acked < %a limit 500", mm.name, ":time_acked")
 
 	mm.postponeQuery = buildPostponeQuery(mm.name, mm.minBackoff, mm.maxBackoff)
+	mm.deadLetterQueries = buildDeadLetterQueries(mm.name, table.MessageInfo.DeadLetterTable)
 
 	return mm
 }
 
+// buildDeadLetterQueries returns the queries that dead-letter messages.
+// If there is no dead letter table, the messages are marked failed by
+// setting time_next to NULL, which keeps them out of the poller and the
+// cache. Otherwise, they are copied to the dead letter table, which must
+// have the same columns as the message table, and deleted.
+func buildDeadLetterQueries(name sqlparser.IdentifierCS, deadLetterTable string) []*sqlparser.ParsedQuery {
+	if deadLetterTable == "" {
+		return []*sqlparser.ParsedQuery{sqlparser.BuildParsedQuery(
+			"update %v set time_next = null where id in %a and time_acked is null",
+			name, "::ids")}
+	}
+	dlq := sqlparser.NewIdentifierCS(deadLetterTable)
+	return []*sqlparser.ParsedQuery{
+		sqlparser.BuildParsedQuery(
+			"insert into %v select * from %v where id in %a and time_acked is null",
+			dlq, name, "::ids"),
+		sqlparser.BuildParsedQuery(
+			"delete from %v where id in %a and time_acked is null",
+			name, "::ids"),
+	}
+}
+
 func buildPostponeQuery(name sqlparser.IdentifierCS, minBackoff, maxBackoff time.Duration) *sqlparser.ParsedQuery {
 	var args []any
 
@@ -549,17 +576,28 @@ func (mm *messageManager) runSend() {
 
 			// Fetch rows from cache.
 			lateCount := int64(0)
+			var deadIDs []string
 			for i := 0; i < mm.batchSize; i++ {
 				mr := mm.cache.Pop()
 				if mr == nil {
 					break
 				}
+				// Messages that have used up their delivery attempts
+				// are dead-lettered instead of being sent again.
+				if mm.maxAttempts > 0 && mr.Epoch >= mm.maxAttempts {
+					deadIDs = append(deadIDs, mr.Row[0].ToString())
+					continue
+				}
 				if mr.Epoch >= 1 {
 					lateCount++
 				}
 				rows = append(rows, mr.Row)
 			}
 			MessageStats.Add([]string{mm.name.String(), "Delayed"}, lateCount)
+			if deadIDs != nil {
+				mm.wg.Add(1)
+				go mm.deadLetter(deadIDs) // calls the offsetting mm.wg.Done()
+			}
 
 			// If we have rows to send, break out of this loop.
 			if rows != nil {
@@ -642,6 +680,38 @@ func (mm *messageManager) postpone(ctx context.Context, tsv TabletService, ackWa
 	return nil
 }
 
+// deadLetter moves the messages to the dead letter table or marks them failed.
+func (mm *messageManager) deadLetter(ids []string) {
+	defer func() {
+		mm.tsv.LogError()
+		mm.wg.Done()
+	}()
+
+	defer func() {
+		// Same as in send: the ids must not be discarded while the
+		// poller is active.
+		mm.cacheManagementMu.Lock()
+		defer mm.cacheManagementMu.Unlock()
+		mm.cache.Discard(ids)
+	}()
+
+	ctx, cancel := context.WithTimeout(tabletenv.LocalContext(), mm.ackWaitTime)
+	defer cancel()
+	if err := mm.postponeSema.Acquire(ctx, 1); err != nil {
+		MessageStats.Add([]string{mm.name.String(), "DeadLetterFailed"}, int64(len(ids)))
+		return
+	}
+	defer mm.postponeSema.Release(1)
+	count, err := mm.tsv.DeadLetterMessages(ctx, nil, mm, ids)
+	if err != nil {
+		// The messages will be retried on the next poll.
+		MessageStats.Add([]string{mm.name.String(), "DeadLetterFailed"}, int64(len(ids)))
+		log.Errorf("Unable to dead-letter messages %v: %v", ids, err)
+		return
+	}
+	MessageStats.Add([]string{mm.name.String(), "DeadLettered"}, count)
+}
+
 func (mm *messageManager) startVStream() {
 	if mm.streamCancel != nil {
 		return
@@ -765,6 +835,11 @@ func (mm *messageManager) processRowEvent(fields []*querypb.Field, rowEvent *bin
 		if mr.TimeAcked != 0 || mr.TimeNext > now {
 			continue
 		}
+		// A NULL time_next on an unacked message means it was
+		// marked failed by the dead-letter handling.
+		if mm.maxAttempts > 0 && row[1].IsNull() {
+			continue
+		}
 		mm.Add(mr)
 	}
 	return nil
@@ -901,6 +976,29 @@ func (mm *messageManager) GeneratePurgeQuery(timeCutoff int64) (string, map[stri
 	}
 }
 
+// GenerateDeadLetterQueries returns the queries for dead-lettering messages.
+// They must be executed in order within the same transaction.
+func (mm *messageManager) GenerateDeadLetterQueries(ids []string) []*querypb.BoundQuery {
+	idbvs := &querypb.BindVariable{
+		Type:   querypb.Type_TUPLE,
+		Values: make([]*querypb.Value, 0, len(ids)),
+	}
+	for _, id := range ids {
+		idbvs.Values = append(idbvs.Values, &querypb.Value{
+			Type:  querypb.Type_VARBINARY,
+			Value: []byte(id),
+		})
+	}
+	queries := make([]*querypb.BoundQuery, 0, len(mm.deadLetterQueries))
+	for _, pq := range mm.deadLetterQueries {
+		queries = append(queries, &querypb.BoundQuery{
+			Sql:           pq.Query,
+			BindVariables: map[string]*querypb.BindVariable{"ids": idbvs},
+		})
+	}
+	return queries
+}
+
 // BuildMessageRow builds a MessageRow from a db row.
 func BuildMessageRow(row []sqltypes.Value) (*MessageRow, error) {
 	mr := &MessageRow{Row: row[4:]}
@@ -436,6 +436,41 @@ func TestMessageManagerBatchSend(t *testing.T) {
 	}
 }
 
+func TestMessageManagerDeadLetter(t *testing.T) {
+	ti := newMMTable()
+	ti.MessageInfo.BatchSize = 2
+	ti.MessageInfo.MaxAttempts = 3
+	tsv := newFakeTabletServer()
+	ch := make(chan string, 1)
+	tsv.SetChannel(ch)
+	mm := newMessageManager(tsv, newFakeVStreamer(), ti, semaphore.NewWeighted(1))
+	mm.Open()
+	defer mm.Close()
+
+	r1 := newTestReceiver(1)
+	mm.Subscribe(context.Background(), r1.rcv)
+	<-r1.ch
+
+	mm.mu.Lock()
+	mm.cache.Add(&MessageRow{Epoch: 3, Row: []sqltypes.Value{sqltypes.NewVarBinary("1"), sqltypes.NULL}})
+	mm.cache.Add(&MessageRow{Epoch: 2, Row: []sqltypes.Value{sqltypes.NewVarBinary("2"), sqltypes.NULL}})
+	mm.cond.Broadcast()
+	mm.mu.Unlock()
+
+	// Only the message with attempts left is sent.
+	want := &sqltypes.Result{
+		Rows: [][]sqltypes.Value{{
+			sqltypes.NewVarBinary("2"),
+			sqltypes.NULL,
+		}},
+	}
+	got := <-r1.ch
+	assert.True(t, got.Equal(want), "Received: %v, want %v", got, want)
+	// Dead-lettering and postponing happen concurrently.
+	assert.ElementsMatch(t, []string{"deadletter", "postpone"}, []string{<-ch, <-ch})
+	assert.EqualValues(t, 1, tsv.deadLetterCount.Load())
+}
+
 func TestMessageManagerStreamerSimple(t *testing.T) {
 	fvs := newFakeVStreamer()
 	fvs.setStreamerResponse([][]*binlogdatapb.VEvent{{{
@@ -786,6 +821,32 @@ func TestMMGenerate(t *testing.T) {
 	}
 }
 
+func TestMMGenerateDeadLetter(t *testing.T) {
+	ti := newMMTable()
+	ti.MessageInfo.MaxAttempts = 3
+	mm := newMessageManager(newFakeTabletServer(), newFakeVStreamer(), ti, semaphore.NewWeighted(1))
+	wantids := &querypb.BindVariable{
+		Type:   querypb.Type_TUPLE,
+		Values: []*querypb.Value{{Type: querypb.Type_VARBINARY, Value: []byte("1")}, {Type: querypb.Type_VARBINARY, Value: []byte("2")}},
+	}
+	queries := mm.GenerateDeadLetterQueries([]string{"1", "2"})
+	utils.MustMatch(t, []*querypb.BoundQuery{{
+		Sql:           "update foo set time_next = null where id in ::ids and time_acked is null",
+		BindVariables: map[string]*querypb.BindVariable{"ids": wantids},
+	}}, queries)
+
+	ti.MessageInfo.DeadLetterTable = "foo_dlq"
+	mm = newMessageManager(newFakeTabletServer(), newFakeVStreamer(), ti, semaphore.NewWeighted(1))
+	queries = mm.GenerateDeadLetterQueries([]string{"1", "2"})
+	utils.MustMatch(t, []*querypb.BoundQuery{{
+		Sql:           "insert into foo_dlq select * from foo where id in ::ids and time_acked is null",
+		BindVariables: map[string]*querypb.BindVariable{"ids": wantids},
+	}, {
+		Sql:           "delete from foo where id in ::ids and time_acked is null",
+		BindVariables: map[string]*querypb.BindVariable{"ids": wantids},
+	}}, queries)
+}
+
 func TestMMGenerateWithBackoff(t *testing.T) {
 	mm := newMessageManager(newFakeTabletServer(), newFakeVStreamer(), newMMTableWithBackoff(), semaphore.NewWeighted(1))
 	mm.Open()
@@ -823,8 +884,9 @@ func TestMMGenerateWithBackoff(t *testing.T) {
 
 type fakeTabletServer struct {
 	tabletenv.Env
-	postponeCount atomic.Int64
-	purgeCount    atomic.Int64
+	postponeCount   atomic.Int64
+	purgeCount      atomic.Int64
+	deadLetterCount atomic.Int64
 
 	mu sync.Mutex
 	ch chan string
@@ -867,6 +929,17 @@ func (fts *fakeTabletServer) PurgeMessages(ctx context.Context, target *querypb.
 	return 0, nil
 }
 
+func (fts *fakeTabletServer) DeadLetterMessages(ctx context.Context, target *querypb.Target, gen QueryGenerator, ids []string) (count int64, err error) {
+	fts.deadLetterCount.Add(int64(len(ids)))
+	fts.mu.Lock()
+	ch := fts.ch
+	fts.mu.Unlock()
+	if ch != nil {
+		ch <- "deadletter"
+	}
+	return int64(len(ids)), nil
+}
+
 type fakeVStreamer struct {
 	streamInvocations atomic.Int64
 	mu                sync.Mutex
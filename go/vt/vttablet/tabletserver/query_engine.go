@@ -465,6 +465,43 @@ func (qe *QueryEngine) GetMessageStreamPlan(name string) (*TabletPlan, error) {
 	return plan, nil
 }
 
+// buildRuleQuery builds the query to execute instead of the plan's FullQuery for a
+// QRHint or QRRewrite rule: sql with the rule's optimizer hint added, or the rule's
+// rewrite. The new query must produce the same plan type as the original one.
+func (qe *QueryEngine) buildRuleQuery(plan *TabletPlan, qr *rules.Rule, sql string) (*sqlparser.ParsedQuery, error) {
+	if qr.Action() == rules.QRRewrite {
+		sql = qr.Rewrite()
+	}
+	statement, err := sqlparser.Parse(sql)
+	if err != nil {
+		return nil, err
+	}
+	if qr.Action() == rules.QRHint {
+		hinted, ok := statement.(sqlparser.SupportOptimizerHint)
+		if !ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "cannot add hint to %s in rule: %s", sqlparser.ASTToStatementType(statement), qr.Description)
+		}
+		// optimizer hints must immediately follow the statement keyword
+		comments := append(sqlparser.Comments{"/*+ " + qr.Hint() + " */"}, hinted.GetParsedComments().GetComments()...)
+		hinted.SetComments(comments)
+	}
+
+	curSchema := qe.schema.Load()
+	var splan *planbuilder.Plan
+	if plan.PlanID == planbuilder.PlanSelectStream {
+		splan, err = planbuilder.BuildStreaming(statement, curSchema.tables)
+	} else {
+		splan, err = planbuilder.Build(statement, curSchema.tables, qe.env.Config().DB.DBName, qe.env.Config().EnableViews)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if splan.PlanID != plan.PlanID || splan.FullQuery == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "query plan %s does not match original plan %s in rule: %s", splan.PlanID, plan.PlanID, qr.Description)
+	}
+	return splan.FullQuery, nil
+}
+
 // GetConnSetting returns system settings for the connection.
 func (qe *QueryEngine) GetConnSetting(ctx context.Context, settings []string) (*smartconnpool.Setting, error) {
 	span, _ := trace.NewSpan(ctx, "QueryEngine.GetConnSetting")
@@ -64,6 +64,11 @@ type QueryExecutor struct {
 	tsv            *TabletServer
 	tabletType     topodatapb.TabletType
 	setting        *smartconnpool.Setting
+
+	// ruleQuery replaces the plan's FullQuery when a query rule hints or rewrites the query.
+	ruleQuery *sqlparser.ParsedQuery
+	// releaseRule releases the concurrency slot held for a query rule, if any.
+	releaseRule func()
 }
 
 const (
@@ -148,6 +153,7 @@ func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 		qre.tsv.Stats().ResultHistogram.Add(int64(len(reply.Rows)))
 	}(time.Now())
 
+	defer qre.releaseRuleSlot()
 	if err = qre.checkPermissions(); err != nil {
 		return nil, err
 	}
@@ -317,6 +323,7 @@ func (qre *QueryExecutor) Stream(callback StreamCallback) error {
 		qre.recordUserQuery("Stream", int64(time.Since(start)))
 	}(time.Now())
 
+	defer qre.releaseRuleSlot()
 	if err := qre.checkPermissions(); err != nil {
 		return err
 	}
@@ -328,7 +335,7 @@ func (qre *QueryExecutor) Stream(callback StreamCallback) error {
 		}
 	}
 
-	sql, sqlWithoutComments, err := qre.generateFinalSQL(qre.plan.FullQuery, qre.bindVars)
+	sql, sqlWithoutComments, err := qre.generateFinalSQL(qre.fullQuery(), qre.bindVars)
 	if err != nil {
 		return err
 	}
@@ -407,6 +414,7 @@ func (qre *QueryExecutor) MessageStream(callback StreamCallback) error {
 		qre.recordUserQuery("MessageStream", int64(time.Since(start)))
 	}(time.Now())
 
+	defer qre.releaseRuleSlot()
 	if err := qre.checkPermissions(); err != nil {
 		return err
 	}
@@ -443,30 +451,10 @@ func (qre *QueryExecutor) checkPermissions() error {
 		username = ci.Username()
 	}
 
-	action, ruleCancelCtx, timeout, desc := qre.plan.Rules.GetAction(remoteAddr, username, qre.bindVars, qre.marginComments)
-
-	bufferingTimeoutCtx, cancel := context.WithTimeout(qre.ctx, timeout) // aborts buffering at given timeout
-	defer cancel()
-
-	switch action {
-	case rules.QRFail:
-		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "disallowed due to rule: %s", desc)
-	case rules.QRFailRetry:
-		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "disallowed due to rule: %s", desc)
-	case rules.QRBuffer:
-		if ruleCancelCtx != nil {
-			// We buffer up to some timeout. The timeout is determined by ctx.Done().
-			// If we're not at timeout yet, we fail the query
-			select {
-			case <-ruleCancelCtx.Done():
-				// good! We have buffered the query, and buffering is completed
-			case <-bufferingTimeoutCtx.Done():
-				// Sorry, timeout while waiting for buffering to complete
-				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "buffer timeout after %v in rule: %s", timeout, desc)
-			}
+	if qr := qre.plan.Rules.GetMatchingRule(remoteAddr, username, qre.bindVars, qre.marginComments); qr != nil {
+		if err := qre.applyRule(qr); err != nil {
+			return err
 		}
-	default:
-		// no rules against this query. Good to proceed
 	}
 	// Skip ACL check for queries against the dummy dual table
 	if qre.plan.TableName().String() == "dual" {
@@ -502,6 +490,71 @@ func (qre *QueryExecutor) checkPermissions() error {
 	return nil
 }
 
+// applyRule performs the action of the query rule matching the query.
+func (qre *QueryExecutor) applyRule(qr *rules.Rule) error {
+	desc := qr.Description
+	switch qr.Action() {
+	case rules.QRFail:
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "disallowed due to rule: %s", desc)
+	case rules.QRFailRetry:
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "disallowed due to rule: %s", desc)
+	case rules.QRBuffer:
+		if ruleCancelCtx := qr.CancelCtx(); ruleCancelCtx != nil {
+			bufferingTimeoutCtx, cancel := context.WithTimeout(qre.ctx, qr.Timeout()) // aborts buffering at given timeout
+			defer cancel()
+
+			// We buffer up to some timeout. The timeout is determined by ctx.Done().
+			// If we're not at timeout yet, we fail the query
+			select {
+			case <-ruleCancelCtx.Done():
+				// good! We have buffered the query, and buffering is completed
+			case <-bufferingTimeoutCtx.Done():
+				// Sorry, timeout while waiting for buffering to complete
+				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "buffer timeout after %v in rule: %s", qr.Timeout(), desc)
+			}
+		}
+	case rules.QRThrottle:
+		startTime := time.Now()
+		err := qr.Throttle(qre.ctx)
+		qre.tsv.stats.WaitTimings.Record("QueryRuleThrottle", startTime)
+		if err != nil {
+			return vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "throttled due to rule: %s: %v", desc, err)
+		}
+	case rules.QRLimitConcurrency:
+		startTime := time.Now()
+		release, err := qr.AcquireConcurrencySlot(qre.ctx)
+		qre.tsv.stats.WaitTimings.Record("QueryRuleConcurrency", startTime)
+		if err != nil {
+			return vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "concurrency limited due to rule: %s: %v", desc, err)
+		}
+		qre.releaseRule = release
+	case rules.QRHint, rules.QRRewrite:
+		ruleQuery, err := qre.tsv.qe.buildRuleQuery(qre.plan, qr, qre.query)
+		if err != nil {
+			return err
+		}
+		qre.ruleQuery = ruleQuery
+	}
+	return nil
+}
+
+// releaseRuleSlot releases the concurrency slot acquired by applyRule, if any.
+func (qre *QueryExecutor) releaseRuleSlot() {
+	if qre.releaseRule != nil {
+		qre.releaseRule()
+		qre.releaseRule = nil
+	}
+}
+
+// fullQuery returns the query to execute: the plan's FullQuery, unless a query
+// rule hinted or rewrote it.
+func (qre *QueryExecutor) fullQuery() *sqlparser.ParsedQuery {
+	if qre.ruleQuery != nil {
+		return qre.ruleQuery
+	}
+	return qre.plan.FullQuery
+}
+
 func (qre *QueryExecutor) checkAccess(authorized *tableacl.ACLResult, tableName string, callerID *querypb.VTGateCallerID) error {
 	statsKey := []string{tableName, authorized.GroupName, qre.plan.PlanID.String(), callerID.Username}
 	if !authorized.IsMember(callerID) {
@@ -566,9 +619,9 @@ func (qre *QueryExecutor) execDDL(conn *StatefulConnection) (*sqltypes.Result, e
 	sql := qre.query
 	// If FullQuery is not nil, then the DDL query was fully parsed
 	// and we should use the ast to generate the query instead.
-	if qre.plan.FullQuery != nil {
+	if fullQuery := qre.fullQuery(); fullQuery != nil {
 		var err error
-		sql, _, err = qre.generateFinalSQL(qre.plan.FullQuery, qre.bindVars)
+		sql, _, err = qre.generateFinalSQL(fullQuery, qre.bindVars)
 		if err != nil {
 			return nil, err
 		}
@@ -688,7 +741,7 @@ func (qre *QueryExecutor) execNextval() (*sqltypes.Result, error) {
 // execSelect sends a query to mysql only if another identical query is not running. Otherwise, it waits and
 // reuses the result. If the plan is missing field info, it sends the query to mysql requesting full info.
 func (qre *QueryExecutor) execSelect() (*sqltypes.Result, error) {
-	sql, sqlWithoutComments, err := qre.generateFinalSQL(qre.plan.FullQuery, qre.bindVars)
+	sql, sqlWithoutComments, err := qre.generateFinalSQL(qre.fullQuery(), qre.bindVars)
 	if err != nil {
 		return nil, err
 	}
@@ -754,7 +807,7 @@ func (qre *QueryExecutor) verifyRowCount(count, maxrows int64) error {
 	if warnThreshold > 0 && count > warnThreshold {
 		callerID := callerid.ImmediateCallerIDFromContext(qre.ctx)
 		qre.tsv.Stats().Warnings.Add("ResultsExceeded", 1)
-		log.Warningf("caller id: %s row count %v exceeds warning threshold %v: %q", callerID.Username, count, warnThreshold, queryAsString(qre.plan.FullQuery.Query, qre.bindVars, qre.tsv.Config().SanitizeLogMessages, true))
+		log.Warningf("caller id: %s row count %v exceeds warning threshold %v: %q", callerID.Username, count, warnThreshold, queryAsString(qre.fullQuery().Query, qre.bindVars, qre.tsv.Config().SanitizeLogMessages, true))
 	}
 	return nil
 }
@@ -803,7 +856,7 @@ func (qre *QueryExecutor) getStreamConn() (*connpool.PooledConn, error) {
 
 // txFetch fetches from a TxConnection.
 func (qre *QueryExecutor) txFetch(conn *StatefulConnection, record bool) (*sqltypes.Result, error) {
-	sql, _, err := qre.generateFinalSQL(qre.plan.FullQuery, qre.bindVars)
+	sql, _, err := qre.generateFinalSQL(qre.fullQuery(), qre.bindVars)
 	if err != nil {
 		return nil, err
 	}
@@ -869,7 +922,7 @@ func (qre *QueryExecutor) execCallProc() (*sqltypes.Result, error) {
 		return nil, err
 	}
 	defer conn.Recycle()
-	sql, _, err := qre.generateFinalSQL(qre.plan.FullQuery, qre.bindVars)
+	sql, _, err := qre.generateFinalSQL(qre.fullQuery(), qre.bindVars)
 	if err != nil {
 		return nil, err
 	}
@@ -894,7 +947,7 @@ func (qre *QueryExecutor) execCallProc() (*sqltypes.Result, error) {
 
 func (qre *QueryExecutor) execProc(conn *StatefulConnection) (*sqltypes.Result, error) {
 	beforeInTx := conn.IsInTransaction()
-	sql, _, err := qre.generateFinalSQL(qre.plan.FullQuery, qre.bindVars)
+	sql, _, err := qre.generateFinalSQL(qre.fullQuery(), qre.bindVars)
 	if err != nil {
 		return nil, err
 	}
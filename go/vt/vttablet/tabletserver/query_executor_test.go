@@ -1238,6 +1238,116 @@ func TestQueryExecutorDenyListQRRetry(t *testing.T) {
 	}
 }
 
+func TestQueryExecutorRuleActions(t *testing.T) {
+	query := "select * from test_table where name = 1 limit 1000"
+	testcases := []struct {
+		name      string
+		rule      func() *rules.Rule
+		wantQuery string
+		wantCode  vtrpcpb.Code
+	}{{
+		name: "hint",
+		rule: func() *rules.Rule {
+			qr := rules.NewQueryRule("add hint", "r1", rules.QRHint)
+			qr.SetHint("MAX_EXECUTION_TIME(1000)")
+			return qr
+		},
+		wantQuery: "select /*+ MAX_EXECUTION_TIME(1000) */ * from test_table where `name` = 1 limit 1000",
+	}, {
+		name: "rewrite",
+		rule: func() *rules.Rule {
+			qr := rules.NewQueryRule("rewrite", "r1", rules.QRRewrite)
+			require.NoError(t, qr.SetRewrite("select pk from test_table where name = 1 limit 10"))
+			return qr
+		},
+		wantQuery: "select pk from test_table where `name` = 1 limit 10",
+	}, {
+		name: "rewrite to a different plan",
+		rule: func() *rules.Rule {
+			qr := rules.NewQueryRule("rewrite", "r1", rules.QRRewrite)
+			require.NoError(t, qr.SetRewrite("delete from test_table where name = 1"))
+			return qr
+		},
+		wantCode: vtrpcpb.Code_INVALID_ARGUMENT,
+	}, {
+		name: "throttle",
+		rule: func() *rules.Rule {
+			qr := rules.NewQueryRule("throttle", "r1", rules.QRThrottle)
+			require.NoError(t, qr.SetMaxQPS(1000))
+			return qr
+		},
+		wantQuery: "select * from test_table where `name` = 1 limit 1000",
+	}, {
+		name: "throttle exhausted",
+		rule: func() *rules.Rule {
+			qr := rules.NewQueryRule("throttle", "r1", rules.QRThrottle)
+			require.NoError(t, qr.SetMaxQPS(0.001))
+			qr.SetMaxWait(time.Millisecond)
+			// use up the burst
+			require.NoError(t, qr.Throttle(context.Background()))
+			return qr
+		},
+		wantCode: vtrpcpb.Code_RESOURCE_EXHAUSTED,
+	}, {
+		name: "concurrency",
+		rule: func() *rules.Rule {
+			qr := rules.NewQueryRule("concurrency", "r1", rules.QRLimitConcurrency)
+			require.NoError(t, qr.SetMaxConcurrency(1))
+			return qr
+		},
+		wantQuery: "select * from test_table where `name` = 1 limit 1000",
+	}, {
+		name: "concurrency exhausted",
+		rule: func() *rules.Rule {
+			qr := rules.NewQueryRule("concurrency", "r1", rules.QRLimitConcurrency)
+			require.NoError(t, qr.SetMaxConcurrency(1))
+			qr.SetMaxWait(time.Millisecond)
+			_, err := qr.AcquireConcurrencySlot(context.Background())
+			require.NoError(t, err)
+			return qr
+		},
+		wantCode: vtrpcpb.Code_RESOURCE_EXHAUSTED,
+	}}
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			db := setUpQueryExecutorTest(t)
+			defer db.Close()
+			if tcase.wantQuery != "" {
+				db.AddQuery(tcase.wantQuery, &sqltypes.Result{Fields: getTestTableFields()})
+			}
+
+			qr := tcase.rule()
+			qr.SetQueryCond("select.*")
+			qr.AddTableCond("test_table")
+			rulesName := "queryRuleActions"
+			qrs := rules.New()
+			qrs.Add(qr)
+
+			ctx := context.Background()
+			tsv := newTestTabletServer(ctx, noFlags, db)
+			defer tsv.StopService()
+			tsv.qe.queryRuleSources.UnRegisterSource(rulesName)
+			tsv.qe.queryRuleSources.RegisterSource(rulesName)
+			defer tsv.qe.queryRuleSources.UnRegisterSource(rulesName)
+			require.NoError(t, tsv.qe.queryRuleSources.SetRules(rulesName, qrs))
+
+			qre := newTestQueryExecutor(ctx, tsv, query, 0)
+			_, err := qre.Execute()
+			if tcase.wantCode != vtrpcpb.Code_OK {
+				assert.Equal(t, tcase.wantCode, vterrors.Code(err), "%v", err)
+				return
+			}
+			require.NoError(t, err)
+			// a concurrency slot is released once the query is done
+			if qr.Action() == rules.QRLimitConcurrency {
+				release, err := qr.AcquireConcurrencySlot(ctx)
+				require.NoError(t, err)
+				release()
+			}
+		})
+	}
+}
+
 func TestReplaceSchemaName(t *testing.T) {
 	db := setUpQueryExecutorTest(t)
 	defer db.Close()
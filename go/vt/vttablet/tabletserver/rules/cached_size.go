@@ -45,7 +45,7 @@ func (cached *Rule) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(256)
+		size += int64(320)
 	}
 	// field Description string
 	size += hack.RuntimeAllocSize(int64(len(cached.Description)))
@@ -79,6 +79,12 @@ func (cached *Rule) CachedSize(alloc bool) int64 {
 			size += elem.CachedSize(false)
 		}
 	}
+	// field hint string
+	size += hack.RuntimeAllocSize(int64(len(cached.hint)))
+	// field rewrite string
+	size += hack.RuntimeAllocSize(int64(len(cached.rewrite)))
+	// field limits *vitess.io/vitess/go/vt/vttablet/tabletserver/rules.ruleLimits
+	size += cached.limits.CachedSize(true)
 	return size
 }
 func (cached *Rules) CachedSize(alloc bool) int64 {
@@ -128,3 +134,17 @@ func (cached *namedRegexp) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *ruleLimits) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(16)
+	}
+	// field limiter *golang.org/x/time/rate.Limiter
+	if cached.limiter != nil {
+		size += hack.RuntimeAllocSize(int64(80))
+	}
+	return size
+}
@@ -21,11 +21,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
 	"regexp"
 	"strconv"
 	"time"
 
+	"golang.org/x/time/rate"
+
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -181,12 +184,26 @@ func (qrs *Rules) GetAction(
 	cancelCtx context.Context,
 	timeout time.Duration,
 	desc string) {
+	if qr := qrs.GetMatchingRule(ip, user, bindVars, marginComments); qr != nil {
+		return qr.act, qr.cancelCtx, qr.timeout, qr.Description
+	}
+	return QRContinue, nil, 0, ""
+}
+
+// GetMatchingRule runs the input against the rules engine and returns the first rule
+// whose action is not QRContinue, or nil if there is none.
+func (qrs *Rules) GetMatchingRule(
+	ip,
+	user string,
+	bindVars map[string]*querypb.BindVariable,
+	marginComments sqlparser.MarginComments,
+) *Rule {
 	for _, qr := range qrs.rules {
 		if act := qr.GetAction(ip, user, bindVars, marginComments); act != QRContinue {
-			return act, qr.cancelCtx, qr.timeout, qr.Description
+			return qr
 		}
 	}
-	return QRContinue, nil, 0, ""
+	return nil
 }
 
 //-----------------------------------------------
@@ -225,6 +242,33 @@ type Rule struct {
 
 	// a rule can timeout.
 	timeout time.Duration
+
+	// maxQPS is the rate at which a QRThrottle rule lets matching queries through.
+	maxQPS float64
+
+	// maxConcurrency is the number of matching queries a QRLimitConcurrency rule
+	// lets execute at the same time.
+	maxConcurrency int
+
+	// maxWait bounds how long a QRThrottle or QRLimitConcurrency rule delays
+	// a query before failing it. Zero means until the query times out.
+	maxWait time.Duration
+
+	// hint is the optimizer hint a QRHint rule adds to matching queries.
+	hint string
+
+	// rewrite is the query a QRRewrite rule executes instead of matching queries.
+	rewrite string
+
+	// limits are shared by all copies of the rule, so that they apply across
+	// all the query plans the rule was filtered into.
+	limits *ruleLimits
+}
+
+// ruleLimits holds the state of a QRThrottle or QRLimitConcurrency rule.
+type ruleLimits struct {
+	limiter *rate.Limiter
+	slots   chan struct{}
 }
 
 type namedRegexp struct {
@@ -270,6 +314,11 @@ func (qr *Rule) Equal(other *Rule) bool {
 		qr.leadingComment.Equal(other.leadingComment) &&
 		qr.trailingComment.Equal(other.trailingComment) &&
 		qr.timeout == other.timeout &&
+		qr.maxQPS == other.maxQPS &&
+		qr.maxConcurrency == other.maxConcurrency &&
+		qr.maxWait == other.maxWait &&
+		qr.hint == other.hint &&
+		qr.rewrite == other.rewrite &&
 		reflect.DeepEqual(qr.plans, other.plans) &&
 		reflect.DeepEqual(qr.tableNames, other.tableNames) &&
 		reflect.DeepEqual(qr.bindVarConds, other.bindVarConds) &&
@@ -289,6 +338,12 @@ func (qr *Rule) Copy() (newqr *Rule) {
 		act:             qr.act,
 		cancelCtx:       qr.cancelCtx,
 		timeout:         qr.timeout,
+		maxQPS:          qr.maxQPS,
+		maxConcurrency:  qr.maxConcurrency,
+		maxWait:         qr.maxWait,
+		hint:            qr.hint,
+		rewrite:         qr.rewrite,
+		limits:          qr.limits,
 	}
 	if qr.plans != nil {
 		newqr.plans = make([]planbuilder.PlanType, len(qr.plans))
@@ -340,6 +395,21 @@ func (qr *Rule) MarshalJSON() ([]byte, error) {
 	if qr.timeout != 0 {
 		safeEncode(b, `,"Timeout":`, qr.timeout)
 	}
+	if qr.maxQPS != 0 {
+		safeEncode(b, `,"MaxQPS":`, qr.maxQPS)
+	}
+	if qr.maxConcurrency != 0 {
+		safeEncode(b, `,"MaxConcurrency":`, qr.maxConcurrency)
+	}
+	if qr.maxWait != 0 {
+		safeEncode(b, `,"MaxWait":`, qr.maxWait.String())
+	}
+	if qr.hint != "" {
+		safeEncode(b, `,"Hint":`, qr.hint)
+	}
+	if qr.rewrite != "" {
+		safeEncode(b, `,"Rewrite":`, qr.rewrite)
+	}
 	_, _ = b.WriteString("}")
 	return b.Bytes(), nil
 }
@@ -395,6 +465,59 @@ func (qr *Rule) SetTrailingCommentCond(pattern string) (err error) {
 	return
 }
 
+// SetMaxQPS sets the rate, in queries per second, at which a QRThrottle rule
+// lets matching queries through. Queries over the rate are delayed.
+func (qr *Rule) SetMaxQPS(maxQPS float64) error {
+	if maxQPS <= 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "MaxQPS must be positive: %v", maxQPS)
+	}
+	// allow bursts of up to one second worth of queries
+	burst := int(math.Ceil(maxQPS))
+	qr.maxQPS = maxQPS
+	qr.ensureLimits().limiter = rate.NewLimiter(rate.Limit(maxQPS), burst)
+	return nil
+}
+
+// SetMaxConcurrency sets the number of matching queries a QRLimitConcurrency
+// rule lets execute at the same time. Queries over the limit are delayed.
+func (qr *Rule) SetMaxConcurrency(maxConcurrency int) error {
+	if maxConcurrency <= 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "MaxConcurrency must be positive: %v", maxConcurrency)
+	}
+	qr.maxConcurrency = maxConcurrency
+	qr.ensureLimits().slots = make(chan struct{}, maxConcurrency)
+	return nil
+}
+
+// SetMaxWait sets how long a QRThrottle or QRLimitConcurrency rule may delay
+// a query before failing it. Zero means until the query times out.
+func (qr *Rule) SetMaxWait(maxWait time.Duration) {
+	qr.maxWait = maxWait
+}
+
+// SetHint sets the optimizer hint a QRHint rule adds to matching queries,
+// e.g. MAX_EXECUTION_TIME(1000).
+func (qr *Rule) SetHint(hint string) {
+	qr.hint = hint
+}
+
+// SetRewrite sets the query a QRRewrite rule executes instead of matching
+// queries. The query may use the bind variables of the original query.
+func (qr *Rule) SetRewrite(query string) error {
+	if _, err := sqlparser.Parse(query); err != nil {
+		return vterrors.Wrapf(err, "invalid Rewrite query %s", query)
+	}
+	qr.rewrite = query
+	return nil
+}
+
+func (qr *Rule) ensureLimits() *ruleLimits {
+	if qr.limits == nil {
+		qr.limits = &ruleLimits{}
+	}
+	return qr.limits
+}
+
 // makeExact forces a full string match for the regex instead of substring
 func makeExact(pattern string) string {
 	return fmt.Sprintf("^%s$", pattern)
@@ -518,6 +641,71 @@ func (qr *Rule) GetAction(
 	return qr.act
 }
 
+// Action returns the action of the rule.
+func (qr *Rule) Action() Action {
+	return qr.act
+}
+
+// CancelCtx returns the context that cancels the rule, if any.
+func (qr *Rule) CancelCtx() context.Context {
+	return qr.cancelCtx
+}
+
+// Timeout returns how long a QRBuffer rule buffers queries.
+func (qr *Rule) Timeout() time.Duration {
+	return qr.timeout
+}
+
+// Hint returns the optimizer hint of a QRHint rule.
+func (qr *Rule) Hint() string {
+	return qr.hint
+}
+
+// Rewrite returns the replacement query of a QRRewrite rule.
+func (qr *Rule) Rewrite() string {
+	return qr.rewrite
+}
+
+// Throttle blocks until a QRThrottle rule lets a query through. It fails if
+// that takes longer than the rule's MaxWait or the context's deadline.
+func (qr *Rule) Throttle(ctx context.Context) error {
+	if qr.limits == nil || qr.limits.limiter == nil {
+		return nil
+	}
+	if qr.maxWait > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, qr.maxWait)
+		defer cancel()
+	}
+	return qr.limits.limiter.Wait(ctx)
+}
+
+// AcquireConcurrencySlot blocks until a QRLimitConcurrency rule lets a query
+// execute, and returns the function that must be called once the query is done.
+// It fails if that takes longer than the rule's MaxWait or the context's deadline.
+func (qr *Rule) AcquireConcurrencySlot(ctx context.Context) (release func(), err error) {
+	if qr.limits == nil || qr.limits.slots == nil {
+		return func() {}, nil
+	}
+	slots := qr.limits.slots
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	default:
+	}
+	if qr.maxWait > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, qr.maxWait)
+		defer cancel()
+	}
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "timed out waiting for one of %d concurrency slots", qr.maxConcurrency)
+	}
+}
+
 func reMatch(re *regexp.Regexp, val string) bool {
 	return re == nil || re.MatchString(val)
 }
@@ -574,6 +762,14 @@ const (
 	QRFail
 	QRFailRetry
 	QRBuffer
+	// QRThrottle delays matching queries to at most MaxQPS per second.
+	QRThrottle
+	// QRLimitConcurrency delays matching queries so that at most MaxConcurrency execute at once.
+	QRLimitConcurrency
+	// QRHint adds an optimizer hint to matching queries.
+	QRHint
+	// QRRewrite executes the Rewrite query instead of matching queries.
+	QRRewrite
 )
 
 // MarshalJSON marshals to JSON.
@@ -587,6 +783,14 @@ func (act Action) MarshalJSON() ([]byte, error) {
 		str = "FAIL_RETRY"
 	case QRBuffer:
 		str = "BUFFER"
+	case QRThrottle:
+		str = "THROTTLE"
+	case QRLimitConcurrency:
+		str = "LIMIT_CONCURRENCY"
+	case QRHint:
+		str = "HINT"
+	case QRRewrite:
+		str = "REWRITE"
 	default:
 		str = "INVALID"
 	}
@@ -875,9 +1079,10 @@ func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 	for k, v := range ruleInfo {
 		var sv string
 		var lv []any
+		var nv float64
 		var ok bool
 		switch k {
-		case "Name", "Description", "RequestIP", "User", "Query", "Action", "LeadingComment", "TrailingComment":
+		case "Name", "Description", "RequestIP", "User", "Query", "Action", "LeadingComment", "TrailingComment", "MaxWait", "Hint", "Rewrite":
 			sv, ok = v.(string)
 			if !ok {
 				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want string for %s", k)
@@ -887,6 +1092,15 @@ func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 			if !ok {
 				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want list for %s", k)
 			}
+		case "MaxQPS", "MaxConcurrency":
+			num, ok := v.(json.Number)
+			if !ok {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want number for %s", k)
+			}
+			nv, err = num.Float64()
+			if err != nil {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want number for %s: %s", k, num)
+			}
 		default:
 			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unrecognized tag %s", k)
 		}
@@ -959,11 +1173,52 @@ func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 				qr.act = QRFailRetry
 			case "BUFFER":
 				qr.act = QRBuffer
+			case "THROTTLE":
+				qr.act = QRThrottle
+			case "LIMIT_CONCURRENCY":
+				qr.act = QRLimitConcurrency
+			case "HINT":
+				qr.act = QRHint
+			case "REWRITE":
+				qr.act = QRRewrite
 			default:
 				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid Action %s", sv)
 			}
+		case "MaxQPS":
+			if err = qr.SetMaxQPS(nv); err != nil {
+				return nil, err
+			}
+		case "MaxConcurrency":
+			if nv != math.Trunc(nv) {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want integer for MaxConcurrency")
+			}
+			if err = qr.SetMaxConcurrency(int(nv)); err != nil {
+				return nil, err
+			}
+		case "MaxWait":
+			maxWait, err := time.ParseDuration(sv)
+			if err != nil {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid MaxWait %s: %v", sv, err)
+			}
+			qr.SetMaxWait(maxWait)
+		case "Hint":
+			qr.SetHint(sv)
+		case "Rewrite":
+			if err = qr.SetRewrite(sv); err != nil {
+				return nil, err
+			}
 		}
 	}
+	switch {
+	case qr.act == QRThrottle && qr.maxQPS == 0:
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "MaxQPS is required for Action THROTTLE")
+	case qr.act == QRLimitConcurrency && qr.maxConcurrency == 0:
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "MaxConcurrency is required for Action LIMIT_CONCURRENCY")
+	case qr.act == QRHint && qr.hint == "":
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Hint is required for Action HINT")
+	case qr.act == QRRewrite && qr.rewrite == "":
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Rewrite is required for Action REWRITE")
+	}
 	return qr, nil
 }
 
@@ -18,6 +18,7 @@ package rules
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"reflect"
 	"regexp"
@@ -26,6 +27,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/sqlparser"
@@ -625,6 +627,79 @@ func TestImport(t *testing.T) {
 	}
 }
 
+func TestImportRuleActions(t *testing.T) {
+	var qrs = New()
+	jsondata := `[{
+		"Description": "throttle",
+		"Name": "r1",
+		"Query": "select .* from a",
+		"Action": "THROTTLE",
+		"MaxQPS": 2.5,
+		"MaxWait": "1s"
+	},{
+		"Description": "concurrency",
+		"Name": "r2",
+		"Action": "LIMIT_CONCURRENCY",
+		"MaxConcurrency": 4
+	},{
+		"Description": "hint",
+		"Name": "r3",
+		"Action": "HINT",
+		"Hint": "MAX_EXECUTION_TIME(1000)"
+	},{
+		"Description": "rewrite",
+		"Name": "r4",
+		"Action": "REWRITE",
+		"Rewrite": "select a from t where id = :id limit 10"
+	}]`
+	err := qrs.UnmarshalJSON([]byte(jsondata))
+	require.NoError(t, err)
+	assert.Equal(t, compacted(jsondata), marshalled(qrs))
+
+	other := New()
+	err = other.UnmarshalJSON([]byte(jsondata))
+	require.NoError(t, err)
+	assert.True(t, qrs.Equal(other))
+
+	assert.Equal(t, QRThrottle, qrs.Find("r1").Action())
+	assert.Equal(t, QRLimitConcurrency, qrs.Find("r2").Action())
+	assert.Equal(t, "MAX_EXECUTION_TIME(1000)", qrs.Find("r3").Hint())
+	assert.Equal(t, "select a from t where id = :id limit 10", qrs.Find("r4").Rewrite())
+}
+
+func TestThrottle(t *testing.T) {
+	qr := NewQueryRule("throttle", "r1", QRThrottle)
+	require.NoError(t, qr.SetMaxQPS(1))
+	qr.SetMaxWait(10 * time.Millisecond)
+
+	// copies of the rule, such as the ones filtered into query plans, share the limit
+	qrCopy := qr.FilterByPlan("select * from a", planbuilder.PlanSelect, []string{"a"})
+	require.NotNil(t, qrCopy)
+
+	ctx := context.Background()
+	require.NoError(t, qr.Throttle(ctx))
+	assert.Error(t, qrCopy.Throttle(ctx))
+}
+
+func TestAcquireConcurrencySlot(t *testing.T) {
+	qr := NewQueryRule("concurrency", "r1", QRLimitConcurrency)
+	require.NoError(t, qr.SetMaxConcurrency(1))
+	qr.SetMaxWait(10 * time.Millisecond)
+	qrCopy := qr.Copy()
+
+	ctx := context.Background()
+	release, err := qr.AcquireConcurrencySlot(ctx)
+	require.NoError(t, err)
+
+	_, err = qrCopy.AcquireConcurrencySlot(ctx)
+	assert.EqualError(t, err, "timed out waiting for one of 1 concurrency slots")
+
+	release()
+	release, err = qrCopy.AcquireConcurrencySlot(ctx)
+	require.NoError(t, err)
+	release()
+}
+
 type ValidJSONCase struct {
 	input string
 	op    Operator
@@ -734,6 +809,15 @@ var invalidjsons = []InvalidJSONCase{
 	{`[{"BindVarConds": [{"Name": "a", "OnAbsent": true, "OnMismatch": true, "Operator": "NOMATCH", "Value": "["}]}]`, "processing [: error parsing regexp: missing closing ]: `[$`"},
 	{`[{"Action": 1 }]`, "want string for Action"},
 	{`[{"Action": "foo" }]`, "invalid Action foo"},
+	{`[{"Action": "THROTTLE" }]`, "MaxQPS is required for Action THROTTLE"},
+	{`[{"Action": "THROTTLE", "MaxQPS": "10" }]`, "want number for MaxQPS"},
+	{`[{"Action": "THROTTLE", "MaxQPS": 0 }]`, "MaxQPS must be positive: 0"},
+	{`[{"Action": "LIMIT_CONCURRENCY" }]`, "MaxConcurrency is required for Action LIMIT_CONCURRENCY"},
+	{`[{"Action": "LIMIT_CONCURRENCY", "MaxConcurrency": 1.5 }]`, "want integer for MaxConcurrency"},
+	{`[{"Action": "LIMIT_CONCURRENCY", "MaxConcurrency": 2, "MaxWait": "a" }]`, "invalid MaxWait a: time: invalid duration \"a\""},
+	{`[{"Action": "HINT" }]`, "Hint is required for Action HINT"},
+	{`[{"Action": "REWRITE" }]`, "Rewrite is required for Action REWRITE"},
+	{`[{"Action": "REWRITE", "Rewrite": "selec 1" }]`, "invalid Rewrite query selec 1: syntax error at position 6 near 'selec'"},
 }
 
 func TestInvalidJSON(t *testing.T) {
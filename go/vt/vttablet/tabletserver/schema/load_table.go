@@ -107,6 +107,13 @@ func loadMessageInfo(ta *Table, comment string) error {
 
 	ta.MessageInfo.MaxBackoff, _ = getDuration(keyvals, "vt_max_backoff")
 
+	// dead-lettering is optional: without vt_max_attempts messages are retried forever
+	ta.MessageInfo.MaxAttempts, _ = getNum(keyvals, "vt_max_attempts")
+	ta.MessageInfo.DeadLetterTable = keyvals["vt_dead_letter_table"]
+	if ta.MessageInfo.DeadLetterTable != "" && ta.MessageInfo.MaxAttempts == 0 {
+		return fmt.Errorf("vt_dead_letter_table requires vt_max_attempts: %s", ta.Name.String())
+	}
+
 	// these columns are required for message manager to function properly, but only
 	// id is required to be streamed to subscribers
 	requiredCols := []string{
@@ -125,6 +125,16 @@ type MessageInfo struct {
 	// MaxBackoff specifies the longest duration message manager
 	// should wait before rescheduling a message
 	MaxBackoff time.Duration
+
+	// MaxAttempts specifies the number of delivery attempts
+	// after which a message is dead-lettered. Zero means
+	// messages are retried forever.
+	MaxAttempts int
+
+	// DeadLetterTable specifies the table dead messages are
+	// moved to. If empty, dead messages are left in place and
+	// marked failed by setting time_next to NULL.
+	DeadLetterTable string
 }
 
 // NewTable creates a new Table.
@@ -31,6 +31,7 @@ import (
 
 	"vitess.io/vitess/go/mysql/fakesqldb"
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/vt/vtgate/fakerpcvtgateconn"
 	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
@@ -433,6 +434,71 @@ func TestExecutorReadAllTransactions(t *testing.T) {
 	}
 }
 
+func TestExecutorUnresolvedTransactions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	txe, tsv, db := newTestTxExecutor(t, ctx)
+	defer db.Close()
+	defer tsv.StopService()
+
+	db.AddQueryPattern(
+		"select t\\.dtid, t\\.state, t\\.time_created, p\\.keyspace, p\\.shard\\s+from _vt\\.dt_state t\\s+join _vt\\.dt_participant p on t\\.dtid = p\\.dtid\\s+where t\\.time_created < .*",
+		&sqltypes.Result{
+			Fields: []*querypb.Field{
+				{Type: sqltypes.VarChar},
+				{Type: sqltypes.Int64},
+				{Type: sqltypes.Int64},
+				{Type: sqltypes.VarChar},
+				{Type: sqltypes.VarChar},
+			},
+			Rows: [][]sqltypes.Value{{
+				sqltypes.NewVarBinary("dtid0"),
+				sqltypes.NewInt64(int64(querypb.TransactionState_PREPARE)),
+				sqltypes.NewVarBinary("1"),
+				sqltypes.NewVarBinary("ks01"),
+				sqltypes.NewVarBinary("shard01"),
+			}, {
+				sqltypes.NewVarBinary("dtid0"),
+				sqltypes.NewInt64(int64(querypb.TransactionState_PREPARE)),
+				sqltypes.NewVarBinary("1"),
+				sqltypes.NewVarBinary("ks01"),
+				sqltypes.NewVarBinary("shard02"),
+			}, {
+				sqltypes.NewVarBinary("dtid1"),
+				sqltypes.NewInt64(int64(querypb.TransactionState_COMMIT)),
+				sqltypes.NewVarBinary("2"),
+				sqltypes.NewVarBinary("ks02"),
+				sqltypes.NewVarBinary("-80"),
+			}},
+		})
+	got, err := txe.UnresolvedTransactions(30 * time.Second)
+	require.NoError(t, err)
+	want := []*querypb.TransactionMetadata{{
+		Dtid:        "dtid0",
+		State:       querypb.TransactionState_PREPARE,
+		TimeCreated: 1,
+		Participants: []*querypb.Target{{
+			Keyspace:   "ks01",
+			Shard:      "shard01",
+			TabletType: topodatapb.TabletType_PRIMARY,
+		}, {
+			Keyspace:   "ks01",
+			Shard:      "shard02",
+			TabletType: topodatapb.TabletType_PRIMARY,
+		}},
+	}, {
+		Dtid:        "dtid1",
+		State:       querypb.TransactionState_COMMIT,
+		TimeCreated: 2,
+		Participants: []*querypb.Target{{
+			Keyspace:   "ks02",
+			Shard:      "-80",
+			TabletType: topodatapb.TabletType_PRIMARY,
+		}},
+	}}
+	utils.MustMatch(t, want, got)
+}
+
 // These vars and types are used only for TestExecutorResolveTransaction
 var dtidCh = make(chan string)
 
@@ -524,6 +590,12 @@ func TestNoTwopc(t *testing.T) {
 			_, _, _, err := txe.ReadTwopcInflight()
 			return err
 		},
+	}, {
+		desc: "UnresolvedTransactions",
+		fun: func() error {
+			_, err := txe.UnresolvedTransactions(0)
+			return err
+		},
 	}}
 
 	want := "2pc is not enabled"
@@ -78,6 +78,9 @@ const (
 	NotEqual
 	// IsNotNull is used to filter a column if it is NULL
 	IsNotNull
+	// Expression is used to filter a row with an expression evaluated by the evalengine,
+	// for constraints like IN, OR, LIKE or BETWEEN that have no dedicated opcode
+	Expression
 )
 
 // Filter contains opcodes for filtering.
@@ -94,6 +97,10 @@ type Filter struct {
 	Vindex        vindexes.Vindex
 	VindexColumns []int
 	KeyRange      *topodatapb.KeyRange
+
+	// Expr is the evalengine expression for Expression filters.
+	// Its columns are the column numbers of the table.
+	Expr evalengine.Expr
 }
 
 // ColExpr represents a column expression.
@@ -213,6 +220,7 @@ func (plan *Plan) filter(values, result []sqltypes.Value, charsets []collations.
 	if len(result) != len(plan.ColExprs) {
 		return false, fmt.Errorf("expected %d values in result slice", len(plan.ColExprs))
 	}
+	var env *evalengine.ExpressionEnv
 	for _, filter := range plan.Filters {
 		switch filter.Opcode {
 		case VindexMatch:
@@ -227,6 +235,18 @@ func (plan *Plan) filter(values, result []sqltypes.Value, charsets []collations.
 			if values[filter.ColNum].IsNull() {
 				return false, nil
 			}
+		case Expression:
+			if env == nil {
+				env = evalengine.EmptyExpressionEnv()
+				env.Row = values
+			}
+			res, err := env.Evaluate(filter.Expr)
+			if err != nil {
+				return false, err
+			}
+			if !res.ToBoolean() {
+				return false, nil
+			}
 		default:
 			match, err := compare(filter.Opcode, values[filter.ColNum], filter.Value, charsets[filter.ColNum])
 			if err != nil {
@@ -509,57 +529,34 @@ func (plan *Plan) analyzeWhere(vschema *localVSchema, where *sqlparser.Where) er
 	for _, expr := range exprs {
 		switch expr := expr.(type) {
 		case *sqlparser.ComparisonExpr:
-			opcode, err := getOpcode(expr)
+			filter, ok, err := plan.analyzeComparison(expr)
 			if err != nil {
 				return err
 			}
-			qualifiedName, ok := expr.Left.(*sqlparser.ColName)
 			if !ok {
-				return fmt.Errorf("unexpected: %v", sqlparser.String(expr))
-			}
-			if !qualifiedName.Qualifier.IsEmpty() {
-				return fmt.Errorf("unsupported qualifier for column: %v", sqlparser.String(qualifiedName))
-			}
-			colnum, err := findColumn(plan.Table, qualifiedName.Name)
-			if err != nil {
-				return err
-			}
-			val, ok := expr.Right.(*sqlparser.Literal)
-			if !ok {
-				return fmt.Errorf("unexpected: %v", sqlparser.String(expr))
-			}
-			//StrVal is varbinary, we do not support varchar since we would have to implement all collation types
-			if val.Type != sqlparser.IntVal && val.Type != sqlparser.StrVal {
-				return fmt.Errorf("unexpected: %v", sqlparser.String(expr))
-			}
-			pv, err := evalengine.Translate(val, nil)
-			if err != nil {
-				return err
-			}
-			env := evalengine.EmptyExpressionEnv()
-			resolved, err := env.Evaluate(pv)
-			if err != nil {
-				return err
+				if err := plan.analyzeExpression(expr); err != nil {
+					return err
+				}
+				continue
 			}
-			plan.Filters = append(plan.Filters, Filter{
-				Opcode: opcode,
-				ColNum: colnum,
-				Value:  resolved.Value(collations.Default()),
-			})
+			plan.Filters = append(plan.Filters, filter)
 		case *sqlparser.FuncExpr:
 			if !expr.Name.EqualString("in_keyrange") {
-				return fmt.Errorf("unsupported constraint: %v", sqlparser.String(expr))
+				if err := plan.analyzeExpression(expr); err != nil {
+					return err
+				}
+				continue
 			}
 			if err := plan.analyzeInKeyRange(vschema, expr.Exprs); err != nil {
 				return err
 			}
 		case *sqlparser.IsExpr: // Needed for CreateLookupVindex with ignore_nulls
-			if expr.Right != sqlparser.IsNotNullOp {
-				return fmt.Errorf("unsupported constraint: %v", sqlparser.String(expr))
-			}
 			qualifiedName, ok := expr.Left.(*sqlparser.ColName)
-			if !ok {
-				return fmt.Errorf("unexpected: %v", sqlparser.String(expr))
+			if expr.Right != sqlparser.IsNotNullOp || !ok {
+				if err := plan.analyzeExpression(expr); err != nil {
+					return err
+				}
+				continue
 			}
 			if !qualifiedName.Qualifier.IsEmpty() {
 				return fmt.Errorf("unsupported qualifier for column: %v", sqlparser.String(qualifiedName))
@@ -573,12 +570,99 @@ func (plan *Plan) analyzeWhere(vschema *localVSchema, where *sqlparser.Where) er
 				ColNum: colnum,
 			})
 		default:
-			return fmt.Errorf("unsupported constraint: %v", sqlparser.String(expr))
+			if err := plan.analyzeExpression(expr); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
+// analyzeComparison builds a dedicated filter for a comparison between a column
+// and a literal. It returns false if the comparison has any other shape, in which
+// case it must be evaluated as an expression.
+func (plan *Plan) analyzeComparison(expr *sqlparser.ComparisonExpr) (Filter, bool, error) {
+	opcode, err := getOpcode(expr)
+	if err != nil {
+		return Filter{}, false, nil
+	}
+	qualifiedName, ok := expr.Left.(*sqlparser.ColName)
+	if !ok {
+		return Filter{}, false, nil
+	}
+	if !qualifiedName.Qualifier.IsEmpty() {
+		return Filter{}, false, fmt.Errorf("unsupported qualifier for column: %v", sqlparser.String(qualifiedName))
+	}
+	colnum, err := findColumn(plan.Table, qualifiedName.Name)
+	if err != nil {
+		return Filter{}, false, err
+	}
+	val, ok := expr.Right.(*sqlparser.Literal)
+	//StrVal is varbinary, we do not support varchar since we would have to implement all collation types
+	if !ok || (val.Type != sqlparser.IntVal && val.Type != sqlparser.StrVal) {
+		return Filter{}, false, nil
+	}
+	pv, err := evalengine.Translate(val, nil)
+	if err != nil {
+		return Filter{}, false, err
+	}
+	env := evalengine.EmptyExpressionEnv()
+	resolved, err := env.Evaluate(pv)
+	if err != nil {
+		return Filter{}, false, err
+	}
+	return Filter{
+		Opcode: opcode,
+		ColNum: colnum,
+		Value:  resolved.Value(collations.Default()),
+	}, true, nil
+}
+
+// analyzeExpression adds a filter that evaluates the constraint with the evalengine,
+// resolving its columns against the columns of the table.
+func (plan *Plan) analyzeExpression(expr sqlparser.Expr) error {
+	var colErr error
+	cfg := &evalengine.Config{
+		ResolveColumn: func(col *sqlparser.ColName) (int, error) {
+			if !col.Qualifier.IsEmpty() {
+				colErr = fmt.Errorf("unsupported qualifier for column: %v", sqlparser.String(col))
+				return 0, colErr
+			}
+			colnum, err := findColumn(plan.Table, col.Name)
+			if err != nil {
+				colErr = err
+				return 0, err
+			}
+			return colnum, nil
+		},
+		ResolveType: func(e sqlparser.Expr) (evalengine.Type, bool) {
+			// the types of the values are only known once the rows are decoded,
+			// but textual columns are compared using their collation
+			col, ok := e.(*sqlparser.ColName)
+			if !ok {
+				return evalengine.UnknownType(), false
+			}
+			colnum := plan.Table.FindColumn(col.Name)
+			if colnum < 0 {
+				return evalengine.UnknownType(), false
+			}
+			return evalengine.Type{Type: sqltypes.Unknown, Coll: collations.ID(plan.Table.Fields[colnum].Charset)}, true
+		},
+	}
+	pv, err := evalengine.Translate(expr, cfg)
+	if colErr != nil {
+		return colErr
+	}
+	if err != nil {
+		return fmt.Errorf("unsupported constraint: %v", sqlparser.String(expr))
+	}
+	plan.Filters = append(plan.Filters, Filter{
+		Opcode: Expression,
+		Expr:   pv,
+	})
+	return nil
+}
+
 // splitAndExpression breaks up the Expr into AND-separated conditions
 // and appends them to filters, which can be shuffled and recombined
 // as needed.
@@ -781,3 +781,98 @@ func TestCompare(t *testing.T) {
 		})
 	}
 }
+
+func TestPlanBuilderFilterExpression(t *testing.T) {
+	t1 := &Table{
+		Name: "t1",
+		Fields: []*querypb.Field{{
+			Name:    "id",
+			Type:    sqltypes.Int64,
+			Charset: collations.CollationBinaryID,
+			Flags:   uint32(querypb.MySqlFlag_BINARY_FLAG | querypb.MySqlFlag_NUM_FLAG),
+		}, {
+			Name:    "tenant",
+			Type:    sqltypes.Int64,
+			Charset: collations.CollationBinaryID,
+			Flags:   uint32(querypb.MySqlFlag_BINARY_FLAG | querypb.MySqlFlag_NUM_FLAG),
+		}, {
+			Name:    "val",
+			Type:    sqltypes.VarChar,
+			Charset: uint32(collations.CollationUtf8mb4ID),
+		}},
+	}
+	rows := [][]sqltypes.Value{
+		{sqltypes.NewInt64(1), sqltypes.NewInt64(10), sqltypes.NewVarChar("abc")},
+		{sqltypes.NewInt64(2), sqltypes.NewInt64(20), sqltypes.NewVarChar("ABD")},
+		{sqltypes.NewInt64(3), sqltypes.NewInt64(30), sqltypes.NULL},
+		{sqltypes.NewInt64(4), sqltypes.NULL, sqltypes.NewVarChar("xyz")},
+	}
+	testcases := []struct {
+		inFilter string
+		outIDs   []int64
+		outErr   string
+	}{{
+		inFilter: "select * from t1 where tenant in (10, 30)",
+		outIDs:   []int64{1, 3},
+	}, {
+		inFilter: "select * from t1 where tenant not in (10, 30)",
+		outIDs:   []int64{2},
+	}, {
+		inFilter: "select * from t1 where tenant = 10 or val = 'xyz'",
+		outIDs:   []int64{1, 4},
+	}, {
+		inFilter: "select * from t1 where val like 'ab%'",
+		outIDs:   []int64{1, 2},
+	}, {
+		inFilter: "select * from t1 where id between 2 and 3",
+		outIDs:   []int64{2, 3},
+	}, {
+		inFilter: "select * from t1 where val is null",
+		outIDs:   []int64{3},
+	}, {
+		inFilter: "select * from t1 where upper(val) = 'ABD' and id > 1",
+		outIDs:   []int64{2},
+	}, {
+		inFilter: "select * from t1 where id = tenant / 10",
+		outIDs:   []int64{1, 2, 3},
+	}, {
+		inFilter: "select * from t1 where in_keyrange(id, 'hash', '-80') and tenant in (10, 20)",
+		outIDs:   []int64{1, 2},
+	}, {
+		inFilter: "select * from t1 where tenant in (1, 2) or t1.id = 1",
+		outErr:   "unsupported qualifier for column: t1.id",
+	}, {
+		inFilter: "select * from t1 where none in (1, 2)",
+		outErr:   "column `none` not found in table t1",
+	}, {
+		inFilter: "select * from t1 where id in (select id from t2)",
+		outErr:   "unsupported constraint: id in (select id from t2)",
+	}}
+	for _, tcase := range testcases {
+		t.Run(tcase.inFilter, func(t *testing.T) {
+			plan, err := buildPlan(t1, testLocalVSchema, &binlogdatapb.Filter{
+				Rules: []*binlogdatapb.Rule{{Match: "t1", Filter: tcase.inFilter}},
+			})
+			if tcase.outErr != "" {
+				assert.Nil(t, plan)
+				assert.EqualError(t, err, tcase.outErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var ids []int64
+			charsets := []collations.ID{collations.CollationBinaryID, collations.CollationBinaryID, collations.CollationUtf8mb4ID}
+			for _, row := range rows {
+				result := make([]sqltypes.Value, len(plan.ColExprs))
+				ok, err := plan.filter(row, result, charsets)
+				require.NoError(t, err)
+				if ok {
+					id, err := result[0].ToInt64()
+					require.NoError(t, err)
+					ids = append(ids, id)
+				}
+			}
+			require.Equal(t, tcase.outIDs, ids)
+		})
+	}
+}
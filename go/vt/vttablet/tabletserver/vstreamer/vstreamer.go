@@ -108,8 +108,10 @@ type streamerPlan struct {
 //	"select * from t where in_keyrange('-80')", same as "-80",
 //	"select * from t where in_keyrange(col1, 'hash', '-80')",
 //	"select col1, col2 from t where...",
-//	"select col1, keyspace_id() from t where...".
-//	Only "in_keyrange" and limited comparison operators (see enum Opcode in planbuilder.go) are supported in the where clause.
+//	"select col1, keyspace_id() from t where...",
+//	"select * from t where tenant_id in (1, 2) or name like 'a%'".
+//	The where clause can use "in_keyrange" and any scalar expression on the columns of the
+//	table that the evalengine supports, like IN, OR, LIKE, BETWEEN, IS NULL or function calls.
 //	Other constructs like joins, group by, etc. are not supported.
 //
 // vschema: the current vschema. This value can later be changed through the SetVSchema method.
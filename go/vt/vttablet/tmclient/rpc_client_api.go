@@ -60,6 +60,13 @@ func init() {
 }
 
 // TabletManagerClient defines the interface used to talk to a remote tablet
+// VerifyBackupStream is the stream of a VerifyBackup call. Recv returns the
+// events logged during the verification, then a response holding the
+// verification, then io.EOF.
+type VerifyBackupStream interface {
+	Recv() (*tabletmanagerdatapb.VerifyBackupResponse, error)
+}
+
 type TabletManagerClient interface {
 	//
 	// Various read-only methods
@@ -246,6 +253,10 @@ type TabletManagerClient interface {
 	// RestoreFromBackup deletes local data and restores database from backup
 	RestoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.RestoreFromBackupRequest) (logutil.EventStream, error)
 
+	// VerifyBackup restores a backup into a scratch mysqld on the tablet's
+	// host and checks the restored data
+	VerifyBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.VerifyBackupRequest) (VerifyBackupStream, error)
+
 	// Throttler
 	CheckThrottler(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.CheckThrottlerRequest) (*tabletmanagerdatapb.CheckThrottlerResponse, error)
 
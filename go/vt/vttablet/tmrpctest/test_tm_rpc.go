@@ -38,6 +38,7 @@ import (
 	"vitess.io/vitess/go/vt/vttablet/tabletmanager"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
+	mysqlctlpb "vitess.io/vitess/go/vt/proto/mysqlctl"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
@@ -1243,6 +1244,8 @@ var testBackupConcurrency = int64(24)
 var testBackupAllowPrimary = false
 var testBackupCalled = false
 var testRestoreFromBackupCalled = false
+var testVerifyBackupCalled = false
+var testVerifyBackupName = "2023-06-01.000000.zone1-100"
 
 func (fra *fakeRPCTM) Backup(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.BackupRequest) error {
 	if fra.panics {
@@ -1317,6 +1320,54 @@ func tmRPCTestRestoreFromBackupPanic(ctx context.Context, t *testing.T, client t
 	expectHandleRPCPanic(t, "RestoreFromBackup", true /*verbose*/, err)
 }
 
+func (fra *fakeRPCTM) VerifyBackup(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.VerifyBackupRequest) (*mysqlctlpb.BackupVerification, error) {
+	if fra.panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	compare(fra.t, "VerifyBackup args", request.BackupName, testVerifyBackupName)
+	logStuff(logger, 10)
+	testVerifyBackupCalled = true
+	return &mysqlctlpb.BackupVerification{BackupName: request.BackupName, Ok: true}, nil
+}
+
+func tmRPCTestVerifyBackup(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	stream, err := client.VerifyBackup(ctx, tablet, &tabletmanagerdatapb.VerifyBackupRequest{BackupName: testVerifyBackupName})
+	if err != nil {
+		t.Fatalf("VerifyBackup failed: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("No logged value for VerifyBackup/%v: %v", i, err)
+		}
+		if resp.Event.GetValue() != testLogString {
+			t.Errorf("Unexpected log response for VerifyBackup: got %v expected %v", resp.Event.GetValue(), testLogString)
+		}
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("No verification for VerifyBackup: %v", err)
+	}
+	compare(t, "VerifyBackup result", resp.Verification, &mysqlctlpb.BackupVerification{BackupName: testVerifyBackupName, Ok: true})
+	_, err = stream.Recv()
+	if err != io.EOF {
+		t.Fatalf("VerifyBackup stream wasn't closed: %v", err)
+	}
+	compareError(t, "VerifyBackup", nil, true, testVerifyBackupCalled)
+}
+
+func tmRPCTestVerifyBackupPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	stream, err := client.VerifyBackup(ctx, tablet, &tabletmanagerdatapb.VerifyBackupRequest{BackupName: testVerifyBackupName})
+	if err != nil {
+		t.Fatalf("VerifyBackup failed: %v", err)
+	}
+	e, err := stream.Recv()
+	if err == nil {
+		t.Fatalf("Unexpected VerifyBackup logs: %v", e)
+	}
+	expectHandleRPCPanic(t, "VerifyBackup", true /*verbose*/, err)
+}
+
 func tmRPCTestCheckThrottler(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.CheckThrottlerRequest) {
 	_, err := client.CheckThrottler(ctx, tablet, req)
 	expectHandleRPCPanic(t, "CheckThrottler", false /*verbose*/, err)
@@ -1405,6 +1456,7 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	// Backup / restore related methods
 	tmRPCTestBackup(ctx, t, client, tablet)
 	tmRPCTestRestoreFromBackup(ctx, t, client, tablet, restoreFromBackupRequest)
+	tmRPCTestVerifyBackup(ctx, t, client, tablet)
 
 	// Throttler related methods
 	tmRPCTestCheckThrottler(ctx, t, client, tablet, checkThrottlerRequest)
@@ -1460,6 +1512,7 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	// Backup / restore related methods
 	tmRPCTestBackupPanic(ctx, t, client, tablet)
 	tmRPCTestRestoreFromBackupPanic(ctx, t, client, tablet, restoreFromBackupRequest)
+	tmRPCTestVerifyBackupPanic(ctx, t, client, tablet)
 
 	client.Close()
 }